@@ -27,6 +27,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -160,37 +161,39 @@ func main() {
 	currentVersion, _ := st.GetSchemaVersion(ctx)
 	log.Printf("Database schema version: %s", currentVersion)
 
-	// Initialize profile registry
+	// Load profiles from YAML
 	log.Println("Loading cluster profiles...")
 	loader := profile.NewLoader(profilesDir)
-	registry, err := profile.NewRegistry(loader)
+	fileProfiles, err := loader.LoadAll()
 	if err != nil {
 		log.Fatalf("Failed to load profiles: %v", err)
 	}
 
-	profileCount := registry.Count()
-	enabledCount := registry.CountEnabled()
-	log.Printf("Loaded %d profiles (%d enabled)", profileCount, enabledCount)
-
-	// Sync profiles from YAML to database
+	// Sync profiles from YAML to database. Changed profiles get a new published
+	// revision; profiles taken over by the admin API are not overwritten.
 	log.Println("Syncing profiles from YAML to database...")
-	allProfiles := registry.ListAll()
 	syncedCount := 0
-	for _, prof := range allProfiles {
-		if err := st.UpsertProfile(ctx, prof); err != nil {
+	for _, prof := range fileProfiles {
+		definition, err := loader.ReadDefinition(prof.Name)
+		if err != nil {
 			log.Printf("Warning: Failed to sync profile %s: %v", prof.Name, err)
-		} else {
+			continue
+		}
+		changed, err := st.SyncFileProfile(ctx, prof, string(definition))
+		if err != nil {
+			log.Printf("Warning: Failed to sync profile %s: %v", prof.Name, err)
+		} else if changed {
 			syncedCount++
 		}
 	}
-	log.Printf("✓ Synced %d profiles to database", syncedCount)
+	log.Printf("✓ Synced %d profiles to database (%d changed)", len(fileProfiles), syncedCount)
 
-	// Prune profiles whose YAML source was renamed or removed. The upsert loop
+	// Prune profiles whose YAML source was renamed or removed. The sync loop
 	// above only ever adds/updates, so without this a renamed profile leaves its
 	// old row behind and the UI shows a duplicate card (the pre-rename
 	// azure-aro-standard lingered in the DB alongside aro-standard).
-	keepProfiles := make([]string, 0, len(allProfiles))
-	for _, prof := range allProfiles {
+	keepProfiles := make([]string, 0, len(fileProfiles))
+	for _, prof := range fileProfiles {
 		keepProfiles = append(keepProfiles, prof.Name)
 	}
 	if pruned, err := st.DeleteProfilesNotIn(ctx, keepProfiles); err != nil {
@@ -199,6 +202,22 @@ func main() {
 		log.Printf("✓ Pruned %d stale profile(s) from database", pruned)
 	}
 
+	// Initialize profile registry from the published profiles in the
+	// database, which includes profiles managed through the admin API
+	registry, err := profile.NewRegistryFromStore(st)
+	if err != nil {
+		log.Fatalf("Failed to load profiles: %v", err)
+	}
+
+	profileCount := registry.Count()
+	enabledCount := registry.CountEnabled()
+	log.Printf("Loaded %d profiles (%d enabled)", profileCount, enabledCount)
+
+	// Pick up profile changes published by other replicas
+	profileWatchCtx, profileWatchCancel := context.WithCancel(context.Background())
+	defer profileWatchCancel()
+	go registry.Watch(profileWatchCtx, profile.DefaultWatchInterval)
+
 	// Sync add-ons from YAML to database
 	log.Println("Syncing add-ons from YAML...")
 	addonsDir := os.Getenv("ADDONS_DIR")
@@ -225,10 +244,43 @@ func main() {
 	config.IAMAllowedGroup = iamAllowedGroup
 	config.Environment = environment
 
+	// Rate limiting: RATE_LIMIT_BACKEND=postgres shares limits across API replicas
+	if rateLimitRequests := os.Getenv("RATE_LIMIT_REQUESTS"); rateLimitRequests != "" {
+		if n, err := strconv.Atoi(rateLimitRequests); err == nil && n > 0 {
+			config.RateLimitRequests = n
+		} else {
+			log.Printf("Invalid RATE_LIMIT_REQUESTS value '%s', using default: %d", rateLimitRequests, config.RateLimitRequests)
+		}
+	}
+	if rateLimitBackend := os.Getenv("RATE_LIMIT_BACKEND"); rateLimitBackend != "" {
+		config.RateLimitBackend = rateLimitBackend
+	}
+
+	// OperatorHub catalog browsing: OPERATOR_CATALOG_DIR holds rendered catalogs,
+	// OPERATOR_CATALOG_RENDER=true renders missing ones with opm, and
+	// OPERATOR_CATALOG_IMAGES overrides index images (catalog=image,...)
+	config.OperatorCatalog.CacheDir = os.Getenv("OPERATOR_CATALOG_DIR")
+	config.OperatorCatalog.RenderImages = os.Getenv("OPERATOR_CATALOG_RENDER") == "true"
+	if images := os.Getenv("OPERATOR_CATALOG_IMAGES"); images != "" {
+		config.OperatorCatalog.IndexImages = make(map[string]string)
+		for _, pair := range strings.Split(images, ",") {
+			name, image, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || name == "" || image == "" {
+				log.Printf("Invalid OPERATOR_CATALOG_IMAGES entry '%s', expected catalog=image", pair)
+				continue
+			}
+			config.OperatorCatalog.IndexImages[name] = image
+		}
+	}
+
 	log.Printf("Server configured:")
 	log.Printf("  Port: %d", config.Port)
 	log.Printf("  Auth enabled: %v (JWT: true, IAM: %v)", config.EnableAuth, config.EnableIAMAuth)
 	log.Printf("  CORS origins: %v", config.AllowedOrigins)
+	log.Printf("  Rate limit: %d requests/minute (backend: %s)", config.RateLimitRequests, config.RateLimitBackend)
+	if config.OperatorCatalog.CacheDir != "" {
+		log.Printf("  Operator catalogs: %s (render: %v)", config.OperatorCatalog.CacheDir, config.OperatorCatalog.RenderImages)
+	}
 
 	// Set version information
 	config.Version = Version
@@ -263,6 +315,11 @@ func main() {
 		log.Fatalf("Failed to create API server: %v", err)
 	}
 
+	// Render operator catalogs in the background so requests never wait for opm
+	catalogCtx, catalogCancel := context.WithCancel(context.Background())
+	defer catalogCancel()
+	go server.RenderCatalogs(catalogCtx)
+
 	// Start server in a goroutine
 	go func() {
 		if err := server.Start(); err != nil {
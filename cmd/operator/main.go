@@ -0,0 +1,128 @@
+// Package main runs the ocpctl operator, which manages ocpctl clusters and pool
+// leases declared as OcpctlCluster and OcpctlPoolLease resources.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/tsanders-rh/ocpctl/internal/operator"
+	"github.com/tsanders-rh/ocpctl/pkg/client"
+	"github.com/tsanders-rh/ocpctl/pkg/operator/v1alpha1"
+)
+
+// Version information (set via -ldflags at build time)
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+func main() {
+	showVersion := flag.Bool("version", false, "Show version information and exit")
+	metricsAddr := flag.String("metrics-bind-address", ":8080", "Address the metrics endpoint binds to")
+	probeAddr := flag.String("health-probe-bind-address", ":8081", "Address the health probe endpoint binds to")
+	leaderElect := flag.Bool("leader-elect", false, "Enable leader election so only one replica reconciles")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Printf("ocpctl-operator version %s\n", Version)
+		fmt.Printf("  Commit:    %s\n", Commit)
+		fmt.Printf("  BuildTime: %s\n", BuildTime)
+		os.Exit(0)
+	}
+
+	ctrl.SetLogger(zap.New())
+
+	api, err := newAPIClient(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to configure ocpctl API client: %v", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		log.Fatalf("Failed to register Kubernetes types: %v", err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		log.Fatalf("Failed to register ocpctl types: %v", err)
+	}
+
+	options := ctrl.Options{
+		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: *metricsAddr},
+		HealthProbeBindAddress: *probeAddr,
+		LeaderElection:         *leaderElect,
+		LeaderElectionID:       "ocpctl-operator.ocpctl.io",
+	}
+	// WATCH_NAMESPACE limits the operator to a comma-separated list of namespaces
+	if ns := os.Getenv("WATCH_NAMESPACE"); ns != "" {
+		namespaces := map[string]cache.Config{}
+		for _, n := range strings.Split(ns, ",") {
+			namespaces[strings.TrimSpace(n)] = cache.Config{}
+		}
+		options.Cache.DefaultNamespaces = namespaces
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
+	if err != nil {
+		log.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := (&operator.ClusterReconciler{Client: mgr.GetClient(), API: api}).SetupWithManager(mgr); err != nil {
+		log.Fatalf("Failed to set up OcpctlCluster controller: %v", err)
+	}
+	if err := (&operator.PoolLeaseReconciler{Client: mgr.GetClient(), API: api}).SetupWithManager(mgr); err != nil {
+		log.Fatalf("Failed to set up OcpctlPoolLease controller: %v", err)
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		log.Fatalf("Failed to add health check: %v", err)
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		log.Fatalf("Failed to add ready check: %v", err)
+	}
+
+	log.Printf("Starting ocpctl operator %s (API %s)", Version, os.Getenv("OCPCTL_API_URL"))
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		log.Fatalf("Operator stopped: %v", err)
+	}
+}
+
+// newAPIClient configures the ocpctl API client from the environment:
+// OCPCTL_API_URL, and either OCPCTL_API_KEY or OCPCTL_AUTH=iam to sign
+// requests with the pod's AWS credentials (IRSA)
+func newAPIClient(ctx context.Context) (*client.Client, error) {
+	apiURL := os.Getenv("OCPCTL_API_URL")
+	if apiURL == "" {
+		return nil, fmt.Errorf("OCPCTL_API_URL must be set")
+	}
+
+	opts := []client.Option{client.WithUserAgent("ocpctl-operator/" + Version)}
+	switch {
+	case os.Getenv("OCPCTL_AUTH") == "iam":
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("load AWS config: %w", err)
+		}
+		opts = append(opts, client.WithAuth(client.NewSigV4Auth(cfg.Credentials, cfg.Region)))
+	case os.Getenv("OCPCTL_API_KEY") != "":
+		opts = append(opts, client.WithAuth(client.APIKey(os.Getenv("OCPCTL_API_KEY"))))
+	default:
+		return nil, fmt.Errorf("set OCPCTL_API_KEY, or OCPCTL_AUTH=iam to use AWS credentials")
+	}
+
+	return client.New(apiURL, opts...)
+}
@@ -36,12 +36,22 @@ func main() {
 
 	log.Printf("Found %d profiles to sync", len(profiles))
 
+	// Profiles managed through the admin API are left alone; unchanged
+	// profiles are skipped so they do not get a new revision
 	for _, p := range profiles {
 		log.Printf("Syncing: %s (creds: %s)", p.Name, p.CredentialsMode)
-		if err := st.UpsertProfile(ctx, p); err != nil {
+		definition, err := loader.ReadDefinition(p.Name)
+		if err != nil {
 			log.Printf("  ERROR: %v", err)
+			continue
+		}
+		changed, err := st.SyncFileProfile(ctx, p, string(definition))
+		if err != nil {
+			log.Printf("  ERROR: %v", err)
+		} else if changed {
+			log.Printf("  ✓ Published new revision")
 		} else {
-			log.Printf("  ✓ Done")
+			log.Printf("  ✓ Unchanged")
 		}
 	}
 
@@ -9,13 +9,17 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/tsanders-rh/ocpctl/internal/events"
+	"github.com/tsanders-rh/ocpctl/internal/gitops"
 	"github.com/tsanders-rh/ocpctl/internal/janitor"
+	"github.com/tsanders-rh/ocpctl/internal/policy"
 	"github.com/tsanders-rh/ocpctl/internal/poolscheduler"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
 	"github.com/tsanders-rh/ocpctl/internal/secrets"
@@ -317,15 +321,10 @@ func main() {
 	currentVersion, _ := st.GetSchemaVersion(ctx)
 	log.Printf("Database schema version: %s", currentVersion)
 
-	// Load profiles
-	profilesDir := os.Getenv("PROFILES_DIR")
-	if profilesDir == "" {
-		profilesDir = "/opt/ocpctl/profiles"
-	}
-
-	log.Printf("Loading profiles from: %s", profilesDir)
-	loader := profile.NewLoader(profilesDir)
-	profileRegistry, err := profile.NewRegistry(loader)
+	// Load the published profiles from the database. The API syncs the
+	// profile files on startup, so the worker needs no local copy.
+	log.Println("Loading profiles from database")
+	profileRegistry, err := profile.NewRegistryFromStore(st)
 	if err != nil {
 		log.Fatalf("Failed to load profiles: %v", err)
 	}
@@ -334,9 +333,25 @@ func main() {
 	enabledCount := profileRegistry.CountEnabled()
 	log.Printf("Loaded %d profiles (%d enabled)", profileCount, enabledCount)
 
+	// Pick up profiles published through the API
+	profileWatchCtx, profileWatchCancel := context.WithCancel(context.Background())
+	defer profileWatchCancel()
+	go profileRegistry.Watch(profileWatchCtx, profile.DefaultWatchInterval)
+
 	// Create worker
 	workerConfig := worker.DefaultConfig()
 	workerConfig.WorkDir = workDir
+	if v := os.Getenv("WORKER_POSTCONFIG_PARALLELISM"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			workerConfig.PostConfigParallelism = n
+		} else {
+			log.Printf("Warning: ignoring invalid WORKER_POSTCONFIG_PARALLELISM %q", v)
+		}
+	}
+	workerConfig.Secrets = secretsManager
+	if v := os.Getenv("WORKER_POSTCONFIG_SECRET_PREFIX"); v != "" {
+		workerConfig.PostConfigSecretPrefix = v
+	}
 
 	w := worker.NewWorker(workerConfig, st, profileRegistry)
 
@@ -348,6 +363,17 @@ func main() {
 	poolSchedulerConfig := poolscheduler.DefaultConfig()
 	ps := poolscheduler.NewScheduler(poolSchedulerConfig, st)
 
+	// Create cluster spec syncer (reconciles git-backed cluster specs)
+	specSyncer := gitops.NewSyncer(gitops.DefaultConfig(), st, gitops.NewReconciler(st, policy.NewEngine(profileRegistry), profileRegistry))
+
+	// Create cluster event dispatcher if a CloudEvents sink is configured
+	var dispatcher *events.Dispatcher
+	if sinkURL := os.Getenv("CLOUDEVENTS_SINK_URL"); sinkURL != "" {
+		publisher := events.NewHTTPPublisher(sinkURL, os.Getenv("CLOUDEVENTS_SOURCE"), os.Getenv("CLOUDEVENTS_SINK_TOKEN"))
+		dispatcher = events.NewDispatcher(events.DefaultConfig(), st.ClusterEvents, publisher)
+		log.Printf("Publishing cluster events as CloudEvents to %s", sinkURL)
+	}
+
 	// Start health check server
 	healthCheck := &HealthCheckServer{
 		store:  st,
@@ -363,6 +389,8 @@ func main() {
 	workerCtx, workerCancel := context.WithCancel(context.Background())
 	janitorCtx, janitorCancel := context.WithCancel(context.Background())
 	poolSchedulerCtx, poolSchedulerCancel := context.WithCancel(context.Background())
+	dispatcherCtx, dispatcherCancel := context.WithCancel(context.Background())
+	specSyncerCtx, specSyncerCancel := context.WithCancel(context.Background())
 
 	// Start worker
 	wg.Add(1)
@@ -394,6 +422,28 @@ func main() {
 		log.Println("Pool scheduler goroutine exiting")
 	}()
 
+	// Start cluster spec syncer
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := specSyncer.Start(specSyncerCtx); err != nil && err != context.Canceled {
+			log.Printf("Cluster spec syncer error: %v", err)
+		}
+		log.Println("Cluster spec syncer goroutine exiting")
+	}()
+
+	// Start event dispatcher
+	if dispatcher != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := dispatcher.Start(dispatcherCtx); err != nil && err != context.Canceled {
+				log.Printf("Event dispatcher error: %v", err)
+			}
+			log.Println("Event dispatcher goroutine exiting")
+		}()
+	}
+
 	log.Println("Worker, janitor, and pool scheduler started successfully")
 
 	// Mark health check as ready (thread-safe atomic store)
@@ -416,6 +466,8 @@ func main() {
 	workerCancel()
 	janitorCancel()
 	poolSchedulerCancel()
+	dispatcherCancel()
+	specSyncerCancel()
 
 	// Wait for all goroutines to complete with timeout
 	done := make(chan struct{})
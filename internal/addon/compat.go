@@ -0,0 +1,167 @@
+package addon
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// CheckCompatibility returns an error saying why an add-on version cannot be
+// installed on a cluster of clusterType running version, or nil if it can.
+// version is an OpenShift version for OpenShift-based cluster types and a
+// Kubernetes version for the rest; an empty version is not checked. Add-ons
+// without compatibility metadata can be installed on any cluster.
+func CheckCompatibility(meta *types.AddonMetadata, clusterType types.ClusterType, version string) error {
+	if meta == nil || meta.Compatibility == nil {
+		return nil
+	}
+	compat := meta.Compatibility
+	if clusterType == "" {
+		clusterType = types.ClusterTypeOpenShift
+	}
+
+	if len(compat.ClusterTypes) > 0 {
+		supported := false
+		for _, t := range compat.ClusterTypes {
+			if t == clusterType {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("cluster type %s is not supported (supported: %v)", clusterType, compat.ClusterTypes)
+		}
+	}
+
+	if version == "" {
+		return nil
+	}
+	kind, constraint := versionRange(compat, clusterType)
+	if constraint == "" {
+		return nil
+	}
+	ok, err := MatchesClusterVersion(version, constraint)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s %s is not supported (supported: %s)", kind, version, constraint)
+	}
+	return nil
+}
+
+// versionRange returns the kind of version a cluster type runs and the
+// add-on's range for it. Managed and hosted OpenShift run OpenShift versions
+// even where profiles do not check them.
+func versionRange(compat *types.AddonCompatibility, clusterType types.ClusterType) (kind, constraint string) {
+	switch clusterType {
+	case types.ClusterTypeOpenShift, types.ClusterTypeROSA, types.ClusterTypeARO, types.ClusterTypeHCP:
+		return "OpenShift", compat.OpenShiftVersions
+	default:
+		return "Kubernetes", compat.KubernetesVersions
+	}
+}
+
+// MatchesClusterVersion reports whether a cluster version satisfies a
+// compatibility range such as ">=4.16, <=4.18". Each bound is compared at the
+// precision it is written with, so "<=4.18" includes 4.18.12 and "==4.18"
+// matches every 4.18 release.
+func MatchesClusterVersion(version, constraint string) (bool, error) {
+	clauses, err := parseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	have, err := postconfig.ParseVersion(version)
+	if err != nil {
+		return false, fmt.Errorf("invalid cluster version %q", version)
+	}
+
+	for _, c := range clauses {
+		v := have
+		switch c.parts {
+		case 1:
+			v = postconfig.Version{Major: have.Major}
+		case 2:
+			v = postconfig.Version{Major: have.Major, Minor: have.Minor}
+		}
+		if !c.matches(v.Compare(c.version)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ValidateCompatibility checks an add-on version's compatibility metadata:
+// version ranges must be comparisons and cluster types must exist
+func ValidateCompatibility(meta *types.AddonMetadata) error {
+	if meta == nil || meta.Compatibility == nil {
+		return nil
+	}
+	compat := meta.Compatibility
+	for _, r := range []struct{ field, constraint string }{
+		{"openshiftVersions", compat.OpenShiftVersions},
+		{"kubernetesVersions", compat.KubernetesVersions},
+	} {
+		if r.constraint == "" {
+			continue
+		}
+		if _, err := parseConstraint(r.constraint); err != nil {
+			return fmt.Errorf("compatibility.%s: %w", r.field, err)
+		}
+	}
+	for _, t := range compat.ClusterTypes {
+		if _, ok := provider.Builtin(t); !ok {
+			return fmt.Errorf("compatibility.clusterTypes: unknown cluster type %q", t)
+		}
+	}
+	return nil
+}
+
+// CoverageGaps returns, for each add-on, the versions of clusterType that
+// none of its versions can be installed on. Disabled add-on versions are
+// ignored, and add-ons that do not support clusterType at all are not
+// reported. Gaps are sorted by add-on ID.
+func CoverageGaps(addons []types.PostConfigAddon, clusterType types.ClusterType, versions []string) []types.AddonCoverageGap {
+	byID := make(map[string][]*types.AddonMetadata)
+	for i := range addons {
+		if addons[i].Enabled {
+			byID[addons[i].AddonID] = append(byID[addons[i].AddonID], addons[i].Metadata)
+		}
+	}
+
+	gaps := []types.AddonCoverageGap{}
+	for id, metas := range byID {
+		supportsType := false
+		for _, meta := range metas {
+			if CheckCompatibility(meta, clusterType, "") == nil {
+				supportsType = true
+				break
+			}
+		}
+		if !supportsType {
+			continue
+		}
+
+		var missing []string
+		for _, v := range versions {
+			covered := false
+			for _, meta := range metas {
+				if CheckCompatibility(meta, clusterType, v) == nil {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				missing = append(missing, v)
+			}
+		}
+		if len(missing) > 0 {
+			gaps = append(gaps, types.AddonCoverageGap{AddonID: id, Versions: missing})
+		}
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i].AddonID < gaps[j].AddonID })
+	return gaps
+}
@@ -0,0 +1,160 @@
+package addon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestMatchesClusterVersion(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"4.17.3", ">=4.16, <=4.18", true},
+		{"4.18.12", ">=4.16, <=4.18", true},
+		{"4.19.0", ">=4.16, <=4.18", false},
+		{"4.15.9", ">=4.16", false},
+		{"4.18.1", "<4.18", false},
+		{"4.18.1", "==4.18", true},
+		{"4.18.1", ">=4.18.2", false},
+		{"4.18.2", ">=4.18.2", true},
+		{"4.19.0-ec.2", ">=4.19", true},
+		{"1.31.2", ">=1.30, <1.32", true},
+		{"5.0.0", "<5", false},
+	}
+	for _, tt := range tests {
+		got, err := MatchesClusterVersion(tt.version, tt.constraint)
+		require.NoError(t, err, "%s %s", tt.version, tt.constraint)
+		assert.Equal(t, tt.want, got, "%s %s", tt.version, tt.constraint)
+	}
+
+	_, err := MatchesClusterVersion("4.18", "4.18")
+	assert.Error(t, err)
+	_, err = MatchesClusterVersion("latest", ">=4.16")
+	assert.Error(t, err)
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	meta := &types.AddonMetadata{Compatibility: &types.AddonCompatibility{
+		OpenShiftVersions:  ">=4.17",
+		KubernetesVersions: ">=1.30",
+		ClusterTypes:       []types.ClusterType{types.ClusterTypeOpenShift, types.ClusterTypeROSA, types.ClusterTypeEKS},
+	}}
+
+	assert.NoError(t, CheckCompatibility(meta, types.ClusterTypeOpenShift, "4.18.2"))
+	assert.NoError(t, CheckCompatibility(meta, "", "4.17"))
+	assert.NoError(t, CheckCompatibility(meta, types.ClusterTypeROSA, ""))
+	assert.NoError(t, CheckCompatibility(meta, types.ClusterTypeEKS, "1.31"))
+	assert.EqualError(t, CheckCompatibility(meta, types.ClusterTypeOpenShift, "4.16.9"),
+		"OpenShift 4.16.9 is not supported (supported: >=4.17)")
+	assert.EqualError(t, CheckCompatibility(meta, types.ClusterTypeEKS, "1.29"),
+		"Kubernetes 1.29 is not supported (supported: >=1.30)")
+	assert.EqualError(t, CheckCompatibility(meta, types.ClusterTypeGKE, "1.31"),
+		"cluster type gke is not supported (supported: [openshift rosa eks])")
+
+	// No compatibility metadata supports every cluster
+	assert.NoError(t, CheckCompatibility(nil, types.ClusterTypeGKE, "1.20"))
+	assert.NoError(t, CheckCompatibility(&types.AddonMetadata{}, types.ClusterTypeOpenShift, "4.10"))
+}
+
+func TestValidateCompatibility(t *testing.T) {
+	valid := &types.AddonMetadata{Compatibility: &types.AddonCompatibility{
+		OpenShiftVersions: ">=4.16, <4.19",
+		ClusterTypes:      []types.ClusterType{types.ClusterTypeOpenShift},
+	}}
+	assert.NoError(t, ValidateCompatibility(valid))
+	assert.NoError(t, ValidateCompatibility(nil))
+
+	for _, compat := range []*types.AddonCompatibility{
+		{OpenShiftVersions: "4.16+"},
+		{KubernetesVersions: ">=1.x"},
+		{ClusterTypes: []types.ClusterType{"openstack"}},
+	} {
+		assert.Error(t, ValidateCompatibility(&types.AddonMetadata{Compatibility: compat}), "%+v", compat)
+	}
+}
+
+func TestCoverageGaps(t *testing.T) {
+	compat := func(openshift string) *types.AddonMetadata {
+		return &types.AddonMetadata{Compatibility: &types.AddonCompatibility{OpenShiftVersions: openshift}}
+	}
+	addons := []types.PostConfigAddon{
+		{AddonID: "cnv", Version: "stable-4.16", Enabled: true, Metadata: compat("==4.16")},
+		{AddonID: "cnv", Version: "stable", Enabled: true, Metadata: compat(">=4.17, <=4.18")},
+		{AddonID: "cnv", Version: "candidate", Enabled: false, Metadata: compat(">=4.19")},
+		{AddonID: "oadp", Version: "stable", Enabled: true},
+		{AddonID: "mta", Version: "stable", Enabled: true, Metadata: compat("<4.18")},
+		{AddonID: "dashboard", Version: "v2", Enabled: true, Metadata: &types.AddonMetadata{
+			Compatibility: &types.AddonCompatibility{ClusterTypes: []types.ClusterType{types.ClusterTypeEKS}},
+		}},
+	}
+
+	gaps := CoverageGaps(addons, types.ClusterTypeOpenShift, []string{"4.16", "4.18", "4.19.1"})
+	assert.Equal(t, []types.AddonCoverageGap{
+		{AddonID: "cnv", Versions: []string{"4.19.1"}},
+		{AddonID: "mta", Versions: []string{"4.18", "4.19.1"}},
+	}, gaps)
+
+	assert.Empty(t, CoverageGaps(addons, types.ClusterTypeEKS, []string{"1.31"}))
+}
+
+func TestLoaderCompatibility(t *testing.T) {
+	tmpDir := t.TempDir()
+	addonYAML := `id: cnv
+name: OpenShift Virtualization
+description: Run VMs on OpenShift
+category: virtualization
+enabled: true
+supportedPlatforms: [openshift]
+metadata:
+  compatibility:
+    clusterTypes: [openshift]
+versions:
+  - channel: stable
+    displayName: Stable
+    isDefault: true
+    compatibility:
+      openshiftVersions: ">=4.17"
+    config:
+      operators: []
+  - channel: stable-4.16
+    displayName: "4.16"
+    config:
+      operators: []
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "cnv.yaml"), []byte(addonYAML), 0644))
+
+	addons, err := NewLoader(tmpDir).LoadAll()
+	require.NoError(t, err)
+	require.Len(t, addons, 1)
+	require.NotNil(t, addons[0].Versions[0].Compatibility)
+	assert.Equal(t, ">=4.17", addons[0].Versions[0].Compatibility.OpenShiftVersions)
+	assert.Nil(t, addons[0].Versions[1].Compatibility)
+	assert.Equal(t, []types.ClusterType{types.ClusterTypeOpenShift}, addons[0].Metadata.Compatibility.ClusterTypes)
+
+	invalid := `id: bad
+name: Bad
+description: Bad range
+category: backup
+enabled: true
+supportedPlatforms: [openshift]
+versions:
+  - channel: stable
+    displayName: Stable
+    isDefault: true
+    compatibility:
+      openshiftVersions: "4.16 - 4.18"
+    config:
+      operators: []
+`
+	badDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(badDir, "bad.yaml"), []byte(invalid), 0644))
+	_, err = NewLoader(badDir).LoadAll()
+	assert.ErrorContains(t, err, "compatibility.openshiftVersions")
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/tsanders-rh/ocpctl/internal/validation"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 	"gopkg.in/yaml.v3"
 )
 
@@ -96,6 +98,12 @@ func (l *Loader) Validate(addon *AddonDefinition) error {
 			return fmt.Errorf("duplicate channel: %s", v.Channel)
 		}
 		channels[v.Channel] = true
+		if errs := validation.ValidateReadiness(&v.Config); len(errs) > 0 {
+			return fmt.Errorf("version %s: config.%w", v.Channel, errs[0])
+		}
+		if err := ValidateCompatibility(&types.AddonMetadata{Compatibility: v.Compatibility}); err != nil {
+			return fmt.Errorf("version %s: %w", v.Channel, err)
+		}
 	}
 
 	if defaultCount == 0 {
@@ -105,5 +113,22 @@ func (l *Loader) Validate(addon *AddonDefinition) error {
 		return fmt.Errorf("multiple default versions specified (%d)", defaultCount)
 	}
 
+	if addon.Metadata != nil {
+		for _, arch := range addon.Metadata.SupportedArchitectures {
+			if !types.ValidArchitecture(arch) {
+				return fmt.Errorf("unsupported architecture %q in metadata.supportedArchitectures (must be amd64 or arm64)", arch)
+			}
+		}
+		if err := ValidateRequirements(addon.ID, &types.AddonMetadata{
+			Requires:   addon.Metadata.Requires,
+			Recommends: addon.Metadata.Recommends,
+		}); err != nil {
+			return err
+		}
+		if err := ValidateCompatibility(&types.AddonMetadata{Compatibility: addon.Metadata.Compatibility}); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
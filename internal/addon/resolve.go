@@ -0,0 +1,411 @@
+package addon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// Catalog looks up add-on versions during dependency resolution
+type Catalog interface {
+	// Get returns an add-on at a version, or its default version when version is empty
+	Get(ctx context.Context, addonID, version string) (*types.PostConfigAddon, error)
+	// Versions returns the enabled versions of an add-on, default first
+	Versions(ctx context.Context, addonID string) ([]types.PostConfigAddon, error)
+}
+
+// storeCatalog is a Catalog backed by the add-on store
+type storeCatalog struct {
+	store  *store.PostConfigAddonStore
+	userID string
+}
+
+// NewStoreCatalog returns a Catalog backed by the add-on store. Draft add-on
+// versions owned by userID can be selected explicitly; requirements are only
+// satisfied from published versions.
+func NewStoreCatalog(s *store.PostConfigAddonStore, userID string) Catalog {
+	return &storeCatalog{store: s, userID: userID}
+}
+
+func (c *storeCatalog) Get(ctx context.Context, addonID, version string) (*types.PostConfigAddon, error) {
+	if version == "" {
+		return c.store.GetByAddonID(ctx, addonID)
+	}
+	if c.userID == "" {
+		return c.store.GetByAddonIDAndVersion(ctx, addonID, version)
+	}
+	return c.store.GetByAddonIDAndVersionForUser(ctx, addonID, version, c.userID)
+}
+
+func (c *storeCatalog) Versions(ctx context.Context, addonID string) ([]types.PostConfigAddon, error) {
+	return c.store.ListVersions(ctx, addonID)
+}
+
+// Resolution is the outcome of resolving add-on requirements
+type Resolution struct {
+	// Addons is the selection plus every add-on it requires, each after the
+	// add-ons it requires
+	Addons []types.PostConfigAddon
+	// Added are the add-ons pulled in by requirements rather than selected
+	Added []types.AddonDependency
+	// Recommended are add-ons recommended by the resolved set but not part of it
+	Recommended []types.AddonDependency
+}
+
+// Refs returns the resolved add-ons as selected add-on references, in dependency order
+func (r *Resolution) Refs() []string {
+	refs := make([]string, 0, len(r.Addons))
+	for _, a := range r.Addons {
+		refs = append(refs, types.AddonRef(a.AddonID, a.Version))
+	}
+	return refs
+}
+
+// Resolve expands selected add-ons with their transitive requirements. A
+// requirement already satisfied by the selection must match its version
+// constraint; otherwise the catalog's default version is used when it
+// matches, or the first matching version. Requirements whose condition does
+// not hold in condCtx are ignored; with a nil condCtx every requirement
+// applies. Conflicts and dependency cycles anywhere in the resolved set are
+// errors.
+func Resolve(ctx context.Context, catalog Catalog, selected []types.PostConfigAddon, condCtx *postconfig.TemplateContext) (*Resolution, error) {
+	byID := make(map[string]*types.PostConfigAddon, len(selected))
+	order := make([]string, 0, len(selected))
+	for i := range selected {
+		a := &selected[i]
+		if _, dup := byID[a.AddonID]; dup {
+			return nil, fmt.Errorf("addon %s is selected more than once", a.AddonID)
+		}
+		byID[a.AddonID] = a
+		order = append(order, a.AddonID)
+	}
+
+	res := &Resolution{}
+	edges := make(map[string][]string) // addon ID -> IDs it requires
+
+	// Breadth-first over the growing set so each addition's own
+	// requirements are resolved as well
+	for i := 0; i < len(order); i++ {
+		a := byID[order[i]]
+		if a.Metadata == nil {
+			continue
+		}
+		for _, req := range a.Metadata.Requires {
+			applies, err := requirementApplies(req, condCtx)
+			if err != nil {
+				return nil, fmt.Errorf("addon %s: requirement %s: %w", a.AddonID, req.ID, err)
+			}
+			if !applies {
+				continue
+			}
+			if req.ID == a.AddonID {
+				return nil, fmt.Errorf("addon dependency cycle: %s -> %s", a.AddonID, a.AddonID)
+			}
+			edges[a.AddonID] = append(edges[a.AddonID], req.ID)
+
+			if dep, ok := byID[req.ID]; ok {
+				match, err := MatchesVersion(dep.Version, req.Version)
+				if err != nil {
+					return nil, fmt.Errorf("addon %s: requirement %s: %w", a.AddonID, req.ID, err)
+				}
+				if !match {
+					return nil, fmt.Errorf("addon %s requires %s %s, but version %s is selected",
+						a.AddonID, req.ID, req.Version, dep.Version)
+				}
+				continue
+			}
+
+			dep, err := pickVersion(ctx, catalog, req)
+			if err != nil {
+				return nil, fmt.Errorf("addon %s requires %s: %w", a.AddonID, types.AddonRef(req.ID, req.Version), err)
+			}
+			byID[dep.AddonID] = dep
+			order = append(order, dep.AddonID)
+			res.Added = append(res.Added, types.AddonDependency{AddonID: dep.AddonID, Version: dep.Version, RequiredBy: a.AddonID})
+		}
+	}
+
+	sorted, err := dependencyOrder(order, edges)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range sorted {
+		res.Addons = append(res.Addons, *byID[id])
+	}
+
+	for i := range res.Addons {
+		if conflict := types.FindAddonConflict(&res.Addons[i], res.Addons); conflict != "" {
+			return nil, fmt.Errorf("addon conflict detected: %s conflicts with %s", res.Addons[i].AddonID, conflict)
+		}
+	}
+
+	recommended := make(map[string]bool)
+	for _, a := range res.Addons {
+		if a.Metadata == nil {
+			continue
+		}
+		for _, rec := range a.Metadata.Recommends {
+			if _, ok := byID[rec.ID]; ok || recommended[rec.ID] {
+				continue
+			}
+			if applies, err := requirementApplies(rec, condCtx); err != nil || !applies {
+				continue
+			}
+			recommended[rec.ID] = true
+			res.Recommended = append(res.Recommended, types.AddonDependency{AddonID: rec.ID, Version: rec.Version, RequiredBy: a.AddonID})
+		}
+	}
+
+	return res, nil
+}
+
+// requirementApplies evaluates a requirement's condition
+func requirementApplies(req types.AddonRequirement, condCtx *postconfig.TemplateContext) (bool, error) {
+	if req.Condition == "" || condCtx == nil {
+		return true, nil
+	}
+	return postconfig.EvaluateCondition(req.Condition, condCtx)
+}
+
+// pickVersion chooses the catalog version that satisfies a requirement,
+// preferring the default version
+func pickVersion(ctx context.Context, catalog Catalog, req types.AddonRequirement) (*types.PostConfigAddon, error) {
+	if req.Version == "" {
+		a, err := catalog.Get(ctx, req.ID, "")
+		if err != nil {
+			return nil, err
+		}
+		if !a.Enabled {
+			return nil, fmt.Errorf("addon %s is disabled", req.ID)
+		}
+		return a, nil
+	}
+
+	versions, err := catalog.Versions(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range versions {
+		match, err := MatchesVersion(versions[i].Version, req.Version)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			return &versions[i], nil
+		}
+	}
+	return nil, errors.New("no available version matches")
+}
+
+// dependencyOrder sorts add-on IDs so each comes after the add-ons it
+// requires, keeping the given order otherwise
+func dependencyOrder(ids []string, edges map[string][]string) ([]string, error) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(ids))
+	sorted := make([]string, 0, len(ids))
+	var path []string
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			start := 0
+			for i, p := range path {
+				if p == id {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), id)
+			return fmt.Errorf("addon dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+		state[id] = visiting
+		path = append(path, id)
+		for _, dep := range edges[id] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		sorted = append(sorted, id)
+		return nil
+	}
+
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// channelVersionPattern finds the version number at the end of a channel name,
+// e.g. "1.4" in "stable-1.4" or "1.8" in "release-v1.8"
+var channelVersionPattern = regexp.MustCompile(`v?(\d+(?:\.\d+){0,2})$`)
+
+// constraintPattern matches one comparison in a version constraint
+var constraintPattern = regexp.MustCompile(`^(>=|<=|!=|==|=|>|<)\s*v?(\d+(?:\.\d+){0,2})$`)
+
+// versionClause is one comparison in a version constraint
+type versionClause struct {
+	op      string
+	version postconfig.Version
+	parts   int // Number of version components written, e.g. 2 for "4.18"
+}
+
+// parseConstraint splits a comparison constraint into its clauses
+func parseConstraint(constraint string) ([]versionClause, error) {
+	var clauses []versionClause
+	for _, clause := range strings.Split(constraint, ",") {
+		m := constraintPattern.FindStringSubmatch(strings.TrimSpace(clause))
+		if m == nil {
+			return nil, fmt.Errorf("invalid version constraint %q", constraint)
+		}
+		v, err := postconfig.ParseVersion(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+		}
+		clauses = append(clauses, versionClause{op: m[1], version: v, parts: strings.Count(m[2], ".") + 1})
+	}
+	return clauses, nil
+}
+
+// isComparison reports whether a constraint compares versions rather than
+// naming a channel
+func isComparison(constraint string) bool {
+	return constraint != "" && strings.ContainsAny(constraint[:1], "<>=!")
+}
+
+// MatchesVersion reports whether an add-on version (channel) satisfies a
+// requirement's version constraint. An empty constraint matches anything. A
+// constraint made of comparisons such as ">=1.4" or ">=1.4, <2" is checked
+// against the version number at the end of the channel name, and channels
+// without one never match. Any other constraint must equal the channel.
+func MatchesVersion(channel, constraint string) (bool, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return true, nil
+	}
+	if !isComparison(constraint) {
+		return channel == constraint, nil
+	}
+
+	clauses, err := parseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+
+	m := channelVersionPattern.FindStringSubmatch(channel)
+	if m == nil {
+		return false, nil
+	}
+	have, err := postconfig.ParseVersion(m[1])
+	if err != nil {
+		return false, nil
+	}
+
+	for _, c := range clauses {
+		if !c.matches(have.Compare(c.version)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matches reports whether a version comparing cmp to the clause's version
+// satisfies the clause
+func (c versionClause) matches(cmp int) bool {
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case "!=":
+		return cmp != 0
+	default:
+		return cmp == 0
+	}
+}
+
+// ValidateVersionConstraint checks the syntax of a requirement's version constraint
+func ValidateVersionConstraint(constraint string) error {
+	constraint = strings.TrimSpace(constraint)
+	if !isComparison(constraint) {
+		return nil
+	}
+	_, err := parseConstraint(constraint)
+	return err
+}
+
+// ValidateRequirements checks an add-on's requires and recommends entries:
+// each names another add-on, with a valid version constraint and condition
+func ValidateRequirements(addonID string, meta *types.AddonMetadata) error {
+	if meta == nil {
+		return nil
+	}
+	check := func(field string, reqs []types.AddonRequirement) error {
+		seen := make(map[string]bool, len(reqs))
+		for _, req := range reqs {
+			if req.ID == "" {
+				return fmt.Errorf("metadata.%s: addon id is required", field)
+			}
+			if req.ID == addonID {
+				return fmt.Errorf("metadata.%s: addon %s cannot reference itself", field, addonID)
+			}
+			if seen[req.ID] {
+				return fmt.Errorf("metadata.%s: duplicate addon %s", field, req.ID)
+			}
+			seen[req.ID] = true
+			if err := ValidateVersionConstraint(req.Version); err != nil {
+				return fmt.Errorf("metadata.%s[%s]: %w", field, req.ID, err)
+			}
+			if req.Condition != "" {
+				if err := postconfig.CompileCondition(req.Condition, nil); err != nil {
+					return fmt.Errorf("metadata.%s[%s]: %w", field, req.ID, err)
+				}
+			}
+		}
+		return nil
+	}
+	if err := check("requires", meta.Requires); err != nil {
+		return err
+	}
+	return check("recommends", meta.Recommends)
+}
+
+// RequiredBy returns the ID of the first installed add-on that requires
+// addonID, or "" if none does. Requirements whose condition does not hold in
+// condCtx are ignored.
+func RequiredBy(addonID string, installed []types.PostConfigAddon, condCtx *postconfig.TemplateContext) string {
+	for _, a := range installed {
+		if a.AddonID == addonID || a.Metadata == nil {
+			continue
+		}
+		for _, req := range a.Metadata.Requires {
+			if req.ID != addonID {
+				continue
+			}
+			if applies, err := requirementApplies(req, condCtx); err == nil && !applies {
+				continue
+			}
+			return a.AddonID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,220 @@
+package addon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// fakeCatalog serves add-on versions from memory. Versions are listed in
+// order, with the default version first.
+type fakeCatalog map[string][]types.PostConfigAddon
+
+func (f fakeCatalog) Get(_ context.Context, addonID, version string) (*types.PostConfigAddon, error) {
+	for i, a := range f[addonID] {
+		if version == "" && a.IsDefault || version != "" && a.Version == version {
+			return &f[addonID][i], nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (f fakeCatalog) Versions(_ context.Context, addonID string) ([]types.PostConfigAddon, error) {
+	return f[addonID], nil
+}
+
+func testAddon(id, version string, isDefault bool, requires ...types.AddonRequirement) types.PostConfigAddon {
+	return types.PostConfigAddon{
+		AddonID:   id,
+		Version:   version,
+		IsDefault: isDefault,
+		Enabled:   true,
+		Metadata:  &types.AddonMetadata{Requires: requires},
+	}
+}
+
+func resolvedIDs(res *Resolution) []string {
+	ids := make([]string, 0, len(res.Addons))
+	for _, a := range res.Addons {
+		ids = append(ids, a.AddonID)
+	}
+	return ids
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	catalog := fakeCatalog{
+		"cert-manager": {
+			testAddon("cert-manager", "stable-v1.14", true),
+			testAddon("cert-manager", "stable-v1.12", false),
+		},
+		"odf": {testAddon("odf", "stable-4.18", true)},
+		"oadp": {testAddon("oadp", "stable-1.4", true,
+			types.AddonRequirement{ID: "cert-manager", Version: ">=1.12"})},
+	}
+
+	t.Run("adds transitive requirements in dependency order", func(t *testing.T) {
+		mta := testAddon("mta", "stable-v7", true, types.AddonRequirement{ID: "oadp"})
+		res, err := Resolve(ctx, catalog, []types.PostConfigAddon{mta}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cert-manager", "oadp", "mta"}, resolvedIDs(res))
+		assert.Equal(t, []types.AddonDependency{
+			{AddonID: "oadp", Version: "stable-1.4", RequiredBy: "mta"},
+			{AddonID: "cert-manager", Version: "stable-v1.14", RequiredBy: "oadp"},
+		}, res.Added)
+		assert.Equal(t, []string{"cert-manager:stable-v1.14", "oadp:stable-1.4", "mta:stable-v7"}, res.Refs())
+	})
+
+	t.Run("picks first matching version when default does not match", func(t *testing.T) {
+		a := testAddon("a", "v1", true, types.AddonRequirement{ID: "cert-manager", Version: "<1.13"})
+		res, err := Resolve(ctx, catalog, []types.PostConfigAddon{a}, nil)
+		require.NoError(t, err)
+		require.Len(t, res.Added, 1)
+		assert.Equal(t, "stable-v1.12", res.Added[0].Version)
+	})
+
+	t.Run("selected requirement must match constraint", func(t *testing.T) {
+		certManager := testAddon("cert-manager", "stable-v1.10", false)
+		oadp := catalog["oadp"][0]
+		_, err := Resolve(ctx, catalog, []types.PostConfigAddon{certManager, oadp}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "oadp requires cert-manager >=1.12")
+	})
+
+	t.Run("selected requirement is not added again", func(t *testing.T) {
+		certManager := catalog["cert-manager"][1]
+		oadp := catalog["oadp"][0]
+		res, err := Resolve(ctx, catalog, []types.PostConfigAddon{oadp, certManager}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, res.Added)
+		assert.Equal(t, []string{"cert-manager", "oadp"}, resolvedIDs(res))
+	})
+
+	t.Run("unknown requirement", func(t *testing.T) {
+		a := testAddon("a", "v1", true, types.AddonRequirement{ID: "missing"})
+		_, err := Resolve(ctx, catalog, []types.PostConfigAddon{a}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "a requires missing")
+	})
+
+	t.Run("no version matches", func(t *testing.T) {
+		a := testAddon("a", "v1", true, types.AddonRequirement{ID: "cert-manager", Version: ">=2"})
+		_, err := Resolve(ctx, catalog, []types.PostConfigAddon{a}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no available version matches")
+	})
+
+	t.Run("dependency cycle", func(t *testing.T) {
+		cyclic := fakeCatalog{
+			"b": {testAddon("b", "v1", true, types.AddonRequirement{ID: "c"})},
+			"c": {testAddon("c", "v1", true, types.AddonRequirement{ID: "a"})},
+		}
+		a := testAddon("a", "v1", true, types.AddonRequirement{ID: "b"})
+		_, err := Resolve(ctx, cyclic, []types.PostConfigAddon{a}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "addon dependency cycle: a -> b -> c -> a")
+	})
+
+	t.Run("conflict with a requirement", func(t *testing.T) {
+		a := testAddon("a", "v1", true, types.AddonRequirement{ID: "odf"})
+		b := testAddon("b", "v1", true)
+		b.Metadata.ConflictsWith = []string{"odf"}
+		_, err := Resolve(ctx, catalog, []types.PostConfigAddon{a, b}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "conflict")
+	})
+
+	t.Run("duplicate selection", func(t *testing.T) {
+		odf := catalog["odf"][0]
+		_, err := Resolve(ctx, catalog, []types.PostConfigAddon{odf, odf}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("conditional requirement", func(t *testing.T) {
+		a := testAddon("a", "v1", true,
+			types.AddonRequirement{ID: "odf", Condition: "!('bare-metal' in capabilities)"})
+
+		res, err := Resolve(ctx, catalog, []types.PostConfigAddon{a}, &postconfig.TemplateContext{Capabilities: []string{"bare-metal"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, resolvedIDs(res))
+
+		res, err = Resolve(ctx, catalog, []types.PostConfigAddon{a}, &postconfig.TemplateContext{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"odf", "a"}, resolvedIDs(res))
+	})
+
+	t.Run("recommendations are reported but not added", func(t *testing.T) {
+		a := testAddon("a", "v1", true)
+		a.Metadata.Recommends = []types.AddonRequirement{{ID: "odf"}, {ID: "cert-manager"}}
+		certManager := catalog["cert-manager"][0]
+		res, err := Resolve(ctx, catalog, []types.PostConfigAddon{a, certManager}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "cert-manager"}, resolvedIDs(res))
+		assert.Equal(t, []types.AddonDependency{{AddonID: "odf", RequiredBy: "a"}}, res.Recommended)
+	})
+}
+
+func TestMatchesVersion(t *testing.T) {
+	tests := []struct {
+		channel    string
+		constraint string
+		want       bool
+	}{
+		{"stable-1.4", "", true},
+		{"stable-1.4", "stable-1.4", true},
+		{"stable-1.4", "stable-1.3", false},
+		{"stable-1.4", ">=1.4", true},
+		{"stable-1.3", ">=1.4", false},
+		{"release-v1.8", ">1.7, <2", true},
+		{"release-v2.0", ">1.7, <2", false},
+		{"stable-v1.14", "!=1.14", false},
+		{"stable", ">=1.0", false},
+		{"4.18", "==4.18", true},
+	}
+	for _, tt := range tests {
+		got, err := MatchesVersion(tt.channel, tt.constraint)
+		require.NoError(t, err, "%s %s", tt.channel, tt.constraint)
+		assert.Equal(t, tt.want, got, "%s %s", tt.channel, tt.constraint)
+	}
+
+	_, err := MatchesVersion("stable-1.4", ">=1.x")
+	assert.Error(t, err)
+}
+
+func TestValidateRequirements(t *testing.T) {
+	assert.NoError(t, ValidateRequirements("a", nil))
+	assert.NoError(t, ValidateRequirements("a", &types.AddonMetadata{
+		Requires:   []types.AddonRequirement{{ID: "b", Version: ">=1.2, <2"}, {ID: "c", Version: "stable"}},
+		Recommends: []types.AddonRequirement{{ID: "d", Condition: "'gpu' in capabilities"}},
+	}))
+
+	invalid := map[string]*types.AddonMetadata{
+		"missing id":        {Requires: []types.AddonRequirement{{Version: "stable"}}},
+		"self reference":    {Requires: []types.AddonRequirement{{ID: "a"}}},
+		"duplicate":         {Recommends: []types.AddonRequirement{{ID: "b"}, {ID: "b"}}},
+		"bad constraint":    {Requires: []types.AddonRequirement{{ID: "b", Version: ">=1.2, <x"}}},
+		"invalid condition": {Requires: []types.AddonRequirement{{ID: "b", Condition: "workerCount >"}}},
+	}
+	for name, meta := range invalid {
+		assert.Error(t, ValidateRequirements("a", meta), name)
+	}
+}
+
+func TestRequiredBy(t *testing.T) {
+	installed := []types.PostConfigAddon{
+		testAddon("oadp", "stable-1.4", true, types.AddonRequirement{ID: "cert-manager"}),
+		testAddon("mta", "stable-v7", true,
+			types.AddonRequirement{ID: "odf", Condition: "!('bare-metal' in capabilities)"}),
+		testAddon("cert-manager", "stable-v1.14", true),
+	}
+
+	assert.Equal(t, "oadp", RequiredBy("cert-manager", installed, nil))
+	assert.Empty(t, RequiredBy("oadp", installed, nil))
+	assert.Equal(t, "mta", RequiredBy("odf", installed, &postconfig.TemplateContext{}))
+	assert.Empty(t, RequiredBy("odf", installed, &postconfig.TemplateContext{Capabilities: []string{"bare-metal"}}))
+}
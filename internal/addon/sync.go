@@ -85,10 +85,21 @@ func (s *Syncer) syncVersion(ctx context.Context, addon *AddonDefinition, versio
 		return fmt.Errorf("marshal config: %w", err)
 	}
 
+	// A version's compatibility replaces the add-on's
+	metadata := addon.Metadata
+	if version.Compatibility != nil {
+		m := AddonMetadata{}
+		if metadata != nil {
+			m = *metadata
+		}
+		m.Compatibility = version.Compatibility
+		metadata = &m
+	}
+
 	// Marshal metadata to JSONB if present
 	var metadataJSON []byte
-	if addon.Metadata != nil {
-		metadataJSON, err = json.Marshal(addon.Metadata)
+	if metadata != nil {
+		metadataJSON, err = json.Marshal(metadata)
 		if err != nil {
 			return fmt.Errorf("marshal metadata: %w", err)
 		}
@@ -21,6 +21,18 @@ type AddonMetadata struct {
 	ConflictsWith        []string `yaml:"conflictsWith,omitempty" json:"conflictsWith,omitempty"`
 	Notes                []string `yaml:"notes,omitempty" json:"notes,omitempty"`
 	Warnings             []string `yaml:"warnings,omitempty" json:"warnings,omitempty"`
+
+	// SupportedArchitectures lists the node architectures the add-on's images
+	// are built for; empty means it runs on any
+	SupportedArchitectures []types.Architecture `yaml:"supportedArchitectures,omitempty" json:"supportedArchitectures,omitempty"`
+
+	// Requires lists add-ons installed along with this one; Recommends lists
+	// add-ons suggested alongside it
+	Requires   []types.AddonRequirement `yaml:"requires,omitempty" json:"requires,omitempty"`
+	Recommends []types.AddonRequirement `yaml:"recommends,omitempty" json:"recommends,omitempty"`
+
+	// Compatibility applies to versions that do not declare their own
+	Compatibility *types.AddonCompatibility `yaml:"compatibility,omitempty" json:"compatibility,omitempty"`
 }
 
 // AddonVersionConfig defines a specific version of an add-on
@@ -29,6 +41,10 @@ type AddonVersionConfig struct {
 	DisplayName string                 `yaml:"displayName" validate:"required"`
 	IsDefault   bool                   `yaml:"isDefault"`
 	Config      types.CustomPostConfig `yaml:"config" validate:"required"`
+
+	// Compatibility declares the cluster types and versions the channel
+	// supports, replacing the add-on's metadata.compatibility
+	Compatibility *types.AddonCompatibility `yaml:"compatibility,omitempty"`
 }
 
 // GetDefaultVersion returns the default version configuration
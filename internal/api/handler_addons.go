@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/addon"
 	"github.com/tsanders-rh/ocpctl/internal/auth"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
 	"github.com/tsanders-rh/ocpctl/internal/store"
@@ -26,39 +27,6 @@ func NewAddonsHandler(store *store.Store, registry *profile.Registry) *AddonsHan
 	}
 }
 
-// AddonWithVersions represents an add-on with all its versions
-type AddonWithVersions struct {
-	ID                 string               `json:"id" example:"oadp"`
-	Name               string               `json:"name" example:"OpenShift API for Data Protection (OADP)"`
-	Description        string               `json:"description" example:"Backup and restore OpenShift clusters and applications"`
-	Category           string               `json:"category" example:"backup"`
-	SupportedPlatforms []string             `json:"supportedPlatforms" example:"openshift"`
-	Enabled            bool                 `json:"enabled" example:"true"`
-	Versions           VersionsInfo         `json:"versions"`
-	Metadata           *types.AddonMetadata `json:"metadata,omitempty"`
-	AddonSource        string               `json:"addonSource" example:"system"`
-	IsPublished        bool                 `json:"isPublished" example:"true"`
-}
-
-// VersionsInfo contains version information
-type VersionsInfo struct {
-	Allowed []VersionOption `json:"allowed"`
-	Default string          `json:"default" example:"stable"`
-}
-
-// VersionOption represents a single version option
-type VersionOption struct {
-	Channel     string `json:"channel" example:"stable"`
-	DisplayName string `json:"displayName" example:"OADP 1.5 (Stable)"`
-}
-
-// AddonsListResponse represents the response from the list addons endpoint
-type AddonsListResponse struct {
-	Addons     []AddonWithVersions            `json:"addons"`
-	Categories map[string][]AddonWithVersions `json:"categories"`
-	Total      int                            `json:"total" example:"3"`
-}
-
 // ListAll returns all system addons and published user addons as individual records
 //
 //	@Summary		List all addons
@@ -119,14 +87,15 @@ func (h *AddonsHandler) ListAll(c echo.Context) error {
 // DEPRECATED: Use ListAll instead
 //
 //	@Summary		List add-ons (grouped by version)
-//	@Description	Lists all enabled post-config add-ons with version information. Supports filtering by category, platform, profile capabilities, and search query. Each add-on includes multiple versions with one marked as default.
+//	@Description	Lists all enabled post-config add-ons with version information. Supports filtering by category, platform, profile capabilities, cluster version, and search query. Each add-on includes multiple versions with one marked as default; when the default version is filtered out, the first compatible version becomes the default.
 //	@Tags			post-config
 //	@Produce		json
 //	@Param			category	query		string	false	"Filter by category (backup, migration, cicd, monitoring, security, storage, networking, virtualization)"
 //	@Param			platform	query		string	false	"Filter by supported platform (openshift, eks, iks)"
 //	@Param			profile		query		string	false	"Filter by profile capabilities (e.g., aws-minimal)"
+//	@Param			version		query		string	false	"Only list add-on versions compatible with this OpenShift or Kubernetes version"
 //	@Param			search		query		string	false	"Search in name and description"
-//	@Success		200			{object}	AddonsListResponse
+//	@Success		200			{object}	types.AddonsListResponse
 //	@Failure		401			{object}	ErrorResponse
 //	@Security		BearerAuth
 //	@Router			/post-config/addons/grouped [get]
@@ -137,6 +106,7 @@ func (h *AddonsHandler) List(c echo.Context) error {
 	category := c.QueryParam("category")
 	platform := c.QueryParam("platform")
 	profileName := c.QueryParam("profile")
+	clusterVersion := c.QueryParam("version")
 	search := c.QueryParam("search")
 
 	var categoryPtr *string
@@ -176,8 +146,9 @@ func (h *AddonsHandler) List(c echo.Context) error {
 	}
 
 	// Filter by profile capabilities if profile parameter is provided
+	var prof *profile.Profile
 	if profileName != "" {
-		prof, err := h.registry.GetAny(profileName)
+		prof, err = h.registry.GetAny(profileName)
 		if err != nil {
 			log.Printf("Warning: failed to get profile %s: %v", profileName, err)
 			// Don't fail the request, just skip capability filtering
@@ -189,6 +160,7 @@ func (h *AddonsHandler) List(c echo.Context) error {
 			if prof.Metadata != nil {
 				profileCapabilities = prof.Metadata.Capabilities
 			}
+			profileArchs := profile.NodeArchitectures(prof, prof.Compute.Architecture)
 
 			for _, addon := range addons {
 				// If addon has no metadata or no requirements, include it
@@ -221,6 +193,12 @@ func (h *AddonsHandler) List(c echo.Context) error {
 					}
 				}
 
+				// Check addon has images for the profile's node architectures
+				if !addon.Metadata.SupportsArchitectures(profileArchs) {
+					log.Printf("Filtering out addon %s: does not support architectures %v of profile %s", addon.AddonID, profileArchs, profileName)
+					continue
+				}
+
 				// Addon meets all requirements
 				filteredAddons = append(filteredAddons, addon)
 			}
@@ -228,6 +206,24 @@ func (h *AddonsHandler) List(c echo.Context) error {
 		}
 	}
 
+	// Filter add-on versions by the cluster type and version they support.
+	// The cluster type is the profile's, or the platform filter's.
+	clusterType := types.ClusterType(platform)
+	if prof != nil && prof.ClusterType != "" {
+		clusterType = prof.ClusterType
+	}
+	if clusterType != "" || clusterVersion != "" {
+		filteredAddons := make([]types.PostConfigAddon, 0, len(addons))
+		for _, a := range addons {
+			if err := addon.CheckCompatibility(a.Metadata, clusterType, clusterVersion); err != nil {
+				log.Printf("Filtering out addon %s version %s: %v", a.AddonID, a.Version, err)
+				continue
+			}
+			filteredAddons = append(filteredAddons, a)
+		}
+		addons = filteredAddons
+	}
+
 	// Client-side search filtering if search parameter is provided
 	if search != "" {
 		filteredAddons := make([]types.PostConfigAddon, 0)
@@ -241,10 +237,10 @@ func (h *AddonsHandler) List(c echo.Context) error {
 	}
 
 	// Group by addon_id and collect versions
-	grouped := make(map[string]*AddonWithVersions)
+	grouped := make(map[string]*types.AddonWithVersions)
 	for _, addon := range addons {
 		if _, exists := grouped[addon.AddonID]; !exists {
-			grouped[addon.AddonID] = &AddonWithVersions{
+			grouped[addon.AddonID] = &types.AddonWithVersions{
 				ID:                 addon.AddonID,
 				Name:               addon.Name,
 				Description:        addon.Description,
@@ -254,15 +250,15 @@ func (h *AddonsHandler) List(c echo.Context) error {
 				Metadata:           addon.Metadata,
 				AddonSource:        string(addon.AddonSource),
 				IsPublished:        addon.IsPublished,
-				Versions: VersionsInfo{
-					Allowed: []VersionOption{},
+				Versions: types.VersionsInfo{
+					Allowed: []types.VersionOption{},
 				},
 			}
 		}
 
 		grouped[addon.AddonID].Versions.Allowed = append(
 			grouped[addon.AddonID].Versions.Allowed,
-			VersionOption{
+			types.VersionOption{
 				Channel:     addon.Version,
 				DisplayName: addon.DisplayName,
 			},
@@ -273,14 +269,18 @@ func (h *AddonsHandler) List(c echo.Context) error {
 		}
 	}
 
-	// Convert to array
-	result := make([]AddonWithVersions, 0, len(grouped))
+	// Convert to array. An add-on whose default version was filtered out
+	// defaults to its first remaining version.
+	result := make([]types.AddonWithVersions, 0, len(grouped))
 	for _, addon := range grouped {
+		if addon.Versions.Default == "" && len(addon.Versions.Allowed) > 0 {
+			addon.Versions.Default = addon.Versions.Allowed[0].Channel
+		}
 		result = append(result, *addon)
 	}
 
 	// Group by category for UI display
-	categories := make(map[string][]AddonWithVersions)
+	categories := make(map[string][]types.AddonWithVersions)
 	for _, addon := range result {
 		categories[addon.Category] = append(categories[addon.Category], addon)
 	}
@@ -381,20 +381,6 @@ func (h *AddonsHandler) GetByID(c echo.Context) error {
 	return c.JSON(200, addon)
 }
 
-// CreateAddonRequest represents the request to create a new user addon
-type CreateAddonRequest struct {
-	AddonID            string                 `json:"addonId" validate:"required,min=1,max=100"`
-	Name               string                 `json:"name" validate:"required,min=1,max=200"`
-	Description        string                 `json:"description" validate:"required"`
-	Category           string                 `json:"category" validate:"required,oneof=backup migration cicd monitoring security storage networking virtualization"`
-	Config             types.CustomPostConfig `json:"config" validate:"required"`
-	SupportedPlatforms []string               `json:"supportedPlatforms" validate:"required,min=1"`
-	Version            string                 `json:"version" validate:"required"`
-	DisplayName        string                 `json:"displayName" validate:"required"`
-	IsDefault          bool                   `json:"isDefault"`
-	Metadata           *types.AddonMetadata   `json:"metadata,omitempty"`
-}
-
 // Create creates a new user addon
 //
 //	@Summary		Create user addon
@@ -402,7 +388,7 @@ type CreateAddonRequest struct {
 //	@Tags			post-config
 //	@Accept			json
 //	@Produce		json
-//	@Param			addon	body		CreateAddonRequest	true	"Addon creation request"
+//	@Param			addon	body		types.CreateAddonRequest	true	"Addon creation request"
 //	@Success		201		{object}	types.PostConfigAddon
 //	@Failure		400		{object}	ErrorResponse
 //	@Failure		401		{object}	ErrorResponse
@@ -416,7 +402,7 @@ func (h *AddonsHandler) Create(c echo.Context) error {
 		return err
 	}
 
-	var req CreateAddonRequest
+	var req types.CreateAddonRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
@@ -425,8 +411,25 @@ func (h *AddonsHandler) Create(c echo.Context) error {
 		return ErrorBadRequest(c, err.Error())
 	}
 
-	// Create addon object
-	addon := &types.PostConfigAddon{
+	if err := addon.ValidateRequirements(req.AddonID, req.Metadata); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+	if err := addon.ValidateCompatibility(req.Metadata); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+
+	addon := newUserAddon(&req, userID)
+	if err := h.store.PostConfigAddons.Create(ctx, addon); err != nil {
+		log.Printf("Error creating addon: %v", err)
+		return LogAndReturnGenericError(c, err)
+	}
+
+	return c.JSON(201, addon)
+}
+
+// newUserAddon builds a draft user addon from a create request
+func newUserAddon(req *types.CreateAddonRequest, userID string) *types.PostConfigAddon {
+	return &types.PostConfigAddon{
 		ID:                 uuid.New().String(),
 		AddonID:            req.AddonID,
 		Name:               req.Name,
@@ -445,26 +448,6 @@ func (h *AddonsHandler) Create(c echo.Context) error {
 		VersionNumber:      1,
 		IsImmutable:        false,
 	}
-
-	if err := h.store.PostConfigAddons.Create(ctx, addon); err != nil {
-		log.Printf("Error creating addon: %v", err)
-		return LogAndReturnGenericError(c, err)
-	}
-
-	return c.JSON(201, addon)
-}
-
-// UpdateAddonRequest represents the request to update an addon
-type UpdateAddonRequest struct {
-	Name               *string                 `json:"name,omitempty" validate:"omitempty,min=1,max=200"`
-	Description        *string                 `json:"description,omitempty"`
-	Category           *string                 `json:"category,omitempty" validate:"omitempty,oneof=backup migration cicd monitoring security storage networking virtualization"`
-	Config             *types.CustomPostConfig `json:"config,omitempty"`
-	SupportedPlatforms []string                `json:"supportedPlatforms,omitempty" validate:"omitempty,min=1"`
-	Version            *string                 `json:"version,omitempty"`
-	DisplayName        *string                 `json:"displayName,omitempty"`
-	IsDefault          *bool                   `json:"isDefault,omitempty"`
-	Metadata           *types.AddonMetadata    `json:"metadata,omitempty"`
 }
 
 // Update updates an existing user addon (draft only)
@@ -475,7 +458,7 @@ type UpdateAddonRequest struct {
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string				true	"Addon database ID"
-//	@Param			addon	body		UpdateAddonRequest	true	"Addon update request"
+//	@Param			addon	body		types.UpdateAddonRequest	true	"Addon update request"
 //	@Success		200		{object}	types.PostConfigAddon
 //	@Failure		400		{object}	ErrorResponse
 //	@Failure		401		{object}	ErrorResponse
@@ -518,7 +501,7 @@ func (h *AddonsHandler) Update(c echo.Context) error {
 		return ErrorBadRequest(c, "Cannot update published addons. Clone the addon to create a new version.")
 	}
 
-	var req UpdateAddonRequest
+	var req types.UpdateAddonRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
@@ -553,6 +536,12 @@ func (h *AddonsHandler) Update(c echo.Context) error {
 		existing.IsDefault = *req.IsDefault
 	}
 	if req.Metadata != nil {
+		if err := addon.ValidateRequirements(existing.AddonID, req.Metadata); err != nil {
+			return ErrorBadRequest(c, err.Error())
+		}
+		if err := addon.ValidateCompatibility(req.Metadata); err != nil {
+			return ErrorBadRequest(c, err.Error())
+		}
 		existing.Metadata = req.Metadata
 	}
 
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"errors"
+	"log"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/addon"
+	"github.com/tsanders-rh/ocpctl/internal/auth"
+	"github.com/tsanders-rh/ocpctl/internal/catalog"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// CatalogHandler handles HTTP requests for browsing OperatorHub catalogs
+type CatalogHandler struct {
+	store *store.Store
+	index *catalog.Index
+}
+
+// NewCatalogHandler creates a new catalog handler
+func NewCatalogHandler(store *store.Store, index *catalog.Index) *CatalogHandler {
+	return &CatalogHandler{
+		store: store,
+		index: index,
+	}
+}
+
+// ListPackages lists the operator packages of a catalog
+//
+//	@Summary		List catalog packages
+//	@Description	Lists the operator packages in an OperatorHub catalog (redhat-operators, certified-operators or community-operators) for an OpenShift version.
+//	@Tags			post-config
+//	@Produce		json
+//	@Param			catalog	path		string	true	"Catalog name"
+//	@Param			version	query		string	true	"OpenShift version, e.g. 4.18"
+//	@Param			search	query		string	false	"Search in name, display name and description"
+//	@Success		200		{object}	types.CatalogPackagesResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse	"Unknown catalog"
+//	@Failure		503		{object}	ErrorResponse	"Catalog browsing not configured, catalog not cached yet or unavailable"
+//	@Security		BearerAuth
+//	@Router			/post-config/catalog/{catalog}/packages [get]
+func (h *CatalogHandler) ListPackages(c echo.Context) error {
+	cat, err := h.catalog(c, c.Param("catalog"), c.QueryParam("version"))
+	if err != nil {
+		return catalogError(c, err)
+	}
+
+	packages := cat.Packages(c.QueryParam("search"))
+	return SuccessOK(c, types.CatalogPackagesResponse{
+		Catalog:  cat.Name,
+		Version:  cat.Version,
+		Packages: packages,
+		Total:    len(packages),
+	})
+}
+
+// GetPackage describes the head of one of a package's channels
+//
+//	@Summary		Get catalog package
+//	@Description	Describes an operator package: its channels, the install modes and suggested namespace of the channel head, and the example custom resources (alm-examples) it publishes.
+//	@Tags			post-config
+//	@Produce		json
+//	@Param			catalog	path		string	true	"Catalog name"
+//	@Param			package	path		string	true	"Package name"
+//	@Param			version	query		string	true	"OpenShift version, e.g. 4.18"
+//	@Param			channel	query		string	false	"Channel (defaults to the package's default channel)"
+//	@Success		200		{object}	types.CatalogPackageDetail
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse	"Unknown catalog, package or channel"
+//	@Failure		503		{object}	ErrorResponse	"Catalog browsing not configured, catalog not cached yet or unavailable"
+//	@Security		BearerAuth
+//	@Router			/post-config/catalog/{catalog}/packages/{package} [get]
+func (h *CatalogHandler) GetPackage(c echo.Context) error {
+	cat, err := h.catalog(c, c.Param("catalog"), c.QueryParam("version"))
+	if err != nil {
+		return catalogError(c, err)
+	}
+
+	detail, err := cat.Package(c.Param("package"), c.QueryParam("channel"))
+	if err != nil {
+		return catalogError(c, err)
+	}
+	return SuccessOK(c, detail)
+}
+
+// GenerateAddon creates a draft addon that installs a catalog package
+//
+//	@Summary		Generate addon from catalog
+//	@Description	Creates a draft user addon that subscribes to an operator package's channel and, optionally, creates one of its example custom resources. The draft can be edited and is published through the usual publish endpoint.
+//	@Tags			post-config
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		types.GenerateCatalogAddonRequest	true	"Catalog package to generate an addon for"
+//	@Success		201		{object}	types.PostConfigAddon
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse	"Unknown catalog, package, channel or example kind"
+//	@Failure		503		{object}	ErrorResponse	"Catalog browsing not configured, catalog not cached yet or unavailable"
+//	@Security		BearerAuth
+//	@Router			/post-config/catalog/addons [post]
+func (h *CatalogHandler) GenerateAddon(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	var req types.GenerateCatalogAddonRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorBadRequest(c, "Invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+
+	cat, err := h.catalog(c, req.Catalog, req.Version)
+	if err != nil {
+		return catalogError(c, err)
+	}
+	detail, err := cat.Package(req.Package, req.Channel)
+	if err != nil {
+		return catalogError(c, err)
+	}
+
+	draft, err := catalog.DraftAddon(detail, &req, cat.Version)
+	if err != nil {
+		if errors.Is(err, catalog.ErrNotFound) {
+			return ErrorNotFound(c, err.Error())
+		}
+		return ErrorBadRequest(c, err.Error())
+	}
+	if err := c.Validate(draft); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+	if err := addon.ValidateRequirements(draft.AddonID, draft.Metadata); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+
+	addon := newUserAddon(draft, userID)
+	if err := h.store.PostConfigAddons.Create(ctx, addon); err != nil {
+		log.Printf("Error creating addon from catalog: %v", err)
+		return LogAndReturnGenericError(c, err)
+	}
+
+	return SuccessCreated(c, addon)
+}
+
+// catalog loads a catalog for an OpenShift version
+func (h *CatalogHandler) catalog(c echo.Context, name, version string) (*catalog.Catalog, error) {
+	if !h.index.Enabled() {
+		return nil, catalog.ErrUnavailable
+	}
+	if version == "" {
+		return nil, catalog.ErrInvalidVersion
+	}
+	return h.index.Catalog(c.Request().Context(), name, version)
+}
+
+// catalogError maps catalog errors to responses. Catalogs that cannot be
+// loaded are logged with their cause and reported without server paths.
+func catalogError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, catalog.ErrNotFound):
+		return ErrorNotFound(c, err.Error())
+	case errors.Is(err, catalog.ErrInvalidVersion):
+		return ErrorBadRequest(c, "A valid OpenShift 4.x version is required, e.g. 4.18")
+	case errors.Is(err, catalog.ErrNotCached):
+		c.Response().Header().Set("Retry-After", "60")
+		return ErrorServiceUnavailable(c, "Operator catalog not cached yet; it is being rendered, retry later")
+	case errors.Is(err, catalog.ErrUnavailable):
+		log.Printf("Operator catalog unavailable: %v", err)
+		return ErrorServiceUnavailable(c, "Operator catalog is not available")
+	default:
+		return LogAndReturnGenericError(c, err)
+	}
+}
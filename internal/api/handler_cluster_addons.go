@@ -0,0 +1,404 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/addon"
+	"github.com/tsanders-rh/ocpctl/internal/auth"
+	"github.com/tsanders-rh/ocpctl/internal/clustercreate"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// addonJobTypes are the job types that change the add-ons installed on a cluster,
+// including drift checks, which may re-apply them. Only one of these (or a full
+// POST_CONFIGURE) may run against a cluster at a time.
+var addonJobTypes = map[types.JobType]bool{
+	types.JobTypePostConfigure:  true,
+	types.JobTypeAddonInstall:   true,
+	types.JobTypeAddonUpgrade:   true,
+	types.JobTypeAddonUninstall: true,
+	types.JobTypeDriftCheck:     true,
+}
+
+// InstallAddon handles POST /api/v1/clusters/:id/addons/:addon_id
+//
+//	@Summary		Install add-on on a running cluster
+//	@Description	Queues a job that installs an add-on on a READY cluster, together with any add-ons it requires that are not installed yet. The add-on's default version is used when no version is given.
+//	@Tags			Clusters
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string						true	"Cluster ID"
+//	@Param			addon_id	path		string						true	"Add-on ID"
+//	@Param			request		body		types.ClusterAddonRequest	false	"Add-on version"
+//	@Success		202			{object}	map[string]interface{}
+//	@Failure		400			{object}	map[string]string	"Cluster not ready, add-on disabled or incompatible"
+//	@Failure		403			{object}	map[string]string	"Forbidden - not cluster owner"
+//	@Failure		404			{object}	map[string]string	"Cluster or add-on not found"
+//	@Failure		409			{object}	map[string]string	"Add-on already installed, conflicting or another add-on job in progress"
+//	@Failure		500			{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/clusters/{id}/addons/{addon_id} [post]
+func (h *ClusterHandler) InstallAddon(c echo.Context) error {
+	ctx := c.Request().Context()
+	addonID := c.Param("addon_id")
+
+	var req types.ClusterAddonRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorBadRequest(c, "Invalid request body")
+	}
+
+	cluster, err := h.getAddonTargetCluster(c)
+	if cluster == nil {
+		return err
+	}
+
+	if _, ok := installedAddonRef(cluster, addonID); ok {
+		return ErrorConflict(c, fmt.Sprintf("Add-on '%s' is already installed on this cluster", addonID))
+	}
+
+	target, err := h.lookupAddon(ctx, cluster, addonID, req.Version)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrorNotFound(c, fmt.Sprintf("Add-on '%s' not found", types.AddonRef(addonID, req.Version)))
+		}
+		return LogAndReturnGenericError(c, fmt.Errorf("failed to retrieve addon: %w", err))
+	}
+	resolution, err := h.checkAddonCompatible(c, cluster, target)
+	if resolution == nil {
+		return err
+	}
+
+	return h.enqueueAddonJob(c, cluster, types.JobTypeAddonInstall, types.JobMetadata{
+		"addon_id": target.AddonID,
+		"version":  target.Version,
+	}, fmt.Sprintf("Add-on '%s' install queued", target.AddonID), map[string]interface{}{
+		"dependencies": resolution.Added,
+		"recommended":  resolution.Recommended,
+	})
+}
+
+// UpgradeAddon handles PATCH /api/v1/clusters/:id/addons/:addon_id
+//
+//	@Summary		Change add-on version on a running cluster
+//	@Description	Queues a job that moves an installed add-on to another version, updating its operator Subscription channels.
+//	@Tags			Clusters
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string						true	"Cluster ID"
+//	@Param			addon_id	path		string						true	"Add-on ID"
+//	@Param			request		body		types.ClusterAddonRequest	true	"Target add-on version"
+//	@Success		202			{object}	map[string]interface{}
+//	@Failure		400			{object}	map[string]string	"Missing version, cluster not ready or add-on incompatible"
+//	@Failure		403			{object}	map[string]string	"Forbidden - not cluster owner"
+//	@Failure		404			{object}	map[string]string	"Cluster, add-on or version not found"
+//	@Failure		409			{object}	map[string]string	"Add-on conflicting, requirement not installed or another add-on job in progress"
+//	@Failure		500			{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/clusters/{id}/addons/{addon_id} [patch]
+func (h *ClusterHandler) UpgradeAddon(c echo.Context) error {
+	ctx := c.Request().Context()
+	addonID := c.Param("addon_id")
+
+	var req types.ClusterAddonRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorBadRequest(c, "Invalid request body")
+	}
+	if req.Version == "" {
+		return ErrorBadRequest(c, "version is required")
+	}
+
+	cluster, err := h.getAddonTargetCluster(c)
+	if cluster == nil {
+		return err
+	}
+
+	ref, ok := installedAddonRef(cluster, addonID)
+	if !ok {
+		return ErrorNotFound(c, fmt.Sprintf("Add-on '%s' is not installed on this cluster", addonID))
+	}
+
+	current, err := h.lookupAddon(ctx, cluster, addonID, ref)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return LogAndReturnGenericError(c, fmt.Errorf("failed to retrieve installed addon: %w", err))
+	}
+	fromVersion := ref
+	if current != nil {
+		fromVersion = current.Version
+	}
+	if fromVersion == req.Version {
+		return ErrorBadRequest(c, fmt.Sprintf("Add-on '%s' is already at version '%s'", addonID, req.Version))
+	}
+
+	target, err := h.lookupAddon(ctx, cluster, addonID, req.Version)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrorNotFound(c, fmt.Sprintf("Add-on '%s' not found", types.AddonRef(addonID, req.Version)))
+		}
+		return LogAndReturnGenericError(c, fmt.Errorf("failed to retrieve addon: %w", err))
+	}
+	if len(target.Config.Operators) == 0 {
+		return ErrorBadRequest(c, fmt.Sprintf("Add-on '%s' has no operators to upgrade; uninstall and reinstall it instead", addonID))
+	}
+	resolution, err := h.checkAddonCompatible(c, cluster, target)
+	if resolution == nil {
+		return err
+	}
+	// An upgrade only moves the one add-on; new requirements must be
+	// installed separately first.
+	if len(resolution.Added) > 0 {
+		dep := resolution.Added[0]
+		return ErrorConflict(c, fmt.Sprintf(
+			"Add-on '%s' version '%s' requires '%s', which is not installed. Install '%s' first.",
+			addonID, target.Version, dep.AddonID, dep.AddonID))
+	}
+
+	return h.enqueueAddonJob(c, cluster, types.JobTypeAddonUpgrade, types.JobMetadata{
+		"addon_id":     target.AddonID,
+		"version":      target.Version,
+		"from_version": fromVersion,
+	}, fmt.Sprintf("Add-on '%s' upgrade to '%s' queued", target.AddonID, target.Version), nil)
+}
+
+// UninstallAddon handles DELETE /api/v1/clusters/:id/addons/:addon_id
+//
+//	@Summary		Uninstall add-on from a running cluster
+//	@Description	Queues a job that removes an installed add-on's custom resources, Subscriptions, CSVs, namespaces, manifests and Helm releases.
+//	@Tags			Clusters
+//	@Produce		json
+//	@Param			id			path		string	true	"Cluster ID"
+//	@Param			addon_id	path		string	true	"Add-on ID"
+//	@Success		202			{object}	map[string]interface{}
+//	@Failure		400			{object}	map[string]string	"Cluster not ready or cluster type unsupported"
+//	@Failure		403			{object}	map[string]string	"Forbidden - not cluster owner"
+//	@Failure		404			{object}	map[string]string	"Cluster not found or add-on not installed"
+//	@Failure		409			{object}	map[string]string	"Add-on required by another installed add-on or another add-on job in progress"
+//	@Failure		500			{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/clusters/{id}/addons/{addon_id} [delete]
+func (h *ClusterHandler) UninstallAddon(c echo.Context) error {
+	addonID := c.Param("addon_id")
+
+	cluster, err := h.getAddonTargetCluster(c)
+	if cluster == nil {
+		return err
+	}
+
+	ref, ok := installedAddonRef(cluster, addonID)
+	if !ok {
+		return ErrorNotFound(c, fmt.Sprintf("Add-on '%s' is not installed on this cluster", addonID))
+	}
+
+	installed := h.installedAddons(c.Request().Context(), cluster)
+	if dependent := addon.RequiredBy(addonID, installed, h.creator.AddonConditionContext(cluster)); dependent != "" {
+		return ErrorConflict(c, fmt.Sprintf(
+			"Add-on '%s' is required by installed add-on '%s'. Uninstall '%s' first.",
+			addonID, dependent, dependent))
+	}
+
+	return h.enqueueAddonJob(c, cluster, types.JobTypeAddonUninstall, types.JobMetadata{
+		"addon_id": addonID,
+		"version":  ref,
+	}, fmt.Sprintf("Add-on '%s' uninstall queued", addonID), nil)
+}
+
+// getAddonTargetCluster loads the cluster from the :id path parameter and checks
+// that the caller can access it and that it is READY with no add-on job running.
+// On failure it writes the error response and returns a nil cluster; callers
+// return the accompanying error as-is.
+func (h *ClusterHandler) getAddonTargetCluster(c echo.Context) (*types.Cluster, error) {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	cluster, err := h.store.Clusters.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrorNotFound(c, "Cluster not found")
+		}
+		return nil, LogAndReturnGenericError(c, fmt.Errorf("failed to retrieve cluster: %w", err))
+	}
+
+	// checkClusterAccess writes its 403 response and returns nil, so check
+	// whether a response was committed as well as the error.
+	if err := h.checkClusterAccess(c, cluster); err != nil || c.Response().Committed {
+		return nil, err
+	}
+
+	if caps, ok := h.providers.Capabilities(cluster.ClusterType); !ok || !caps.Addons {
+		return nil, ErrorBadRequest(c, fmt.Sprintf("Add-ons cannot be changed on %s clusters", cluster.ClusterType))
+	}
+
+	if cluster.Status != types.ClusterStatusReady {
+		return nil, ErrorBadRequest(c, fmt.Sprintf("Cluster must be in READY status (current: %s)", cluster.Status))
+	}
+
+	existingJobs, err := h.store.Jobs.ListByClusterID(ctx, id)
+	if err != nil {
+		return nil, LogAndReturnGenericError(c, fmt.Errorf("check existing jobs: %w", err))
+	}
+	for _, job := range existingJobs {
+		if addonJobTypes[job.JobType] &&
+			(job.Status == types.JobStatusPending || job.Status == types.JobStatusRunning || job.Status == types.JobStatusRetrying) {
+			return nil, ErrorConflict(c, fmt.Sprintf("A %s job is already in progress for this cluster", job.JobType))
+		}
+	}
+
+	return cluster, nil
+}
+
+// lookupAddon fetches an add-on by ID and version, falling back to its default
+// version when none is given. Draft add-ons are visible to the cluster owner.
+func (h *ClusterHandler) lookupAddon(ctx context.Context, cluster *types.Cluster, addonID, version string) (*types.PostConfigAddon, error) {
+	if version == "" {
+		return h.store.PostConfigAddons.GetByAddonID(ctx, addonID)
+	}
+	return h.store.PostConfigAddons.GetByAddonIDAndVersionForUser(ctx, addonID, version, cluster.OwnerID)
+}
+
+// checkAddonCompatible verifies an add-on is enabled, runs on the cluster's
+// node architectures and, together with the add-ons already installed,
+// resolves to a conflict-free set whose requirements are satisfiable. Any
+// add-on the resolution adds must pass the same enabled and architecture
+// checks, and all of them must support the cluster's type and version. On
+// failure the error response has already been written and the returned
+// resolution is nil.
+func (h *ClusterHandler) checkAddonCompatible(c echo.Context, cluster *types.Cluster, target *types.PostConfigAddon) (*addon.Resolution, error) {
+	ctx := c.Request().Context()
+
+	var nodeArchs []types.Architecture
+	if cluster.Profile != "" {
+		if prof, err := h.registry.GetAny(cluster.Profile); err != nil {
+			log.Printf("Warning: failed to get profile %s for addon compatibility check: %v", cluster.Profile, err)
+		} else {
+			nodeArchs = profile.NodeArchitectures(prof, profile.EffectiveArchitecture(prof, cluster.Architecture))
+		}
+	}
+	if err := checkAddonUsable(c, target, nodeArchs); err != nil || c.Response().Committed {
+		return nil, err
+	}
+
+	installed := h.installedAddons(ctx, cluster)
+	if conflict := types.FindAddonConflict(target, installed); conflict != "" {
+		return nil, ErrorConflict(c, fmt.Sprintf(
+			"addon conflict: '%s' conflicts with installed addon '%s'. Uninstall '%s' first.",
+			target.AddonID, conflict, conflict))
+	}
+
+	selection := make([]types.PostConfigAddon, 0, len(installed)+1)
+	for _, a := range installed {
+		if a.AddonID != target.AddonID {
+			selection = append(selection, a)
+		}
+	}
+	selection = append(selection, *target)
+
+	catalog := addon.NewStoreCatalog(h.store.PostConfigAddons, cluster.OwnerID)
+	resolution, err := addon.Resolve(ctx, catalog, selection, h.creator.AddonConditionContext(cluster))
+	if err != nil {
+		return nil, ErrorConflict(c, fmt.Sprintf("addon dependency resolution failed: %v", err))
+	}
+
+	// The target and the add-ons it pulls in must support the cluster's type
+	// and version; installed add-ons were checked when they were installed
+	adding := []types.PostConfigAddon{*target}
+	for _, a := range resolution.Addons {
+		if _, isInstalled := installedAddonRef(cluster, a.AddonID); isInstalled || a.AddonID == target.AddonID {
+			continue
+		}
+		if err := checkAddonUsable(c, &a, nodeArchs); err != nil || c.Response().Committed {
+			return nil, err
+		}
+		adding = append(adding, a)
+	}
+	if validation := h.policy.ValidateAddons(cluster.ClusterType, cluster.Version, adding); !validation.Valid {
+		return nil, ErrorValidation(c, validation)
+	}
+
+	return resolution, nil
+}
+
+// checkAddonUsable writes a 400 response if the add-on is disabled or does not
+// support every architecture in nodeArchs.
+func checkAddonUsable(c echo.Context, a *types.PostConfigAddon, nodeArchs []types.Architecture) error {
+	if err := clustercreate.CheckAddonUsable(a, nodeArchs); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+	return nil
+}
+
+// installedAddons resolves the cluster's selected add-on refs. An installed
+// add-on that no longer exists in the catalog has no metadata to check
+// against, so it is skipped.
+func (h *ClusterHandler) installedAddons(ctx context.Context, cluster *types.Cluster) []types.PostConfigAddon {
+	installed := make([]types.PostConfigAddon, 0, len(cluster.SelectedAddonIDs))
+	for _, ref := range cluster.SelectedAddonIDs {
+		id, version := types.ParseAddonRef(ref)
+		other, err := h.lookupAddon(ctx, cluster, id, version)
+		if err != nil {
+			log.Printf("Warning: failed to resolve installed addon %s on cluster %s: %v", ref, cluster.ID, err)
+			continue
+		}
+		installed = append(installed, *other)
+	}
+	return installed
+}
+
+// enqueueAddonJob creates a day-2 add-on job for the cluster and responds with
+// 202 Accepted. Entries in extra are added to the response body.
+func (h *ClusterHandler) enqueueAddonJob(c echo.Context, cluster *types.Cluster, jobType types.JobType, metadata types.JobMetadata, message string, extra map[string]interface{}) error {
+	ctx := c.Request().Context()
+
+	job := &types.Job{
+		ID:          uuid.New().String(),
+		ClusterID:   cluster.ID,
+		JobType:     jobType,
+		Status:      types.JobStatusPending,
+		Attempt:     1,
+		MaxAttempts: 3,
+		Metadata:    metadata,
+	}
+
+	if err := h.store.Jobs.Create(ctx, nil, job); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return ErrorConflict(c, "Another add-on or post-configuration job is already in progress for this cluster")
+		}
+		return LogAndReturnGenericError(c, fmt.Errorf("create %s job: %w", jobType, err))
+	}
+
+	userID, _ := auth.GetUserID(c)
+	LogInfo(c, "addon job queued",
+		"cluster_id", cluster.ID,
+		"job_type", jobType,
+		"addon_id", metadata["addon_id"],
+		"job_id", job.ID,
+		"user_id", userID)
+
+	resp := map[string]interface{}{
+		"message":    message,
+		"cluster_id": cluster.ID,
+		"addon_id":   metadata["addon_id"],
+		"job_id":     job.ID,
+	}
+	for k, v := range extra {
+		resp[k] = v
+	}
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+// installedAddonRef returns the selected version of addonID on the cluster
+// ("" for the default version) and whether the add-on is installed at all.
+func installedAddonRef(cluster *types.Cluster, addonID string) (string, bool) {
+	for _, ref := range cluster.SelectedAddonIDs {
+		if id, version := types.ParseAddonRef(ref); id == addonID {
+			return version, true
+		}
+	}
+	return "", false
+}
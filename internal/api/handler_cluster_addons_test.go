@@ -0,0 +1,93 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/api"
+	"github.com/tsanders-rh/ocpctl/internal/dbtest"
+	"github.com/tsanders-rh/ocpctl/internal/policy"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestClusterHandler_Addons_NotFound(t *testing.T) {
+	s := dbtest.New(t)
+	user := newTemplateUser(t, s)
+	registry, err := profile.NewRegistry(profile.NewLoader(""))
+	require.NoError(t, err)
+	h := api.NewClusterHandler(s, policy.NewEngine(nil), registry)
+
+	// The cluster has an add-on installed whose catalog entry no longer exists
+	cluster := &types.Cluster{
+		Name:             "addons-" + uuid.New().String()[:8],
+		Platform:         types.PlatformAWS,
+		ClusterType:      types.ClusterTypeOpenShift,
+		Version:          "4.18",
+		Profile:          "aws-sno-ga",
+		Region:           "us-east-1",
+		Owner:            user.Email,
+		OwnerID:          user.ID,
+		Team:             "test",
+		CostCenter:       "test",
+		Status:           types.ClusterStatusReady,
+		TTLHours:         72,
+		SelectedAddonIDs: []string{"retired-addon:v1"},
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+	require.NoError(t, s.Clusters.Create(ctx, cluster))
+
+	e := echo.New()
+	call := func(method, clusterID, addonID, body string, handler func(echo.Context) error) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id", "addon_id")
+		c.SetParamValues(clusterID, addonID)
+		setAuthContext(c, user)
+		require.NoError(t, handler(c))
+		return rec
+	}
+
+	t.Run("missing cluster", func(t *testing.T) {
+		missing := uuid.New().String()
+		for name, handler := range map[string]func(echo.Context) error{
+			"install":   h.InstallAddon,
+			"upgrade":   h.UpgradeAddon,
+			"uninstall": h.UninstallAddon,
+		} {
+			rec := call(http.MethodPost, missing, "retired-addon", `{"version":"v2"}`, handler)
+			assert.Equal(t, http.StatusNotFound, rec.Code, name)
+			assert.Contains(t, rec.Body.String(), "Cluster not found", name)
+		}
+	})
+
+	t.Run("missing add-on", func(t *testing.T) {
+		rec := call(http.MethodPost, cluster.ID, "no-such-addon", `{}`, h.InstallAddon)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Add-on 'no-such-addon' not found")
+
+		rec = call(http.MethodPost, cluster.ID, "no-such-addon", `{"version":"v2"}`, h.InstallAddon)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("upgrade of an installed add-on missing from the catalog", func(t *testing.T) {
+		// The installed version falls back to the cluster's ref; the target is missing too
+		rec := call(http.MethodPatch, cluster.ID, "retired-addon", `{"version":"v2"}`, h.UpgradeAddon)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Add-on 'retired-addon:v2' not found")
+
+		rec = call(http.MethodPatch, cluster.ID, "retired-addon", `{"version":"v1"}`, h.UpgradeAddon)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "already at version 'v1'")
+	})
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/auth"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// GetDrift handles GET /api/v1/clusters/:id/drift
+//
+//	@Summary		Get post-config drift report
+//	@Description	Returns the result of the cluster's most recent post-config drift check: which operators, manifests and Helm releases no longer match the recorded configuration, and whether they were re-applied.
+//	@Tags			Clusters
+//	@Produce		json
+//	@Param			id	path		string	true	"Cluster ID"
+//	@Success		200	{object}	types.ConfigDriftReport
+//	@Failure		403	{object}	map[string]string	"Forbidden - not cluster owner"
+//	@Failure		404	{object}	map[string]string	"Cluster not found or never checked"
+//	@Failure		500	{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/clusters/{id}/drift [get]
+func (h *ClusterHandler) GetDrift(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	cluster, err := h.store.Clusters.GetByID(ctx, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrorNotFound(c, "Cluster not found")
+		}
+		return LogAndReturnGenericError(c, fmt.Errorf("failed to retrieve cluster: %w", err))
+	}
+
+	// checkClusterAccess writes its 403 response and returns nil, so check
+	// whether a response was committed as well as the error.
+	if err := h.checkClusterAccess(c, cluster); err != nil || c.Response().Committed {
+		return err
+	}
+
+	report, err := h.store.ConfigDrift.GetByClusterID(ctx, cluster.ID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrorNotFound(c, "No drift check has run for this cluster")
+		}
+		return LogAndReturnGenericError(c, fmt.Errorf("get drift report: %w", err))
+	}
+
+	return SuccessOK(c, report)
+}
+
+// CheckDrift handles POST /api/v1/clusters/:id/drift-check
+//
+//	@Summary		Check post-config drift
+//	@Description	Queues a job that compares the cluster's installed add-ons and custom post-config with the cluster. With reconcile set, operators, manifests and Helm charts that drifted are re-applied. Scripts are not checked.
+//	@Tags			Clusters
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Cluster ID"
+//	@Param			request	body		types.DriftCheckRequest	false	"Drift check options"
+//	@Success		202		{object}	map[string]interface{}
+//	@Failure		400		{object}	map[string]string	"Cluster not ready or has no post-config"
+//	@Failure		403		{object}	map[string]string	"Forbidden - not cluster owner"
+//	@Failure		404		{object}	map[string]string	"Cluster not found"
+//	@Failure		409		{object}	map[string]string	"Another add-on or drift job in progress"
+//	@Failure		500		{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/clusters/{id}/drift-check [post]
+func (h *ClusterHandler) CheckDrift(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req types.DriftCheckRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorBadRequest(c, "Invalid request body")
+	}
+
+	cluster, err := h.getAddonTargetCluster(c)
+	if cluster == nil {
+		return err
+	}
+
+	if len(cluster.SelectedAddonIDs) == 0 && cluster.CustomPostConfig == nil {
+		return ErrorBadRequest(c, "Cluster has no add-ons or custom post-config to check")
+	}
+
+	job := &types.Job{
+		ID:          uuid.New().String(),
+		ClusterID:   cluster.ID,
+		JobType:     types.JobTypeDriftCheck,
+		Status:      types.JobStatusPending,
+		Attempt:     1,
+		MaxAttempts: 3,
+		Metadata: types.JobMetadata{
+			"reconcile": req.Reconcile,
+		},
+	}
+
+	if err := h.store.Jobs.Create(ctx, nil, job); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return ErrorConflict(c, "Another add-on or post-configuration job is already in progress for this cluster")
+		}
+		return LogAndReturnGenericError(c, fmt.Errorf("create drift check job: %w", err))
+	}
+
+	userID, _ := auth.GetUserID(c)
+	LogInfo(c, "drift check queued",
+		"cluster_id", cluster.ID,
+		"reconcile", req.Reconcile,
+		"job_id", job.ID,
+		"user_id", userID)
+
+	return c.JSON(http.StatusAccepted, map[string]interface{}{
+		"message":    "Drift check queued",
+		"cluster_id": cluster.ID,
+		"reconcile":  req.Reconcile,
+		"job_id":     job.ID,
+	})
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/auth"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+)
+
+const (
+	// EventStreamPath is the SSE firehose route. It is excluded from the
+	// timeout and gzip middleware because the connection stays open.
+	EventStreamPath = "/api/v1/events/stream"
+
+	// MaxEventLimit is the maximum number of events returned per request
+	MaxEventLimit = 500
+
+	eventStreamPollInterval      = 2 * time.Second
+	eventStreamHeartbeatInterval = 15 * time.Second
+	eventStreamBatchSize         = 100
+)
+
+// ClusterEventHandler handles cluster lifecycle event endpoints
+type ClusterEventHandler struct {
+	store *store.Store
+}
+
+// NewClusterEventHandler creates a new cluster event handler
+func NewClusterEventHandler(s *store.Store) *ClusterEventHandler {
+	return &ClusterEventHandler{
+		store: s,
+	}
+}
+
+// eventContext attributes cluster state changes made with the returned
+// context to the authenticated user
+func eventContext(c echo.Context, reason string) context.Context {
+	actor := store.EventActorSystem
+	if user, err := auth.GetUser(c); err == nil {
+		actor = user.Email
+	} else if userID, err := auth.GetUserID(c); err == nil {
+		actor = userID
+	}
+
+	return store.WithEventSource(c.Request().Context(), store.EventSource{
+		Actor:  actor,
+		Reason: reason,
+	})
+}
+
+// ListClusterEvents handles GET /api/v1/clusters/:id/events
+//
+//	@Summary		Get cluster lifecycle events
+//	@Description	Returns the state transitions of a cluster in order, with who caused each one and why
+//	@Tags			Clusters
+//	@Produce		json
+//	@Param			id			path		string	true	"Cluster ID"
+//	@Param			after_id	query		int		false	"Return events after this event ID"
+//	@Param			limit		query		int		false	"Number of events to return (default 100, max 500)"
+//	@Success		200			{object}	map[string]interface{}
+//	@Failure		403			{object}	map[string]string	"Forbidden - not cluster owner"
+//	@Failure		404			{object}	map[string]string	"Cluster not found"
+//	@Failure		500			{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/clusters/{id}/events [get]
+func (h *ClusterEventHandler) ListClusterEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+	clusterID := c.Param("id")
+
+	cluster, err := h.store.Clusters.GetByID(ctx, clusterID)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return ErrorNotFound(c, "Cluster not found")
+		}
+		return LogAndReturnGenericError(c, err)
+	}
+
+	if err := checkClusterAccess(c, cluster); err != nil {
+		return err
+	}
+
+	afterID := parseInt64Param(c.QueryParam("after_id"), 0)
+	limit := parseIntParam(c.QueryParam("limit"), 100)
+	if limit > MaxEventLimit {
+		limit = MaxEventLimit
+	}
+	if limit < 1 {
+		limit = 100
+	}
+
+	events, err := h.store.ClusterEvents.ListByCluster(ctx, clusterID, afterID, limit)
+	if err != nil {
+		return LogAndReturnGenericError(c, err)
+	}
+
+	nextAfterID := afterID
+	if len(events) > 0 {
+		nextAfterID = events[len(events)-1].ID
+	}
+
+	return SuccessOK(c, map[string]interface{}{
+		"events":        events,
+		"next_after_id": nextAfterID,
+		"has_more":      len(events) == limit,
+	})
+}
+
+// Stream handles GET /api/v1/events/stream
+//
+//	@Summary		Stream cluster lifecycle events
+//	@Description	Server-Sent Events stream of cluster lifecycle events. Admins receive events for every cluster, other users for the clusters they own. Reconnecting clients resume from the Last-Event-ID header; new clients start with events created after they connect unless after_id is given. Events are delivered in commit order, so an event can be held back briefly while an earlier cluster change is still being written.
+//	@Tags			Clusters
+//	@Produce		text/event-stream
+//	@Param			after_id	query	int	false	"Replay events after this event ID"
+//	@Success		200
+//	@Failure		500	{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/events/stream [get]
+func (h *ClusterEventHandler) Stream(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ownerID := ""
+	if !auth.IsAdmin(c) {
+		userID, err := auth.GetUserID(c)
+		if err != nil {
+			return err
+		}
+		ownerID = userID
+	}
+
+	lastID := parseInt64Param(c.Request().Header.Get("Last-Event-ID"), -1)
+	if lastID < 0 {
+		lastID = parseInt64Param(c.QueryParam("after_id"), -1)
+	}
+	var cursor store.EventCursor
+	var err error
+	if lastID < 0 {
+		cursor, err = h.store.ClusterEvents.LatestCursor(ctx)
+	} else {
+		cursor, err = h.store.ClusterEvents.CursorAt(ctx, lastID)
+	}
+	if err != nil {
+		return LogAndReturnGenericError(c, err)
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	poll := time.NewTicker(eventStreamPollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(eventStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+
+		case <-poll.C:
+			events, next, err := h.store.ClusterEvents.ListSince(ctx, cursor, ownerID, eventStreamBatchSize)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				log.Printf("Warning: failed to poll cluster events for stream: %v", err)
+				continue
+			}
+
+			for _, event := range events {
+				data, err := json.Marshal(event)
+				if err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+					return nil
+				}
+			}
+			cursor = next
+			if len(events) > 0 {
+				res.Flush()
+			}
+		}
+	}
+}
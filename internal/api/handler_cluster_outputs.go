@@ -16,35 +16,6 @@ import (
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
-// ClusterOutputsResponse represents the cluster outputs (kubeconfig, credentials, etc.)
-type ClusterOutputsResponse struct {
-	ClusterID          string                `json:"cluster_id"`
-	ClusterName        string                `json:"cluster_name"`
-	Status             string                `json:"status"`
-	APIUrl             string                `json:"api_url,omitempty"`
-	ConsoleURL         string                `json:"console_url,omitempty"`
-	DashboardToken     string                `json:"dashboard_token,omitempty"`      // Kubernetes Dashboard bearer token
-	Kubeconfig         string                `json:"kubeconfig,omitempty"`           // Full kubeconfig content
-	KubeconfigS3URI    string                `json:"kubeconfig_s3_uri,omitempty"`    // S3 URI to kubeconfig file
-	Kubeadmin          *KubeadminCredentials `json:"kubeadmin,omitempty"`            // Actual credentials
-	KubeadminSecretRef string                `json:"kubeadmin_secret_ref,omitempty"` // Reference to secret location
-
-	// ServiceAccount credentials for pool clusters
-	SAName           string     `json:"sa_name,omitempty"`
-	SANamespace      string     `json:"sa_namespace,omitempty"`
-	SAToken          string     `json:"sa_token,omitempty"`
-	SATokenExpiresAt *time.Time `json:"sa_token_expires_at,omitempty"`
-	OcLoginCommand   string     `json:"oc_login_command,omitempty"`
-
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
-}
-
-// KubeadminCredentials holds the kubeadmin username and password
-type KubeadminCredentials struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-}
-
 // validateOutputFilePath validates that an output file path is safe to access
 // Prevents path traversal attacks by ensuring paths are normalized and within allowed directories
 func validateOutputFilePath(path string) (string, error) {
@@ -98,7 +69,7 @@ func validateOutputFilePath(path string) (string, error) {
 //	@Accept			json
 //	@Produce		json
 //	@Param			id	path		string	true	"Cluster ID"
-//	@Success		200	{object}	ClusterOutputsResponse
+//	@Success		200	{object}	types.ClusterOutputsResponse
 //	@Failure		400	{object}	map[string]string	"Cluster not ready or outputs not available"
 //	@Failure		403	{object}	map[string]string	"Forbidden - not cluster owner"
 //	@Failure		404	{object}	map[string]string	"Cluster not found"
@@ -137,7 +108,7 @@ func (h *ClusterHandler) GetOutputs(c echo.Context) error {
 	}
 
 	// Build response
-	response := &ClusterOutputsResponse{
+	response := &types.ClusterOutputsResponse{
 		ClusterID:   cluster.ID,
 		ClusterName: cluster.Name,
 		Status:      string(cluster.Status),
@@ -229,7 +200,7 @@ func (h *ClusterHandler) GetOutputs(c echo.Context) error {
 			} else if passwordData, err := s3.DownloadFile(ctx, bucket, key); err != nil {
 				LogInfo(c, "failed to download kubeadmin password from s3", "error", err.Error(), "uri", ref)
 			} else {
-				response.Kubeadmin = &KubeadminCredentials{
+				response.Kubeadmin = &types.KubeadminCredentials{
 					Username: "kubeadmin",
 					Password: strings.TrimSpace(string(passwordData)),
 				}
@@ -245,7 +216,7 @@ func (h *ClusterHandler) GetOutputs(c echo.Context) error {
 			} else if passwordData, err := os.ReadFile(validatedPath); err != nil {
 				LogInfo(c, "failed to read kubeadmin password", "error", err.Error(), "path", validatedPath)
 			} else {
-				response.Kubeadmin = &KubeadminCredentials{
+				response.Kubeadmin = &types.KubeadminCredentials{
 					Username: "kubeadmin",
 					Password: strings.TrimSpace(string(passwordData)),
 				}
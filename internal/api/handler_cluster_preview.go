@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/auth"
+	"github.com/tsanders-rh/ocpctl/internal/clusterconfig"
+	"github.com/tsanders-rh/ocpctl/internal/cost"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// Preview handles POST /api/v1/clusters/preview
+//
+//	@Summary		Preview cluster
+//	@Description	Runs the same validation as cluster creation and renders what the worker would submit to the installer (install-config.yaml, eksctl ClusterConfig, or the rosa/gcloud/az/ibmcloud arguments) without creating anything. Secrets are redacted. Also returns the merged tags, the estimated hourly cost and the add-ons that would be installed, including those pulled in by add-on requirements.
+//	@Tags			clusters
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		types.CreateClusterAPIRequest	true	"Cluster configuration"
+//	@Success		200		{object}	types.ClusterPreview
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		422		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Security		BearerAuth
+//	@Router			/clusters/preview [post]
+func (h *ClusterHandler) Preview(c echo.Context) error {
+	var req types.CreateClusterAPIRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorBadRequest(c, "Invalid request body")
+	}
+
+	checked, err := h.validateCreateRequest(c, &req)
+	if checked == nil {
+		return err
+	}
+
+	// Build the cluster record the worker would receive. The ID and creation
+	// time only exist once the cluster is created, so the provenance tags
+	// rendered here carry placeholder values for them.
+	ownerID, _ := auth.GetUserID(c)
+	cluster, err := h.creator.Build(&req, ownerID, checked)
+	if err != nil {
+		return writeCreateError(c, err)
+	}
+
+	resolution, err := h.creator.ResolveAddons(c.Request().Context(), &req, cluster, checked.Profile)
+	if err != nil {
+		return writeCreateError(c, err)
+	}
+
+	preview, err := clusterconfig.Preview(cluster, checked.Profile)
+	if err != nil {
+		log.Printf("[ERROR] Failed to render preview for cluster %s: %v", req.Name, err)
+		return ErrorBadRequest(c, fmt.Sprintf("Failed to render cluster: %v", err))
+	}
+	preview.EstimatedHourlyCost = cost.ShapeHourlyCost(checked.Profile, cluster.ComputeOverrides)
+	preview.Addons = cluster.SelectedAddonIDs
+	preview.AddonDependencies = resolution.Added
+	preview.RecommendedAddons = resolution.Recommended
+
+	return SuccessOK(c, preview)
+}
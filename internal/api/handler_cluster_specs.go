@@ -0,0 +1,489 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/auth"
+	"github.com/tsanders-rh/ocpctl/internal/gitops"
+	"github.com/tsanders-rh/ocpctl/internal/policy"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// DefaultClusterSpecSyncInterval is used when a git spec does not set one
+const DefaultClusterSpecSyncInterval = 5
+
+// ClusterSpecHandler handles declarative cluster spec endpoints
+type ClusterSpecHandler struct {
+	store      *store.Store
+	reconciler *gitops.Reconciler
+	syncer     *gitops.Syncer
+}
+
+// NewClusterSpecHandler creates a new cluster spec handler
+func NewClusterSpecHandler(s *store.Store, policyEngine *policy.Engine, registry *profile.Registry) *ClusterSpecHandler {
+	reconciler := gitops.NewReconciler(s, policyEngine, registry)
+	return &ClusterSpecHandler{
+		store:      s,
+		reconciler: reconciler,
+		syncer:     gitops.NewSyncer(nil, s, reconciler),
+	}
+}
+
+// readSpecDocument parses the request body as a YAML or JSON document, or a
+// gzipped tar bundle of spec files. It also returns a digest of the body,
+// used as the revision of uploaded documents.
+func readSpecDocument(c echo.Context) (*types.ClusterSpecDocument, string, error) {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, gitops.MaxDocumentSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(body) > gitops.MaxDocumentSize {
+		return nil, "", fmt.Errorf("document exceeds %d bytes", gitops.MaxDocumentSize)
+	}
+
+	var doc *types.ClusterSpecDocument
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	if strings.Contains(contentType, "gzip") || bytes.HasPrefix(body, []byte{0x1f, 0x8b}) {
+		doc, err = gitops.ParseBundle(bytes.NewReader(body))
+	} else {
+		doc, err = gitops.ParseDocument(body)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	digest := sha256.Sum256(body)
+	return doc, "sha256:" + hex.EncodeToString(digest[:]), nil
+}
+
+// checkSpecDocument validates a document and that the user may declare it
+func checkSpecDocument(c echo.Context, doc *types.ClusterSpecDocument) error {
+	if err := gitops.Validate(doc); err != nil {
+		return err
+	}
+	user, err := auth.GetUser(c)
+	if err != nil {
+		return err
+	}
+	return gitops.CheckOwnership(doc, user)
+}
+
+// getSpec loads the named spec and checks the user owns it or is an admin
+func (h *ClusterSpecHandler) getSpec(c echo.Context) (*types.ClusterSpec, error) {
+	spec, err := h.store.ClusterSpecs.GetByName(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrorNotFound(c, "Cluster spec not found")
+		}
+		return nil, LogAndReturnGenericError(c, err)
+	}
+
+	if !auth.IsAdmin(c) {
+		userID, err := auth.GetUserID(c)
+		if err != nil {
+			return nil, err
+		}
+		if spec.OwnerID != userID {
+			return nil, ErrorForbidden(c, "You do not have access to this cluster spec")
+		}
+	}
+
+	return spec, nil
+}
+
+// Plan handles POST /api/v1/cluster-specs/plan
+//
+//	@Summary		Plan a cluster spec document
+//	@Description	Computes what applying a desired-state document would do, without registering or applying it. The body is a YAML or JSON document, or a gzipped tar bundle of spec files.
+//	@Tags			ClusterSpecs
+//	@Accept			application/yaml,json,application/gzip
+//	@Produce		json
+//	@Success		200	{object}	types.ClusterSpecSyncResult
+//	@Failure		400	{object}	map[string]string	"Invalid document"
+//	@Failure		500	{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/cluster-specs/plan [post]
+func (h *ClusterSpecHandler) Plan(c echo.Context) error {
+	doc, revision, err := readSpecDocument(c)
+	if err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+	if err := checkSpecDocument(c, doc); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+
+	plan, err := h.reconciler.Plan(c.Request().Context(), nil, doc)
+	if err != nil {
+		return LogAndReturnGenericError(c, err)
+	}
+	plan.Revision = revision
+
+	return SuccessOK(c, &types.ClusterSpecSyncResult{
+		Spec:     doc.Metadata.Name,
+		Revision: revision,
+		DryRun:   true,
+		Plan:     plan,
+	})
+}
+
+// List handles GET /api/v1/cluster-specs
+//
+//	@Summary		List cluster specs
+//	@Description	Lists registered cluster specs. Admins see all specs, other users see their own.
+//	@Tags			ClusterSpecs
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		500	{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/cluster-specs [get]
+func (h *ClusterSpecHandler) List(c echo.Context) error {
+	ownerID := ""
+	if !auth.IsAdmin(c) {
+		userID, err := auth.GetUserID(c)
+		if err != nil {
+			return err
+		}
+		ownerID = userID
+	}
+
+	specs, err := h.store.ClusterSpecs.List(c.Request().Context(), ownerID)
+	if err != nil {
+		return LogAndReturnGenericError(c, err)
+	}
+
+	return SuccessOK(c, map[string]interface{}{
+		"specs": specs,
+	})
+}
+
+// Create handles POST /api/v1/cluster-specs
+//
+//	@Summary		Register a cluster spec
+//	@Description	Registers a cluster spec. Git specs are synced by the worker every sync_interval_minutes. Upload specs are applied when a document is uploaded with PUT /cluster-specs/{name}/document, or on POST /cluster-specs/{name}/sync.
+//	@Tags			ClusterSpecs
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		types.CreateClusterSpecRequest	true	"Cluster spec"
+//	@Success		201		{object}	types.ClusterSpec
+//	@Failure		400		{object}	map[string]string	"Invalid request"
+//	@Failure		409		{object}	map[string]string	"Name already exists"
+//	@Failure		500		{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/cluster-specs [post]
+func (h *ClusterSpecHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req types.CreateClusterSpecRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorBadRequest(c, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+	if !req.Source.IsValid() {
+		return ErrorBadRequest(c, "source must be 'upload' or 'git'")
+	}
+
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	spec := &types.ClusterSpec{
+		Name:                req.Name,
+		Source:              req.Source,
+		SyncIntervalMinutes: req.SyncIntervalMinutes,
+		Prune:               req.Prune,
+		OwnerID:             userID,
+	}
+	if spec.SyncIntervalMinutes == 0 {
+		spec.SyncIntervalMinutes = DefaultClusterSpecSyncInterval
+	}
+
+	switch req.Source {
+	case types.ClusterSpecSourceGit:
+		if req.GitURL == nil || *req.GitURL == "" {
+			return ErrorBadRequest(c, "git_url is required for git cluster specs")
+		}
+		if req.Document != nil {
+			return ErrorBadRequest(c, "document cannot be set on git cluster specs")
+		}
+		if err := gitops.ValidateGitSource(*req.GitURL, stringValue(req.GitRef)); err != nil {
+			return ErrorBadRequest(c, err.Error())
+		}
+		spec.GitURL = req.GitURL
+		spec.GitRef = req.GitRef
+		spec.GitPath = req.GitPath
+
+	case types.ClusterSpecSourceUpload:
+		if req.GitURL != nil || req.GitRef != nil || req.GitPath != nil {
+			return ErrorBadRequest(c, "git settings cannot be set on upload cluster specs")
+		}
+		if req.Document != nil {
+			if err := checkSpecDocument(c, req.Document); err != nil {
+				return ErrorBadRequest(c, err.Error())
+			}
+			revision := "initial"
+			spec.Document = req.Document
+			spec.Revision = &revision
+		}
+	}
+
+	if err := h.store.ClusterSpecs.Create(ctx, spec); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return ErrorConflict(c, fmt.Sprintf("Cluster spec '%s' already exists", req.Name))
+		}
+		return LogAndReturnGenericError(c, err)
+	}
+
+	LogInfo(c, "cluster spec created",
+		"spec_id", spec.ID,
+		"spec_name", spec.Name,
+		"source", string(spec.Source),
+		"user_id", userID)
+
+	return SuccessCreated(c, spec)
+}
+
+// Get handles GET /api/v1/cluster-specs/:name
+//
+//	@Summary		Get a cluster spec
+//	@Description	Returns a cluster spec, its last plan and sync status, and the resources it manages
+//	@Tags			ClusterSpecs
+//	@Produce		json
+//	@Param			name	path		string	true	"Cluster spec name"
+//	@Success		200		{object}	types.ClusterSpecDetail
+//	@Failure		403		{object}	map[string]string	"Forbidden - not spec owner"
+//	@Failure		404		{object}	map[string]string	"Cluster spec not found"
+//	@Failure		500		{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/cluster-specs/{name} [get]
+func (h *ClusterSpecHandler) Get(c echo.Context) error {
+	spec, err := h.getSpec(c)
+	if spec == nil {
+		return err
+	}
+
+	resources, err := h.store.ClusterSpecs.ListResources(c.Request().Context(), spec.ID)
+	if err != nil {
+		return LogAndReturnGenericError(c, err)
+	}
+
+	return SuccessOK(c, &types.ClusterSpecDetail{ClusterSpec: spec, Resources: resources})
+}
+
+// Update handles PATCH /api/v1/cluster-specs/:name
+//
+//	@Summary		Update a cluster spec
+//	@Description	Updates the git source, sync interval or prune setting of a cluster spec
+//	@Tags			ClusterSpecs
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string							true	"Cluster spec name"
+//	@Param			body	body		types.UpdateClusterSpecRequest	true	"Settings to change"
+//	@Success		200		{object}	types.ClusterSpec
+//	@Failure		400		{object}	map[string]string	"Invalid request"
+//	@Failure		403		{object}	map[string]string	"Forbidden - not spec owner"
+//	@Failure		404		{object}	map[string]string	"Cluster spec not found"
+//	@Failure		500		{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/cluster-specs/{name} [patch]
+func (h *ClusterSpecHandler) Update(c echo.Context) error {
+	spec, err := h.getSpec(c)
+	if spec == nil {
+		return err
+	}
+
+	var req types.UpdateClusterSpecRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorBadRequest(c, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+
+	if spec.Source != types.ClusterSpecSourceGit && (req.GitURL != nil || req.GitRef != nil || req.GitPath != nil) {
+		return ErrorBadRequest(c, "git settings cannot be set on upload cluster specs")
+	}
+	if req.GitURL != nil {
+		if *req.GitURL == "" {
+			return ErrorBadRequest(c, "git_url cannot be empty")
+		}
+		spec.GitURL = req.GitURL
+	}
+	if req.GitRef != nil {
+		spec.GitRef = req.GitRef
+	}
+	if req.GitPath != nil {
+		spec.GitPath = req.GitPath
+	}
+	if req.GitURL != nil || req.GitRef != nil {
+		if err := gitops.ValidateGitSource(stringValue(spec.GitURL), stringValue(spec.GitRef)); err != nil {
+			return ErrorBadRequest(c, err.Error())
+		}
+	}
+	if req.SyncIntervalMinutes != nil {
+		spec.SyncIntervalMinutes = *req.SyncIntervalMinutes
+	}
+	if req.Prune != nil {
+		spec.Prune = *req.Prune
+	}
+
+	if err := h.store.ClusterSpecs.Update(c.Request().Context(), spec); err != nil {
+		return LogAndReturnGenericError(c, err)
+	}
+
+	return SuccessOK(c, spec)
+}
+
+// Delete handles DELETE /api/v1/cluster-specs/:name
+//
+//	@Summary		Delete a cluster spec
+//	@Description	Unregisters a cluster spec. Clusters, pools and links it created are left in place and no longer managed.
+//	@Tags			ClusterSpecs
+//	@Produce		json
+//	@Param			name	path		string	true	"Cluster spec name"
+//	@Success		200		{object}	map[string]string
+//	@Failure		403		{object}	map[string]string	"Forbidden - not spec owner"
+//	@Failure		404		{object}	map[string]string	"Cluster spec not found"
+//	@Failure		500		{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/cluster-specs/{name} [delete]
+func (h *ClusterSpecHandler) Delete(c echo.Context) error {
+	spec, err := h.getSpec(c)
+	if spec == nil {
+		return err
+	}
+
+	if err := h.store.ClusterSpecs.Delete(c.Request().Context(), spec.ID); err != nil {
+		return LogAndReturnGenericError(c, err)
+	}
+
+	LogInfo(c, "cluster spec deleted",
+		"spec_id", spec.ID,
+		"spec_name", spec.Name)
+
+	return SuccessOK(c, map[string]string{
+		"message": "cluster spec deleted",
+	})
+}
+
+// PutDocument handles PUT /api/v1/cluster-specs/:name/document
+//
+//	@Summary		Upload a cluster spec document
+//	@Description	Replaces the desired-state document of an upload cluster spec and applies it. With dry_run=true the plan is returned and nothing is stored or changed. The body is a YAML or JSON document, or a gzipped tar bundle of spec files.
+//	@Tags			ClusterSpecs
+//	@Accept			application/yaml,json,application/gzip
+//	@Produce		json
+//	@Param			name	path		string	true	"Cluster spec name"
+//	@Param			dry_run	query		bool	false	"Only compute the plan"
+//	@Success		200		{object}	types.ClusterSpecSyncResult
+//	@Failure		400		{object}	map[string]string	"Invalid document"
+//	@Failure		403		{object}	map[string]string	"Forbidden - not spec owner"
+//	@Failure		404		{object}	map[string]string	"Cluster spec not found"
+//	@Failure		409		{object}	map[string]string	"Sync already in progress"
+//	@Failure		500		{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/cluster-specs/{name}/document [put]
+func (h *ClusterSpecHandler) PutDocument(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	spec, err := h.getSpec(c)
+	if spec == nil {
+		return err
+	}
+	if spec.Source != types.ClusterSpecSourceUpload {
+		return ErrorBadRequest(c, "The document of a git cluster spec is read from its repository")
+	}
+
+	doc, revision, err := readSpecDocument(c)
+	if err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+	if err := checkSpecDocument(c, doc); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+
+	if c.QueryParam("dry_run") == "true" {
+		plan, err := h.reconciler.Plan(ctx, spec, doc)
+		if err != nil {
+			return LogAndReturnGenericError(c, err)
+		}
+		plan.Revision = revision
+		return SuccessOK(c, &types.ClusterSpecSyncResult{Spec: spec.Name, Revision: revision, DryRun: true, Plan: plan})
+	}
+
+	if err := h.store.ClusterSpecs.SetDocument(ctx, spec.ID, doc, revision); err != nil {
+		return LogAndReturnGenericError(c, err)
+	}
+	spec.Document = doc
+	spec.Revision = &revision
+
+	return h.sync(c, spec, false)
+}
+
+// Sync handles POST /api/v1/cluster-specs/:name/sync
+//
+//	@Summary		Sync a cluster spec
+//	@Description	Loads the spec's document (fetching it from git for git specs) and applies it now. With dry_run=true the plan is returned and nothing is changed.
+//	@Tags			ClusterSpecs
+//	@Produce		json
+//	@Param			name	path		string	true	"Cluster spec name"
+//	@Param			dry_run	query		bool	false	"Only compute the plan"
+//	@Success		200		{object}	types.ClusterSpecSyncResult
+//	@Failure		400		{object}	map[string]string	"Document could not be loaded or is invalid"
+//	@Failure		403		{object}	map[string]string	"Forbidden - not spec owner"
+//	@Failure		404		{object}	map[string]string	"Cluster spec not found"
+//	@Failure		409		{object}	map[string]string	"Sync already in progress"
+//	@Failure		500		{object}	map[string]string
+//	@Security		BearerAuth
+//	@Router			/cluster-specs/{name}/sync [post]
+func (h *ClusterSpecHandler) Sync(c echo.Context) error {
+	spec, err := h.getSpec(c)
+	if spec == nil {
+		return err
+	}
+
+	return h.sync(c, spec, c.QueryParam("dry_run") == "true")
+}
+
+func (h *ClusterSpecHandler) sync(c echo.Context, spec *types.ClusterSpec, dryRun bool) error {
+	result, err := h.syncer.Sync(c.Request().Context(), spec, dryRun)
+	if err != nil {
+		if errors.Is(err, gitops.ErrSyncInProgress) {
+			return ErrorConflict(c, err.Error())
+		}
+		// Load and validation failures are recorded on the spec as well
+		var docErr *gitops.DocumentError
+		if errors.As(err, &docErr) {
+			return ErrorBadRequest(c, err.Error())
+		}
+		return LogAndReturnGenericError(c, err)
+	}
+
+	if !dryRun {
+		LogInfo(c, "cluster spec synced",
+			"spec_id", spec.ID,
+			"spec_name", spec.Name,
+			"revision", result.Revision,
+			"actions", len(result.Results))
+	}
+
+	return SuccessOK(c, result)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -23,13 +23,6 @@ func NewClusterTemplateHandler(s *store.Store) *ClusterTemplateHandler {
 	return &ClusterTemplateHandler{store: s}
 }
 
-// ClusterTemplateRequest is the body for creating or updating a cluster template.
-// Config is a partial create-cluster payload captured from the creation form.
-type ClusterTemplateRequest struct {
-	Name   string          `json:"name" validate:"required,min=3,max=100"`
-	Config json.RawMessage `json:"config" validate:"required"`
-}
-
 // sanitizeTemplateConfig ensures the config is a JSON object and strips any
 // cluster "name" so a template can never carry a cluster name.
 func sanitizeTemplateConfig(raw json.RawMessage) (json.RawMessage, bool) {
@@ -50,7 +43,7 @@ func sanitizeTemplateConfig(raw json.RawMessage) (json.RawMessage, bool) {
 func (h *ClusterTemplateHandler) Create(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	var req ClusterTemplateRequest
+	var req types.ClusterTemplateRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
@@ -134,7 +127,7 @@ func (h *ClusterTemplateHandler) Update(c echo.Context) error {
 
 	id := c.Param("id")
 
-	var req ClusterTemplateRequest
+	var req types.ClusterTemplateRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
@@ -21,29 +21,34 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/tsanders-rh/ocpctl/internal/auth"
+	"github.com/tsanders-rh/ocpctl/internal/clustercreate"
 	"github.com/tsanders-rh/ocpctl/internal/policy"
 	"github.com/tsanders-rh/ocpctl/internal/postconfig"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
 	"github.com/tsanders-rh/ocpctl/internal/s3"
 	"github.com/tsanders-rh/ocpctl/internal/store"
-	validation2 "github.com/tsanders-rh/ocpctl/internal/validation"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 // ClusterHandler handles cluster-related API endpoints
 type ClusterHandler struct {
-	store    *store.Store
-	policy   *policy.Engine
-	registry *profile.Registry
+	store     *store.Store
+	policy    *policy.Engine
+	registry  *profile.Registry
+	providers *provider.Registry
+	creator   *clustercreate.Creator
 }
 
 // NewClusterHandler creates a new cluster handler with dependencies for database access, policy enforcement, and profile management.
 // The handler provides endpoints for cluster lifecycle operations (create, list, update, delete, extend TTL).
 func NewClusterHandler(s *store.Store, p *policy.Engine, r *profile.Registry) *ClusterHandler {
 	return &ClusterHandler{
-		store:    s,
-		policy:   p,
-		registry: r,
+		store:     s,
+		policy:    p,
+		registry:  r,
+		providers: provider.NewRegistry(),
+		creator:   clustercreate.New(s, p, r),
 	}
 }
 
@@ -102,39 +107,6 @@ func (h *ClusterHandler) checkClusterAccess(c echo.Context, cluster *types.Clust
 	return ErrorForbidden(c, "You do not have access to this cluster")
 }
 
-// CreateClusterRequest represents the API request to create a cluster
-type CreateClusterRequest struct {
-	Name               string                   `json:"name" validate:"required,min=3,max=63,cluster_name"`
-	Platform           string                   `json:"platform" validate:"required,oneof=aws ibmcloud gcp azure"`
-	ClusterType        string                   `json:"cluster_type" validate:"required,oneof=openshift rosa eks iks gke aro aks"`
-	Version            string                   `json:"version" validate:"required"`
-	Profile            string                   `json:"profile" validate:"required"`
-	Region             string                   `json:"region" validate:"required"`
-	BaseDomain         string                   `json:"base_domain,omitempty"` // Only required for OpenShift
-	Owner              string                   `json:"owner" validate:"required,email"`
-	Team               string                   `json:"team" validate:"required"`
-	CostCenter         string                   `json:"cost_center" validate:"required"`
-	TTLHours           *int                     `json:"ttl_hours,omitempty"`
-	SSHPublicKey       *string                  `json:"ssh_public_key,omitempty"`
-	ExtraTags          map[string]string        `json:"extra_tags,omitempty"`
-	OffhoursOptIn      bool                     `json:"offhours_opt_in,omitempty"`
-	WorkHoursEnabled   *bool                    `json:"work_hours_enabled,omitempty"`
-	WorkHours          *types.WorkHoursSchedule `json:"work_hours,omitempty"`
-	SkipPostDeployment bool                     `json:"skip_post_deployment,omitempty"`
-	EnableEFSStorage   bool                     `json:"enable_efs_storage,omitempty"`
-	PostConfigAddOns   []types.AddonSelection   `json:"postConfigAddOns,omitempty"` // Pre-approved add-ons with version selection
-	CustomPostConfig   *types.CustomPostConfig  `json:"customPostConfig,omitempty"` // Custom post-deployment operators, scripts, and manifests
-	PreserveOnFailure  bool                     `json:"preserve_on_failure,omitempty"`
-	CredentialsMode    *string                  `json:"credentials_mode,omitempty" validate:"omitempty,oneof=Auto Manual Passthrough Mint Static"`
-	CustomPullSecret   *string                  `json:"custom_pull_secret,omitempty"` // Optional custom pull secret JSON to merge with standard pull secret
-	IdempotencyKey     string                   `json:"idempotency_key,omitempty"`
-}
-
-// ExtendClusterRequest represents the API request to extend cluster TTL
-type ExtendClusterRequest struct {
-	TTLHours int `json:"ttl_hours" validate:"required,min=1"`
-}
-
 // ListClustersFilters holds filter parameters for listing clusters
 type ListClustersFilters struct {
 	Platform   string
@@ -152,7 +124,7 @@ type ListClustersFilters struct {
 //	@Tags			clusters
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		CreateClusterRequest	true	"Cluster configuration"
+//	@Param			request	body		types.CreateClusterAPIRequest	true	"Cluster configuration"
 //	@Success		201		{object}	types.Cluster
 //	@Failure		400		{object}	ErrorResponse
 //	@Failure		401		{object}	ErrorResponse
@@ -164,7 +136,7 @@ type ListClustersFilters struct {
 func (h *ClusterHandler) Create(c echo.Context) error {
 	ctx := c.Request().Context()
 	debugLog("Create cluster endpoint called")
-	var req CreateClusterRequest
+	var req types.CreateClusterAPIRequest
 	if err := c.Bind(&req); err != nil {
 		log.Printf("[ERROR] Failed to bind request: %v", err)
 		return ErrorBadRequest(c, "Invalid request body")
@@ -178,169 +150,11 @@ func (h *ClusterHandler) Create(c echo.Context) error {
 		log.Printf("[DEBUG] CredentialsMode from request: nil")
 	}
 
-	// Validate request body
-	if err := c.Validate(req); err != nil {
-		log.Printf("[ERROR] Request validation failed: %v", err)
-		return ErrorBadRequest(c, err.Error())
-	}
-	debugLog("Request validation passed")
-
-	// Custom validation: base_domain is required for OpenShift IPI clusters
-	// (not required for managed services like ROSA, ARO)
-	if req.ClusterType == "openshift" && req.BaseDomain == "" {
-		return ErrorBadRequest(c, "base_domain is required for OpenShift clusters")
-	}
-
-	// ROSA clusters don't use base_domain (AWS-managed DNS)
-	if req.ClusterType == "rosa" && req.BaseDomain != "" {
-		return ErrorBadRequest(c, "base_domain is not supported for ROSA clusters (AWS-managed DNS)")
-	}
-
-	// ARO and AKS clusters don't use base_domain (Azure-managed DNS)
-	if (req.ClusterType == "aro" || req.ClusterType == "aks") && req.BaseDomain != "" {
-		return ErrorBadRequest(c, "base_domain is not supported for ARO/AKS clusters (Azure-managed DNS)")
-	}
-
-	// Validate platform and cluster type combinations
-	validCombinations := map[types.Platform][]types.ClusterType{
-		types.PlatformAWS:      {types.ClusterTypeOpenShift, types.ClusterTypeROSA, types.ClusterTypeEKS},
-		types.PlatformGCP:      {types.ClusterTypeOpenShift, types.ClusterTypeGKE},
-		types.PlatformIBMCloud: {types.ClusterTypeOpenShift, types.ClusterTypeIKS},
-		types.PlatformAzure:    {types.ClusterTypeOpenShift, types.ClusterTypeARO, types.ClusterTypeAKS},
-	}
-
-	platform := types.Platform(req.Platform)
-	clusterType := types.ClusterType(req.ClusterType)
-
-	if validTypes, ok := validCombinations[platform]; ok {
-		valid := false
-		for _, vt := range validTypes {
-			if vt == clusterType {
-				valid = true
-				break
-			}
-		}
-		if !valid {
-			return ErrorBadRequest(c, fmt.Sprintf("Invalid cluster_type %s for platform %s", req.ClusterType, req.Platform))
-		}
-	} else {
-		return ErrorBadRequest(c, fmt.Sprintf("Unsupported platform: %s", req.Platform))
-	}
-
-	// Check for duplicate cluster creation using idempotency key
-	// If idempotency key is provided, check if cluster with same key already exists
-	if req.IdempotencyKey != "" {
-		// NOTE: Idempotency check temporarily disabled - GetByName method needs implementation
-		// TODO: Implement proper idempotency using idempotency_key in database schema
-		debugLog("Idempotency key provided: %s", req.IdempotencyKey)
-	}
-
-	// Convert empty base_domain to empty string for non-OpenShift clusters
-	// (frontend sends empty string, but we want to store empty string in DB for EKS/IKS)
-	if req.ClusterType != "openshift" && req.BaseDomain == "" {
-		// Keep it as empty string - the database column allows empty strings
-	}
-
-	// Get default TTL if not provided
-	// Load profile from database (used for validation and default values)
-	profileForValidation, err := h.store.GetProfile(ctx, req.Profile)
-	if err != nil {
-		return ErrorBadRequest(c, "Invalid profile: "+err.Error())
-	}
-
-	ttl := 0
-	if req.TTLHours != nil {
-		ttl = *req.TTLHours
-	} else {
-		// Use profile default TTL
-		ttl = profileForValidation.Lifecycle.DefaultTTLHours
-	}
-
-	// Build policy validation request
-	policyReq := &types.CreateClusterRequest{
-		Name:              req.Name,
-		Platform:          req.Platform,
-		ClusterType:       req.ClusterType,
-		Version:           req.Version,
-		Profile:           req.Profile,
-		Region:            req.Region,
-		BaseDomain:        req.BaseDomain,
-		Owner:             req.Owner,
-		Team:              req.Team,
-		CostCenter:        req.CostCenter,
-		TTLHours:          ttl,
-		SSHPublicKey:      req.SSHPublicKey,
-		ExtraTags:         req.ExtraTags,
-		OffhoursOptIn:     req.OffhoursOptIn,
-		CredentialsMode:   req.CredentialsMode,
-		PreserveOnFailure: req.PreserveOnFailure,
-	}
-
-	// Validate against policy using database-loaded profile
-	debugLog("Starting policy validation for profile: %s", req.Profile)
-	validation := h.policy.ValidateWithProfile(policyReq, profileForValidation)
-	debugLog("Policy validation completed, valid=%v", validation.Valid)
-
-	if !validation.Valid {
-		log.Printf("[ERROR] Policy validation failed: %+v", validation)
-		return ErrorValidation(c, validation)
-	}
-
-	// Validate profile is allowed for the team (non-admin users only)
-	user, err := auth.GetUser(c)
-	if err == nil && user.Role != types.RoleAdmin {
-		team, err := h.store.Teams.Get(ctx, req.Team)
-		if err == nil {
-			// Check if team has profile restrictions
-			if team.AllowedProfiles != nil && len(team.AllowedProfiles) > 0 {
-				profileAllowed := false
-				for _, allowedProfile := range team.AllowedProfiles {
-					if allowedProfile == req.Profile {
-						profileAllowed = true
-						break
-					}
-				}
-				if !profileAllowed {
-					return ErrorBadRequest(c, fmt.Sprintf("Profile '%s' is not allowed for team '%s'", req.Profile, req.Team))
-				}
-			}
-		}
-	}
-
-	// Validate custom post-config if provided
-	if req.CustomPostConfig != nil {
-		if errs := validation2.ValidateCustomPostConfig(req.CustomPostConfig); len(errs) > 0 {
-			log.Printf("[ERROR] Custom post-config validation failed: %v", errs)
-			// Return first validation error
-			return ErrorBadRequest(c, errs[0].Error())
-		}
-	}
-
-	// Validate custom pull secret if provided
-	if req.CustomPullSecret != nil && *req.CustomPullSecret != "" {
-		var pullSecretData map[string]interface{}
-		if err := json.Unmarshal([]byte(*req.CustomPullSecret), &pullSecretData); err != nil {
-			log.Printf("[ERROR] Invalid custom pull secret JSON: %v", err)
-			return ErrorBadRequest(c, "custom_pull_secret must be valid JSON")
-		}
-
-		// Validate that it has the expected structure: {"auths": {...}}
-		auths, hasAuths := pullSecretData["auths"]
-		if !hasAuths {
-			return ErrorBadRequest(c, "custom_pull_secret must have an 'auths' field")
-		}
-
-		// Validate auths is an object
-		if _, ok := auths.(map[string]interface{}); !ok {
-			return ErrorBadRequest(c, "custom_pull_secret 'auths' field must be an object")
-		}
-	}
-
-	// Check for potential infra ID collisions with recently destroyed clusters
-	if err := h.checkInfraIDCollision(c.Request().Context(), req.Name, types.Platform(req.Platform)); err != nil {
-		log.Printf("[ERROR] Infra ID collision detected: %v", err)
-		return ErrorBadRequest(c, err.Error())
+	checked, err := h.validateCreateRequest(c, &req)
+	if checked == nil {
+		return err
 	}
+	profileForValidation := checked.Profile
 
 	// Get authenticated user ID
 	ownerID, err := auth.GetUserID(c)
@@ -350,269 +164,25 @@ func (h *ClusterHandler) Create(c echo.Context) error {
 	}
 	debugLog("Creating cluster for user ID: %s", ownerID)
 
-	// Parse destroy_at timestamp (empty means infinite TTL)
-	var destroyAt *time.Time
-	if validation.DestroyAt != "" {
-		parsedTime, err := time.Parse(time.RFC3339, validation.DestroyAt)
-		if err != nil {
-			return LogAndReturnGenericError(c, fmt.Errorf("invalid destroy_at timestamp: %w", err))
-		}
-		destroyAt = &parsedTime
-	}
-
-	// Prepare base_domain - use pointer for nullable field
-	// EKS/IKS clusters will have nil, OpenShift will have actual value
-	var baseDomain *string
-	if req.ClusterType == "openshift" && req.BaseDomain != "" {
-		baseDomain = &req.BaseDomain
-	}
-
 	// Create cluster record
-	cluster := &types.Cluster{
-		ID:                 uuid.New().String(),
-		Name:               req.Name,
-		Platform:           types.Platform(req.Platform),
-		ClusterType:        types.ClusterType(req.ClusterType),
-		Version:            req.Version,
-		Profile:            req.Profile,
-		Region:             req.Region,
-		BaseDomain:         baseDomain,
-		Status:             types.ClusterStatusPending,
-		Owner:              req.Owner,
-		OwnerID:            ownerID,
-		Team:               req.Team,
-		CostCenter:         req.CostCenter,
-		TTLHours:           ttl,
-		RequestTags:        validation.MergedTags,
-		EffectiveTags:      validation.MergedTags,
-		SSHPublicKey:       req.SSHPublicKey,
-		DestroyAt:          destroyAt,
-		OffhoursOptIn:      req.OffhoursOptIn,
-		SkipPostDeployment: req.SkipPostDeployment,
-		CustomPostConfig:   req.CustomPostConfig,
-		PreserveOnFailure:  req.PreserveOnFailure,
-		CredentialsMode:    req.CredentialsMode,
-		CustomPullSecret:   req.CustomPullSecret,
-		CreatedAt:          time.Now(),
-		UpdatedAt:          time.Now(),
-	}
-
-	// Stamp ocpctl provenance tags now that the cluster UUID exists. These flow
-	// through EffectiveTags into install-config userTags, eksctl/rosa tags, so
-	// every ocpctl-created resource can be traced back to this cluster.
-	// EffectiveTags is a distinct copy so RequestTags keeps the user's request.
-	effectiveTags := make(types.Tags, len(validation.MergedTags)+4)
-	for k, v := range validation.MergedTags {
-		effectiveTags[k] = v
-	}
-	for k, v := range types.ProvenanceTags(cluster.ID, cluster.Name, cluster.CreatedAt) {
-		effectiveTags[k] = v
-	}
-	cluster.EffectiveTags = effectiveTags
-
-	// Handle work hours override if provided
-	if req.WorkHoursEnabled != nil {
-		cluster.WorkHoursEnabled = req.WorkHoursEnabled
-
-		// If work hours are provided, parse and store them
-		if req.WorkHours != nil {
-			// Parse "09:00" format to time.Time
-			startTime, err := time.Parse("15:04", req.WorkHours.StartTime)
-			if err != nil {
-				return ErrorBadRequest(c, "invalid work hours start time format, use HH:MM")
-			}
-			endTime, err := time.Parse("15:04", req.WorkHours.EndTime)
-			if err != nil {
-				return ErrorBadRequest(c, "invalid work hours end time format, use HH:MM")
-			}
-
-			// Convert day names to bitmask
-			workDaysMask := types.WorkDaysFromStrings(req.WorkHours.WorkDays)
-			if workDaysMask == 0 {
-				return ErrorBadRequest(c, "at least one work day must be selected")
-			}
-
-			cluster.WorkHoursStart = &startTime
-			cluster.WorkHoursEnd = &endTime
-			cluster.WorkDays = &workDaysMask
-		}
-	}
-
-	// Populate selected_addon_ids from profile defaultAddons and user selections
-	// Use a map to deduplicate (frontend may send back defaults that were pre-checked)
-	addonMap := make(map[string]bool)
-
-	// Add profile's default addons (for API compatibility)
-	if len(profileForValidation.DefaultAddons) > 0 {
-		for _, addonRef := range profileForValidation.DefaultAddons {
-			// Format: "addonID" or "addonID:version"
-			addonIDRef := addonRef.AddonID
-			if addonRef.Version != "" {
-				addonIDRef = addonRef.AddonID + ":" + addonRef.Version
-			}
-			addonMap[addonIDRef] = true
-		}
-		log.Printf("Added %d default addons from profile %s", len(profileForValidation.DefaultAddons), req.Profile)
-	}
-
-	// Add user-selected addons from request (duplicates automatically skipped)
-	if len(req.PostConfigAddOns) > 0 {
-		userAddedCount := 0
-		for _, selection := range req.PostConfigAddOns {
-			addonIDRef := selection.ID + ":" + selection.Version
-			if !addonMap[addonIDRef] {
-				addonMap[addonIDRef] = true
-				userAddedCount++
-			}
-		}
-		if userAddedCount > 0 {
-			log.Printf("Added %d user-selected addons to cluster %s", userAddedCount, req.Name)
-		}
-		duplicateCount := len(req.PostConfigAddOns) - userAddedCount
-		if duplicateCount > 0 {
-			log.Printf("Skipped %d duplicate addon(s) already in profile defaults", duplicateCount)
-		}
-	}
-
-	// Convert map to slice
-	selectedAddonIDs := make([]string, 0, len(addonMap))
-	for addonID := range addonMap {
-		selectedAddonIDs = append(selectedAddonIDs, addonID)
+	cluster, err := h.creator.Build(&req, ownerID, checked)
+	if err != nil {
+		return writeCreateError(c, err)
 	}
 
-	cluster.SelectedAddonIDs = selectedAddonIDs
-
-	// Set initial post_deploy_status based on profile configuration, selected addons, or custom config
-	// This prevents hibernation from blocking clusters that don't have post-deployment config
-	// Reuse profile loaded earlier for validation
-	hasPostDeployment := (profileForValidation.PostDeployment != nil && (len(profileForValidation.PostDeployment.Operators) > 0 ||
-		len(profileForValidation.PostDeployment.Scripts) > 0 ||
-		len(profileForValidation.PostDeployment.Manifests) > 0 ||
-		len(profileForValidation.PostDeployment.HelmCharts) > 0)) ||
-		len(selectedAddonIDs) > 0 ||
-		req.CustomPostConfig != nil
-
 	// NOTE: All addons (both default and user-selected) are tracked in selected_addon_ids
 	// and processed via the selectedAddonsConfig path in the worker.
 	// DO NOT merge PostConfigAddOns into CustomPostConfig - it causes duplicate execution.
 	// CustomPostConfig should ONLY be used for truly custom user-defined operators/scripts/manifests,
 	// not for addons selected from the addon catalog.
 	debugLog("PostConfigAddOns received: %+v (count: %d)", req.PostConfigAddOns, len(req.PostConfigAddOns))
-	if len(req.PostConfigAddOns) > 0 {
-		// Validate add-on selections have required fields
-		for _, selection := range req.PostConfigAddOns {
-			if selection.ID == "" {
-				return ErrorBadRequest(c, "add-on ID is required")
-			}
-			if selection.Version == "" {
-				return ErrorBadRequest(c, fmt.Sprintf("version is required for add-on '%s'", selection.ID))
-			}
-		}
+	if _, err := h.creator.ResolveAddons(ctx, &req, cluster, profileForValidation); err != nil {
+		return writeCreateError(c, err)
 	}
 
-	// Validate no conflicting addons are selected (check ALL addons: profile defaults + user selections)
-	// Build merged list of all addons that will be installed
-	type addonInfo struct {
-		ID       string
-		Version  string
-		Metadata *types.AddonMetadata
-		Source   string // "profile" or "user"
-	}
-	allAddons := make(map[string]addonInfo)
-
-	// Add profile default addons
-	if len(profileForValidation.DefaultAddons) > 0 {
-		for _, addonRef := range profileForValidation.DefaultAddons {
-			addon, err := h.store.PostConfigAddons.GetByAddonIDAndVersion(ctx, addonRef.AddonID, addonRef.Version)
-			if err != nil {
-				log.Printf("Warning: failed to resolve profile default addon '%s' version '%s': %v", addonRef.AddonID, addonRef.Version, err)
-				continue
-			}
-			allAddons[addonRef.AddonID] = addonInfo{
-				ID:       addonRef.AddonID,
-				Version:  addonRef.Version,
-				Metadata: addon.Metadata,
-				Source:   "profile",
-			}
-		}
-	}
-
-	// Add user-selected addons (overrides profile defaults if same ID)
-	for _, selection := range req.PostConfigAddOns {
-		addon, err := h.store.PostConfigAddons.GetByAddonIDAndVersion(ctx, selection.ID, selection.Version)
-		if err != nil {
-			return ErrorBadRequest(c, fmt.Sprintf("failed to resolve add-on '%s' version '%s': %v", selection.ID, selection.Version, err))
-		}
-		allAddons[selection.ID] = addonInfo{
-			ID:       selection.ID,
-			Version:  selection.Version,
-			Metadata: addon.Metadata,
-			Source:   "user",
-		}
-	}
-
-	// Smart conflict resolution: Auto-exclude profile defaults that conflict with user selections
-	// This allows users to override conflicting defaults by selecting an alternative
-	// while still keeping non-conflicting defaults
-	for addonID, info := range allAddons {
-		if info.Source != "user" || info.Metadata == nil {
-			continue
-		}
-
-		// Check if this user-selected addon conflicts with any profile defaults
-		for _, conflictingID := range info.Metadata.ConflictsWith {
-			if conflictingInfo, exists := allAddons[conflictingID]; exists && conflictingInfo.Source == "profile" {
-				// User explicitly selected an addon that conflicts with a profile default
-				// Remove the profile default to honor user's choice
-				log.Printf("Auto-excluding profile default addon '%s' because user selected conflicting addon '%s'",
-					conflictingID, addonID)
-				delete(allAddons, conflictingID)
-			}
-		}
-	}
-
-	// Check for conflicts in the final merged addon list (only user-user conflicts remain)
-	for addonID, info := range allAddons {
-		if info.Metadata == nil || len(info.Metadata.ConflictsWith) == 0 {
-			continue
-		}
-
-		for _, conflictingID := range info.Metadata.ConflictsWith {
-			if _, exists := allAddons[conflictingID]; exists {
-				// Found a conflict between two user-selected addons
-				// (profile defaults that conflicted were already removed above)
-				return ErrorBadRequest(c, fmt.Sprintf(
-					"addon conflict: '%s' conflicts with '%s'. Please deselect one of these addons.",
-					addonID, conflictingID))
-			}
-		}
-	}
-
-	// Rebuild cluster.SelectedAddonIDs from the cleaned-up allAddons map
-	// This ensures only non-conflicting addons are saved to the database
-	finalSelectedAddonIDs := make([]string, 0, len(allAddons))
-	for addonID, info := range allAddons {
-		addonRef := addonID + ":" + info.Version
-		finalSelectedAddonIDs = append(finalSelectedAddonIDs, addonRef)
-	}
-	cluster.SelectedAddonIDs = finalSelectedAddonIDs
-	log.Printf("Final addon list after conflict resolution: %v", finalSelectedAddonIDs)
-
-	// Check if user provided custom post-config (operators/scripts/manifests/helm charts)
-	hasCustomPostConfig := req.CustomPostConfig != nil && (len(req.CustomPostConfig.Operators) > 0 ||
-		len(req.CustomPostConfig.Scripts) > 0 ||
-		len(req.CustomPostConfig.Manifests) > 0 ||
-		len(req.CustomPostConfig.HelmCharts) > 0)
-
-	if req.SkipPostDeployment || (!hasPostDeployment && !hasCustomPostConfig) {
-		// No post-deployment needed - set to 'skipped' so hibernation works immediately
-		skipped := "skipped"
-		cluster.PostDeployStatus = &skipped
-	} else {
-		// Post-deployment will run - set to 'pending' to block hibernation until complete
-		pending := "pending"
-		cluster.PostDeployStatus = &pending
-	}
+	// Set initial post_deploy_status based on profile configuration, selected addons, or custom config
+	// This prevents hibernation from blocking clusters that don't have post-deployment config
+	clustercreate.SetPostDeployStatus(cluster, profileForValidation)
 
 	baseDomainStr := ""
 	if cluster.BaseDomain != nil {
@@ -685,6 +255,47 @@ func (h *ClusterHandler) Create(c echo.Context) error {
 	return SuccessCreated(c, cluster)
 }
 
+// validateCreateRequest runs the checks shared by Create and Preview: request
+// shape, then the clustercreate checks. Team profile restrictions apply to
+// everyone but admins. When the request is rejected the error response has
+// already been written; the result is nil and the returned error is the one
+// from writing the response.
+func (h *ClusterHandler) validateCreateRequest(c echo.Context, req *types.CreateClusterAPIRequest) (*clustercreate.Validated, error) {
+	// Validate request body
+	if err := c.Validate(req); err != nil {
+		log.Printf("[ERROR] Request validation failed: %v", err)
+		return nil, ErrorBadRequest(c, err.Error())
+	}
+	debugLog("Request validation passed")
+
+	checkTeamProfiles := false
+	if user, err := auth.GetUser(c); err == nil && user.Role != types.RoleAdmin {
+		checkTeamProfiles = true
+	}
+
+	checked, err := h.creator.Validate(c.Request().Context(), req, checkTeamProfiles)
+	if err != nil {
+		return nil, writeCreateError(c, err)
+	}
+	return checked, nil
+}
+
+// writeCreateError writes the response for a create request clustercreate
+// rejected
+func writeCreateError(c echo.Context, err error) error {
+	var reqErr *clustercreate.RequestError
+	if !errors.As(err, &reqErr) {
+		return LogAndReturnGenericError(c, err)
+	}
+	switch {
+	case reqErr.Validation != nil:
+		return ErrorValidation(c, reqErr.Validation)
+	case reqErr.Forbidden:
+		return ErrorForbidden(c, reqErr.Message)
+	}
+	return ErrorBadRequest(c, reqErr.Message)
+}
+
 // List handles GET /api/v1/clusters
 //
 //	@Summary		List clusters
@@ -1039,6 +650,9 @@ func (h *ClusterHandler) buildExecutionOrderMetadata(config *types.CustomPostCon
 						"namespace":  op.CustomResource.Namespace,
 					}
 				}
+				if len(op.Readiness) > 0 {
+					taskInfo.Metadata["readiness"] = op.Readiness
+				}
 			}
 		case "script":
 			if script, ok := scriptMap[task.Name]; ok {
@@ -1055,6 +669,9 @@ func (h *ClusterHandler) buildExecutionOrderMetadata(config *types.CustomPostCon
 				} else if script.Content != "" {
 					taskInfo.Metadata["hasInlineContent"] = true
 				}
+				if len(script.Readiness) > 0 {
+					taskInfo.Metadata["readiness"] = script.Readiness
+				}
 			}
 		case "manifest":
 			if manifest, ok := manifestMap[task.Name]; ok {
@@ -1066,9 +683,17 @@ func (h *ClusterHandler) buildExecutionOrderMetadata(config *types.CustomPostCon
 				}
 				if manifest.URL != "" {
 					taskInfo.Metadata["url"] = manifest.URL
+				} else if manifest.Git != nil {
+					taskInfo.Metadata["git"] = manifest.Git
+					if manifest.Kustomize {
+						taskInfo.Metadata["kustomize"] = true
+					}
 				} else if manifest.Content != "" {
 					taskInfo.Metadata["hasInlineContent"] = true
 				}
+				if len(manifest.Readiness) > 0 {
+					taskInfo.Metadata["readiness"] = manifest.Readiness
+				}
 			}
 		case "helmChart":
 			if helm, ok := helmChartMap[task.Name]; ok {
@@ -1077,9 +702,15 @@ func (h *ClusterHandler) buildExecutionOrderMetadata(config *types.CustomPostCon
 				if helm.Version != "" {
 					taskInfo.Metadata["version"] = helm.Version
 				}
+				if helm.Digest != "" {
+					taskInfo.Metadata["digest"] = helm.Digest
+				}
 				if helm.Namespace != "" {
 					taskInfo.Metadata["namespace"] = helm.Namespace
 				}
+				if len(helm.Readiness) > 0 {
+					taskInfo.Metadata["readiness"] = helm.Readiness
+				}
 			}
 		}
 
@@ -1190,7 +821,7 @@ func (h *ClusterHandler) Delete(c echo.Context) error {
 
 	err = h.store.WithTx(ctx, func(tx pgx.Tx) error {
 		// Update cluster status to destroying
-		if err := h.store.Clusters.UpdateStatus(ctx, tx, cluster.ID, types.ClusterStatusDestroying); err != nil {
+		if err := h.store.Clusters.UpdateStatus(eventContext(c, "deletion requested"), tx, cluster.ID, types.ClusterStatusDestroying); err != nil {
 			return fmt.Errorf("update cluster status: %w", err)
 		}
 
@@ -1226,7 +857,7 @@ func (h *ClusterHandler) Delete(c echo.Context) error {
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string					true	"Cluster ID"
-//	@Param			request	body		ExtendClusterRequest	true	"TTL extension request"
+//	@Param			request	body		types.ExtendClusterRequest	true	"TTL extension request"
 //	@Success		200		{object}	types.Cluster
 //	@Failure		400		{object}	ErrorResponse
 //	@Failure		401		{object}	ErrorResponse
@@ -1242,7 +873,7 @@ func (h *ClusterHandler) Extend(c echo.Context) error {
 	id := c.Param("id")
 
 	// Parse request body
-	var req ExtendClusterRequest
+	var req types.ExtendClusterRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
@@ -1427,6 +1058,10 @@ func (h *ClusterHandler) Hibernate(c echo.Context) error {
 		return ErrorBadRequest(c, "Can only hibernate clusters in READY status")
 	}
 
+	if caps, ok := h.providers.Capabilities(cluster.ClusterType); !ok || !caps.Hibernate {
+		return ErrorBadRequest(c, fmt.Sprintf("Hibernation is not supported for %s clusters", cluster.ClusterType))
+	}
+
 	// Check for existing HIBERNATE job
 	existingJobs, err := h.store.Jobs.GetByClusterIDAndType(ctx, id, types.JobTypeHibernate)
 	if err != nil {
@@ -1459,7 +1094,7 @@ func (h *ClusterHandler) Hibernate(c echo.Context) error {
 
 	err = h.store.WithTx(ctx, func(tx pgx.Tx) error {
 		// Update cluster status to HIBERNATING
-		if err := h.store.Clusters.UpdateStatus(ctx, tx, id, types.ClusterStatusHibernating); err != nil {
+		if err := h.store.Clusters.UpdateStatus(eventContext(c, "hibernation requested"), tx, id, types.ClusterStatusHibernating); err != nil {
 			return fmt.Errorf("update cluster status: %w", err)
 		}
 
@@ -1653,7 +1288,7 @@ func (h *ClusterHandler) Resume(c echo.Context) error {
 
 	err = h.store.WithTx(ctx, func(tx pgx.Tx) error {
 		// Update cluster status to RESUMING
-		if err := h.store.Clusters.UpdateStatus(ctx, tx, id, types.ClusterStatusResuming); err != nil {
+		if err := h.store.Clusters.UpdateStatus(eventContext(c, "resume requested"), tx, id, types.ClusterStatusResuming); err != nil {
 			return fmt.Errorf("update cluster status: %w", err)
 		}
 
@@ -1697,64 +1332,13 @@ func (h *ClusterHandler) Resume(c echo.Context) error {
 	return SuccessOK(c, cluster)
 }
 
-// ClusterStatistics represents aggregated cluster statistics
-type ClusterStatistics struct {
-	TotalClusters      int                    `json:"total_clusters"`
-	ClustersByStatus   []ClusterStatusCount   `json:"clusters_by_status"`
-	ClustersByProfile  []ClusterProfileCount  `json:"clusters_by_profile"`
-	ClustersByPlatform []ClusterPlatformCount `json:"clusters_by_platform"`
-	ActiveClusters     int                    `json:"active_clusters"`
-	TotalHourlyCost    float64                `json:"total_hourly_cost"`
-	TotalDailyCost     float64                `json:"total_daily_cost"`
-	TotalMonthlyCost   float64                `json:"total_monthly_cost"`
-	CostByProfile      []ProfileCostBreakdown `json:"cost_by_profile"`
-	CostByUser         []UserCostBreakdown    `json:"cost_by_user"`
-}
-
-// ClusterStatusCount represents cluster count per status
-type ClusterStatusCount struct {
-	Status string `json:"status"`
-	Count  int    `json:"count"`
-}
-
-// ClusterProfileCount represents cluster count per profile
-type ClusterProfileCount struct {
-	Profile string `json:"profile"`
-	Count   int    `json:"count"`
-}
-
-// ClusterPlatformCount represents cluster count per platform
-type ClusterPlatformCount struct {
-	Platform string `json:"platform"`
-	Count    int    `json:"count"`
-}
-
-// ProfileCostBreakdown represents cost breakdown by profile
-type ProfileCostBreakdown struct {
-	Profile      string  `json:"profile"`
-	ClusterCount int     `json:"cluster_count"`
-	HourlyCost   float64 `json:"hourly_cost"`
-	DailyCost    float64 `json:"daily_cost"`
-	MonthlyCost  float64 `json:"monthly_cost"`
-}
-
-// UserCostBreakdown represents cost breakdown by user
-type UserCostBreakdown struct {
-	UserID       string  `json:"user_id"`
-	Username     string  `json:"username"`
-	ClusterCount int     `json:"cluster_count"`
-	HourlyCost   float64 `json:"hourly_cost"`
-	DailyCost    float64 `json:"daily_cost"`
-	MonthlyCost  float64 `json:"monthly_cost"`
-}
-
 // GetStatistics handles GET /api/v1/admin/clusters/statistics
 //
 //	@Summary		Get cluster statistics
 //	@Description	Returns aggregated statistics for all clusters (admin only)
 //	@Tags			admin
 //	@Produce		json
-//	@Success		200	{object}	ClusterStatistics
+//	@Success		200	{object}	types.ClusterStatistics
 //	@Failure		401	{object}	ErrorResponse
 //	@Failure		403	{object}	ErrorResponse
 //	@Failure		500	{object}	ErrorResponse
@@ -1825,19 +1409,19 @@ func (h *ClusterHandler) GetStatistics(c echo.Context) error {
 	}
 
 	// Initialize statistics response
-	stats := ClusterStatistics{
+	stats := types.ClusterStatistics{
 		TotalClusters:      totalClusters,
 		ActiveClusters:     activeClusters,
-		ClustersByStatus:   make([]ClusterStatusCount, 0),
-		ClustersByProfile:  make([]ClusterProfileCount, 0),
-		ClustersByPlatform: make([]ClusterPlatformCount, 0),
-		CostByProfile:      make([]ProfileCostBreakdown, 0),
-		CostByUser:         make([]UserCostBreakdown, 0),
+		ClustersByStatus:   make([]types.ClusterStatusCount, 0),
+		ClustersByProfile:  make([]types.ClusterProfileCount, 0),
+		ClustersByPlatform: make([]types.ClusterPlatformCount, 0),
+		CostByProfile:      make([]types.ProfileCostBreakdown, 0),
+		CostByUser:         make([]types.UserCostBreakdown, 0),
 	}
 
 	// Convert status stats to response format
 	for _, stat := range statusStats {
-		stats.ClustersByStatus = append(stats.ClustersByStatus, ClusterStatusCount{
+		stats.ClustersByStatus = append(stats.ClustersByStatus, types.ClusterStatusCount{
 			Status: stat.Status,
 			Count:  stat.Count,
 		})
@@ -1845,7 +1429,7 @@ func (h *ClusterHandler) GetStatistics(c echo.Context) error {
 
 	// Convert platform stats to response format
 	for _, stat := range platformStats {
-		stats.ClustersByPlatform = append(stats.ClustersByPlatform, ClusterPlatformCount{
+		stats.ClustersByPlatform = append(stats.ClustersByPlatform, types.ClusterPlatformCount{
 			Platform: stat.Platform,
 			Count:    stat.Count,
 		})
@@ -1854,7 +1438,7 @@ func (h *ClusterHandler) GetStatistics(c echo.Context) error {
 	// Calculate costs from aggregated profile stats
 	profileCounts := make(map[string]int)
 	profileCosts := make(map[string]float64)
-	userCosts := make(map[string]*UserCostBreakdown)
+	userCosts := make(map[string]*types.UserCostBreakdown)
 
 	for _, stat := range profileStats {
 		profileCounts[stat.Profile] += stat.Count
@@ -1888,7 +1472,7 @@ func (h *ClusterHandler) GetStatistics(c echo.Context) error {
 			if user, exists := usersByID[stat.OwnerID]; exists {
 				username = user.Username
 			}
-			userCosts[stat.OwnerID] = &UserCostBreakdown{
+			userCosts[stat.OwnerID] = &types.UserCostBreakdown{
 				UserID:       stat.OwnerID,
 				Username:     username,
 				ClusterCount: stat.Count,
@@ -1903,7 +1487,7 @@ func (h *ClusterHandler) GetStatistics(c echo.Context) error {
 
 	// Convert profile counts to response format
 	for profile, count := range profileCounts {
-		stats.ClustersByProfile = append(stats.ClustersByProfile, ClusterProfileCount{
+		stats.ClustersByProfile = append(stats.ClustersByProfile, types.ClusterProfileCount{
 			Profile: profile,
 			Count:   count,
 		})
@@ -1911,7 +1495,7 @@ func (h *ClusterHandler) GetStatistics(c echo.Context) error {
 
 	// Convert profile costs to response format
 	for profile, hourlyCost := range profileCosts {
-		stats.CostByProfile = append(stats.CostByProfile, ProfileCostBreakdown{
+		stats.CostByProfile = append(stats.CostByProfile, types.ProfileCostBreakdown{
 			Profile:      profile,
 			ClusterCount: profileCounts[profile],
 			HourlyCost:   hourlyCost,
@@ -2011,32 +1595,6 @@ func (h *ClusterHandler) GetLongRunningClusters(c echo.Context) error {
 	})
 }
 
-// EC2Instance represents an EC2 instance with relevant details
-type EC2Instance struct {
-	InstanceID       string    `json:"instance_id"`
-	InstanceType     string    `json:"instance_type"`
-	State            string    `json:"state"`
-	PrivateIPAddress *string   `json:"private_ip_address"`
-	PublicIPAddress  *string   `json:"public_ip_address"`
-	LaunchTime       time.Time `json:"launch_time"`
-	Name             string    `json:"name"`
-}
-
-// ClusterInstance represents a generic instance across cloud platforms
-type ClusterInstance struct {
-	InstanceID       string            `json:"instance_id"`
-	InstanceType     string            `json:"instance_type"`
-	State            string            `json:"state"`
-	PrivateIPAddress *string           `json:"private_ip_address,omitempty"`
-	PublicIPAddress  *string           `json:"public_ip_address,omitempty"`
-	LaunchTime       *time.Time        `json:"launch_time,omitempty"`
-	Name             string            `json:"name"`
-	Zone             string            `json:"zone,omitempty"`
-	Platform         string            `json:"platform"` // aws, gcp, ibmcloud
-	Labels           map[string]string `json:"labels,omitempty"`
-	MachineType      string            `json:"machine_type,omitempty"` // GCP-specific
-}
-
 // GetInstances handles GET /api/v1/clusters/:id/instances
 //
 //	@Summary		Get cluster EC2 instances
@@ -2044,7 +1602,7 @@ type ClusterInstance struct {
 //	@Tags			clusters
 //	@Produce		json
 //	@Param			id	path		string	true	"Cluster ID"
-//	@Success		200	{array}		EC2Instance
+//	@Success		200	{array}		types.EC2Instance
 //	@Failure		400	{object}	ErrorResponse
 //	@Failure		401	{object}	ErrorResponse
 //	@Failure		403	{object}	ErrorResponse
@@ -2077,7 +1635,7 @@ func (h *ClusterHandler) GetInstances(c echo.Context) error {
 	}
 
 	// Route to platform-specific handler
-	var instances []ClusterInstance
+	var instances []types.ClusterInstance
 	switch cluster.Platform {
 	case types.PlatformAWS:
 		log.Printf("[DEBUG] Routing to AWS instance handler")
@@ -2098,16 +1656,16 @@ func (h *ClusterHandler) GetInstances(c echo.Context) error {
 }
 
 // getAWSInstances fetches AWS instances and converts to generic ClusterInstance format
-func (h *ClusterHandler) getAWSInstances(ctx context.Context, cluster *types.Cluster) ([]ClusterInstance, error) {
+func (h *ClusterHandler) getAWSInstances(ctx context.Context, cluster *types.Cluster) ([]types.ClusterInstance, error) {
 	ec2Instances, err := h.getClusterEC2Instances(ctx, cluster)
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert EC2Instance to ClusterInstance
-	instances := make([]ClusterInstance, len(ec2Instances))
+	instances := make([]types.ClusterInstance, len(ec2Instances))
 	for i, ec2 := range ec2Instances {
-		instances[i] = ClusterInstance{
+		instances[i] = types.ClusterInstance{
 			InstanceID:       ec2.InstanceID,
 			InstanceType:     ec2.InstanceType,
 			State:            ec2.State,
@@ -2123,7 +1681,7 @@ func (h *ClusterHandler) getAWSInstances(ctx context.Context, cluster *types.Clu
 }
 
 // getGCPInstances fetches GCP instances and converts to generic ClusterInstance format
-func (h *ClusterHandler) getGCPInstances(ctx context.Context, cluster *types.Cluster) ([]ClusterInstance, error) {
+func (h *ClusterHandler) getGCPInstances(ctx context.Context, cluster *types.Cluster) ([]types.ClusterInstance, error) {
 	// Handle based on cluster type
 	if cluster.ClusterType == types.ClusterTypeGKE {
 		return h.getGKEInstances(ctx, cluster)
@@ -2134,7 +1692,7 @@ func (h *ClusterHandler) getGCPInstances(ctx context.Context, cluster *types.Clu
 }
 
 // getGKEInstances fetches GKE node pool instances
-func (h *ClusterHandler) getGKEInstances(ctx context.Context, cluster *types.Cluster) ([]ClusterInstance, error) {
+func (h *ClusterHandler) getGKEInstances(ctx context.Context, cluster *types.Cluster) ([]types.ClusterInstance, error) {
 	log.Printf("[DEBUG] getGKEInstances: Starting for cluster %s", cluster.Name)
 
 	// Get GCP project from environment or cluster metadata
@@ -2191,7 +1749,7 @@ func (h *ClusterHandler) getGKEInstances(ctx context.Context, cluster *types.Clu
 
 	// For GKE, show node pools as "instances" rather than individual VMs
 	// GKE manages VMs automatically, so node pool info is more useful
-	var instances []ClusterInstance
+	var instances []types.ClusterInstance
 	for _, pool := range nodePools {
 		// Use the first instance group URL to extract zone information
 		var zone string
@@ -2208,7 +1766,7 @@ func (h *ClusterHandler) getGKEInstances(ctx context.Context, cluster *types.Clu
 
 		nodeCount := fmt.Sprintf("%d nodes", pool.InitialNodeCount)
 		zoneInfo := zone
-		instances = append(instances, ClusterInstance{
+		instances = append(instances, types.ClusterInstance{
 			InstanceID:       fmt.Sprintf("%s-%s", cluster.Name, pool.Name),
 			Name:             pool.Name,
 			InstanceType:     pool.Config.MachineType,
@@ -2224,7 +1782,7 @@ func (h *ClusterHandler) getGKEInstances(ctx context.Context, cluster *types.Clu
 }
 
 // getGKENodePoolInstances fetches actual VM instances for a GKE node pool
-func (h *ClusterHandler) getGKENodePoolInstances(ctx context.Context, clusterName, poolName, project, region string) ([]ClusterInstance, error) {
+func (h *ClusterHandler) getGKENodePoolInstances(ctx context.Context, clusterName, poolName, project, region string) ([]types.ClusterInstance, error) {
 	// Create a new context with longer timeout for gcloud commands (independent of HTTP request timeout)
 	// This function makes multiple gcloud calls, so we need a generous timeout
 	cmdCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -2257,7 +1815,7 @@ func (h *ClusterHandler) getGKENodePoolInstances(ctx context.Context, clusterNam
 		return nil, fmt.Errorf("failed to parse instance groups: %w", err)
 	}
 
-	var instances []ClusterInstance
+	var instances []types.ClusterInstance
 	for _, group := range instanceGroups {
 		// Get instances in this group
 		cmd := exec.CommandContext(cmdCtx, "gcloud", "compute", "instance-groups", "list-instances",
@@ -2355,7 +1913,7 @@ func (h *ClusterHandler) getGKENodePoolInstances(ctx context.Context, clusterNam
 				}
 			}
 
-			instances = append(instances, ClusterInstance{
+			instances = append(instances, types.ClusterInstance{
 				InstanceID:       instanceName,
 				InstanceType:     machineType,
 				MachineType:      machineType,
@@ -2375,12 +1933,12 @@ func (h *ClusterHandler) getGKENodePoolInstances(ctx context.Context, clusterNam
 }
 
 // getGCPComputeInstances fetches GCP Compute instances for OpenShift on GCP
-func (h *ClusterHandler) getGCPComputeInstances(ctx context.Context, cluster *types.Cluster) ([]ClusterInstance, error) {
+func (h *ClusterHandler) getGCPComputeInstances(ctx context.Context, cluster *types.Cluster) ([]types.ClusterInstance, error) {
 	// Get infraID from metadata.json (similar to AWS)
 	infraID, err := h.getInfraIDFromMetadata(cluster)
 	if err != nil {
 		// If we can't get infraID, return empty list
-		return []ClusterInstance{}, nil
+		return []types.ClusterInstance{}, nil
 	}
 
 	// Get GCP project from environment
@@ -2441,7 +1999,7 @@ func (h *ClusterHandler) getGCPComputeInstances(ctx context.Context, cluster *ty
 	}
 
 	// Convert to ClusterInstance format
-	instances := make([]ClusterInstance, 0, len(gcpInstances))
+	instances := make([]types.ClusterInstance, 0, len(gcpInstances))
 	for _, gcp := range gcpInstances {
 		// Parse creation timestamp
 		var launchTime *time.Time
@@ -2467,7 +2025,7 @@ func (h *ClusterHandler) getGCPComputeInstances(ctx context.Context, cluster *ty
 			}
 		}
 
-		instances = append(instances, ClusterInstance{
+		instances = append(instances, types.ClusterInstance{
 			InstanceID:       gcp.Name,
 			InstanceType:     machineType,
 			MachineType:      machineType,
@@ -2486,13 +2044,13 @@ func (h *ClusterHandler) getGCPComputeInstances(ctx context.Context, cluster *ty
 }
 
 // getClusterEC2Instances fetches EC2 instances for a cluster from AWS
-func (h *ClusterHandler) getClusterEC2Instances(ctx context.Context, cluster *types.Cluster) ([]EC2Instance, error) {
+func (h *ClusterHandler) getClusterEC2Instances(ctx context.Context, cluster *types.Cluster) ([]types.EC2Instance, error) {
 	// Get infraID from metadata.json
 	infraID, err := h.getInfraIDFromMetadata(cluster)
 	if err != nil {
 		// If we can't get infraID, try using cluster name as fallback
 		// (for clusters that haven't completed provisioning)
-		return []EC2Instance{}, nil
+		return []types.EC2Instance{}, nil
 	}
 
 	// Load AWS config
@@ -2529,7 +2087,7 @@ func (h *ClusterHandler) getClusterEC2Instances(ctx context.Context, cluster *ty
 	}
 
 	// Collect instance information
-	var instances []EC2Instance
+	var instances []types.EC2Instance
 	for _, reservation := range result.Reservations {
 		for _, instance := range reservation.Instances {
 			if instance.InstanceId == nil {
@@ -2545,7 +2103,7 @@ func (h *ClusterHandler) getClusterEC2Instances(ctx context.Context, cluster *ty
 				}
 			}
 
-			ec2Instance := EC2Instance{
+			ec2Instance := types.EC2Instance{
 				InstanceID:       *instance.InstanceId,
 				InstanceType:     string(instance.InstanceType),
 				State:            string(instance.State.Name),
@@ -2698,6 +2256,13 @@ func (h *ClusterHandler) calculateEffectiveCost(cluster *types.Cluster, prof *pr
 			// GKE Standard tier has no control plane charges
 			// Only persistent disks remain when hibernated (~2-5% of running cost)
 			return baseCost * 0.03
+		case types.ClusterTypeHCP:
+			// HCP: NodePools scaled to 0, but the hosted control plane pods
+			// keep running on the management cluster
+			return baseCost * 0.20
+		case types.ClusterTypeKind:
+			// kind: node containers stopped on the worker host, nothing billed
+			return 0
 		default:
 			// Unknown cluster type, use conservative estimate
 			return baseCost * 0.10
@@ -2708,15 +2273,6 @@ func (h *ClusterHandler) calculateEffectiveCost(cluster *types.Cluster, prof *pr
 	return baseCost
 }
 
-// StorageClass represents a Kubernetes storage class
-type StorageClass struct {
-	Name              string `json:"name"`
-	Provisioner       string `json:"provisioner"`
-	ReclaimPolicy     string `json:"reclaim_policy,omitempty"`
-	VolumeBindingMode string `json:"volume_binding_mode,omitempty"`
-	IsDefault         bool   `json:"is_default"`
-}
-
 // GetStorageClasses handles GET /api/v1/clusters/:id/storage-classes
 //
 //	@Summary		Get cluster storage classes
@@ -2724,7 +2280,7 @@ type StorageClass struct {
 //	@Tags			clusters
 //	@Produce		json
 //	@Param			id	path		string	true	"Cluster ID"
-//	@Success		200	{array}		StorageClass
+//	@Success		200	{array}		types.StorageClass
 //	@Failure		400	{object}	ErrorResponse
 //	@Failure		401	{object}	ErrorResponse
 //	@Failure		403	{object}	ErrorResponse
@@ -2767,11 +2323,11 @@ func (h *ClusterHandler) GetStorageClasses(c echo.Context) error {
 }
 
 // getClusterStorageClasses fetches storage classes from a Kubernetes cluster
-func (h *ClusterHandler) getClusterStorageClasses(ctx context.Context, cluster *types.Cluster) ([]StorageClass, error) {
+func (h *ClusterHandler) getClusterStorageClasses(ctx context.Context, cluster *types.Cluster) ([]types.StorageClass, error) {
 	// GKE clusters use gcloud as auth provider in kubeconfig, which doesn't work from API server
 	// Return well-known GKE storage classes instead of calling kubectl
 	if cluster.ClusterType == types.ClusterTypeGKE {
-		return []StorageClass{
+		return []types.StorageClass{
 			{
 				Name:              "standard",
 				Provisioner:       "kubernetes.io/gce-pd",
@@ -2799,7 +2355,7 @@ func (h *ClusterHandler) getClusterStorageClasses(ctx context.Context, cluster *
 	// Azure clusters - kubeconfigs not available on API server (stored in S3)
 	// Return well-known Azure storage classes for OpenShift
 	if cluster.Platform == types.PlatformAzure {
-		return []StorageClass{
+		return []types.StorageClass{
 			{
 				Name:              "managed-csi",
 				Provisioner:       "disk.csi.azure.com",
@@ -2826,7 +2382,7 @@ func (h *ClusterHandler) getClusterStorageClasses(ctx context.Context, cluster *
 
 	// Check if kubeconfig exists
 	if _, err := os.Stat(kubeconfigPath); os.IsNotExist(err) {
-		return []StorageClass{}, nil // Return empty list if kubeconfig doesn't exist yet
+		return []types.StorageClass{}, nil // Return empty list if kubeconfig doesn't exist yet
 	}
 
 	// Get storage classes using kubectl with JSON output
@@ -2856,7 +2412,7 @@ func (h *ClusterHandler) getClusterStorageClasses(ctx context.Context, cluster *
 	}
 
 	// Convert to StorageClass objects
-	var storageClasses []StorageClass
+	var storageClasses []types.StorageClass
 	for _, item := range result.Items {
 		isDefault := false
 		// Check for default storage class annotation
@@ -2864,7 +2420,7 @@ func (h *ClusterHandler) getClusterStorageClasses(ctx context.Context, cluster *
 			isDefault = true
 		}
 
-		storageClasses = append(storageClasses, StorageClass{
+		storageClasses = append(storageClasses, types.StorageClass{
 			Name:              item.Metadata.Name,
 			Provisioner:       item.Provisioner,
 			ReclaimPolicy:     item.ReclaimPolicy,
@@ -2875,28 +2431,3 @@ func (h *ClusterHandler) getClusterStorageClasses(ctx context.Context, cluster *
 
 	return storageClasses, nil
 }
-
-// checkInfraIDCollision checks if the cluster name could collide with recently destroyed clusters
-// OpenShift generates infra IDs by truncating cluster names to ~27 chars + 5-char random suffix
-// Similar names can generate the same infra ID if created close together
-func (h *ClusterHandler) checkInfraIDCollision(ctx context.Context, clusterName string, platform types.Platform) error {
-	// Only check for OpenShift clusters on AWS (where we've seen this issue)
-	if platform != types.PlatformAWS {
-		return nil
-	}
-
-	// Query for recently destroyed clusters with similar name prefixes
-	recentClusters, err := h.store.Clusters.CheckInfraIDCollision(ctx, clusterName, platform)
-	if err != nil {
-		// Don't fail cluster creation if collision check fails - just log
-		log.Printf("[WARN] Failed to check infra ID collision: %v", err)
-		return nil
-	}
-
-	// If we found similar recently-destroyed clusters, return an error
-	if len(recentClusters) > 0 {
-		return fmt.Errorf("potential infra ID collision: cluster name '%s' is similar to recently destroyed cluster(s): %v. OpenShift may reuse the same infrastructure ID, causing creation to fail. Please choose a more distinct cluster name", clusterName, recentClusters)
-	}
-
-	return nil
-}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -118,6 +119,7 @@ func (h *ConfigurationHandler) ListClusterConfigurations(c echo.Context) error {
 //	@Success		200			{object}	map[string]interface{}
 //	@Failure		400			{object}	map[string]string	"Configuration not failed or doesn't belong to cluster"
 //	@Failure		404			{object}	map[string]string	"Cluster or configuration not found"
+//	@Failure		409			{object}	map[string]string	"Another add-on or post-configuration job in progress"
 //	@Failure		500			{object}	map[string]string
 //	@Security		BearerAuth
 //	@Router			/clusters/{id}/configurations/{config_id}/retry [patch]
@@ -167,6 +169,9 @@ func (h *ConfigurationHandler) RetryConfiguration(c echo.Context) error {
 	}
 
 	if err := h.store.Jobs.Create(ctx, nil, job); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return ErrorConflict(c, "Another add-on or post-configuration job is already in progress for this cluster")
+		}
 		return LogAndReturnGenericError(c, fmt.Errorf("create retry job: %w", err))
 	}
 
@@ -188,6 +193,7 @@ func (h *ConfigurationHandler) RetryConfiguration(c echo.Context) error {
 //	@Success		200	{object}	map[string]interface{}
 //	@Failure		400	{object}	map[string]string	"Cluster not ready, already configured, or job already running"
 //	@Failure		404	{object}	map[string]string	"Cluster not found"
+//	@Failure		409	{object}	map[string]string	"Another add-on or post-configuration job in progress"
 //	@Failure		500	{object}	map[string]string
 //	@Security		BearerAuth
 //	@Router			/clusters/{id}/configure [post]
@@ -238,6 +244,9 @@ func (h *ConfigurationHandler) TriggerPostConfiguration(c echo.Context) error {
 	}
 
 	if err := h.store.Jobs.Create(ctx, nil, job); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return ErrorConflict(c, "Another add-on or post-configuration job is already in progress for this cluster")
+		}
 		return LogAndReturnGenericError(c, fmt.Errorf("create post-configure job: %w", err))
 	}
 
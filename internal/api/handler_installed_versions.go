@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,34 +23,18 @@ func NewInstalledVersionsHandler() *InstalledVersionsHandler {
 	return &InstalledVersionsHandler{}
 }
 
-// InstalledVersionsResponse represents the response for installed versions
-type InstalledVersionsResponse struct {
-	OpenShiftVersions map[string]InstalledVersion `json:"openshift_versions"`
-	TotalInstalled    int                         `json:"total_installed"`
-	BinariesPath      string                      `json:"binaries_path"`
-}
-
-// InstalledVersion represents details about an installed version
-type InstalledVersion struct {
-	MajorMinor        string   `json:"major_minor"`                  // e.g., "4.20"
-	ExactVersion      string   `json:"exact_version"`                // e.g., "4.20.17"
-	BinaryPath        string   `json:"binary_path"`                  // e.g., "/usr/local/bin/openshift-install-4.20"
-	CcoctlPath        string   `json:"ccoctl_path,omitempty"`        // e.g., "/usr/local/bin/ccoctl-4.20"
-	ProfileReferences []string `json:"profile_references,omitempty"` // e.g., ["4.20", "4.20.3", "4.20.4"]
-}
-
 // @Summary		Get installed OpenShift versions
 // @Description	Returns all OpenShift installer versions currently installed on the server by checking /usr/local/bin/openshift-install-* binaries
 // @Tags			admin
 // @Produce		json
-// @Success		200	{object}	InstalledVersionsResponse
+// @Success		200	{object}	types.InstalledVersionsResponse
 // @Failure		401	{object}	ErrorResponse	"Unauthorized"
 // @Failure		403	{object}	ErrorResponse	"Forbidden - Admin access required"
 // @Failure		500	{object}	ErrorResponse	"Internal server error"
 // @Security		BearerAuth
 // @Router			/admin/installed-versions [get]
 func (h *InstalledVersionsHandler) HandleGetInstalledVersions(c echo.Context) error {
-	versions := make(map[string]InstalledVersion)
+	versions := make(map[string]types.InstalledVersion)
 	binariesPath := "/usr/local/bin"
 	profilesPath := "/opt/ocpctl/profiles"
 
@@ -119,7 +104,7 @@ func (h *InstalledVersionsHandler) HandleGetInstalledVersions(c echo.Context) er
 			}
 		}
 
-		versions[majorMinor] = InstalledVersion{
+		versions[majorMinor] = types.InstalledVersion{
 			MajorMinor:   majorMinor,
 			ExactVersion: exactVersion,
 			BinaryPath:   binaryPath,
@@ -133,7 +118,7 @@ func (h *InstalledVersionsHandler) HandleGetInstalledVersions(c echo.Context) er
 		}
 	}
 
-	response := InstalledVersionsResponse{
+	response := types.InstalledVersionsResponse{
 		OpenShiftVersions: versions,
 		TotalInstalled:    len(versions),
 		BinariesPath:      binariesPath,
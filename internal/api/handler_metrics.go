@@ -7,9 +7,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
-	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/labstack/echo/v4"
 	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 // MetricsHandler handles metrics API requests
@@ -22,64 +23,19 @@ func NewMetricsHandler(store *store.Store) *MetricsHandler {
 	return &MetricsHandler{store: store}
 }
 
-// MetricsSnapshot represents current system metrics in JSON format
-type MetricsSnapshot struct {
-	API       APIMetricsSnapshot       `json:"api"`
-	Workers   WorkerMetricsSnapshot    `json:"workers"`
-	Jobs      JobMetricsSnapshot       `json:"jobs"`
-	Clusters  ClusterMetricsSnapshot   `json:"clusters"`
-	Autoscale AutoscaleMetricsSnapshot `json:"autoscale"`
-	Database  DatabaseMetricsSnapshot  `json:"database"`
-}
-
-type APIMetricsSnapshot struct {
-	RequestsPerSecond float64        `json:"requests_per_second"`
-	ActiveConnections int64          `json:"active_connections"`
-	ErrorRate         float64        `json:"error_rate"`
-	RequestsByStatus  map[string]int `json:"requests_by_status"`
-}
-
-type WorkerMetricsSnapshot struct {
-	Total  int `json:"total"`
-	Active int `json:"active"`
-	Idle   int `json:"idle"`
-}
-
-type JobMetricsSnapshot struct {
-	QueuedByType    map[string]int `json:"queued_by_type"`
-	ProcessingTotal int            `json:"processing_total"`
-	TotalQueued     int            `json:"total_queued"`
-}
-
-type ClusterMetricsSnapshot struct {
-	Total     int            `json:"total"`
-	ByStatus  map[string]int `json:"by_status"`
-	ByProfile map[string]int `json:"by_profile"`
-}
-
-type AutoscaleMetricsSnapshot struct {
-	CurrentWorkers int `json:"current_workers"`
-	DesiredWorkers int `json:"desired_workers"`
-}
-
-type DatabaseMetricsSnapshot struct {
-	OpenConnections int `json:"open_connections"`
-	MaxConnections  int `json:"max_connections"`
-}
-
 // GetCurrentMetrics returns current system metrics
 // @Summary Get current system metrics
 // @Description Returns current system metrics including API stats, job queue, clusters, and workers
 // @Tags metrics
 // @Produce json
-// @Success 200 {object} MetricsSnapshot
+// @Success 200 {object} types.MetricsSnapshot
 // @Failure 500 {object} ErrorResponse
 // @Router /metrics/current [get]
 // @Security BearerAuth
 func (h *MetricsHandler) GetCurrentMetrics(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	snapshot := MetricsSnapshot{
+	snapshot := types.MetricsSnapshot{
 		API:       h.getAPIMetrics(),
 		Workers:   h.getWorkerMetrics(ctx),
 		Jobs:      h.getJobMetrics(ctx),
@@ -91,8 +47,8 @@ func (h *MetricsHandler) GetCurrentMetrics(c echo.Context) error {
 	return c.JSON(http.StatusOK, snapshot)
 }
 
-func (h *MetricsHandler) getAPIMetrics() APIMetricsSnapshot {
-	return APIMetricsSnapshot{
+func (h *MetricsHandler) getAPIMetrics() types.APIMetricsSnapshot {
+	return types.APIMetricsSnapshot{
 		RequestsPerSecond: 0, // Calculated on frontend from historical data
 		ActiveConnections: 0, // Prometheus gauges don't expose current value easily
 		ErrorRate:         0,
@@ -100,7 +56,7 @@ func (h *MetricsHandler) getAPIMetrics() APIMetricsSnapshot {
 	}
 }
 
-func (h *MetricsHandler) getWorkerMetrics(ctx context.Context) WorkerMetricsSnapshot {
+func (h *MetricsHandler) getWorkerMetrics(ctx context.Context) types.WorkerMetricsSnapshot {
 	// Count total workers: 1 static + autoscale workers
 	totalWorkers := 1 // Static worker
 
@@ -110,7 +66,7 @@ func (h *MetricsHandler) getWorkerMetrics(ctx context.Context) WorkerMetricsSnap
 		ec2Client := ec2.NewFromConfig(cfg)
 
 		input := &ec2.DescribeInstancesInput{
-			Filters: []types.Filter{
+			Filters: []ec2types.Filter{
 				{
 					Name:   aws.String("tag:Name"),
 					Values: []string{"ocpctl-worker"},
@@ -144,14 +100,14 @@ func (h *MetricsHandler) getWorkerMetrics(ctx context.Context) WorkerMetricsSnap
 
 	idle := totalWorkers - active
 
-	return WorkerMetricsSnapshot{
+	return types.WorkerMetricsSnapshot{
 		Total:  totalWorkers,
 		Active: active,
 		Idle:   idle,
 	}
 }
 
-func (h *MetricsHandler) getJobMetrics(ctx context.Context) JobMetricsSnapshot {
+func (h *MetricsHandler) getJobMetrics(ctx context.Context) types.JobMetricsSnapshot {
 	queuedByType := make(map[string]int)
 	totalQueued := 0
 	processingTotal := 0
@@ -181,14 +137,14 @@ func (h *MetricsHandler) getJobMetrics(ctx context.Context) JobMetricsSnapshot {
 		SELECT COUNT(*) FROM jobs WHERE status = 'RUNNING'
 	`).Scan(&processingTotal)
 
-	return JobMetricsSnapshot{
+	return types.JobMetricsSnapshot{
 		QueuedByType:    queuedByType,
 		ProcessingTotal: processingTotal,
 		TotalQueued:     totalQueued,
 	}
 }
 
-func (h *MetricsHandler) getClusterMetrics(ctx context.Context) ClusterMetricsSnapshot {
+func (h *MetricsHandler) getClusterMetrics(ctx context.Context) types.ClusterMetricsSnapshot {
 	byStatus := make(map[string]int)
 	byProfile := make(map[string]int)
 	total := 0
@@ -231,14 +187,14 @@ func (h *MetricsHandler) getClusterMetrics(ctx context.Context) ClusterMetricsSn
 		}
 	}
 
-	return ClusterMetricsSnapshot{
+	return types.ClusterMetricsSnapshot{
 		Total:     total,
 		ByStatus:  byStatus,
 		ByProfile: byProfile,
 	}
 }
 
-func (h *MetricsHandler) getAutoscaleMetrics(ctx context.Context) AutoscaleMetricsSnapshot {
+func (h *MetricsHandler) getAutoscaleMetrics(ctx context.Context) types.AutoscaleMetricsSnapshot {
 	// Count running autoscale workers from AWS EC2
 	currentWorkers := 1 // Static worker always counts as 1
 
@@ -248,7 +204,7 @@ func (h *MetricsHandler) getAutoscaleMetrics(ctx context.Context) AutoscaleMetri
 		ec2Client := ec2.NewFromConfig(cfg)
 
 		input := &ec2.DescribeInstancesInput{
-			Filters: []types.Filter{
+			Filters: []ec2types.Filter{
 				{
 					Name:   aws.String("tag:Name"),
 					Values: []string{"ocpctl-worker"},
@@ -286,16 +242,16 @@ func (h *MetricsHandler) getAutoscaleMetrics(ctx context.Context) AutoscaleMetri
 	// Total desired = 1 static worker + autoscale workers
 	desired := 1 + autoscaleNeeded
 
-	return AutoscaleMetricsSnapshot{
+	return types.AutoscaleMetricsSnapshot{
 		CurrentWorkers: currentWorkers,
 		DesiredWorkers: desired,
 	}
 }
 
-func (h *MetricsHandler) getDatabaseMetrics() DatabaseMetricsSnapshot {
+func (h *MetricsHandler) getDatabaseMetrics() types.DatabaseMetricsSnapshot {
 	stats := h.store.Stats()
 
-	return DatabaseMetricsSnapshot{
+	return types.DatabaseMetricsSnapshot{
 		OpenConnections: int(stats.TotalConns()),
 		MaxConnections:  int(stats.MaxConns()),
 	}
@@ -40,24 +40,6 @@ func NewOrphanedResourceHandler(s *store.Store, p *policy.Engine) *OrphanedResou
 	}
 }
 
-// MarkResolvedRequest represents the request to mark a resource as resolved
-type MarkResolvedRequest struct {
-	Notes string `json:"notes"`
-}
-
-// MarkIgnoredRequest represents the request to mark a resource as ignored
-type MarkIgnoredRequest struct {
-	Notes string `json:"notes"`
-}
-
-// OrphanedResourceListResponse represents the paginated list response
-type OrphanedResourceListResponse struct {
-	Resources []*types.OrphanedResource `json:"resources"`
-	Total     int                       `json:"total"`
-	Limit     int                       `json:"limit"`
-	Offset    int                       `json:"offset"`
-}
-
 // List handles GET /api/v1/admin/orphaned-resources
 //
 //	@Summary		List orphaned resources
@@ -66,11 +48,11 @@ type OrphanedResourceListResponse struct {
 //	@Accept			json
 //	@Produce		json
 //	@Param			status	query		string	false	"Filter by status (active, resolved, ignored)"
-//	@Param			type	query		string	false	"Filter by resource type (VPC, LoadBalancer, HostedZone, DNSRecord, EC2Instance, S3Bucket)"
+//	@Param			type	query		string	false	"Filter by resource type (VPC, LoadBalancer, HostedZone, DNSRecord, types.EC2Instance, S3Bucket)"
 //	@Param			region	query		string	false	"Filter by AWS region"
 //	@Param			limit	query		int		false	"Maximum number of results (default 50, max 100)"
 //	@Param			offset	query		int		false	"Number of results to skip (default 0)"
-//	@Success		200		{object}	OrphanedResourceListResponse
+//	@Success		200		{object}	types.OrphanedResourceListResponse
 //	@Failure		500		{object}	map[string]string
 //	@Security		BearerAuth
 //	@Router			/admin/orphaned-resources [get]
@@ -121,7 +103,7 @@ func (h *OrphanedResourceHandler) List(c echo.Context) error {
 	}
 
 	// Return paginated response
-	return c.JSON(200, OrphanedResourceListResponse{
+	return c.JSON(200, types.OrphanedResourceListResponse{
 		Resources: resources,
 		Total:     total,
 		Limit:     filters.Limit,
@@ -157,7 +139,7 @@ func (h *OrphanedResourceHandler) GetStats(c echo.Context) error {
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string					true	"Resource ID"
-//	@Param			body	body		MarkResolvedRequest		true	"Resolution notes"
+//	@Param			body	body		types.MarkResolvedRequest		true	"Resolution notes"
 //	@Success		200		{object}	types.OrphanedResource
 //	@Failure		400		{object}	map[string]string
 //	@Failure		500		{object}	map[string]string
@@ -166,7 +148,7 @@ func (h *OrphanedResourceHandler) GetStats(c echo.Context) error {
 func (h *OrphanedResourceHandler) MarkResolved(c echo.Context) error {
 	id := c.Param("id")
 
-	var req MarkResolvedRequest
+	var req types.MarkResolvedRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
@@ -202,7 +184,7 @@ func (h *OrphanedResourceHandler) MarkResolved(c echo.Context) error {
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string					true	"Resource ID"
-//	@Param			body	body		MarkIgnoredRequest		true	"Ignore reason"
+//	@Param			body	body		types.MarkIgnoredRequest		true	"Ignore reason"
 //	@Success		200		{object}	types.OrphanedResource
 //	@Failure		400		{object}	map[string]string
 //	@Failure		500		{object}	map[string]string
@@ -211,7 +193,7 @@ func (h *OrphanedResourceHandler) MarkResolved(c echo.Context) error {
 func (h *OrphanedResourceHandler) MarkIgnored(c echo.Context) error {
 	id := c.Param("id")
 
-	var req MarkIgnoredRequest
+	var req types.MarkIgnoredRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
@@ -67,7 +67,7 @@ func (h *PoolLeaseHandler) LeaseCluster(c echo.Context) error {
 	}
 
 	// Lease cluster
-	cluster, err := h.store.Pools.LeaseCluster(ctx, poolName, &req)
+	cluster, err := h.store.Pools.LeaseCluster(eventContext(c, "lease requested"), poolName, &req)
 	if err != nil {
 		if err.Error() == "pool not found: sql: no rows in result set" {
 			return ErrorNotFound(c, "pool '"+poolName+"' not found")
@@ -210,7 +210,7 @@ func (h *PoolLeaseHandler) ReleaseCluster(c echo.Context) error {
 	}
 
 	// Release cluster (transitions to CLEANING state)
-	if err := h.store.Pools.ReleaseCluster(ctx, clusterID); err != nil {
+	if err := h.store.Pools.ReleaseCluster(eventContext(c, "release requested"), clusterID); err != nil {
 		if err.Error() == "cluster "+clusterID+" is not leased or does not exist" {
 			return ErrorBadRequest(c, "cluster is not leased or does not exist")
 		}
@@ -80,6 +80,11 @@ func (h *PoolHandler) CreatePool(c echo.Context) error {
 		autoRefreshEnabled = *req.AutoRefreshEnabled
 	}
 
+	reconcileDriftEnabled := false
+	if req.ReconcileDriftEnabled != nil {
+		reconcileDriftEnabled = *req.ReconcileDriftEnabled
+	}
+
 	scheduledMode := false
 	if req.ScheduledMode != nil {
 		scheduledMode = *req.ScheduledMode
@@ -145,6 +150,7 @@ func (h *PoolHandler) CreatePool(c echo.Context) error {
 		AutoReleaseEnabled:        autoReleaseEnabled,
 		MaxClusterAgeDays:         maxClusterAgeDays,
 		AutoRefreshEnabled:        autoRefreshEnabled,
+		ReconcileDriftEnabled:     reconcileDriftEnabled,
 		ScheduledMode:             scheduledMode,
 		ScheduleTimezone:          req.ScheduleTimezone,
 		ScheduleStartHour:         scheduleStartHour,
@@ -216,6 +222,7 @@ func (h *PoolHandler) ListPools(c echo.Context) error {
 			"auto_release_enabled":         pool.AutoReleaseEnabled,
 			"max_cluster_age_days":         pool.MaxClusterAgeDays,
 			"auto_refresh_enabled":         pool.AutoRefreshEnabled,
+			"reconcile_drift_enabled":      pool.ReconcileDriftEnabled,
 			"scheduled_mode":               pool.ScheduledMode,
 			"schedule_timezone":            pool.ScheduleTimezone,
 			"schedule_start_hour":          pool.ScheduleStartHour,
@@ -368,6 +375,10 @@ func (h *PoolHandler) UpdatePool(c echo.Context) error {
 		updates["auto_refresh_enabled"] = *req.AutoRefreshEnabled
 	}
 
+	if req.ReconcileDriftEnabled != nil {
+		updates["reconcile_drift_enabled"] = *req.ReconcileDriftEnabled
+	}
+
 	if req.ScheduledMode != nil {
 		updates["scheduled_mode"] = *req.ScheduledMode
 	}
@@ -15,25 +15,6 @@ func NewPostConfigHandler() *PostConfigHandler {
 	return &PostConfigHandler{}
 }
 
-// ValidateRequest represents a validation request
-type ValidateRequest struct {
-	Config *types.CustomPostConfig `json:"config" validate:"required"`
-}
-
-// ValidateResponse represents a validation response
-type ValidateResponse struct {
-	Valid  bool     `json:"valid"`
-	Errors []string `json:"errors,omitempty"`
-	DAG    *DAGInfo `json:"dag,omitempty"`
-}
-
-// DAGInfo provides information about the execution DAG
-type DAGInfo struct {
-	ExecutionOrder []string            `json:"executionOrder"`
-	TaskCount      int                 `json:"taskCount"`
-	Dependencies   map[string][]string `json:"dependencies"`
-}
-
 // Validate handles POST /api/v1/post-config/validate
 //
 //	@Summary		Validate custom post-config
@@ -41,14 +22,14 @@ type DAGInfo struct {
 //	@Tags			post-config
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		ValidateRequest	true	"Validation request"
-//	@Success		200		{object}	ValidateResponse
+//	@Param			request	body		types.ValidatePostConfigRequest	true	"Validation request"
+//	@Success		200		{object}	types.ValidatePostConfigResponse
 //	@Failure		400		{object}	ErrorResponse
 //	@Failure		401		{object}	ErrorResponse
 //	@Security		BearerAuth
 //	@Router			/post-config/validate [post]
 func (h *PostConfigHandler) Validate(c echo.Context) error {
-	var req ValidateRequest
+	var req types.ValidatePostConfigRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
@@ -64,7 +45,7 @@ func (h *PostConfigHandler) Validate(c echo.Context) error {
 		for i, err := range validationErrors {
 			errorMessages[i] = err.Error()
 		}
-		return SuccessOK(c, ValidateResponse{
+		return SuccessOK(c, types.ValidatePostConfigResponse{
 			Valid:  false,
 			Errors: errorMessages,
 		})
@@ -73,20 +54,20 @@ func (h *PostConfigHandler) Validate(c echo.Context) error {
 	// Build execution DAG to validate dependencies
 	dag, err := postconfig.BuildExecutionDAG(req.Config)
 	if err != nil {
-		return SuccessOK(c, ValidateResponse{
+		return SuccessOK(c, types.ValidatePostConfigResponse{
 			Valid:  false,
 			Errors: []string{err.Error()},
 		})
 	}
 
 	// Validation passed - return DAG info
-	dagInfo := &DAGInfo{
+	dagInfo := &types.DAGInfo{
 		ExecutionOrder: dag.ExecutionOrder,
 		TaskCount:      len(dag.Nodes),
 		Dependencies:   dag.AdjacencyList,
 	}
 
-	return SuccessOK(c, ValidateResponse{
+	return SuccessOK(c, types.ValidatePostConfigResponse{
 		Valid: true,
 		DAG:   dagInfo,
 	})
@@ -0,0 +1,479 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// @Summary		Create a profile
+// @Description	Creates a profile from a YAML (or JSON) definition. The definition may extend profiles and include fragments from the profiles directory. It is validated and saved as revision 1, published unless draft is set.
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			request	body		types.SaveProfileRequest	true	"Profile definition"
+// @Success		201		{object}	types.ProfileRevision
+// @Failure		400		{object}	ErrorResponse	"Invalid definition"
+// @Failure		401		{object}	ErrorResponse	"Unauthorized"
+// @Failure		403		{object}	ErrorResponse	"Forbidden - Admin access required"
+// @Failure		409		{object}	ErrorResponse	"Profile already exists"
+// @Failure		500		{object}	ErrorResponse	"Internal server error"
+// @Security		BearerAuth
+// @Router			/admin/profiles [post]
+func (h *ProfileUpdateHandler) HandleCreateProfile(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req types.SaveProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+
+	user, err := h.getCurrentUser(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Failed to get current user")
+	}
+
+	var header struct {
+		Name string `yaml:"name"`
+	}
+	if err := yaml.Unmarshal([]byte(req.Definition), &header); err != nil || header.Name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Definition must be a profile with a name")
+	}
+
+	if _, _, err := h.store.GetProfileSource(ctx, header.Name); err == nil {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Profile %s already exists", header.Name))
+	} else if !errors.Is(err, store.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get profile: %v", err))
+	}
+
+	p, err := h.resolveDefinition(header.Name, req.Definition)
+	if err != nil {
+		return err
+	}
+
+	rev, err := h.saveRevision(ctx, user, nil, p, req.Definition, req.Message, req.Draft)
+	if err != nil {
+		return err
+	}
+
+	h.auditProfileChange(ctx, user, "profile_create", rev)
+	return c.JSON(http.StatusCreated, rev)
+}
+
+// @Summary		Update a profile
+// @Description	Saves a new revision of a profile from a YAML (or JSON) definition. The revision is published unless draft is set. Profiles changed through the API are no longer overwritten by the profile file sync.
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			name	path		string						true	"Profile name"
+// @Param			request	body		types.SaveProfileRequest	true	"Profile definition"
+// @Success		200		{object}	types.ProfileRevision
+// @Failure		400		{object}	ErrorResponse	"Invalid definition"
+// @Failure		401		{object}	ErrorResponse	"Unauthorized"
+// @Failure		403		{object}	ErrorResponse	"Forbidden - Admin access required"
+// @Failure		404		{object}	ErrorResponse	"Profile not found"
+// @Failure		409		{object}	ErrorResponse	"Concurrent update"
+// @Failure		500		{object}	ErrorResponse	"Internal server error"
+// @Security		BearerAuth
+// @Router			/admin/profiles/{name} [put]
+func (h *ProfileUpdateHandler) HandleUpdateProfile(c echo.Context) error {
+	ctx := c.Request().Context()
+	profileName := c.Param("name")
+
+	var req types.SaveProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+	}
+
+	user, err := h.getCurrentUser(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Failed to get current user")
+	}
+
+	current, err := h.publishedProfile(ctx, profileName)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		// A profile that only has drafts can still be edited
+		revisions, err := h.store.ListProfileRevisions(ctx, profileName)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list revisions: %v", err))
+		}
+		if len(revisions) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Profile not found: %s", profileName))
+		}
+	}
+
+	p, err := h.resolveDefinition(profileName, req.Definition)
+	if err != nil {
+		return err
+	}
+	if p.Name != profileName {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Definition name %q does not match profile %s", p.Name, profileName))
+	}
+
+	rev, err := h.saveRevision(ctx, user, current, p, req.Definition, req.Message, req.Draft)
+	if err != nil {
+		return err
+	}
+
+	h.auditProfileChange(ctx, user, "profile_edit", rev)
+	return c.JSON(http.StatusOK, rev)
+}
+
+// @Summary		Delete a profile
+// @Description	Deletes a profile that no active cluster or pool uses. Its revisions are kept so it can be restored with a rollback. A profile that still has a file in the profiles directory is recreated from it on the next file sync.
+// @Tags			admin
+// @Param			name	path	string	true	"Profile name"
+// @Success		204
+// @Failure		401	{object}	ErrorResponse	"Unauthorized"
+// @Failure		403	{object}	ErrorResponse	"Forbidden - Admin access required"
+// @Failure		404	{object}	ErrorResponse	"Profile not found"
+// @Failure		409	{object}	ErrorResponse	"Profile in use"
+// @Failure		500	{object}	ErrorResponse	"Internal server error"
+// @Security		BearerAuth
+// @Router			/admin/profiles/{name} [delete]
+func (h *ProfileUpdateHandler) HandleDeleteProfile(c echo.Context) error {
+	ctx := c.Request().Context()
+	profileName := c.Param("name")
+
+	user, err := h.getCurrentUser(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Failed to get current user")
+	}
+
+	clusters, pools, err := h.store.CountProfileUsage(ctx, profileName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if clusters > 0 || pools > 0 {
+		return echo.NewHTTPError(http.StatusConflict,
+			fmt.Sprintf("Profile %s is used by %d active cluster(s) and %d pool(s)", profileName, clusters, pools))
+	}
+
+	if err := h.store.DeleteProfile(ctx, profileName); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Profile not found: %s", profileName))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete profile: %v", err))
+	}
+	h.reloadRegistry()
+
+	auditEvent := &types.AuditEvent{
+		Actor:  user.ID,
+		Action: fmt.Sprintf("profile_delete:%s", profileName),
+		Status: types.AuditEventStatusSuccess,
+	}
+	if err := h.store.Audit.Log(ctx, auditEvent); err != nil {
+		fmt.Printf("Warning: failed to create audit event: %v\n", err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary		List profile revisions
+// @Description	Lists the revisions of a profile, newest first, with author, status and diff
+// @Tags			admin
+// @Produce		json
+// @Param			name	path		string	true	"Profile name"
+// @Success		200		{array}		types.ProfileRevision
+// @Failure		401		{object}	ErrorResponse	"Unauthorized"
+// @Failure		403		{object}	ErrorResponse	"Forbidden - Admin access required"
+// @Failure		404		{object}	ErrorResponse	"Profile not found"
+// @Failure		500		{object}	ErrorResponse	"Internal server error"
+// @Security		BearerAuth
+// @Router			/admin/profiles/{name}/revisions [get]
+func (h *ProfileUpdateHandler) HandleListProfileRevisions(c echo.Context) error {
+	profileName := c.Param("name")
+
+	revisions, err := h.store.ListProfileRevisions(c.Request().Context(), profileName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list revisions: %v", err))
+	}
+	if len(revisions) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("No revisions for profile %s", profileName))
+	}
+
+	return c.JSON(http.StatusOK, revisions)
+}
+
+// @Summary		Get a profile revision
+// @Tags			admin
+// @Produce		json
+// @Param			name		path		string	true	"Profile name"
+// @Param			revision	path		int		true	"Revision number"
+// @Success		200			{object}	types.ProfileRevision
+// @Failure		400			{object}	ErrorResponse	"Invalid revision"
+// @Failure		401			{object}	ErrorResponse	"Unauthorized"
+// @Failure		403			{object}	ErrorResponse	"Forbidden - Admin access required"
+// @Failure		404			{object}	ErrorResponse	"Revision not found"
+// @Failure		500			{object}	ErrorResponse	"Internal server error"
+// @Security		BearerAuth
+// @Router			/admin/profiles/{name}/revisions/{revision} [get]
+func (h *ProfileUpdateHandler) HandleGetProfileRevision(c echo.Context) error {
+	profileName := c.Param("name")
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Revision must be a positive number")
+	}
+
+	rev, err := h.store.GetProfileRevision(c.Request().Context(), profileName, revision)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Revision %d of profile %s not found", revision, profileName))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, rev)
+}
+
+// @Summary		Publish a draft revision
+// @Description	Publishes a draft revision, replacing the published profile
+// @Tags			admin
+// @Produce		json
+// @Param			name		path		string	true	"Profile name"
+// @Param			revision	path		int		true	"Revision number"
+// @Success		200			{object}	types.ProfileRevision
+// @Failure		400			{object}	ErrorResponse	"Invalid revision"
+// @Failure		401			{object}	ErrorResponse	"Unauthorized"
+// @Failure		403			{object}	ErrorResponse	"Forbidden - Admin access required"
+// @Failure		404			{object}	ErrorResponse	"Revision not found"
+// @Failure		409			{object}	ErrorResponse	"Revision is not a draft"
+// @Failure		500			{object}	ErrorResponse	"Internal server error"
+// @Security		BearerAuth
+// @Router			/admin/profiles/{name}/revisions/{revision}/publish [post]
+func (h *ProfileUpdateHandler) HandlePublishProfileRevision(c echo.Context) error {
+	ctx := c.Request().Context()
+	profileName := c.Param("name")
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Revision must be a positive number")
+	}
+
+	user, err := h.getCurrentUser(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Failed to get current user")
+	}
+
+	rev, err := h.store.PublishProfileRevision(ctx, profileName, revision)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Revision %d of profile %s not found", revision, profileName))
+		case errors.Is(err, store.ErrConflict):
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Revision %d is not a draft; use rollback to restore it", revision))
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to publish revision: %v", err))
+	}
+	h.reloadRegistry()
+
+	h.auditProfileChange(ctx, user, "profile_publish", rev)
+	return c.JSON(http.StatusOK, rev)
+}
+
+// @Summary		Roll back a profile
+// @Description	Publishes the content of an earlier revision as a new revision. Without a revision in the body, restores the revision published before the current one.
+// @Tags			admin
+// @Accept			json
+// @Produce		json
+// @Param			name	path		string							true	"Profile name"
+// @Param			request	body		types.RollbackProfileRequest	false	"Revision to restore"
+// @Success		200		{object}	types.ProfileRevision
+// @Failure		400		{object}	ErrorResponse	"Invalid request"
+// @Failure		401		{object}	ErrorResponse	"Unauthorized"
+// @Failure		403		{object}	ErrorResponse	"Forbidden - Admin access required"
+// @Failure		404		{object}	ErrorResponse	"Profile or revision not found"
+// @Failure		409		{object}	ErrorResponse	"Concurrent update"
+// @Failure		500		{object}	ErrorResponse	"Internal server error"
+// @Security		BearerAuth
+// @Router			/admin/profiles/{name}/rollback [post]
+func (h *ProfileUpdateHandler) HandleRollbackProfile(c echo.Context) error {
+	ctx := c.Request().Context()
+	profileName := c.Param("name")
+
+	var req types.RollbackProfileRequest
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		}
+	}
+
+	user, err := h.getCurrentUser(c)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Failed to get current user")
+	}
+
+	revisions, err := h.store.ListProfileRevisions(ctx, profileName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list revisions: %v", err))
+	}
+	if len(revisions) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("No revisions for profile %s", profileName))
+	}
+
+	target := rollbackTarget(revisions, req.Revision)
+	if target == nil {
+		if req.Revision == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "No earlier published revision to roll back to")
+		}
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Revision %d of profile %s not found", req.Revision, profileName))
+	}
+
+	var p profile.Profile
+	if err := json.Unmarshal(target.ProfileData, &p); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to read revision %d: %v", target.Revision, err))
+	}
+
+	current, err := h.publishedProfile(ctx, profileName)
+	if err != nil {
+		return err
+	}
+
+	message := req.Message
+	if message == "" {
+		message = fmt.Sprintf("Rollback to revision %d", target.Revision)
+	}
+	rev, err := h.saveRevision(ctx, user, current, &p, target.Definition, message, false)
+	if err != nil {
+		return err
+	}
+
+	h.auditProfileChange(ctx, user, "profile_rollback", rev)
+	return c.JSON(http.StatusOK, rev)
+}
+
+// rollbackTarget picks the revision to restore from revisions, newest first.
+// revision 0 selects the latest revision published before the current one.
+func rollbackTarget(revisions []*types.ProfileRevision, revision int) *types.ProfileRevision {
+	if revision != 0 {
+		for _, rev := range revisions {
+			if rev.Revision == revision {
+				return rev
+			}
+		}
+		return nil
+	}
+
+	seenCurrent := false
+	for _, rev := range revisions {
+		if rev.Status == types.ProfileRevisionPublished {
+			seenCurrent = true
+			continue
+		}
+		if seenCurrent && rev.Status == types.ProfileRevisionSuperseded {
+			return rev
+		}
+	}
+	return nil
+}
+
+// resolveDefinition loads and validates a submitted profile definition.
+// Definitions must be standalone; see profile.CheckStandalone.
+func (h *ProfileUpdateHandler) resolveDefinition(name, definition string) (*profile.Profile, error) {
+	if definition == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Definition is required")
+	}
+
+	if err := profile.CheckStandalone([]byte(definition)); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid profile: %v", err))
+	}
+
+	p, err := h.loader.LoadData(name, []byte(definition))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid profile: %v", err))
+	}
+	return p, nil
+}
+
+// publishedProfile returns the published profile, or nil if there is none
+func (h *ProfileUpdateHandler) publishedProfile(ctx context.Context, name string) (*profile.Profile, error) {
+	if _, _, err := h.store.GetProfileSource(ctx, name); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get profile: %v", err))
+	}
+
+	p, err := h.store.GetProfile(ctx, name)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get profile: %v", err))
+	}
+	return p, nil
+}
+
+// saveRevision records p as a new API-managed revision, diffed against the
+// published profile current (nil for a new profile), and reloads the
+// registry if it was published
+func (h *ProfileUpdateHandler) saveRevision(ctx context.Context, user *types.User, current, p *profile.Profile, definition, message string, draft bool) (*types.ProfileRevision, error) {
+	profileData, err := json.Marshal(p)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal profile: %v", err))
+	}
+
+	diff, err := profile.Diff(current, p)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	rev := &types.ProfileRevision{
+		ProfileName: p.Name,
+		Status:      types.ProfileRevisionPublished,
+		Source:      types.ProfileSourceAPI,
+		Definition:  definition,
+		ProfileData: profileData,
+		Diff:        diff,
+		Message:     message,
+		AuthorID:    &user.ID,
+		AuthorEmail: &user.Email,
+	}
+	if draft {
+		rev.Status = types.ProfileRevisionDraft
+	}
+
+	if err := h.store.CreateProfileRevision(ctx, rev); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "Profile was changed concurrently, retry the request")
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to save revision: %v", err))
+	}
+
+	if !draft {
+		h.reloadRegistry()
+	}
+	return rev, nil
+}
+
+// reloadRegistry makes a published change visible on this replica
+// immediately; other replicas pick it up when they next poll
+func (h *ProfileUpdateHandler) reloadRegistry() {
+	if err := h.registry.Reload(); err != nil {
+		fmt.Printf("Warning: failed to reload profile registry: %v\n", err)
+	}
+}
+
+func (h *ProfileUpdateHandler) auditProfileChange(ctx context.Context, user *types.User, action string, rev *types.ProfileRevision) {
+	auditEvent := &types.AuditEvent{
+		Actor:  user.ID,
+		Action: fmt.Sprintf("%s:%s", action, rev.ProfileName),
+		Status: types.AuditEventStatusSuccess,
+		Metadata: types.JobMetadata{
+			"revision": rev.Revision,
+			"status":   rev.Status,
+			"message":  rev.Message,
+		},
+	}
+	if err := h.store.Audit.Log(ctx, auditEvent); err != nil {
+		fmt.Printf("Warning: failed to create audit event: %v\n", err)
+	}
+}
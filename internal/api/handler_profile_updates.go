@@ -1,39 +1,34 @@
 package api
 
 import (
-	"context"
 	"fmt"
 	"net/http"
-	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/addon"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
-	"github.com/tsanders-rh/ocpctl/internal/s3"
 	"github.com/tsanders-rh/ocpctl/internal/store"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
-// ProfileUpdateHandler handles profile version update operations
+// ProfileUpdateHandler handles profile management and version update
+// operations. Changes are saved as revisions in the database; the profiles
+// directory is only read to resolve base profiles and fragments.
 type ProfileUpdateHandler struct {
 	registry       *profile.Registry
 	versionChecker *profile.VersionChecker
-	updater        *profile.ProfileUpdater
+	loader         *profile.Loader
 	store          *store.Store
-	s3Client       *s3.Client
-	profilesDir    string
 }
 
 // NewProfileUpdateHandler creates a new profile update handler
-func NewProfileUpdateHandler(registry *profile.Registry, store *store.Store, s3Client *s3.Client, profilesDir string) *ProfileUpdateHandler {
+func NewProfileUpdateHandler(registry *profile.Registry, store *store.Store, profilesDir string) *ProfileUpdateHandler {
 	return &ProfileUpdateHandler{
 		registry:       registry,
 		versionChecker: profile.NewVersionChecker(),
-		updater:        profile.NewProfileUpdater(profilesDir),
+		loader:         profile.NewLoader(profilesDir),
 		store:          store,
-		s3Client:       s3Client,
-		profilesDir:    profilesDir,
 	}
 }
 
@@ -46,35 +41,19 @@ type CheckVersionsResponse struct {
 	LastChecked         time.Time                      `json:"last_checked"`
 }
 
-// UpdateVersionsRequest represents the request to update profile versions
-type UpdateVersionsRequest struct {
-	OpenshiftVersions        []string `json:"openshift_versions,omitempty"`
-	KubernetesVersions       []string `json:"kubernetes_versions,omitempty"`
-	OpenshiftDefaultVersion  string   `json:"openshift_default_version,omitempty"`
-	KubernetesDefaultVersion string   `json:"kubernetes_default_version,omitempty"`
-	DryRun                   bool     `json:"dry_run,omitempty"`
-}
-
 // UpdateVersionsResponse represents the response after updating versions
 type UpdateVersionsResponse struct {
 	Success        bool             `json:"success"`
 	ProfileName    string           `json:"profile_name"`
-	BackupPath     string           `json:"backup_path,omitempty"`
+	Revision       int              `json:"revision,omitempty"`
 	UpdatedAt      time.Time        `json:"updated_at"`
 	AuditEventID   string           `json:"audit_event_id,omitempty"`
 	DryRun         bool             `json:"dry_run,omitempty"`
 	PreviewProfile *profile.Profile `json:"preview_profile,omitempty"`
 }
 
-// ReloadProfilesResponse represents the response after reloading profiles
-type ReloadProfilesResponse struct {
-	Success        bool      `json:"success"`
-	ProfilesLoaded int       `json:"profiles_loaded"`
-	ReloadedAt     time.Time `json:"reloaded_at"`
-}
-
 // @Summary		Check profiles for version updates
-// @Description	Checks all enabled profiles for available OpenShift and Kubernetes version updates from official release channels
+// @Description	Checks all enabled profiles for available OpenShift and Kubernetes version updates from official release channels, and flags add-ons whose compatibility ranges miss a profile's current or new versions
 // @Tags			admin
 // @Produce		json
 // @Param			refresh		query		bool	false	"Force refresh version cache"
@@ -123,6 +102,12 @@ func (h *ProfileUpdateHandler) HandleCheckVersions(c echo.Context) error {
 		}(prof)
 	}
 
+	// Add-ons are checked for versions their compatibility ranges miss
+	addons, err := h.store.PostConfigAddons.List(ctx, nil, nil)
+	if err != nil {
+		fmt.Printf("Warning: failed to load addons for coverage check: %v\n", err)
+	}
+
 	// Collect results
 	allProfiles := []profile.ProfileVersionStatus{}
 	totalProfiles := len(profiles)
@@ -137,6 +122,8 @@ func (h *ProfileUpdateHandler) HandleCheckVersions(c echo.Context) error {
 		}
 
 		if res.status != nil {
+			versions := append(append([]string{}, res.status.CurrentVersions...), res.status.NewVersions...)
+			res.status.AddonGaps = addon.CoverageGaps(addons, types.ClusterType(res.status.ClusterType), versions)
 			allProfiles = append(allProfiles, *res.status)
 			if res.status.UpdateCount > 0 {
 				updatesAvailable++
@@ -156,12 +143,12 @@ func (h *ProfileUpdateHandler) HandleCheckVersions(c echo.Context) error {
 }
 
 // @Summary		Update profile versions
-// @Description	Updates the OpenShift or Kubernetes version allowlist and default version for a specific profile. Creates a backup before updating.
+// @Description	Updates the OpenShift or Kubernetes version allowlist and default version for a specific profile. The change is published as a new profile revision.
 // @Tags			admin
 // @Accept			json
 // @Produce		json
 // @Param			name	path		string					true	"Profile name"
-// @Param			request	body		UpdateVersionsRequest	true	"Version update request"
+// @Param			request	body		types.UpdateVersionsRequest	true	"Version update request"
 // @Success		200		{object}	UpdateVersionsResponse
 // @Failure		400		{object}	ErrorResponse	"Invalid request"
 // @Failure		401		{object}	ErrorResponse	"Unauthorized"
@@ -175,7 +162,7 @@ func (h *ProfileUpdateHandler) HandleUpdateVersions(c echo.Context) error {
 	profileName := c.Param("name")
 
 	// Parse request
-	var req UpdateVersionsRequest
+	var req types.UpdateVersionsRequest
 	if err := c.Bind(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
 	}
@@ -191,35 +178,15 @@ func (h *ProfileUpdateHandler) HandleUpdateVersions(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Failed to get current user")
 	}
 
-	// Prepare version update
-	updates := &profile.VersionUpdate{
-		OpenshiftVersions:        req.OpenshiftVersions,
-		KubernetesVersions:       req.KubernetesVersions,
-		OpenshiftDefaultVersion:  req.OpenshiftDefaultVersion,
-		KubernetesDefaultVersion: req.KubernetesDefaultVersion,
-	}
-
-	// Dry run mode - validate without writing
-	if req.DryRun {
-		previewProfile, err := h.updater.DryRun(profileName, updates)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Dry run validation failed: %v", err))
-		}
-
-		return c.JSON(http.StatusOK, UpdateVersionsResponse{
-			Success:        true,
-			ProfileName:    profileName,
-			UpdatedAt:      time.Now(),
-			DryRun:         true,
-			PreviewProfile: previewProfile,
-		})
-	}
-
 	// Get current profile
-	p, err := h.store.GetProfile(ctx, profileName)
+	current, err := h.store.GetProfile(ctx, profileName)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Profile not found: %v", err))
 	}
+	p, err := current.Clone()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to copy profile: %v", err))
+	}
 
 	// Update the version configuration based on cluster type
 	if len(req.OpenshiftVersions) > 0 {
@@ -242,13 +209,34 @@ func (h *ProfileUpdateHandler) HandleUpdateVersions(c echo.Context) error {
 		}
 	}
 
-	// Perform database update (no cache, immediately visible!)
-	err = h.store.UpsertProfile(ctx, p)
+	if err := h.loader.Validate(p); err != nil {
+		if req.DryRun {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Dry run validation failed: %v", err))
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid profile: %v", err))
+	}
+
+	// Dry run mode - validate without saving
+	if req.DryRun {
+		return c.JSON(http.StatusOK, UpdateVersionsResponse{
+			Success:        true,
+			ProfileName:    profileName,
+			UpdatedAt:      time.Now(),
+			DryRun:         true,
+			PreviewProfile: p,
+		})
+	}
+
+	definition, err := profile.Definition(p)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update profile: %v", err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	backupPath := "" // Database doesn't need file backups
+	// Publish as a new revision (visible to every replica on its next poll)
+	rev, err := h.saveRevision(ctx, user, current, p, string(definition), "Updated versions", false)
+	if err != nil {
+		return err
+	}
 
 	// Create audit event
 	auditEvent := &types.AuditEvent{
@@ -258,7 +246,7 @@ func (h *ProfileUpdateHandler) HandleUpdateVersions(c echo.Context) error {
 		Metadata: types.JobMetadata{
 			"openshift_versions":  req.OpenshiftVersions,
 			"kubernetes_versions": req.KubernetesVersions,
-			"backup_path":         backupPath,
+			"revision":            rev.Revision,
 		},
 	}
 
@@ -267,13 +255,10 @@ func (h *ProfileUpdateHandler) HandleUpdateVersions(c echo.Context) error {
 		fmt.Printf("Warning: failed to create audit event: %v\n", err)
 	}
 
-	// Sync to S3 if configured (for multi-node deployment)
-	go h.syncProfileToS3(profileName)
-
 	response := UpdateVersionsResponse{
 		Success:      true,
 		ProfileName:  profileName,
-		BackupPath:   backupPath,
+		Revision:     rev.Revision,
 		UpdatedAt:    time.Now(),
 		AuditEventID: auditEvent.ID,
 		DryRun:       false,
@@ -283,10 +268,10 @@ func (h *ProfileUpdateHandler) HandleUpdateVersions(c echo.Context) error {
 }
 
 // @Summary		Reload all profiles
-// @Description	Forces a reload of all profiles from the profiles directory into the in-memory registry. Useful after manual profile file changes.
+// @Description	Forces a reload of the published profiles from the database into this replica's in-memory registry. Replicas also reload on their own when a profile changes.
 // @Tags			admin
 // @Produce		json
-// @Success		200	{object}	ReloadProfilesResponse
+// @Success		200	{object}	types.ReloadProfilesResponse
 // @Failure		401	{object}	ErrorResponse	"Unauthorized"
 // @Failure		403	{object}	ErrorResponse	"Forbidden - Admin access required"
 // @Failure		500	{object}	ErrorResponse	"Internal server error"
@@ -325,7 +310,7 @@ func (h *ProfileUpdateHandler) HandleReloadProfiles(c echo.Context) error {
 		fmt.Printf("Warning: failed to create audit event: %v\n", err)
 	}
 
-	response := ReloadProfilesResponse{
+	response := types.ReloadProfilesResponse{
 		Success:        true,
 		ProfilesLoaded: profileCount,
 		ReloadedAt:     time.Now(),
@@ -334,62 +319,6 @@ func (h *ProfileUpdateHandler) HandleReloadProfiles(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
-// @Summary		Rollback profile to backup
-// @Description	Rolls back a profile to its latest backup file. This restores the previous version of the profile configuration before the last update.
-// @Tags			admin
-// @Produce		json
-// @Param			name	path		string	true	"Profile name"
-// @Success		200		{object}	map[string]interface{}
-// @Failure		401		{object}	ErrorResponse	"Unauthorized"
-// @Failure		403		{object}	ErrorResponse	"Forbidden - Admin access required"
-// @Failure		404		{object}	ErrorResponse	"Profile not found or no backup available"
-// @Failure		500		{object}	ErrorResponse	"Internal server error"
-// @Security		BearerAuth
-// @Router			/admin/profiles/{name}/rollback [post]
-func (h *ProfileUpdateHandler) HandleRollbackProfile(c echo.Context) error {
-	ctx := c.Request().Context()
-	profileName := c.Param("name")
-
-	// Get current user for audit logging
-	user, err := h.getCurrentUser(c)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, "Failed to get current user")
-	}
-
-	// Perform rollback
-	if err := h.updater.Rollback(profileName); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to rollback profile: %v", err))
-	}
-
-	// Reload profile registry
-	if err := h.registry.Reload(); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Profile rolled back but registry reload failed: %v", err))
-	}
-
-	// Create audit event
-	auditEvent := &types.AuditEvent{
-		Actor:  user.ID,
-		Action: fmt.Sprintf("profile_rollback:%s", profileName),
-		Status: types.AuditEventStatusSuccess,
-		Metadata: types.JobMetadata{
-			"rolled_back_at": time.Now(),
-		},
-	}
-
-	if err := h.store.Audit.Log(ctx, auditEvent); err != nil {
-		fmt.Printf("Warning: failed to create audit event: %v\n", err)
-	}
-
-	// Sync to S3
-	go h.syncProfileToS3(profileName)
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"success":        true,
-		"profile_name":   profileName,
-		"rolled_back_at": time.Now(),
-	})
-}
-
 // getCurrentUser extracts the current user from the context
 func (h *ProfileUpdateHandler) getCurrentUser(c echo.Context) (*types.User, error) {
 	user, ok := c.Get("user").(*types.User)
@@ -398,34 +327,3 @@ func (h *ProfileUpdateHandler) getCurrentUser(c echo.Context) (*types.User, erro
 	}
 	return user, nil
 }
-
-// syncProfileToS3 syncs a profile to S3 for multi-node deployment
-func (h *ProfileUpdateHandler) syncProfileToS3(profileName string) {
-	ctx := context.Background()
-
-	// Check if S3 client is available
-	if h.s3Client == nil {
-		fmt.Printf("Warning: S3 client not initialized, skipping profile sync for %s\n", profileName)
-		return
-	}
-
-	// Read the updated profile file
-	profilePath := filepath.Join(h.profilesDir, profileName+".yaml")
-	data, err := os.ReadFile(profilePath)
-	if err != nil {
-		fmt.Printf("Error reading profile %s for S3 sync: %v\n", profileName, err)
-		return
-	}
-
-	// Upload to S3 bucket (ocpctl-binaries/profiles/)
-	bucket := "ocpctl-binaries"
-	s3Key := fmt.Sprintf("profiles/%s.yaml", profileName)
-
-	err = h.s3Client.UploadFile(ctx, bucket, s3Key, data)
-	if err != nil {
-		fmt.Printf("Error syncing profile %s to S3: %v\n", profileName, err)
-		return
-	}
-
-	fmt.Printf("✓ Synced profile %s to s3://%s/%s\n", profileName, bucket, s3Key)
-}
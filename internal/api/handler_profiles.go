@@ -38,6 +38,7 @@ type ProfileResponse struct {
 	Regions            profile.RegionConfig            `json:"regions"`
 	BaseDomains        *profile.BaseDomainConfig       `json:"base_domains,omitempty"`
 	Compute            profile.ComputeConfig           `json:"compute"`
+	Overridable        *profile.OverridableConfig      `json:"overridable,omitempty"` // Compute parameters a create request may override
 	Lifecycle          profile.LifecycleConfig         `json:"lifecycle"`
 	Networking         *profile.NetworkingConfig       `json:"networking,omitempty"`
 	Tags               profile.TagsConfig              `json:"tags"`
@@ -45,6 +46,7 @@ type ProfileResponse struct {
 	CostControls       *profile.CostControlsConfig     `json:"cost_controls,omitempty"`
 	PostDeployment     *profile.PostDeploymentConfig   `json:"post_deployment,omitempty"`
 	DefaultAddons      []profile.AddonReference        `json:"default_addons,omitempty"`
+	Layers             []string                        `json:"layers,omitempty"` // Files merged into the profile, base first
 	DeploymentMetrics  *types.ProfileDeploymentMetrics `json:"deployment_metrics,omitempty"`
 }
 
@@ -63,6 +65,7 @@ func toProfileResponse(p *profile.Profile) *ProfileResponse {
 		Regions:            p.Regions,
 		BaseDomains:        p.BaseDomains,
 		Compute:            p.Compute,
+		Overridable:        p.Overridable,
 		Lifecycle:          p.Lifecycle,
 		Networking:         p.Networking,
 		Tags:               p.Tags,
@@ -70,6 +73,7 @@ func toProfileResponse(p *profile.Profile) *ProfileResponse {
 		CostControls:       &p.CostControls,
 		PostDeployment:     p.PostDeployment,
 		DefaultAddons:      p.DefaultAddons,
+		Layers:             p.Layers,
 	}
 }
 
@@ -109,8 +113,10 @@ func (h *ProfileHandler) List(c echo.Context) error {
 			platform = types.PlatformGCP
 		case "azure":
 			platform = types.PlatformAzure
+		case "local":
+			platform = types.PlatformLocal
 		default:
-			return ErrorBadRequest(c, "Invalid platform. Must be 'aws', 'ibmcloud', 'gcp', or 'azure'")
+			return ErrorBadRequest(c, "Invalid platform. Must be 'aws', 'ibmcloud', 'gcp', 'azure', or 'local'")
 		}
 		platformFilter = &platform
 	}
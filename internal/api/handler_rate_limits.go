@@ -0,0 +1,211 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/auth"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// RateLimitHandler handles rate limit policy management endpoints (admin only)
+type RateLimitHandler struct {
+	store    *store.Store
+	resolver *RateLimitResolver
+}
+
+// NewRateLimitHandler creates a new rate limit handler
+func NewRateLimitHandler(st *store.Store, resolver *RateLimitResolver) *RateLimitHandler {
+	return &RateLimitHandler{
+		store:    st,
+		resolver: resolver,
+	}
+}
+
+// List returns all rate limit policies
+//
+//	@Summary		List rate limit policies
+//	@Description	Returns all per-role, per-user and per-API-key rate limit overrides (admin only)
+//	@Tags			RateLimits
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}	"Returns policies array"
+//	@Failure		500	{object}	map[string]string		"Failed to list policies"
+//	@Security		BearerAuth
+//	@Router			/admin/rate-limits [get]
+func (h *RateLimitHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	policies, err := h.store.RateLimits.ListPolicies(ctx)
+	if err != nil {
+		return LogAndReturnGenericError(c, err)
+	}
+
+	return SuccessOK(c, map[string]interface{}{
+		"policies": policies,
+	})
+}
+
+// Create creates a rate limit policy
+//
+//	@Summary		Create rate limit policy
+//	@Description	Overrides the global rate limit for an API key, user or role (admin only)
+//	@Tags			RateLimits
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		types.CreateRateLimitPolicyRequest	true	"Policy creation request"
+//	@Success		201		{object}	types.RateLimitPolicy
+//	@Failure		400		{object}	map[string]string	"Invalid request or validation error"
+//	@Failure		409		{object}	map[string]string	"Policy already exists for scope and subject"
+//	@Failure		500		{object}	map[string]string	"Failed to create policy"
+//	@Security		BearerAuth
+//	@Router			/admin/rate-limits [post]
+func (h *RateLimitHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req types.CreateRateLimitPolicyRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorBadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+
+	if !req.Scope.IsValid() {
+		return ErrorBadRequest(c, "scope must be one of: api_key, user, role")
+	}
+
+	// Catch typos early: a policy for a subject that doesn't exist never matches
+	switch req.Scope {
+	case types.RateLimitScopeRole:
+		if !types.UserRole(req.Subject).IsValid() {
+			return ErrorBadRequest(c, "subject must be a valid role for scope 'role'")
+		}
+	case types.RateLimitScopeUser:
+		if _, err := h.store.Users.GetByID(ctx, req.Subject); err != nil {
+			return ErrorBadRequest(c, "user not found: "+req.Subject)
+		}
+	case types.RateLimitScopeAPIKey:
+		if _, err := h.store.APIKeys.GetByID(ctx, req.Subject); err != nil {
+			return ErrorBadRequest(c, "API key not found: "+req.Subject)
+		}
+	}
+
+	userID, err := auth.GetUserID(c)
+	if err != nil {
+		return err
+	}
+
+	policy := &types.RateLimitPolicy{
+		Scope:             req.Scope,
+		Subject:           req.Subject,
+		RequestsPerMinute: req.RequestsPerMinute,
+		Burst:             req.Burst,
+		Description:       req.Description,
+		CreatedBy:         &userID,
+	}
+
+	if err := h.store.RateLimits.CreatePolicy(ctx, policy); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return ErrorConflict(c, "a rate limit policy already exists for "+string(req.Scope)+" '"+req.Subject+"'")
+		}
+		return LogAndReturnGenericError(c, err)
+	}
+
+	h.resolver.Invalidate()
+
+	LogInfo(c, "rate limit policy created",
+		"policy_id", policy.ID,
+		"scope", policy.Scope,
+		"subject", policy.Subject,
+		"user_id", userID)
+
+	return SuccessCreated(c, policy)
+}
+
+// Update updates a rate limit policy
+//
+//	@Summary		Update rate limit policy
+//	@Description	Updates the limits of a rate limit policy (admin only)
+//	@Tags			RateLimits
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"Policy ID"
+//	@Param			body	body		types.UpdateRateLimitPolicyRequest	true	"Policy update fields"
+//	@Success		200		{object}	types.RateLimitPolicy
+//	@Failure		400		{object}	map[string]string	"Invalid request or validation error"
+//	@Failure		404		{object}	map[string]string	"Policy not found"
+//	@Failure		500		{object}	map[string]string	"Failed to update policy"
+//	@Security		BearerAuth
+//	@Router			/admin/rate-limits/{id} [patch]
+func (h *RateLimitHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req types.UpdateRateLimitPolicyRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrorBadRequest(c, "invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return ErrorBadRequest(c, err.Error())
+	}
+
+	policy, err := h.store.RateLimits.GetPolicy(ctx, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrorNotFound(c, "rate limit policy not found")
+		}
+		return LogAndReturnGenericError(c, err)
+	}
+
+	if req.RequestsPerMinute != nil {
+		policy.RequestsPerMinute = *req.RequestsPerMinute
+	}
+	if req.Burst != nil {
+		policy.Burst = *req.Burst
+	}
+	if req.Description != nil {
+		policy.Description = req.Description
+	}
+
+	if err := h.store.RateLimits.UpdatePolicy(ctx, policy); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrorNotFound(c, "rate limit policy not found")
+		}
+		return LogAndReturnGenericError(c, err)
+	}
+
+	h.resolver.Invalidate()
+
+	return SuccessOK(c, policy)
+}
+
+// Delete deletes a rate limit policy
+//
+//	@Summary		Delete rate limit policy
+//	@Description	Removes a rate limit override; the principal falls back to the next matching policy or the global limit (admin only)
+//	@Tags			RateLimits
+//	@Produce		json
+//	@Param			id	path		string	true	"Policy ID"
+//	@Success		200	{object}	map[string]string
+//	@Failure		404	{object}	map[string]string	"Policy not found"
+//	@Failure		500	{object}	map[string]string	"Failed to delete policy"
+//	@Security		BearerAuth
+//	@Router			/admin/rate-limits/{id} [delete]
+func (h *RateLimitHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := h.store.RateLimits.DeletePolicy(ctx, c.Param("id")); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrorNotFound(c, "rate limit policy not found")
+		}
+		return LogAndReturnGenericError(c, err)
+	}
+
+	h.resolver.Invalidate()
+
+	return SuccessOK(c, map[string]string{
+		"message": "rate limit policy deleted successfully",
+	})
+}
@@ -26,33 +26,6 @@ func NewStorageHandler(s *store.Store, p *policy.Engine) *StorageHandler {
 	}
 }
 
-// LinkStorageRequest represents the request to link storage between clusters
-type LinkStorageRequest struct {
-	TargetClusterID string `json:"target_cluster_id" validate:"required"`
-}
-
-// StorageGroupResponse represents the response for a storage group
-type StorageGroupResponse struct {
-	ID                 string                       `json:"id"`
-	Name               string                       `json:"name"`
-	EFSID              *string                      `json:"efs_id,omitempty"`
-	EFSSecurityGroupID *string                      `json:"efs_security_group_id,omitempty"`
-	S3Bucket           *string                      `json:"s3_bucket,omitempty"`
-	Region             string                       `json:"region"`
-	Status             types.StorageGroupStatus     `json:"status"`
-	LinkedClusters     []ClusterStorageLinkResponse `json:"linked_clusters"`
-	CreatedAt          time.Time                    `json:"created_at"`
-	UpdatedAt          time.Time                    `json:"updated_at"`
-}
-
-// ClusterStorageLinkResponse represents a cluster linked to a storage group
-type ClusterStorageLinkResponse struct {
-	ClusterID   string                       `json:"cluster_id"`
-	ClusterName string                       `json:"cluster_name"`
-	Role        types.ClusterStorageLinkRole `json:"role"`
-	LinkedAt    time.Time                    `json:"linked_at"`
-}
-
 // LinkToCluster handles POST /api/v1/clusters/:id/storage/link
 //
 //	@Summary		Link storage to cluster
@@ -61,8 +34,8 @@ type ClusterStorageLinkResponse struct {
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string				true	"Cluster ID"
-//	@Param			body	body		LinkStorageRequest	true	"Link storage request"
-//	@Success		200		{object}	StorageGroupResponse
+//	@Param			body	body		types.LinkStorageRequest	true	"Link storage request"
+//	@Success		200		{object}	types.StorageGroupResponse
 //	@Failure		400		{object}	map[string]string	"Invalid request or validation error"
 //	@Failure		403		{object}	map[string]string	"Forbidden - not cluster owner"
 //	@Failure		404		{object}	map[string]string	"Cluster not found"
@@ -72,7 +45,7 @@ type ClusterStorageLinkResponse struct {
 func (h *StorageHandler) LinkToCluster(c echo.Context) error {
 	clusterID := c.Param("id")
 
-	var req LinkStorageRequest
+	var req types.LinkStorageRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
@@ -180,7 +153,7 @@ func (h *StorageHandler) LinkToCluster(c echo.Context) error {
 //	@Accept			json
 //	@Produce		json
 //	@Param			id	path		string	true	"Cluster ID"
-//	@Success		200	{array}		StorageGroupResponse
+//	@Success		200	{array}		types.StorageGroupResponse
 //	@Failure		403	{object}	map[string]string	"Forbidden - not cluster owner"
 //	@Failure		404	{object}	map[string]string	"Cluster not found"
 //	@Failure		500	{object}	map[string]string
@@ -207,7 +180,7 @@ func (h *StorageHandler) GetStorage(c echo.Context) error {
 	}
 
 	// Build response for each storage group
-	responses := []StorageGroupResponse{}
+	responses := []types.StorageGroupResponse{}
 	for _, link := range links {
 		storageGroup, err := h.store.StorageGroups.GetByID(c.Request().Context(), link.StorageGroupID)
 		if err != nil {
@@ -288,16 +261,16 @@ func (h *StorageHandler) UnlinkStorage(c echo.Context) error {
 }
 
 // buildStorageGroupResponse builds a complete storage group response with linked clusters
-func (h *StorageHandler) buildStorageGroupResponse(c echo.Context, storageGroup *types.StorageGroup) StorageGroupResponse {
+func (h *StorageHandler) buildStorageGroupResponse(c echo.Context, storageGroup *types.StorageGroup) types.StorageGroupResponse {
 	// Get all linked clusters
 	links, err := h.store.ClusterStorageLinks.GetByStorageGroupID(c.Request().Context(), storageGroup.ID)
 
-	linkedClusters := []ClusterStorageLinkResponse{}
+	linkedClusters := []types.ClusterStorageLinkResponse{}
 	if err == nil {
 		for _, link := range links {
 			cluster, err := h.store.Clusters.GetByID(c.Request().Context(), link.ClusterID)
 			if err == nil {
-				linkedClusters = append(linkedClusters, ClusterStorageLinkResponse{
+				linkedClusters = append(linkedClusters, types.ClusterStorageLinkResponse{
 					ClusterID:   link.ClusterID,
 					ClusterName: cluster.Name,
 					Role:        link.Role,
@@ -307,7 +280,7 @@ func (h *StorageHandler) buildStorageGroupResponse(c echo.Context, storageGroup
 		}
 	}
 
-	return StorageGroupResponse{
+	return types.StorageGroupResponse{
 		ID:                 storageGroup.ID,
 		Name:               storageGroup.Name,
 		EFSID:              storageGroup.EFSID,
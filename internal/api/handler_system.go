@@ -11,6 +11,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 // SystemHandler handles system/infrastructure API endpoints
@@ -27,58 +28,20 @@ func NewSystemHandler(s *store.Store, version string) *SystemHandler {
 	}
 }
 
-// WorkerInfo represents a worker instance
-type WorkerInfo struct {
-	InstanceID   string    `json:"instance_id"`
-	PrivateIP    string    `json:"private_ip"`
-	PublicIP     string    `json:"public_ip,omitempty"`
-	LaunchTime   time.Time `json:"launch_time"`
-	State        string    `json:"state"`
-	HealthStatus string    `json:"health_status"`
-	Type         string    `json:"type"` // "static" or "autoscale"
-	Version      string    `json:"version,omitempty"`
-}
-
-// ASGInfo represents autoscaling group information
-type ASGInfo struct {
-	Name            string       `json:"name"`
-	DesiredCapacity int          `json:"desired_capacity"`
-	MinSize         int          `json:"min_size"`
-	MaxSize         int          `json:"max_size"`
-	Instances       []WorkerInfo `json:"instances"`
-}
-
-// InfrastructureInfo represents the overall infrastructure status
-type InfrastructureInfo struct {
-	APIServer struct {
-		IP      string `json:"ip"`
-		Version string `json:"version"`
-		Status  string `json:"status"`
-		Uptime  string `json:"uptime,omitempty"`
-	} `json:"api_server"`
-	Database struct {
-		Host   string `json:"host"`
-		Status string `json:"status"`
-	} `json:"database"`
-	StaticWorkers  []WorkerInfo `json:"static_workers"`
-	AutoscaleGroup *ASGInfo     `json:"autoscale_group,omitempty"`
-	Timestamp      time.Time    `json:"timestamp"`
-}
-
 // GetInfrastructure returns infrastructure status
 //
 //	@Summary		Get infrastructure status
 //	@Description	Returns information about API server, workers, and autoscaling groups
 //	@Tags			system
 //	@Produce		json
-//	@Success		200	{object}	InfrastructureInfo
+//	@Success		200	{object}	types.InfrastructureInfo
 //	@Failure		500	{object}	ErrorResponse
 //	@Security		BearerAuth
 //	@Router			/admin/system/infrastructure [get]
 func (h *SystemHandler) GetInfrastructure(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	info := InfrastructureInfo{
+	info := types.InfrastructureInfo{
 		Timestamp: time.Now(),
 	}
 
@@ -149,10 +112,10 @@ func (h *SystemHandler) getDatabaseInfo(ctx context.Context) (string, string) {
 }
 
 // getStaticWorkers returns info about static workers (running on API server)
-func (h *SystemHandler) getStaticWorkers(ctx context.Context) []WorkerInfo {
+func (h *SystemHandler) getStaticWorkers(ctx context.Context) []types.WorkerInfo {
 	// Non-nil so it marshals to [] (not null) when no static worker is active,
 	// e.g. while the worker is draining/restarting — the frontend spreads this.
-	workers := []WorkerInfo{}
+	workers := []types.WorkerInfo{}
 
 	// Check if worker service is running locally
 	cmd := exec.Command("systemctl", "is-active", "ocpctl-worker")
@@ -167,7 +130,7 @@ func (h *SystemHandler) getStaticWorkers(ctx context.Context) []WorkerInfo {
 		// Otherwise use a sentinel value (Unix epoch) to indicate "unknown"
 		launchTime := time.Unix(0, 0) // Will be handled specially in frontend
 
-		workers = append(workers, WorkerInfo{
+		workers = append(workers, types.WorkerInfo{
 			InstanceID:   "static-worker",
 			PrivateIP:    getAPIServerIP(),
 			Type:         "static",
@@ -182,7 +145,7 @@ func (h *SystemHandler) getStaticWorkers(ctx context.Context) []WorkerInfo {
 }
 
 // getAutoscaleWorkers queries AWS for ASG information
-func (h *SystemHandler) getAutoscaleWorkers(ctx context.Context) *ASGInfo {
+func (h *SystemHandler) getAutoscaleWorkers(ctx context.Context) *types.ASGInfo {
 	asgName := "ocpctl-worker-asg"
 
 	// Get ASG details
@@ -212,12 +175,12 @@ func (h *SystemHandler) getAutoscaleWorkers(ctx context.Context) *ASGInfo {
 		return nil
 	}
 
-	asgInfo := &ASGInfo{
+	asgInfo := &types.ASGInfo{
 		Name:            asgName,
 		DesiredCapacity: asgData.DesiredCapacity,
 		MinSize:         asgData.MinSize,
 		MaxSize:         asgData.MaxSize,
-		Instances:       []WorkerInfo{},
+		Instances:       []types.WorkerInfo{},
 	}
 
 	// Get instance details for each instance
@@ -235,7 +198,7 @@ func (h *SystemHandler) getAutoscaleWorkers(ctx context.Context) *ASGInfo {
 }
 
 // getInstanceDetails gets EC2 instance details
-func (h *SystemHandler) getInstanceDetails(instanceID string) *WorkerInfo {
+func (h *SystemHandler) getInstanceDetails(instanceID string) *types.WorkerInfo {
 	cmd := exec.Command("aws", "ec2", "describe-instances",
 		"--instance-ids", instanceID,
 		"--query", "Reservations[0].Instances[0].[PrivateIpAddress,PublicIpAddress,LaunchTime,State.Name]",
@@ -264,7 +227,7 @@ func (h *SystemHandler) getInstanceDetails(instanceID string) *WorkerInfo {
 		version = h.getWorkerVersion(privateIP + ":8081")
 	}
 
-	return &WorkerInfo{
+	return &types.WorkerInfo{
 		InstanceID: instanceID,
 		PrivateIP:  privateIP,
 		PublicIP:   publicIP,
@@ -356,14 +356,9 @@ func (h *TeamHandler) ListTeamMembers(c echo.Context) error {
 	}
 
 	// Enrich member data with user details
-	type MemberWithUser struct {
-		*types.UserTeamMembership
-		User *types.UserResponse `json:"user"`
-	}
-
-	enrichedMembers := make([]MemberWithUser, 0, len(members))
+	enrichedMembers := make([]types.TeamMember, 0, len(members))
 	for _, member := range members {
-		enriched := MemberWithUser{
+		enriched := types.TeamMember{
 			UserTeamMembership: member,
 		}
 		if user, ok := usersMap[member.UserID]; ok {
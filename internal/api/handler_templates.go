@@ -21,24 +21,6 @@ func NewTemplateHandler(s *store.Store) *TemplateHandler {
 	return &TemplateHandler{store: s}
 }
 
-// CreateTemplateRequest represents a request to create a template
-type CreateTemplateRequest struct {
-	Name        string                  `json:"name" validate:"required,min=3,max=100"`
-	Description string                  `json:"description" validate:"max=500"`
-	Config      *types.CustomPostConfig `json:"config" validate:"required"`
-	IsPublic    bool                    `json:"isPublic"`
-	Tags        []string                `json:"tags"`
-}
-
-// UpdateTemplateRequest represents a request to update a template
-type UpdateTemplateRequest struct {
-	Name        string                  `json:"name" validate:"required,min=3,max=100"`
-	Description string                  `json:"description" validate:"max=500"`
-	Config      *types.CustomPostConfig `json:"config" validate:"required"`
-	IsPublic    bool                    `json:"isPublic"`
-	Tags        []string                `json:"tags"`
-}
-
 // Create handles POST /api/v1/templates
 //
 //	@Summary		Create template
@@ -46,7 +28,7 @@ type UpdateTemplateRequest struct {
 //	@Tags			templates
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		CreateTemplateRequest	true	"Template configuration"
+//	@Param			request	body		types.CreateTemplateRequest	true	"Template configuration"
 //	@Success		201		{object}	types.PostConfigTemplate
 //	@Failure		400		{object}	ErrorResponse
 //	@Failure		401		{object}	ErrorResponse
@@ -55,7 +37,7 @@ type UpdateTemplateRequest struct {
 func (h *TemplateHandler) Create(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	var req CreateTemplateRequest
+	var req types.CreateTemplateRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
@@ -191,7 +173,7 @@ func (h *TemplateHandler) Get(c echo.Context) error {
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string					true	"Template ID"
-//	@Param			request	body		UpdateTemplateRequest	true	"Updated template"
+//	@Param			request	body		types.UpdateTemplateRequest	true	"Updated template"
 //	@Success		200		{object}	types.PostConfigTemplate
 //	@Failure		400		{object}	ErrorResponse
 //	@Failure		401		{object}	ErrorResponse
@@ -204,7 +186,7 @@ func (h *TemplateHandler) Update(c echo.Context) error {
 
 	id := c.Param("id")
 
-	var req UpdateTemplateRequest
+	var req types.UpdateTemplateRequest
 	if err := c.Bind(&req); err != nil {
 		return ErrorBadRequest(c, "Invalid request body")
 	}
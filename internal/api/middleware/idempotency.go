@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+const (
+	// HeaderIdempotencyKey is sent by clients that want a request to be safe to retry
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed marks a response replayed from an earlier request
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength bounds client-supplied keys
+	maxIdempotencyKeyLength = 255
+
+	// defaultReservationTTL is how long a request may run before a retry
+	// with its key may take the key over
+	defaultReservationTTL = 5 * time.Minute
+)
+
+// IdempotencyBackend persists idempotency keys and the responses recorded for them.
+// Implementations must be safe for concurrent use.
+type IdempotencyBackend interface {
+	// Reserve records a key before its request runs and reports whether it was
+	// new. A reservation without a response past its ReservedUntil is taken over.
+	Reserve(ctx context.Context, key types.IdempotencyKey) (bool, error)
+	// Get returns the entry for a key
+	Get(ctx context.Context, key string) (*types.IdempotencyKey, error)
+	// Store records the response for a reserved key
+	Store(ctx context.Context, key types.IdempotencyKey) error
+	// Delete releases a key so the request can be retried
+	Delete(ctx context.Context, key string) error
+}
+
+// IdempotencyConfig configures Idempotency
+type IdempotencyConfig struct {
+	Backend IdempotencyBackend
+	// TTL is how long a response is replayed for. Defaults to 24 hours.
+	TTL time.Duration
+	// ReservationTTL is how long a key is held for a request that has not
+	// responded yet, so a request lost to a crash or deploy does not block
+	// retries until the key expires. Defaults to 5 minutes.
+	ReservationTTL time.Duration
+	// Scope namespaces keys, typically by the authenticated principal, so
+	// clients cannot replay each other's responses
+	Scope func(c echo.Context) string
+}
+
+// Idempotency replays the recorded response when a request is retried with the
+// same Idempotency-Key header. Requests without the header pass through.
+//
+// A key reused with a different method, path or body is rejected with 422, and
+// a retry that arrives while the first request is still running gets 409.
+// Responses with a 5xx status are not recorded, and neither are requests that
+// panic, so those requests can be retried. A request that never finishes, for
+// example because the API crashed, holds its key for ReservationTTL.
+func Idempotency(config IdempotencyConfig) echo.MiddlewareFunc {
+	if config.TTL == 0 {
+		config.TTL = 24 * time.Hour
+	}
+	if config.ReservationTTL == 0 {
+		config.ReservationTTL = defaultReservationTTL
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			clientKey := c.Request().Header.Get(HeaderIdempotencyKey)
+			if clientKey == "" {
+				return next(c)
+			}
+			if len(clientKey) > maxIdempotencyKeyLength {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error":   "bad_request",
+					"message": "Idempotency-Key must be at most 255 characters",
+				})
+			}
+
+			req := c.Request()
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error":   "bad_request",
+					"message": "failed to read request body",
+				})
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := ""
+			if config.Scope != nil {
+				scope = config.Scope(c)
+			}
+			key := hashParts(scope, clientKey)
+			requestHash := hashParts(req.Method, req.URL.Path, string(body))
+
+			ctx := req.Context()
+			now := time.Now()
+			reservedUntil := now.Add(config.ReservationTTL)
+			reserved, err := config.Backend.Reserve(ctx, types.IdempotencyKey{
+				ID:            uuid.New().String(),
+				Key:           key,
+				RequestHash:   requestHash,
+				ExpiresAt:     now.Add(config.TTL),
+				ReservedUntil: &reservedUntil,
+			})
+			if err != nil {
+				// Fail open: the request is still handled, just without replay protection
+				log.Printf("Warning: idempotency key lookup failed: %v", err)
+				return next(c)
+			}
+
+			if !reserved {
+				return replay(c, config.Backend, key, requestHash)
+			}
+
+			release := func() {
+				if err := config.Backend.Delete(context.WithoutCancel(ctx), key); err != nil {
+					log.Printf("Warning: failed to release idempotency key: %v", err)
+				}
+			}
+			// Recover runs outside this middleware, so a panic is seen here
+			// first: release the key and let it propagate
+			defer func() {
+				if r := recover(); r != nil {
+					release()
+					panic(r)
+				}
+			}()
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			handlerErr := next(c)
+
+			status := c.Response().Status
+			if handlerErr != nil || status >= http.StatusInternalServerError || !recordable(recorder.body.Bytes()) {
+				release()
+				return handlerErr
+			}
+
+			var responseBody []byte
+			if recorder.body.Len() > 0 {
+				responseBody = recorder.body.Bytes()
+			}
+			if err := config.Backend.Store(context.WithoutCancel(ctx), types.IdempotencyKey{
+				Key:                key,
+				RequestHash:        requestHash,
+				ResponseStatusCode: &status,
+				ResponseBody:       responseBody,
+			}); err != nil {
+				log.Printf("Warning: failed to record idempotent response: %v", err)
+			}
+
+			return nil
+		}
+	}
+}
+
+// replay writes the recorded response for a key that was already used
+func replay(c echo.Context, backend IdempotencyBackend, key, requestHash string) error {
+	entry, err := backend.Get(c.Request().Context(), key)
+	if err != nil {
+		return inProgress(c)
+	}
+
+	if entry.RequestHash != requestHash {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error":   "unprocessable_entity",
+			"message": "Idempotency-Key was already used for a different request",
+		})
+	}
+
+	if entry.ResponseStatusCode == nil {
+		return inProgress(c)
+	}
+
+	c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	if len(entry.ResponseBody) == 0 {
+		return c.NoContent(*entry.ResponseStatusCode)
+	}
+	return c.JSONBlob(*entry.ResponseStatusCode, entry.ResponseBody)
+}
+
+// inProgress rejects a retry that arrived while the original request is still
+// running. Retry-After tells clients the conflict is temporary.
+func inProgress(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderRetryAfter, "1")
+	return c.JSON(http.StatusConflict, map[string]string{
+		"error":   "idempotency_key_in_use",
+		"message": "a request with this Idempotency-Key is in progress",
+	})
+}
+
+// recordable reports whether a response body can be stored for replay
+func recordable(body []byte) bool {
+	return len(body) == 0 || json.Valid(body)
+}
+
+func hashParts(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder copies the response body as it is written
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("response does not support hijacking")
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -8,6 +10,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 // newCtx builds an Echo context backed by httptest for exercising middleware.
@@ -119,6 +122,145 @@ func TestStrictRateLimit(t *testing.T) {
 	}
 }
 
+func TestRateLimitSetsHeaders(t *testing.T) {
+	handler := RateLimit(60, 2)(okHandler)
+
+	c1, rec1 := newCtx(http.MethodGet, "/")
+	if err := handler(c1); err != nil {
+		t.Fatalf("first request should pass, got %v", err)
+	}
+	if got := rec1.Header().Get(HeaderRateLimitLimit); got != "2" {
+		t.Errorf("RateLimit-Limit = %q, want %q", got, "2")
+	}
+	if got := rec1.Header().Get(HeaderRateLimitRemaining); got != "1" {
+		t.Errorf("RateLimit-Remaining = %q, want %q", got, "1")
+	}
+	// 2 requests at once, refilled at 1 per second
+	if got := rec1.Header().Get(HeaderRateLimitPolicy); got != "2;w=2" {
+		t.Errorf("RateLimit-Policy = %q, want %q", got, "2;w=2")
+	}
+
+	c2, _ := newCtx(http.MethodGet, "/")
+	if err := handler(c2); err != nil {
+		t.Fatalf("second request should pass, got %v", err)
+	}
+
+	c3, rec3 := newCtx(http.MethodGet, "/")
+	if err := handler(c3); err == nil {
+		t.Fatal("third request should be rate limited")
+	}
+	if got := rec3.Header().Get(HeaderRateLimitRemaining); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, want %q", got, "0")
+	}
+	if got := rec3.Header().Get(echo.HeaderRetryAfter); got == "" || got == "0" {
+		t.Errorf("expected positive Retry-After on 429, got %q", got)
+	}
+}
+
+func TestRateLimitWithConfigKeysOnPrincipal(t *testing.T) {
+	mw := RateLimitWithConfig(RateLimitConfig{
+		Limit: Limit{RequestsPerMinute: 60, Burst: 1},
+		Identify: func(c echo.Context) RateLimitPrincipal {
+			return RateLimitPrincipal{Key: "user:" + c.Request().Header.Get("X-User")}
+		},
+	})
+	handler := mw(okHandler)
+
+	request := func(user string) error {
+		c, _ := newCtx(http.MethodGet, "/")
+		c.Request().Header.Set("X-User", user)
+		return handler(c)
+	}
+
+	// Both requests share an IP, but different principals get separate buckets
+	if err := request("alice"); err != nil {
+		t.Fatalf("alice should pass, got %v", err)
+	}
+	if err := request("bob"); err != nil {
+		t.Fatalf("bob should pass, got %v", err)
+	}
+	if err := request("alice"); err == nil {
+		t.Error("alice's second request should be rate limited")
+	}
+}
+
+func TestRateLimitWithConfigPolicyOverride(t *testing.T) {
+	mw := RateLimitWithConfig(RateLimitConfig{
+		Limit: Limit{RequestsPerMinute: 60, Burst: 1},
+		Policy: func(_ context.Context, p RateLimitPrincipal) (Limit, bool) {
+			return Limit{RequestsPerMinute: 600, Burst: 3}, true
+		},
+	})
+	handler := mw(okHandler)
+
+	for i := 0; i < 3; i++ {
+		c, _ := newCtx(http.MethodGet, "/")
+		if err := handler(c); err != nil {
+			t.Fatalf("request %d should pass under the override, got %v", i, err)
+		}
+	}
+	c, _ := newCtx(http.MethodGet, "/")
+	if err := handler(c); err == nil {
+		t.Error("expected override burst to be enforced")
+	}
+}
+
+// fakeCounter is an in-memory RateLimitCounter for exercising the Postgres backend
+type fakeCounter struct {
+	counts map[string]int
+	err    error
+}
+
+func (f *fakeCounter) IncrementCounter(_ context.Context, key string, windowStart time.Time) (int, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	k := key + "@" + windowStart.String()
+	f.counts[k]++
+	return f.counts[k], nil
+}
+
+func (f *fakeCounter) DeleteCountersBefore(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestPostgresRateLimitBackend(t *testing.T) {
+	backend := &PostgresRateLimitBackend{counter: &fakeCounter{counts: map[string]int{}}, window: time.Minute}
+	limit := Limit{RequestsPerMinute: 2, Burst: 1}
+
+	for i := 0; i < 2; i++ {
+		res, err := backend.Allow(context.Background(), "k", limit)
+		if err != nil || !res.Allowed {
+			t.Fatalf("request %d should be allowed, got %+v err=%v", i, res, err)
+		}
+	}
+	res, err := backend.Allow(context.Background(), "k", limit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Allowed || res.Remaining != 0 || res.Limit != 2 || res.Window != time.Minute {
+		t.Errorf("third request should be denied with nothing remaining, got %+v", res)
+	}
+	if res.Reset <= 0 || res.Reset > time.Minute {
+		t.Errorf("reset should fall within the window, got %v", res.Reset)
+	}
+}
+
+func TestRateLimitFailsOpenOnBackendError(t *testing.T) {
+	backend := &PostgresRateLimitBackend{counter: &fakeCounter{err: errors.New("db down")}, window: time.Minute}
+	handler := RateLimitWithConfig(RateLimitConfig{
+		Backend: backend,
+		Limit:   Limit{RequestsPerMinute: 1, Burst: 1},
+	})(okHandler)
+
+	for i := 0; i < 3; i++ {
+		c, _ := newCtx(http.MethodGet, "/")
+		if err := handler(c); err != nil {
+			t.Fatalf("request %d should pass when the backend fails, got %v", i, err)
+		}
+	}
+}
+
 func TestPrometheusMetrics(t *testing.T) {
 	// Normal request path records metrics and passes through.
 	c, _ := newCtx(http.MethodGet, "/api/v1/clusters")
@@ -155,3 +297,178 @@ func TestLogger(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+// memoryIdempotencyBackend is an in-memory IdempotencyBackend for tests.
+type memoryIdempotencyBackend struct {
+	keys map[string]types.IdempotencyKey
+}
+
+func (b *memoryIdempotencyBackend) Reserve(_ context.Context, key types.IdempotencyKey) (bool, error) {
+	if existing, ok := b.keys[key.Key]; ok {
+		stale := existing.ResponseStatusCode == nil && existing.ReservedUntil != nil && !existing.ReservedUntil.After(time.Now())
+		if !stale {
+			return false, nil
+		}
+	}
+	b.keys[key.Key] = key
+	return true, nil
+}
+
+func (b *memoryIdempotencyBackend) Get(_ context.Context, key string) (*types.IdempotencyKey, error) {
+	k, ok := b.keys[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &k, nil
+}
+
+func (b *memoryIdempotencyBackend) Store(_ context.Context, key types.IdempotencyKey) error {
+	existing := b.keys[key.Key]
+	existing.ResponseStatusCode = key.ResponseStatusCode
+	existing.ResponseBody = key.ResponseBody
+	b.keys[key.Key] = existing
+	return nil
+}
+
+func (b *memoryIdempotencyBackend) Delete(_ context.Context, key string) error {
+	delete(b.keys, key)
+	return nil
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	backend := &memoryIdempotencyBackend{keys: map[string]types.IdempotencyKey{}}
+	calls := 0
+	handler := Idempotency(IdempotencyConfig{
+		Backend: backend,
+		Scope:   func(c echo.Context) string { return c.Request().Header.Get("X-User") },
+	})(func(c echo.Context) error {
+		calls++
+		return c.JSON(http.StatusCreated, map[string]int{"call": calls})
+	})
+
+	request := func(user, key, body string) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/clusters", strings.NewReader(body))
+		req.Header.Set("X-User", user)
+		req.Header.Set(HeaderIdempotencyKey, key)
+		rec := httptest.NewRecorder()
+		if err := handler(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return rec
+	}
+
+	first := request("alice", "k1", `{"name":"a"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first request: expected 201, got %d", first.Code)
+	}
+
+	retry := request("alice", "k1", `{"name":"a"}`)
+	if retry.Code != http.StatusCreated || retry.Header().Get(HeaderIdempotentReplayed) != "true" {
+		t.Fatalf("retry should be replayed, got %d %q", retry.Code, retry.Header().Get(HeaderIdempotentReplayed))
+	}
+	if strings.TrimSpace(retry.Body.String()) != strings.TrimSpace(first.Body.String()) {
+		t.Errorf("replayed body %q, want %q", retry.Body.String(), first.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+
+	if rec := request("alice", "k1", `{"name":"b"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reused key with different body: expected 422, got %d", rec.Code)
+	}
+
+	// Keys are scoped, so another user's identical key runs the handler
+	if rec := request("bob", "k1", `{"name":"a"}`); rec.Code != http.StatusCreated || calls != 2 {
+		t.Errorf("other user's key should not replay, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnServerError(t *testing.T) {
+	backend := &memoryIdempotencyBackend{keys: map[string]types.IdempotencyKey{}}
+	fail := true
+	handler := Idempotency(IdempotencyConfig{Backend: backend})(func(c echo.Context) error {
+		if fail {
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "unavailable"})
+		}
+		return c.JSON(http.StatusOK, map[string]string{"ok": "true"})
+	})
+
+	request := func() int {
+		c, rec := newCtx(http.MethodPost, "/")
+		c.Request().Header.Set(HeaderIdempotencyKey, "k1")
+		if err := handler(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return rec.Code
+	}
+
+	if code := request(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", code)
+	}
+	if len(backend.keys) != 0 {
+		t.Fatal("key should be released after a 5xx response")
+	}
+
+	fail = false
+	if code := request(); code != http.StatusOK {
+		t.Errorf("retry after 5xx should run the handler, got %d", code)
+	}
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	backend := &memoryIdempotencyBackend{keys: map[string]types.IdempotencyKey{}}
+	handler := Idempotency(IdempotencyConfig{Backend: backend})(okHandler)
+
+	c, _ := newCtx(http.MethodPost, "/")
+	c.Request().Header.Set(HeaderIdempotencyKey, "k1")
+	key := hashParts("", "k1")
+	backend.keys[key] = types.IdempotencyKey{Key: key, RequestHash: hashParts(http.MethodPost, "/", "")}
+
+	if err := handler(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Response().Status != http.StatusConflict {
+		t.Errorf("expected 409 while the first request runs, got %d", c.Response().Status)
+	}
+}
+
+func TestIdempotencyReleasesKeyOnPanic(t *testing.T) {
+	backend := &memoryIdempotencyBackend{keys: map[string]types.IdempotencyKey{}}
+	handler := Idempotency(IdempotencyConfig{Backend: backend})(func(c echo.Context) error {
+		panic("boom")
+	})
+
+	c, _ := newCtx(http.MethodPost, "/")
+	c.Request().Header.Set(HeaderIdempotencyKey, "k1")
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("expected the panic to propagate, got %v", r)
+			}
+		}()
+		_ = handler(c)
+	}()
+
+	if len(backend.keys) != 0 {
+		t.Error("key should be released after a panic")
+	}
+}
+
+func TestIdempotencyTakesOverStaleReservation(t *testing.T) {
+	backend := &memoryIdempotencyBackend{keys: map[string]types.IdempotencyKey{}}
+	handler := Idempotency(IdempotencyConfig{Backend: backend})(okHandler)
+
+	c, _ := newCtx(http.MethodPost, "/")
+	c.Request().Header.Set(HeaderIdempotencyKey, "k1")
+	key := hashParts("", "k1")
+	lapsed := time.Now().Add(-time.Second)
+	backend.keys[key] = types.IdempotencyKey{Key: key, RequestHash: hashParts(http.MethodPost, "/", ""), ReservedUntil: &lapsed}
+
+	if err := handler(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Response().Status != http.StatusOK {
+		t.Errorf("expected a lapsed reservation to be taken over, got %d", c.Response().Status)
+	}
+}
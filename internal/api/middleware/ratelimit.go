@@ -1,21 +1,91 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/internal/metrics"
 	"golang.org/x/time/rate"
 )
 
+// Standard rate limit response headers (IETF draft-ietf-httpapi-ratelimit-headers)
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// RateLimitHeaders lists the rate limit headers so they can be exposed via CORS
+var RateLimitHeaders = []string{
+	HeaderRateLimitLimit,
+	HeaderRateLimitRemaining,
+	HeaderRateLimitReset,
+	HeaderRateLimitPolicy,
+	echo.HeaderRetryAfter,
+}
+
+// Limit describes the rate applied to a single bucket
+type Limit struct {
+	RequestsPerMinute int
+	Burst             int
+}
+
+// RateLimitResult is the outcome of a single rate limit check
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int           // Requests permitted per window
+	Window    time.Duration // Period in which Limit requests are permitted
+	Remaining int           // Requests left before the bucket is exhausted
+	Reset     time.Duration // Time until the bucket has capacity again
+}
+
+// RateLimitBackend tracks request counts for rate limit buckets.
+// Implementations must be safe for concurrent use.
+type RateLimitBackend interface {
+	Allow(ctx context.Context, key string, limit Limit) (RateLimitResult, error)
+}
+
+// RateLimitPrincipal identifies who a request is counted against
+type RateLimitPrincipal struct {
+	Key      string // Bucket identity, e.g. "user:<id>", "apikey:<id>" or "ip:<addr>"
+	UserID   string
+	Role     string
+	APIKeyID string
+}
+
+// RateLimitConfig configures RateLimitWithConfig
+type RateLimitConfig struct {
+	// Backend stores bucket state. Defaults to a new in-memory store.
+	Backend RateLimitBackend
+	// Scope namespaces bucket keys. Limiters sharing a scope share buckets.
+	// Defaults to the request method and route path.
+	Scope string
+	// Limit is applied when Policy returns no override
+	Limit Limit
+	// Identify resolves the principal for a request. Defaults to the client IP.
+	Identify func(c echo.Context) RateLimitPrincipal
+	// Policy optionally overrides Limit for a principal
+	Policy func(ctx context.Context, p RateLimitPrincipal) (Limit, bool)
+	// ErrorMessage is returned with 429 responses
+	ErrorMessage string
+}
+
 // rateLimiterEntry holds a rate limiter and its last access time
 type rateLimiterEntry struct {
 	limiter    *rate.Limiter
 	lastAccess time.Time
 }
 
-// RateLimiterStore holds rate limiters per IP with last access tracking
+// RateLimiterStore is the in-memory RateLimitBackend. It holds a token bucket
+// per key with last access tracking. Buckets are local to one API replica.
 type RateLimiterStore struct {
 	limiters map[string]*rateLimiterEntry
 	mu       sync.RWMutex
@@ -25,11 +95,12 @@ type RateLimiterStore struct {
 	maxAge   time.Duration // How long to keep inactive limiters
 }
 
-// NewRateLimiterStore creates a new rate limiter store
+// NewRateLimiterStore creates a new rate limiter store. The rate and burst are
+// used for keys looked up without an explicit Limit.
 func NewRateLimiterStore(requestsPerMinute int, burst int) *RateLimiterStore {
 	store := &RateLimiterStore{
 		limiters: make(map[string]*rateLimiterEntry),
-		rate:     rate.Limit(float64(requestsPerMinute) / 60.0), // Convert to per-second
+		rate:     perMinute(requestsPerMinute),
 		burst:    burst,
 		cleanup:  5 * time.Minute,  // Run cleanup every 5 minutes
 		maxAge:   30 * time.Minute, // Remove limiters inactive for 30 minutes
@@ -41,27 +112,81 @@ func NewRateLimiterStore(requestsPerMinute int, burst int) *RateLimiterStore {
 	return store
 }
 
-// getLimiter returns a rate limiter for the given IP and updates last access time
-func (s *RateLimiterStore) getLimiter(ip string) *rate.Limiter {
+// perMinute converts a per-minute request count to a per-second rate
+func perMinute(requestsPerMinute int) rate.Limit {
+	return rate.Limit(float64(requestsPerMinute) / 60.0)
+}
+
+// getLimiter returns the rate limiter for the given key using the store defaults
+func (s *RateLimiterStore) getLimiter(key string) *rate.Limiter {
+	return s.limiterFor(key, s.rate, s.burst)
+}
+
+// limiterFor returns the rate limiter for key and updates its last access time.
+// An existing limiter is updated in place if the configured limit changed.
+func (s *RateLimiterStore) limiterFor(key string, r rate.Limit, burst int) *rate.Limiter {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	entry, exists := s.limiters[ip]
+	entry, exists := s.limiters[key]
 	if !exists {
 		// Create new limiter entry
 		entry = &rateLimiterEntry{
-			limiter:    rate.NewLimiter(s.rate, s.burst),
+			limiter:    rate.NewLimiter(r, burst),
 			lastAccess: time.Now(),
 		}
-		s.limiters[ip] = entry
-	} else {
-		// Update last access time
-		entry.lastAccess = time.Now()
+		s.limiters[key] = entry
+		return entry.limiter
+	}
+
+	// Update last access time
+	entry.lastAccess = time.Now()
+	if entry.limiter.Limit() != r {
+		entry.limiter.SetLimit(r)
+	}
+	if entry.limiter.Burst() != burst {
+		entry.limiter.SetBurst(burst)
 	}
 
 	return entry.limiter
 }
 
+// Allow implements RateLimitBackend using a token bucket per key
+func (s *RateLimiterStore) Allow(_ context.Context, key string, limit Limit) (RateLimitResult, error) {
+	r := perMinute(limit.RequestsPerMinute)
+	limiter := s.limiterFor(key, r, limit.Burst)
+
+	now := time.Now()
+	allowed := limiter.AllowN(now, 1)
+	tokens := limiter.TokensAt(now)
+
+	// The bucket admits Burst requests at once, then refills at the per-minute
+	// rate, so the quota is Burst requests per time taken to refill it
+	result := RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Window:    time.Minute,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+	}
+	if limit.RequestsPerMinute > 0 {
+		result.Window = time.Duration(limit.Burst) * time.Minute / time.Duration(limit.RequestsPerMinute)
+	}
+
+	// Reset is the time until one token is available when exhausted,
+	// otherwise the time until the bucket is full again
+	if r > 0 {
+		target := float64(limit.Burst)
+		if result.Remaining == 0 {
+			target = 1
+		}
+		if missing := target - tokens; missing > 0 {
+			result.Reset = time.Duration(missing / float64(r) * float64(time.Second))
+		}
+	}
+
+	return result, nil
+}
+
 // cleanupRoutine periodically removes inactive limiters
 // Only removes limiters that haven't been accessed in maxAge duration
 func (s *RateLimiterStore) cleanupRoutine() {
@@ -71,24 +196,92 @@ func (s *RateLimiterStore) cleanupRoutine() {
 	for range ticker.C {
 		s.mu.Lock()
 		now := time.Now()
-		removed := 0
 
 		// Remove entries not accessed within maxAge
-		for ip, entry := range s.limiters {
+		for key, entry := range s.limiters {
 			if now.Sub(entry.lastAccess) > s.maxAge {
-				delete(s.limiters, ip)
-				removed++
+				delete(s.limiters, key)
 			}
 		}
 
 		s.mu.Unlock()
+	}
+}
+
+// RateLimitCounter is the storage needed by PostgresRateLimitBackend.
+// It is implemented by store.RateLimitStore.
+type RateLimitCounter interface {
+	IncrementCounter(ctx context.Context, key string, windowStart time.Time) (int, error)
+	DeleteCountersBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PostgresRateLimitBackend is a RateLimitBackend that counts requests in
+// fixed one-minute windows stored in the database, so every API replica
+// shares the same buckets. Burst is not used: a fixed window already admits
+// up to RequestsPerMinute requests at once.
+type PostgresRateLimitBackend struct {
+	counter RateLimitCounter
+	window  time.Duration
+}
+
+// NewPostgresRateLimitBackend creates a database-backed rate limit backend and
+// starts a goroutine that removes expired counters
+func NewPostgresRateLimitBackend(counter RateLimitCounter) *PostgresRateLimitBackend {
+	b := &PostgresRateLimitBackend{
+		counter: counter,
+		window:  time.Minute,
+	}
+
+	go b.cleanupRoutine()
+
+	return b
+}
+
+// Allow implements RateLimitBackend
+func (b *PostgresRateLimitBackend) Allow(ctx context.Context, key string, limit Limit) (RateLimitResult, error) {
+	now := time.Now()
+	windowStart := now.Truncate(b.window)
+
+	count, err := b.counter.IncrementCounter(ctx, key, windowStart)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	remaining := limit.RequestsPerMinute - count
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return RateLimitResult{
+		Allowed:   count <= limit.RequestsPerMinute,
+		Limit:     limit.RequestsPerMinute,
+		Window:    b.window,
+		Remaining: remaining,
+		Reset:     windowStart.Add(b.window).Sub(now),
+	}, nil
+}
 
-		// Log cleanup stats if any limiters were removed
-		if removed > 0 {
-			// Don't use log.Printf here to avoid spamming logs
-			// Could add metrics instead
+// cleanupRoutine periodically deletes counters from previous windows
+func (b *PostgresRateLimitBackend) cleanupRoutine() {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if _, err := b.counter.DeleteCountersBefore(ctx, time.Now().Add(-2*b.window)); err != nil {
+			log.Printf("Warning: failed to delete expired rate limit counters: %v", err)
 		}
+		cancel()
+	}
+}
+
+// IdentifyByIP keys requests on the client IP address
+func IdentifyByIP(c echo.Context) RateLimitPrincipal {
+	ip := c.RealIP()
+	if ip == "" {
+		ip = c.Request().RemoteAddr
 	}
+	return RateLimitPrincipal{Key: "ip:" + ip}
 }
 
 // RateLimit returns a rate limiting middleware
@@ -98,24 +291,64 @@ func RateLimit(requestsPerMinute int, burst int) echo.MiddlewareFunc {
 
 // RateLimitWithMessage returns a rate limiting middleware with a custom error message
 func RateLimitWithMessage(requestsPerMinute int, burst int, errorMessage string) echo.MiddlewareFunc {
-	store := NewRateLimiterStore(requestsPerMinute, burst)
+	return RateLimitWithConfig(RateLimitConfig{
+		Limit:        Limit{RequestsPerMinute: requestsPerMinute, Burst: burst},
+		ErrorMessage: errorMessage,
+	})
+}
+
+// RateLimitWithConfig returns a rate limiting middleware using the given backend,
+// principal resolution and policy overrides. Every response carries the
+// RateLimit-* headers. If the backend fails the request is allowed so that a
+// database outage does not take the whole API down.
+func RateLimitWithConfig(config RateLimitConfig) echo.MiddlewareFunc {
+	if config.Backend == nil {
+		config.Backend = NewRateLimiterStore(config.Limit.RequestsPerMinute, config.Limit.Burst)
+	}
+	if config.Identify == nil {
+		config.Identify = IdentifyByIP
+	}
+	if config.ErrorMessage == "" {
+		config.ErrorMessage = "rate limit exceeded, please try again later"
+	}
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			// Get client IP
-			ip := c.RealIP()
-			if ip == "" {
-				ip = c.Request().RemoteAddr
+			ctx := c.Request().Context()
+			principal := config.Identify(c)
+
+			limit := config.Limit
+			if config.Policy != nil {
+				if override, ok := config.Policy(ctx, principal); ok {
+					limit = override
+				}
+			}
+
+			scope := config.Scope
+			if scope == "" {
+				scope = c.Request().Method + " " + c.Path()
+			}
+
+			result, err := config.Backend.Allow(ctx, scope+"|"+principal.Key, limit)
+			if err != nil {
+				log.Printf("Warning: rate limit backend error (allowing request): %v", err)
+				return next(c)
 			}
 
-			// Get rate limiter for this IP
-			limiter := store.getLimiter(ip)
+			resetSeconds := int(math.Ceil(result.Reset.Seconds()))
+			header := c.Response().Header()
+			header.Set(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+			header.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+			header.Set(HeaderRateLimitReset, strconv.Itoa(resetSeconds))
+			header.Set(HeaderRateLimitPolicy, fmt.Sprintf("%d;w=%d", result.Limit, max(int(math.Ceil(result.Window.Seconds())), 1)))
 
 			// Check if request is allowed
-			if !limiter.Allow() {
+			if !result.Allowed {
+				metrics.RateLimitHitsTotal.WithLabelValues(c.Path()).Inc()
+				header.Set(echo.HeaderRetryAfter, strconv.Itoa(max(resetSeconds, 1)))
 				return echo.NewHTTPError(
 					http.StatusTooManyRequests,
-					errorMessage,
+					config.ErrorMessage,
 				)
 			}
 
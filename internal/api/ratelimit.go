@@ -0,0 +1,278 @@
+package api
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	apimiddleware "github.com/tsanders-rh/ocpctl/internal/api/middleware"
+	"github.com/tsanders-rh/ocpctl/internal/auth"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+const (
+	// RateLimitBackendMemory keeps rate limit buckets in each API replica's memory
+	RateLimitBackendMemory = "memory"
+	// RateLimitBackendPostgres shares rate limit buckets across replicas via the database
+	RateLimitBackendPostgres = "postgres"
+
+	// rateLimitPolicyTTL is how long policies are cached before being reloaded,
+	// which bounds how quickly an admin change reaches other replicas
+	rateLimitPolicyTTL = 30 * time.Second
+	// rateLimitAPIKeyTTL is how long an API key lookup is cached
+	rateLimitAPIKeyTTL = time.Minute
+	// rateLimitAPIKeyCacheSize bounds the number of valid API keys cached
+	rateLimitAPIKeyCacheSize = 10000
+	// rateLimitInvalidKeyCacheSize bounds the number of failed lookups cached.
+	// It only spares the database a client retrying a revoked or mistyped key;
+	// random keys from unauthenticated clients churn it rather than grow it.
+	rateLimitInvalidKeyCacheSize = 1000
+)
+
+// rateLimitAPIKeyEntry caches the principal behind an API key hash.
+// A nil principal records a failed lookup.
+type rateLimitAPIKeyEntry struct {
+	principal *apimiddleware.RateLimitPrincipal
+	expiresAt time.Time
+}
+
+// apiKeyCache is a fixed-capacity cache of API key lookups. When it is full,
+// expired entries are swept and, if none have expired, the entry closest to
+// expiry is evicted. It is not safe for concurrent use.
+type apiKeyCache struct {
+	capacity int
+	entries  map[string]rateLimitAPIKeyEntry
+}
+
+func newAPIKeyCache(capacity int) *apiKeyCache {
+	return &apiKeyCache{capacity: capacity, entries: make(map[string]rateLimitAPIKeyEntry)}
+}
+
+// get returns the unexpired entry for a key hash
+func (c *apiKeyCache) get(keyHash string, now time.Time) (rateLimitAPIKeyEntry, bool) {
+	entry, ok := c.entries[keyHash]
+	if !ok || !now.Before(entry.expiresAt) {
+		return rateLimitAPIKeyEntry{}, false
+	}
+	return entry, true
+}
+
+// put stores an entry, making room for it if the cache is full
+func (c *apiKeyCache) put(keyHash string, entry rateLimitAPIKeyEntry, now time.Time) {
+	if _, ok := c.entries[keyHash]; !ok && len(c.entries) >= c.capacity {
+		oldest := ""
+		for hash, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, hash)
+			} else if oldest == "" || e.expiresAt.Before(c.entries[oldest].expiresAt) {
+				oldest = hash
+			}
+		}
+		if len(c.entries) >= c.capacity {
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[keyHash] = entry
+}
+
+// RateLimitResolver identifies the principal behind a request and looks up
+// admin-configured rate limit policies for it. Requests are keyed on the
+// authenticated user or API key and fall back to the client IP for anonymous
+// and IAM-signed requests.
+type RateLimitResolver struct {
+	store *store.Store
+	auth  *auth.Auth
+
+	mu             sync.RWMutex
+	policies       map[string]apimiddleware.Limit // "<scope>:<subject>" -> limit
+	policiesLoaded time.Time
+	apiKeys        *apiKeyCache // key hash -> principal
+	invalidKeys    *apiKeyCache // key hash -> nil principal
+}
+
+// NewRateLimitResolver creates a new rate limit resolver
+func NewRateLimitResolver(st *store.Store, authService *auth.Auth) *RateLimitResolver {
+	return &RateLimitResolver{
+		store:       st,
+		auth:        authService,
+		apiKeys:     newAPIKeyCache(rateLimitAPIKeyCacheSize),
+		invalidKeys: newAPIKeyCache(rateLimitInvalidKeyCacheSize),
+	}
+}
+
+// Identify resolves the rate limit principal for a request
+func (r *RateLimitResolver) Identify(c echo.Context) apimiddleware.RateLimitPrincipal {
+	req := c.Request()
+
+	// SigV4 signatures are not verified here, so the access key in the header
+	// cannot be trusted as an identity
+	if auth.IsIAMRequest(req) {
+		return apimiddleware.IdentifyByIP(c)
+	}
+
+	parts := strings.SplitN(req.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return apimiddleware.IdentifyByIP(c)
+	}
+	token := parts[1]
+
+	if auth.IsAPIKey(token) {
+		if principal := r.lookupAPIKey(req.Context(), token); principal != nil {
+			return *principal
+		}
+		return apimiddleware.IdentifyByIP(c)
+	}
+
+	if r.auth != nil {
+		if claims, err := r.auth.ValidateAccessToken(token); err == nil {
+			return apimiddleware.RateLimitPrincipal{
+				Key:    "user:" + claims.UserID,
+				UserID: claims.UserID,
+				Role:   claims.Role,
+			}
+		}
+	}
+
+	return apimiddleware.IdentifyByIP(c)
+}
+
+// lookupAPIKey returns the principal for an API key, or nil if the key is invalid
+func (r *RateLimitResolver) lookupAPIKey(ctx context.Context, plainKey string) *apimiddleware.RateLimitPrincipal {
+	keyHash := auth.HashAPIKey(plainKey)
+
+	now := time.Now()
+	r.mu.RLock()
+	entry, ok := r.apiKeys.get(keyHash, now)
+	if !ok {
+		entry, ok = r.invalidKeys.get(keyHash, now)
+	}
+	r.mu.RUnlock()
+	if ok {
+		return entry.principal
+	}
+
+	var principal *apimiddleware.RateLimitPrincipal
+	if apiKey, err := r.store.APIKeys.GetByKeyHash(ctx, keyHash); err == nil {
+		principal = &apimiddleware.RateLimitPrincipal{
+			Key:      "apikey:" + apiKey.ID,
+			UserID:   apiKey.UserID,
+			APIKeyID: apiKey.ID,
+		}
+		if user, err := r.store.Users.GetByID(ctx, apiKey.UserID); err == nil {
+			principal.Role = string(user.Role)
+		}
+	}
+
+	cache := r.apiKeys
+	if principal == nil {
+		cache = r.invalidKeys
+	}
+	now = time.Now()
+	r.mu.Lock()
+	cache.put(keyHash, rateLimitAPIKeyEntry{principal: principal, expiresAt: now.Add(rateLimitAPIKeyTTL)}, now)
+	r.mu.Unlock()
+
+	return principal
+}
+
+// Policy returns the most specific policy for a principal: API key, then user, then role
+func (r *RateLimitResolver) Policy(ctx context.Context, p apimiddleware.RateLimitPrincipal) (apimiddleware.Limit, bool) {
+	policies := r.loadPolicies(ctx)
+
+	candidates := []string{
+		policyKey(types.RateLimitScopeAPIKey, p.APIKeyID),
+		policyKey(types.RateLimitScopeUser, p.UserID),
+		policyKey(types.RateLimitScopeRole, p.Role),
+	}
+	for _, key := range candidates {
+		if key == "" {
+			continue
+		}
+		if limit, ok := policies[key]; ok {
+			return limit, true
+		}
+	}
+
+	return apimiddleware.Limit{}, false
+}
+
+// Invalidate drops cached policies so the next request reloads them
+func (r *RateLimitResolver) Invalidate() {
+	r.mu.Lock()
+	r.policiesLoaded = time.Time{}
+	r.mu.Unlock()
+}
+
+// loadPolicies returns the cached policies, reloading them once the TTL has passed.
+// On a load failure the previous policies are kept.
+func (r *RateLimitResolver) loadPolicies(ctx context.Context) map[string]apimiddleware.Limit {
+	r.mu.RLock()
+	policies, loaded := r.policies, r.policiesLoaded
+	r.mu.RUnlock()
+
+	if time.Since(loaded) < rateLimitPolicyTTL {
+		return policies
+	}
+
+	list, err := r.store.RateLimits.ListPolicies(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Retry on the next TTL either way so a failing database isn't queried per request
+	r.policiesLoaded = time.Now()
+	if err != nil {
+		log.Printf("Warning: failed to load rate limit policies (keeping %d cached): %v", len(r.policies), err)
+		return r.policies
+	}
+
+	r.policies = make(map[string]apimiddleware.Limit, len(list))
+	for _, p := range list {
+		r.policies[policyKey(p.Scope, p.Subject)] = apimiddleware.Limit{
+			RequestsPerMinute: p.RequestsPerMinute,
+			Burst:             p.Burst,
+		}
+	}
+
+	return r.policies
+}
+
+// policyKey builds the cache key for a policy, or "" if the subject is empty
+func policyKey(scope types.RateLimitScope, subject string) string {
+	if subject == "" {
+		return ""
+	}
+	return string(scope) + ":" + subject
+}
+
+// rateLimit returns the global rate limit middleware. Admin policies override
+// the default limit for matching principals.
+func (s *Server) rateLimit(requestsPerMinute, burst int) echo.MiddlewareFunc {
+	return apimiddleware.RateLimitWithConfig(apimiddleware.RateLimitConfig{
+		Backend:  s.rateLimitBackend,
+		Scope:    "global",
+		Limit:    apimiddleware.Limit{RequestsPerMinute: requestsPerMinute, Burst: burst},
+		Identify: s.rateLimits.Identify,
+		Policy:   s.rateLimits.Policy,
+	})
+}
+
+// strictRateLimit returns a fixed per-route rate limit for sensitive endpoints.
+// Admin policies do not apply.
+func (s *Server) strictRateLimit(requestsPerMinute int) echo.MiddlewareFunc {
+	return s.strictRateLimitWithMessage(requestsPerMinute, "")
+}
+
+// strictRateLimitWithMessage is strictRateLimit with a custom error message
+func (s *Server) strictRateLimitWithMessage(requestsPerMinute int, errorMessage string) echo.MiddlewareFunc {
+	return apimiddleware.RateLimitWithConfig(apimiddleware.RateLimitConfig{
+		Backend:      s.rateLimitBackend,
+		Limit:        apimiddleware.Limit{RequestsPerMinute: requestsPerMinute, Burst: 1},
+		Identify:     s.rateLimits.Identify,
+		ErrorMessage: errorMessage,
+	})
+}
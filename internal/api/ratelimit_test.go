@@ -0,0 +1,60 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	apimiddleware "github.com/tsanders-rh/ocpctl/internal/api/middleware"
+)
+
+func TestAPIKeyCacheIsBounded(t *testing.T) {
+	now := time.Now()
+	cache := newAPIKeyCache(3)
+	entry := func(ttl time.Duration) rateLimitAPIKeyEntry {
+		return rateLimitAPIKeyEntry{principal: &apimiddleware.RateLimitPrincipal{}, expiresAt: now.Add(ttl)}
+	}
+
+	cache.put("a", entry(time.Minute), now)
+	cache.put("b", entry(30*time.Second), now)
+	cache.put("c", entry(2*time.Minute), now)
+
+	// Full with nothing expired: the entry closest to expiry makes room
+	cache.put("d", entry(time.Minute), now)
+	if len(cache.entries) != 3 {
+		t.Fatalf("cache has %d entries, want 3", len(cache.entries))
+	}
+	if _, ok := cache.get("b", now); ok {
+		t.Error("entry closest to expiry should have been evicted")
+	}
+	for _, hash := range []string{"a", "c", "d"} {
+		if _, ok := cache.get(hash, now); !ok {
+			t.Errorf("entry %s should still be cached", hash)
+		}
+	}
+
+	// Expired entries are not returned and are swept once the cache is full
+	later := now.Add(90 * time.Second)
+	if _, ok := cache.get("a", later); ok {
+		t.Error("expired entry should not be returned")
+	}
+	cache.put("e", entry(5*time.Minute), later)
+	if len(cache.entries) != 2 {
+		t.Errorf("expired entries should be swept, got %d entries", len(cache.entries))
+	}
+
+	// Replacing a cached key never evicts another
+	cache.put("c", entry(time.Hour), later)
+	cache.put("f", entry(time.Hour), later)
+	cache.put("f", entry(time.Hour), later)
+	if len(cache.entries) != 3 {
+		t.Errorf("cache has %d entries, want 3", len(cache.entries))
+	}
+
+	for i := 0; i < 100; i++ {
+		cache.put(fmt.Sprintf("random-%d", i), entry(time.Minute), later)
+	}
+	if len(cache.entries) > 3 {
+		t.Errorf("cache grew to %d entries past its capacity", len(cache.entries))
+	}
+}
@@ -5,23 +5,16 @@ import (
 	"strconv"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 // PaginatedResponse represents a paginated API response
 type PaginatedResponse struct {
 	Data       interface{}            `json:"data"`
-	Pagination PaginationMeta         `json:"pagination"`
+	Pagination types.PaginationMeta   `json:"pagination"`
 	Filters    map[string]interface{} `json:"filters,omitempty"`
 }
 
-// PaginationMeta holds pagination metadata
-type PaginationMeta struct {
-	Page       int `json:"page"`
-	PerPage    int `json:"per_page"`
-	Total      int `json:"total"`
-	TotalPages int `json:"total_pages"`
-}
-
 // PaginationParams holds pagination parameters from request
 type PaginationParams struct {
 	Page    int
@@ -59,13 +52,13 @@ func ParsePaginationParams(c echo.Context) *PaginationParams {
 }
 
 // CalculatePagination calculates pagination metadata
-func CalculatePagination(page, perPage, total int) PaginationMeta {
+func CalculatePagination(page, perPage, total int) types.PaginationMeta {
 	totalPages := total / perPage
 	if total%perPage > 0 {
 		totalPages++
 	}
 
-	return PaginationMeta{
+	return types.PaginationMeta{
 		Page:       page,
 		PerPage:    perPage,
 		Total:      total,
@@ -74,7 +67,7 @@ func CalculatePagination(page, perPage, total int) PaginationMeta {
 }
 
 // SuccessPaginated returns a paginated success response
-func SuccessPaginated(c echo.Context, data interface{}, pagination PaginationMeta, filters map[string]interface{}) error {
+func SuccessPaginated(c echo.Context, data interface{}, pagination types.PaginationMeta, filters map[string]interface{}) error {
 	return c.JSON(http.StatusOK, &PaginatedResponse{
 		Data:       data,
 		Pagination: pagination,
@@ -18,6 +18,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	apimiddleware "github.com/tsanders-rh/ocpctl/internal/api/middleware"
 	"github.com/tsanders-rh/ocpctl/internal/auth"
+	"github.com/tsanders-rh/ocpctl/internal/catalog"
 	"github.com/tsanders-rh/ocpctl/internal/policy"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
 	"github.com/tsanders-rh/ocpctl/internal/s3"
@@ -43,7 +44,9 @@ type ServerConfig struct {
 	MaxBodySize       string
 	RateLimitRequests int
 	RateLimitDuration time.Duration
-	Environment       string // Environment name (development, production, etc.)
+	RateLimitBackend  string         // memory (per replica) or postgres (shared across replicas)
+	Environment       string         // Environment name (development, production, etc.)
+	OperatorCatalog   catalog.Config // OperatorHub catalog browsing (disabled without a cache directory)
 	// Version information
 	Version   string
 	Commit    string
@@ -65,6 +68,7 @@ func DefaultServerConfig() *ServerConfig {
 		MaxBodySize:       "1M",
 		RateLimitRequests: 300, // Increased to support auto-refresh UI patterns
 		RateLimitDuration: 1 * time.Minute,
+		RateLimitBackend:  RateLimitBackendMemory,
 	}
 }
 
@@ -77,7 +81,11 @@ type Server struct {
 	policy   *policy.Engine
 	auth     *auth.Auth
 	iamAuth  *auth.IAMAuthenticator
-	s3Client *s3.Client
+
+	rateLimits       *RateLimitResolver
+	rateLimitBackend apimiddleware.RateLimitBackend
+
+	catalogs *catalog.Index
 }
 
 // NewServer creates a new API server
@@ -120,14 +128,6 @@ func NewServer(
 		e.Logger.Warn("Failed to initialize IAM authenticator (IAM auth will be unavailable): ", err)
 	}
 
-	// Create S3 client for profile synchronization
-	s3Client, err := s3.NewClient(context.Background())
-	if err != nil {
-		// S3 client is optional - log warning but continue
-		// Profile updates will work locally but won't sync to S3
-		e.Logger.Warn("Failed to initialize S3 client (profile sync will be unavailable): ", err)
-	}
-
 	s := &Server{
 		echo:     e,
 		config:   config,
@@ -136,7 +136,18 @@ func NewServer(
 		policy:   policyEngine,
 		auth:     authService,
 		iamAuth:  iamAuthService,
-		s3Client: s3Client,
+		catalogs: catalog.NewIndex(config.OperatorCatalog),
+	}
+
+	s.rateLimits = NewRateLimitResolver(store, authService)
+	switch config.RateLimitBackend {
+	case RateLimitBackendPostgres:
+		s.rateLimitBackend = apimiddleware.NewPostgresRateLimitBackend(store.RateLimits)
+	case "", RateLimitBackendMemory:
+		s.rateLimitBackend = apimiddleware.NewRateLimiterStore(config.RateLimitRequests, 30)
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q (expected %q or %q)",
+			config.RateLimitBackend, RateLimitBackendMemory, RateLimitBackendPostgres)
 	}
 
 	s.setupMiddleware()
@@ -181,8 +192,8 @@ func (s *Server) setupMiddleware() {
 	s.echo.Use(middleware.GzipWithConfig(middleware.GzipConfig{
 		Level: 5, // Balance between compression ratio and CPU usage
 		Skipper: func(c echo.Context) bool {
-			// Skip compression for health checks and small responses
-			return c.Path() == "/health" || c.Path() == "/ready"
+			// Skip compression for health checks, small responses and event streams
+			return c.Path() == "/health" || c.Path() == "/ready" || c.Path() == EventStreamPath
 		},
 	}))
 
@@ -191,9 +202,9 @@ func (s *Server) setupMiddleware() {
 		s.echo.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 			AllowOrigins:     s.config.AllowedOrigins,
 			AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch},
-			AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+			AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, apimiddleware.HeaderIdempotencyKey},
 			AllowCredentials: true, // Required for cookies
-			ExposeHeaders:    []string{echo.HeaderContentLength},
+			ExposeHeaders:    append([]string{echo.HeaderContentLength, apimiddleware.HeaderIdempotentReplayed}, apimiddleware.RateLimitHeaders...),
 		}))
 	}
 
@@ -203,11 +214,15 @@ func (s *Server) setupMiddleware() {
 	// Timeout middleware
 	s.echo.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
 		Timeout: 30 * time.Second,
+		Skipper: func(c echo.Context) bool {
+			// Event streams stay open until the client disconnects
+			return c.Path() == EventStreamPath
+		},
 	}))
 
-	// Rate limiting (global, moderate limits)
+	// Rate limiting (global, moderate limits, keyed on user/API key with IP fallback)
 	// Burst of 30 allows initial page load with multiple concurrent queries
-	s.echo.Use(apimiddleware.RateLimit(s.config.RateLimitRequests, 30))
+	s.echo.Use(s.rateLimit(s.config.RateLimitRequests, 30))
 }
 
 // setupRoutes configures API routes
@@ -230,35 +245,38 @@ func (s *Server) setupRoutes() {
 	// API v1 routes
 	v1 := s.echo.Group("/api/v1")
 
+	// Replays responses for retried creates that carry an Idempotency-Key header
+	idem := s.idempotency()
+
 	// Auth routes (public)
 	authHandler := NewAuthHandler(s.store, s.auth)
 	authGroup := v1.Group("/auth")
 
 	// Strict rate limiting for login to prevent brute force
-	authGroup.POST("/login", authHandler.Login, apimiddleware.StrictRateLimitWithMessage(5, "too many login attempts, please wait a minute and try again")) // 5 requests/minute
-	authGroup.POST("/logout", authHandler.Logout, apimiddleware.StrictRateLimit(10))                                                                        // 10 requests/minute
-	authGroup.POST("/refresh", authHandler.Refresh, apimiddleware.StrictRateLimit(10))                                                                      // 10 requests/minute
+	authGroup.POST("/login", authHandler.Login, s.strictRateLimitWithMessage(5, "too many login attempts, please wait a minute and try again")) // 5 requests/minute
+	authGroup.POST("/logout", authHandler.Logout, s.strictRateLimit(10))                                                                        // 10 requests/minute
+	authGroup.POST("/refresh", authHandler.Refresh, s.strictRateLimit(10))                                                                      // 10 requests/minute
 
 	// Protected auth routes (require authentication)
 	authProtected := authGroup.Group("", auth.RequireAuthDual(s.auth, s.iamAuth))
 	authProtected.GET("/me", authHandler.GetMe)
 	authProtected.PATCH("/me", authHandler.UpdateMe)
-	authProtected.POST("/password", authHandler.ChangePassword, apimiddleware.StrictRateLimit(3)) // 3 password changes/minute
+	authProtected.POST("/password", authHandler.ChangePassword, s.strictRateLimit(3)) // 3 password changes/minute
 
 	// API key management routes (require authentication)
 	apiKeyHandler := NewAPIKeyHandler(s.store)
 	apiKeysGroup := v1.Group("/api-keys", auth.RequireAuthDual(s.auth, s.iamAuth))
 	apiKeysGroup.GET("", apiKeyHandler.List)
-	apiKeysGroup.POST("", apiKeyHandler.Create, apimiddleware.StrictRateLimit(5))             // 5 creates/minute
-	apiKeysGroup.PATCH("/:id", apiKeyHandler.Update, apimiddleware.StrictRateLimit(10))       // 10 updates/minute
-	apiKeysGroup.POST("/:id/revoke", apiKeyHandler.Revoke, apimiddleware.StrictRateLimit(10)) // 10 revocations/minute
-	apiKeysGroup.DELETE("/:id", apiKeyHandler.Delete, apimiddleware.StrictRateLimit(10))      // 10 deletions/minute
+	apiKeysGroup.POST("", apiKeyHandler.Create, s.strictRateLimit(5), idem)       // 5 creates/minute
+	apiKeysGroup.PATCH("/:id", apiKeyHandler.Update, s.strictRateLimit(10))       // 10 updates/minute
+	apiKeysGroup.POST("/:id/revoke", apiKeyHandler.Revoke, s.strictRateLimit(10)) // 10 revocations/minute
+	apiKeysGroup.DELETE("/:id", apiKeyHandler.Delete, s.strictRateLimit(10))      // 10 deletions/minute
 
 	// User management routes (admin only)
 	userHandler := NewUserHandler(s.store)
 	usersGroup := v1.Group("/users", auth.RequireAuthDual(s.auth, s.iamAuth), auth.RequireAdmin())
 	usersGroup.GET("", userHandler.List)
-	usersGroup.POST("", userHandler.Create)
+	usersGroup.POST("", userHandler.Create, idem)
 	usersGroup.GET("/:id", userHandler.Get)
 	usersGroup.PATCH("/:id", userHandler.Update)
 	usersGroup.DELETE("/:id", userHandler.Delete)
@@ -276,13 +294,20 @@ func (s *Server) setupRoutes() {
 	metricsHandler := NewMetricsHandler(s.store)
 	adminGroup.GET("/metrics/current", metricsHandler.GetCurrentMetrics)
 
-	// Profile version update routes (admin only)
+	// Profile management routes (admin only). The profiles directory is only
+	// read to resolve base profiles and fragments of submitted definitions.
 	profilesDir := os.Getenv("PROFILES_DIR")
 	if profilesDir == "" {
 		profilesDir = "/opt/ocpctl/profiles"
 	}
-	profileUpdateHandler := NewProfileUpdateHandler(s.registry, s.store, s.s3Client, profilesDir)
+	profileUpdateHandler := NewProfileUpdateHandler(s.registry, s.store, profilesDir)
 	adminGroup.GET("/profiles/version-check", profileUpdateHandler.HandleCheckVersions)
+	adminGroup.POST("/profiles", profileUpdateHandler.HandleCreateProfile, idem)
+	adminGroup.PUT("/profiles/:name", profileUpdateHandler.HandleUpdateProfile)
+	adminGroup.DELETE("/profiles/:name", profileUpdateHandler.HandleDeleteProfile)
+	adminGroup.GET("/profiles/:name/revisions", profileUpdateHandler.HandleListProfileRevisions)
+	adminGroup.GET("/profiles/:name/revisions/:revision", profileUpdateHandler.HandleGetProfileRevision)
+	adminGroup.POST("/profiles/:name/revisions/:revision/publish", profileUpdateHandler.HandlePublishProfileRevision)
 	adminGroup.POST("/profiles/:name/update-versions", profileUpdateHandler.HandleUpdateVersions)
 	adminGroup.POST("/profiles/:name/rollback", profileUpdateHandler.HandleRollbackProfile)
 	adminGroup.POST("/profiles/reload", profileUpdateHandler.HandleReloadProfiles)
@@ -296,9 +321,16 @@ func (s *Server) setupRoutes() {
 	adminGroup.GET("/windows-snapshots", windowsSnapshotHandler.ListWindowsSnapshots)
 	adminGroup.GET("/windows-snapshots/coverage", windowsSnapshotHandler.GetWindowsSnapshotCoverage)
 	adminGroup.GET("/windows-snapshots/:id", windowsSnapshotHandler.GetWindowsSnapshot)
-	adminGroup.POST("/windows-snapshots", windowsSnapshotHandler.CreateWindowsSnapshot)
+	adminGroup.POST("/windows-snapshots", windowsSnapshotHandler.CreateWindowsSnapshot, idem)
 	adminGroup.DELETE("/windows-snapshots/:id", windowsSnapshotHandler.DeleteWindowsSnapshot)
 
+	// Rate limit policy routes (admin only)
+	rateLimitHandler := NewRateLimitHandler(s.store, s.rateLimits)
+	adminGroup.GET("/rate-limits", rateLimitHandler.List)
+	adminGroup.POST("/rate-limits", rateLimitHandler.Create, idem)
+	adminGroup.PATCH("/rate-limits/:id", rateLimitHandler.Update)
+	adminGroup.DELETE("/rate-limits/:id", rateLimitHandler.Delete)
+
 	// Usage report (admin only)
 	reportHandler := NewReportHandler(s.store, s.registry)
 	adminGroup.GET("/reports/usage", reportHandler.GetUsageReport)
@@ -321,7 +353,7 @@ func (s *Server) setupRoutes() {
 	v1.GET("/teams/:name/costs", teamHandler.GetTeamCosts, auth.RequireAuthDual(s.auth, s.iamAuth), auth.RequireTeamAdmin())
 
 	// Admin-only team routes
-	adminGroup.POST("/teams", teamHandler.CreateTeam)
+	adminGroup.POST("/teams", teamHandler.CreateTeam, idem)
 	adminGroup.PATCH("/teams/:name", teamHandler.UpdateTeam)
 	adminGroup.DELETE("/teams/:name", teamHandler.DeleteTeam)
 	adminGroup.GET("/teams/:name/admins", teamHandler.ListTeamAdmins)
@@ -330,7 +362,7 @@ func (s *Server) setupRoutes() {
 
 	// Cluster pool management routes (admin only)
 	poolHandler := NewPoolHandler(s.store)
-	adminGroup.POST("/pools", poolHandler.CreatePool)
+	adminGroup.POST("/pools", poolHandler.CreatePool, idem)
 	adminGroup.GET("/pools", poolHandler.ListPools)
 	adminGroup.GET("/pools/:name", poolHandler.GetPool)
 	adminGroup.PATCH("/pools/:name", poolHandler.UpdatePool)
@@ -341,9 +373,9 @@ func (s *Server) setupRoutes() {
 	poolsGroup := v1.Group("/pools", auth.RequireAuthDual(s.auth, s.iamAuth))
 	poolsGroup.GET("", poolHandler.ListPools) // List enabled pools (all authenticated users)
 	poolsGroup.GET("/:pool_name/stats", poolLeaseHandler.GetPoolStats)
-	poolsGroup.GET("/:pool_name/clusters", poolLeaseHandler.GetPoolClusters)                                             // Get clusters in pool
-	poolsGroup.POST("/:pool_name/lease", poolLeaseHandler.LeaseCluster, apimiddleware.StrictRateLimit(20))               // 20 requests/minute
-	poolsGroup.POST("/clusters/:cluster_id/release", poolLeaseHandler.ReleaseCluster, apimiddleware.StrictRateLimit(20)) // 20 requests/minute
+	poolsGroup.GET("/:pool_name/clusters", poolLeaseHandler.GetPoolClusters)                                 // Get clusters in pool
+	poolsGroup.POST("/:pool_name/lease", poolLeaseHandler.LeaseCluster, s.strictRateLimit(20), idem)         // 20 requests/minute
+	poolsGroup.POST("/clusters/:cluster_id/release", poolLeaseHandler.ReleaseCluster, s.strictRateLimit(20)) // 20 requests/minute
 
 	// Cluster routes (all require authentication)
 	clusterHandler := NewClusterHandler(s.store, s.policy, s.registry)
@@ -354,7 +386,8 @@ func (s *Server) setupRoutes() {
 	clustersGroup := v1.Group("/clusters", auth.RequireAuthDual(s.auth, s.iamAuth))
 
 	// Stricter rate limit for cluster creation (resource intensive)
-	clustersGroup.POST("", clusterHandler.Create, apimiddleware.StrictRateLimit(10)) // 10 requests/minute
+	clustersGroup.POST("", clusterHandler.Create, s.strictRateLimit(10), idem)    // 10 requests/minute
+	clustersGroup.POST("/preview", clusterHandler.Preview, s.strictRateLimit(30)) // 30 requests/minute
 	clustersGroup.GET("", clusterHandler.List)
 	clustersGroup.GET("/:id", clusterHandler.Get)
 	clustersGroup.DELETE("/:id", clusterHandler.Delete)
@@ -362,6 +395,11 @@ func (s *Server) setupRoutes() {
 	clustersGroup.POST("/:id/refresh-outputs", clusterHandler.RefreshOutputs)
 	clustersGroup.POST("/:id/hibernate", clusterHandler.Hibernate)
 	clustersGroup.POST("/:id/resume", clusterHandler.Resume)
+	clustersGroup.POST("/:id/addons/:addon_id", clusterHandler.InstallAddon, idem)
+	clustersGroup.PATCH("/:id/addons/:addon_id", clusterHandler.UpgradeAddon, idem)
+	clustersGroup.DELETE("/:id/addons/:addon_id", clusterHandler.UninstallAddon)
+	clustersGroup.GET("/:id/drift", clusterHandler.GetDrift)
+	clustersGroup.POST("/:id/drift-check", clusterHandler.CheckDrift, idem)
 	clustersGroup.GET("/:id/outputs", clusterHandler.GetOutputs)
 	clustersGroup.GET("/:id/kubeconfig", clusterHandler.DownloadKubeconfig)
 	clustersGroup.GET("/:id/kubeconfig/download-url", clusterHandler.GetKubeconfigDownloadURL)
@@ -372,16 +410,21 @@ func (s *Server) setupRoutes() {
 	logHandler := NewLogHandler(s.store)
 	clustersGroup.GET("/:id/logs", logHandler.GetClusterLogs)
 
+	// Lifecycle event routes (require authentication, checked within handler)
+	eventHandler := NewClusterEventHandler(s.store)
+	clustersGroup.GET("/:id/events", eventHandler.ListClusterEvents)
+	v1.GET("/events/stream", eventHandler.Stream, auth.RequireAuthDual(s.auth, s.iamAuth))
+
 	// Storage routes (require authentication, checked within handler)
 	storageHandler := NewStorageHandler(s.store, s.policy)
-	clustersGroup.POST("/:id/storage/link", storageHandler.LinkToCluster)
+	clustersGroup.POST("/:id/storage/link", storageHandler.LinkToCluster, idem)
 	clustersGroup.GET("/:id/storage", storageHandler.GetStorage)
 	clustersGroup.DELETE("/:id/storage/link/:group_id", storageHandler.UnlinkStorage)
 
 	// Configuration routes (require authentication)
 	configHandler := NewConfigurationHandler(s.store)
 	clustersGroup.GET("/:id/configurations", configHandler.ListClusterConfigurations)
-	clustersGroup.POST("/:id/configure", configHandler.TriggerPostConfiguration)
+	clustersGroup.POST("/:id/configure", configHandler.TriggerPostConfiguration, idem)
 	clustersGroup.PATCH("/:id/configurations/:config_id/retry", configHandler.RetryConfiguration)
 
 	// Profile routes (require authentication)
@@ -394,15 +437,21 @@ func (s *Server) setupRoutes() {
 	// Post-config add-ons routes (require authentication)
 	addonsHandler := NewAddonsHandler(s.store, s.registry)
 	postConfigGroup := v1.Group("/post-config", auth.RequireAuthDual(s.auth, s.iamAuth))
-	postConfigGroup.GET("/addons", addonsHandler.List)                 // List with categories (for cluster creation)
-	postConfigGroup.GET("/addons/all", addonsHandler.ListAll)          // List all as flat array (for addon management)
-	postConfigGroup.GET("/addons/my", addonsHandler.ListUserAddons)    // Get user's custom addons
-	postConfigGroup.GET("/addons/:id", addonsHandler.GetByID)          // Get specific addon
-	postConfigGroup.POST("/addons", addonsHandler.Create)              // Create new addon
-	postConfigGroup.PUT("/addons/:id", addonsHandler.Update)           // Update draft addon
-	postConfigGroup.DELETE("/addons/:id", addonsHandler.Delete)        // Delete addon
-	postConfigGroup.POST("/addons/:id/publish", addonsHandler.Publish) // Publish addon
-	postConfigGroup.POST("/addons/:id/clone", addonsHandler.Clone)     // Clone addon
+	postConfigGroup.GET("/addons", addonsHandler.List)                   // List with categories (for cluster creation)
+	postConfigGroup.GET("/addons/all", addonsHandler.ListAll)            // List all as flat array (for addon management)
+	postConfigGroup.GET("/addons/my", addonsHandler.ListUserAddons)      // Get user's custom addons
+	postConfigGroup.GET("/addons/:id", addonsHandler.GetByID)            // Get specific addon
+	postConfigGroup.POST("/addons", addonsHandler.Create, idem)          // Create new addon
+	postConfigGroup.PUT("/addons/:id", addonsHandler.Update)             // Update draft addon
+	postConfigGroup.DELETE("/addons/:id", addonsHandler.Delete)          // Delete addon
+	postConfigGroup.POST("/addons/:id/publish", addonsHandler.Publish)   // Publish addon
+	postConfigGroup.POST("/addons/:id/clone", addonsHandler.Clone, idem) // Clone addon
+
+	// OperatorHub catalog browsing and draft addon generation
+	catalogHandler := NewCatalogHandler(s.store, s.catalogs)
+	postConfigGroup.GET("/catalog/:catalog/packages", catalogHandler.ListPackages)
+	postConfigGroup.GET("/catalog/:catalog/packages/:package", catalogHandler.GetPackage)
+	postConfigGroup.POST("/catalog/addons", catalogHandler.GenerateAddon, idem)
 
 	// Post-config validation and templates
 	postConfigHandler := NewPostConfigHandler()
@@ -411,7 +460,7 @@ func (s *Server) setupRoutes() {
 	// Template routes (require authentication)
 	templateHandler := NewTemplateHandler(s.store)
 	templatesGroup := v1.Group("/templates", auth.RequireAuthDual(s.auth, s.iamAuth))
-	templatesGroup.POST("", templateHandler.Create)
+	templatesGroup.POST("", templateHandler.Create, idem)
 	templatesGroup.GET("", templateHandler.List)
 	templatesGroup.GET("/:id", templateHandler.Get)
 	templatesGroup.PATCH("/:id", templateHandler.Update)
@@ -420,12 +469,24 @@ func (s *Server) setupRoutes() {
 	// Cluster-creation template routes (per-user, require authentication)
 	clusterTemplateHandler := NewClusterTemplateHandler(s.store)
 	clusterTemplatesGroup := v1.Group("/cluster-templates", auth.RequireAuthDual(s.auth, s.iamAuth))
-	clusterTemplatesGroup.POST("", clusterTemplateHandler.Create)
+	clusterTemplatesGroup.POST("", clusterTemplateHandler.Create, idem)
 	clusterTemplatesGroup.GET("", clusterTemplateHandler.List)
 	clusterTemplatesGroup.GET("/:id", clusterTemplateHandler.Get)
 	clusterTemplatesGroup.PATCH("/:id", clusterTemplateHandler.Update)
 	clusterTemplatesGroup.DELETE("/:id", clusterTemplateHandler.Delete)
 
+	// Declarative cluster spec routes (spec owner or admin)
+	clusterSpecHandler := NewClusterSpecHandler(s.store, s.policy, s.registry)
+	clusterSpecsGroup := v1.Group("/cluster-specs", auth.RequireAuthDual(s.auth, s.iamAuth))
+	clusterSpecsGroup.POST("/plan", clusterSpecHandler.Plan)
+	clusterSpecsGroup.GET("", clusterSpecHandler.List)
+	clusterSpecsGroup.POST("", clusterSpecHandler.Create, idem)
+	clusterSpecsGroup.GET("/:name", clusterSpecHandler.Get)
+	clusterSpecsGroup.PATCH("/:name", clusterSpecHandler.Update)
+	clusterSpecsGroup.DELETE("/:name", clusterSpecHandler.Delete)
+	clusterSpecsGroup.PUT("/:name/document", clusterSpecHandler.PutDocument)
+	clusterSpecsGroup.POST("/:name/sync", clusterSpecHandler.Sync)
+
 	// Job routes (require authentication)
 	jobHandler := NewJobHandler(s.store)
 	jobsGroup := v1.Group("/jobs", auth.RequireAuthDual(s.auth, s.iamAuth))
@@ -612,6 +673,12 @@ func (s *Server) Start() error {
 	return s.echo.Start(addr)
 }
 
+// RenderCatalogs renders the operator catalogs that requests found missing or
+// stale, until ctx is cancelled
+func (s *Server) RenderCatalogs(ctx context.Context) {
+	s.catalogs.Start(ctx)
+}
+
 // Shutdown gracefully shuts down the server
 func (s *Server) Shutdown(ctx context.Context) error {
 	return s.echo.Shutdown(ctx)
@@ -621,3 +688,16 @@ func (s *Server) Shutdown(ctx context.Context) error {
 func (s *Server) Echo() *echo.Echo {
 	return s.echo
 }
+
+// idempotency returns middleware that replays responses for retried requests
+// carrying an Idempotency-Key header. Keys are scoped to the authenticated
+// user, so it must run after authentication.
+func (s *Server) idempotency() echo.MiddlewareFunc {
+	return apimiddleware.Idempotency(apimiddleware.IdempotencyConfig{
+		Backend: s.store.Idempotency,
+		Scope: func(c echo.Context) string {
+			userID, _ := auth.GetUserID(c)
+			return userID
+		},
+	})
+}
@@ -47,13 +47,18 @@ func GenerateAPIKey() (plainKey string, keyPrefix string, keyHash string, error
 	keyPrefix = plainKey[:12] + "..."
 
 	// Hash the key for storage
-	hasher := sha256.New()
-	hasher.Write([]byte(plainKey))
-	keyHash = hex.EncodeToString(hasher.Sum(nil))
+	keyHash = HashAPIKey(plainKey)
 
 	return plainKey, keyPrefix, keyHash, nil
 }
 
+// HashAPIKey returns the hex-encoded SHA-256 hash stored for an API key
+func HashAPIKey(plainKey string) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(plainKey))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
 // ValidateAPIKey validates an API key and returns the associated user
 func ValidateAPIKey(ctx context.Context, st *store.Store, plainKey string) (*types.User, error) {
 	// Check if it has the correct prefix
@@ -62,9 +67,7 @@ func ValidateAPIKey(ctx context.Context, st *store.Store, plainKey string) (*typ
 	}
 
 	// Hash the key
-	hasher := sha256.New()
-	hasher.Write([]byte(plainKey))
-	keyHash := hex.EncodeToString(hasher.Sum(nil))
+	keyHash := HashAPIKey(plainKey)
 
 	// Look up the API key
 	apiKey, err := st.APIKeys.GetByKeyHash(ctx, keyHash)
@@ -0,0 +1,161 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// globalOperatorsNamespace has a built-in OperatorGroup targeting all namespaces
+const globalOperatorsNamespace = "openshift-operators"
+
+// exampleManifest is an alm-example written out as a manifest
+type exampleManifest struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace,omitempty"`
+	} `yaml:"metadata"`
+	Spec map[string]interface{} `yaml:"spec,omitempty"`
+}
+
+// DraftAddon builds the add-on a catalog package generates: a subscription to
+// the package's channel and, with an example kind requested, that alm-example
+// applied once the operator is installed. detail describes the head of the
+// requested channel.
+func DraftAddon(detail *types.CatalogPackageDetail, req *types.GenerateCatalogAddonRequest, version string) (*types.CreateAddonRequest, error) {
+	namespace, err := operatorNamespace(detail, req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	addonID := req.AddonID
+	if addonID == "" {
+		addonID = detail.Name
+	}
+
+	operator := types.CustomOperatorConfig{
+		Name:      detail.Name,
+		Namespace: namespace,
+		Source:    detail.Catalog,
+		Channel:   detail.Channel,
+	}
+	config := types.CustomPostConfig{Operators: []types.CustomOperatorConfig{operator}}
+
+	if req.ExampleKind != "" {
+		manifest, err := exampleManifestConfig(detail, req.ExampleKind, namespace)
+		if err != nil {
+			return nil, err
+		}
+		config.Manifests = append(config.Manifests, *manifest)
+	}
+
+	description := detail.Description
+	if description == "" {
+		description = fmt.Sprintf("%s from the %s catalog", detail.DisplayName, detail.Catalog)
+	}
+
+	currentCSV := ""
+	for _, ch := range detail.Channels {
+		if ch.Name == detail.Channel {
+			currentCSV = ch.CurrentCSV
+		}
+	}
+
+	return &types.CreateAddonRequest{
+		AddonID:            addonID,
+		Name:               detail.DisplayName,
+		Description:        description,
+		Category:           req.Category,
+		Config:             config,
+		SupportedPlatforms: []string{"openshift"},
+		Version:            detail.Channel,
+		DisplayName:        fmt.Sprintf("%s (%s)", detail.DisplayName, detail.Channel),
+		IsDefault:          true,
+		Metadata: &types.AddonMetadata{
+			Notes: []string{fmt.Sprintf("Generated from %s %s for OpenShift %s (%s)", detail.Catalog, detail.Name, version, currentCSV)},
+		},
+	}, nil
+}
+
+// operatorNamespace picks the namespace the operator is installed in. The
+// worker gives an operator's own namespace an OperatorGroup targeting only
+// that namespace, so operators that only support AllNamespaces go in
+// openshift-operators.
+func operatorNamespace(detail *types.CatalogPackageDetail, requested string) (string, error) {
+	supports := func(mode string) bool {
+		for _, m := range detail.InstallModes {
+			if m == mode {
+				return true
+			}
+		}
+		// Catalogs without CSV metadata don't say; let the install decide
+		return len(detail.InstallModes) == 0
+	}
+
+	if requested != "" {
+		if requested == globalOperatorsNamespace && !supports("AllNamespaces") {
+			return "", fmt.Errorf("%s does not support the AllNamespaces install mode needed in %s", detail.Name, globalOperatorsNamespace)
+		}
+		if requested != globalOperatorsNamespace && !supports("OwnNamespace") {
+			return "", fmt.Errorf("%s does not support the OwnNamespace install mode; install it in %s", detail.Name, globalOperatorsNamespace)
+		}
+		return requested, nil
+	}
+
+	switch {
+	case supports("OwnNamespace") && detail.SuggestedNamespace != "":
+		return detail.SuggestedNamespace, nil
+	case supports("AllNamespaces"):
+		return globalOperatorsNamespace, nil
+	case supports("OwnNamespace"):
+		return detail.Name, nil
+	default:
+		return "", fmt.Errorf("%s supports neither the OwnNamespace nor the AllNamespaces install mode", detail.Name)
+	}
+}
+
+// exampleManifestConfig writes the alm-example of a kind as a manifest task
+// that runs after the operator is installed
+func exampleManifestConfig(detail *types.CatalogPackageDetail, kind, namespace string) (*types.CustomManifestConfig, error) {
+	var example *types.CatalogExample
+	kinds := make([]string, 0, len(detail.Examples))
+	for i := range detail.Examples {
+		kinds = append(kinds, detail.Examples[i].Kind)
+		if detail.Examples[i].Kind == kind && example == nil {
+			example = &detail.Examples[i]
+		}
+	}
+	if example == nil {
+		return nil, fmt.Errorf("%w: %s has no %s example (examples: %s)", ErrNotFound, detail.Name, kind, strings.Join(kinds, ", "))
+	}
+
+	m := exampleManifest{APIVersion: example.APIVersion, Kind: example.Kind, Spec: example.Spec}
+	m.Metadata.Name = example.Name
+	if m.Metadata.Name == "" {
+		m.Metadata.Name = strings.ToLower(example.Kind)
+	}
+	// Examples usually leave the namespace out; namespaced resources go with
+	// the operator
+	m.Metadata.Namespace = example.Namespace
+	if m.Metadata.Namespace == "" && namespace != globalOperatorsNamespace {
+		m.Metadata.Namespace = namespace
+	}
+
+	content, err := yaml.Marshal(&m)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s example: %w", kind, err)
+	}
+
+	return &types.CustomManifestConfig{
+		Name:        fmt.Sprintf("%s-%s", detail.Name, strings.ToLower(example.Kind)),
+		Description: fmt.Sprintf("Example %s published by the operator", example.Kind),
+		// Manifest content is rendered as a template; keep any braces in the
+		// example literal
+		Content:   strings.ReplaceAll(string(content), "{{", `{{"{{"}}`),
+		DependsOn: []string{detail.Name},
+	}, nil
+}
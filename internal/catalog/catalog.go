@@ -0,0 +1,322 @@
+// Package catalog indexes the OperatorHub catalogs shipped with each
+// OpenShift version so operators can be browsed and turned into add-ons.
+// Catalogs are read from file-based catalog files in a cache directory,
+// rendered from their index images with opm in the background when missing or
+// stale.
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
+)
+
+// Catalogs that can be indexed
+const (
+	RedHatOperators    = "redhat-operators"
+	CertifiedOperators = "certified-operators"
+	CommunityOperators = "community-operators"
+)
+
+// Names lists the catalogs that can be indexed
+var Names = []string{RedHatOperators, CertifiedOperators, CommunityOperators}
+
+// DefaultIndexImages are the index images of each catalog. "{version}" is
+// replaced with the OpenShift minor version, e.g. 4.18.
+var DefaultIndexImages = map[string]string{
+	RedHatOperators:    "registry.redhat.io/redhat/redhat-operator-index:v{version}",
+	CertifiedOperators: "registry.redhat.io/redhat/certified-operator-index:v{version}",
+	CommunityOperators: "registry.redhat.io/redhat/community-operator-index:v{version}",
+}
+
+// DefaultCacheTTL is how long a rendered catalog is used before its index
+// image is rendered again
+const DefaultCacheTTL = 24 * time.Hour
+
+const (
+	// renderTimeout bounds one opm render
+	renderTimeout = 30 * time.Minute
+
+	// renderRetryInterval is how long a catalog whose render failed is
+	// reported unavailable before it is rendered again
+	renderRetryInterval = 5 * time.Minute
+
+	// renderQueueSize is how many catalogs can wait to be rendered
+	renderQueueSize = 16
+)
+
+var (
+	// ErrNotFound is returned for catalogs, packages and channels that do not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrUnavailable is returned when a catalog has no cached file and cannot
+	// be rendered from its index image
+	ErrUnavailable = errors.New("catalog unavailable")
+
+	// ErrInvalidVersion is returned for versions catalogs cannot be published for
+	ErrInvalidVersion = errors.New("invalid OpenShift version")
+
+	// ErrNotCached is returned for a catalog that has no cached file yet and
+	// is being rendered in the background
+	ErrNotCached = errors.New("catalog not cached yet")
+)
+
+// Config configures where catalogs are read from
+type Config struct {
+	// CacheDir holds rendered catalogs named <catalog>-<version>.json, e.g.
+	// redhat-operators-4.18.json. Files can be placed here by hand (the
+	// output of "opm render <index image> -o json"). Browsing is disabled
+	// when empty.
+	CacheDir string
+
+	// RenderImages allows missing or stale catalogs to be rendered from
+	// their index images with opm. The registry credentials opm uses must be
+	// available to the API server.
+	RenderImages bool
+
+	// IndexImages overrides DefaultIndexImages, e.g. with a mirror registry
+	IndexImages map[string]string
+
+	// CacheTTL is how long a rendered catalog is used before it is rendered
+	// again (DefaultCacheTTL when zero). Only applies with RenderImages set.
+	CacheTTL time.Duration
+}
+
+// Index reads and caches catalogs. Requests only read cached files; missing
+// and stale catalogs are queued and rendered by Start.
+type Index struct {
+	config Config
+
+	// render writes the rendered catalog of an index image to path
+	render func(ctx context.Context, image, path string) error
+
+	renders chan renderRequest
+
+	mu      sync.Mutex
+	entries map[string]*indexEntry
+}
+
+// indexEntry caches one catalog. Its mutex is held while the cached file is
+// parsed so concurrent requests parse it once. The render state is guarded
+// by the Index mutex.
+type indexEntry struct {
+	mu      sync.Mutex
+	catalog *Catalog
+	modTime time.Time
+
+	rendering      bool
+	renderErr      error
+	renderFailedAt time.Time
+}
+
+// renderRequest is a catalog queued for rendering
+type renderRequest struct {
+	key, name, minor, path string
+	entry                  *indexEntry
+}
+
+// NewIndex creates a catalog index
+func NewIndex(config Config) *Index {
+	if config.CacheTTL == 0 {
+		config.CacheTTL = DefaultCacheTTL
+	}
+	return &Index{
+		config:  config,
+		render:  renderIndexImage,
+		renders: make(chan renderRequest, renderQueueSize),
+		entries: make(map[string]*indexEntry),
+	}
+}
+
+// Enabled reports whether catalogs can be browsed
+func (i *Index) Enabled() bool {
+	return i != nil && i.config.CacheDir != ""
+}
+
+// Start renders queued catalogs one at a time until ctx is cancelled. It
+// returns at once when index images are not rendered.
+func (i *Index) Start(ctx context.Context) {
+	if !i.Enabled() || !i.config.RenderImages {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-i.renders:
+			i.renderQueued(ctx, req)
+		}
+	}
+}
+
+// Catalog returns a catalog for an OpenShift version such as "4.18" or
+// "4.18.3" from the cache directory. A missing catalog is queued for
+// rendering and reported as ErrNotCached; a stale one is served while it is
+// rendered again.
+func (i *Index) Catalog(ctx context.Context, name, version string) (*Catalog, error) {
+	if !i.Enabled() {
+		return nil, fmt.Errorf("%w: no catalog cache directory is configured", ErrUnavailable)
+	}
+	if !isCatalog(name) {
+		return nil, fmt.Errorf("%w: unknown catalog %s (expected one of %s)", ErrNotFound, name, strings.Join(Names, ", "))
+	}
+	minor, err := MinorVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	key := name + "-" + minor
+	i.mu.Lock()
+	entry, ok := i.entries[key]
+	if !ok {
+		entry = &indexEntry{}
+		i.entries[key] = entry
+	}
+	i.mu.Unlock()
+
+	path := filepath.Join(i.config.CacheDir, key+".json")
+	info, statErr := os.Stat(path)
+	if statErr != nil && !os.IsNotExist(statErr) {
+		return nil, fmt.Errorf("stat catalog %s: %w", path, statErr)
+	}
+	if i.config.RenderImages && (statErr != nil || time.Since(info.ModTime()) > i.config.CacheTTL) {
+		if err := i.queueRender(renderRequest{key: key, name: name, minor: minor, path: path, entry: entry}); err != nil && statErr != nil {
+			return nil, err
+		}
+	}
+	if statErr != nil {
+		if i.config.RenderImages {
+			return nil, fmt.Errorf("%w: %s is being rendered", ErrNotCached, key)
+		}
+		return nil, fmt.Errorf("%w: no cached catalog %s and index image rendering is disabled", ErrUnavailable, path)
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.catalog != nil && entry.modTime.Equal(info.ModTime()) {
+		return entry.catalog, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open catalog %s: %w", path, err)
+	}
+	defer f.Close()
+
+	c, err := Parse(name, minor, f)
+	if err != nil {
+		return nil, err
+	}
+	entry.catalog = c
+	entry.modTime = info.ModTime()
+	return c, nil
+}
+
+// queueRender queues a catalog for rendering unless it is already queued. A
+// catalog whose last render failed recently is not queued again, and the
+// failure is returned.
+func (i *Index) queueRender(req renderRequest) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	entry := req.entry
+	if entry.rendering {
+		return nil
+	}
+	if entry.renderErr != nil && time.Since(entry.renderFailedAt) < renderRetryInterval {
+		return fmt.Errorf("%w: render %s: %v", ErrUnavailable, i.indexImage(req.name, req.minor), entry.renderErr)
+	}
+
+	select {
+	case i.renders <- req:
+		entry.rendering = true
+	default:
+		// The queue is full; a later request queues the catalog again
+	}
+	return nil
+}
+
+// renderQueued renders a queued catalog and records the outcome
+func (i *Index) renderQueued(ctx context.Context, req renderRequest) {
+	ctx, cancel := context.WithTimeout(ctx, renderTimeout)
+	defer cancel()
+
+	err := i.render(ctx, i.indexImage(req.name, req.minor), req.path)
+	if err != nil {
+		log.Printf("Warning: failed to render catalog %s: %v", req.key, err)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	req.entry.rendering = false
+	req.entry.renderErr = err
+	if err != nil {
+		req.entry.renderFailedAt = time.Now()
+	}
+}
+
+// indexImage returns the index image of a catalog for an OpenShift minor version
+func (i *Index) indexImage(name, minor string) string {
+	image, ok := i.config.IndexImages[name]
+	if !ok {
+		image = DefaultIndexImages[name]
+	}
+	return strings.ReplaceAll(image, "{version}", minor)
+}
+
+// MinorVersion returns the major.minor form of an OpenShift version, which
+// catalogs are published for. OpenShift 3 had no operator catalogs.
+func MinorVersion(version string) (string, error) {
+	v, err := postconfig.ParseVersion(version)
+	if err != nil || v.Major < 4 {
+		return "", fmt.Errorf("%w %q", ErrInvalidVersion, version)
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor), nil
+}
+
+// isCatalog reports whether name is a catalog that can be indexed
+func isCatalog(name string) bool {
+	for _, n := range Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// renderIndexImage renders an index image with opm, replacing the file at
+// path only once rendering succeeds
+func renderIndexImage(ctx context.Context, image, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create catalog cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create catalog file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	log.Printf("Rendering operator catalog %s", image)
+	var stderr strings.Builder
+	cmd := exec.CommandContext(ctx, "opm", "render", image, "-o", "json")
+	cmd.Stdout = tmp
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("opm render: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write catalog file: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,319 @@
+package catalog
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// testCatalog returns a rendered catalog with two packages. oadp lists its
+// CSV metadata as olm.csv.metadata; legacy-operator only carries the CSV in
+// an olm.bundle.object, as catalogs rendered by older opm versions do.
+func testCatalog(t *testing.T) string {
+	t.Helper()
+
+	csv := `{"kind":"ClusterServiceVersion","metadata":{"annotations":{"description":"Legacy operator"}},` +
+		`"spec":{"displayName":"Legacy","installModes":[{"type":"AllNamespaces","supported":true},{"type":"OwnNamespace","supported":false}]}}`
+	crd := `{"kind":"CustomResourceDefinition"}`
+	examples := `[{"apiVersion":"oadp.openshift.io/v1alpha1","kind":"DataProtectionApplication","metadata":{"name":"velero-sample"},"spec":{"configuration":{"velero":{"defaultPlugins":["aws"]}},"template":"{{ .Values }}"}}]`
+	examplesJSON, err := json.Marshal(examples)
+	require.NoError(t, err)
+
+	return strings.Join([]string{
+		`{"schema":"olm.package","name":"redhat-oadp-operator","defaultChannel":"stable","description":"OADP long description"}`,
+		`{"schema":"olm.channel","package":"redhat-oadp-operator","name":"stable","entries":[` +
+			`{"name":"oadp-operator.v1.4.0"},` +
+			`{"name":"oadp-operator.v1.4.1","replaces":"oadp-operator.v1.4.0"},` +
+			`{"name":"oadp-operator.v1.5.1","replaces":"oadp-operator.v1.4.1","skips":["oadp-operator.v1.5.0"]}]}`,
+		`{"schema":"olm.channel","package":"redhat-oadp-operator","name":"stable-1.4","entries":[` +
+			`{"name":"oadp-operator.v1.4.0"},{"name":"oadp-operator.v1.4.1","replaces":"oadp-operator.v1.4.0"}]}`,
+		bundleJSON("redhat-oadp-operator", "oadp-operator.v1.4.0", "1.4.0", ""),
+		bundleJSON("redhat-oadp-operator", "oadp-operator.v1.4.1", "1.4.1", ""),
+		bundleJSON("redhat-oadp-operator", "oadp-operator.v1.5.1", "1.5.1",
+			`,{"type":"olm.csv.metadata","value":{"displayName":"OADP Operator","provider":{"name":"Red Hat"},`+
+				`"annotations":{"description":"Backup and restore","operatorframework.io/suggested-namespace":"openshift-adp","alm-examples":`+string(examplesJSON)+`},`+
+				`"installModes":[{"type":"OwnNamespace","supported":true},{"type":"AllNamespaces","supported":false}]}}`),
+		`{"schema":"olm.deprecations","package":"redhat-oadp-operator"}`,
+		`{"schema":"olm.package","name":"legacy-operator","defaultChannel":"alpha"}`,
+		// Two unrelated entries: the higher version is the head
+		`{"schema":"olm.channel","package":"legacy-operator","name":"alpha","entries":[{"name":"legacy.v0.10.0"},{"name":"legacy.v0.9.0"}]}`,
+		bundleJSON("legacy-operator", "legacy.v0.9.0", "0.9.0", ""),
+		bundleJSON("legacy-operator", "legacy.v0.10.0", "0.10.0",
+			`,{"type":"olm.bundle.object","value":{"data":"`+base64.StdEncoding.EncodeToString([]byte(crd))+`"}}`+
+				`,{"type":"olm.bundle.object","value":{"data":"`+base64.StdEncoding.EncodeToString([]byte(csv))+`"}}`),
+	}, "\n")
+}
+
+func bundleJSON(pkg, name, version, properties string) string {
+	return fmt.Sprintf(`{"schema":"olm.bundle","package":%q,"name":%q,"properties":[{"type":"olm.package","value":{"packageName":%q,"version":%q}}%s]}`,
+		pkg, name, pkg, version, properties)
+}
+
+func TestParse(t *testing.T) {
+	c, err := Parse(RedHatOperators, "4.18", strings.NewReader(testCatalog(t)))
+	require.NoError(t, err)
+
+	packages := c.Packages("")
+	require.Len(t, packages, 2)
+	assert.Equal(t, "legacy-operator", packages[0].Name)
+	assert.Equal(t, "Legacy", packages[0].DisplayName)
+	assert.Equal(t, "Legacy operator", packages[0].Description)
+	assert.Equal(t, []types.CatalogChannel{{Name: "alpha", CurrentCSV: "legacy.v0.10.0", Version: "0.10.0"}}, packages[0].Channels)
+
+	oadp := packages[1]
+	assert.Equal(t, "redhat-oadp-operator", oadp.Name)
+	assert.Equal(t, RedHatOperators, oadp.Catalog)
+	assert.Equal(t, "OADP Operator", oadp.DisplayName)
+	assert.Equal(t, "Backup and restore", oadp.Description)
+	assert.Equal(t, "Red Hat", oadp.Provider)
+	assert.Equal(t, "stable", oadp.DefaultChannel)
+	assert.Equal(t, []types.CatalogChannel{
+		{Name: "stable", CurrentCSV: "oadp-operator.v1.5.1", Version: "1.5.1"},
+		{Name: "stable-1.4", CurrentCSV: "oadp-operator.v1.4.1", Version: "1.4.1"},
+	}, oadp.Channels)
+
+	assert.Len(t, c.Packages("BACKUP"), 1)
+	assert.Len(t, c.Packages("legacy"), 1)
+	assert.Empty(t, c.Packages("nothing"))
+
+	_, err = Parse(RedHatOperators, "4.18", strings.NewReader(`{"schema":`))
+	assert.Error(t, err)
+}
+
+func TestPackage(t *testing.T) {
+	c, err := Parse(RedHatOperators, "4.18", strings.NewReader(testCatalog(t)))
+	require.NoError(t, err)
+
+	detail, err := c.Package("redhat-oadp-operator", "")
+	require.NoError(t, err)
+	assert.Equal(t, "stable", detail.Channel)
+	assert.Equal(t, []string{"OwnNamespace"}, detail.InstallModes)
+	assert.Equal(t, "openshift-adp", detail.SuggestedNamespace)
+	require.Len(t, detail.Examples, 1)
+	assert.Equal(t, "DataProtectionApplication", detail.Examples[0].Kind)
+	assert.Equal(t, "velero-sample", detail.Examples[0].Name)
+	assert.Contains(t, detail.Examples[0].Spec, "configuration")
+
+	// The 1.4 channel head has no CSV metadata
+	detail, err = c.Package("redhat-oadp-operator", "stable-1.4")
+	require.NoError(t, err)
+	assert.Empty(t, detail.InstallModes)
+	assert.Empty(t, detail.Examples)
+
+	legacy, err := c.Package("legacy-operator", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AllNamespaces"}, legacy.InstallModes)
+
+	_, err = c.Package("missing", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = c.Package("redhat-oadp-operator", "fast")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMinorVersion(t *testing.T) {
+	for version, want := range map[string]string{"4.18": "4.18", "4.18.3": "4.18", "4.20.0-rc.1": "4.20", "5.0.1": "5.0"} {
+		got, err := MinorVersion(version)
+		require.NoError(t, err, version)
+		assert.Equal(t, want, got)
+	}
+	for _, version := range []string{"", "latest", "3.11"} {
+		_, err := MinorVersion(version)
+		assert.ErrorIs(t, err, ErrInvalidVersion, version)
+	}
+}
+
+func TestIndexCatalog(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	// Without rendering, only cached files are read
+	index := NewIndex(Config{CacheDir: dir})
+	_, err := index.Catalog(ctx, RedHatOperators, "4.18")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	_, err = index.Catalog(ctx, "my-operators", "4.18")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = index.Catalog(ctx, RedHatOperators, "latest")
+	assert.ErrorIs(t, err, ErrInvalidVersion)
+
+	path := filepath.Join(dir, "redhat-operators-4.18.json")
+	require.NoError(t, os.WriteFile(path, []byte(testCatalog(t)), 0644))
+	c, err := index.Catalog(ctx, RedHatOperators, "4.18.3")
+	require.NoError(t, err)
+	assert.Equal(t, "4.18", c.Version)
+	assert.Len(t, c.Packages(""), 2)
+
+	again, err := index.Catalog(ctx, RedHatOperators, "4.18")
+	require.NoError(t, err)
+	assert.Same(t, c, again)
+
+	// A replaced file is parsed again
+	require.NoError(t, os.WriteFile(path, []byte(`{"schema":"olm.package","name":"only"}`), 0644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	c, err = index.Catalog(ctx, RedHatOperators, "4.18")
+	require.NoError(t, err)
+	assert.Len(t, c.Packages(""), 1)
+
+	assert.False(t, NewIndex(Config{}).Enabled())
+	var nilIndex *Index
+	assert.False(t, nilIndex.Enabled())
+}
+
+func TestIndexRender(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var rendered []string
+	failRender := false
+	renderedImages := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, rendered...)
+	}
+
+	index := NewIndex(Config{
+		CacheDir:     dir,
+		RenderImages: true,
+		IndexImages:  map[string]string{CommunityOperators: "mirror.example.com/community:v{version}"},
+	})
+	index.render = func(ctx context.Context, image, path string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		rendered = append(rendered, image)
+		if failRender {
+			return errors.New("registry unreachable")
+		}
+		return os.WriteFile(path, []byte(testCatalog(t)), 0644)
+	}
+	go index.Start(ctx)
+
+	// Missing catalogs are rendered in the background, not by the request
+	waitForCatalog := func(name, version string) *Catalog {
+		t.Helper()
+		_, err := index.Catalog(ctx, name, version)
+		require.ErrorIs(t, err, ErrNotCached)
+		var c *Catalog
+		require.Eventually(t, func() bool {
+			c, err = index.Catalog(ctx, name, version)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		return c
+	}
+	waitForCatalog(RedHatOperators, "4.18")
+	waitForCatalog(CommunityOperators, "4.17")
+
+	// Fresh cached files are not rendered again
+	_, err := index.Catalog(ctx, RedHatOperators, "4.18")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"registry.redhat.io/redhat/redhat-operator-index:v4.18",
+		"mirror.example.com/community:v4.17",
+	}, renderedImages())
+
+	// Stale files are served while they are rendered again, and kept when
+	// rendering fails
+	mu.Lock()
+	failRender = true
+	mu.Unlock()
+	old := time.Now().Add(-2 * DefaultCacheTTL)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "redhat-operators-4.18.json"), old, old))
+	c, err := index.Catalog(ctx, RedHatOperators, "4.18")
+	require.NoError(t, err)
+	assert.Len(t, c.Packages(""), 2)
+	require.Eventually(t, func() bool { return len(renderedImages()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	c, err = index.Catalog(ctx, RedHatOperators, "4.18")
+	require.NoError(t, err)
+	assert.Len(t, c.Packages(""), 2)
+
+	// A catalog that fails to render is reported unavailable, without
+	// rendering it again on every request
+	_, err = index.Catalog(ctx, CertifiedOperators, "4.18")
+	require.ErrorIs(t, err, ErrNotCached)
+	require.Eventually(t, func() bool {
+		_, err = index.Catalog(ctx, CertifiedOperators, "4.18")
+		return errors.Is(err, ErrUnavailable)
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ErrorContains(t, err, "registry unreachable")
+	_, err = index.Catalog(ctx, CertifiedOperators, "4.18")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Len(t, renderedImages(), 4)
+}
+
+func TestDraftAddon(t *testing.T) {
+	c, err := Parse(RedHatOperators, "4.18", strings.NewReader(testCatalog(t)))
+	require.NoError(t, err)
+	oadp, err := c.Package("redhat-oadp-operator", "")
+	require.NoError(t, err)
+
+	req := &types.GenerateCatalogAddonRequest{
+		Version:     "4.18",
+		Catalog:     RedHatOperators,
+		Package:     "redhat-oadp-operator",
+		Category:    "backup",
+		ExampleKind: "DataProtectionApplication",
+	}
+	draft, err := DraftAddon(oadp, req, "4.18")
+	require.NoError(t, err)
+	assert.Equal(t, "redhat-oadp-operator", draft.AddonID)
+	assert.Equal(t, "OADP Operator", draft.Name)
+	assert.Equal(t, "OADP Operator (stable)", draft.DisplayName)
+	assert.Equal(t, "stable", draft.Version)
+	assert.Equal(t, "backup", draft.Category)
+	assert.Equal(t, []string{"openshift"}, draft.SupportedPlatforms)
+	assert.Equal(t, []types.CustomOperatorConfig{{
+		Name:      "redhat-oadp-operator",
+		Namespace: "openshift-adp",
+		Source:    RedHatOperators,
+		Channel:   "stable",
+	}}, draft.Config.Operators)
+	require.NotNil(t, draft.Metadata)
+	assert.Equal(t, []string{"Generated from redhat-operators redhat-oadp-operator for OpenShift 4.18 (oadp-operator.v1.5.1)"}, draft.Metadata.Notes)
+
+	require.Len(t, draft.Config.Manifests, 1)
+	manifest := draft.Config.Manifests[0]
+	assert.Equal(t, "redhat-oadp-operator-dataprotectionapplication", manifest.Name)
+	assert.Equal(t, []string{"redhat-oadp-operator"}, manifest.DependsOn)
+	assert.Contains(t, manifest.Content, "kind: DataProtectionApplication")
+	assert.Contains(t, manifest.Content, "name: velero-sample")
+	assert.Contains(t, manifest.Content, "namespace: openshift-adp")
+	assert.Contains(t, manifest.Content, `{{"{{"}} .Values }}`)
+
+	req.ExampleKind = "Restore"
+	_, err = DraftAddon(oadp, req, "4.18")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// OADP only supports OwnNamespace
+	req.ExampleKind = ""
+	req.Namespace = "openshift-operators"
+	_, err = DraftAddon(oadp, req, "4.18")
+	assert.Error(t, err)
+	req.Namespace = "backup"
+	req.AddonID = "oadp"
+	draft, err = DraftAddon(oadp, req, "4.18")
+	require.NoError(t, err)
+	assert.Equal(t, "oadp", draft.AddonID)
+	assert.Equal(t, "backup", draft.Config.Operators[0].Namespace)
+	assert.Empty(t, draft.Config.Manifests)
+
+	// AllNamespaces-only operators go in openshift-operators
+	legacy, err := c.Package("legacy-operator", "")
+	require.NoError(t, err)
+	draft, err = DraftAddon(legacy, &types.GenerateCatalogAddonRequest{Category: "cicd"}, "4.18")
+	require.NoError(t, err)
+	assert.Equal(t, "openshift-operators", draft.Config.Operators[0].Namespace)
+	_, err = DraftAddon(legacy, &types.GenerateCatalogAddonRequest{Category: "cicd", Namespace: "legacy"}, "4.18")
+	assert.Error(t, err)
+}
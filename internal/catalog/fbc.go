@@ -0,0 +1,355 @@
+package catalog
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// Catalog is an indexed OperatorHub catalog for one OpenShift version
+type Catalog struct {
+	Name     string
+	Version  string
+	packages map[string]*catalogPackage
+}
+
+// catalogPackage is an indexed olm.package with its channels and bundles
+type catalogPackage struct {
+	name           string
+	description    string
+	defaultChannel string
+	channels       map[string]*channel
+	bundles        map[string]*bundle
+}
+
+// channel is an indexed olm.channel
+type channel struct {
+	name    string
+	entries []channelEntry
+}
+
+// channelEntry is a bundle in a channel and the bundles it upgrades from
+type channelEntry struct {
+	Name     string   `json:"name"`
+	Replaces string   `json:"replaces,omitempty"`
+	Skips    []string `json:"skips,omitempty"`
+}
+
+// bundle holds the CSV metadata of an indexed olm.bundle
+type bundle struct {
+	name         string
+	version      string
+	displayName  string
+	description  string
+	provider     string
+	annotations  map[string]string
+	installModes []string
+}
+
+// fbcObject is one object of a file-based catalog: an olm.package,
+// olm.channel or olm.bundle. Other schemas are ignored.
+type fbcObject struct {
+	Schema         string         `json:"schema"`
+	Name           string         `json:"name"`
+	Package        string         `json:"package"`
+	DefaultChannel string         `json:"defaultChannel"`
+	Description    string         `json:"description"`
+	Entries        []channelEntry `json:"entries"`
+	Properties     []struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	} `json:"properties"`
+}
+
+// csvMetadata is the part of a ClusterServiceVersion the index keeps. It is
+// read from olm.csv.metadata properties, or from the CSV in an
+// olm.bundle.object property for catalogs rendered before those existed.
+type csvMetadata struct {
+	Annotations  map[string]string     `json:"annotations"`
+	DisplayName  string                `json:"displayName"`
+	Description  string                `json:"description"`
+	Provider     struct{ Name string } `json:"provider"`
+	InstallModes []struct {
+		Type      string `json:"type"`
+		Supported bool   `json:"supported"`
+	} `json:"installModes"`
+}
+
+// Parse indexes a file-based catalog as written by "opm render -o json", a
+// stream of JSON objects
+func Parse(name, version string, r io.Reader) (*Catalog, error) {
+	c := &Catalog{Name: name, Version: version, packages: make(map[string]*catalogPackage)}
+	pkg := func(name string) *catalogPackage {
+		p, ok := c.packages[name]
+		if !ok {
+			p = &catalogPackage{name: name, channels: make(map[string]*channel), bundles: make(map[string]*bundle)}
+			c.packages[name] = p
+		}
+		return p
+	}
+
+	dec := json.NewDecoder(r)
+	for {
+		var obj fbcObject
+		err := dec.Decode(&obj)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse catalog %s: %w", name, err)
+		}
+
+		switch obj.Schema {
+		case "olm.package":
+			p := pkg(obj.Name)
+			p.defaultChannel = obj.DefaultChannel
+			p.description = obj.Description
+		case "olm.channel":
+			pkg(obj.Package).channels[obj.Name] = &channel{name: obj.Name, entries: obj.Entries}
+		case "olm.bundle":
+			b, err := parseBundle(&obj)
+			if err != nil {
+				return nil, fmt.Errorf("parse catalog %s: bundle %s: %w", name, obj.Name, err)
+			}
+			pkg(obj.Package).bundles[obj.Name] = b
+		}
+	}
+	return c, nil
+}
+
+// parseBundle reads the version and CSV metadata from a bundle's properties
+func parseBundle(obj *fbcObject) (*bundle, error) {
+	b := &bundle{name: obj.Name}
+	var csv *csvMetadata
+	for _, prop := range obj.Properties {
+		switch prop.Type {
+		case "olm.package":
+			var v struct {
+				Version string `json:"version"`
+			}
+			if err := json.Unmarshal(prop.Value, &v); err != nil {
+				return nil, fmt.Errorf("olm.package property: %w", err)
+			}
+			b.version = v.Version
+		case "olm.csv.metadata":
+			csv = &csvMetadata{}
+			if err := json.Unmarshal(prop.Value, csv); err != nil {
+				return nil, fmt.Errorf("olm.csv.metadata property: %w", err)
+			}
+		case "olm.bundle.object":
+			if csv != nil {
+				continue
+			}
+			found, err := bundleObjectCSV(prop.Value)
+			if err != nil {
+				return nil, err
+			}
+			if found != nil {
+				csv = found
+			}
+		}
+	}
+
+	if csv != nil {
+		b.displayName = csv.DisplayName
+		b.description = csv.Description
+		b.provider = csv.Provider.Name
+		b.annotations = csv.Annotations
+		for _, mode := range csv.InstallModes {
+			if mode.Supported {
+				b.installModes = append(b.installModes, mode.Type)
+			}
+		}
+	}
+	return b, nil
+}
+
+// bundleObjectCSV returns the CSV metadata in an olm.bundle.object property,
+// or nil if the object is not the bundle's ClusterServiceVersion
+func bundleObjectCSV(value json.RawMessage) (*csvMetadata, error) {
+	var prop struct {
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal(value, &prop); err != nil {
+		return nil, fmt.Errorf("olm.bundle.object property: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(prop.Data)
+	if err != nil {
+		return nil, fmt.Errorf("olm.bundle.object property: %w", err)
+	}
+
+	var obj struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Spec csvMetadata `json:"spec"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil || obj.Kind != "ClusterServiceVersion" {
+		// Bundles also carry CRDs and other manifests
+		return nil, nil
+	}
+	obj.Spec.Annotations = obj.Metadata.Annotations
+	return &obj.Spec, nil
+}
+
+// Packages lists the catalog's packages, sorted by name. With search set,
+// only packages whose name, display name or description contain it
+// (case-insensitively) are listed.
+func (c *Catalog) Packages(search string) []types.CatalogPackage {
+	search = strings.ToLower(search)
+	result := make([]types.CatalogPackage, 0, len(c.packages))
+	for _, p := range c.packages {
+		summary := c.summary(p)
+		if search != "" &&
+			!strings.Contains(strings.ToLower(summary.Name), search) &&
+			!strings.Contains(strings.ToLower(summary.DisplayName), search) &&
+			!strings.Contains(strings.ToLower(summary.Description), search) {
+			continue
+		}
+		result = append(result, summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Package describes the head of one of a package's channels. The default
+// channel is described when channelName is empty. Returns ErrNotFound if
+// the package or channel is not in the catalog.
+func (c *Catalog) Package(name, channelName string) (*types.CatalogPackageDetail, error) {
+	p, ok := c.packages[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: package %s is not in %s for OpenShift %s", ErrNotFound, name, c.Name, c.Version)
+	}
+	if channelName == "" {
+		channelName = p.defaultChannel
+	}
+	ch, ok := p.channels[channelName]
+	if !ok {
+		return nil, fmt.Errorf("%w: package %s has no channel %s", ErrNotFound, name, channelName)
+	}
+
+	detail := &types.CatalogPackageDetail{
+		CatalogPackage: c.summary(p),
+		Channel:        channelName,
+		Examples:       []types.CatalogExample{},
+	}
+	head := p.bundles[p.head(ch)]
+	if head == nil {
+		return detail, nil
+	}
+
+	detail.InstallModes = head.installModes
+	detail.SuggestedNamespace = head.annotations["operatorframework.io/suggested-namespace"]
+	if raw := head.annotations["alm-examples"]; raw != "" {
+		examples, err := parseExamples(raw)
+		if err != nil {
+			return nil, fmt.Errorf("bundle %s: %w", head.name, err)
+		}
+		detail.Examples = examples
+	}
+	return detail, nil
+}
+
+// summary describes a package by the bundle at the head of its default channel
+func (c *Catalog) summary(p *catalogPackage) types.CatalogPackage {
+	summary := types.CatalogPackage{
+		Name:           p.name,
+		Catalog:        c.Name,
+		DisplayName:    p.name,
+		Description:    p.description,
+		DefaultChannel: p.defaultChannel,
+		Channels:       make([]types.CatalogChannel, 0, len(p.channels)),
+	}
+
+	for _, ch := range p.channels {
+		entry := types.CatalogChannel{Name: ch.name, CurrentCSV: p.head(ch)}
+		if b := p.bundles[entry.CurrentCSV]; b != nil {
+			entry.Version = b.version
+		}
+		summary.Channels = append(summary.Channels, entry)
+	}
+	sort.Slice(summary.Channels, func(i, j int) bool { return summary.Channels[i].Name < summary.Channels[j].Name })
+
+	if ch, ok := p.channels[p.defaultChannel]; ok {
+		if b := p.bundles[p.head(ch)]; b != nil {
+			if b.displayName != "" {
+				summary.DisplayName = b.displayName
+			}
+			// CSVs carry a one-line description in their annotations; the
+			// spec description is long-form markdown
+			if d := b.annotations["description"]; d != "" {
+				summary.Description = d
+			} else if summary.Description == "" {
+				summary.Description, _, _ = strings.Cut(b.description, "\n")
+			}
+			summary.Provider = b.provider
+		}
+	}
+	return summary
+}
+
+// head returns the bundle at the head of a channel: the entry no other entry
+// replaces or skips. When the upgrade graph has several, the one with the
+// highest version is the head.
+func (p *catalogPackage) head(ch *channel) string {
+	superseded := make(map[string]bool)
+	for _, e := range ch.entries {
+		if e.Replaces != "" {
+			superseded[e.Replaces] = true
+		}
+		for _, s := range e.Skips {
+			superseded[s] = true
+		}
+	}
+
+	head := ""
+	var headVersion postconfig.Version
+	for _, e := range ch.entries {
+		if superseded[e.Name] {
+			continue
+		}
+		var v postconfig.Version
+		if b := p.bundles[e.Name]; b != nil {
+			v, _ = postconfig.ParseVersion(b.version)
+		}
+		if head == "" || v.Compare(headVersion) > 0 {
+			head, headVersion = e.Name, v
+		}
+	}
+	return head
+}
+
+// parseExamples parses a CSV's alm-examples annotation
+func parseExamples(raw string) ([]types.CatalogExample, error) {
+	var objects []struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Metadata   struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Spec map[string]interface{} `json:"spec"`
+	}
+	if err := json.Unmarshal([]byte(raw), &objects); err != nil {
+		return nil, fmt.Errorf("parse alm-examples: %w", err)
+	}
+
+	examples := make([]types.CatalogExample, 0, len(objects))
+	for _, o := range objects {
+		examples = append(examples, types.CatalogExample{
+			APIVersion: o.APIVersion,
+			Kind:       o.Kind,
+			Name:       o.Metadata.Name,
+			Namespace:  o.Metadata.Namespace,
+			Spec:       o.Spec,
+		})
+	}
+	return examples, nil
+}
@@ -0,0 +1,599 @@
+// Package clusterconfig builds what the worker submits to each installer for a
+// cluster: the install-config request, the eksctl ClusterConfig and the
+// rosa/gcloud/az/ibmcloud inputs. The builders only read the cluster record
+// and its profile, so the API can render the same artifacts for a preview
+// without touching any cloud account.
+package clusterconfig
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+const (
+	// AROMasterSubnet and AROWorkerSubnet are the subnets created in the ARO VNet
+	AROMasterSubnet = "master-subnet"
+	AROWorkerSubnet = "worker-subnet"
+
+	// TrustBundleFile is the work-dir file holding the additional trust
+	// bundle for installers that take it as a file
+	TrustBundleFile = "additional-trust-bundle.pem"
+)
+
+// InstallConfigRequest builds the renderer request for an OpenShift IPI cluster
+func InstallConfigRequest(cluster *types.Cluster) *types.CreateClusterRequest {
+	// Convert BaseDomain pointer to string
+	baseDomain := ""
+	if cluster.BaseDomain != nil {
+		baseDomain = *cluster.BaseDomain
+	}
+
+	return &types.CreateClusterRequest{
+		Name:              cluster.Name,
+		Platform:          string(cluster.Platform),
+		Version:           cluster.Version,
+		Profile:           cluster.Profile,
+		Region:            cluster.Region,
+		BaseDomain:        baseDomain,
+		Owner:             cluster.Owner,
+		Team:              cluster.Team,
+		CostCenter:        cluster.CostCenter,
+		TTLHours:          cluster.TTLHours,
+		SSHPublicKey:      cluster.SSHPublicKey,
+		ExtraTags:         cluster.RequestTags,
+		OffhoursOptIn:     cluster.OffhoursOptIn,
+		CredentialsMode:   cluster.CredentialsMode,
+		Overrides:         cluster.ComputeOverrides,
+		RestrictedNetwork: cluster.RestrictedNetwork,
+		Architecture:      cluster.Architecture,
+	}
+}
+
+// EKS builds the eksctl ClusterConfig for a cluster. sshKeyPath is the file
+// holding the cluster's SSH public key; empty disables SSH on the node groups.
+func EKS(cluster *types.Cluster, prof *profile.Profile, sshKeyPath string) *installer.EKSClusterConfig {
+	eksConfig := &installer.EKSClusterConfig{
+		APIVersion: "eksctl.io/v1alpha5",
+		Kind:       "ClusterConfig",
+		Metadata: installer.EKSMetadata{
+			Name:    cluster.Name,
+			Region:  cluster.Region,
+			Version: cluster.Version,
+			Tags:    cluster.EffectiveTags,
+		},
+		IAM: &installer.EKSIAM{
+			WithOIDC: true, // Enable OIDC provider for IRSA
+		},
+		NodeGroups: []installer.EKSNodeGroup{},
+	}
+
+	var ssh *installer.EKSNodeGroupSSH
+	if sshKeyPath != "" {
+		ssh = &installer.EKSNodeGroupSSH{
+			Allow:         true,
+			PublicKeyPath: sshKeyPath,
+		}
+	}
+
+	// Add node groups from profile (unmanaged)
+	for _, ng := range prof.Compute.NodeGroups {
+		group := installer.EKSNodeGroup{
+			Name:            ng.Name,
+			InstanceType:    ng.InstanceType,
+			DesiredCapacity: ng.DesiredCapacity,
+			MinSize:         ng.MinSize,
+			MaxSize:         ng.MaxSize,
+			VolumeSize:      ng.VolumeSize,
+			VolumeType:      ng.VolumeType,
+			Tags:            cluster.EffectiveTags,
+			SSH:             ssh,
+		}
+		// Spot groups become an all-spot mixed-instance auto scaling group
+		if ng.Spot != nil {
+			group.InstanceType = ""
+			group.InstancesDistribution = &installer.EKSInstancesDistribution{
+				InstanceTypes:          eksSpotInstanceTypes(ng),
+				SpotAllocationStrategy: "price-capacity-optimized",
+			}
+			if price, err := profile.ParseSpotMaxPrice(ng.Spot); err == nil && price > 0 {
+				group.InstancesDistribution.MaxPrice = &price
+			}
+		}
+		eksConfig.NodeGroups = append(eksConfig.NodeGroups, group)
+	}
+
+	// Add managed node groups from profile (EKS-managed)
+	for _, ng := range prof.Compute.ManagedNodeGroups {
+		group := installer.EKSManagedNodeGroup{
+			Name:            ng.Name,
+			InstanceType:    ng.InstanceType,
+			DesiredCapacity: ng.DesiredCapacity,
+			MinSize:         ng.MinSize,
+			MaxSize:         ng.MaxSize,
+			VolumeSize:      ng.VolumeSize,
+			VolumeType:      ng.VolumeType,
+			AMIFamily:       ng.AMIFamily,
+			Tags:            cluster.EffectiveTags,
+			SSH:             ssh,
+		}
+		// Managed groups take capacityType SPOT; eksctl rejects instanceType
+		// alongside instanceTypes
+		if ng.Spot != nil {
+			group.Spot = true
+			group.InstanceType = ""
+			group.InstanceTypes = eksSpotInstanceTypes(ng)
+		}
+		eksConfig.ManagedNodeGroups = append(eksConfig.ManagedNodeGroups, group)
+	}
+
+	// Set VPC configuration from profile
+	if prof.Networking != nil && prof.Networking.VpcCIDR != "" {
+		eksConfig.VPC = &installer.EKSVPC{
+			CIDR: prof.Networking.VpcCIDR,
+		}
+		// Set NAT gateway mode
+		if prof.Networking.NatGateway != "" {
+			eksConfig.VPC.NAT = &installer.EKSNAT{
+				Gateway: prof.Networking.NatGateway,
+			}
+		}
+	}
+
+	return eksConfig
+}
+
+// eksSpotInstanceTypes returns the instance types a spot node group may use,
+// its own type first
+func eksSpotInstanceTypes(ng profile.NodeGroupConfig) []string {
+	instanceTypes := []string{ng.InstanceType}
+	for _, it := range ng.Spot.InstanceTypes {
+		if it != ng.InstanceType {
+			instanceTypes = append(instanceTypes, it)
+		}
+	}
+	return instanceTypes
+}
+
+// ROSAArgs builds the rosa create cluster arguments. version must already be
+// resolved to a full patch release; rosa rejects a bare minor version.
+// trustBundlePath is the file holding the additional trust bundle, if any.
+func ROSAArgs(cluster *types.Cluster, prof *profile.Profile, version, trustBundlePath string) []string {
+	args := []string{
+		"--cluster-name", cluster.Name,
+		"--region", cluster.Region,
+		"--version", version,
+		"--yes", // Auto-approve
+	}
+
+	// Add compute configuration from profile
+	if workers := prof.Compute.Workers; workers != nil {
+		if workers.Replicas > 0 {
+			args = append(args, "--replicas", fmt.Sprintf("%d", workers.Replicas))
+		}
+		if workers.InstanceType != "" {
+			args = append(args, "--compute-machine-type", workers.InstanceType)
+		}
+		if workers.RootVolumeGB > 0 {
+			args = append(args, "--worker-disk-size", fmt.Sprintf("%dGiB", workers.RootVolumeGB))
+		}
+	}
+
+	// Add multi-AZ and BYO VPC subnets if specified in profile
+	if rosa := prof.PlatformConfig.ROSA; rosa != nil {
+		if rosa.MultiAZ {
+			args = append(args, "--multi-az")
+		}
+		if len(rosa.SubnetIDs) > 0 {
+			args = append(args, "--subnet-ids", strings.Join(rosa.SubnetIDs, ","))
+		}
+	}
+
+	// Cluster-wide proxy and trust bundle (ROSA has no image mirror support)
+	if rn := profile.EffectiveRestrictedNetwork(prof, cluster.RestrictedNetwork); rn != nil {
+		if proxy := rn.Proxy; !proxy.IsEmpty() {
+			if proxy.HTTPProxy != "" {
+				args = append(args, "--http-proxy", proxy.HTTPProxy)
+			}
+			if proxy.HTTPSProxy != "" {
+				args = append(args, "--https-proxy", proxy.HTTPSProxy)
+			}
+			if proxy.NoProxy != "" {
+				args = append(args, "--no-proxy", proxy.NoProxy)
+			}
+		}
+		if rn.AdditionalTrustBundle != "" && trustBundlePath != "" {
+			args = append(args, "--additional-trust-bundle-file", trustBundlePath)
+		}
+	}
+
+	// Add tags (ROSA format: 'key value, key2 value2')
+	if len(cluster.EffectiveTags) > 0 {
+		tagStrs := make([]string, 0, len(cluster.EffectiveTags))
+		for _, k := range sortedKeys(cluster.EffectiveTags) {
+			tagStrs = append(tagStrs, fmt.Sprintf("%s %s", k, cluster.EffectiveTags[k]))
+		}
+		args = append(args, "--tags", strings.Join(tagStrs, ", "))
+	}
+
+	// Add STS mode with auto mode (ROSA auto-creates operator roles)
+	args = append(args, "--sts", "--mode", "auto")
+
+	return args
+}
+
+// GKE builds GKE cluster configuration from profile and cluster
+func GKE(cluster *types.Cluster, prof *profile.Profile) *installer.GKEClusterConfig {
+	config := &installer.GKEClusterConfig{
+		Name:    cluster.Name,
+		Project: GCPProject(prof),
+		Region:  cluster.Region,
+		Labels:  make(map[string]string),
+	}
+
+	// Add GKE-specific configuration from profile
+	if prof.PlatformConfig.GKE != nil {
+		gkeConfig := prof.PlatformConfig.GKE
+
+		// Set cluster version (Kubernetes version)
+		if cluster.Version != "" {
+			config.ClusterVersion = cluster.Version
+		}
+
+		// Set release channel
+		if gkeConfig.ReleaseChannel != "" {
+			config.ReleaseChannel = gkeConfig.ReleaseChannel
+		}
+
+		// Enable Workload Identity
+		if gkeConfig.EnableWorkloadIdentity {
+			config.EnableWorkloadIdentity = true
+		}
+
+		// Set logging
+		if len(gkeConfig.EnabledClusterLogTypes) > 0 {
+			config.EnableClusterLogging = gkeConfig.EnabledClusterLogTypes
+			// Also enable monitoring if logging is enabled
+			config.EnableClusterMonitoring = []string{"SYSTEM_COMPONENTS"}
+		}
+
+		// Set access configuration
+		config.PublicAccess = gkeConfig.PublicAccess
+		config.PrivateAccess = gkeConfig.PrivateAccess
+
+		// Configure node pools
+		if len(gkeConfig.NodePools) > 0 {
+			config.NodePools = make([]installer.GKENodePoolConfig, len(gkeConfig.NodePools))
+			for i, pool := range gkeConfig.NodePools {
+				config.NodePools[i] = installer.GKENodePoolConfig{
+					Name:              pool.Name,
+					MachineType:       pool.MachineType,
+					DiskType:          pool.DiskType,
+					DiskSize:          pool.DiskSizeGB,
+					NumNodes:          pool.NodeCount,
+					MinNodes:          pool.MinNodeCount,
+					MaxNodes:          pool.MaxNodeCount,
+					EnableAutoscaling: pool.EnableAutoScale,
+					Spot:              pool.Spot != nil,
+					Labels:            make(map[string]string),
+				}
+
+				// Add cluster tags to node pool labels (sanitized for GCP)
+				for k, v := range cluster.EffectiveTags {
+					config.NodePools[i].Labels[sanitizeGCPLabel(k)] = sanitizeGCPLabel(v)
+				}
+			}
+		}
+	}
+
+	// Add compute configuration from profile workers
+	if prof.Compute.Workers != nil {
+		workers := prof.Compute.Workers
+
+		// Set default node pool configuration if no node pools specified
+		if len(config.NodePools) == 0 {
+			config.MachineType = workers.MachineType
+			if workers.Autoscaling {
+				config.EnableAutoscaling = true
+				config.MinNodes = workers.MinReplicas
+				config.MaxNodes = workers.MaxReplicas
+				config.NumNodes = workers.MinReplicas // Start at minimum
+			} else {
+				config.NumNodes = workers.Replicas
+			}
+		}
+	}
+
+	// Add cluster labels for cost tracking and management. GCP labels are
+	// lowercase alphanumeric plus hyphens, so convert tags to a valid format.
+	for k, v := range cluster.EffectiveTags {
+		config.Labels[sanitizeGCPLabel(k)] = sanitizeGCPLabel(v)
+	}
+
+	// Add standard ocpctl labels
+	config.Labels["managed-by"] = "ocpctl"
+	config.Labels["cluster-id"] = cluster.ID
+	config.Labels["profile"] = sanitizeGCPLabel(cluster.Profile)
+
+	return config
+}
+
+// GCPProject extracts the GCP project ID from profile configuration, falling
+// back to the GCP_PROJECT environment variable
+func GCPProject(prof *profile.Profile) string {
+	// Check GCP config (for OpenShift on GCP)
+	if prof.PlatformConfig.GCP != nil && prof.PlatformConfig.GCP.Project != "" {
+		return prof.PlatformConfig.GCP.Project
+	}
+
+	// Fall back to environment variable
+	if project := os.Getenv("GCP_PROJECT"); project != "" {
+		return project
+	}
+
+	// Default to empty - will cause error in GCP API calls
+	log.Printf("Warning: GCP project ID not found in profile or GCP_PROJECT environment variable")
+	return ""
+}
+
+// AzureResourceGroup returns the resource group ocpctl creates for an ARO or
+// AKS cluster
+func AzureResourceGroup(clusterName string) string {
+	return fmt.Sprintf("ocpctl-%s-rg", clusterName)
+}
+
+// AROVNet returns the VNet ocpctl creates for an ARO cluster
+func AROVNet(clusterName string) string {
+	return fmt.Sprintf("%s-vnet", clusterName)
+}
+
+// AKS builds the AKS cluster configuration from profile and cluster
+func AKS(cluster *types.Cluster, prof *profile.Profile) (*installer.AKSClusterConfig, error) {
+	if prof.PlatformConfig.AKS == nil {
+		return nil, fmt.Errorf("profile missing AKS configuration")
+	}
+	aksConfig := prof.PlatformConfig.AKS
+
+	clusterConfig := &installer.AKSClusterConfig{
+		Name:              cluster.Name,
+		ResourceGroup:     AzureResourceGroup(cluster.Name),
+		Region:            cluster.Region,
+		KubernetesVersion: cluster.Version,
+		NetworkPlugin:     aksConfig.NetworkPlugin,
+		NetworkPolicy:     aksConfig.NetworkPolicy,
+		NodePools:         []installer.AKSNodePoolConfig{},
+		Tags:              cluster.EffectiveTags,
+	}
+
+	// Convert profile node pools to installer node pools
+	for _, pool := range aksConfig.NodePools {
+		np := installer.AKSNodePoolConfig{
+			Name:            pool.Name,
+			VMSize:          pool.VMSize,
+			Count:           pool.Count,
+			MinCount:        pool.MinCount,
+			MaxCount:        pool.MaxCount,
+			EnableAutoScale: pool.EnableAutoScale,
+			OSDiskSizeGB:    pool.OSDiskSizeGB,
+		}
+		if pool.Spot != nil {
+			np.Spot = true
+			np.SpotMaxPrice = pool.Spot.MaxPrice
+		}
+		clusterConfig.NodePools = append(clusterConfig.NodePools, np)
+	}
+
+	return clusterConfig, nil
+}
+
+// ARO builds the ARO cluster configuration from profile and cluster. version
+// must already be resolved to a full patch release. The pull secret and
+// service principal are credentials and are left for the caller to set.
+func ARO(cluster *types.Cluster, prof *profile.Profile, version string) (*installer.AROClusterConfig, error) {
+	if prof.PlatformConfig.ARO == nil {
+		return nil, fmt.Errorf("profile missing ARO configuration")
+	}
+	aroConfig := prof.PlatformConfig.ARO
+
+	return &installer.AROClusterConfig{
+		Name:             cluster.Name,
+		ResourceGroup:    AzureResourceGroup(cluster.Name),
+		Region:           cluster.Region,
+		MasterVMSize:     aroConfig.MasterVMSize,
+		WorkerVMSize:     aroConfig.WorkerVMSize,
+		WorkerCount:      aroConfig.WorkerCount,
+		WorkerDiskSizeGB: aroConfig.WorkerDiskSizeGB,
+		OpenShiftVersion: version,
+		Tags:             cluster.EffectiveTags,
+	}, nil
+}
+
+// IKS builds the IKS cluster create options from profile and cluster. VLANs
+// left empty or set to "auto" in the profile are returned as-is; the worker
+// resolves them against the zone before creating the cluster.
+func IKS(cluster *types.Cluster, prof *profile.Profile) (*installer.IKSClusterCreateOptions, error) {
+	zone := prof.Zones.Default
+	if zone == "" {
+		return nil, fmt.Errorf("no default zone configured in profile")
+	}
+
+	opts := &installer.IKSClusterCreateOptions{
+		Name:                   cluster.Name,
+		Zone:                   zone,
+		MachineType:            "bx2.4x16", // Default machine type
+		Workers:                2,          // Default worker count
+		KubeVersion:            cluster.Version,
+		PublicServiceEndpoint:  prof.Features.PublicServiceEndpoint,
+		PrivateServiceEndpoint: prof.Features.PrivateServiceEndpoint,
+	}
+
+	if workers := prof.Compute.Workers; workers != nil {
+		if workers.MachineType != "" {
+			opts.MachineType = workers.MachineType
+		}
+		if workers.Count > 0 {
+			opts.Workers = workers.Count
+		}
+		opts.PublicVLAN = workers.PublicVLAN
+		opts.PrivateVLAN = workers.PrivateVLAN
+	}
+
+	return opts, nil
+}
+
+// HCP builds the HostedCluster configuration from profile and cluster. The
+// version must be a full X.Y.Z release; it selects the release image. The
+// pull secret is a credential and is left for the caller to set.
+func HCP(cluster *types.Cluster, prof *profile.Profile) (*installer.HCPClusterConfig, error) {
+	hcpConfig := prof.PlatformConfig.HCP
+	if hcpConfig == nil {
+		return nil, fmt.Errorf("profile missing HCP configuration")
+	}
+
+	releaseImage, err := installer.HCPReleaseImage(cluster.Version, "")
+	if err != nil {
+		return nil, err
+	}
+
+	config := &installer.HCPClusterConfig{
+		Name:                         cluster.Name,
+		ReleaseImage:                 releaseImage,
+		ControllerAvailabilityPolicy: hcpConfig.ControllerAvailabilityPolicy,
+		Labels: map[string]string{
+			"ocpctl.io/managed":    "true",
+			"ocpctl.io/cluster-id": cluster.ID,
+		},
+	}
+	if cluster.BaseDomain != nil {
+		config.BaseDomain = *cluster.BaseDomain
+	}
+	if cluster.SSHPublicKey != nil {
+		config.SSHPublicKey = *cluster.SSHPublicKey
+	}
+	if n := prof.Networking; n != nil {
+		config.NetworkType = n.NetworkType
+		if len(n.ClusterNetworks) > 0 {
+			config.ClusterCIDR = n.ClusterNetworks[0].CIDR
+		}
+		if len(n.ServiceNetwork) > 0 {
+			config.ServiceCIDR = n.ServiceNetwork[0]
+		}
+	}
+
+	for _, pool := range hcpConfig.NodePools {
+		cores, memoryGiB, err := profile.ParseHCPInstanceType(pool.InstanceType)
+		if err != nil {
+			return nil, fmt.Errorf("node pool %s: %w", pool.Name, err)
+		}
+		config.NodePools = append(config.NodePools, installer.HCPNodePoolConfig{
+			Name:         pool.Name,
+			Replicas:     pool.Replicas,
+			Cores:        cores,
+			MemoryGiB:    memoryGiB,
+			RootVolumeGB: pool.RootVolumeGB,
+		})
+	}
+
+	return config, nil
+}
+
+// HCPNamespace returns the management-cluster namespace for a profile's
+// HostedClusters
+func HCPNamespace(prof *profile.Profile) string {
+	if prof.PlatformConfig.HCP != nil && prof.PlatformConfig.HCP.Namespace != "" {
+		return prof.PlatformConfig.HCP.Namespace
+	}
+	return installer.HCPDefaultNamespace
+}
+
+// Kind builds the kind cluster configuration for a cluster. KIND_API_SERVER_ADDRESS
+// binds the API server to a host address other than loopback, for API servers
+// that run on a different host than the worker.
+func Kind(cluster *types.Cluster, prof *profile.Profile) (*installer.KindClusterConfig, error) {
+	kindConfig := prof.PlatformConfig.Kind
+	if kindConfig == nil {
+		return nil, fmt.Errorf("profile missing kind configuration")
+	}
+
+	nodeImage := kindConfig.NodeImage
+	if nodeImage == "" {
+		var err error
+		if nodeImage, err = installer.KindNodeImage(cluster.Version); err != nil {
+			return nil, err
+		}
+	}
+
+	return &installer.KindClusterConfig{
+		Name:             cluster.Name,
+		NodeImage:        nodeImage,
+		Workers:          kindConfig.Workers,
+		APIServerAddress: os.Getenv("KIND_API_SERVER_ADDRESS"),
+	}, nil
+}
+
+// NeedsVLANResolution reports whether a VLAN setting is resolved at create time
+func NeedsVLANResolution(vlan string) bool {
+	return vlan == "" || vlan == "auto"
+}
+
+// sortedKeys returns the keys of a tag map in sorted order so the arguments
+// built from it are stable
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sanitizeGCPLabel converts a string to a valid GCP label value
+// GCP labels must be lowercase alphanumeric plus hyphens and underscores, max 63 chars
+// Keys must start with a lowercase letter
+func sanitizeGCPLabel(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	// Convert to lowercase
+	s = strings.ToLower(s)
+
+	// Replace invalid characters with hyphens
+	// Valid characters: lowercase letters, numbers, hyphens, underscores
+	var result strings.Builder
+	for i, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			result.WriteRune(r)
+		} else {
+			// Replace invalid characters with hyphen
+			result.WriteRune('-')
+		}
+
+		// For keys (first position), ensure it starts with a letter
+		// If first character is not a letter, prepend 'x'
+		if i == 0 && !(r >= 'a' && r <= 'z') {
+			// Move to beginning
+			temp := result.String()
+			result.Reset()
+			result.WriteString("x")
+			result.WriteString(temp)
+		}
+	}
+
+	// Truncate to 63 characters max
+	sanitized := result.String()
+	if len(sanitized) > 63 {
+		sanitized = sanitized[:63]
+	}
+
+	// Remove trailing hyphens or underscores (GCP requirement)
+	sanitized = strings.TrimRight(sanitized, "-_")
+
+	return sanitized
+}
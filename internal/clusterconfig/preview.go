@@ -0,0 +1,210 @@
+package clusterconfig
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted stands in for secret values in a preview
+const Redacted = "REDACTED"
+
+// Preview renders the installer input for a cluster without creating it. prof
+// is the cluster's profile before compute overrides are applied; cluster must
+// carry the overrides and the effective tags the worker would see.
+func Preview(cluster *types.Cluster, prof *profile.Profile) (*types.ClusterPreview, error) {
+	shaped, err := profile.ApplyOverrides(prof, cluster.ComputeOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("apply compute overrides: %w", err)
+	}
+
+	// Proxy URLs may carry credentials, which the preview must not show
+	redacted := *cluster
+	redacted.RestrictedNetwork = redactProxyCredentials(profile.EffectiveRestrictedNetwork(shaped, cluster.RestrictedNetwork))
+	cluster = &redacted
+
+	preview := &types.ClusterPreview{
+		Name:         cluster.Name,
+		Platform:     cluster.Platform,
+		ClusterType:  cluster.ClusterType,
+		Profile:      cluster.Profile,
+		ArtifactType: types.PreviewArtifactCommand,
+		MergedTags:   cluster.EffectiveTags,
+	}
+
+	switch cluster.ClusterType {
+	case "", types.ClusterTypeOpenShift:
+		installConfig, err := profile.RenderInstallConfigForProfile(prof, InstallConfigRequest(cluster), Redacted, cluster.EffectiveTags)
+		if err != nil {
+			return nil, fmt.Errorf("render install-config: %w", err)
+		}
+		preview.ArtifactType = types.PreviewArtifactInstallConfig
+		preview.Artifact = string(installConfig)
+
+	case types.ClusterTypeEKS:
+		sshKeyPath := ""
+		if cluster.SSHPublicKey != nil && *cluster.SSHPublicKey != "" {
+			sshKeyPath = "ssh-key.pub"
+		}
+		data, err := yaml.Marshal(EKS(cluster, shaped, sshKeyPath))
+		if err != nil {
+			return nil, fmt.Errorf("marshal eksctl config: %w", err)
+		}
+		preview.ArtifactType = types.PreviewArtifactEksctlConfig
+		preview.Artifact = string(data)
+
+	case types.ClusterTypeROSA:
+		preview.Commands = [][]string{
+			append([]string{"rosa", "create", "cluster"}, ROSAArgs(cluster, shaped, cluster.Version, TrustBundleFile)...),
+		}
+		if isMinorVersion(cluster.Version) {
+			preview.Notes = append(preview.Notes, fmt.Sprintf("--version %s is resolved to the latest supported %s.z patch at create time", cluster.Version, cluster.Version))
+		}
+
+	case types.ClusterTypeGKE:
+		config := GKE(cluster, shaped)
+		args, err := config.CreateArgs()
+		if err != nil {
+			return nil, fmt.Errorf("build gcloud arguments: %w", err)
+		}
+		preview.Commands = [][]string{append([]string{"gcloud"}, args...)}
+		for _, pool := range config.NodePools {
+			preview.Commands = append(preview.Commands,
+				append([]string{"gcloud"}, pool.CreateArgs(config.Name, config.Project, config.Region, config.Zone)...))
+		}
+		if config.Network == "" {
+			preview.Notes = append(preview.Notes, fmt.Sprintf("VPC network %s is created if it does not already exist", config.NetworkName()))
+		}
+
+	case types.ClusterTypeAKS:
+		config, err := AKS(cluster, shaped)
+		if err != nil {
+			return nil, err
+		}
+		args, err := config.CreateArgs()
+		if err != nil {
+			return nil, fmt.Errorf("build az arguments: %w", err)
+		}
+		preview.Commands = [][]string{append([]string{"az"}, args...)}
+		for _, pool := range config.NodePools[1:] {
+			preview.Commands = append(preview.Commands,
+				append([]string{"az"}, pool.CreateArgs(config.ResourceGroup, config.Name)...))
+		}
+		preview.Notes = append(preview.Notes, fmt.Sprintf("resource group %s is created before the cluster", config.ResourceGroup))
+
+	case types.ClusterTypeARO:
+		config, err := ARO(cluster, shaped, cluster.Version)
+		if err != nil {
+			return nil, err
+		}
+		config.PullSecret = Redacted
+		config.ClientID = Redacted
+		config.ClientSecret = Redacted
+		preview.Commands = [][]string{
+			append([]string{"az"}, config.CreateArgs(AROVNet(cluster.Name), AROMasterSubnet, AROWorkerSubnet)...),
+		}
+		preview.Notes = append(preview.Notes,
+			fmt.Sprintf("resource group %s and VNet %s are created before the cluster", config.ResourceGroup, AROVNet(cluster.Name)),
+			"a dedicated service principal is created for the cluster")
+		if rn := profile.EffectiveRestrictedNetwork(shaped, cluster.RestrictedNetwork); rn != nil {
+			preview.Notes = append(preview.Notes, "the cluster-wide proxy and trust bundle are applied to the cluster once it is ready")
+		}
+		if isMinorVersion(cluster.Version) {
+			preview.Notes = append(preview.Notes, fmt.Sprintf("--version %s is resolved to the latest available %s.z patch at create time", cluster.Version, cluster.Version))
+		}
+
+	case types.ClusterTypeIKS:
+		opts, err := IKS(cluster, shaped)
+		if err != nil {
+			return nil, err
+		}
+		preview.Commands = [][]string{append([]string{"ibmcloud"}, opts.CreateArgs()...)}
+		if NeedsVLANResolution(opts.PublicVLAN) || NeedsVLANResolution(opts.PrivateVLAN) {
+			preview.Notes = append(preview.Notes, fmt.Sprintf("VLANs not set in the profile are resolved for zone %s at create time", opts.Zone))
+		}
+
+	case types.ClusterTypeHCP:
+		config, err := HCP(cluster, shaped)
+		if err != nil {
+			return nil, err
+		}
+		config.PullSecret = Redacted
+		objects, err := config.Objects(HCPNamespace(shaped))
+		if err != nil {
+			return nil, err
+		}
+		data, err := installer.RenderObjects(objects)
+		if err != nil {
+			return nil, fmt.Errorf("render hosted cluster manifests: %w", err)
+		}
+		preview.ArtifactType = types.PreviewArtifactHostedCluster
+		preview.Artifact = string(data)
+		preview.Notes = append(preview.Notes, "manifests are applied to the HCP management cluster")
+
+	case types.ClusterTypeKind:
+		config, err := Kind(cluster, shaped)
+		if err != nil {
+			return nil, err
+		}
+		data, err := config.Render()
+		if err != nil {
+			return nil, fmt.Errorf("render kind config: %w", err)
+		}
+		preview.ArtifactType = types.PreviewArtifactKindConfig
+		preview.Artifact = string(data)
+		preview.Notes = append(preview.Notes, "the cluster runs as containers on the worker host")
+
+	default:
+		return nil, fmt.Errorf("unsupported cluster type: %s", cluster.ClusterType)
+	}
+
+	if rn := profile.EffectiveRestrictedNetwork(shaped, cluster.RestrictedNetwork); rn != nil && len(rn.ImageMirrors) > 0 {
+		preview.Notes = append(preview.Notes, "mirror registries are checked for reachability from the worker before install")
+	}
+	if n := len(shaped.AdditionalMachinePools); n > 0 {
+		preview.Notes = append(preview.Notes, fmt.Sprintf("%d additional machine pool(s) are created once the cluster is ready", n))
+	}
+
+	return preview, nil
+}
+
+// redactProxyCredentials returns rn with the userinfo of its proxy URLs
+// replaced by Redacted
+func redactProxyCredentials(rn *types.RestrictedNetwork) *types.RestrictedNetwork {
+	if rn == nil || rn.Proxy.IsEmpty() {
+		return rn
+	}
+	proxy := *rn.Proxy
+	proxy.HTTPProxy = redactUserinfo(proxy.HTTPProxy)
+	proxy.HTTPSProxy = redactUserinfo(proxy.HTTPSProxy)
+	out := *rn
+	out.Proxy = &proxy
+	return &out
+}
+
+// redactUserinfo replaces the user and password of a URL with Redacted. A
+// value that does not parse is redacted whole if it could hold credentials.
+func redactUserinfo(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		if strings.Contains(raw, "@") {
+			return Redacted
+		}
+		return raw
+	}
+	if u.User == nil {
+		return raw
+	}
+	u.User = url.User(Redacted)
+	return u.String()
+}
+
+// isMinorVersion reports whether a version is MAJOR.MINOR without a patch
+func isMinorVersion(version string) bool {
+	return strings.Count(version, ".") == 1
+}
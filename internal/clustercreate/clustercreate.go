@@ -0,0 +1,572 @@
+// Package clustercreate validates cluster create requests and builds the
+// cluster records they create. POST /clusters, POST /clusters/preview and
+// cluster specs synced from Git share it, so a cluster is checked the same
+// way whichever path creates it.
+package clustercreate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsanders-rh/ocpctl/internal/addon"
+	"github.com/tsanders-rh/ocpctl/internal/policy"
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/internal/validation"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// Creator validates create requests and builds cluster records
+type Creator struct {
+	store     *store.Store
+	policy    *policy.Engine
+	registry  *profile.Registry
+	providers *provider.Registry
+}
+
+// New creates a Creator
+func New(st *store.Store, policyEngine *policy.Engine, profileRegistry *profile.Registry) *Creator {
+	return &Creator{
+		store:     st,
+		policy:    policyEngine,
+		registry:  profileRegistry,
+		providers: provider.NewRegistry(),
+	}
+}
+
+// RequestError is a create request rejected by validation. The API responds
+// with 422 when Validation is set, 403 when Forbidden is set and 400
+// otherwise.
+type RequestError struct {
+	Message    string
+	Forbidden  bool
+	Validation *policy.ValidationResult
+}
+
+func (e *RequestError) Error() string {
+	if e.Validation != nil {
+		return e.Validation.FirstError()
+	}
+	return e.Message
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &RequestError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Validated holds what Validate derived from a create request
+type Validated struct {
+	Profile    *profile.Profile
+	TTL        int
+	Validation *policy.ValidationResult
+}
+
+// Validate runs the checks every create path shares: platform/cluster type
+// combination, base domain, policy validation against the profile, team
+// profile restrictions, custom post-config and pull secret, and infra ID
+// collisions. Team profile restrictions are skipped unless checkTeamProfiles
+// is set, which callers do for everyone but admins.
+func (cr *Creator) Validate(ctx context.Context, req *types.CreateClusterAPIRequest, checkTeamProfiles bool) (*Validated, error) {
+	platform := types.Platform(req.Platform)
+	clusterType := types.ClusterType(req.ClusterType)
+
+	// Validate platform and cluster type combinations against the cluster
+	// type capabilities
+	caps, ok := cr.providers.Capabilities(clusterType)
+	if !ok {
+		return nil, badRequest("Unsupported cluster_type: %s", req.ClusterType)
+	}
+	if len(cr.providers.ClusterTypesFor(platform)) == 0 {
+		return nil, badRequest("Unsupported platform: %s", req.Platform)
+	}
+	if !caps.SupportsPlatform(platform) {
+		return nil, badRequest("Invalid cluster_type %s for platform %s", req.ClusterType, req.Platform)
+	}
+
+	// base_domain is required where the cluster publishes DNS under it
+	// (OpenShift IPI, HCP) and rejected where DNS is managed elsewhere
+	switch caps.BaseDomain {
+	case provider.BaseDomainRequired:
+		if req.BaseDomain == "" {
+			return nil, badRequest("base_domain is required for %s clusters", caps.DisplayName)
+		}
+	case provider.BaseDomainUnsupported:
+		if req.BaseDomain != "" {
+			msg := fmt.Sprintf("base_domain is not supported for %s clusters", caps.DisplayName)
+			if caps.ManagedDNS != "" {
+				msg += fmt.Sprintf(" (%s)", caps.ManagedDNS)
+			}
+			return nil, badRequest("%s", msg)
+		}
+	}
+
+	// Custom post-config runs as a POST_CONFIGURE job the cluster type must support
+	if req.CustomPostConfig != nil && !caps.PostConfigure {
+		return nil, badRequest("custom_post_config is not supported for %s clusters", caps.DisplayName)
+	}
+
+	// Load profile from database (used for validation and default values)
+	prof, err := cr.store.GetProfile(ctx, req.Profile)
+	if err != nil {
+		return nil, badRequest("Invalid profile: %v", err)
+	}
+
+	ttl := prof.Lifecycle.DefaultTTLHours
+	if req.TTLHours != nil {
+		ttl = *req.TTLHours
+	}
+
+	policyReq := &types.CreateClusterRequest{
+		Name:              req.Name,
+		Platform:          req.Platform,
+		ClusterType:       req.ClusterType,
+		Version:           req.Version,
+		Profile:           req.Profile,
+		Region:            req.Region,
+		BaseDomain:        req.BaseDomain,
+		Owner:             req.Owner,
+		Team:              req.Team,
+		CostCenter:        req.CostCenter,
+		TTLHours:          ttl,
+		SSHPublicKey:      req.SSHPublicKey,
+		ExtraTags:         req.ExtraTags,
+		OffhoursOptIn:     req.OffhoursOptIn,
+		CredentialsMode:   req.CredentialsMode,
+		PreserveOnFailure: req.PreserveOnFailure,
+		Overrides:         req.ComputeOverrides,
+		RestrictedNetwork: req.RestrictedNetwork,
+		Architecture:      req.Architecture,
+	}
+
+	result := cr.policy.ValidateWithProfile(policyReq, prof)
+	if !result.Valid {
+		log.Printf("[ERROR] Policy validation failed: %+v", result)
+		return nil, &RequestError{Validation: result}
+	}
+
+	// Validate profile is allowed for the team
+	if checkTeamProfiles {
+		if team, err := cr.store.Teams.Get(ctx, req.Team); err == nil && len(team.AllowedProfiles) > 0 {
+			profileAllowed := false
+			for _, allowedProfile := range team.AllowedProfiles {
+				if allowedProfile == req.Profile {
+					profileAllowed = true
+					break
+				}
+			}
+			if !profileAllowed {
+				return nil, badRequest("Profile '%s' is not allowed for team '%s'", req.Profile, req.Team)
+			}
+		}
+	}
+
+	// Validate custom post-config if provided
+	if req.CustomPostConfig != nil {
+		if errs := validation.ValidateCustomPostConfig(req.CustomPostConfig); len(errs) > 0 {
+			log.Printf("[ERROR] Custom post-config validation failed: %v", errs)
+			return nil, badRequest("%s", errs[0].Error())
+		}
+		// Secret references resolve with the cluster's team, so they may only
+		// name that team's secrets
+		if errs := validation.ValidateSecretRefTeams(req.CustomPostConfig, req.Team); len(errs) > 0 {
+			return nil, &RequestError{Message: errs[0].Error(), Forbidden: true}
+		}
+	}
+
+	// Validate custom pull secret if provided
+	if req.CustomPullSecret != nil && *req.CustomPullSecret != "" {
+		var pullSecretData map[string]interface{}
+		if err := json.Unmarshal([]byte(*req.CustomPullSecret), &pullSecretData); err != nil {
+			log.Printf("[ERROR] Invalid custom pull secret JSON: %v", err)
+			return nil, badRequest("custom_pull_secret must be valid JSON")
+		}
+
+		// Validate that it has the expected structure: {"auths": {...}}
+		auths, hasAuths := pullSecretData["auths"]
+		if !hasAuths {
+			return nil, badRequest("custom_pull_secret must have an 'auths' field")
+		}
+		if _, ok := auths.(map[string]interface{}); !ok {
+			return nil, badRequest("custom_pull_secret 'auths' field must be an object")
+		}
+	}
+
+	// Check for potential infra ID collisions with recently destroyed clusters
+	if err := cr.checkInfraIDCollision(ctx, req.Name, platform); err != nil {
+		log.Printf("[ERROR] Infra ID collision detected: %v", err)
+		return nil, badRequest("%s", err.Error())
+	}
+
+	return &Validated{
+		Profile:    prof,
+		TTL:        ttl,
+		Validation: result,
+	}, nil
+}
+
+// Build builds the record of the cluster a validated request creates, owned
+// by ownerID. Add-ons and the post-deployment status are left to the caller.
+func (cr *Creator) Build(req *types.CreateClusterAPIRequest, ownerID string, checked *Validated) (*types.Cluster, error) {
+	// Parse destroy_at timestamp (empty means infinite TTL)
+	var destroyAt *time.Time
+	if checked.Validation.DestroyAt != "" {
+		parsedTime, err := time.Parse(time.RFC3339, checked.Validation.DestroyAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destroy_at timestamp: %w", err)
+		}
+		destroyAt = &parsedTime
+	}
+
+	now := time.Now()
+	cluster := &types.Cluster{
+		ID:                 uuid.New().String(),
+		Name:               req.Name,
+		Platform:           types.Platform(req.Platform),
+		ClusterType:        types.ClusterType(req.ClusterType),
+		Version:            req.Version,
+		Profile:            req.Profile,
+		Region:             req.Region,
+		BaseDomain:         cr.BaseDomain(req),
+		Status:             types.ClusterStatusPending,
+		Owner:              req.Owner,
+		OwnerID:            ownerID,
+		Team:               req.Team,
+		CostCenter:         req.CostCenter,
+		TTLHours:           checked.TTL,
+		RequestTags:        checked.Validation.MergedTags,
+		SSHPublicKey:       req.SSHPublicKey,
+		DestroyAt:          destroyAt,
+		OffhoursOptIn:      req.OffhoursOptIn,
+		SkipPostDeployment: req.SkipPostDeployment,
+		CustomPostConfig:   req.CustomPostConfig,
+		PreserveOnFailure:  req.PreserveOnFailure,
+		CredentialsMode:    req.CredentialsMode,
+		CustomPullSecret:   req.CustomPullSecret,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	// Stamp ocpctl provenance tags now that the cluster UUID exists
+	cluster.EffectiveTags = EffectiveTags(checked.Validation.MergedTags, cluster)
+
+	// Only persist compute overrides that actually change the profile shape, so
+	// a nil column keeps meaning "profile defaults".
+	if !req.ComputeOverrides.IsEmpty() {
+		cluster.ComputeOverrides = req.ComputeOverrides
+	}
+	if !req.RestrictedNetwork.IsEmpty() {
+		cluster.RestrictedNetwork = req.RestrictedNetwork
+	}
+	if !req.Architecture.IsEmpty() {
+		cluster.Architecture = req.Architecture
+	}
+
+	// Handle work hours override if provided
+	if req.WorkHoursEnabled != nil {
+		cluster.WorkHoursEnabled = req.WorkHoursEnabled
+
+		// If work hours are provided, parse and store them
+		if req.WorkHours != nil {
+			// Parse "09:00" format to time.Time
+			startTime, err := time.Parse("15:04", req.WorkHours.StartTime)
+			if err != nil {
+				return nil, badRequest("invalid work hours start time format, use HH:MM")
+			}
+			endTime, err := time.Parse("15:04", req.WorkHours.EndTime)
+			if err != nil {
+				return nil, badRequest("invalid work hours end time format, use HH:MM")
+			}
+
+			// Convert day names to bitmask
+			workDaysMask := types.WorkDaysFromStrings(req.WorkHours.WorkDays)
+			if workDaysMask == 0 {
+				return nil, badRequest("at least one work day must be selected")
+			}
+
+			cluster.WorkHoursStart = &startTime
+			cluster.WorkHoursEnd = &endTime
+			cluster.WorkDays = &workDaysMask
+		}
+	}
+
+	return cluster, nil
+}
+
+// ResolveAddons merges the profile's default add-ons with the add-ons
+// selected in a create request, drops profile defaults that conflict with a
+// selection, are disabled or do not run on the cluster's architectures, type
+// or version, and adds the add-ons the result requires. Selected and required
+// add-ons that are disabled or do not run on the cluster are rejected. cluster.SelectedAddonIDs is set to the
+// resolved refs in dependency order.
+func (cr *Creator) ResolveAddons(ctx context.Context, req *types.CreateClusterAPIRequest, cluster *types.Cluster, prof *profile.Profile) (*addon.Resolution, error) {
+	if len(req.PostConfigAddOns) > 0 {
+		// Validate add-on selections have required fields
+		for _, selection := range req.PostConfigAddOns {
+			if selection.ID == "" {
+				return nil, badRequest("add-on ID is required")
+			}
+			if selection.Version == "" {
+				return nil, badRequest("version is required for add-on '%s'", selection.ID)
+			}
+		}
+	}
+
+	// Validate no conflicting addons are selected (check ALL addons: profile defaults + user selections)
+	// Build merged list of all addons that will be installed
+	type addonInfo struct {
+		ID       string
+		Version  string
+		Metadata *types.AddonMetadata
+		Addon    *types.PostConfigAddon
+		Source   string // "profile" or "user"
+	}
+	allAddons := make(map[string]addonInfo)
+
+	// Add profile default addons
+	if len(prof.DefaultAddons) > 0 {
+		for _, addonRef := range prof.DefaultAddons {
+			resolved, err := cr.store.PostConfigAddons.GetByAddonIDAndVersion(ctx, addonRef.AddonID, addonRef.Version)
+			if err != nil {
+				log.Printf("Warning: failed to resolve profile default addon '%s' version '%s': %v", addonRef.AddonID, addonRef.Version, err)
+				continue
+			}
+			allAddons[addonRef.AddonID] = addonInfo{
+				ID:       addonRef.AddonID,
+				Version:  addonRef.Version,
+				Metadata: resolved.Metadata,
+				Addon:    resolved,
+				Source:   "profile",
+			}
+		}
+	}
+
+	// Add user-selected addons (overrides profile defaults if same ID)
+	for _, selection := range req.PostConfigAddOns {
+		resolved, err := cr.store.PostConfigAddons.GetByAddonIDAndVersion(ctx, selection.ID, selection.Version)
+		if err != nil {
+			return nil, badRequest("failed to resolve add-on '%s' version '%s': %v", selection.ID, selection.Version, err)
+		}
+		allAddons[selection.ID] = addonInfo{
+			ID:       selection.ID,
+			Version:  selection.Version,
+			Metadata: resolved.Metadata,
+			Addon:    resolved,
+			Source:   "user",
+		}
+	}
+
+	// Smart conflict resolution: Auto-exclude profile defaults that conflict with user selections
+	// This allows users to override conflicting defaults by selecting an alternative
+	// while still keeping non-conflicting defaults
+	for addonID, info := range allAddons {
+		if info.Source != "user" || info.Metadata == nil {
+			continue
+		}
+
+		// Check if this user-selected addon conflicts with any profile defaults
+		for _, conflictingID := range info.Metadata.ConflictsWith {
+			if conflictingInfo, exists := allAddons[conflictingID]; exists && conflictingInfo.Source == "profile" {
+				// User explicitly selected an addon that conflicts with a profile default
+				// Remove the profile default to honor user's choice
+				log.Printf("Auto-excluding profile default addon '%s' because user selected conflicting addon '%s'",
+					conflictingID, addonID)
+				delete(allAddons, conflictingID)
+			}
+		}
+	}
+
+	// Check for conflicts in the final merged addon list (only user-user conflicts remain)
+	for addonID, info := range allAddons {
+		if info.Metadata == nil || len(info.Metadata.ConflictsWith) == 0 {
+			continue
+		}
+
+		for _, conflictingID := range info.Metadata.ConflictsWith {
+			if _, exists := allAddons[conflictingID]; exists {
+				// Found a conflict between two user-selected addons
+				// (profile defaults that conflicted were already removed above)
+				return nil, badRequest("addon conflict: '%s' conflicts with '%s'. Please deselect one of these addons.",
+					addonID, conflictingID)
+			}
+		}
+	}
+
+	// Add-ons must be enabled and have images for every node architecture in
+	// the cluster. Unusable profile defaults are dropped; user selections are
+	// rejected.
+	nodeArchs := profile.NodeArchitectures(prof, profile.EffectiveArchitecture(prof, req.Architecture))
+	for addonID, info := range allAddons {
+		err := CheckAddonUsable(info.Addon, nodeArchs)
+		if err == nil {
+			continue
+		}
+		if info.Source == "profile" {
+			log.Printf("Auto-excluding profile default addon '%s': %v", addonID, err)
+			delete(allAddons, addonID)
+			continue
+		}
+		return nil, err
+	}
+
+	// Profile defaults that do not support the cluster's type and version are
+	// dropped; the policy engine rejects incompatible selections once the
+	// selection is resolved.
+	for addonID, info := range allAddons {
+		if info.Source != "profile" {
+			continue
+		}
+		if err := addon.CheckCompatibility(info.Metadata, cluster.ClusterType, cluster.Version); err != nil {
+			log.Printf("Auto-excluding profile default addon '%s': %v", addonID, err)
+			delete(allAddons, addonID)
+		}
+	}
+
+	// Pull in the add-ons the remaining selection requires. Selected IDs are
+	// sorted so the resolved order does not depend on map iteration.
+	selectedIDs := make([]string, 0, len(allAddons))
+	for addonID := range allAddons {
+		selectedIDs = append(selectedIDs, addonID)
+	}
+	sort.Strings(selectedIDs)
+	selection := make([]types.PostConfigAddon, 0, len(selectedIDs))
+	for _, addonID := range selectedIDs {
+		selection = append(selection, *allAddons[addonID].Addon)
+	}
+	cluster.SelectedAddonIDs = selectedIDs
+
+	catalog := addon.NewStoreCatalog(cr.store.PostConfigAddons, cluster.OwnerID)
+	resolution, err := addon.Resolve(ctx, catalog, selection, cr.AddonConditionContext(cluster))
+	if err != nil {
+		return nil, badRequest("addon dependency resolution failed: %v", err)
+	}
+	for _, dep := range resolution.Added {
+		for i := range resolution.Addons {
+			if resolution.Addons[i].AddonID != dep.AddonID {
+				continue
+			}
+			if err := CheckAddonUsable(&resolution.Addons[i], nodeArchs); err != nil {
+				return nil, badRequest("addon '%s' requires '%s': %v", dep.RequiredBy, dep.AddonID, err)
+			}
+		}
+		log.Printf("Adding addon '%s' version '%s' required by '%s'", dep.AddonID, dep.Version, dep.RequiredBy)
+	}
+
+	if validation := cr.policy.ValidateAddons(cluster.ClusterType, cluster.Version, resolution.Addons); !validation.Valid {
+		return nil, &RequestError{Validation: validation}
+	}
+
+	// Store refs in dependency order; the worker merges configs in this order
+	cluster.SelectedAddonIDs = resolution.Refs()
+	log.Printf("Final addon list after conflict and dependency resolution: %v", cluster.SelectedAddonIDs)
+
+	return resolution, nil
+}
+
+// CheckAddonUsable rejects an add-on that is disabled or does not support
+// every architecture in nodeArchs.
+func CheckAddonUsable(a *types.PostConfigAddon, nodeArchs []types.Architecture) error {
+	if !a.Enabled {
+		return badRequest("Add-on '%s' is disabled", a.AddonID)
+	}
+	if len(nodeArchs) > 0 && !a.Metadata.SupportsArchitectures(nodeArchs) {
+		return badRequest("addon '%s' does not support the cluster's architectures %v (supported: %v)",
+			a.AddonID, nodeArchs, a.Metadata.SupportedArchitectures)
+	}
+	return nil
+}
+
+// AddonConditionContext builds the context add-on requirement conditions are
+// evaluated against.
+func (cr *Creator) AddonConditionContext(cluster *types.Cluster) *postconfig.TemplateContext {
+	condCtx := postconfig.BuildTemplateContext(cluster, "", nil)
+	prof, err := cr.registry.GetAny(cluster.Profile)
+	if err != nil {
+		return condCtx
+	}
+	if effective, err := profile.ApplyOverrides(prof, cluster.ComputeOverrides); err == nil {
+		prof = effective
+	}
+	condCtx.ApplyProfile(prof)
+	return condCtx
+}
+
+// SetPostDeployStatus sets the cluster's initial post_deploy_status: pending
+// if the profile, the selected add-ons or custom post-config have anything to
+// run, and skipped otherwise so hibernation works immediately.
+func SetPostDeployStatus(cluster *types.Cluster, prof *profile.Profile) {
+	hasPostDeployment := (prof.PostDeployment != nil && (len(prof.PostDeployment.Operators) > 0 ||
+		len(prof.PostDeployment.Scripts) > 0 ||
+		len(prof.PostDeployment.Manifests) > 0 ||
+		len(prof.PostDeployment.HelmCharts) > 0)) ||
+		len(cluster.SelectedAddonIDs) > 0 ||
+		cluster.CustomPostConfig != nil
+
+	hasCustomPostConfig := cluster.CustomPostConfig != nil && (len(cluster.CustomPostConfig.Operators) > 0 ||
+		len(cluster.CustomPostConfig.Scripts) > 0 ||
+		len(cluster.CustomPostConfig.Manifests) > 0 ||
+		len(cluster.CustomPostConfig.HelmCharts) > 0)
+
+	status := "pending"
+	if cluster.SkipPostDeployment || (!hasPostDeployment && !hasCustomPostConfig) {
+		status = "skipped"
+	}
+	cluster.PostDeployStatus = &status
+}
+
+// BaseDomain returns the base domain to store for a new cluster. Only
+// cluster types that publish DNS under it keep one; managed Kubernetes
+// accepts base_domain but has no use for it.
+func (cr *Creator) BaseDomain(req *types.CreateClusterAPIRequest) *string {
+	caps, _ := cr.providers.Capabilities(types.ClusterType(req.ClusterType))
+	if caps.BaseDomain != provider.BaseDomainRequired || req.BaseDomain == "" {
+		return nil
+	}
+	return &req.BaseDomain
+}
+
+// EffectiveTags stamps ocpctl provenance tags onto the merged request tags.
+// These flow through EffectiveTags into install-config userTags, eksctl/rosa
+// tags, so every ocpctl-created resource can be traced back to the cluster.
+// The result is a distinct copy so RequestTags keeps the user's request.
+func EffectiveTags(merged types.Tags, cluster *types.Cluster) types.Tags {
+	tags := make(types.Tags, len(merged)+4)
+	for k, v := range merged {
+		tags[k] = v
+	}
+	for k, v := range types.ProvenanceTags(cluster.ID, cluster.Name, cluster.CreatedAt) {
+		tags[k] = v
+	}
+	return tags
+}
+
+// checkInfraIDCollision checks if the cluster name could collide with recently destroyed clusters
+// OpenShift generates infra IDs by truncating cluster names to ~27 chars + 5-char random suffix
+// Similar names can generate the same infra ID if created close together
+func (cr *Creator) checkInfraIDCollision(ctx context.Context, clusterName string, platform types.Platform) error {
+	// Only check for OpenShift clusters on AWS (where we've seen this issue)
+	if platform != types.PlatformAWS {
+		return nil
+	}
+
+	// Query for recently destroyed clusters with similar name prefixes
+	recentClusters, err := cr.store.Clusters.CheckInfraIDCollision(ctx, clusterName, platform)
+	if err != nil {
+		// Don't fail cluster creation if collision check fails - just log
+		log.Printf("[WARN] Failed to check infra ID collision: %v", err)
+		return nil
+	}
+
+	// If we found similar recently-destroyed clusters, return an error
+	if len(recentClusters) > 0 {
+		return fmt.Errorf("potential infra ID collision: cluster name '%s' is similar to recently destroyed cluster(s): %v. OpenShift may reuse the same infrastructure ID, causing creation to fail. Please choose a more distinct cluster name", clusterName, recentClusters)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package clustercreate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/policy"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestValidate_RejectsBeforeProfileLookup(t *testing.T) {
+	// These checks run before the store is touched, so no database is needed
+	cr := New(nil, policy.NewEngine(nil), nil)
+
+	tests := []struct {
+		name string
+		req  types.CreateClusterAPIRequest
+		want string
+	}{
+		{
+			name: "unknown cluster type",
+			req:  types.CreateClusterAPIRequest{Platform: "aws", ClusterType: "nomad"},
+			want: "Unsupported cluster_type: nomad",
+		},
+		{
+			name: "cluster type not on platform",
+			req:  types.CreateClusterAPIRequest{Platform: "gcp", ClusterType: "eks"},
+			want: "Invalid cluster_type eks for platform gcp",
+		},
+		{
+			name: "HCP without base domain",
+			req:  types.CreateClusterAPIRequest{Platform: "aws", ClusterType: "hcp"},
+			want: "base_domain is required for HCP clusters",
+		},
+		{
+			name: "kind with base domain",
+			req:  types.CreateClusterAPIRequest{Platform: "local", ClusterType: "kind", BaseDomain: "example.com"},
+			want: "base_domain is not supported for kind clusters",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cr.Validate(context.Background(), &tt.req, true)
+			require.Error(t, err)
+			var reqErr *RequestError
+			require.ErrorAs(t, err, &reqErr)
+			assert.False(t, reqErr.Forbidden)
+			assert.Nil(t, reqErr.Validation)
+			assert.Equal(t, tt.want, reqErr.Message)
+		})
+	}
+}
+
+func TestBaseDomain(t *testing.T) {
+	cr := New(nil, policy.NewEngine(nil), nil)
+
+	for clusterType, want := range map[string]bool{
+		"openshift": true,
+		"hcp":       true,
+		"rosa":      false,
+		"eks":       false,
+	} {
+		req := &types.CreateClusterAPIRequest{ClusterType: clusterType, BaseDomain: "example.com"}
+		got := cr.BaseDomain(req)
+		if want {
+			require.NotNil(t, got, clusterType)
+			assert.Equal(t, "example.com", *got, clusterType)
+		} else {
+			assert.Nil(t, got, clusterType)
+		}
+	}
+
+	assert.Nil(t, cr.BaseDomain(&types.CreateClusterAPIRequest{ClusterType: "openshift"}))
+}
+
+func TestBuild(t *testing.T) {
+	cr := New(nil, policy.NewEngine(nil), nil)
+	enabled := true
+	req := &types.CreateClusterAPIRequest{
+		Name:             "build-test",
+		Platform:         "aws",
+		ClusterType:      "hcp",
+		BaseDomain:       "example.com",
+		Team:             "platform",
+		WorkHoursEnabled: &enabled,
+		WorkHours: &types.WorkHoursSchedule{
+			StartTime: "09:00",
+			EndTime:   "17:00",
+			WorkDays:  []string{"Monday"},
+		},
+	}
+	checked := &Validated{
+		TTL: 8,
+		Validation: &policy.ValidationResult{
+			Valid:      true,
+			MergedTags: types.Tags{"team": "platform"},
+			DestroyAt:  time.Now().Add(8 * time.Hour).Format(time.RFC3339),
+		},
+	}
+
+	cluster, err := cr.Build(req, "owner-id", checked)
+	require.NoError(t, err)
+	assert.Equal(t, "owner-id", cluster.OwnerID)
+	assert.Equal(t, types.ClusterStatusPending, cluster.Status)
+	require.NotNil(t, cluster.BaseDomain)
+	assert.Equal(t, "example.com", *cluster.BaseDomain)
+	require.NotNil(t, cluster.DestroyAt)
+	require.NotNil(t, cluster.WorkDays)
+	assert.Equal(t, types.Tags{"team": "platform"}, cluster.RequestTags)
+	assert.Equal(t, "platform", cluster.EffectiveTags["team"])
+	assert.Greater(t, len(cluster.EffectiveTags), len(cluster.RequestTags))
+
+	req.WorkHours.WorkDays = nil
+	_, err = cr.Build(req, "owner-id", checked)
+	var reqErr *RequestError
+	require.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, "at least one work day must be selected", reqErr.Message)
+}
+
+func TestSetPostDeployStatus(t *testing.T) {
+	empty := &profile.Profile{}
+	withOperators := &profile.Profile{PostDeployment: &profile.PostDeploymentConfig{
+		Operators: []profile.OperatorConfig{{Name: "op"}},
+	}}
+
+	tests := []struct {
+		name    string
+		cluster types.Cluster
+		prof    *profile.Profile
+		want    string
+	}{
+		{name: "nothing to run", prof: empty, want: "skipped"},
+		{name: "profile post-deployment", prof: withOperators, want: "pending"},
+		{name: "resolved add-ons", cluster: types.Cluster{SelectedAddonIDs: []string{"a:v1"}}, prof: empty, want: "pending"},
+		{name: "skip requested", cluster: types.Cluster{SkipPostDeployment: true, SelectedAddonIDs: []string{"a:v1"}}, prof: withOperators, want: "skipped"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := tt.cluster
+			SetPostDeployStatus(&cluster, tt.prof)
+			require.NotNil(t, cluster.PostDeployStatus)
+			assert.Equal(t, tt.want, *cluster.PostDeployStatus)
+		})
+	}
+}
+
+func TestCheckAddonUsable(t *testing.T) {
+	amd64Only := &types.AddonMetadata{SupportedArchitectures: []types.Architecture{types.ArchitectureAMD64}}
+	arm64 := []types.Architecture{types.ArchitectureARM64}
+
+	assert.NoError(t, CheckAddonUsable(&types.PostConfigAddon{AddonID: "a", Enabled: true}, arm64))
+	assert.NoError(t, CheckAddonUsable(&types.PostConfigAddon{AddonID: "a", Enabled: true, Metadata: amd64Only}, nil))
+
+	err := CheckAddonUsable(&types.PostConfigAddon{AddonID: "a", Metadata: amd64Only}, nil)
+	var reqErr *RequestError
+	require.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, "Add-on 'a' is disabled", reqErr.Message)
+
+	err = CheckAddonUsable(&types.PostConfigAddon{AddonID: "a", Enabled: true, Metadata: amd64Only}, arm64)
+	require.ErrorAs(t, err, &reqErr)
+	assert.Contains(t, reqErr.Message, "does not support the cluster's architectures [arm64]")
+}
@@ -13,8 +13,9 @@ import (
 //
 // This is the shared implementation used by both the per-team costs endpoint and
 // the platform-wide usage report so cost figures stay consistent across surfaces.
+// Clusters created with compute overrides are priced on their overridden shape.
 func EffectiveHourlyCost(cluster *types.Cluster, prof *profile.Profile) float64 {
-	baseCost := prof.CostControls.EstimatedHourlyCost
+	baseCost := ShapeHourlyCost(prof, cluster.ComputeOverrides)
 
 	// If cluster is hibernated, calculate reduced cost based on cluster type
 	if cluster.Status == types.ClusterStatusHibernated {
@@ -35,6 +36,13 @@ func EffectiveHourlyCost(cluster *types.Cluster, prof *profile.Profile) float64
 			// GKE Standard: node pools scaled to 0, but the $0.10/hr cluster
 			// management fee continues (plus a little for persistent disks).
 			return 0.10
+		case types.ClusterTypeHCP:
+			// HCP: NodePools scaled to 0, but the hosted control plane pods keep
+			// running on the management cluster (~20%)
+			return baseCost * 0.20
+		case types.ClusterTypeKind:
+			// kind: node containers stopped on the worker host, nothing billed
+			return 0
 		default:
 			// Unknown cluster type, use conservative estimate
 			return baseCost * 0.10
@@ -27,6 +27,8 @@ func TestEffectiveHourlyCost(t *testing.T) {
 		{"hibernated rosa fixed", types.ClusterStatusHibernated, types.ClusterTypeROSA, 1.0, 0.03},
 		{"hibernated eks fixed", types.ClusterStatusHibernated, types.ClusterTypeEKS, 1.0, 0.10},
 		{"hibernated gke fixed mgmt fee", types.ClusterStatusHibernated, types.ClusterTypeGKE, 1.0, 0.10},
+		{"hibernated hcp control plane pods", types.ClusterStatusHibernated, types.ClusterTypeHCP, 1.0, 0.20},
+		{"hibernated kind nothing billed", types.ClusterStatusHibernated, types.ClusterTypeKind, 1.0, 0},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
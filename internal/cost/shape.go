@@ -0,0 +1,131 @@
+package cost
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+const (
+	// defaultNodeVCPUs is assumed for instance types whose size can't be parsed
+	defaultNodeVCPUs = 4
+
+	// defaultRootVolumeGB stands in for a node whose disk size is left to the
+	// platform default (the OpenShift installer default is 120GB)
+	defaultRootVolumeGB = 120
+
+	// diskCostPerGBHour approximates block storage at ~$0.10/GB-month
+	diskCostPerGBHour = 0.10 / 730
+
+	// spotDiscount is the typical saving of spot/preemptible nodes over
+	// on-demand. AWS, GCP and Azure all advertise up to 90%; 70% is a
+	// conservative average across common general-purpose instance types.
+	spotDiscount = 0.70
+)
+
+var (
+	awsSizeRegex   = regexp.MustCompile(`^(\d*)xlarge$`)
+	azureSizeRegex = regexp.MustCompile(`^Standard_[A-Za-z]+(\d+)`)
+	ibmSizeRegex   = regexp.MustCompile(`(\d+)x\d+$`)
+)
+
+// ShapeHourlyCost returns the profile's estimated hourly cost for the compute
+// shape produced by the overrides. The profile constant prices the profile's
+// default shape, so the override is priced relative to it: node cost scales
+// with total vCPUs (control plane included) and root disk is priced per GB.
+// The profile constant is an on-demand price, so spot worker vCPUs are
+// discounted by spotDiscount.
+func ShapeHourlyCost(prof *profile.Profile, o *types.ComputeOverrides) float64 {
+	base := prof.CostControls.EstimatedHourlyCost
+	if o.IsEmpty() && len(prof.SpotPools()) == 0 {
+		return base
+	}
+
+	shaped := prof
+	if !o.IsEmpty() {
+		var err error
+		if shaped, err = profile.ApplyOverrides(prof, o); err != nil {
+			return base
+		}
+	}
+
+	defaultVCPUs, defaultSpotVCPUs, defaultDisk := shapeTotals(prof)
+	defaultVCPUs += defaultSpotVCPUs
+	if defaultVCPUs == 0 {
+		return base
+	}
+	vcpus, spotVCPUs, disk := shapeTotals(shaped)
+	priced := float64(vcpus) + float64(spotVCPUs)*(1-spotDiscount)
+
+	cost := base*priced/float64(defaultVCPUs) + float64(disk-defaultDisk)*diskCostPerGBHour
+	if cost < 0 {
+		return 0
+	}
+	return cost
+}
+
+// shapeTotals sums on-demand vCPUs, spot vCPUs and worker root disk across a
+// profile's nodes
+func shapeTotals(p *profile.Profile) (vcpus, spotVCPUs, diskGB int) {
+	if cp, ok := p.ControlPlaneShape(); ok {
+		vcpus += cp.Replicas * instanceVCPUs(cp.InstanceType)
+	}
+	for _, s := range p.WorkerShapes() {
+		if s.Spot != nil {
+			spotVCPUs += s.Replicas * instanceVCPUs(s.InstanceType)
+		} else {
+			vcpus += s.Replicas * instanceVCPUs(s.InstanceType)
+		}
+		disk := s.RootVolumeGB
+		if disk == 0 {
+			disk = defaultRootVolumeGB
+		}
+		diskGB += s.Replicas * disk
+	}
+	return vcpus, spotVCPUs, diskGB
+}
+
+// instanceVCPUs estimates the vCPU count of an instance type from its name
+// across AWS (m6i.2xlarge), GCP (n2-standard-8), Azure (Standard_D8s_v3),
+// IBM Cloud (bx2-4x16 / bx2.4x16) and HCP KubeVirt (4x16) naming schemes
+func instanceVCPUs(instanceType string) int {
+	switch {
+	case strings.HasPrefix(instanceType, "Standard_"):
+		if m := azureSizeRegex.FindStringSubmatch(instanceType); m != nil {
+			return atoiOr(m[1], defaultNodeVCPUs)
+		}
+	case ibmSizeRegex.MatchString(instanceType):
+		return atoiOr(ibmSizeRegex.FindStringSubmatch(instanceType)[1], defaultNodeVCPUs)
+	case strings.Contains(instanceType, "."):
+		size := instanceType[strings.LastIndex(instanceType, ".")+1:]
+		switch size {
+		case "nano", "micro", "small", "medium":
+			return 1
+		case "large":
+			return 2
+		}
+		if m := awsSizeRegex.FindStringSubmatch(size); m != nil {
+			return 4 * atoiOr(m[1], 1)
+		}
+	case strings.Contains(instanceType, "-"):
+		parts := strings.Split(instanceType, "-")
+		if parts[0] == "custom" && len(parts) >= 2 {
+			return atoiOr(parts[1], defaultNodeVCPUs)
+		}
+		if parts[len(parts)-1] == "medium" || parts[len(parts)-1] == "small" || parts[len(parts)-1] == "micro" {
+			return 1
+		}
+		return atoiOr(parts[len(parts)-1], defaultNodeVCPUs)
+	}
+	return defaultNodeVCPUs
+}
+
+func atoiOr(s string, fallback int) int {
+	if n, err := strconv.Atoi(s); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
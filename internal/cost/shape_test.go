@@ -0,0 +1,88 @@
+package cost
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestInstanceVCPUs(t *testing.T) {
+	tests := map[string]int{
+		"m6i.large":       2,
+		"m6i.xlarge":      4,
+		"m6i.2xlarge":     8,
+		"m6i.24xlarge":    96,
+		"t3.medium":       1,
+		"n2-standard-8":   8,
+		"e2-medium":       1,
+		"custom-6-24576":  6,
+		"Standard_D8s_v3": 8,
+		"Standard_E16_v5": 16,
+		"bx2-4x16":        4,
+		"bx2.16x64":       16,
+		"8x32":            8,
+		"mystery":         defaultNodeVCPUs,
+	}
+	for it, want := range tests {
+		if got := instanceVCPUs(it); got != want {
+			t.Errorf("instanceVCPUs(%q) = %d, want %d", it, got, want)
+		}
+	}
+}
+
+func TestShapeHourlyCost(t *testing.T) {
+	p := &profile.Profile{
+		Platform: types.PlatformAWS,
+		Compute: profile.ComputeConfig{
+			ControlPlane: &profile.ControlPlaneConfig{Replicas: 3, InstanceType: "m6i.xlarge"},
+			Workers:      &profile.WorkersConfig{Replicas: 3, MaxReplicas: 10, InstanceType: "m6i.2xlarge"},
+		},
+		CostControls: profile.CostControlsConfig{EstimatedHourlyCost: 2.0},
+	}
+	six := 6
+	bigDisk := 220
+
+	// Default shape: 3x4 + 3x8 = 36 vCPUs
+	tests := []struct {
+		name string
+		o    *types.ComputeOverrides
+		want float64
+	}{
+		{"no overrides", nil, 2.0},
+		{"double workers", &types.ComputeOverrides{WorkerReplicas: &six}, 2.0*60/36 + 3*defaultRootVolumeGB*diskCostPerGBHour},
+		{"bigger instances", &types.ComputeOverrides{WorkerInstanceType: "m6i.4xlarge"}, 2.0 * 60 / 36},
+		{"extra pool", &types.ComputeOverrides{MachinePools: []types.MachinePoolOverride{{Name: "infra", InstanceType: "m6i.xlarge", Replicas: 3}}},
+			2.0*48/36 + 3*defaultRootVolumeGB*diskCostPerGBHour},
+		{"bigger disk", &types.ComputeOverrides{RootVolumeGB: &bigDisk}, 2.0 + 3*100*diskCostPerGBHour},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ShapeHourlyCost(p, tc.o)
+			if math.Abs(got-tc.want) > 1e-9 {
+				t.Fatalf("ShapeHourlyCost = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	// Spot workers are discounted; the on-demand fallback prices them in full
+	spot, err := p.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	spot.Compute.Workers.Spot = &profile.SpotConfig{OnDemandFallback: true}
+	if got, want := ShapeHourlyCost(spot, nil), 2.0*(12+24*(1-spotDiscount))/36; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("ShapeHourlyCost(spot) = %v, want %v", got, want)
+	}
+	if got := ShapeHourlyCost(spot, &types.ComputeOverrides{OnDemand: true}); math.Abs(got-2.0) > 1e-9 {
+		t.Fatalf("ShapeHourlyCost(on-demand fallback) = %v, want 2.0", got)
+	}
+
+	// Hibernated clusters are priced off the overridden shape too
+	cl := &types.Cluster{Status: types.ClusterStatusHibernated, ClusterType: types.ClusterTypeOpenShift,
+		ComputeOverrides: &types.ComputeOverrides{WorkerReplicas: &six}}
+	if got, want := EffectiveHourlyCost(cl, p), 0.10*(2.0*60/36+3*defaultRootVolumeGB*diskCostPerGBHour); math.Abs(got-want) > 1e-9 {
+		t.Fatalf("EffectiveHourlyCost = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+const (
+	// CloudEventsSpecVersion is the CloudEvents specification version emitted
+	CloudEventsSpecVersion = "1.0"
+	// CloudEventsContentType is the structured-mode JSON content type
+	CloudEventsContentType = "application/cloudevents+json"
+
+	// DefaultSource is the CloudEvents source used when none is configured
+	DefaultSource = "ocpctl"
+
+	// eventTypePrefix namespaces CloudEvents types, e.g. "com.ocpctl.cluster.status_changed"
+	eventTypePrefix = "com.ocpctl.cluster."
+)
+
+// CloudEvent is a CloudEvents 1.0 envelope in structured JSON mode.
+// ClusterStatus is an extension attribute carrying the new status so that
+// brokers and triggers can filter on READY or DESTROYED without reading data.
+type CloudEvent struct {
+	SpecVersion     string              `json:"specversion"`
+	ID              string              `json:"id"`
+	Source          string              `json:"source"`
+	Type            string              `json:"type"`
+	Subject         string              `json:"subject"`
+	Time            time.Time           `json:"time"`
+	DataContentType string              `json:"datacontenttype"`
+	ClusterStatus   string              `json:"clusterstatus"`
+	Data            *types.ClusterEvent `json:"data"`
+}
+
+// EventType returns the CloudEvents type for a cluster event type
+func EventType(t types.ClusterEventType) string {
+	return eventTypePrefix + strings.ToLower(string(t))
+}
+
+// NewCloudEvent wraps a cluster event in a CloudEvents envelope. The event ID
+// is the cluster event's database ID, so redelivered events can be deduplicated.
+func NewCloudEvent(source string, event *types.ClusterEvent) *CloudEvent {
+	return &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              strconv.FormatInt(event.ID, 10),
+		Source:          source,
+		Type:            EventType(event.Type),
+		Subject:         event.ClusterID,
+		Time:            event.CreatedAt,
+		DataContentType: "application/json",
+		ClusterStatus:   event.ToStatus,
+		Data:            event,
+	}
+}
+
+// HTTPPublisher delivers cluster events to an HTTP sink as CloudEvents
+type HTTPPublisher struct {
+	sinkURL string
+	source  string
+	token   string
+	client  *http.Client
+}
+
+// NewHTTPPublisher creates a publisher that POSTs events to sinkURL.
+// If token is non-empty it is sent as a bearer token.
+func NewHTTPPublisher(sinkURL, source, token string) *HTTPPublisher {
+	if source == "" {
+		source = DefaultSource
+	}
+
+	return &HTTPPublisher{
+		sinkURL: sinkURL,
+		source:  source,
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Publish sends a single event. Any 2xx response counts as delivered.
+func (p *HTTPPublisher) Publish(ctx context.Context, event *types.ClusterEvent) error {
+	body, err := json.Marshal(NewCloudEvent(p.source, event))
+	if err != nil {
+		return fmt.Errorf("marshal cloud event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.sinkURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", CloudEventsContentType)
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post cloud event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sink returned %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	return nil
+}
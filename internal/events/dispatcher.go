@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// Publisher delivers a single cluster event
+type Publisher interface {
+	Publish(ctx context.Context, event *types.ClusterEvent) error
+}
+
+// PendingEvents is the storage used by the Dispatcher.
+// It is implemented by store.ClusterEventStore.
+type PendingEvents interface {
+	PublishPending(ctx context.Context, limit, maxAttempts int, publish func(context.Context, *types.ClusterEvent) error) (int, error)
+}
+
+// Config holds dispatcher configuration
+type Config struct {
+	PollInterval time.Duration // How often to look for unpublished events
+	BatchSize    int           // Maximum events published per poll
+	MaxAttempts  int           // Attempts before an event is abandoned
+}
+
+// DefaultConfig returns default dispatcher configuration
+func DefaultConfig() *Config {
+	return &Config{
+		PollInterval: 5 * time.Second,
+		BatchSize:    50,
+		MaxAttempts:  10,
+	}
+}
+
+// Dispatcher publishes recorded cluster events in order. Delivery is
+// at-least-once: a crash between publishing and recording the result
+// resends the event with the same ID once its claim expires.
+type Dispatcher struct {
+	config    *Config
+	events    PendingEvents
+	publisher Publisher
+}
+
+// NewDispatcher creates a new event dispatcher
+func NewDispatcher(config *Config, events PendingEvents, publisher Publisher) *Dispatcher {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Dispatcher{
+		config:    config,
+		events:    events,
+		publisher: publisher,
+	}
+}
+
+// Start runs the dispatch loop until ctx is cancelled
+func (d *Dispatcher) Start(ctx context.Context) error {
+	log.Printf("Event dispatcher starting (poll_interval=%s, batch_size=%d)",
+		d.config.PollInterval, d.config.BatchSize)
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Event dispatcher shutting down")
+			return ctx.Err()
+
+		case <-ticker.C:
+			d.Dispatch(ctx)
+		}
+	}
+}
+
+// Dispatch publishes pending events until none are left or a batch is not
+// fully delivered, and returns the number published
+func (d *Dispatcher) Dispatch(ctx context.Context) int {
+	total := 0
+	for ctx.Err() == nil {
+		published, err := d.events.PublishPending(ctx, d.config.BatchSize, d.config.MaxAttempts, d.publisher.Publish)
+		if err != nil {
+			log.Printf("Warning: failed to publish cluster events: %v", err)
+			return total
+		}
+
+		total += published
+		if published < d.config.BatchSize {
+			return total
+		}
+	}
+	return total
+}
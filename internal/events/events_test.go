@@ -0,0 +1,171 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func testEvent(id int64, to types.ClusterStatus) *types.ClusterEvent {
+	return &types.ClusterEvent{
+		ID:          id,
+		ClusterID:   "cluster-1",
+		ClusterName: "my-cluster",
+		Type:        types.ClusterEventStatusChanged,
+		FromStatus:  string(types.ClusterStatusCreating),
+		ToStatus:    string(to),
+		Actor:       "worker",
+		JobID:       "job-1",
+		CreatedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestEventType(t *testing.T) {
+	assert.Equal(t, "com.ocpctl.cluster.status_changed", EventType(types.ClusterEventStatusChanged))
+	assert.Equal(t, "com.ocpctl.cluster.created", EventType(types.ClusterEventCreated))
+	assert.Equal(t, "com.ocpctl.cluster.leased", EventType(types.ClusterEventLeased))
+}
+
+func TestHTTPPublisher_PostsStructuredCloudEvent(t *testing.T) {
+	var gotHeaders http.Header
+	var got map[string]interface{}
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	p := NewHTTPPublisher(sink.URL, "https://ocpctl.example.com", "secret")
+	require.NoError(t, p.Publish(context.Background(), testEvent(42, types.ClusterStatusReady)))
+
+	assert.Equal(t, CloudEventsContentType, gotHeaders.Get("Content-Type"))
+	assert.Equal(t, "Bearer secret", gotHeaders.Get("Authorization"))
+
+	assert.Equal(t, "1.0", got["specversion"])
+	assert.Equal(t, "42", got["id"])
+	assert.Equal(t, "https://ocpctl.example.com", got["source"])
+	assert.Equal(t, "com.ocpctl.cluster.status_changed", got["type"])
+	assert.Equal(t, "cluster-1", got["subject"])
+	assert.Equal(t, "READY", got["clusterstatus"])
+	assert.Equal(t, "2026-01-02T03:04:05Z", got["time"])
+
+	data, ok := got["data"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "CREATING", data["from_status"])
+	assert.Equal(t, "READY", data["to_status"])
+	assert.Equal(t, "job-1", data["job_id"])
+}
+
+func TestHTTPPublisher_DefaultsSourceAndOmitsToken(t *testing.T) {
+	var auth, source string
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		var ce CloudEvent
+		_ = json.NewDecoder(r.Body).Decode(&ce)
+		source = ce.Source
+	}))
+	defer sink.Close()
+
+	require.NoError(t, NewHTTPPublisher(sink.URL, "", "").Publish(context.Background(), testEvent(1, types.ClusterStatusReady)))
+	assert.Empty(t, auth)
+	assert.Equal(t, DefaultSource, source)
+}
+
+func TestHTTPPublisher_NonSuccessStatusIsError(t *testing.T) {
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "broker unavailable", http.StatusServiceUnavailable)
+	}))
+	defer sink.Close()
+
+	err := NewHTTPPublisher(sink.URL, "", "").Publish(context.Background(), testEvent(1, types.ClusterStatusReady))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Contains(t, err.Error(), "broker unavailable")
+}
+
+// fakePending hands out queued events in batches like ClusterEventStore.PublishPending
+type fakePending struct {
+	queue     []*types.ClusterEvent
+	published []int64
+	failed    []int64
+	err       error
+}
+
+func (f *fakePending) PublishPending(ctx context.Context, limit, maxAttempts int, publish func(context.Context, *types.ClusterEvent) error) (int, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+
+	n := min(limit, len(f.queue))
+	batch := f.queue[:n]
+	f.queue = f.queue[n:]
+
+	published := 0
+	for _, e := range batch {
+		if err := publish(ctx, e); err != nil {
+			f.failed = append(f.failed, e.ID)
+			continue
+		}
+		f.published = append(f.published, e.ID)
+		published++
+	}
+	return published, nil
+}
+
+type publisherFunc func(ctx context.Context, event *types.ClusterEvent) error
+
+func (f publisherFunc) Publish(ctx context.Context, event *types.ClusterEvent) error {
+	return f(ctx, event)
+}
+
+func TestDispatcher_DrainsAllBatches(t *testing.T) {
+	pending := &fakePending{}
+	for i := int64(1); i <= 5; i++ {
+		pending.queue = append(pending.queue, testEvent(i, types.ClusterStatusReady))
+	}
+
+	d := NewDispatcher(&Config{PollInterval: time.Second, BatchSize: 2, MaxAttempts: 3}, pending,
+		publisherFunc(func(context.Context, *types.ClusterEvent) error { return nil }))
+
+	assert.Equal(t, 5, d.Dispatch(context.Background()))
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, pending.published)
+}
+
+func TestDispatcher_StopsAfterPartialBatch(t *testing.T) {
+	pending := &fakePending{}
+	for i := int64(1); i <= 4; i++ {
+		pending.queue = append(pending.queue, testEvent(i, types.ClusterStatusReady))
+	}
+
+	// Event 2 fails, so the first batch is short and the rest waits for the next poll
+	d := NewDispatcher(&Config{PollInterval: time.Second, BatchSize: 2, MaxAttempts: 3}, pending,
+		publisherFunc(func(_ context.Context, e *types.ClusterEvent) error {
+			if e.ID == 2 {
+				return errors.New("sink down")
+			}
+			return nil
+		}))
+
+	assert.Equal(t, 1, d.Dispatch(context.Background()))
+	assert.Equal(t, []int64{1}, pending.published)
+	assert.Equal(t, []int64{2}, pending.failed)
+	assert.Len(t, pending.queue, 2)
+}
+
+func TestDispatcher_StoreErrorIsNotFatal(t *testing.T) {
+	pending := &fakePending{err: errors.New("connection refused")}
+	d := NewDispatcher(nil, pending, publisherFunc(func(context.Context, *types.ClusterEvent) error { return nil }))
+
+	assert.Equal(t, 0, d.Dispatch(context.Background()))
+}
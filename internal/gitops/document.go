@@ -0,0 +1,363 @@
+// Package gitops reconciles declarative cluster specs: desired-state documents
+// listing clusters, pools and storage links, uploaded through the API or synced
+// from a git repository.
+package gitops
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MaxDocumentSize limits a single document or an uncompressed bundle
+	MaxDocumentSize = 5 * 1024 * 1024
+	// MaxBundleFiles limits the number of spec files in a bundle or directory
+	MaxBundleFiles = 200
+)
+
+var (
+	resourceNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+	// ErrNoDocuments is returned when a bundle or directory has no spec files
+	ErrNoDocuments = errors.New("no cluster spec documents found")
+)
+
+// IsSpecFile reports whether a file name looks like a spec document
+func IsSpecFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseDocument parses a YAML or JSON desired-state document. A YAML stream
+// with several documents is merged into one.
+func ParseDocument(data []byte) (*types.ClusterSpecDocument, error) {
+	if len(data) > MaxDocumentSize {
+		return nil, fmt.Errorf("document exceeds %d bytes", MaxDocumentSize)
+	}
+
+	var docs []*types.ClusterSpecDocument
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		doc := &types.ClusterSpecDocument{}
+		err := dec.Decode(doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse document: %w", err)
+		}
+		// Skip empty documents such as a trailing "---"
+		if doc.APIVersion == "" && doc.Kind == "" && isEmptyState(doc.Spec) {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	if len(docs) == 0 {
+		return nil, ErrNoDocuments
+	}
+	return Merge(docs...)
+}
+
+// ParseBundle parses a gzipped tar archive of spec files into one document
+func ParseBundle(r io.Reader) (*types.ClusterSpecDocument, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open bundle: %w", err)
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	total := 0
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !IsSpecFile(hdr.Name) {
+			continue
+		}
+		if len(files) >= MaxBundleFiles {
+			return nil, fmt.Errorf("bundle has more than %d spec files", MaxBundleFiles)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, MaxDocumentSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", hdr.Name, err)
+		}
+		total += len(data)
+		if total > MaxDocumentSize {
+			return nil, fmt.Errorf("bundle exceeds %d bytes", MaxDocumentSize)
+		}
+		files[hdr.Name] = data
+	}
+
+	return parseFiles(files)
+}
+
+// LoadPath parses a spec file, or every spec file under a directory
+func LoadPath(path string) (*types.ClusterSpecDocument, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return ParseDocument(data)
+	}
+
+	files := map[string][]byte{}
+	total := 0
+	err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !IsSpecFile(p) {
+			return nil
+		}
+		if len(files) >= MaxBundleFiles {
+			return fmt.Errorf("directory has more than %d spec files", MaxBundleFiles)
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		total += len(data)
+		if total > MaxDocumentSize {
+			return fmt.Errorf("spec files exceed %d bytes", MaxDocumentSize)
+		}
+		files[p] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFiles(files)
+}
+
+// parseFiles parses and merges files in name order
+func parseFiles(files map[string][]byte) (*types.ClusterSpecDocument, error) {
+	if len(files) == 0 {
+		return nil, ErrNoDocuments
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	docs := make([]*types.ClusterSpecDocument, 0, len(names))
+	for _, name := range names {
+		doc, err := ParseDocument(files[name])
+		if errors.Is(err, ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(name), err)
+		}
+		docs = append(docs, doc)
+	}
+
+	if len(docs) == 0 {
+		return nil, ErrNoDocuments
+	}
+	return Merge(docs...)
+}
+
+// Merge combines documents into one. The metadata of the first document wins.
+func Merge(docs ...*types.ClusterSpecDocument) (*types.ClusterSpecDocument, error) {
+	merged := &types.ClusterSpecDocument{}
+	for i, doc := range docs {
+		if doc.APIVersion != types.ClusterSpecAPIVersion {
+			return nil, fmt.Errorf("unsupported apiVersion %q (expected %s)", doc.APIVersion, types.ClusterSpecAPIVersion)
+		}
+		if doc.Kind != types.ClusterSpecKind {
+			return nil, fmt.Errorf("unsupported kind %q (expected %s)", doc.Kind, types.ClusterSpecKind)
+		}
+		if i == 0 {
+			merged.APIVersion = doc.APIVersion
+			merged.Kind = doc.Kind
+			merged.Metadata = doc.Metadata
+		}
+		merged.Spec.Clusters = append(merged.Spec.Clusters, doc.Spec.Clusters...)
+		merged.Spec.Pools = append(merged.Spec.Pools, doc.Spec.Pools...)
+		merged.Spec.StorageLinks = append(merged.Spec.StorageLinks, doc.Spec.StorageLinks...)
+	}
+	return merged, nil
+}
+
+func isEmptyState(s types.DesiredState) bool {
+	return len(s.Clusters) == 0 && len(s.Pools) == 0 && len(s.StorageLinks) == 0
+}
+
+// Validate checks a document for structural errors. Profile and policy checks
+// happen when the plan is computed.
+func Validate(doc *types.ClusterSpecDocument) error {
+	var errs []string
+	addf := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	if doc.APIVersion != types.ClusterSpecAPIVersion {
+		addf("apiVersion must be %s", types.ClusterSpecAPIVersion)
+	}
+	if doc.Kind != types.ClusterSpecKind {
+		addf("kind must be %s", types.ClusterSpecKind)
+	}
+
+	clusters := map[string]bool{}
+	for i, c := range doc.Spec.Clusters {
+		field := fmt.Sprintf("spec.clusters[%d]", i)
+		if !resourceNameRegex.MatchString(c.Name) || len(c.Name) < 3 || len(c.Name) > 63 {
+			addf("%s.name %q must be 3-63 lowercase alphanumeric characters or '-'", field, c.Name)
+		}
+		if clusters[c.Name] {
+			addf("%s.name %q is declared more than once", field, c.Name)
+		}
+		clusters[c.Name] = true
+
+		required := map[string]string{
+			"platform":    c.Platform,
+			"clusterType": c.ClusterType,
+			"version":     c.Version,
+			"profile":     c.Profile,
+			"region":      c.Region,
+			"owner":       c.Owner,
+			"team":        c.Team,
+			"costCenter":  c.CostCenter,
+		}
+		for _, key := range []string{"platform", "clusterType", "version", "profile", "region", "owner", "team", "costCenter"} {
+			if required[key] == "" {
+				addf("%s.%s is required", field, key)
+			}
+		}
+		if c.ClusterType == string(types.ClusterTypeOpenShift) && c.BaseDomain == "" {
+			addf("%s.baseDomain is required for OpenShift clusters", field)
+		}
+		if c.TTLHours != nil && *c.TTLHours < 0 {
+			addf("%s.ttlHours must not be negative", field)
+		}
+		for j, a := range c.Addons {
+			if a.ID == "" || a.Version == "" {
+				addf("%s.addons[%d] requires id and version", field, j)
+			}
+		}
+	}
+
+	pools := map[string]bool{}
+	for i, p := range doc.Spec.Pools {
+		field := fmt.Sprintf("spec.pools[%d]", i)
+		if !resourceNameRegex.MatchString(p.Name) || len(p.Name) < 3 || len(p.Name) > 50 {
+			addf("%s.name %q must be 3-50 lowercase alphanumeric characters or '-'", field, p.Name)
+		}
+		if pools[p.Name] {
+			addf("%s.name %q is declared more than once", field, p.Name)
+		}
+		pools[p.Name] = true
+
+		if p.Profile == "" {
+			addf("%s.profile is required", field)
+		}
+		if p.TargetSize < 1 || p.TargetSize > 50 {
+			addf("%s.targetSize must be between 1 and 50", field)
+		}
+		cfg := resolvePool(p)
+		if cfg.MinSize > cfg.TargetSize {
+			addf("%s.minSize cannot be greater than targetSize", field)
+		}
+		if cfg.TargetSize > cfg.MaxSize {
+			addf("%s.targetSize cannot be greater than maxSize", field)
+		}
+	}
+
+	links := map[string]bool{}
+	for i, l := range doc.Spec.StorageLinks {
+		field := fmt.Sprintf("spec.storageLinks[%d]", i)
+		if l.Source == l.Target {
+			addf("%s cannot link cluster %q to itself", field, l.Source)
+		}
+		for _, name := range []string{l.Source, l.Target} {
+			if !clusters[name] {
+				addf("%s references cluster %q which is not declared in this spec", field, name)
+			}
+		}
+		key := LinkName(l)
+		if links[key] || links[LinkName(types.DesiredStorageLink{Source: l.Target, Target: l.Source})] {
+			addf("%s links %s and %s more than once", field, l.Source, l.Target)
+		}
+		links[key] = true
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// ValidationError lists every problem found in a document
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid cluster spec: " + strings.Join(e.Errors, "; ")
+}
+
+// CheckOwnership restricts what a non-admin spec owner may declare: clusters
+// they own, and no pools (pool management is admin only)
+func CheckOwnership(doc *types.ClusterSpecDocument, owner *types.User) error {
+	if owner.Role == types.RoleAdmin {
+		return nil
+	}
+
+	var errs []string
+	if len(doc.Spec.Pools) > 0 {
+		errs = append(errs, "only admins can declare pools")
+	}
+	for _, c := range doc.Spec.Clusters {
+		if !strings.EqualFold(c.Owner, owner.Email) {
+			errs = append(errs, fmt.Sprintf("cluster %q must be owned by %s", c.Name, owner.Email))
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// LinkName is the resource name recorded for a storage link
+func LinkName(l types.DesiredStorageLink) string {
+	return l.Source + "->" + l.Target
+}
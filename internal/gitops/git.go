@@ -0,0 +1,180 @@
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/internal/validation"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// DefaultGitRef is fetched when a spec does not name a branch, tag or commit
+const DefaultGitRef = "HEAD"
+
+// allowedGitSchemes are the remote transports a repository may be fetched
+// over. Local paths, file:// URLs and unencrypted transports are rejected so
+// sources cannot read the worker's disk or be tampered with in transit.
+var allowedGitSchemes = map[string]bool{
+	"https": true,
+	"ssh":   true,
+}
+
+// scpLikeURL matches the user@host:path form git accepts for SSH remotes
+var scpLikeURL = regexp.MustCompile(`^[A-Za-z0-9._-]+@([A-Za-z0-9.-]+):[^/]`)
+
+// ValidateGitSource checks a spec's repository URL and ref before they are
+// stored or passed to git
+func ValidateGitSource(repoURL, ref string) error {
+	if err := ValidateGitURL(repoURL); err != nil {
+		return fmt.Errorf("git_url: %w", err)
+	}
+	if strings.HasPrefix(ref, "-") || strings.ContainsAny(ref, " \t\n") {
+		return fmt.Errorf("git_ref %q is not a valid branch, tag or commit", ref)
+	}
+	return nil
+}
+
+// ValidateGitURL checks that a repository is fetched over HTTPS or SSH from a
+// host that does not resolve to a private, loopback or link-local address
+func ValidateGitURL(repoURL string) error {
+	if strings.HasPrefix(repoURL, "-") {
+		return fmt.Errorf("invalid repository URL")
+	}
+	if m := scpLikeURL.FindStringSubmatch(repoURL); m != nil {
+		return validation.ValidateSecureHost(m[1])
+	}
+
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if !allowedGitSchemes[strings.ToLower(u.Scheme)] {
+		return fmt.Errorf("only HTTPS and SSH repositories are allowed, got: %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("URL has no hostname")
+	}
+	return validation.ValidateSecureHost(u.Hostname())
+}
+
+// FetchGit fetches a single ref of a git repository and loads the spec files
+// at path (a file or directory, relative to the repository root). It returns
+// the document and the commit SHA it was read from. Credentials may be
+// embedded in the URL; they are redacted from errors.
+func FetchGit(ctx context.Context, repoURL, ref, path string) (*types.ClusterSpecDocument, string, error) {
+	dir, err := os.MkdirTemp("", "ocpctl-cluster-spec-")
+	if err != nil {
+		return nil, "", fmt.Errorf("create checkout directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	revision, err := Checkout(ctx, dir, repoURL, ref, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Spec paths are relative to the repository root even when written with a leading slash
+	specPath, err := ResolveCheckoutPath(dir, strings.TrimPrefix(filepath.Clean("/"+path), "/"))
+	if err != nil {
+		return nil, "", fmt.Errorf("load %s at %s: %w", displayPath(path), revision, err)
+	}
+	doc, err := LoadPath(specPath)
+	if err != nil {
+		msg := strings.ReplaceAll(err.Error(), dir+string(filepath.Separator), "")
+		return nil, "", fmt.Errorf("load %s at %s: %s", displayPath(path), revision, msg)
+	}
+
+	return doc, revision, nil
+}
+
+// Checkout fetches a single ref of a git repository into the empty directory
+// dir and returns the commit SHA checked out. env is added to git's
+// environment, e.g. to pass credentials without putting them on the command
+// line. Credentials embedded in repoURL are redacted from errors.
+func Checkout(ctx context.Context, dir, repoURL, ref string, env []string) (string, error) {
+	if ref == "" {
+		ref = DefaultGitRef
+	}
+
+	// init + fetch rather than clone so ref can be a commit SHA as well as a branch or tag
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", "--", repoURL, ref},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		if _, err := runGit(ctx, dir, env, args...); err != nil {
+			return "", redact(err, repoURL)
+		}
+	}
+
+	return runGit(ctx, dir, env, "rev-parse", "HEAD")
+}
+
+// ResolveCheckoutPath resolves a path relative to a checkout, following
+// symlinks, and rejects paths that end up outside it, so a repository cannot
+// link to files elsewhere on the worker
+func ResolveCheckoutPath(root, path string) (string, error) {
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("resolve checkout: %w", err)
+	}
+
+	joined := filepath.Join(realRoot, path)
+	if filepath.IsAbs(path) || !withinDir(joined, realRoot) {
+		return "", fmt.Errorf("invalid git path %s: outside the repository", path)
+	}
+
+	resolved, err := filepath.EvalSymlinks(joined)
+	if err != nil {
+		return "", fmt.Errorf("%s not found in repository", displayPath(path))
+	}
+	if !withinDir(resolved, realRoot) {
+		return "", fmt.Errorf("invalid git path %s: links outside the repository", path)
+	}
+	return resolved, nil
+}
+
+// withinDir reports whether the clean path is dir or inside it
+func withinDir(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+func runGit(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Never prompt for credentials; fail instead
+	cmd.Env = append(append(os.Environ(), env...), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// redact removes any credentials in repoURL from an error message
+func redact(err error, repoURL string) error {
+	u, parseErr := url.Parse(repoURL)
+	if parseErr != nil || u.User == nil {
+		return err
+	}
+	u.User = nil
+	return fmt.Errorf("%s", strings.ReplaceAll(err.Error(), repoURL, u.String()))
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "repository root"
+	}
+	return path
+}
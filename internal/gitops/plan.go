@@ -0,0 +1,426 @@
+package gitops
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// Snapshot is the current state relevant to a cluster spec. It is built by
+// the Reconciler and kept free of store access so planning is a pure function.
+type Snapshot struct {
+	// Managed maps resource kind and name to the ID recorded for this spec
+	Managed map[types.ClusterSpecResourceKind]map[string]string
+	// Clusters holds the current record of managed clusters that still exist, by name
+	Clusters map[string]*types.Cluster
+	// ForeignClusters holds names of active clusters this spec does not manage
+	ForeignClusters map[string]bool
+	// Pools holds existing pools with a desired or managed name, by name
+	Pools map[string]*types.ClusterPool
+	// LinkGroups maps a link name to the storage group shared by both clusters
+	LinkGroups map[string]string
+	// LinkPending holds link names with a shared storage job in progress
+	LinkPending map[string]bool
+}
+
+// NewSnapshot returns an empty snapshot
+func NewSnapshot() *Snapshot {
+	return &Snapshot{
+		Managed: map[types.ClusterSpecResourceKind]map[string]string{
+			types.ClusterSpecResourceCluster:     {},
+			types.ClusterSpecResourcePool:        {},
+			types.ClusterSpecResourceStorageLink: {},
+		},
+		Clusters:        map[string]*types.Cluster{},
+		ForeignClusters: map[string]bool{},
+		Pools:           map[string]*types.ClusterPool{},
+		LinkGroups:      map[string]string{},
+		LinkPending:     map[string]bool{},
+	}
+}
+
+// isManaged reports whether the spec has recorded a resource
+func (s *Snapshot) isManaged(kind types.ClusterSpecResourceKind, name string) bool {
+	_, ok := s.Managed[kind][name]
+	return ok
+}
+
+// ComputePlan diffs a document against current state. Resources are only
+// updated or removed if this spec created them; resources removed from the
+// document are destroyed when prune is set and released otherwise.
+func ComputePlan(doc *types.ClusterSpecDocument, snap *Snapshot, prune bool) *types.ClusterSpecPlan {
+	plan := &types.ClusterSpecPlan{Actions: []types.PlanAction{}}
+	add := func(a types.PlanAction) {
+		if a.Action == types.PlanActionUnchanged {
+			plan.Unchanged++
+			return
+		}
+		plan.Actions = append(plan.Actions, a)
+	}
+
+	desiredClusters := map[string]bool{}
+	for _, c := range doc.Spec.Clusters {
+		desiredClusters[c.Name] = true
+		add(planCluster(c, snap))
+	}
+	for _, name := range sortedKeys(snap.Managed[types.ClusterSpecResourceCluster]) {
+		if !desiredClusters[name] {
+			add(planRemovedCluster(name, snap, prune))
+		}
+	}
+
+	desiredPools := map[string]bool{}
+	for _, p := range doc.Spec.Pools {
+		desiredPools[p.Name] = true
+		add(planPool(p, snap))
+	}
+	for _, name := range sortedKeys(snap.Managed[types.ClusterSpecResourcePool]) {
+		if !desiredPools[name] {
+			add(planRemovedPool(name, snap, prune))
+		}
+	}
+
+	desiredLinks := map[string]bool{}
+	for _, l := range doc.Spec.StorageLinks {
+		desiredLinks[LinkName(l)] = true
+		add(planLink(l, snap))
+	}
+	for _, name := range sortedKeys(snap.Managed[types.ClusterSpecResourceStorageLink]) {
+		if !desiredLinks[name] {
+			add(planRemovedLink(name, snap, prune))
+		}
+	}
+
+	return plan
+}
+
+func planCluster(c types.DesiredCluster, snap *Snapshot) types.PlanAction {
+	action := types.PlanAction{Kind: types.ClusterSpecResourceCluster, Name: c.Name}
+
+	current, exists := snap.Clusters[c.Name]
+	if !exists {
+		if snap.ForeignClusters[c.Name] {
+			action.Action = types.PlanActionBlocked
+			action.Reason = "a cluster with this name exists and is not managed by this spec"
+			return action
+		}
+		action.Action = types.PlanActionCreate
+		if snap.isManaged(types.ClusterSpecResourceCluster, c.Name) {
+			action.Reason = "cluster no longer exists"
+		}
+		return action
+	}
+
+	switch current.Status {
+	case types.ClusterStatusFailed:
+		action.Action = types.PlanActionBlocked
+		action.Reason = "cluster is FAILED; remove it from the spec to destroy it, or rename it to replace it"
+		return action
+	case types.ClusterStatusDestroying, types.ClusterStatusDestroyVerifying, types.ClusterStatusDestroyFailed:
+		action.Action = types.PlanActionWaiting
+		action.Reason = fmt.Sprintf("cluster is %s", current.Status)
+		return action
+	}
+
+	baseDomain := ""
+	if current.BaseDomain != nil {
+		baseDomain = *current.BaseDomain
+	}
+	immutable := diffFields(
+		field("platform", string(current.Platform), c.Platform),
+		field("clusterType", string(current.ClusterType), c.ClusterType),
+		field("version", current.Version, c.Version),
+		field("profile", current.Profile, c.Profile),
+		field("region", current.Region, c.Region),
+		field("baseDomain", baseDomain, c.BaseDomain),
+		field("owner", current.Owner, c.Owner),
+	)
+	if len(immutable) > 0 {
+		action.Action = types.PlanActionBlocked
+		action.Changes = immutable
+		action.Reason = "these fields cannot change on an existing cluster; rename the cluster to replace it"
+		return action
+	}
+
+	mutable := []types.PlanFieldChange{}
+	if c.TTLHours != nil {
+		mutable = append(mutable, field("ttlHours", strconv.Itoa(current.TTLHours), strconv.Itoa(*c.TTLHours)))
+	}
+	mutable = append(mutable,
+		field("team", current.Team, c.Team),
+		field("costCenter", current.CostCenter, c.CostCenter),
+		field("offhoursOptIn", strconv.FormatBool(current.OffhoursOptIn), strconv.FormatBool(c.OffhoursOptIn)),
+	)
+	if changes := diffFields(mutable...); len(changes) > 0 {
+		action.Action = types.PlanActionUpdate
+		action.Changes = changes
+		return action
+	}
+
+	action.Action = types.PlanActionUnchanged
+	return action
+}
+
+func planRemovedCluster(name string, snap *Snapshot, prune bool) types.PlanAction {
+	action := types.PlanAction{Kind: types.ClusterSpecResourceCluster, Name: name}
+
+	current, exists := snap.Clusters[name]
+	switch {
+	case !exists:
+		action.Action = types.PlanActionUnmanage
+		action.Reason = "cluster no longer exists"
+	case !prune:
+		action.Action = types.PlanActionUnmanage
+		action.Reason = "removed from spec; prune is disabled so the cluster is left running"
+	case current.Status == types.ClusterStatusDestroying || current.Status == types.ClusterStatusDestroyVerifying:
+		action.Action = types.PlanActionWaiting
+		action.Reason = fmt.Sprintf("cluster is %s", current.Status)
+	default:
+		action.Action = types.PlanActionDestroy
+		action.Reason = "removed from spec"
+	}
+	return action
+}
+
+// resolvedPool is a desired pool with POST /admin/pools defaults applied
+type resolvedPool struct {
+	DisplayName               string
+	Description               string
+	TargetSize                int
+	MinSize                   int
+	MaxSize                   int
+	DefaultLeaseDurationHours int
+	MaxLeaseDurationHours     int
+	AutoReleaseEnabled        bool
+	MaxClusterAgeDays         int
+	AutoRefreshEnabled        bool
+	Enabled                   bool
+}
+
+func resolvePool(p types.DesiredPool) resolvedPool {
+	r := resolvedPool{
+		DisplayName:               p.DisplayName,
+		Description:               p.Description,
+		TargetSize:                p.TargetSize,
+		MinSize:                   1,
+		MaxSize:                   p.TargetSize * 2,
+		DefaultLeaseDurationHours: 24,
+		MaxLeaseDurationHours:     2,
+		AutoReleaseEnabled:        true,
+		MaxClusterAgeDays:         7,
+		AutoRefreshEnabled:        false,
+		Enabled:                   true,
+	}
+	if r.DisplayName == "" {
+		r.DisplayName = p.Name
+	}
+	if p.MinSize > 0 {
+		r.MinSize = p.MinSize
+	}
+	if p.MaxSize > 0 {
+		r.MaxSize = p.MaxSize
+	}
+	if p.DefaultLeaseDurationHours != nil {
+		r.DefaultLeaseDurationHours = *p.DefaultLeaseDurationHours
+	}
+	if p.MaxLeaseDurationHours != nil {
+		r.MaxLeaseDurationHours = *p.MaxLeaseDurationHours
+	}
+	if p.AutoReleaseEnabled != nil {
+		r.AutoReleaseEnabled = *p.AutoReleaseEnabled
+	}
+	if p.MaxClusterAgeDays != nil {
+		r.MaxClusterAgeDays = *p.MaxClusterAgeDays
+	}
+	if p.AutoRefreshEnabled != nil {
+		r.AutoRefreshEnabled = *p.AutoRefreshEnabled
+	}
+	if p.Enabled != nil {
+		r.Enabled = *p.Enabled
+	}
+	return r
+}
+
+// poolColumns maps plan field names to cluster_pools columns
+var poolColumns = map[string]string{
+	"displayName":               "display_name",
+	"description":               "description",
+	"targetSize":                "target_size",
+	"minSize":                   "min_size",
+	"maxSize":                   "max_size",
+	"defaultLeaseDurationHours": "default_lease_duration_hours",
+	"maxLeaseDurationHours":     "max_lease_duration_hours",
+	"autoReleaseEnabled":        "auto_release_enabled",
+	"maxClusterAgeDays":         "max_cluster_age_days",
+	"autoRefreshEnabled":        "auto_refresh_enabled",
+	"enabled":                   "enabled",
+}
+
+// poolUpdates returns the column updates that bring a pool to its desired state
+func poolUpdates(current *types.ClusterPool, desired types.DesiredPool) map[string]interface{} {
+	r := resolvePool(desired)
+	values := map[string]interface{}{
+		"displayName":               r.DisplayName,
+		"description":               r.Description,
+		"targetSize":                r.TargetSize,
+		"minSize":                   r.MinSize,
+		"maxSize":                   r.MaxSize,
+		"defaultLeaseDurationHours": r.DefaultLeaseDurationHours,
+		"maxLeaseDurationHours":     r.MaxLeaseDurationHours,
+		"autoReleaseEnabled":        r.AutoReleaseEnabled,
+		"maxClusterAgeDays":         r.MaxClusterAgeDays,
+		"autoRefreshEnabled":        r.AutoRefreshEnabled,
+		"enabled":                   r.Enabled,
+	}
+
+	updates := map[string]interface{}{}
+	for _, change := range poolChanges(current, desired) {
+		updates[poolColumns[change.Field]] = values[change.Field]
+	}
+	return updates
+}
+
+func poolChanges(current *types.ClusterPool, desired types.DesiredPool) []types.PlanFieldChange {
+	r := resolvePool(desired)
+	itoa := strconv.Itoa
+	btoa := strconv.FormatBool
+	return diffFields(
+		field("displayName", current.DisplayName, r.DisplayName),
+		field("description", current.Description, r.Description),
+		field("targetSize", itoa(current.TargetSize), itoa(r.TargetSize)),
+		field("minSize", itoa(current.MinSize), itoa(r.MinSize)),
+		field("maxSize", itoa(current.MaxSize), itoa(r.MaxSize)),
+		field("defaultLeaseDurationHours", itoa(current.DefaultLeaseDurationHours), itoa(r.DefaultLeaseDurationHours)),
+		field("maxLeaseDurationHours", itoa(current.MaxLeaseDurationHours), itoa(r.MaxLeaseDurationHours)),
+		field("autoReleaseEnabled", btoa(current.AutoReleaseEnabled), btoa(r.AutoReleaseEnabled)),
+		field("maxClusterAgeDays", itoa(current.MaxClusterAgeDays), itoa(r.MaxClusterAgeDays)),
+		field("autoRefreshEnabled", btoa(current.AutoRefreshEnabled), btoa(r.AutoRefreshEnabled)),
+		field("enabled", btoa(current.Enabled), btoa(r.Enabled)),
+	)
+}
+
+func planPool(p types.DesiredPool, snap *Snapshot) types.PlanAction {
+	action := types.PlanAction{Kind: types.ClusterSpecResourcePool, Name: p.Name}
+
+	current, exists := snap.Pools[p.Name]
+	managed := snap.isManaged(types.ClusterSpecResourcePool, p.Name)
+	switch {
+	case !exists:
+		action.Action = types.PlanActionCreate
+	case !managed || snap.Managed[types.ClusterSpecResourcePool][p.Name] != current.ID:
+		action.Action = types.PlanActionBlocked
+		action.Reason = "a pool with this name exists and is not managed by this spec"
+	case current.Profile != p.Profile:
+		action.Action = types.PlanActionBlocked
+		action.Changes = []types.PlanFieldChange{field("profile", current.Profile, p.Profile)}
+		action.Reason = "the profile of an existing pool cannot change; rename the pool to replace it"
+	default:
+		if changes := poolChanges(current, p); len(changes) > 0 {
+			action.Action = types.PlanActionUpdate
+			action.Changes = changes
+		} else {
+			action.Action = types.PlanActionUnchanged
+		}
+	}
+	return action
+}
+
+func planRemovedPool(name string, snap *Snapshot, prune bool) types.PlanAction {
+	action := types.PlanAction{Kind: types.ClusterSpecResourcePool, Name: name}
+
+	current, exists := snap.Pools[name]
+	switch {
+	case !exists || snap.Managed[types.ClusterSpecResourcePool][name] != current.ID:
+		action.Action = types.PlanActionUnmanage
+		action.Reason = "pool no longer exists"
+	case !prune:
+		action.Action = types.PlanActionUnmanage
+		action.Reason = "removed from spec; prune is disabled so the pool is kept"
+	default:
+		action.Action = types.PlanActionDestroy
+		action.Reason = "removed from spec; pool clusters are orphaned, not destroyed"
+	}
+	return action
+}
+
+func planLink(l types.DesiredStorageLink, snap *Snapshot) types.PlanAction {
+	name := LinkName(l)
+	action := types.PlanAction{Kind: types.ClusterSpecResourceStorageLink, Name: name}
+
+	if group, linked := snap.LinkGroups[name]; linked {
+		if snap.Managed[types.ClusterSpecResourceStorageLink][name] == group {
+			action.Action = types.PlanActionUnchanged
+		} else {
+			// Linked outside the spec or by a job started on an earlier sync: adopt it
+			action.Action = types.PlanActionLink
+			action.Reason = "already linked"
+		}
+		return action
+	}
+
+	if snap.LinkPending[name] {
+		action.Action = types.PlanActionWaiting
+		action.Reason = "shared storage is being provisioned"
+		return action
+	}
+
+	for _, clusterName := range []string{l.Source, l.Target} {
+		c, ok := snap.Clusters[clusterName]
+		if !ok || c.Status != types.ClusterStatusReady {
+			action.Action = types.PlanActionWaiting
+			action.Reason = fmt.Sprintf("waiting for cluster %s to be READY", clusterName)
+			return action
+		}
+	}
+	if snap.Clusters[l.Source].Region != snap.Clusters[l.Target].Region {
+		action.Action = types.PlanActionBlocked
+		action.Reason = "clusters must be in the same region to share storage"
+		return action
+	}
+
+	action.Action = types.PlanActionLink
+	return action
+}
+
+func planRemovedLink(name string, snap *Snapshot, prune bool) types.PlanAction {
+	action := types.PlanAction{Kind: types.ClusterSpecResourceStorageLink, Name: name}
+
+	switch group, linked := snap.LinkGroups[name]; {
+	case !linked || group != snap.Managed[types.ClusterSpecResourceStorageLink][name]:
+		action.Action = types.PlanActionUnmanage
+		action.Reason = "link no longer exists"
+	case !prune:
+		action.Action = types.PlanActionUnmanage
+		action.Reason = "removed from spec; prune is disabled so the link is kept"
+	default:
+		action.Action = types.PlanActionUnlink
+		action.Reason = "removed from spec"
+	}
+	return action
+}
+
+func field(name, from, to string) types.PlanFieldChange {
+	return types.PlanFieldChange{Field: name, From: from, To: to}
+}
+
+// diffFields returns the fields whose values differ
+func diffFields(fields ...types.PlanFieldChange) []types.PlanFieldChange {
+	var changes []types.PlanFieldChange
+	for _, f := range fields {
+		if f.From != f.To {
+			changes = append(changes, f)
+		}
+	}
+	return changes
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
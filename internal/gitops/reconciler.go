@@ -0,0 +1,557 @@
+package gitops
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/tsanders-rh/ocpctl/internal/clustercreate"
+	"github.com/tsanders-rh/ocpctl/internal/policy"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// Reconciler plans and applies cluster specs against the store. Applying only
+// records state and enqueues jobs; the worker does the provisioning.
+type Reconciler struct {
+	store   *store.Store
+	creator *clustercreate.Creator
+}
+
+// NewReconciler creates a new reconciler
+func NewReconciler(st *store.Store, policyEngine *policy.Engine, profileRegistry *profile.Registry) *Reconciler {
+	return &Reconciler{
+		store:   st,
+		creator: clustercreate.New(st, policyEngine, profileRegistry),
+	}
+}
+
+// EventActor is the cluster event actor recorded for changes made by a spec
+func EventActor(specName string) string {
+	return "cluster-spec/" + specName
+}
+
+// Snapshot loads the current state relevant to a document. spec may be nil
+// when planning a document that has not been registered yet.
+func (r *Reconciler) Snapshot(ctx context.Context, spec *types.ClusterSpec, doc *types.ClusterSpecDocument) (*Snapshot, error) {
+	snap := NewSnapshot()
+
+	if spec != nil {
+		resources, err := r.store.ClusterSpecs.ListResources(ctx, spec.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range resources {
+			snap.Managed[res.Kind][res.Name] = res.ResourceID
+		}
+	}
+
+	// Clusters
+	managedClusters := snap.Managed[types.ClusterSpecResourceCluster]
+	if len(managedClusters) > 0 {
+		ids := make([]string, 0, len(managedClusters))
+		for _, id := range managedClusters {
+			ids = append(ids, id)
+		}
+		clusters, err := r.store.Clusters.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("load managed clusters: %w", err)
+		}
+		for _, c := range clusters {
+			if c.Status != types.ClusterStatusDestroyed && managedClusters[c.Name] == c.ID {
+				snap.Clusters[c.Name] = c
+			}
+		}
+	}
+
+	names := make([]string, 0, len(doc.Spec.Clusters)+len(managedClusters))
+	for _, c := range doc.Spec.Clusters {
+		names = append(names, c.Name)
+	}
+	for name := range managedClusters {
+		names = append(names, name)
+	}
+	active, err := r.store.ClusterSpecs.FindActiveClusterIDs(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	for name, id := range active {
+		if managedClusters[name] != id {
+			snap.ForeignClusters[name] = true
+		}
+	}
+
+	// Pools
+	poolNames := map[string]bool{}
+	for _, p := range doc.Spec.Pools {
+		poolNames[p.Name] = true
+	}
+	for name := range snap.Managed[types.ClusterSpecResourcePool] {
+		poolNames[name] = true
+	}
+	for name := range poolNames {
+		pool, err := r.store.Pools.GetByName(ctx, name)
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("load pool %s: %w", name, err)
+		}
+		snap.Pools[name] = pool
+	}
+
+	// Storage links
+	linkNames := map[string]types.DesiredStorageLink{}
+	for _, l := range doc.Spec.StorageLinks {
+		linkNames[LinkName(l)] = l
+	}
+	for name := range snap.Managed[types.ClusterSpecResourceStorageLink] {
+		if source, target, ok := strings.Cut(name, "->"); ok {
+			linkNames[name] = types.DesiredStorageLink{Source: source, Target: target}
+		}
+	}
+	for name, l := range linkNames {
+		source, target := snap.Clusters[l.Source], snap.Clusters[l.Target]
+		if source == nil || target == nil {
+			continue
+		}
+		group, pending, err := r.linkState(ctx, source, target)
+		if err != nil {
+			return nil, err
+		}
+		if group != "" {
+			snap.LinkGroups[name] = group
+		}
+		if pending {
+			snap.LinkPending[name] = true
+		}
+	}
+
+	return snap, nil
+}
+
+// linkState finds the storage group shared by two clusters, and whether a
+// shared storage job between them is still in progress
+func (r *Reconciler) linkState(ctx context.Context, source, target *types.Cluster) (string, bool, error) {
+	sourceLinks, err := r.store.ClusterStorageLinks.GetByClusterID(ctx, source.ID)
+	if err != nil {
+		return "", false, fmt.Errorf("load storage links for %s: %w", source.Name, err)
+	}
+	for _, link := range sourceLinks {
+		if _, err := r.store.ClusterStorageLinks.GetByClusterAndGroup(ctx, target.ID, link.StorageGroupID); err == nil {
+			return link.StorageGroupID, false, nil
+		}
+	}
+
+	jobs, err := r.store.Jobs.GetByClusterIDAndType(ctx, source.ID, types.JobTypeProvisionSharedStorage)
+	if err != nil {
+		return "", false, fmt.Errorf("load storage jobs for %s: %w", source.Name, err)
+	}
+	for _, job := range jobs {
+		inProgress := job.Status == types.JobStatusPending || job.Status == types.JobStatusRunning || job.Status == types.JobStatusRetrying
+		if inProgress && job.Metadata["target_cluster_id"] == target.ID {
+			return "", true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// Plan computes the plan for a document. Clusters that would be created or
+// updated are checked against their profile policy, and blocked if invalid.
+func (r *Reconciler) Plan(ctx context.Context, spec *types.ClusterSpec, doc *types.ClusterSpecDocument) (*types.ClusterSpecPlan, error) {
+	plan, _, err := r.plan(ctx, spec, doc)
+	return plan, err
+}
+
+func (r *Reconciler) plan(ctx context.Context, spec *types.ClusterSpec, doc *types.ClusterSpecDocument) (*types.ClusterSpecPlan, *Snapshot, error) {
+	snap, err := r.Snapshot(ctx, spec, doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prune := spec != nil && spec.Prune
+	plan := ComputePlan(doc, snap, prune)
+
+	desired := desiredClusters(doc)
+	for i, action := range plan.Actions {
+		if action.Kind != types.ClusterSpecResourceCluster {
+			continue
+		}
+		if action.Action != types.PlanActionCreate && action.Action != types.PlanActionUpdate {
+			continue
+		}
+		if _, err := r.buildCluster(ctx, desired[action.Name]); err != nil {
+			plan.Actions[i].Action = types.PlanActionBlocked
+			plan.Actions[i].Reason = err.Error()
+		}
+	}
+
+	return plan, snap, nil
+}
+
+// Reconcile plans a registered spec's document and applies the plan. Failures
+// are reported per action so one bad resource does not hold back the rest.
+func (r *Reconciler) Reconcile(ctx context.Context, spec *types.ClusterSpec, doc *types.ClusterSpecDocument) (*types.ClusterSpecPlan, []types.ApplyResult, error) {
+	plan, snap, err := r.plan(ctx, spec, doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx = store.WithEventSource(ctx, store.EventSource{
+		Actor:  EventActor(spec.Name),
+		Reason: "cluster spec " + spec.Name,
+	})
+
+	clusters := desiredClusters(doc)
+	pools := map[string]types.DesiredPool{}
+	for _, p := range doc.Spec.Pools {
+		pools[p.Name] = p
+	}
+
+	results := make([]types.ApplyResult, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		result := types.ApplyResult{PlanAction: action}
+
+		var err error
+		switch action.Kind {
+		case types.ClusterSpecResourceCluster:
+			err = r.applyCluster(ctx, spec, snap, clusters[action.Name], &result)
+		case types.ClusterSpecResourcePool:
+			err = r.applyPool(ctx, spec, snap, pools[action.Name], &result)
+		case types.ClusterSpecResourceStorageLink:
+			err = r.applyLink(ctx, spec, snap, &result)
+		}
+
+		if err != nil {
+			log.Printf("[cluster-spec %s] %s %s %s failed: %v", spec.Name, action.Action, action.Kind, action.Name, err)
+			result.Error = err.Error()
+		} else if result.Applied {
+			log.Printf("[cluster-spec %s] %s %s %s", spec.Name, action.Action, action.Kind, action.Name)
+		}
+		results = append(results, result)
+	}
+
+	return plan, results, nil
+}
+
+// ApplyFailed reports whether any action failed to apply
+func ApplyFailed(results []types.ApplyResult) error {
+	var failed []string
+	for _, res := range results {
+		if res.Error != "" {
+			failed = append(failed, fmt.Sprintf("%s %s %s: %s", res.Action, res.Kind, res.Name, res.Error))
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (r *Reconciler) applyCluster(ctx context.Context, spec *types.ClusterSpec, snap *Snapshot, desired types.DesiredCluster, result *types.ApplyResult) error {
+	switch result.Action {
+	case types.PlanActionCreate:
+		cluster, err := r.buildCluster(ctx, desired)
+		if err != nil {
+			return err
+		}
+
+		if err := r.store.Clusters.Create(ctx, cluster); err != nil {
+			return fmt.Errorf("create cluster: %w", err)
+		}
+		result.ResourceID = cluster.ID
+		if err := r.putResource(ctx, spec, types.ClusterSpecResourceCluster, cluster.Name, cluster.ID); err != nil {
+			return err
+		}
+
+		job := newJob(cluster.ID, types.JobTypeCreate, types.JobMetadata{})
+		if err := r.store.Jobs.Create(ctx, nil, job); err != nil {
+			return fmt.Errorf("create provision job: %w", err)
+		}
+		result.JobID = job.ID
+
+	case types.PlanActionUpdate:
+		current := snap.Clusters[result.Name]
+		updates := map[string]interface{}{}
+		for _, change := range result.Changes {
+			switch change.Field {
+			case "team":
+				updates["team"] = desired.Team
+			case "costCenter":
+				updates["cost_center"] = desired.CostCenter
+			case "offhoursOptIn":
+				updates["offhours_opt_in"] = desired.OffhoursOptIn
+			case "ttlHours":
+				// TTL counts from cluster creation, as it does for new clusters
+				var destroyAt *time.Time
+				if *desired.TTLHours > 0 {
+					t := current.CreatedAt.Add(time.Duration(*desired.TTLHours) * time.Hour)
+					destroyAt = &t
+				}
+				updates["ttl_hours"] = *desired.TTLHours
+				updates["destroy_at"] = destroyAt
+			}
+		}
+		if err := r.store.Clusters.Update(ctx, current.ID, updates); err != nil {
+			return fmt.Errorf("update cluster: %w", err)
+		}
+		result.ResourceID = current.ID
+
+	case types.PlanActionDestroy:
+		current := snap.Clusters[result.Name]
+		jobID, err := r.destroyCluster(ctx, current)
+		if err != nil {
+			return err
+		}
+		result.ResourceID = current.ID
+		result.JobID = jobID
+		if err := r.store.ClusterSpecs.DeleteResource(ctx, spec.ID, types.ClusterSpecResourceCluster, result.Name); err != nil {
+			return err
+		}
+
+	case types.PlanActionUnmanage:
+		if err := r.store.ClusterSpecs.DeleteResource(ctx, spec.ID, types.ClusterSpecResourceCluster, result.Name); err != nil {
+			return err
+		}
+
+	default:
+		return nil
+	}
+
+	result.Applied = true
+	return nil
+}
+
+// destroyCluster moves a cluster to DESTROYING and enqueues its destroy job,
+// cancelling any create job that has not finished
+func (r *Reconciler) destroyCluster(ctx context.Context, cluster *types.Cluster) (string, error) {
+	jobs, err := r.store.Jobs.GetByClusterIDAndType(ctx, cluster.ID, types.JobTypeCreate)
+	if err != nil {
+		return "", fmt.Errorf("check for existing jobs: %w", err)
+	}
+	for _, job := range jobs {
+		if job.Status == types.JobStatusPending || job.Status == types.JobStatusRunning || job.Status == types.JobStatusRetrying {
+			if err := r.store.Jobs.UpdateStatus(ctx, job.ID, types.JobStatusFailed); err != nil {
+				log.Printf("Warning: failed to cancel CREATE job %s: %v", job.ID, err)
+			}
+		}
+	}
+
+	job := newJob(cluster.ID, types.JobTypeDestroy, types.JobMetadata{})
+	err = r.store.WithTx(ctx, func(tx pgx.Tx) error {
+		if err := r.store.Clusters.UpdateStatus(ctx, tx, cluster.ID, types.ClusterStatusDestroying); err != nil {
+			return fmt.Errorf("update cluster status: %w", err)
+		}
+		if err := r.store.Jobs.Create(ctx, tx, job); err != nil {
+			return fmt.Errorf("create destroy job: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return job.ID, nil
+}
+
+func (r *Reconciler) applyPool(ctx context.Context, spec *types.ClusterSpec, snap *Snapshot, desired types.DesiredPool, result *types.ApplyResult) error {
+	switch result.Action {
+	case types.PlanActionCreate:
+		cfg := resolvePool(desired)
+		pool := &types.ClusterPool{
+			Name:                      desired.Name,
+			DisplayName:               cfg.DisplayName,
+			Description:               cfg.Description,
+			Profile:                   desired.Profile,
+			TargetSize:                cfg.TargetSize,
+			MinSize:                   cfg.MinSize,
+			MaxSize:                   cfg.MaxSize,
+			DefaultLeaseDurationHours: cfg.DefaultLeaseDurationHours,
+			MaxLeaseDurationHours:     cfg.MaxLeaseDurationHours,
+			AutoReleaseEnabled:        cfg.AutoReleaseEnabled,
+			MaxClusterAgeDays:         cfg.MaxClusterAgeDays,
+			AutoRefreshEnabled:        cfg.AutoRefreshEnabled,
+			ScheduleDaysOfWeek:        []int{1, 2, 3, 4, 5},
+			ScheduleStartHour:         8,
+			ScheduleEndHour:           18,
+			Enabled:                   cfg.Enabled,
+			CreatedBy:                 spec.OwnerID,
+		}
+		if _, err := r.store.GetProfile(ctx, desired.Profile); err != nil {
+			return fmt.Errorf("invalid profile: %w", err)
+		}
+		if err := r.store.Pools.Create(ctx, nil, pool); err != nil {
+			return fmt.Errorf("create pool: %w", err)
+		}
+		result.ResourceID = pool.ID
+		if err := r.putResource(ctx, spec, types.ClusterSpecResourcePool, pool.Name, pool.ID); err != nil {
+			return err
+		}
+
+	case types.PlanActionUpdate:
+		current := snap.Pools[result.Name]
+		if err := r.store.Pools.Update(ctx, nil, current.ID, poolUpdates(current, desired)); err != nil {
+			return fmt.Errorf("update pool: %w", err)
+		}
+		result.ResourceID = current.ID
+
+	case types.PlanActionDestroy:
+		current := snap.Pools[result.Name]
+		if err := r.store.Pools.Delete(ctx, current.ID); err != nil {
+			return fmt.Errorf("delete pool: %w", err)
+		}
+		result.ResourceID = current.ID
+		if err := r.store.ClusterSpecs.DeleteResource(ctx, spec.ID, types.ClusterSpecResourcePool, result.Name); err != nil {
+			return err
+		}
+
+	case types.PlanActionUnmanage:
+		if err := r.store.ClusterSpecs.DeleteResource(ctx, spec.ID, types.ClusterSpecResourcePool, result.Name); err != nil {
+			return err
+		}
+
+	default:
+		return nil
+	}
+
+	result.Applied = true
+	return nil
+}
+
+func (r *Reconciler) applyLink(ctx context.Context, spec *types.ClusterSpec, snap *Snapshot, result *types.ApplyResult) error {
+	source, target, _ := strings.Cut(result.Name, "->")
+
+	switch result.Action {
+	case types.PlanActionLink:
+		// Already linked: record it so removing it from the spec unlinks it
+		if group, linked := snap.LinkGroups[result.Name]; linked {
+			result.ResourceID = group
+			if err := r.putResource(ctx, spec, types.ClusterSpecResourceStorageLink, result.Name, group); err != nil {
+				return err
+			}
+			break
+		}
+
+		// The link is recorded on a later sync, once the job has created the storage group
+		job := newJob(snap.Clusters[source].ID, types.JobTypeProvisionSharedStorage, types.JobMetadata{
+			"target_cluster_id": snap.Clusters[target].ID,
+		})
+		if err := r.store.Jobs.Create(ctx, nil, job); err != nil {
+			return fmt.Errorf("create provision job: %w", err)
+		}
+		result.JobID = job.ID
+
+	case types.PlanActionUnlink:
+		group := snap.LinkGroups[result.Name]
+		job := newJob(snap.Clusters[target].ID, types.JobTypeUnlinkSharedStorage, types.JobMetadata{
+			"storage_group_id": group,
+		})
+		if err := r.store.Jobs.Create(ctx, nil, job); err != nil {
+			return fmt.Errorf("create unlink job: %w", err)
+		}
+		result.ResourceID = group
+		result.JobID = job.ID
+		if err := r.store.ClusterSpecs.DeleteResource(ctx, spec.ID, types.ClusterSpecResourceStorageLink, result.Name); err != nil {
+			return err
+		}
+
+	case types.PlanActionUnmanage:
+		if err := r.store.ClusterSpecs.DeleteResource(ctx, spec.ID, types.ClusterSpecResourceStorageLink, result.Name); err != nil {
+			return err
+		}
+
+	default:
+		return nil
+	}
+
+	result.Applied = true
+	return nil
+}
+
+func (r *Reconciler) putResource(ctx context.Context, spec *types.ClusterSpec, kind types.ClusterSpecResourceKind, name, id string) error {
+	return r.store.ClusterSpecs.PutResource(ctx, &types.ClusterSpecResource{
+		SpecID:     spec.ID,
+		Kind:       kind,
+		Name:       name,
+		ResourceID: id,
+	})
+}
+
+// buildCluster validates a desired cluster the way POST /clusters validates a
+// request from its owner and builds the record POST /clusters would create
+func (r *Reconciler) buildCluster(ctx context.Context, desired types.DesiredCluster) (*types.Cluster, error) {
+	owner, err := r.store.Users.GetByEmail(ctx, desired.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("look up owner %s: %w", desired.Owner, err)
+	}
+
+	req := &types.CreateClusterAPIRequest{
+		Name:               desired.Name,
+		Platform:           desired.Platform,
+		ClusterType:        desired.ClusterType,
+		Version:            desired.Version,
+		Profile:            desired.Profile,
+		Region:             desired.Region,
+		BaseDomain:         desired.BaseDomain,
+		Owner:              desired.Owner,
+		Team:               desired.Team,
+		CostCenter:         desired.CostCenter,
+		TTLHours:           desired.TTLHours,
+		ExtraTags:          desired.ExtraTags,
+		OffhoursOptIn:      desired.OffhoursOptIn,
+		SkipPostDeployment: desired.SkipPostDeployment,
+		PostConfigAddOns:   desired.Addons,
+	}
+
+	checked, err := r.creator.Validate(ctx, req, owner.Role != types.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	prof := checked.Profile
+	if prof.ClusterType != "" && string(prof.ClusterType) != desired.ClusterType {
+		return nil, fmt.Errorf("cluster type %s does not match profile cluster type %s", desired.ClusterType, prof.ClusterType)
+	}
+
+	cluster, err := r.creator.Build(req, owner.ID, checked)
+	if err != nil {
+		return nil, err
+	}
+	cluster.RequestedBy = desired.Owner
+
+	if _, err := r.creator.ResolveAddons(ctx, req, cluster, prof); err != nil {
+		return nil, err
+	}
+	clustercreate.SetPostDeployStatus(cluster, prof)
+
+	return cluster, nil
+}
+
+func desiredClusters(doc *types.ClusterSpecDocument) map[string]types.DesiredCluster {
+	clusters := make(map[string]types.DesiredCluster, len(doc.Spec.Clusters))
+	for _, c := range doc.Spec.Clusters {
+		clusters[c.Name] = c
+	}
+	return clusters
+}
+
+func newJob(clusterID string, jobType types.JobType, metadata types.JobMetadata) *types.Job {
+	now := time.Now()
+	return &types.Job{
+		ID:          uuid.New().String(),
+		ClusterID:   clusterID,
+		JobType:     jobType,
+		Status:      types.JobStatusPending,
+		Metadata:    metadata,
+		MaxAttempts: 3,
+		Attempt:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
@@ -0,0 +1,242 @@
+package gitops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ErrSyncInProgress is returned when another sync of the same spec is running
+var ErrSyncInProgress = errors.New("cluster spec sync already in progress")
+
+// DocumentError is returned by Sync when the spec's document cannot be
+// loaded or is invalid, as opposed to a failure talking to the store
+type DocumentError struct {
+	Err error
+}
+
+func (e *DocumentError) Error() string { return e.Err.Error() }
+func (e *DocumentError) Unwrap() error { return e.Err }
+
+// Config holds cluster spec syncer configuration
+type Config struct {
+	CheckInterval time.Duration // How often to look for git specs due for sync
+	GitTimeout    time.Duration // Maximum time to fetch a repository
+}
+
+// DefaultConfig returns default cluster spec syncer configuration
+func DefaultConfig() *Config {
+	return &Config{
+		CheckInterval: 1 * time.Minute,
+		GitTimeout:    2 * time.Minute,
+	}
+}
+
+// Syncer loads cluster spec documents and reconciles them. The worker runs
+// it periodically for git specs; the API uses it for on-demand syncs.
+type Syncer struct {
+	config     *Config
+	store      *store.Store
+	reconciler *Reconciler
+	fetchGit   func(ctx context.Context, repoURL, ref, path string) (*types.ClusterSpecDocument, string, error)
+}
+
+// NewSyncer creates a new cluster spec syncer
+func NewSyncer(config *Config, st *store.Store, reconciler *Reconciler) *Syncer {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Syncer{
+		config:     config,
+		store:      st,
+		reconciler: reconciler,
+		fetchGit:   FetchGit,
+	}
+}
+
+// Start runs the sync loop until ctx is cancelled
+func (s *Syncer) Start(ctx context.Context) error {
+	log.Printf("Cluster spec syncer starting (check_interval=%s)", s.config.CheckInterval)
+
+	s.SyncDue(ctx)
+
+	ticker := time.NewTicker(s.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Cluster spec syncer shutting down")
+			return ctx.Err()
+
+		case <-ticker.C:
+			s.SyncDue(ctx)
+		}
+	}
+}
+
+// SyncDue syncs every git spec whose sync interval has elapsed
+func (s *Syncer) SyncDue(ctx context.Context) {
+	specs, err := s.store.ClusterSpecs.ListDueForSync(ctx)
+	if err != nil {
+		log.Printf("Warning: failed to list cluster specs due for sync: %v", err)
+		return
+	}
+
+	for _, spec := range specs {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := s.Sync(ctx, spec, false); err != nil && !errors.Is(err, ErrSyncInProgress) {
+			log.Printf("Warning: cluster spec %s sync failed: %v", spec.Name, err)
+		}
+	}
+}
+
+// Sync loads a spec's document and reconciles it, recording the outcome on the
+// spec. With dryRun the plan is returned and nothing is changed.
+func (s *Syncer) Sync(ctx context.Context, spec *types.ClusterSpec, dryRun bool) (*types.ClusterSpecSyncResult, error) {
+	if !dryRun {
+		ok, err := s.store.ClusterSpecs.TryBeginSync(ctx, spec.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrSyncInProgress
+		}
+	}
+
+	doc, revision, err := s.load(ctx, spec)
+	if err != nil {
+		if !dryRun {
+			s.finish(ctx, spec, types.ClusterSpecSyncFailed, nil, err)
+		}
+		return nil, err
+	}
+
+	result := &types.ClusterSpecSyncResult{Spec: spec.Name, Revision: revision, DryRun: dryRun}
+
+	if dryRun {
+		plan, err := s.reconciler.Plan(ctx, spec, doc)
+		if err != nil {
+			return nil, err
+		}
+		plan.Revision = revision
+		result.Plan = plan
+		return result, nil
+	}
+
+	if spec.Source == types.ClusterSpecSourceGit && (spec.Revision == nil || *spec.Revision != revision) {
+		if err := s.store.ClusterSpecs.SetDocument(ctx, spec.ID, doc, revision); err != nil {
+			s.finish(ctx, spec, types.ClusterSpecSyncFailed, nil, err)
+			return nil, err
+		}
+		spec.Document = doc
+		spec.Revision = &revision
+	}
+
+	plan, results, err := s.reconciler.Reconcile(ctx, spec, doc)
+	if err != nil {
+		s.finish(ctx, spec, types.ClusterSpecSyncFailed, nil, err)
+		return nil, err
+	}
+	plan.Revision = revision
+	result.Plan = plan
+	result.Results = results
+
+	status := types.ClusterSpecSyncSucceeded
+	syncErr := ApplyFailed(results)
+	if syncErr == nil {
+		syncErr = blockedError(plan)
+	}
+	if syncErr != nil {
+		status = types.ClusterSpecSyncFailed
+	}
+	s.finish(ctx, spec, status, plan, syncErr)
+
+	return result, nil
+}
+
+// load returns the document to reconcile and its revision
+func (s *Syncer) load(ctx context.Context, spec *types.ClusterSpec) (*types.ClusterSpecDocument, string, error) {
+	var doc *types.ClusterSpecDocument
+	revision := ""
+
+	switch spec.Source {
+	case types.ClusterSpecSourceGit:
+		if spec.GitURL == nil || *spec.GitURL == "" {
+			return nil, "", &DocumentError{Err: errors.New("git_url is not set")}
+		}
+		// Checked again at fetch time: the host may resolve differently than when the spec was saved
+		if err := ValidateGitSource(*spec.GitURL, deref(spec.GitRef)); err != nil {
+			return nil, "", &DocumentError{Err: err}
+		}
+		fetchCtx, cancel := context.WithTimeout(ctx, s.config.GitTimeout)
+		defer cancel()
+
+		var err error
+		doc, revision, err = s.fetchGit(fetchCtx, *spec.GitURL, deref(spec.GitRef), deref(spec.GitPath))
+		if err != nil {
+			return nil, "", &DocumentError{Err: err}
+		}
+
+	default:
+		if spec.Document == nil {
+			return nil, "", &DocumentError{Err: errors.New("no document has been uploaded")}
+		}
+		doc = spec.Document
+		revision = deref(spec.Revision)
+	}
+
+	if err := Validate(doc); err != nil {
+		return nil, "", &DocumentError{Err: err}
+	}
+
+	owner, err := s.store.Users.GetByID(ctx, spec.OwnerID)
+	if err != nil {
+		return nil, "", fmt.Errorf("load spec owner: %w", err)
+	}
+	if err := CheckOwnership(doc, owner); err != nil {
+		return nil, "", &DocumentError{Err: err}
+	}
+
+	return doc, revision, nil
+}
+
+func (s *Syncer) finish(ctx context.Context, spec *types.ClusterSpec, status types.ClusterSpecSyncStatus, plan *types.ClusterSpecPlan, syncErr error) {
+	var msg *string
+	if syncErr != nil {
+		m := syncErr.Error()
+		msg = &m
+	}
+	if err := s.store.ClusterSpecs.FinishSync(ctx, spec.ID, status, plan, msg); err != nil {
+		log.Printf("Warning: failed to record cluster spec %s sync: %v", spec.Name, err)
+	}
+}
+
+// blockedError reports actions that need someone to intervene
+func blockedError(plan *types.ClusterSpecPlan) error {
+	blocked := 0
+	for _, a := range plan.Actions {
+		if a.Action == types.PlanActionBlocked {
+			blocked++
+		}
+	}
+	if blocked > 0 {
+		return fmt.Errorf("%d action(s) blocked; see last_plan", blocked)
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
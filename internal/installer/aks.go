@@ -40,6 +40,8 @@ type AKSNodePoolConfig struct {
 	MaxCount        int
 	EnableAutoScale bool
 	OSDiskSizeGB    int
+	Spot            bool   // Spot priority; user pools only
+	SpotMaxPrice    string // Hourly cap in USD; empty caps at the on-demand price
 }
 
 // AKSClusterInfo represents cluster information from Azure
@@ -64,27 +66,26 @@ func NewAKSInstaller() *AKSInstaller {
 	}
 }
 
-// CreateCluster creates an AKS cluster using az aks create
-func (a *AKSInstaller) CreateCluster(ctx context.Context, config *AKSClusterConfig, logFile string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, a.timeout)
-	defer cancel()
-
+// CreateArgs builds the az aks create arguments for the cluster. The first
+// node pool is the system pool created with the cluster; the rest are added
+// afterwards with CreateNodePool.
+func (c *AKSClusterConfig) CreateArgs() ([]string, error) {
 	// Get first node pool (system pool)
-	if len(config.NodePools) == 0 {
-		return "", fmt.Errorf("at least one node pool required")
+	if len(c.NodePools) == 0 {
+		return nil, fmt.Errorf("at least one node pool required")
 	}
-	systemPool := config.NodePools[0]
+	systemPool := c.NodePools[0]
 
 	// Build az aks create command
 	args := []string{
 		"aks", "create",
-		"--resource-group", config.ResourceGroup,
-		"--name", config.Name,
-		"--kubernetes-version", config.KubernetesVersion,
+		"--resource-group", c.ResourceGroup,
+		"--name", c.Name,
+		"--kubernetes-version", c.KubernetesVersion,
 		"--node-count", fmt.Sprintf("%d", systemPool.Count),
 		"--node-vm-size", systemPool.VMSize,
-		"--network-plugin", config.NetworkPlugin,
-		"--network-policy", config.NetworkPolicy,
+		"--network-plugin", c.NetworkPlugin,
+		"--network-policy", c.NetworkPolicy,
 		"--generate-ssh-keys",
 	}
 
@@ -102,14 +103,27 @@ func (a *AKSInstaller) CreateCluster(ctx context.Context, config *AKSClusterConf
 	}
 
 	// Add tags
-	if len(config.Tags) > 0 {
+	if len(c.Tags) > 0 {
 		tagPairs := []string{}
-		for k, v := range config.Tags {
-			tagPairs = append(tagPairs, fmt.Sprintf("%s=%s", k, v))
+		for _, k := range sortedKeys(c.Tags) {
+			tagPairs = append(tagPairs, fmt.Sprintf("%s=%s", k, c.Tags[k]))
 		}
 		args = append(args, "--tags", strings.Join(tagPairs, " "))
 	}
 
+	return args, nil
+}
+
+// CreateCluster creates an AKS cluster using az aks create
+func (a *AKSInstaller) CreateCluster(ctx context.Context, config *AKSClusterConfig, logFile string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	args, err := config.CreateArgs()
+	if err != nil {
+		return "", err
+	}
+
 	cmd := exec.CommandContext(ctx, a.binaryPath, args...)
 
 	// Open log file for appending
@@ -138,28 +152,45 @@ func (a *AKSInstaller) CreateCluster(ctx context.Context, config *AKSClusterConf
 	return string(logData), nil
 }
 
-// CreateNodePool creates an additional node pool
-func (a *AKSInstaller) CreateNodePool(ctx context.Context, resourceGroup, clusterName string, pool *AKSNodePoolConfig, logFile string) error {
+// CreateArgs builds the az aks nodepool add arguments for the node pool
+func (p *AKSNodePoolConfig) CreateArgs(resourceGroup, clusterName string) []string {
 	args := []string{
 		"aks", "nodepool", "add",
 		"--resource-group", resourceGroup,
 		"--cluster-name", clusterName,
-		"--name", pool.Name,
-		"--node-count", fmt.Sprintf("%d", pool.Count),
-		"--node-vm-size", pool.VMSize,
+		"--name", p.Name,
+		"--node-count", fmt.Sprintf("%d", p.Count),
+		"--node-vm-size", p.VMSize,
 	}
 
-	if pool.EnableAutoScale {
+	if p.EnableAutoScale {
 		args = append(args,
 			"--enable-cluster-autoscaler",
-			"--min-count", fmt.Sprintf("%d", pool.MinCount),
-			"--max-count", fmt.Sprintf("%d", pool.MaxCount))
+			"--min-count", fmt.Sprintf("%d", p.MinCount),
+			"--max-count", fmt.Sprintf("%d", p.MaxCount))
+	}
+
+	if p.OSDiskSizeGB > 0 {
+		args = append(args, "--node-osdisk-size", fmt.Sprintf("%d", p.OSDiskSizeGB))
 	}
 
-	if pool.OSDiskSizeGB > 0 {
-		args = append(args, "--node-osdisk-size", fmt.Sprintf("%d", pool.OSDiskSizeGB))
+	// Evicted spot nodes are deleted so the autoscaler can replace them;
+	// a max price of -1 means pay up to the on-demand price
+	if p.Spot {
+		maxPrice := p.SpotMaxPrice
+		if maxPrice == "" {
+			maxPrice = "-1"
+		}
+		args = append(args, "--priority", "Spot", "--eviction-policy", "Delete", "--spot-max-price", maxPrice)
 	}
 
+	return args
+}
+
+// CreateNodePool creates an additional node pool
+func (a *AKSInstaller) CreateNodePool(ctx context.Context, resourceGroup, clusterName string, pool *AKSNodePoolConfig, logFile string) error {
+	args := pool.CreateArgs(resourceGroup, clusterName)
+
 	cmd := exec.CommandContext(ctx, a.binaryPath, args...)
 
 	f, err := os.OpenFile(logFile, os.O_APPEND|os.O_WRONLY, 0644)
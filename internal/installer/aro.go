@@ -43,6 +43,7 @@ type AROClusterConfig struct {
 	MasterVMSize     string
 	WorkerVMSize     string
 	WorkerCount      int
+	WorkerDiskSizeGB int // 0 = ARO default
 	OpenShiftVersion string
 	PullSecret       string
 	Tags             map[string]string
@@ -420,27 +421,29 @@ func isDuplicateClientID(s string) bool {
 		(strings.Contains(l, "client id") && strings.Contains(l, "already"))
 }
 
-// CreateCluster creates an ARO cluster using az aro create
-func (a *AROInstaller) CreateCluster(ctx context.Context, config *AROClusterConfig, logFile string, vnetName, masterSubnetName, workerSubnetName string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, a.timeout)
-	defer cancel()
-
+// CreateArgs builds the az aro create arguments for the cluster in the given
+// VNet and subnets
+func (c *AROClusterConfig) CreateArgs(vnetName, masterSubnetName, workerSubnetName string) []string {
 	// Build az aro create command
 	args := []string{
 		"aro", "create",
-		"--resource-group", config.ResourceGroup,
-		"--name", config.Name,
+		"--resource-group", c.ResourceGroup,
+		"--name", c.Name,
 		"--vnet", vnetName,
 		"--master-subnet", masterSubnetName,
 		"--worker-subnet", workerSubnetName,
-		"--master-vm-size", config.MasterVMSize,
-		"--worker-vm-size", config.WorkerVMSize,
-		"--worker-count", fmt.Sprintf("%d", config.WorkerCount),
+		"--master-vm-size", c.MasterVMSize,
+		"--worker-vm-size", c.WorkerVMSize,
+		"--worker-count", fmt.Sprintf("%d", c.WorkerCount),
+	}
+
+	if c.WorkerDiskSizeGB > 0 {
+		args = append(args, "--worker-vm-disk-size-gb", fmt.Sprintf("%d", c.WorkerDiskSizeGB))
 	}
 
 	// Add version if specified
-	if config.OpenShiftVersion != "" {
-		args = append(args, "--version", config.OpenShiftVersion)
+	if c.OpenShiftVersion != "" {
+		args = append(args, "--version", c.OpenShiftVersion)
 	}
 
 	// BYO service principal (Red Hat recommended pattern). Passing an existing SP
@@ -453,25 +456,35 @@ func (a *AROInstaller) CreateCluster(ctx context.Context, config *AROClusterConf
 	// "Invalid client secret" — the literal "@/path" is sent). argv is the only
 	// supported form, so #81 must be mitigated by blast-radius reduction instead
 	// (dedicated least-privilege ARO SP / short-lived secret / managed identity).
-	if config.ClientID != "" && config.ClientSecret != "" {
-		args = append(args, "--client-id", config.ClientID, "--client-secret", config.ClientSecret)
+	if c.ClientID != "" && c.ClientSecret != "" {
+		args = append(args, "--client-id", c.ClientID, "--client-secret", c.ClientSecret)
 	}
 
 	// Add pull secret
-	if config.PullSecret != "" {
-		args = append(args, "--pull-secret", config.PullSecret)
+	if c.PullSecret != "" {
+		args = append(args, "--pull-secret", c.PullSecret)
 	}
 
 	// Add tags. Each key=value pair must be a separate argument to --tags; joining
 	// them into a single space-delimited string makes az treat the whole string as
 	// one tag value, which trips Azure's 256-char tag-value limit.
-	if len(config.Tags) > 0 {
+	if len(c.Tags) > 0 {
 		args = append(args, "--tags")
-		for k, v := range config.Tags {
-			args = append(args, fmt.Sprintf("%s=%s", k, v))
+		for _, k := range sortedKeys(c.Tags) {
+			args = append(args, fmt.Sprintf("%s=%s", k, c.Tags[k]))
 		}
 	}
 
+	return args
+}
+
+// CreateCluster creates an ARO cluster using az aro create
+func (a *AROInstaller) CreateCluster(ctx context.Context, config *AROClusterConfig, logFile string, vnetName, masterSubnetName, workerSubnetName string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	args := config.CreateArgs(vnetName, masterSubnetName, workerSubnetName)
+
 	cmd := exec.CommandContext(ctx, a.binaryPath, args...)
 
 	// Open log file for appending
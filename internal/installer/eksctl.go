@@ -45,7 +45,7 @@ type EKSIAM struct {
 // EKSNodeGroup represents a node group configuration
 type EKSNodeGroup struct {
 	Name            string            `yaml:"name"`
-	InstanceType    string            `yaml:"instanceType"`
+	InstanceType    string            `yaml:"instanceType,omitempty"` // Empty when InstancesDistribution is set
 	DesiredCapacity int               `yaml:"desiredCapacity"`
 	MinSize         int               `yaml:"minSize"`
 	MaxSize         int               `yaml:"maxSize"`
@@ -53,6 +53,18 @@ type EKSNodeGroup struct {
 	VolumeType      string            `yaml:"volumeType,omitempty"`
 	SSH             *EKSNodeGroupSSH  `yaml:"ssh,omitempty"`
 	Tags            map[string]string `yaml:"tags,omitempty"`
+
+	InstancesDistribution *EKSInstancesDistribution `yaml:"instancesDistribution,omitempty"` // Spot/mixed-instance group
+}
+
+// EKSInstancesDistribution is the mixed-instance policy of an unmanaged node
+// group's auto scaling group
+type EKSInstancesDistribution struct {
+	InstanceTypes                       []string `yaml:"instanceTypes"`
+	MaxPrice                            *float64 `yaml:"maxPrice,omitempty"`
+	OnDemandBaseCapacity                int      `yaml:"onDemandBaseCapacity"`
+	OnDemandPercentageAboveBaseCapacity int      `yaml:"onDemandPercentageAboveBaseCapacity"`
+	SpotAllocationStrategy              string   `yaml:"spotAllocationStrategy,omitempty"`
 }
 
 // EKSNodeGroupSSH represents SSH configuration for nodes
@@ -64,7 +76,9 @@ type EKSNodeGroupSSH struct {
 // EKSManagedNodeGroup represents a managed node group configuration
 type EKSManagedNodeGroup struct {
 	Name            string            `yaml:"name"`
-	InstanceType    string            `yaml:"instanceType"`
+	InstanceType    string            `yaml:"instanceType,omitempty"` // Empty when InstanceTypes is set
+	InstanceTypes   []string          `yaml:"instanceTypes,omitempty"`
+	Spot            bool              `yaml:"spot,omitempty"`
 	DesiredCapacity int               `yaml:"desiredCapacity"`
 	MinSize         int               `yaml:"minSize"`
 	MaxSize         int               `yaml:"maxSize"`
@@ -57,6 +57,7 @@ type GKENodePoolConfig struct {
 	MinNodes          int               `json:"min_nodes,omitempty"`
 	MaxNodes          int               `json:"max_nodes,omitempty"`
 	EnableAutoscaling bool              `json:"enable_autoscaling,omitempty"`
+	Spot              bool              `json:"spot,omitempty"` // Preemptible Spot VMs
 	Labels            map[string]string `json:"labels,omitempty"`
 	Tags              []string          `json:"tags,omitempty"`
 }
@@ -118,107 +119,104 @@ func (g *GKEInstaller) CreateNetwork(ctx context.Context, networkName, project s
 	return nil
 }
 
-// CreateCluster creates a GKE cluster using gcloud
-func (g *GKEInstaller) CreateCluster(ctx context.Context, config *GKEClusterConfig, logFile string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, g.timeout)
-	defer cancel()
-
-	// Create VPC network if not specified
-	if config.Network == "" {
-		networkName := config.Name + "-vpc"
-		if err := g.CreateNetwork(ctx, networkName, config.Project); err != nil {
-			return "", fmt.Errorf("create VPC network: %w", err)
-		}
-		config.Network = networkName
+// NetworkName returns the VPC network the cluster is created in. Clusters
+// without an explicit network get a dedicated auto-mode VPC named after them.
+func (c *GKEClusterConfig) NetworkName() string {
+	if c.Network != "" {
+		return c.Network
 	}
+	return c.Name + "-vpc"
+}
 
-	// Build gcloud command arguments
+// CreateArgs builds the gcloud arguments for creating the cluster's default
+// node pool. Additional node pools are created separately after the cluster.
+func (c *GKEClusterConfig) CreateArgs() ([]string, error) {
 	args := []string{
-		"container", "clusters", "create", config.Name,
-		"--project", config.Project,
+		"container", "clusters", "create", c.Name,
+		"--project", c.Project,
 		// Don't use --no-user-output-enabled as it suppresses ALL output
 		// gcloud is inherently quiet during cluster creation
 	}
 
 	// Add region or zone
-	if config.Region != "" {
-		args = append(args, "--region", config.Region)
-	} else if config.Zone != "" {
-		args = append(args, "--zone", config.Zone)
+	if c.Region != "" {
+		args = append(args, "--region", c.Region)
+	} else if c.Zone != "" {
+		args = append(args, "--zone", c.Zone)
 	} else {
-		return "", fmt.Errorf("either region or zone must be specified")
+		return nil, fmt.Errorf("either region or zone must be specified")
 	}
 
 	// Add cluster version
-	if config.ClusterVersion != "" {
-		args = append(args, "--cluster-version", config.ClusterVersion)
+	if c.ClusterVersion != "" {
+		args = append(args, "--cluster-version", c.ClusterVersion)
 	}
 
 	// Add release channel
-	if config.ReleaseChannel != "" {
-		args = append(args, "--release-channel", config.ReleaseChannel)
+	if c.ReleaseChannel != "" {
+		args = append(args, "--release-channel", c.ReleaseChannel)
 	}
 
 	// Add network configuration
 	args = append(args, "--enable-ip-alias")
-	args = append(args, "--network", config.Network)
+	args = append(args, "--network", c.NetworkName())
 
 	// Auto-create subnetwork within the VPC
-	if config.Subnetwork == "" {
-		args = append(args, "--create-subnetwork", "name="+config.Name+"-subnet")
+	if c.Subnetwork == "" {
+		args = append(args, "--create-subnetwork", "name="+c.Name+"-subnet")
 	} else {
-		args = append(args, "--subnetwork", config.Subnetwork)
+		args = append(args, "--subnetwork", c.Subnetwork)
 	}
 
 	// Add Workload Identity
-	if config.EnableWorkloadIdentity {
-		args = append(args, "--workload-pool="+config.Project+".svc.id.goog")
+	if c.EnableWorkloadIdentity {
+		args = append(args, "--workload-pool="+c.Project+".svc.id.goog")
 	}
 
 	// Add node configuration (for default node pool)
-	if config.MachineType != "" {
-		args = append(args, "--machine-type", config.MachineType)
+	if c.MachineType != "" {
+		args = append(args, "--machine-type", c.MachineType)
 	}
-	if config.DiskType != "" {
-		args = append(args, "--disk-type", config.DiskType)
+	if c.DiskType != "" {
+		args = append(args, "--disk-type", c.DiskType)
 	}
-	if config.DiskSize > 0 {
-		args = append(args, "--disk-size", fmt.Sprintf("%d", config.DiskSize))
+	if c.DiskSize > 0 {
+		args = append(args, "--disk-size", fmt.Sprintf("%d", c.DiskSize))
 	}
 
 	// Add autoscaling
-	if config.EnableAutoscaling {
+	if c.EnableAutoscaling {
 		args = append(args, "--enable-autoscaling")
-		if config.MinNodes > 0 {
-			args = append(args, "--min-nodes", fmt.Sprintf("%d", config.MinNodes))
+		if c.MinNodes > 0 {
+			args = append(args, "--min-nodes", fmt.Sprintf("%d", c.MinNodes))
 		}
-		if config.MaxNodes > 0 {
-			args = append(args, "--max-nodes", fmt.Sprintf("%d", config.MaxNodes))
+		if c.MaxNodes > 0 {
+			args = append(args, "--max-nodes", fmt.Sprintf("%d", c.MaxNodes))
 		}
-	} else if config.NumNodes > 0 {
-		args = append(args, "--num-nodes", fmt.Sprintf("%d", config.NumNodes))
+	} else if c.NumNodes > 0 {
+		args = append(args, "--num-nodes", fmt.Sprintf("%d", c.NumNodes))
 	}
 
 	// Add labels
-	if len(config.Labels) > 0 {
+	if len(c.Labels) > 0 {
 		labelPairs := []string{}
-		for k, v := range config.Labels {
-			labelPairs = append(labelPairs, fmt.Sprintf("%s=%s", k, v))
+		for _, k := range sortedKeys(c.Labels) {
+			labelPairs = append(labelPairs, fmt.Sprintf("%s=%s", k, c.Labels[k]))
 		}
 		args = append(args, "--labels", strings.Join(labelPairs, ","))
 	}
 
 	// Add tags
-	if len(config.Tags) > 0 {
-		args = append(args, "--tags", strings.Join(config.Tags, ","))
+	if len(c.Tags) > 0 {
+		args = append(args, "--tags", strings.Join(c.Tags, ","))
 	}
 
 	// Add logging (use modern --logging flag, default SYSTEM is enabled automatically)
 	// Skip deprecated --enable-cloud-logging flag
-	if len(config.EnableClusterLogging) > 0 {
+	if len(c.EnableClusterLogging) > 0 {
 		// Map old values to new: SYSTEM_COMPONENTS -> SYSTEM, WORKLOADS -> WORKLOAD
 		modernValues := []string{}
-		for _, v := range config.EnableClusterLogging {
+		for _, v := range c.EnableClusterLogging {
 			if v == "SYSTEM_COMPONENTS" {
 				modernValues = append(modernValues, "SYSTEM")
 			} else if v == "WORKLOADS" {
@@ -234,10 +232,10 @@ func (g *GKEInstaller) CreateCluster(ctx context.Context, config *GKEClusterConf
 
 	// Add monitoring (use modern --monitoring flag, default SYSTEM is enabled automatically)
 	// Skip deprecated --enable-cloud-monitoring flag
-	if len(config.EnableClusterMonitoring) > 0 {
+	if len(c.EnableClusterMonitoring) > 0 {
 		// Map old values to new: SYSTEM_COMPONENTS -> SYSTEM, WORKLOADS -> WORKLOAD
 		modernValues := []string{}
-		for _, v := range config.EnableClusterMonitoring {
+		for _, v := range c.EnableClusterMonitoring {
 			if v == "SYSTEM_COMPONENTS" {
 				modernValues = append(modernValues, "SYSTEM")
 			} else if v == "WORKLOADS" {
@@ -252,12 +250,34 @@ func (g *GKEInstaller) CreateCluster(ctx context.Context, config *GKEClusterConf
 	}
 
 	// Add access configuration
-	if config.PrivateAccess {
+	if c.PrivateAccess {
 		args = append(args, "--enable-master-authorized-networks")
 		args = append(args, "--enable-ip-alias")
 		args = append(args, "--enable-private-nodes")
 	}
 
+	return args, nil
+}
+
+// CreateCluster creates a GKE cluster using gcloud
+func (g *GKEInstaller) CreateCluster(ctx context.Context, config *GKEClusterConfig, logFile string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	// Create VPC network if not specified
+	if config.Network == "" {
+		networkName := config.NetworkName()
+		if err := g.CreateNetwork(ctx, networkName, config.Project); err != nil {
+			return "", fmt.Errorf("create VPC network: %w", err)
+		}
+		config.Network = networkName
+	}
+
+	args, err := config.CreateArgs()
+	if err != nil {
+		return "", err
+	}
+
 	cmd := exec.CommandContext(ctx, g.binaryPath, args...)
 
 	// Open log file for appending (not truncating)
@@ -296,10 +316,11 @@ func (g *GKEInstaller) CreateCluster(ctx context.Context, config *GKEClusterConf
 	return string(logData), nil
 }
 
-// CreateNodePool creates an additional node pool in an existing GKE cluster
-func (g *GKEInstaller) CreateNodePool(ctx context.Context, clusterName, project, region, zone string, pool *GKENodePoolConfig, logFile string) error {
+// CreateArgs builds the gcloud arguments for adding the node pool to an
+// existing cluster
+func (p *GKENodePoolConfig) CreateArgs(clusterName, project, region, zone string) []string {
 	args := []string{
-		"container", "node-pools", "create", pool.Name,
+		"container", "node-pools", "create", p.Name,
 		"--cluster", clusterName,
 		"--project", project,
 	}
@@ -312,43 +333,54 @@ func (g *GKEInstaller) CreateNodePool(ctx context.Context, clusterName, project,
 	}
 
 	// Add node configuration
-	if pool.MachineType != "" {
-		args = append(args, "--machine-type", pool.MachineType)
+	if p.MachineType != "" {
+		args = append(args, "--machine-type", p.MachineType)
 	}
-	if pool.DiskType != "" {
-		args = append(args, "--disk-type", pool.DiskType)
+	if p.DiskType != "" {
+		args = append(args, "--disk-type", p.DiskType)
 	}
-	if pool.DiskSize > 0 {
-		args = append(args, "--disk-size", fmt.Sprintf("%d", pool.DiskSize))
+	if p.DiskSize > 0 {
+		args = append(args, "--disk-size", fmt.Sprintf("%d", p.DiskSize))
 	}
 
 	// Add autoscaling
-	if pool.EnableAutoscaling {
+	if p.EnableAutoscaling {
 		args = append(args, "--enable-autoscaling")
-		if pool.MinNodes > 0 {
-			args = append(args, "--min-nodes", fmt.Sprintf("%d", pool.MinNodes))
+		if p.MinNodes > 0 {
+			args = append(args, "--min-nodes", fmt.Sprintf("%d", p.MinNodes))
 		}
-		if pool.MaxNodes > 0 {
-			args = append(args, "--max-nodes", fmt.Sprintf("%d", pool.MaxNodes))
+		if p.MaxNodes > 0 {
+			args = append(args, "--max-nodes", fmt.Sprintf("%d", p.MaxNodes))
 		}
-	} else if pool.NumNodes > 0 {
-		args = append(args, "--num-nodes", fmt.Sprintf("%d", pool.NumNodes))
+	} else if p.NumNodes > 0 {
+		args = append(args, "--num-nodes", fmt.Sprintf("%d", p.NumNodes))
+	}
+
+	if p.Spot {
+		args = append(args, "--spot")
 	}
 
 	// Add labels
-	if len(pool.Labels) > 0 {
+	if len(p.Labels) > 0 {
 		labelPairs := []string{}
-		for k, v := range pool.Labels {
-			labelPairs = append(labelPairs, fmt.Sprintf("%s=%s", k, v))
+		for _, k := range sortedKeys(p.Labels) {
+			labelPairs = append(labelPairs, fmt.Sprintf("%s=%s", k, p.Labels[k]))
 		}
 		args = append(args, "--node-labels", strings.Join(labelPairs, ","))
 	}
 
 	// Add tags
-	if len(pool.Tags) > 0 {
-		args = append(args, "--tags", strings.Join(pool.Tags, ","))
+	if len(p.Tags) > 0 {
+		args = append(args, "--tags", strings.Join(p.Tags, ","))
 	}
 
+	return args
+}
+
+// CreateNodePool creates an additional node pool in an existing GKE cluster
+func (g *GKEInstaller) CreateNodePool(ctx context.Context, clusterName, project, region, zone string, pool *GKENodePoolConfig, logFile string) error {
+	args := pool.CreateArgs(clusterName, project, region, zone)
+
 	cmd := exec.CommandContext(ctx, g.binaryPath, args...)
 
 	// Append to log file
@@ -0,0 +1,637 @@
+package installer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// HCPDefaultNamespace is where HostedClusters are created on the
+	// management cluster unless the profile says otherwise
+	HCPDefaultNamespace = "clusters"
+
+	hypershiftAPIVersion = "hypershift.openshift.io/v1beta1"
+)
+
+// ErrHCPNotFound is returned by a management client for a missing object
+var ErrHCPNotFound = errors.New("not found")
+
+// HCPManagementClient is the subset of management-cluster API operations the
+// HCP installer needs. Resources are oc resource names (hostedcluster,
+// nodepool, secret). The default implementation shells out to oc.
+type HCPManagementClient interface {
+	Apply(ctx context.Context, obj map[string]interface{}) error
+	Get(ctx context.Context, resource, namespace, name string) (map[string]interface{}, error)
+	List(ctx context.Context, resource, namespace string) ([]map[string]interface{}, error)
+	Patch(ctx context.Context, resource, namespace, name string, mergePatch []byte) error
+	Delete(ctx context.Context, resource, namespace, name string) error
+}
+
+// HCPInstaller creates hosted control plane clusters as HostedCluster and
+// NodePool resources on a management cluster. Worker nodes are KubeVirt VMs
+// on the management cluster, so clusters need no cloud account of their own.
+type HCPInstaller struct {
+	client       HCPManagementClient
+	namespace    string
+	pollInterval time.Duration
+}
+
+// HCPClusterConfig describes a hosted cluster and its node pools
+type HCPClusterConfig struct {
+	Name         string
+	ReleaseImage string
+	BaseDomain   string // Empty lets HyperShift derive it from the management cluster's ingress
+	PullSecret   string
+	SSHPublicKey string
+	// SingleReplica (cheap, default) or HighlyAvailable control plane pods
+	ControllerAvailabilityPolicy string
+	NetworkType                  string
+	ClusterCIDR                  string
+	ServiceCIDR                  string
+	NodePools                    []HCPNodePoolConfig
+	Labels                       map[string]string
+}
+
+// HCPNodePoolConfig is one NodePool of KubeVirt worker VMs
+type HCPNodePoolConfig struct {
+	Name         string
+	Replicas     int
+	Cores        int
+	MemoryGiB    int
+	RootVolumeGB int
+}
+
+// HCPClusterInfo is the state of a hosted cluster read from its HostedCluster
+type HCPClusterInfo struct {
+	Name       string
+	APIURL     string
+	ConsoleURL string
+	Available  bool
+	Version    string
+}
+
+// NewHCPInstaller creates an HCP installer using the given management client
+func NewHCPInstaller(client HCPManagementClient, namespace string) *HCPInstaller {
+	if namespace == "" {
+		namespace = HCPDefaultNamespace
+	}
+	return &HCPInstaller{
+		client:       client,
+		namespace:    namespace,
+		pollInterval: 30 * time.Second,
+	}
+}
+
+// NewHCPInstallerFromEnv creates an HCP installer for the management cluster
+// whose kubeconfig is in HCP_MANAGEMENT_KUBECONFIG
+func NewHCPInstallerFromEnv(namespace string) (*HCPInstaller, error) {
+	kubeconfig := os.Getenv("HCP_MANAGEMENT_KUBECONFIG")
+	if kubeconfig == "" {
+		return nil, fmt.Errorf("HCP_MANAGEMENT_KUBECONFIG environment variable not set")
+	}
+	return NewHCPInstaller(NewOCManagementClient(kubeconfig), namespace), nil
+}
+
+// Namespace returns the management-cluster namespace holding HostedClusters
+func (h *HCPInstaller) Namespace() string {
+	return h.namespace
+}
+
+// HCPReleaseImage returns the OCP release image for a full X.Y.Z version
+func HCPReleaseImage(version, arch string) (string, error) {
+	if strings.Count(version, ".") < 2 {
+		return "", fmt.Errorf("hosted clusters need a full X.Y.Z OpenShift version, got %q", version)
+	}
+	if arch == "" {
+		arch = "x86_64"
+	}
+	return fmt.Sprintf("quay.io/openshift-release-dev/ocp-release:%s-%s", version, arch), nil
+}
+
+// Objects returns the management-cluster objects for the hosted cluster, in
+// apply order: secrets, the HostedCluster, then its NodePools
+func (c *HCPClusterConfig) Objects(namespace string) ([]map[string]interface{}, error) {
+	if len(c.NodePools) == 0 {
+		return nil, fmt.Errorf("at least one node pool required")
+	}
+
+	pullSecretName := c.Name + "-pull-secret"
+	objects := []map[string]interface{}{
+		{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   objectMeta(pullSecretName, namespace, c.Labels),
+			"type":       "kubernetes.io/dockerconfigjson",
+			"stringData": map[string]interface{}{".dockerconfigjson": c.PullSecret},
+		},
+	}
+
+	availability := c.ControllerAvailabilityPolicy
+	if availability == "" {
+		availability = "SingleReplica"
+	}
+	networkType := c.NetworkType
+	if networkType == "" {
+		networkType = "OVNKubernetes"
+	}
+	clusterCIDR := c.ClusterCIDR
+	if clusterCIDR == "" {
+		clusterCIDR = "10.132.0.0/14"
+	}
+	serviceCIDR := c.ServiceCIDR
+	if serviceCIDR == "" {
+		serviceCIDR = "172.31.0.0/16"
+	}
+
+	spec := map[string]interface{}{
+		"release":                          map[string]interface{}{"image": c.ReleaseImage},
+		"pullSecret":                       map[string]interface{}{"name": pullSecretName},
+		"controllerAvailabilityPolicy":     availability,
+		"infrastructureAvailabilityPolicy": availability,
+		"networking": map[string]interface{}{
+			"networkType":    networkType,
+			"clusterNetwork": []interface{}{map[string]interface{}{"cidr": clusterCIDR}},
+			"serviceNetwork": []interface{}{map[string]interface{}{"cidr": serviceCIDR}},
+		},
+		"platform": map[string]interface{}{
+			"type":     "KubeVirt",
+			"kubevirt": map[string]interface{}{},
+		},
+		"etcd": map[string]interface{}{
+			"managementType": "Managed",
+			"managed": map[string]interface{}{
+				"storage": map[string]interface{}{
+					"type":             "PersistentVolume",
+					"persistentVolume": map[string]interface{}{"size": "8Gi"},
+				},
+			},
+		},
+		"services": []interface{}{
+			servicePublishing("APIServer", "LoadBalancer"),
+			servicePublishing("OAuthServer", "Route"),
+			servicePublishing("Konnectivity", "Route"),
+			servicePublishing("Ignition", "Route"),
+		},
+	}
+	if c.BaseDomain != "" {
+		spec["dns"] = map[string]interface{}{"baseDomain": c.BaseDomain}
+	}
+	if c.SSHPublicKey != "" {
+		sshKeyName := c.Name + "-ssh-key"
+		objects = append(objects, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   objectMeta(sshKeyName, namespace, c.Labels),
+			"stringData": map[string]interface{}{"id_rsa.pub": c.SSHPublicKey},
+		})
+		spec["sshKey"] = map[string]interface{}{"name": sshKeyName}
+	}
+
+	objects = append(objects, map[string]interface{}{
+		"apiVersion": hypershiftAPIVersion,
+		"kind":       "HostedCluster",
+		"metadata":   objectMeta(c.Name, namespace, c.Labels),
+		"spec":       spec,
+	})
+
+	for _, pool := range c.NodePools {
+		kubevirt := map[string]interface{}{
+			"compute": map[string]interface{}{
+				"cores":  pool.Cores,
+				"memory": fmt.Sprintf("%dGi", pool.MemoryGiB),
+			},
+		}
+		if pool.RootVolumeGB > 0 {
+			kubevirt["rootVolume"] = map[string]interface{}{
+				"type":       "Persistent",
+				"persistent": map[string]interface{}{"size": fmt.Sprintf("%dGi", pool.RootVolumeGB)},
+			}
+		}
+		objects = append(objects, map[string]interface{}{
+			"apiVersion": hypershiftAPIVersion,
+			"kind":       "NodePool",
+			"metadata":   objectMeta(c.NodePoolName(pool.Name), namespace, c.Labels),
+			"spec": map[string]interface{}{
+				"clusterName": c.Name,
+				"replicas":    pool.Replicas,
+				"management":  map[string]interface{}{"upgradeType": "Replace"},
+				"release":     map[string]interface{}{"image": c.ReleaseImage},
+				"platform": map[string]interface{}{
+					"type":     "KubeVirt",
+					"kubevirt": kubevirt,
+				},
+			},
+		})
+	}
+
+	return objects, nil
+}
+
+// NodePoolName returns the NodePool object name for a pool of this cluster
+func (c *HCPClusterConfig) NodePoolName(pool string) string {
+	return c.Name + "-" + pool
+}
+
+// RenderObjects renders the objects as a multi-document YAML stream
+func RenderObjects(objects []map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, obj := range objects {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// CreateCluster applies the hosted cluster's secrets, HostedCluster and
+// NodePools. Applying is idempotent, so a retried job converges on the same
+// objects.
+func (h *HCPInstaller) CreateCluster(ctx context.Context, config *HCPClusterConfig) error {
+	objects, err := config.Objects(h.namespace)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		if err := h.client.Apply(ctx, obj); err != nil {
+			return fmt.Errorf("apply %s %s: %w", obj["kind"], nestedString(obj, "metadata", "name"), err)
+		}
+	}
+	return nil
+}
+
+// WaitForReady polls until the HostedCluster is Available and every NodePool
+// has its requested replicas, or ctx is done
+func (h *HCPInstaller) WaitForReady(ctx context.Context, name string) (*HCPClusterInfo, error) {
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		info, err := h.GetClusterInfo(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if info.Available {
+			ready, err := h.nodePoolsReady(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			if ready {
+				return info, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for hosted cluster %s: %w", name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetClusterInfo reads the hosted cluster's endpoint and availability
+func (h *HCPInstaller) GetClusterInfo(ctx context.Context, name string) (*HCPClusterInfo, error) {
+	hc, err := h.client.Get(ctx, "hostedcluster", h.namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("get hostedcluster %s: %w", name, err)
+	}
+
+	info := &HCPClusterInfo{
+		Name:      name,
+		Available: conditionTrue(hc, "Available"),
+		Version:   nestedString(hc, "status", "version", "desired", "version"),
+	}
+	if host := nestedString(hc, "status", "controlPlaneEndpoint", "host"); host != "" {
+		port := nestedInt(hc, "status", "controlPlaneEndpoint", "port")
+		if port == 0 {
+			port = 6443
+		}
+		info.APIURL = fmt.Sprintf("https://%s:%d", host, port)
+	}
+	if baseDomain := nestedString(hc, "spec", "dns", "baseDomain"); baseDomain != "" {
+		info.ConsoleURL = fmt.Sprintf("https://console-openshift-console.apps.%s.%s", name, baseDomain)
+	}
+	return info, nil
+}
+
+// GetKubeconfig returns the hosted cluster's admin kubeconfig
+func (h *HCPInstaller) GetKubeconfig(ctx context.Context, name string) ([]byte, error) {
+	return h.statusSecret(ctx, name, "kubeconfig", name+"-admin-kubeconfig", "kubeconfig")
+}
+
+// GetKubeadminPassword returns the hosted cluster's kubeadmin password
+func (h *HCPInstaller) GetKubeadminPassword(ctx context.Context, name string) (string, error) {
+	password, err := h.statusSecret(ctx, name, "kubeadminPassword", name+"-kubeadmin-password", "password")
+	if err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
+
+// statusSecret reads a key from the secret the HostedCluster status points
+// at, falling back to the conventional name
+func (h *HCPInstaller) statusSecret(ctx context.Context, cluster, statusField, fallback, key string) ([]byte, error) {
+	secretName := fallback
+	if hc, err := h.client.Get(ctx, "hostedcluster", h.namespace, cluster); err == nil {
+		if ref := nestedString(hc, "status", statusField, "name"); ref != "" {
+			secretName = ref
+		}
+	}
+
+	secret, err := h.client.Get(ctx, "secret", h.namespace, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("get secret %s: %w", secretName, err)
+	}
+	encoded := nestedString(secret, "data", key)
+	if encoded == "" {
+		return nil, fmt.Errorf("secret %s has no %s", secretName, key)
+	}
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode secret %s: %w", secretName, err)
+	}
+	return value, nil
+}
+
+// DestroyCluster deletes the hosted cluster's NodePools, HostedCluster and
+// secrets, and waits for the HostedCluster to be gone. Missing objects are
+// ignored so destroy can be retried.
+func (h *HCPInstaller) DestroyCluster(ctx context.Context, name string) error {
+	pools, err := h.nodePools(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, pool := range pools {
+		poolName := nestedString(pool, "metadata", "name")
+		if err := h.client.Delete(ctx, "nodepool", h.namespace, poolName); err != nil {
+			return fmt.Errorf("delete nodepool %s: %w", poolName, err)
+		}
+	}
+
+	if err := h.client.Delete(ctx, "hostedcluster", h.namespace, name); err != nil {
+		return fmt.Errorf("delete hostedcluster %s: %w", name, err)
+	}
+
+	// The HyperShift operator tears down the control plane namespace and
+	// KubeVirt VMs before dropping the HostedCluster finalizer
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+	for {
+		_, err := h.client.Get(ctx, "hostedcluster", h.namespace, name)
+		if errors.Is(err, ErrHCPNotFound) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("get hostedcluster %s: %w", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for hosted cluster %s deletion: %w", name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	for _, secret := range []string{name + "-pull-secret", name + "-ssh-key"} {
+		if err := h.client.Delete(ctx, "secret", h.namespace, secret); err != nil {
+			return fmt.Errorf("delete secret %s: %w", secret, err)
+		}
+	}
+	return nil
+}
+
+// Hibernate scales every NodePool of the cluster to zero and returns the
+// replica counts it had, keyed by NodePool name. The control plane keeps
+// running on the management cluster.
+func (h *HCPInstaller) Hibernate(ctx context.Context, name string) (map[string]int, error) {
+	pools, err := h.nodePools(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make(map[string]int)
+	for _, pool := range pools {
+		poolName := nestedString(pool, "metadata", "name")
+		count := nestedInt(pool, "spec", "replicas")
+		if count == 0 {
+			continue
+		}
+		if err := h.scaleNodePool(ctx, poolName, 0); err != nil {
+			return nil, err
+		}
+		replicas[poolName] = count
+	}
+	return replicas, nil
+}
+
+// Resume restores NodePool replica counts recorded by Hibernate
+func (h *HCPInstaller) Resume(ctx context.Context, replicas map[string]int) error {
+	names := make([]string, 0, len(replicas))
+	for name := range replicas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := h.scaleNodePool(ctx, name, replicas[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *HCPInstaller) scaleNodePool(ctx context.Context, name string, replicas int) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	if err := h.client.Patch(ctx, "nodepool", h.namespace, name, patch); err != nil {
+		return fmt.Errorf("scale nodepool %s to %d: %w", name, replicas, err)
+	}
+	return nil
+}
+
+// nodePools returns the NodePools belonging to a hosted cluster
+func (h *HCPInstaller) nodePools(ctx context.Context, cluster string) ([]map[string]interface{}, error) {
+	all, err := h.client.List(ctx, "nodepool", h.namespace)
+	if err != nil {
+		return nil, fmt.Errorf("list nodepools: %w", err)
+	}
+	var pools []map[string]interface{}
+	for _, pool := range all {
+		if nestedString(pool, "spec", "clusterName") == cluster {
+			pools = append(pools, pool)
+		}
+	}
+	return pools, nil
+}
+
+// nodePoolsReady reports whether every NodePool has its requested replicas
+func (h *HCPInstaller) nodePoolsReady(ctx context.Context, cluster string) (bool, error) {
+	pools, err := h.nodePools(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+	if len(pools) == 0 {
+		return false, nil
+	}
+	for _, pool := range pools {
+		if nestedInt(pool, "status", "replicas") < nestedInt(pool, "spec", "replicas") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ocManagementClient implements HCPManagementClient with the oc CLI
+type ocManagementClient struct {
+	binaryPath string
+	kubeconfig string
+}
+
+// NewOCManagementClient returns a management client that runs oc against the
+// given kubeconfig
+func NewOCManagementClient(kubeconfig string) HCPManagementClient {
+	binaryPath := os.Getenv("OC_BINARY")
+	if binaryPath == "" {
+		binaryPath = "oc"
+	}
+	return &ocManagementClient{binaryPath: binaryPath, kubeconfig: kubeconfig}
+}
+
+func (o *ocManagementClient) run(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, o.binaryPath, append([]string{"--kubeconfig", o.kubeconfig}, args...)...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "NotFound") || strings.Contains(stderr.String(), "not found") {
+			return nil, ErrHCPNotFound
+		}
+		return nil, fmt.Errorf("oc %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+func (o *ocManagementClient) Apply(ctx context.Context, obj map[string]interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	_, err = o.run(ctx, data, "apply", "-f", "-")
+	return err
+}
+
+func (o *ocManagementClient) Get(ctx context.Context, resource, namespace, name string) (map[string]interface{}, error) {
+	output, err := o.run(ctx, nil, "get", resource, name, "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(output, &obj); err != nil {
+		return nil, fmt.Errorf("parse %s %s: %w", resource, name, err)
+	}
+	return obj, nil
+}
+
+func (o *ocManagementClient) List(ctx context.Context, resource, namespace string) ([]map[string]interface{}, error) {
+	output, err := o.run(ctx, nil, "get", resource, "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("parse %s list: %w", resource, err)
+	}
+	return list.Items, nil
+}
+
+func (o *ocManagementClient) Patch(ctx context.Context, resource, namespace, name string, mergePatch []byte) error {
+	_, err := o.run(ctx, nil, "patch", resource, name, "-n", namespace, "--type=merge", "-p", string(mergePatch))
+	return err
+}
+
+func (o *ocManagementClient) Delete(ctx context.Context, resource, namespace, name string) error {
+	_, err := o.run(ctx, nil, "delete", resource, name, "-n", namespace, "--ignore-not-found", "--wait=false")
+	return err
+}
+
+// objectMeta builds metadata for a namespaced object
+func objectMeta(name, namespace string, labels map[string]string) map[string]interface{} {
+	meta := map[string]interface{}{"name": name, "namespace": namespace}
+	if len(labels) > 0 {
+		l := make(map[string]interface{}, len(labels))
+		for k, v := range labels {
+			l[k] = v
+		}
+		meta["labels"] = l
+	}
+	return meta
+}
+
+func servicePublishing(service, strategy string) map[string]interface{} {
+	return map[string]interface{}{
+		"service":                   service,
+		"servicePublishingStrategy": map[string]interface{}{"type": strategy},
+	}
+}
+
+// conditionTrue reports whether status.conditions has type=True
+func conditionTrue(obj map[string]interface{}, condType string) bool {
+	status, _ := obj["status"].(map[string]interface{})
+	conditions, _ := status["conditions"].([]interface{})
+	for _, c := range conditions {
+		cond, _ := c.(map[string]interface{})
+		if cond["type"] == condType {
+			return cond["status"] == "True"
+		}
+	}
+	return false
+}
+
+// nestedString reads a string field from nested maps
+func nestedString(obj map[string]interface{}, path ...string) string {
+	v, _ := nestedValue(obj, path...).(string)
+	return v
+}
+
+// nestedInt reads an integer field from nested maps, accepting the int and
+// float64 forms produced by Go literals and JSON decoding
+func nestedInt(obj map[string]interface{}, path ...string) int {
+	switch v := nestedValue(obj, path...).(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func nestedValue(obj map[string]interface{}, path ...string) interface{} {
+	var cur interface{} = obj
+	for _, key := range path {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[key]
+	}
+	return cur
+}
@@ -0,0 +1,293 @@
+package installer
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeManagementClient is an in-memory management cluster. onGet lets a test
+// play the HyperShift operator by mutating objects as they are read.
+type fakeManagementClient struct {
+	mu      sync.Mutex
+	objects map[string]map[string]interface{}
+	patches []string
+	deletes []string
+	onGet   func(key string, obj map[string]interface{}) (map[string]interface{}, error)
+}
+
+func newFakeManagementClient() *fakeManagementClient {
+	return &fakeManagementClient{objects: map[string]map[string]interface{}{}}
+}
+
+func objectKey(resource, namespace, name string) string {
+	return resource + "/" + namespace + "/" + name
+}
+
+// roundTrip copies an object through JSON, as the API server would
+func roundTrip(obj map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(obj)
+	var out map[string]interface{}
+	_ = json.Unmarshal(data, &out)
+	return out
+}
+
+func (f *fakeManagementClient) put(resource string, obj map[string]interface{}) {
+	f.objects[objectKey(resource, nestedString(obj, "metadata", "namespace"), nestedString(obj, "metadata", "name"))] = roundTrip(obj)
+}
+
+func (f *fakeManagementClient) Apply(_ context.Context, obj map[string]interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.put(strings.ToLower(obj["kind"].(string)), obj)
+	return nil
+}
+
+func (f *fakeManagementClient) Get(_ context.Context, resource, namespace, name string) (map[string]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := objectKey(resource, namespace, name)
+	obj, ok := f.objects[key]
+	if f.onGet != nil {
+		var err error
+		if obj, err = f.onGet(key, obj); err != nil {
+			return nil, err
+		}
+		ok = obj != nil
+	}
+	if !ok {
+		return nil, ErrHCPNotFound
+	}
+	return roundTrip(obj), nil
+}
+
+func (f *fakeManagementClient) List(_ context.Context, resource, namespace string) ([]map[string]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []map[string]interface{}
+	for key, obj := range f.objects {
+		if strings.HasPrefix(key, resource+"/"+namespace+"/") {
+			out = append(out, roundTrip(obj))
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeManagementClient) Patch(_ context.Context, resource, namespace, name string, patch []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := objectKey(resource, namespace, name)
+	obj, ok := f.objects[key]
+	if !ok {
+		return ErrHCPNotFound
+	}
+	var p map[string]interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return err
+	}
+	for k, v := range p["spec"].(map[string]interface{}) {
+		obj["spec"].(map[string]interface{})[k] = v
+	}
+	f.patches = append(f.patches, key+" "+string(patch))
+	return nil
+}
+
+func (f *fakeManagementClient) Delete(_ context.Context, resource, namespace, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := objectKey(resource, namespace, name)
+	delete(f.objects, key)
+	f.deletes = append(f.deletes, key)
+	return nil
+}
+
+func testHCPConfig() *HCPClusterConfig {
+	return &HCPClusterConfig{
+		Name:         "hcp-test",
+		ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.20.3-x86_64",
+		PullSecret:   `{"auths":{}}`,
+		SSHPublicKey: "ssh-ed25519 AAAA test",
+		NodePools: []HCPNodePoolConfig{
+			{Name: "workers", Replicas: 2, Cores: 4, MemoryGiB: 16, RootVolumeGB: 64},
+			{Name: "infra", Replicas: 1, Cores: 2, MemoryGiB: 8},
+		},
+		Labels: map[string]string{"ocpctl.io/managed": "true"},
+	}
+}
+
+func newTestHCPInstaller(client HCPManagementClient) *HCPInstaller {
+	h := NewHCPInstaller(client, "")
+	h.pollInterval = time.Millisecond
+	return h
+}
+
+func TestHCPReleaseImage(t *testing.T) {
+	image, err := HCPReleaseImage("4.20.3", "")
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/openshift-release-dev/ocp-release:4.20.3-x86_64", image)
+
+	image, err = HCPReleaseImage("4.20.3", "aarch64")
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/openshift-release-dev/ocp-release:4.20.3-aarch64", image)
+
+	_, err = HCPReleaseImage("4.20", "")
+	assert.Error(t, err)
+}
+
+func TestHCPClusterConfig_Objects(t *testing.T) {
+	objects, err := testHCPConfig().Objects("clusters")
+	require.NoError(t, err)
+
+	var kinds []string
+	for _, obj := range objects {
+		kinds = append(kinds, obj["kind"].(string)+"/"+nestedString(obj, "metadata", "name"))
+	}
+	assert.Equal(t, []string{
+		"Secret/hcp-test-pull-secret",
+		"Secret/hcp-test-ssh-key",
+		"HostedCluster/hcp-test",
+		"NodePool/hcp-test-workers",
+		"NodePool/hcp-test-infra",
+	}, kinds)
+
+	hc := roundTrip(objects[2])
+	assert.Equal(t, "SingleReplica", nestedString(hc, "spec", "controllerAvailabilityPolicy"))
+	assert.Equal(t, "KubeVirt", nestedString(hc, "spec", "platform", "type"))
+	assert.Equal(t, "hcp-test-ssh-key", nestedString(hc, "spec", "sshKey", "name"))
+
+	pool := roundTrip(objects[3])
+	assert.Equal(t, "hcp-test", nestedString(pool, "spec", "clusterName"))
+	assert.Equal(t, 2, nestedInt(pool, "spec", "replicas"))
+	assert.Equal(t, "16Gi", nestedString(pool, "spec", "platform", "kubevirt", "compute", "memory"))
+	assert.Equal(t, "64Gi", nestedString(pool, "spec", "platform", "kubevirt", "rootVolume", "persistent", "size"))
+	assert.Nil(t, nestedValue(roundTrip(objects[4]), "spec", "platform", "kubevirt", "rootVolume"))
+
+	rendered, err := RenderObjects(objects)
+	require.NoError(t, err)
+	assert.Equal(t, 4, strings.Count(string(rendered), "---\n"))
+
+	_, err = (&HCPClusterConfig{Name: "empty"}).Objects("clusters")
+	assert.Error(t, err)
+}
+
+func TestHCPInstaller_CreateAndWait(t *testing.T) {
+	client := newFakeManagementClient()
+	h := newTestHCPInstaller(client)
+	ctx := context.Background()
+
+	require.NoError(t, h.CreateCluster(ctx, testHCPConfig()))
+	assert.Len(t, client.objects, 5)
+
+	// The operator reports the control plane available on the third read and
+	// the node pools fill up after that
+	reads := 0
+	client.onGet = func(key string, obj map[string]interface{}) (map[string]interface{}, error) {
+		if key != objectKey("hostedcluster", "clusters", "hcp-test") {
+			return obj, nil
+		}
+		reads++
+		if reads >= 3 {
+			obj["status"] = map[string]interface{}{
+				"conditions":           []interface{}{map[string]interface{}{"type": "Available", "status": "True"}},
+				"controlPlaneEndpoint": map[string]interface{}{"host": "api.hcp-test.example.com", "port": 443},
+			}
+			for k, pool := range client.objects {
+				if strings.HasPrefix(k, "nodepool/") {
+					pool["status"] = map[string]interface{}{"replicas": pool["spec"].(map[string]interface{})["replicas"]}
+				}
+			}
+		}
+		return obj, nil
+	}
+
+	info, err := h.WaitForReady(ctx, "hcp-test")
+	require.NoError(t, err)
+	assert.True(t, info.Available)
+	assert.Equal(t, "https://api.hcp-test.example.com:443", info.APIURL)
+	assert.GreaterOrEqual(t, reads, 3)
+}
+
+func TestHCPInstaller_WaitForReadyTimeout(t *testing.T) {
+	client := newFakeManagementClient()
+	h := newTestHCPInstaller(client)
+	require.NoError(t, h.CreateCluster(context.Background(), testHCPConfig()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := h.WaitForReady(ctx, "hcp-test")
+	assert.ErrorContains(t, err, "timed out")
+}
+
+func TestHCPInstaller_GetKubeconfig(t *testing.T) {
+	client := newFakeManagementClient()
+	h := newTestHCPInstaller(client)
+	client.put("hostedcluster", map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "hcp-test", "namespace": "clusters"},
+		"status":   map[string]interface{}{"kubeconfig": map[string]interface{}{"name": "custom-kubeconfig"}},
+	})
+	client.put("secret", map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "custom-kubeconfig", "namespace": "clusters"},
+		"data":     map[string]interface{}{"kubeconfig": base64.StdEncoding.EncodeToString([]byte("apiVersion: v1"))},
+	})
+	client.put("secret", map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "hcp-test-kubeadmin-password", "namespace": "clusters"},
+		"data":     map[string]interface{}{"password": base64.StdEncoding.EncodeToString([]byte("s3cret"))},
+	})
+
+	kubeconfig, err := h.GetKubeconfig(context.Background(), "hcp-test")
+	require.NoError(t, err)
+	assert.Equal(t, "apiVersion: v1", string(kubeconfig))
+
+	password, err := h.GetKubeadminPassword(context.Background(), "hcp-test")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", password)
+}
+
+func TestHCPInstaller_HibernateResume(t *testing.T) {
+	client := newFakeManagementClient()
+	h := newTestHCPInstaller(client)
+	ctx := context.Background()
+	require.NoError(t, h.CreateCluster(ctx, testHCPConfig()))
+
+	// A pool of another cluster in the same namespace is left alone
+	other := testHCPConfig()
+	other.Name = "other"
+	require.NoError(t, h.CreateCluster(ctx, other))
+
+	replicas, err := h.Hibernate(ctx, "hcp-test")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"hcp-test-workers": 2, "hcp-test-infra": 1}, replicas)
+	assert.Equal(t, 0, nestedInt(client.objects[objectKey("nodepool", "clusters", "hcp-test-workers")], "spec", "replicas"))
+	assert.Equal(t, 2, nestedInt(client.objects[objectKey("nodepool", "clusters", "other-workers")], "spec", "replicas"))
+
+	// Hibernating again is a no-op
+	again, err := h.Hibernate(ctx, "hcp-test")
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	require.NoError(t, h.Resume(ctx, replicas))
+	assert.Equal(t, 2, nestedInt(client.objects[objectKey("nodepool", "clusters", "hcp-test-workers")], "spec", "replicas"))
+	assert.Equal(t, 1, nestedInt(client.objects[objectKey("nodepool", "clusters", "hcp-test-infra")], "spec", "replicas"))
+}
+
+func TestHCPInstaller_Destroy(t *testing.T) {
+	client := newFakeManagementClient()
+	h := newTestHCPInstaller(client)
+	ctx := context.Background()
+	require.NoError(t, h.CreateCluster(ctx, testHCPConfig()))
+
+	require.NoError(t, h.DestroyCluster(ctx, "hcp-test"))
+	assert.Empty(t, client.objects)
+	assert.Equal(t, objectKey("hostedcluster", "clusters", "hcp-test"), client.deletes[2],
+		"node pools are deleted before the hosted cluster")
+
+	// Destroying a cluster that is already gone succeeds
+	require.NoError(t, h.DestroyCluster(ctx, "hcp-test"))
+}
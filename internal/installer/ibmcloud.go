@@ -149,39 +149,46 @@ func (i *IKSInstaller) ResolveClassicVLANs(ctx context.Context, zone string) (*R
 	return resolved, nil
 }
 
-// CreateCluster creates an IKS cluster
-// If logPath is provided, outputs are written to the log file for streaming
-func (i *IKSInstaller) CreateCluster(ctx context.Context, opts *IKSClusterCreateOptions, logPath string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, i.timeout)
-	defer cancel()
-
+// CreateArgs builds the ibmcloud arguments for creating the classic cluster
+func (o *IKSClusterCreateOptions) CreateArgs() []string {
 	args := []string{"ks", "cluster", "create", "classic",
-		"--name", opts.Name,
-		"--zone", opts.Zone,
-		"--machine-type", opts.MachineType,
-		"--workers", fmt.Sprintf("%d", opts.Workers),
+		"--name", o.Name,
+		"--zone", o.Zone,
+		"--machine-type", o.MachineType,
+		"--workers", fmt.Sprintf("%d", o.Workers),
 	}
 
-	if opts.KubeVersion != "" {
-		args = append(args, "--version", opts.KubeVersion)
+	if o.KubeVersion != "" {
+		args = append(args, "--version", o.KubeVersion)
 	}
 
-	if opts.PublicVLAN != "" {
-		args = append(args, "--public-vlan", opts.PublicVLAN)
+	if o.PublicVLAN != "" {
+		args = append(args, "--public-vlan", o.PublicVLAN)
 	}
 
-	if opts.PrivateVLAN != "" {
-		args = append(args, "--private-vlan", opts.PrivateVLAN)
+	if o.PrivateVLAN != "" {
+		args = append(args, "--private-vlan", o.PrivateVLAN)
 	}
 
-	if opts.PublicServiceEndpoint {
+	if o.PublicServiceEndpoint {
 		args = append(args, "--public-service-endpoint")
 	}
 
-	if opts.PrivateServiceEndpoint {
+	if o.PrivateServiceEndpoint {
 		args = append(args, "--private-service-endpoint")
 	}
 
+	return args
+}
+
+// CreateCluster creates an IKS cluster
+// If logPath is provided, outputs are written to the log file for streaming
+func (i *IKSInstaller) CreateCluster(ctx context.Context, opts *IKSClusterCreateOptions, logPath string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, i.timeout)
+	defer cancel()
+
+	args := opts.CreateArgs()
+
 	cmd := exec.CommandContext(ctx, i.binaryPath, args...)
 
 	var stdout, stderr bytes.Buffer
@@ -17,6 +17,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -48,8 +49,9 @@ type Installer struct {
 	binaryPath      string
 	ccoCtlPath      string
 	timeout         time.Duration
-	useSTSCreds     bool   // true if using temporary STS/IMDS credentials
-	credentialsMode string // credentials mode from cluster request (e.g., "Static", "Manual")
+	useSTSCreds     bool               // true if using temporary STS/IMDS credentials
+	credentialsMode string             // credentials mode from cluster request (e.g., "Static", "Manual")
+	spotWorkers     *SpotMarketOptions // non-nil to run AWS workers on spot capacity
 }
 
 // CredentialType represents the type of AWS credentials in use
@@ -98,6 +100,15 @@ func NewInstaller() *Installer {
 // NewInstallerForVersion creates a new installer instance for a specific OpenShift version
 // Supports versions 4.14-4.22 and 5.0+
 func NewInstallerForVersion(version string) (*Installer, error) {
+	return NewInstallerForArchitecture(version, types.ArchitectureAMD64)
+}
+
+// NewInstallerForArchitecture creates an installer for a specific OpenShift
+// version and release payload architecture. arm64 and heterogeneous clusters
+// are installed from the multi-architecture payload, whose installer binaries
+// are kept apart from the amd64 ones with a "-multi" suffix (env vars end in
+// _MULTI).
+func NewInstallerForArchitecture(version string, releaseArch types.Architecture) (*Installer, error) {
 	// Extract major.minor version (e.g., "4.20.3" -> "4.20")
 	majorMinor := extractMajorMinor(version)
 	if majorMinor == "" {
@@ -117,21 +128,27 @@ func NewInstallerForVersion(version string) (*Installer, error) {
 		return nil, fmt.Errorf("unsupported OpenShift version: %s (supported: 4.14, 4.16, 4.18-4.22, 5.0-5.1)", version)
 	}
 
+	// Multi-architecture installers live beside the amd64 ones
+	envSuffix, pathSuffix := "", ""
+	if releaseArch == types.ArchitectureMulti {
+		envSuffix, pathSuffix = "_MULTI", "-multi"
+	}
+
 	// Check for version-specific binaries in environment (exact version first, then major.minor)
 	// Try exact version env var first (e.g., OPENSHIFT_INSTALL_BINARY_4_22_0_ec_5)
 	exactVersionKey := strings.ReplaceAll(strings.ReplaceAll(version, ".", "_"), "-", "_")
-	binaryEnvKey := fmt.Sprintf("OPENSHIFT_INSTALL_BINARY_%s", exactVersionKey)
+	binaryEnvKey := fmt.Sprintf("OPENSHIFT_INSTALL_BINARY_%s%s", exactVersionKey, envSuffix)
 	binaryPath := os.Getenv(binaryEnvKey)
 
 	// Fall back to major.minor env var (e.g., OPENSHIFT_INSTALL_BINARY_4_22)
 	if binaryPath == "" {
-		binaryEnvKey = fmt.Sprintf("OPENSHIFT_INSTALL_BINARY_%s", strings.ReplaceAll(majorMinor, ".", "_"))
+		binaryEnvKey = fmt.Sprintf("OPENSHIFT_INSTALL_BINARY_%s%s", strings.ReplaceAll(majorMinor, ".", "_"), envSuffix)
 		binaryPath = os.Getenv(binaryEnvKey)
 	}
 
 	// Fall back to exact version binary in standard location
 	if binaryPath == "" {
-		exactVersionPath := fmt.Sprintf("/usr/local/bin/openshift-install-%s", version)
+		exactVersionPath := fmt.Sprintf("/usr/local/bin/openshift-install-%s%s", version, pathSuffix)
 		if _, err := os.Stat(exactVersionPath); err == nil {
 			binaryPath = exactVersionPath
 		}
@@ -139,7 +156,7 @@ func NewInstallerForVersion(version string) (*Installer, error) {
 
 	// Fall back to major.minor version binary
 	if binaryPath == "" {
-		binaryPath = fmt.Sprintf("/usr/local/bin/openshift-install-%s", majorMinor)
+		binaryPath = fmt.Sprintf("/usr/local/bin/openshift-install-%s%s", majorMinor, pathSuffix)
 	}
 
 	// Check for version-specific ccoctl (exact version first, then major.minor)
@@ -170,7 +187,7 @@ func NewInstallerForVersion(version string) (*Installer, error) {
 		log.Printf("openshift-install binary not found for version %s at %s, attempting to download...", version, binaryPath)
 
 		// Try downloading the specific version on-demand
-		if downloadErr := downloadInstallerOnDemand(version); downloadErr != nil {
+		if downloadErr := downloadInstallerOnDemand(version, releaseArch); downloadErr != nil {
 			return nil, fmt.Errorf("openshift-install binary not found for version %s at %s: %w (download attempt failed: %v)", version, binaryPath, err, downloadErr)
 		}
 
@@ -190,7 +207,7 @@ func NewInstallerForVersion(version string) (*Installer, error) {
 	// Detect if we're using STS/IMDS credentials
 	useSTSCreds := detectSTSCredentials()
 
-	log.Printf("Using OpenShift installer version %s: %s", majorMinor, binaryPath)
+	log.Printf("Using OpenShift installer version %s (%s payload): %s", majorMinor, releaseArch, binaryPath)
 	log.Printf("Using ccoctl version %s: %s", majorMinor, ccoCtlPath)
 
 	// Verify the binary is the correct version
@@ -204,7 +221,7 @@ func NewInstallerForVersion(version string) (*Installer, error) {
 		}
 
 		// Download the correct version
-		if downloadErr := downloadInstallerOnDemand(version); downloadErr != nil {
+		if downloadErr := downloadInstallerOnDemand(version, releaseArch); downloadErr != nil {
 			return nil, fmt.Errorf("failed to download correct installer version %s: %w", version, downloadErr)
 		}
 
@@ -321,7 +338,7 @@ func parseMajorMinor(majorMinor string) (major, minor int, ok bool) {
 
 // downloadInstallerOnDemand attempts to download the OpenShift installer for a specific version
 // Uses download-specific-version.sh script which tries S3 → Public Mirror → CI Release Stream
-func downloadInstallerOnDemand(version string) error {
+func downloadInstallerOnDemand(version string, releaseArch types.Architecture) error {
 	// Path to download script
 	scriptPath := "/opt/ocpctl/scripts/download-specific-version.sh"
 
@@ -334,10 +351,10 @@ func downloadInstallerOnDemand(version string) error {
 		}
 	}
 
-	log.Printf("Running download script for version %s...", version)
+	log.Printf("Running download script for version %s (%s payload)...", version, releaseArch)
 
 	// Execute download script with full version - use sudo since /usr/local/bin requires root
-	cmd := exec.Command("sudo", "/bin/bash", scriptPath, version, "/usr/local/bin")
+	cmd := exec.Command("sudo", "/bin/bash", scriptPath, version, "/usr/local/bin", string(releaseArch))
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
@@ -508,6 +525,19 @@ func (i *Installer) CreateCluster(ctx context.Context, workDir string, metadata
 		return i.createClusterManualMode(ctx, workDir, metadata)
 	}
 
+	// Spot workers are set on the rendered MachineSet manifests. create
+	// manifests consumes install-config.yaml, so pin the credentials mode
+	// read from it before rendering.
+	if i.spotWorkers != nil {
+		i.credentialsMode = credMode
+		if err := i.CreateManifests(ctx, workDir); err != nil {
+			return "", fmt.Errorf("create manifests: %w", err)
+		}
+		if err := patchSpotWorkerMachineSets(workDir, i.spotWorkers); err != nil {
+			return "", fmt.Errorf("configure spot workers: %w", err)
+		}
+	}
+
 	// All other modes use direct cluster creation
 	return i.CreateClusterDirect(ctx, workDir)
 }
@@ -602,6 +632,12 @@ func (i *Installer) createClusterManualMode(ctx context.Context, workDir string,
 	}
 	i.logInstallState(workDir, "AFTER create manifests")
 
+	if i.spotWorkers != nil {
+		if err := patchSpotWorkerMachineSets(workDir, i.spotWorkers); err != nil {
+			return "", fmt.Errorf("configure spot workers: %w", err)
+		}
+	}
+
 	// Step 2: Tag Route53 hosted zone for cluster discovery
 	fmt.Printf("Tagging Route53 hosted zone for cluster discovery...\n")
 	if err := i.tagRoute53Zone(ctx, workDir); err != nil {
@@ -1724,6 +1760,17 @@ func buildTagSet(metadata ClusterMetadata) map[string]string {
 	return tags
 }
 
+// sortedKeys returns the keys of a tag or label map in sorted order so the
+// CLI arguments built from it are stable
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // isClusterIAMRole checks if an IAM role name matches the cluster's infraID
 func isClusterIAMRole(roleName, infraID string) bool {
 	// Pattern 1: <cluster>-<infraID>-openshift-*
@@ -0,0 +1,320 @@
+package installer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KindInstaller wraps the kind CLI for clusters on the local platform. Node
+// containers are stopped and started with the container runtime directly,
+// which kind supports for hibernation.
+type KindInstaller struct {
+	binaryPath  string
+	runtimePath string
+	timeout     time.Duration
+}
+
+// KindClusterConfig represents a kind cluster configuration
+type KindClusterConfig struct {
+	Name             string
+	NodeImage        string
+	Workers          int
+	APIServerAddress string // Host address the API server binds to (default 127.0.0.1)
+}
+
+// kindNodeVersionPattern matches the full X.Y.Z versions kindest/node is tagged with
+var kindNodeVersionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// NewKindInstaller creates a new kind installer instance. KIND_BINARY
+// overrides the kind CLI and KIND_CONTAINER_RUNTIME the runtime used to stop
+// and start nodes; the runtime defaults to podman when kind is told to use it.
+func NewKindInstaller() *KindInstaller {
+	binaryPath := os.Getenv("KIND_BINARY")
+	if binaryPath == "" {
+		binaryPath = "kind"
+	}
+
+	runtimePath := os.Getenv("KIND_CONTAINER_RUNTIME")
+	if runtimePath == "" {
+		runtimePath = "docker"
+		if os.Getenv("KIND_EXPERIMENTAL_PROVIDER") == "podman" {
+			runtimePath = "podman"
+		}
+	}
+
+	return &KindInstaller{
+		binaryPath:  binaryPath,
+		runtimePath: runtimePath,
+		timeout:     15 * time.Minute, // kind clusters come up in 1-2 minutes
+	}
+}
+
+// KindNodeImage returns the kindest/node image for a Kubernetes version. kind
+// publishes images per patch release, so the version must be X.Y.Z.
+func KindNodeImage(version string) (string, error) {
+	version = strings.TrimPrefix(version, "v")
+	if !kindNodeVersionPattern.MatchString(version) {
+		return "", fmt.Errorf("kind requires a full X.Y.Z Kubernetes version, got %q", version)
+	}
+	return "kindest/node:v" + version, nil
+}
+
+// kindNode is a node entry in the kind cluster config
+type kindNode struct {
+	Role  string `yaml:"role"`
+	Image string `yaml:"image,omitempty"`
+}
+
+// kindConfigFile is the kind.x-k8s.io/v1alpha4 Cluster document
+type kindConfigFile struct {
+	Kind       string `yaml:"kind"`
+	APIVersion string `yaml:"apiVersion"`
+	Name       string `yaml:"name"`
+	Networking *struct {
+		APIServerAddress string `yaml:"apiServerAddress"`
+	} `yaml:"networking,omitempty"`
+	Nodes []kindNode `yaml:"nodes"`
+}
+
+// Render builds the kind cluster config: one control plane node plus the
+// requested workers, all on the same node image
+func (c *KindClusterConfig) Render() ([]byte, error) {
+	if c.Name == "" {
+		return nil, fmt.Errorf("cluster name required")
+	}
+	if c.Workers < 0 {
+		return nil, fmt.Errorf("workers must be >= 0")
+	}
+
+	file := kindConfigFile{
+		Kind:       "Cluster",
+		APIVersion: "kind.x-k8s.io/v1alpha4",
+		Name:       c.Name,
+		Nodes:      []kindNode{{Role: "control-plane", Image: c.NodeImage}},
+	}
+	for i := 0; i < c.Workers; i++ {
+		file.Nodes = append(file.Nodes, kindNode{Role: "worker", Image: c.NodeImage})
+	}
+	if c.APIServerAddress != "" {
+		file.Networking = &struct {
+			APIServerAddress string `yaml:"apiServerAddress"`
+		}{APIServerAddress: c.APIServerAddress}
+	}
+
+	return yaml.Marshal(file)
+}
+
+// CreateCluster creates a kind cluster and writes its kubeconfig to
+// kubeconfigPath. The rendered config is kept in workDir as kind-config.yaml.
+func (k *KindInstaller) CreateCluster(ctx context.Context, config *KindClusterConfig, workDir, kubeconfigPath, logFile string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, k.timeout)
+	defer cancel()
+
+	rendered, err := config.Render()
+	if err != nil {
+		return "", err
+	}
+	configPath := filepath.Join(workDir, "kind-config.yaml")
+	if err := os.WriteFile(configPath, rendered, 0644); err != nil {
+		return "", fmt.Errorf("write kind config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(kubeconfigPath), 0755); err != nil {
+		return "", fmt.Errorf("create auth directory: %w", err)
+	}
+
+	args := []string{
+		"create", "cluster",
+		"--name", config.Name,
+		"--config", configPath,
+		"--kubeconfig", kubeconfigPath,
+		"--wait", "5m",
+	}
+	cmd := exec.CommandContext(ctx, k.binaryPath, args...)
+
+	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return "", fmt.Errorf("open log file: %w", err)
+	}
+	defer f.Close()
+
+	cmd.Stdout = f
+	cmd.Stderr = f
+
+	if err := cmd.Run(); err != nil {
+		logData, _ := os.ReadFile(logFile)
+		return string(logData), fmt.Errorf("kind create cluster failed: %w", err)
+	}
+
+	logData, _ := os.ReadFile(logFile)
+	return string(logData), nil
+}
+
+// DeleteCluster deletes a kind cluster. kind treats a missing cluster as
+// already deleted.
+func (k *KindInstaller) DeleteCluster(ctx context.Context, clusterName string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, k.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, k.binaryPath, "delete", "cluster", "--name", clusterName)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return stderr.String(), fmt.Errorf("kind delete cluster failed: %w", err)
+	}
+
+	return stdout.String() + stderr.String(), nil
+}
+
+// ClusterExists reports whether kind knows about a cluster
+func (k *KindInstaller) ClusterExists(ctx context.Context, clusterName string) (bool, error) {
+	out, err := k.lines(ctx, k.binaryPath, "get", "clusters")
+	if err != nil {
+		return false, fmt.Errorf("kind get clusters failed: %w", err)
+	}
+	for _, name := range out {
+		if name == clusterName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Nodes returns the node container names of a kind cluster
+func (k *KindInstaller) Nodes(ctx context.Context, clusterName string) ([]string, error) {
+	out, err := k.lines(ctx, k.binaryPath, "get", "nodes", "--name", clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("kind get nodes failed: %w", err)
+	}
+	return out, nil
+}
+
+// GetKubeconfig writes the kubeconfig for a kind cluster to outputPath
+func (k *KindInstaller) GetKubeconfig(ctx context.Context, clusterName, outputPath string) error {
+	cmd := exec.CommandContext(ctx, k.binaryPath, "get", "kubeconfig", "--name", clusterName)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("get kubeconfig: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("create auth directory: %w", err)
+	}
+	return os.WriteFile(outputPath, stdout.Bytes(), 0600)
+}
+
+// StopNodes stops the node containers of a kind cluster and returns their
+// names. Stopping an already stopped container is a no-op.
+func (k *KindInstaller) StopNodes(ctx context.Context, clusterName string) ([]string, error) {
+	nodes, err := k.Nodes(ctx, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("kind cluster %s has no nodes", clusterName)
+	}
+	if err := k.runtime(ctx, append([]string{"stop"}, nodes...)...); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// StartNodes starts the node containers of a kind cluster. The control plane
+// is started first so workers can rejoin it.
+func (k *KindInstaller) StartNodes(ctx context.Context, clusterName string) error {
+	nodes, err := k.Nodes(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("kind cluster %s has no nodes", clusterName)
+	}
+
+	var controlPlane, workers []string
+	for _, n := range nodes {
+		if strings.Contains(n, "control-plane") {
+			controlPlane = append(controlPlane, n)
+		} else {
+			workers = append(workers, n)
+		}
+	}
+	for _, group := range [][]string{controlPlane, workers} {
+		if len(group) == 0 {
+			continue
+		}
+		if err := k.runtime(ctx, append([]string{"start"}, group...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runtime runs a container runtime command
+func (k *KindInstaller) runtime(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, k.runtimePath, args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s failed: %w: %s", filepath.Base(k.runtimePath), args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// lines runs a command and returns its non-empty stdout lines
+func (k *KindInstaller) lines(ctx context.Context, name string, args ...string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var out []string
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out, nil
+}
+
+// KubeconfigServer returns the API server URL of the first cluster in a
+// kubeconfig
+func KubeconfigServer(kubeconfig []byte) (string, error) {
+	var cfg struct {
+		Clusters []struct {
+			Cluster struct {
+				Server string `yaml:"server"`
+			} `yaml:"cluster"`
+		} `yaml:"clusters"`
+	}
+	if err := yaml.Unmarshal(kubeconfig, &cfg); err != nil {
+		return "", fmt.Errorf("parse kubeconfig: %w", err)
+	}
+	if len(cfg.Clusters) == 0 || cfg.Clusters[0].Cluster.Server == "" {
+		return "", fmt.Errorf("kubeconfig has no cluster server")
+	}
+	return cfg.Clusters[0].Cluster.Server, nil
+}
@@ -0,0 +1,112 @@
+package installer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestKindNodeImage(t *testing.T) {
+	image, err := KindNodeImage("1.33.1")
+	require.NoError(t, err)
+	assert.Equal(t, "kindest/node:v1.33.1", image)
+
+	image, err = KindNodeImage("v1.32.5")
+	require.NoError(t, err)
+	assert.Equal(t, "kindest/node:v1.32.5", image)
+
+	_, err = KindNodeImage("1.33")
+	assert.Error(t, err)
+}
+
+func TestKindClusterConfig_Render(t *testing.T) {
+	rendered, err := (&KindClusterConfig{
+		Name:             "kind-test",
+		NodeImage:        "kindest/node:v1.33.1",
+		Workers:          2,
+		APIServerAddress: "0.0.0.0",
+	}).Render()
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(rendered, &doc))
+	assert.Equal(t, "kind.x-k8s.io/v1alpha4", doc["apiVersion"])
+	assert.Equal(t, "kind-test", doc["name"])
+	assert.Equal(t, "0.0.0.0", nestedString(doc, "networking", "apiServerAddress"))
+
+	nodes := doc["nodes"].([]interface{})
+	require.Len(t, nodes, 3)
+	assert.Equal(t, "control-plane", nodes[0].(map[string]interface{})["role"])
+	assert.Equal(t, "worker", nodes[2].(map[string]interface{})["role"])
+	assert.Equal(t, "kindest/node:v1.33.1", nodes[2].(map[string]interface{})["image"])
+
+	// No workers and no address: a single-node cluster on loopback
+	rendered, err = (&KindClusterConfig{Name: "single"}).Render()
+	require.NoError(t, err)
+	assert.NotContains(t, string(rendered), "networking")
+	assert.Equal(t, 1, strings.Count(string(rendered), "role:"))
+
+	_, err = (&KindClusterConfig{}).Render()
+	assert.Error(t, err)
+}
+
+func TestKubeconfigServer(t *testing.T) {
+	server, err := KubeconfigServer([]byte(`apiVersion: v1
+clusters:
+- cluster:
+    server: https://127.0.0.1:41234
+  name: kind-test
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "https://127.0.0.1:41234", server)
+
+	_, err = KubeconfigServer([]byte("apiVersion: v1\n"))
+	assert.Error(t, err)
+}
+
+// writeFakeBinary writes a script that appends its arguments to calls and
+// prints stdout
+func writeFakeBinary(t *testing.T, dir, name, stdout, calls string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	script := "#!/bin/sh\necho \"" + name + " $*\" >> " + calls + "\nprintf '%s' '" + stdout + "'\n"
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+func TestKindInstaller_StopStartNodes(t *testing.T) {
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	k := &KindInstaller{
+		binaryPath:  writeFakeBinary(t, dir, "kind", "kind-test-worker\nkind-test-control-plane\n", calls),
+		runtimePath: writeFakeBinary(t, dir, "docker", "", calls),
+	}
+	ctx := context.Background()
+
+	nodes, err := k.StopNodes(ctx, "kind-test")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kind-test-worker", "kind-test-control-plane"}, nodes)
+
+	require.NoError(t, k.StartNodes(ctx, "kind-test"))
+
+	exists, err := k.ClusterExists(ctx, "kind-test-worker")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	data, err := os.ReadFile(calls)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"kind get nodes --name kind-test",
+		"docker stop kind-test-worker kind-test-control-plane",
+		"kind get nodes --name kind-test",
+		"docker start kind-test-control-plane",
+		"docker start kind-test-worker",
+		"kind get clusters",
+	}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+}
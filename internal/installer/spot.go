@@ -0,0 +1,80 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// workerMachineSetGlob matches the worker MachineSet manifests rendered by
+// openshift-install create manifests, one per availability zone
+const workerMachineSetGlob = "99_openshift-cluster-api_worker-machineset-*.yaml"
+
+// SpotMarketOptions requests spot capacity for AWS worker machines
+type SpotMarketOptions struct {
+	MaxPrice string // Hourly cap in USD; empty caps at the on-demand price
+}
+
+// SetSpotWorkers makes CreateCluster run the AWS worker machines on spot
+// capacity. This should be called before CreateCluster.
+func (i *Installer) SetSpotWorkers(opts *SpotMarketOptions) {
+	i.spotWorkers = opts
+}
+
+// patchSpotWorkerMachineSets adds spotMarketOptions to the providerSpec of
+// every worker MachineSet manifest in workDir. Control plane machines are
+// left on-demand.
+func patchSpotWorkerMachineSets(workDir string, opts *SpotMarketOptions) error {
+	paths, err := filepath.Glob(filepath.Join(workDir, "openshift", workerMachineSetGlob))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no worker MachineSet manifests found in %s", filepath.Join(workDir, "openshift"))
+	}
+
+	spot := map[string]interface{}{}
+	if opts.MaxPrice != "" {
+		spot["maxPrice"] = opts.MaxPrice
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var ms map[string]interface{}
+		if err := yaml.Unmarshal(data, &ms); err != nil {
+			return fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+		}
+
+		value, ok := nestedMap(ms, "spec", "template", "spec", "providerSpec", "value")
+		if !ok {
+			return fmt.Errorf("%s has no providerSpec.value", filepath.Base(path))
+		}
+		value["spotMarketOptions"] = spot
+
+		out, err := yaml.Marshal(ms)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, out, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nestedMap walks a decoded YAML document down the given keys
+func nestedMap(m map[string]interface{}, keys ...string) (map[string]interface{}, bool) {
+	for _, k := range keys {
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		m = next
+	}
+	return m, true
+}
@@ -0,0 +1,48 @@
+package installer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const workerMachineSet = `apiVersion: machine.openshift.io/v1beta1
+kind: MachineSet
+metadata:
+  name: demo-abc12-worker-us-east-1a
+  namespace: openshift-machine-api
+spec:
+  replicas: 1
+  template:
+    spec:
+      providerSpec:
+        value:
+          apiVersion: machine.openshift.io/v1beta1
+          kind: AWSMachineProviderConfig
+          instanceType: m6i.xlarge
+`
+
+func TestPatchSpotWorkerMachineSets(t *testing.T) {
+	workDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workDir, "openshift"), 0o755))
+
+	err := patchSpotWorkerMachineSets(workDir, &SpotMarketOptions{})
+	assert.Error(t, err, "no MachineSets rendered")
+
+	path := filepath.Join(workDir, "openshift", "99_openshift-cluster-api_worker-machineset-0.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(workerMachineSet), 0o644))
+	require.NoError(t, patchSpotWorkerMachineSets(workDir, &SpotMarketOptions{MaxPrice: "0.12"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var ms map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &ms))
+	value, ok := nestedMap(ms, "spec", "template", "spec", "providerSpec", "value")
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"maxPrice": "0.12"}, value["spotMarketOptions"])
+	assert.Equal(t, "m6i.xlarge", value["instanceType"])
+}
@@ -0,0 +1,297 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ocpctlclient "github.com/tsanders-rh/ocpctl/pkg/client"
+	"github.com/tsanders-rh/ocpctl/pkg/operator/v1alpha1"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ClusterReconciler creates, mirrors and destroys the cluster behind an OcpctlCluster
+type ClusterReconciler struct {
+	client.Client
+	API API
+}
+
+// +kubebuilder:rbac:groups=ocpctl.io,resources=ocpctlclusters,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ocpctl.io,resources=ocpctlclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ocpctl.io,resources=ocpctlclusters/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// SetupWithManager registers the reconciler with a manager
+func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.OcpctlCluster{}).
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
+
+// Reconcile implements reconcile.Reconciler
+func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var obj v1alpha1.OcpctlCluster
+	if err := r.Get(ctx, req.NamespacedName, &obj); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !obj.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &obj)
+	}
+
+	if controllerutil.AddFinalizer(&obj, Finalizer) {
+		if err := r.Update(ctx, &obj); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	status := obj.Status.DeepCopy()
+	result, err := r.reconcile(ctx, &obj, status)
+	setSynced(&status.Conditions, obj.Generation, err)
+	status.ObservedGeneration = obj.Generation
+
+	obj.Status = *status
+	if updateErr := r.Status().Update(ctx, &obj); updateErr != nil {
+		return ctrl.Result{}, updateErr
+	}
+	return result, err
+}
+
+func (r *ClusterReconciler) reconcile(ctx context.Context, obj *v1alpha1.OcpctlCluster, status *v1alpha1.OcpctlClusterStatus) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if status.ClusterID == "" {
+		cluster, err := r.API.CreateCluster(ocpctlclient.WithIdempotencyKey(ctx, createIdempotencyKey(obj)), createRequest(obj))
+		if err != nil {
+			if isPermanent(err) {
+				setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, "Rejected", err.Error())
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("create cluster: %w", err)
+		}
+		logger.Info("created ocpctl cluster", "clusterID", cluster.ID, "name", cluster.Name)
+		status.ClusterID = cluster.ID
+		mirrorCluster(status, obj.Generation, cluster)
+		return ctrl.Result{RequeueAfter: ProvisioningRequeue}, nil
+	}
+
+	cluster, err := r.API.GetCluster(ctx, status.ClusterID)
+	if err != nil {
+		if ocpctlclient.IsNotFound(err) {
+			// Destroyed outside the operator, typically at TTL expiry. Clusters are
+			// ephemeral, so it is not recreated.
+			status.Phase = string(types.ClusterStatusDestroyed)
+			setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, "Destroyed", "cluster no longer exists")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("get cluster: %w", err)
+	}
+
+	if obj.Spec.TTLHours != nil && isActive(cluster.Status) {
+		// Extending adds hours to the cluster's TTL, so only the difference is sent
+		desired := *obj.Spec.TTLHours
+		if desired > cluster.TTLHours {
+			extended, err := r.API.ExtendCluster(ctx, cluster.ID, &types.ExtendClusterRequest{TTLHours: desired - cluster.TTLHours})
+			if err != nil && !isPermanent(err) {
+				return ctrl.Result{}, fmt.Errorf("update cluster TTL: %w", err)
+			}
+			if err != nil {
+				setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionTTLApplied, metav1.ConditionFalse, "Rejected", err.Error())
+			} else {
+				cluster = extended
+			}
+		}
+		if desired < cluster.TTLHours {
+			setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionTTLApplied, metav1.ConditionFalse, "CannotShorten",
+				fmt.Sprintf("ttlHours %d is lower than the cluster's TTL of %d hours; a TTL can only be extended", desired, cluster.TTLHours))
+		} else if desired == cluster.TTLHours {
+			setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionTTLApplied, metav1.ConditionTrue, "Applied", "")
+		}
+	}
+
+	mirrorCluster(status, obj.Generation, cluster)
+
+	if cluster.Status != types.ClusterStatusReady {
+		if isActive(cluster.Status) {
+			return ctrl.Result{RequeueAfter: ProvisioningRequeue}, nil
+		}
+		return ctrl.Result{RequeueAfter: SteadyStateRequeue}, nil
+	}
+
+	outputs, err := r.API.GetClusterOutputs(ctx, cluster.ID)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("get cluster outputs: %w", err)
+	}
+	status.APIURL = outputs.APIUrl
+	status.ConsoleURL = outputs.ConsoleURL
+
+	if outputs.Kubeconfig == "" {
+		setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, "KubeconfigPending", "cluster is ready but its kubeconfig is not available yet")
+		return ctrl.Result{RequeueAfter: ProvisioningRequeue}, nil
+	}
+
+	secretName := connectionSecretName(obj, obj.Spec.WriteConnectionSecretTo)
+	data := map[string][]byte{
+		SecretKeyKubeconfig: []byte(outputs.Kubeconfig),
+		SecretKeyAPIURL:     []byte(outputs.APIUrl),
+		SecretKeyConsoleURL: []byte(outputs.ConsoleURL),
+		SecretKeyClusterID:  []byte(cluster.ID),
+	}
+	if outputs.SAToken != "" {
+		data[SecretKeyToken] = []byte(outputs.SAToken)
+	}
+	if err := writeConnectionSecret(ctx, r.Client, obj, secretName, data); err != nil {
+		return ctrl.Result{}, err
+	}
+	status.SecretName = secretName
+	setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionReady, metav1.ConditionTrue, "Ready", "credentials written to Secret "+secretName)
+
+	return ctrl.Result{RequeueAfter: SteadyStateRequeue}, nil
+}
+
+// finalize destroys the cluster (unless orphaned) and waits for it to be gone
+// before releasing the resource
+func (r *ClusterReconciler) finalize(ctx context.Context, obj *v1alpha1.OcpctlCluster) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(obj, Finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if obj.Spec.DeletionPolicy != v1alpha1.DeletionPolicyOrphan && obj.Status.ClusterID != "" {
+		done, err := r.destroy(ctx, obj)
+		if err != nil || !done {
+			setSynced(&obj.Status.Conditions, obj.Generation, err)
+			if updateErr := r.Status().Update(ctx, obj); updateErr != nil && !apierrors.IsNotFound(updateErr) {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: ProvisioningRequeue}, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(obj, Finalizer)
+	return ctrl.Result{}, r.Update(ctx, obj)
+}
+
+// destroy starts destroying the cluster and reports whether it is gone
+func (r *ClusterReconciler) destroy(ctx context.Context, obj *v1alpha1.OcpctlCluster) (bool, error) {
+	cluster, err := r.API.GetCluster(ctx, obj.Status.ClusterID)
+	if err != nil {
+		if ocpctlclient.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("get cluster: %w", err)
+	}
+
+	switch cluster.Status {
+	case types.ClusterStatusDestroyed:
+		return true, nil
+	case types.ClusterStatusDestroying, types.ClusterStatusDestroyVerifying:
+		// Already on its way
+	case types.ClusterStatusDestroyFailed:
+		mirrorCluster(&obj.Status, obj.Generation, cluster)
+		return false, fmt.Errorf("cluster %s failed to destroy; resources may remain and need an admin", cluster.ID)
+	default:
+		cluster, err = r.API.DeleteCluster(ctx, cluster.ID)
+		if err != nil {
+			return false, fmt.Errorf("destroy cluster: %w", err)
+		}
+		log.FromContext(ctx).Info("destroying ocpctl cluster", "clusterID", cluster.ID)
+	}
+
+	mirrorCluster(&obj.Status, obj.Generation, cluster)
+	return false, nil
+}
+
+// createRequest builds the API request for an OcpctlCluster
+func createRequest(obj *v1alpha1.OcpctlCluster) *types.CreateClusterAPIRequest {
+	spec := obj.Spec
+	name := spec.ClusterName
+	if name == "" {
+		name = obj.Name
+	}
+
+	req := &types.CreateClusterAPIRequest{
+		Name:               name,
+		Platform:           spec.Platform,
+		ClusterType:        spec.ClusterType,
+		Version:            spec.Version,
+		Profile:            spec.Profile,
+		Region:             spec.Region,
+		BaseDomain:         spec.BaseDomain,
+		Owner:              spec.Owner,
+		Team:               spec.Team,
+		CostCenter:         spec.CostCenter,
+		TTLHours:           spec.TTLHours,
+		ExtraTags:          spec.ExtraTags,
+		OffhoursOptIn:      spec.OffhoursOptIn,
+		SkipPostDeployment: spec.SkipPostDeployment,
+	}
+	if spec.SSHPublicKey != "" {
+		req.SSHPublicKey = &spec.SSHPublicKey
+	}
+	for _, addon := range spec.Addons {
+		req.PostConfigAddOns = append(req.PostConfigAddOns, types.AddonSelection{ID: addon.ID, Version: addon.Version})
+	}
+	return req
+}
+
+// mirrorCluster copies the ocpctl cluster state into status
+func mirrorCluster(status *v1alpha1.OcpctlClusterStatus, generation int64, cluster *types.Cluster) {
+	status.Phase = string(cluster.Status)
+	if cluster.DestroyAt != nil {
+		t := metav1.NewTime(*cluster.DestroyAt)
+		status.DestroyAt = &t
+	}
+
+	if cluster.Status == types.ClusterStatusReady {
+		return // Ready is set once the Secret is written
+	}
+	setCondition(&status.Conditions, generation, v1alpha1.ConditionReady, metav1.ConditionFalse, phaseReason(cluster.Status), "cluster is "+string(cluster.Status))
+}
+
+// isActive reports whether a cluster is provisioning or running
+func isActive(status types.ClusterStatus) bool {
+	switch status {
+	case types.ClusterStatusPending, types.ClusterStatusCreating, types.ClusterStatusReady,
+		types.ClusterStatusHibernating, types.ClusterStatusHibernated, types.ClusterStatusResuming:
+		return true
+	}
+	return false
+}
+
+// createIdempotencyKey scopes idempotencyKey to the spec generation. A
+// rejected create is replayed for its key, so the create for a corrected spec
+// needs a new one.
+func createIdempotencyKey(obj client.Object) string {
+	if obj.GetGeneration() <= 1 {
+		return idempotencyKey(obj)
+	}
+	return fmt.Sprintf("%s/generation-%d", idempotencyKey(obj), obj.GetGeneration())
+}
+
+// isPermanent reports whether an API error will not go away by retrying
+func isPermanent(err error) bool {
+	code := ocpctlclient.StatusCode(err)
+	return code >= 400 && code < 500 && code != 401 && code != 408 && code != 409 && code != 429
+}
+
+// phaseReason turns an ocpctl status such as DESTROY_FAILED into a condition
+// reason such as DestroyFailed
+func phaseReason(status types.ClusterStatus) string {
+	parts := strings.Split(strings.ToLower(string(status)), "_")
+	for i, p := range parts {
+		if p != "" {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,244 @@
+package operator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tsanders-rh/ocpctl/pkg/operator/v1alpha1"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+const (
+	eventuallyTimeout = 10 * time.Second
+	eventuallyTick    = 100 * time.Millisecond
+)
+
+func newOcpctlCluster(name string, policy v1alpha1.DeletionPolicy) *v1alpha1.OcpctlCluster {
+	ttl := 4
+	return &v1alpha1.OcpctlCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1alpha1.OcpctlClusterSpec{
+			Platform:       "aws",
+			ClusterType:    "openshift",
+			Version:        "4.20",
+			Profile:        "aws-sno-ga",
+			Region:         "us-east-1",
+			BaseDomain:     "example.com",
+			Owner:          "ci@example.com",
+			Team:           "platform",
+			CostCenter:     "cc-1",
+			TTLHours:       &ttl,
+			DeletionPolicy: policy,
+		},
+	}
+}
+
+// waitForClusterID waits for the controller to create the ocpctl cluster
+func waitForClusterID(t *testing.T, key client.ObjectKey) string {
+	t.Helper()
+	var id string
+	require.Eventually(t, func() bool {
+		var obj v1alpha1.OcpctlCluster
+		if err := k8sClient.Get(context.Background(), key, &obj); err != nil {
+			return false
+		}
+		id = obj.Status.ClusterID
+		return id != ""
+	}, eventuallyTimeout, eventuallyTick)
+	return id
+}
+
+func TestClusterReconcilerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	obj := newOcpctlCluster("lifecycle", v1alpha1.DeletionPolicyDelete)
+	require.NoError(t, k8sClient.Create(ctx, obj))
+	key := client.ObjectKeyFromObject(obj)
+
+	id := waitForClusterID(t, key)
+	cluster := fakeAPI.cluster(id)
+	assert.Equal(t, "lifecycle", cluster.Name)
+	assert.Equal(t, "ci@example.com", cluster.Owner)
+	assert.Equal(t, 4, cluster.TTLHours)
+
+	fakeAPI.setStatus(id, types.ClusterStatusReady, &types.ClusterOutputsResponse{
+		ClusterID:  id,
+		APIUrl:     "https://api.lifecycle.example.com:6443",
+		ConsoleURL: "https://console.lifecycle.example.com",
+		Kubeconfig: "apiVersion: v1\nkind: Config\n",
+	})
+
+	require.Eventually(t, func() bool {
+		var got v1alpha1.OcpctlCluster
+		if err := k8sClient.Get(ctx, key, &got); err != nil {
+			return false
+		}
+		return meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.ConditionReady)
+	}, eventuallyTimeout, eventuallyTick)
+
+	var got v1alpha1.OcpctlCluster
+	require.NoError(t, k8sClient.Get(ctx, key, &got))
+	assert.Equal(t, string(types.ClusterStatusReady), got.Status.Phase)
+	assert.Equal(t, "https://api.lifecycle.example.com:6443", got.Status.APIURL)
+	assert.Equal(t, "lifecycle-kubeconfig", got.Status.SecretName)
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.ConditionSynced))
+
+	var secret corev1.Secret
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "lifecycle-kubeconfig"}, &secret))
+	assert.Equal(t, "apiVersion: v1\nkind: Config\n", string(secret.Data[SecretKeyKubeconfig]))
+	assert.Equal(t, id, string(secret.Data[SecretKeyClusterID]))
+	require.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, got.UID, secret.OwnerReferences[0].UID)
+
+	// Raising ttlHours extends the cluster to the new TTL, and no further
+	ttl := 8
+	got.Spec.TTLHours = &ttl
+	require.NoError(t, k8sClient.Update(ctx, &got))
+	require.Eventually(t, func() bool {
+		var obj v1alpha1.OcpctlCluster
+		if err := k8sClient.Get(ctx, key, &obj); err != nil {
+			return false
+		}
+		cond := meta.FindStatusCondition(obj.Status.Conditions, v1alpha1.ConditionTTLApplied)
+		return cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == obj.Generation
+	}, eventuallyTimeout, eventuallyTick)
+	assert.Never(t, func() bool {
+		return fakeAPI.cluster(id).TTLHours != 8
+	}, time.Second, eventuallyTick)
+
+	// Lowering ttlHours leaves the TTL alone and reports it
+	require.NoError(t, k8sClient.Get(ctx, key, &got))
+	ttl = 2
+	got.Spec.TTLHours = &ttl
+	require.NoError(t, k8sClient.Update(ctx, &got))
+	require.Eventually(t, func() bool {
+		var obj v1alpha1.OcpctlCluster
+		if err := k8sClient.Get(ctx, key, &obj); err != nil {
+			return false
+		}
+		cond := meta.FindStatusCondition(obj.Status.Conditions, v1alpha1.ConditionTTLApplied)
+		return cond != nil && cond.Status == metav1.ConditionFalse && cond.Reason == "CannotShorten"
+	}, eventuallyTimeout, eventuallyTick)
+	assert.Equal(t, 8, fakeAPI.cluster(id).TTLHours)
+	require.NoError(t, k8sClient.Get(ctx, key, &got))
+
+	// Deleting the resource destroys the cluster before the finalizer is removed
+	require.NoError(t, k8sClient.Delete(ctx, &got))
+	require.Eventually(t, func() bool {
+		err := k8sClient.Get(ctx, key, &v1alpha1.OcpctlCluster{})
+		return apierrors.IsNotFound(err)
+	}, eventuallyTimeout, eventuallyTick)
+	assert.Equal(t, types.ClusterStatusDestroyed, fakeAPI.cluster(id).Status)
+	assert.Equal(t, 1, countClustersNamed("lifecycle"))
+}
+
+func TestClusterReconcilerOrphan(t *testing.T) {
+	ctx := context.Background()
+	obj := newOcpctlCluster("orphaned", v1alpha1.DeletionPolicyOrphan)
+	require.NoError(t, k8sClient.Create(ctx, obj))
+	key := client.ObjectKeyFromObject(obj)
+
+	id := waitForClusterID(t, key)
+
+	require.NoError(t, k8sClient.Delete(ctx, obj))
+	require.Eventually(t, func() bool {
+		err := k8sClient.Get(ctx, key, &v1alpha1.OcpctlCluster{})
+		return apierrors.IsNotFound(err)
+	}, eventuallyTimeout, eventuallyTick)
+	assert.Equal(t, types.ClusterStatusPending, fakeAPI.cluster(id).Status)
+}
+
+func TestClusterReconcilerDestroyedOutsideOperator(t *testing.T) {
+	ctx := context.Background()
+	obj := newOcpctlCluster("expired", v1alpha1.DeletionPolicyDelete)
+	require.NoError(t, k8sClient.Create(ctx, obj))
+	key := client.ObjectKeyFromObject(obj)
+
+	id := waitForClusterID(t, key)
+	fakeAPI.remove(id)
+
+	require.Eventually(t, func() bool {
+		var got v1alpha1.OcpctlCluster
+		if err := k8sClient.Get(ctx, key, &got); err != nil {
+			return false
+		}
+		cond := meta.FindStatusCondition(got.Status.Conditions, v1alpha1.ConditionReady)
+		return got.Status.Phase == string(types.ClusterStatusDestroyed) && cond != nil && cond.Reason == "Destroyed"
+	}, eventuallyTimeout, eventuallyTick)
+
+	// The cluster is not recreated
+	assert.Zero(t, countClustersNamed("expired"))
+
+	require.NoError(t, k8sClient.Delete(ctx, obj))
+	require.Eventually(t, func() bool {
+		err := k8sClient.Get(ctx, key, &v1alpha1.OcpctlCluster{})
+		return apierrors.IsNotFound(err)
+	}, eventuallyTimeout, eventuallyTick)
+}
+
+func TestClusterReconcilerRejectedThenFixed(t *testing.T) {
+	ctx := context.Background()
+	obj := newOcpctlCluster("rejected", v1alpha1.DeletionPolicyDelete)
+	obj.Spec.Profile = unknownProfile
+	require.NoError(t, k8sClient.Create(ctx, obj))
+	key := client.ObjectKeyFromObject(obj)
+
+	require.Eventually(t, func() bool {
+		var got v1alpha1.OcpctlCluster
+		if err := k8sClient.Get(ctx, key, &got); err != nil {
+			return false
+		}
+		cond := meta.FindStatusCondition(got.Status.Conditions, v1alpha1.ConditionReady)
+		return cond != nil && cond.Reason == "Rejected"
+	}, eventuallyTimeout, eventuallyTick)
+	assert.Zero(t, countClustersNamed("rejected"))
+
+	// The corrected spec is created under a new key rather than getting the
+	// rejection replayed, or a 422 for reusing the key
+	require.NoError(t, retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var got v1alpha1.OcpctlCluster
+		if err := k8sClient.Get(ctx, key, &got); err != nil {
+			return err
+		}
+		got.Spec.Profile = "aws-sno-ga"
+		return k8sClient.Update(ctx, &got)
+	}))
+	waitForClusterID(t, key)
+	assert.Equal(t, 1, countClustersNamed("rejected"))
+
+	require.NoError(t, k8sClient.Delete(ctx, obj))
+	require.Eventually(t, func() bool {
+		err := k8sClient.Get(ctx, key, &v1alpha1.OcpctlCluster{})
+		return apierrors.IsNotFound(err)
+	}, eventuallyTimeout, eventuallyTick)
+}
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	obj := newOcpctlCluster("keyed", v1alpha1.DeletionPolicyDelete)
+	obj.UID = "uid-1"
+	obj.Generation = 1
+	assert.Equal(t, "ocpctl-operator/uid-1", createIdempotencyKey(obj))
+	obj.Generation = 2
+	assert.Equal(t, "ocpctl-operator/uid-1/generation-2", createIdempotencyKey(obj))
+}
+
+func countClustersNamed(name string) int {
+	fakeAPI.mu.Lock()
+	defer fakeAPI.mu.Unlock()
+	n := 0
+	for _, c := range fakeAPI.clusters {
+		if c.Name == name {
+			n++
+		}
+	}
+	return n
+}
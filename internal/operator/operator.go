@@ -0,0 +1,110 @@
+// Package operator reconciles the ocpctl custom resources (OcpctlCluster and
+// OcpctlPoolLease) against the ocpctl API, so clusters can be requested from
+// inside a management cluster, for example by a Tekton pipeline.
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/tsanders-rh/ocpctl/pkg/operator/v1alpha1"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+const (
+	// Finalizer keeps a resource until its cluster is destroyed or released
+	Finalizer = "ocpctl.io/finalizer"
+
+	// Keys of the connection Secret
+	SecretKeyKubeconfig = "kubeconfig"
+	SecretKeyToken      = "token"
+	SecretKeyAPIURL     = "api-url"
+	SecretKeyConsoleURL = "console-url"
+	SecretKeyClusterID  = "cluster-id"
+)
+
+// Requeue intervals, variables so tests can shorten them
+var (
+	// ProvisioningRequeue is how often a cluster that is not ready yet is polled
+	ProvisioningRequeue = 30 * time.Second
+	// SteadyStateRequeue is how often a ready cluster or active lease is re-checked
+	SteadyStateRequeue = 5 * time.Minute
+)
+
+// Leader election, shared by both controllers
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
+
+// API is the part of the ocpctl API client used by the controllers.
+// *client.Client from pkg/client implements it.
+type API interface {
+	CreateCluster(ctx context.Context, req *types.CreateClusterAPIRequest) (*types.Cluster, error)
+	GetCluster(ctx context.Context, id string) (*types.Cluster, error)
+	DeleteCluster(ctx context.Context, id string) (*types.Cluster, error)
+	ExtendCluster(ctx context.Context, id string, req *types.ExtendClusterRequest) (*types.Cluster, error)
+	GetClusterOutputs(ctx context.Context, id string) (*types.ClusterOutputsResponse, error)
+	LeaseCluster(ctx context.Context, poolName string, req *types.LeaseRequest) (*types.LeaseResponse, error)
+	ReleaseCluster(ctx context.Context, clusterID string) error
+}
+
+// idempotencyKey is sent with creates and leases so a request repeated after
+// a failed status update returns the original cluster instead of a new one
+func idempotencyKey(obj client.Object) string {
+	return "ocpctl-operator/" + string(obj.GetUID())
+}
+
+// connectionSecretName returns the Secret a resource writes its credentials to
+func connectionSecretName(obj client.Object, override string) string {
+	if override != "" {
+		return override
+	}
+	return obj.GetName() + "-kubeconfig"
+}
+
+// writeConnectionSecret creates or updates the credentials Secret, owned by obj
+// so it is removed along with it
+func writeConnectionSecret(ctx context.Context, c client.Client, owner client.Object, name string, data map[string][]byte) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.GetNamespace()},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, c, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels["app.kubernetes.io/managed-by"] = "ocpctl-operator"
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = data
+		return controllerutil.SetControllerReference(owner, secret, c.Scheme())
+	})
+	if err != nil {
+		return fmt.Errorf("write secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// setCondition records a condition for the resource's current generation
+func setCondition(conditions *[]metav1.Condition, generation int64, condType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setSynced records the outcome of the last ocpctl API call
+func setSynced(conditions *[]metav1.Condition, generation int64, err error) {
+	if err != nil {
+		setCondition(conditions, generation, v1alpha1.ConditionSynced, metav1.ConditionFalse, "APIError", err.Error())
+		return
+	}
+	setCondition(conditions, generation, v1alpha1.ConditionSynced, metav1.ConditionTrue, "Synced", "")
+}
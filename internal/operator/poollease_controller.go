@@ -0,0 +1,206 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ocpctlclient "github.com/tsanders-rh/ocpctl/pkg/client"
+	"github.com/tsanders-rh/ocpctl/pkg/operator/v1alpha1"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// PoolLeaseReconciler leases a cluster for an OcpctlPoolLease and releases it on deletion
+type PoolLeaseReconciler struct {
+	client.Client
+	API API
+}
+
+// +kubebuilder:rbac:groups=ocpctl.io,resources=ocpctlpoolleases,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ocpctl.io,resources=ocpctlpoolleases/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ocpctl.io,resources=ocpctlpoolleases/finalizers,verbs=update
+
+// SetupWithManager registers the reconciler with a manager
+func (r *PoolLeaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.OcpctlPoolLease{}).
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
+
+// Reconcile implements reconcile.Reconciler
+func (r *PoolLeaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var obj v1alpha1.OcpctlPoolLease
+	if err := r.Get(ctx, req.NamespacedName, &obj); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !obj.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &obj)
+	}
+
+	if controllerutil.AddFinalizer(&obj, Finalizer) {
+		if err := r.Update(ctx, &obj); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	status := obj.Status.DeepCopy()
+	result, err := r.reconcile(ctx, &obj, status)
+	setSynced(&status.Conditions, obj.Generation, err)
+	status.ObservedGeneration = obj.Generation
+
+	obj.Status = *status
+	if updateErr := r.Status().Update(ctx, &obj); updateErr != nil {
+		return ctrl.Result{}, updateErr
+	}
+	return result, err
+}
+
+func (r *PoolLeaseReconciler) reconcile(ctx context.Context, obj *v1alpha1.OcpctlPoolLease, status *v1alpha1.OcpctlPoolLeaseStatus) (ctrl.Result, error) {
+	if status.ClusterID != "" {
+		// A lease is not renewed: once it expires the pool reclaims the cluster
+		if status.LeaseExpiresAt != nil && time.Now().After(status.LeaseExpiresAt.Time) {
+			setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, "LeaseExpired", "lease expired; the cluster has returned to the pool")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: untilExpiry(status.LeaseExpiresAt)}, nil
+	}
+
+	metadata := map[string]interface{}{
+		"kubernetes_namespace": obj.Namespace,
+		"kubernetes_name":      obj.Name,
+		"kubernetes_uid":       string(obj.UID),
+	}
+	for k, v := range obj.Spec.Metadata {
+		metadata[k] = v
+	}
+
+	lease, err := r.API.LeaseCluster(ocpctlclient.WithIdempotencyKey(ctx, leaseIdempotencyKey(obj, status.LeaseAttempts)), obj.Spec.PoolName, &types.LeaseRequest{
+		LeasedBy: fmt.Sprintf("k8s:%s/%s", obj.Namespace, obj.Name),
+		Duration: obj.Spec.DurationHours,
+		Metadata: metadata,
+	})
+	if err != nil {
+		if ocpctlclient.IsNotFound(err) {
+			// The pool is missing or has no ready cluster; wait for one. The API
+			// replays the 404 for the same key, so the next attempt uses a new one
+			status.LeaseAttempts++
+			setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, "Waiting", err.Error())
+			return ctrl.Result{RequeueAfter: ProvisioningRequeue}, nil
+		}
+		if isPermanent(err) {
+			setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, "Rejected", err.Error())
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("lease cluster: %w", err)
+	}
+	log.FromContext(ctx).Info("leased ocpctl cluster", "pool", obj.Spec.PoolName, "clusterID", lease.ClusterID)
+
+	status.ClusterID = lease.ClusterID
+	status.ClusterName = lease.ClusterName
+	status.APIURL = lease.APIUrl
+	status.ConsoleURL = lease.ConsoleUrl
+	leasedAt := metav1.NewTime(lease.LeasedAt)
+	expiresAt := metav1.NewTime(lease.LeaseExpiresAt)
+	status.LeasedAt = &leasedAt
+	status.LeaseExpiresAt = &expiresAt
+
+	data := map[string][]byte{
+		SecretKeyAPIURL:     []byte(lease.APIUrl),
+		SecretKeyConsoleURL: []byte(lease.ConsoleUrl),
+		SecretKeyClusterID:  []byte(lease.ClusterID),
+	}
+	if lease.SAToken != "" {
+		kubeconfig, err := tokenKubeconfig(lease.ClusterName, lease.APIUrl, lease.SAToken)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		data[SecretKeyToken] = []byte(lease.SAToken)
+		data[SecretKeyKubeconfig] = kubeconfig
+	}
+
+	secretName := connectionSecretName(obj, obj.Spec.WriteConnectionSecretTo)
+	if err := writeConnectionSecret(ctx, r.Client, obj, secretName, data); err != nil {
+		return ctrl.Result{}, err
+	}
+	status.SecretName = secretName
+	setCondition(&status.Conditions, obj.Generation, v1alpha1.ConditionReady, metav1.ConditionTrue, "Leased", "credentials written to Secret "+secretName)
+
+	return ctrl.Result{RequeueAfter: untilExpiry(status.LeaseExpiresAt)}, nil
+}
+
+// finalize releases the leased cluster back to its pool
+func (r *PoolLeaseReconciler) finalize(ctx context.Context, obj *v1alpha1.OcpctlPoolLease) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(obj, Finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	expired := obj.Status.LeaseExpiresAt != nil && time.Now().After(obj.Status.LeaseExpiresAt.Time)
+	if obj.Status.ClusterID != "" && !expired {
+		err := r.API.ReleaseCluster(ctx, obj.Status.ClusterID)
+		// 400 and 404 mean the cluster is no longer leased or no longer exists
+		if err != nil && !ocpctlclient.IsNotFound(err) && ocpctlclient.StatusCode(err) != 400 {
+			setSynced(&obj.Status.Conditions, obj.Generation, err)
+			if updateErr := r.Status().Update(ctx, obj); updateErr != nil && !apierrors.IsNotFound(updateErr) {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{}, fmt.Errorf("release cluster: %w", err)
+		}
+		log.FromContext(ctx).Info("released ocpctl cluster", "clusterID", obj.Status.ClusterID)
+	}
+
+	controllerutil.RemoveFinalizer(obj, Finalizer)
+	return ctrl.Result{}, r.Update(ctx, obj)
+}
+
+// leaseIdempotencyKey scopes idempotencyKey to one lease attempt. A 404 leaves
+// nothing leased, so moving to a new key after one cannot lease a second cluster.
+func leaseIdempotencyKey(obj client.Object, attempt int) string {
+	if attempt == 0 {
+		return idempotencyKey(obj)
+	}
+	return fmt.Sprintf("%s/%d", idempotencyKey(obj), attempt)
+}
+
+// untilExpiry schedules the next check for the lease expiry, at most SteadyStateRequeue away
+func untilExpiry(expiresAt *metav1.Time) time.Duration {
+	if expiresAt == nil {
+		return SteadyStateRequeue
+	}
+	d := time.Until(expiresAt.Time) + time.Second
+	if d <= 0 || d > SteadyStateRequeue {
+		return SteadyStateRequeue
+	}
+	return d
+}
+
+// tokenKubeconfig builds a kubeconfig for a leased cluster's service account
+// token, in the same form the API serves for pool clusters
+func tokenKubeconfig(clusterName, server, token string) ([]byte, error) {
+	const user = "ocpctl-lease-user"
+	config := clientcmdapi.NewConfig()
+	config.Clusters[clusterName] = &clientcmdapi.Cluster{
+		Server:                server,
+		InsecureSkipTLSVerify: true, // Pool cluster CAs are not published with the lease
+	}
+	config.AuthInfos[user] = &clientcmdapi.AuthInfo{Token: token}
+	config.Contexts[clusterName] = &clientcmdapi.Context{Cluster: clusterName, AuthInfo: user}
+	config.CurrentContext = clusterName
+
+	data, err := clientcmd.Write(*config)
+	if err != nil {
+		return nil, fmt.Errorf("build kubeconfig: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,97 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tsanders-rh/ocpctl/pkg/operator/v1alpha1"
+)
+
+func TestPoolLeaseReconcilerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	duration := 2
+	obj := &v1alpha1.OcpctlPoolLease{
+		ObjectMeta: metav1.ObjectMeta{Name: "e2e-run", Namespace: "default"},
+		Spec: v1alpha1.OcpctlPoolLeaseSpec{
+			PoolName:                "ci-pool",
+			DurationHours:           &duration,
+			Metadata:                map[string]string{"pipelineRun": "e2e-run-abc12"},
+			WriteConnectionSecretTo: "e2e-creds",
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, obj))
+	key := client.ObjectKeyFromObject(obj)
+
+	// The pool is empty, so the lease waits
+	require.Eventually(t, func() bool {
+		var got v1alpha1.OcpctlPoolLease
+		if err := k8sClient.Get(ctx, key, &got); err != nil {
+			return false
+		}
+		cond := meta.FindStatusCondition(got.Status.Conditions, v1alpha1.ConditionReady)
+		return cond != nil && cond.Reason == "Waiting"
+	}, eventuallyTimeout, eventuallyTick)
+
+	id := fakeAPI.addPoolCluster("ci-pool", "pool-ci-1")
+
+	var got v1alpha1.OcpctlPoolLease
+	require.Eventually(t, func() bool {
+		if err := k8sClient.Get(ctx, key, &got); err != nil {
+			return false
+		}
+		return meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.ConditionReady)
+	}, eventuallyTimeout, eventuallyTick)
+	assert.Equal(t, id, got.Status.ClusterID)
+	assert.Equal(t, "pool-ci-1", got.Status.ClusterName)
+	assert.Equal(t, "e2e-creds", got.Status.SecretName)
+	require.NotNil(t, got.Status.LeaseExpiresAt)
+
+	req, ok := fakeAPI.lease(id)
+	require.True(t, ok)
+	assert.Equal(t, "k8s:default/e2e-run", req.LeasedBy)
+	assert.Equal(t, 2, *req.Duration)
+	assert.Equal(t, "e2e-run-abc12", req.Metadata["pipelineRun"])
+	assert.Equal(t, string(got.UID), req.Metadata["kubernetes_uid"])
+
+	var secret corev1.Secret
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "e2e-creds"}, &secret))
+	assert.Equal(t, "token-"+id, string(secret.Data[SecretKeyToken]))
+
+	kubeconfig, err := clientcmd.Load(secret.Data[SecretKeyKubeconfig])
+	require.NoError(t, err)
+	assert.Equal(t, "pool-ci-1", kubeconfig.CurrentContext)
+	assert.Equal(t, "https://api.pool-ci-1.example.com:6443", kubeconfig.Clusters["pool-ci-1"].Server)
+
+	// Deleting the lease releases the cluster
+	require.NoError(t, k8sClient.Delete(ctx, &got))
+	require.Eventually(t, func() bool {
+		err := k8sClient.Get(ctx, key, &v1alpha1.OcpctlPoolLease{})
+		return apierrors.IsNotFound(err)
+	}, eventuallyTimeout, eventuallyTick)
+	assert.True(t, fakeAPI.wasReleased(id))
+}
+
+func TestPoolLeaseSpecImmutable(t *testing.T) {
+	ctx := context.Background()
+	obj := &v1alpha1.OcpctlPoolLease{
+		ObjectMeta: metav1.ObjectMeta{Name: "immutable", Namespace: "default"},
+		Spec:       v1alpha1.OcpctlPoolLeaseSpec{PoolName: "empty-pool"},
+	}
+	require.NoError(t, k8sClient.Create(ctx, obj))
+
+	obj.Spec.PoolName = "other-pool"
+	err := k8sClient.Update(ctx, obj)
+	require.Error(t, err)
+	assert.True(t, apierrors.IsInvalid(err))
+
+	require.NoError(t, k8sClient.Delete(ctx, obj))
+}
@@ -0,0 +1,325 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	ocpctlclient "github.com/tsanders-rh/ocpctl/pkg/client"
+	"github.com/tsanders-rh/ocpctl/pkg/operator/v1alpha1"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// The controller tests run against a real API server from envtest. Point
+// KUBEBUILDER_ASSETS at the control plane binaries to run them:
+//
+//	export KUBEBUILDER_ASSETS=$(setup-envtest use 1.28.x -p path)
+
+var (
+	k8sClient client.Client
+	fakeAPI   *fakeOcpctlAPI
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		fmt.Println("KUBEBUILDER_ASSETS not set, skipping operator envtest suite")
+		os.Exit(0)
+	}
+	os.Exit(runSuite(m))
+}
+
+func runSuite(m *testing.M) int {
+	ProvisioningRequeue = 200 * time.Millisecond
+	SteadyStateRequeue = time.Second
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "deploy", "operator", "crd")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "start envtest: %v\n", err)
+		return 1
+	}
+	defer env.Stop()
+
+	if err := v1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		fmt.Fprintf(os.Stderr, "add scheme: %v\n", err)
+		return 1
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme.Scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create manager: %v\n", err)
+		return 1
+	}
+
+	fakeAPI = newFakeOcpctlAPI()
+	if err := (&ClusterReconciler{Client: mgr.GetClient(), API: fakeAPI}).SetupWithManager(mgr); err != nil {
+		fmt.Fprintf(os.Stderr, "setup cluster controller: %v\n", err)
+		return 1
+	}
+	if err := (&PoolLeaseReconciler{Client: mgr.GetClient(), API: fakeAPI}).SetupWithManager(mgr); err != nil {
+		fmt.Fprintf(os.Stderr, "setup pool lease controller: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "manager stopped: %v\n", err)
+		}
+	}()
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "create client: %v\n", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// fakeOcpctlAPI is an in-memory ocpctl API. Clusters stay in the status the
+// test sets; a destroy completes on the next read. Like the API, creates
+// replay the response recorded for their Idempotency-Key, and reject a key
+// reused for a different request.
+type fakeOcpctlAPI struct {
+	mu       sync.Mutex
+	clusters map[string]*types.Cluster
+	creates  map[string]fakeCreate
+	outputs  map[string]*types.ClusterOutputsResponse
+	pools    map[string][]string
+	leases   map[string]*types.LeaseRequest
+	released []string
+}
+
+func newFakeOcpctlAPI() *fakeOcpctlAPI {
+	return &fakeOcpctlAPI{
+		clusters: map[string]*types.Cluster{},
+		creates:  map[string]fakeCreate{},
+		outputs:  map[string]*types.ClusterOutputsResponse{},
+		pools:    map[string][]string{},
+		leases:   map[string]*types.LeaseRequest{},
+	}
+}
+
+func notFound() error {
+	return &ocpctlclient.APIError{StatusCode: 404, Message: "not found"}
+}
+
+// fakeCreate is the response recorded for a create's Idempotency-Key
+type fakeCreate struct {
+	request types.CreateClusterAPIRequest
+	cluster *types.Cluster
+	err     error
+}
+
+// unknownProfile is rejected by the fake API's creates
+const unknownProfile = "unknown-profile"
+
+func (f *fakeOcpctlAPI) CreateCluster(ctx context.Context, req *types.CreateClusterAPIRequest) (*types.Cluster, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := ocpctlclient.IdempotencyKeyFromContext(ctx)
+	if recorded, ok := f.creates[key]; ok && key != "" {
+		if !reflect.DeepEqual(recorded.request, *req) {
+			return nil, &ocpctlclient.APIError{StatusCode: 422, Message: "Idempotency-Key was already used for a different request"}
+		}
+		if recorded.err != nil {
+			return nil, recorded.err
+		}
+		out := *recorded.cluster
+		return &out, nil
+	}
+
+	c, err := f.create(req)
+	if key != "" {
+		f.creates[key] = fakeCreate{request: *req, cluster: c, err: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := *c
+	return &out, nil
+}
+
+func (f *fakeOcpctlAPI) create(req *types.CreateClusterAPIRequest) (*types.Cluster, error) {
+	if req.Profile == unknownProfile {
+		return nil, &ocpctlclient.APIError{StatusCode: 400, Message: "unknown profile " + req.Profile}
+	}
+	for _, c := range f.clusters {
+		if c.Name == req.Name {
+			return nil, &ocpctlclient.APIError{StatusCode: 409, Message: "cluster name already exists"}
+		}
+	}
+
+	c := &types.Cluster{
+		ID:     uuid.New().String(),
+		Name:   req.Name,
+		Owner:  req.Owner,
+		Status: types.ClusterStatusPending,
+	}
+	if req.TTLHours != nil {
+		c.TTLHours = *req.TTLHours
+	}
+	f.clusters[c.ID] = c
+	return c, nil
+}
+
+func (f *fakeOcpctlAPI) GetCluster(ctx context.Context, id string) (*types.Cluster, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, ok := f.clusters[id]
+	if !ok {
+		return nil, notFound()
+	}
+	if c.Status == types.ClusterStatusDestroying {
+		c.Status = types.ClusterStatusDestroyed
+	}
+	out := *c
+	return &out, nil
+}
+
+func (f *fakeOcpctlAPI) DeleteCluster(ctx context.Context, id string) (*types.Cluster, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, ok := f.clusters[id]
+	if !ok {
+		return nil, notFound()
+	}
+	c.Status = types.ClusterStatusDestroying
+	out := *c
+	return &out, nil
+}
+
+func (f *fakeOcpctlAPI) ExtendCluster(ctx context.Context, id string, req *types.ExtendClusterRequest) (*types.Cluster, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c, ok := f.clusters[id]
+	if !ok {
+		return nil, notFound()
+	}
+	// Like the API, extending adds hours to the current TTL
+	c.TTLHours += req.TTLHours
+	out := *c
+	return &out, nil
+}
+
+func (f *fakeOcpctlAPI) GetClusterOutputs(ctx context.Context, id string) (*types.ClusterOutputsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out, ok := f.outputs[id]
+	if !ok {
+		return &types.ClusterOutputsResponse{ClusterID: id}, nil
+	}
+	return out, nil
+}
+
+func (f *fakeOcpctlAPI) LeaseCluster(ctx context.Context, poolName string, req *types.LeaseRequest) (*types.LeaseResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.pools[poolName]) == 0 {
+		return nil, &ocpctlclient.APIError{StatusCode: 404, Message: "no available clusters in pool"}
+	}
+	id := f.pools[poolName][0]
+	f.pools[poolName] = f.pools[poolName][1:]
+	f.leases[id] = req
+
+	now := time.Now()
+	return &types.LeaseResponse{
+		ClusterID:      id,
+		ClusterName:    f.clusters[id].Name,
+		LeasedBy:       req.LeasedBy,
+		LeasedAt:       now,
+		LeaseExpiresAt: now.Add(time.Hour),
+		APIUrl:         "https://api." + f.clusters[id].Name + ".example.com:6443",
+		ConsoleUrl:     "https://console." + f.clusters[id].Name + ".example.com",
+		SAToken:        "token-" + id,
+	}, nil
+}
+
+func (f *fakeOcpctlAPI) ReleaseCluster(ctx context.Context, clusterID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.leases[clusterID]; !ok {
+		return &ocpctlclient.APIError{StatusCode: 400, Message: "cluster is not leased"}
+	}
+	delete(f.leases, clusterID)
+	f.released = append(f.released, clusterID)
+	return nil
+}
+
+// setStatus moves a cluster to status, with outputs once it is ready
+func (f *fakeOcpctlAPI) setStatus(id string, status types.ClusterStatus, outputs *types.ClusterOutputsResponse) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clusters[id].Status = status
+	if outputs != nil {
+		f.outputs[id] = outputs
+	}
+}
+
+func (f *fakeOcpctlAPI) cluster(id string) types.Cluster {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return *f.clusters[id]
+}
+
+// remove deletes a cluster, as the janitor does at TTL expiry
+func (f *fakeOcpctlAPI) remove(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.clusters, id)
+}
+
+// addPoolCluster adds a ready cluster to a pool
+func (f *fakeOcpctlAPI) addPoolCluster(pool, name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := &types.Cluster{ID: uuid.New().String(), Name: name, Status: types.ClusterStatusReady}
+	f.clusters[c.ID] = c
+	f.pools[pool] = append(f.pools[pool], c.ID)
+	return c.ID
+}
+
+func (f *fakeOcpctlAPI) lease(id string) (*types.LeaseRequest, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	req, ok := f.leases[id]
+	return req, ok
+}
+
+func (f *fakeOcpctlAPI) wasReleased(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.released {
+		if r == id {
+			return true
+		}
+	}
+	return false
+}
@@ -3,20 +3,26 @@ package policy
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
+	"github.com/tsanders-rh/ocpctl/internal/addon"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 // Engine validates cluster creation requests against profile policies
 type Engine struct {
-	registry *profile.Registry
+	registry  *profile.Registry
+	providers *provider.Registry
 }
 
 // NewEngine creates a new policy validation engine
 func NewEngine(registry *profile.Registry) *Engine {
 	return &Engine{
-		registry: registry,
+		registry:  registry,
+		providers: provider.NewRegistry(),
 	}
 }
 
@@ -56,6 +62,9 @@ func (e *Engine) ValidateWithProfile(req *CreateClusterRequest, prof *profile.Pr
 	e.validateTTL(req, prof, result)
 	e.validateTags(req, prof, result)
 	e.validateOffhoursOptIn(req, prof, result)
+	e.validateOverrides(req, prof, result)
+	e.validateRestrictedNetwork(req, prof, result)
+	e.validateArchitecture(req, prof, result)
 
 	// Calculate destroy_at timestamp (0 = never expires)
 	if result.Valid && req.TTLHours > 0 {
@@ -103,10 +112,12 @@ func (e *Engine) validateVersion(req *CreateClusterRequest, prof *profile.Profil
 	var versionConfig *profile.VersionConfig
 	var versionType string
 
-	if req.ClusterType == "openshift" {
+	caps, _ := e.providers.Capabilities(types.ClusterType(req.ClusterType))
+	switch caps.Versions {
+	case provider.VersionTrackOpenShift:
 		versionConfig = prof.OpenshiftVersions
 		versionType = "OpenShift"
-	} else if req.ClusterType == "eks" || req.ClusterType == "iks" {
+	case provider.VersionTrackKubernetes:
 		versionConfig = prof.KubernetesVersions
 		versionType = "Kubernetes"
 	}
@@ -153,12 +164,10 @@ func (e *Engine) validateRegion(req *CreateClusterRequest, prof *profile.Profile
 
 // validateBaseDomain checks base domain is in profile allowlist
 func (e *Engine) validateBaseDomain(req *CreateClusterRequest, prof *profile.Profile, result *ValidationResult) {
-	// Base domain is only required for self-managed OpenShift IPI clusters
-	// Managed services don't use base domain (cloud provider manages DNS):
-	// - ROSA uses AWS-managed DNS
-	// - ARO uses Azure-managed DNS
-	// - EKS, IKS, GKE, AKS are managed Kubernetes (no base domain)
-	if req.ClusterType == "eks" || req.ClusterType == "iks" || req.ClusterType == "gke" || req.ClusterType == "rosa" || req.ClusterType == "aro" || req.ClusterType == "aks" {
+	// Base domain is only required for self-managed OpenShift IPI and HCP
+	// clusters (HCP publishes its API and ingress under it). Managed services
+	// leave DNS to the cloud provider and kind has no DNS at all.
+	if caps, ok := e.providers.Capabilities(types.ClusterType(req.ClusterType)); ok && caps.BaseDomain != provider.BaseDomainRequired {
 		return
 	}
 
@@ -253,6 +262,192 @@ func (e *Engine) validateOffhoursOptIn(req *CreateClusterRequest, prof *profile.
 	}
 }
 
+// machinePoolNameRegex matches DNS-label pool names; AKS additionally caps
+// pool names at 12 lowercase alphanumerics
+var (
+	machinePoolNameRegex    = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	aksMachinePoolNameRegex = regexp.MustCompile(`^[a-z][a-z0-9]{0,11}$`)
+)
+
+// validateOverrides checks compute overrides against the profile's overridable
+// schema. Profiles without an overridable section have a fixed shape, though
+// any request may ask for on-demand instead of spot workers.
+func (e *Engine) validateOverrides(req *CreateClusterRequest, prof *profile.Profile, result *ValidationResult) {
+	o := req.Overrides
+	if o.IsEmpty() {
+		return
+	}
+	if shape := *o; shape.OnDemand {
+		shape.OnDemand = false
+		if shape.IsEmpty() {
+			return
+		}
+	}
+	allowed := prof.Overridable
+	if allowed == nil {
+		result.AddError("computeOverrides", fmt.Sprintf("profile %s does not allow compute overrides", prof.Name))
+		return
+	}
+
+	if o.WorkerReplicas != nil {
+		if allowed.Workers == nil {
+			result.AddError("computeOverrides.workerReplicas", "worker count is not overridable for this profile")
+		} else if *o.WorkerReplicas < allowed.Workers.Min || *o.WorkerReplicas > allowed.Workers.Max {
+			result.AddError("computeOverrides.workerReplicas", fmt.Sprintf("worker count %d outside allowed range %d-%d",
+				*o.WorkerReplicas, allowed.Workers.Min, allowed.Workers.Max))
+		}
+	}
+
+	if o.WorkerInstanceType != "" && !contains(allowed.InstanceTypes, o.WorkerInstanceType) {
+		result.AddError("computeOverrides.workerInstanceType", fmt.Sprintf("instance type %s not in profile allowlist: %v",
+			o.WorkerInstanceType, allowed.InstanceTypes))
+	}
+
+	if o.RootVolumeGB != nil {
+		if allowed.RootVolumeGB == nil {
+			result.AddError("computeOverrides.rootVolumeGB", "root volume size is not overridable for this profile")
+		} else if *o.RootVolumeGB < allowed.RootVolumeGB.Min || *o.RootVolumeGB > allowed.RootVolumeGB.Max {
+			result.AddError("computeOverrides.rootVolumeGB", fmt.Sprintf("root volume %dGB outside allowed range %d-%dGB",
+				*o.RootVolumeGB, allowed.RootVolumeGB.Min, allowed.RootVolumeGB.Max))
+		}
+	}
+
+	if len(o.MachinePools) > 0 {
+		e.validateMachinePools(o.MachinePools, prof, result)
+	}
+
+	// Bounds are fine; make sure the platform has somewhere to put them
+	if result.Valid {
+		if _, err := profile.ApplyOverrides(prof, o); err != nil {
+			result.AddError("computeOverrides", err.Error())
+		}
+	}
+}
+
+// validateRestrictedNetwork checks the request's proxy, trust bundle and image
+// mirror settings. The profile's own settings are checked when it is loaded,
+// but a proxy credentials secret from either must belong to the cluster's team.
+func (e *Engine) validateRestrictedNetwork(req *CreateClusterRequest, prof *profile.Profile, result *ValidationResult) {
+	for _, problem := range profile.ValidateRestrictedNetwork(req.RestrictedNetwork, prof) {
+		field := "restrictedNetwork"
+		if problem.Field != "" {
+			field += "." + problem.Field
+		}
+		result.AddError(field, problem.Message)
+	}
+
+	if rn := profile.EffectiveRestrictedNetwork(prof, req.RestrictedNetwork); rn != nil && rn.Proxy != nil && rn.Proxy.CredentialsSecret != "" {
+		ref := rn.Proxy.CredentialsSecret
+		if team, _, _ := strings.Cut(ref, "/"); team != req.Team {
+			result.AddError("restrictedNetwork.proxy.credentialsSecret",
+				fmt.Sprintf("secret %s belongs to team %s; clusters of team %s can only reference %s/ secrets", ref, team, req.Team, req.Team))
+		}
+	}
+}
+
+// validateArchitecture checks the request's architecture, merged over the
+// profile's, against the instance types the cluster will be created with
+func (e *Engine) validateArchitecture(req *CreateClusterRequest, prof *profile.Profile, result *ValidationResult) {
+	if req.Architecture.IsEmpty() && req.Overrides.IsEmpty() {
+		return // the profile's own settings are checked when it is loaded
+	}
+	shaped, err := profile.ApplyOverrides(prof, req.Overrides)
+	if err != nil {
+		return // reported by validateOverrides
+	}
+	arch := profile.EffectiveArchitecture(prof, req.Architecture)
+	for _, problem := range profile.ValidateArchitecture(arch, shaped) {
+		field := "architecture"
+		if problem.Field != "" {
+			field += "." + problem.Field
+		}
+		result.AddError(field, problem.Message)
+	}
+}
+
+// ValidateAddons checks that add-on versions declare support for a cluster's
+// type and version. It is run once add-on selections are resolved, for new
+// clusters and for add-ons installed on running ones.
+func (e *Engine) ValidateAddons(clusterType types.ClusterType, version string, addons []types.PostConfigAddon) *ValidationResult {
+	result := &ValidationResult{
+		Valid:  true,
+		Errors: []ValidationError{},
+	}
+	for i := range addons {
+		if err := addon.CheckCompatibility(addons[i].Metadata, clusterType, version); err != nil {
+			result.AddError("post_config_addons", fmt.Sprintf("addon '%s' version '%s' is not compatible with this cluster: %v",
+				addons[i].AddonID, addons[i].Version, err))
+		}
+	}
+	return result
+}
+
+// validateMachinePools checks the extra machine pools requested on top of the
+// default worker pool
+func (e *Engine) validateMachinePools(pools []types.MachinePoolOverride, prof *profile.Profile, result *ValidationResult) {
+	limits := prof.Overridable.MachinePools
+	if limits == nil {
+		result.AddError("computeOverrides.machinePools", "extra machine pools are not allowed by this profile")
+		return
+	}
+	if len(pools) > limits.MaxPools {
+		result.AddError("computeOverrides.machinePools", fmt.Sprintf("%d machine pools requested, profile allows at most %d", len(pools), limits.MaxPools))
+	}
+
+	nameRegex := machinePoolNameRegex
+	if prof.EffectiveClusterType() == types.ClusterTypeAKS {
+		nameRegex = aksMachinePoolNameRegex
+	}
+
+	seen := map[string]bool{}
+	for i, pool := range pools {
+		field := fmt.Sprintf("computeOverrides.machinePools[%d]", i)
+		switch {
+		case pool.Name == "":
+			result.AddError(field+".name", "machine pool name is required")
+		case len(pool.Name) > 30 || !nameRegex.MatchString(pool.Name):
+			result.AddError(field+".name", fmt.Sprintf("invalid machine pool name %q", pool.Name))
+		case isReservedPoolName(pool.Name, prof):
+			result.AddError(field+".name", fmt.Sprintf("machine pool name %q is used by the default pool", pool.Name))
+		case seen[pool.Name]:
+			result.AddError(field+".name", fmt.Sprintf("duplicate machine pool name %q", pool.Name))
+		}
+		seen[pool.Name] = true
+
+		if !contains(prof.Overridable.InstanceTypes, pool.InstanceType) {
+			result.AddError(field+".instanceType", fmt.Sprintf("instance type %q not in profile allowlist: %v",
+				pool.InstanceType, prof.Overridable.InstanceTypes))
+		}
+		if pool.Replicas < 1 || pool.Replicas > limits.MaxReplicas {
+			result.AddError(field+".replicas", fmt.Sprintf("replicas %d outside allowed range 1-%d", pool.Replicas, limits.MaxReplicas))
+		}
+	}
+}
+
+// isReservedPoolName reports whether a pool name collides with one the
+// profile already defines
+func isReservedPoolName(name string, prof *profile.Profile) bool {
+	if name == "worker" || name == "master" {
+		return true
+	}
+	for _, s := range prof.WorkerShapes() {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// contains checks if a slice contains a string
+func contains(slice []string, str string) bool {
+	for _, s := range slice {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
 // GetDefaultVersion returns the default version for a profile
 func (e *Engine) GetDefaultVersion(profileName string) (string, error) {
 	prof, err := e.registry.Get(profileName)
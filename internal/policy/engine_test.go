@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tsanders-rh/ocpctl/internal/policy"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 func setupPolicyEngine(t *testing.T) *policy.Engine {
@@ -104,6 +105,59 @@ func TestEngine_ValidateCreateRequest(t *testing.T) {
 		assert.True(t, found, "should have version validation error")
 	})
 
+	t.Run("validates HCP versions against openshiftVersions", func(t *testing.T) {
+		req := &policy.CreateClusterRequest{
+			Name:        "hcp-cluster-01",
+			Platform:    "aws",
+			Version:     "4.20.3",
+			Profile:     "hcp-standard",
+			Region:      "us-east-1",
+			BaseDomain:  "mg.dog8code.com",
+			Owner:       "test-user",
+			Team:        "platform-team",
+			CostCenter:  "engineering",
+			TTLHours:    24,
+			ClusterType: "hcp",
+		}
+
+		result, err := engine.ValidateCreateRequest(req)
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+
+		req.Version = "4.20"
+		result, err = engine.ValidateCreateRequest(req)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Equal(t, "version", result.Errors[0].Field)
+	})
+
+	t.Run("validates kind versions against kubernetesVersions without a base domain", func(t *testing.T) {
+		req := &policy.CreateClusterRequest{
+			Name:        "kind-cluster-01",
+			Platform:    "local",
+			Version:     "1.33.1",
+			Profile:     "kind-local",
+			Region:      "local",
+			Owner:       "test-user",
+			Team:        "platform-team",
+			CostCenter:  "engineering",
+			TTLHours:    4,
+			ClusterType: "kind",
+		}
+
+		result, err := engine.ValidateCreateRequest(req)
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+
+		req.Version = "1.30.0"
+		result, err = engine.ValidateCreateRequest(req)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		assert.Equal(t, "version", result.Errors[0].Field)
+	})
+
 	t.Run("rejects region not in allowlist", func(t *testing.T) {
 		req := &policy.CreateClusterRequest{
 			Name:        "test-cluster-01",
@@ -252,3 +306,228 @@ func TestEngine_GetDefaults(t *testing.T) {
 		assert.Equal(t, 24, ttl)
 	})
 }
+
+func TestEngine_ValidateOverrides(t *testing.T) {
+	engine := setupPolicyEngine(t)
+
+	baseReq := func(o *types.ComputeOverrides) *policy.CreateClusterRequest {
+		return &policy.CreateClusterRequest{
+			Name:        "test-cluster-01",
+			Platform:    "aws",
+			ClusterType: "openshift",
+			Version:     "4.20",
+			Profile:     "aws-standard-ga",
+			Region:      "us-east-1",
+			BaseDomain:  "mg.dog8code.com",
+			Owner:       "test-user",
+			Team:        "platform-team",
+			CostCenter:  "engineering",
+			TTLHours:    24,
+			Overrides:   o,
+		}
+	}
+	intPtr := func(i int) *int { return &i }
+	fields := func(result *policy.ValidationResult) []string {
+		var out []string
+		for _, e := range result.Errors {
+			out = append(out, e.Field)
+		}
+		return out
+	}
+
+	t.Run("accepts overrides within bounds", func(t *testing.T) {
+		result, err := engine.ValidateCreateRequest(baseReq(&types.ComputeOverrides{
+			WorkerReplicas:     intPtr(6),
+			WorkerInstanceType: "m6i.4xlarge",
+			RootVolumeGB:       intPtr(250),
+			MachinePools: []types.MachinePoolOverride{
+				{Name: "gpu", InstanceType: "m6i.xlarge", Replicas: 2},
+			},
+		}))
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+
+	t.Run("rejects out of range values", func(t *testing.T) {
+		result, err := engine.ValidateCreateRequest(baseReq(&types.ComputeOverrides{
+			WorkerReplicas:     intPtr(12),
+			WorkerInstanceType: "p4d.24xlarge",
+			RootVolumeGB:       intPtr(50),
+		}))
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.ElementsMatch(t, []string{
+			"computeOverrides.workerReplicas",
+			"computeOverrides.workerInstanceType",
+			"computeOverrides.rootVolumeGB",
+		}, fields(result))
+	})
+
+	t.Run("rejects invalid machine pools", func(t *testing.T) {
+		result, err := engine.ValidateCreateRequest(baseReq(&types.ComputeOverrides{
+			MachinePools: []types.MachinePoolOverride{
+				{Name: "worker", InstanceType: "m6i.xlarge", Replicas: 1},
+				{Name: "Bad_Name", InstanceType: "m6i.xlarge", Replicas: 9},
+				{Name: "extra", InstanceType: "m6i.xlarge", Replicas: 1},
+			},
+		}))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"computeOverrides.machinePools",
+			"computeOverrides.machinePools[0].name",
+			"computeOverrides.machinePools[1].name",
+			"computeOverrides.machinePools[1].replicas",
+		}, fields(result))
+	})
+
+	t.Run("rejects overrides on a fixed profile", func(t *testing.T) {
+		req := baseReq(&types.ComputeOverrides{WorkerReplicas: intPtr(3)})
+		req.Profile = "aws-minimal-test"
+		result, err := engine.ValidateCreateRequest(req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"computeOverrides"}, fields(result))
+	})
+
+	t.Run("ignores empty overrides", func(t *testing.T) {
+		req := baseReq(&types.ComputeOverrides{})
+		req.Profile = "aws-minimal-test"
+		result, err := engine.ValidateCreateRequest(req)
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+}
+
+func TestEngine_ValidateRestrictedNetwork(t *testing.T) {
+	engine := setupPolicyEngine(t)
+
+	req := func(profileName, clusterType string, rn *types.RestrictedNetwork) *policy.CreateClusterRequest {
+		return &policy.CreateClusterRequest{
+			Name:              "test-cluster-01",
+			Platform:          "aws",
+			ClusterType:       clusterType,
+			Version:           "4.20",
+			Profile:           profileName,
+			Region:            "us-east-1",
+			BaseDomain:        "mg.dog8code.com",
+			Owner:             "test-user",
+			Team:              "platform-team",
+			CostCenter:        "engineering",
+			TTLHours:          24,
+			RestrictedNetwork: rn,
+		}
+	}
+	mirrors := []types.ImageMirror{{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []string{"registry.lab:5000/ocp/release"}}}
+
+	t.Run("accepts proxy and mirrors for openshift", func(t *testing.T) {
+		result, err := engine.ValidateCreateRequest(req("aws-standard-ga", "openshift", &types.RestrictedNetwork{
+			Proxy:        &types.ProxyConfig{HTTPProxy: "http://proxy.lab:3128", NoProxy: ".lab"},
+			ImageMirrors: mirrors,
+		}))
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+
+	t.Run("rejects mirrors for rosa", func(t *testing.T) {
+		result, err := engine.ValidateCreateRequest(req("rosa-standard", "rosa", &types.RestrictedNetwork{ImageMirrors: mirrors}))
+		require.NoError(t, err)
+		require.False(t, result.Valid)
+		assert.Equal(t, "restrictedNetwork.imageMirrors", result.Errors[len(result.Errors)-1].Field)
+	})
+
+	t.Run("rejects another team's proxy credentials", func(t *testing.T) {
+		proxy := &types.ProxyConfig{HTTPProxy: "http://proxy.lab:3128", CredentialsSecret: "platform-team/proxy"}
+		result, err := engine.ValidateCreateRequest(req("aws-standard-ga", "openshift", &types.RestrictedNetwork{Proxy: proxy}))
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+
+		proxy.CredentialsSecret = "storage-team/proxy"
+		result, err = engine.ValidateCreateRequest(req("aws-standard-ga", "openshift", &types.RestrictedNetwork{Proxy: proxy}))
+		require.NoError(t, err)
+		require.False(t, result.Valid)
+		assert.Equal(t, "restrictedNetwork.proxy.credentialsSecret", result.Errors[0].Field)
+	})
+
+	t.Run("rejects an invalid trust bundle", func(t *testing.T) {
+		result, err := engine.ValidateCreateRequest(req("aws-standard-ga", "openshift", &types.RestrictedNetwork{AdditionalTrustBundle: "not a certificate"}))
+		require.NoError(t, err)
+		require.False(t, result.Valid)
+		assert.Equal(t, "restrictedNetwork.additionalTrustBundle", result.Errors[0].Field)
+	})
+}
+
+func TestEngine_ValidateArchitecture(t *testing.T) {
+	engine := setupPolicyEngine(t)
+
+	req := func(profileName, clusterType string, arch *types.ClusterArchitecture) *policy.CreateClusterRequest {
+		return &policy.CreateClusterRequest{
+			Name:         "test-cluster-01",
+			Platform:     "aws",
+			ClusterType:  clusterType,
+			Version:      "4.20",
+			Profile:      profileName,
+			Region:       "us-east-1",
+			BaseDomain:   "mg.dog8code.com",
+			Owner:        "test-user",
+			Team:         "platform-team",
+			CostCenter:   "engineering",
+			TTLHours:     24,
+			Architecture: arch,
+		}
+	}
+
+	t.Run("accepts amd64 explicitly", func(t *testing.T) {
+		result, err := engine.ValidateCreateRequest(req("aws-standard-ga", "openshift", &types.ClusterArchitecture{
+			ControlPlane: types.ArchitectureAMD64, Compute: types.ArchitectureAMD64,
+		}))
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+
+	t.Run("rejects arm64 workers on amd64 instance types", func(t *testing.T) {
+		result, err := engine.ValidateCreateRequest(req("aws-standard-ga", "openshift", &types.ClusterArchitecture{Compute: types.ArchitectureARM64}))
+		require.NoError(t, err)
+		require.False(t, result.Valid)
+		assert.Equal(t, "architecture.compute", result.Errors[0].Field)
+	})
+
+	t.Run("rejects arm64 for rosa", func(t *testing.T) {
+		result, err := engine.ValidateCreateRequest(req("rosa-standard", "rosa", &types.ClusterArchitecture{Compute: types.ArchitectureARM64}))
+		require.NoError(t, err)
+		require.False(t, result.Valid)
+		assert.Contains(t, result.Errors[len(result.Errors)-1].Message, "not supported for rosa")
+	})
+}
+
+func TestEngine_ValidateAddons(t *testing.T) {
+	engine := setupPolicyEngine(t)
+
+	cnv := types.PostConfigAddon{
+		AddonID: "cnv",
+		Version: "stable",
+		Metadata: &types.AddonMetadata{Compatibility: &types.AddonCompatibility{
+			OpenShiftVersions: ">=4.17",
+			ClusterTypes:      []types.ClusterType{types.ClusterTypeOpenShift, types.ClusterTypeROSA},
+		}},
+	}
+	oadp := types.PostConfigAddon{AddonID: "oadp", Version: "stable"}
+
+	t.Run("accepts compatible add-ons", func(t *testing.T) {
+		result := engine.ValidateAddons(types.ClusterTypeOpenShift, "4.18.3", []types.PostConfigAddon{cnv, oadp})
+		assert.True(t, result.Valid, "errors: %v", result.Errors)
+	})
+
+	t.Run("rejects unsupported versions", func(t *testing.T) {
+		result := engine.ValidateAddons(types.ClusterTypeROSA, "4.16.9", []types.PostConfigAddon{cnv, oadp})
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "post_config_addons", result.Errors[0].Field)
+		assert.Contains(t, result.Errors[0].Message, "addon 'cnv' version 'stable'")
+		assert.Contains(t, result.Errors[0].Message, "OpenShift 4.16.9 is not supported")
+	})
+
+	t.Run("rejects unsupported cluster types", func(t *testing.T) {
+		result := engine.ValidateAddons(types.ClusterTypeEKS, "1.31", []types.PostConfigAddon{cnv})
+		require.False(t, result.Valid)
+		assert.Contains(t, result.Errors[0].Message, "cluster type eks is not supported")
+	})
+}
@@ -161,7 +161,11 @@ func (s *Scheduler) checkExpiredLeases() {
 			clusterName, pool.Name, *leasedBy, leaseExpiresAt.Format(time.RFC3339))
 
 		// Release the cluster (transitions to CLEANING state)
-		if err := s.store.Pools.ReleaseCluster(ctx, clusterID); err != nil {
+		releaseCtx := store.WithEventSource(ctx, store.EventSource{
+			Actor:  store.EventActorSystem,
+			Reason: "lease expired",
+		})
+		if err := s.store.Pools.ReleaseCluster(releaseCtx, clusterID); err != nil {
 			log.Printf("Error releasing expired lease (cluster=%s): %v", clusterName, err)
 			continue
 		}
@@ -0,0 +1,610 @@
+package postconfig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+// conditionCostLimit bounds the work a single condition may do. Conditions
+// are short boolean checks; the limit only stops pathological comprehensions.
+const conditionCostLimit = 10000
+
+// Condition variables. Besides these, each custom variable whose name is a
+// valid identifier is declared as a top-level string, as the original
+// "variable operator value" syntax allowed. A custom variable named after one
+// of the variables below is only reachable as vars.name in CEL, but keeps its
+// string meaning in the original syntax, e.g. "version == 4.14".
+//
+//	cluster           map: id, name, type, platform, region, profile, baseDomain, infraID, workerCount
+//	clusterType       string
+//	platform          string
+//	region            string
+//	profile           string
+//	baseDomain        string
+//	version           semver (OpenShift version for OpenShift clusters, Kubernetes otherwise)
+//	kubernetesVersion semver
+//	workerCount       int
+//	features          map(string, bool) of profile features, e.g. features.fipsMode
+//	addons            list(string) of selected addon IDs
+//	capabilities      list(string) of profile capabilities, e.g. "bare-metal"
+//	vars              map(string, string) of custom variables
+var conditionBuiltins = []string{
+	"cluster", "clusterType", "platform", "region", "profile", "baseDomain",
+	"version", "kubernetesVersion", "workerCount", "features", "addons", "capabilities", "vars",
+}
+
+var (
+	semverCELType     = cel.OpaqueType("semver")
+	semverRuntimeType = types.NewTypeValue("semver", traits.ComparerType)
+)
+
+// CompileCondition checks a condition's syntax and types without evaluating
+// it. varNames are the custom variables the task declares.
+func CompileCondition(condition string, varNames []string) error {
+	_, err := compileCondition(condition, varNames)
+	return err
+}
+
+// EvaluateCondition evaluates a conditional expression against the context.
+// Conditions are CEL expressions that must yield a bool, for example:
+//   - "clusterType == 'openshift'"
+//   - "platform == 'aws' && version >= '4.18'"
+//   - "workerCount >= 3 && features.fipsMode"
+//   - "'openshift-virtualization' in addons"
+//
+// The earlier "variable operator value" form, including "region contains 'us-'"
+// and unquoted values, is still accepted.
+func EvaluateCondition(condition string, ctx *TemplateContext) (bool, error) {
+	if condition == "" {
+		return true, nil // Empty condition always passes
+	}
+
+	varNames := make([]string, 0, len(ctx.Variables))
+	for name := range ctx.Variables {
+		varNames = append(varNames, name)
+	}
+	prg, err := compileCondition(condition, varNames)
+	if err != nil {
+		return false, err
+	}
+
+	out, _, err := prg.Eval(conditionActivation(ctx))
+	if err != nil {
+		return false, fmt.Errorf("evaluate condition %q: %w", condition, err)
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("condition %q did not evaluate to a bool", condition)
+	}
+	return result, nil
+}
+
+// compileCondition parses and type-checks a condition and plans it for
+// evaluation
+func compileCondition(condition string, varNames []string) (cel.Program, error) {
+	env, err := conditionEnv(varNames)
+	if err != nil {
+		return nil, fmt.Errorf("create condition environment: %w", err)
+	}
+
+	parsed, issues := env.Parse(legacyCondition(condition, varNames))
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", condition, issues.Err())
+	}
+	parsed, err = coerceVersionEquality(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", condition, err)
+	}
+	ast, issues := env.Check(parsed)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", condition, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("invalid condition %q: must evaluate to a bool, not %s", condition, ast.OutputType())
+	}
+
+	prg, err := env.Program(ast, cel.CostLimit(conditionCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", condition, err)
+	}
+	return prg, nil
+}
+
+// conditionEnv builds the CEL environment conditions are checked in
+func conditionEnv(varNames []string) (*cel.Env, error) {
+	opts := []cel.EnvOption{
+		cel.Variable("cluster", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("clusterType", cel.StringType),
+		cel.Variable("platform", cel.StringType),
+		cel.Variable("region", cel.StringType),
+		cel.Variable("profile", cel.StringType),
+		cel.Variable("baseDomain", cel.StringType),
+		cel.Variable("version", semverCELType),
+		cel.Variable("kubernetesVersion", semverCELType),
+		cel.Variable("workerCount", cel.IntType),
+		cel.Variable("features", cel.MapType(cel.StringType, cel.BoolType)),
+		cel.Variable("addons", cel.ListType(cel.StringType)),
+		cel.Variable("capabilities", cel.ListType(cel.StringType)),
+		cel.Variable("vars", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Function("semver",
+			cel.Overload("semver_string", []*cel.Type{cel.StringType}, semverCELType,
+				cel.UnaryBinding(func(v ref.Val) ref.Val { return toSemverVal(v) }))),
+		cel.Function("major",
+			cel.MemberOverload("semver_major", []*cel.Type{semverCELType}, cel.IntType,
+				cel.UnaryBinding(func(v ref.Val) ref.Val { return types.Int(v.(semverVal).Major) }))),
+		cel.Function("minor",
+			cel.MemberOverload("semver_minor", []*cel.Type{semverCELType}, cel.IntType,
+				cel.UnaryBinding(func(v ref.Val) ref.Val { return types.Int(v.(semverVal).Minor) }))),
+	}
+
+	// Ordering operators between versions, and from a version to a version
+	// string, so "version >= '4.18'" compares numerically. These only declare
+	// the overloads for the type checker: at runtime the standard operators
+	// dispatch to semverVal.Compare. Equality is CEL's own, between two
+	// versions; coerceVersionEquality turns "version == '4.18'" into that.
+	for _, op := range []struct{ name, id string }{
+		{operators.Less, "less"},
+		{operators.LessEquals, "less_equals"},
+		{operators.Greater, "greater"},
+		{operators.GreaterEquals, "greater_equals"},
+	} {
+		opts = append(opts, cel.Function(op.name,
+			cel.Overload(op.id+"_semver_semver", []*cel.Type{semverCELType, semverCELType}, cel.BoolType),
+			cel.Overload(op.id+"_semver_string", []*cel.Type{semverCELType, cel.StringType}, cel.BoolType),
+		))
+	}
+
+	builtin := make(map[string]bool, len(conditionBuiltins))
+	for _, name := range conditionBuiltins {
+		builtin[name] = true
+	}
+	sorted := append([]string(nil), varNames...)
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		if !builtin[name] && identifierPattern.MatchString(name) && !celReserved[name] {
+			opts = append(opts, cel.Variable(name, cel.StringType))
+		}
+	}
+
+	return cel.NewEnv(opts...)
+}
+
+// conditionActivation returns the variable values a condition is evaluated
+// with
+func conditionActivation(ctx *TemplateContext) map[string]any {
+
+	features := ctx.Features
+	if features == nil {
+		features = map[string]bool{}
+	}
+	addons := ctx.Addons
+	if addons == nil {
+		addons = []string{}
+	}
+	capabilities := ctx.Capabilities
+	if capabilities == nil {
+		capabilities = []string{}
+	}
+	vars := ctx.Variables
+	if vars == nil {
+		vars = map[string]string{}
+	}
+
+	kubernetesVersion := ref.Val(conditionVersion(ctx.KubernetesVersion))
+	if ctx.kubernetesVersionErr != nil {
+		kubernetesVersion = types.NewErr("kubernetesVersion: %v", ctx.kubernetesVersionErr)
+	}
+
+	activation := map[string]any{
+		"cluster": map[string]any{
+			"id":          ctx.ClusterID,
+			"name":        ctx.ClusterName,
+			"type":        ctx.ClusterType,
+			"platform":    ctx.Platform,
+			"region":      ctx.Region,
+			"profile":     ctx.Profile,
+			"baseDomain":  ctx.BaseDomain,
+			"infraID":     ctx.InfraID,
+			"workerCount": ctx.WorkerCount,
+		},
+		"clusterType":       ctx.ClusterType,
+		"platform":          ctx.Platform,
+		"region":            ctx.Region,
+		"profile":           ctx.Profile,
+		"baseDomain":        ctx.BaseDomain,
+		"version":           conditionVersion(ctx.Version),
+		"kubernetesVersion": kubernetesVersion,
+		"workerCount":       ctx.WorkerCount,
+		"features":          features,
+		"addons":            addons,
+		"capabilities":      capabilities,
+		"vars":              vars,
+	}
+	for name, value := range vars {
+		if _, builtin := activation[name]; !builtin {
+			activation[name] = value
+		}
+	}
+	return activation
+}
+
+// conditionVersion parses a context version. Unknown or unparseable versions
+// are 0.0.0, so conditions that don't look at the version still evaluate.
+func conditionVersion(s string) semverVal {
+	v, err := ParseVersion(s)
+	if err != nil {
+		return semverVal{}
+	}
+	return semverVal{v}
+}
+
+var (
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// legacyContains matches the original "variable contains value" form
+	legacyContains = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s+contains\s+(.+?)\s*$`)
+
+	// legacyComparison matches the original "variable == value" form with a
+	// quoted or unquoted value, e.g. "platform == aws" or "replicas == 3"
+	legacyComparison = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*(==|!=)\s*('[^']*'|"[^"]*"|[A-Za-z0-9][A-Za-z0-9_.-]*)\s*$`)
+)
+
+// legacyStringVariables are the built-in variables the original syntax knew
+// about. Like custom variables, they are strings, so unquoted values compared
+// with them are strings too.
+var legacyStringVariables = map[string]bool{
+	"clusterType": true, "platform": true, "region": true, "profile": true, "baseDomain": true,
+}
+
+// celReserved are CEL keywords and literals that cannot name a variable
+var celReserved = map[string]bool{
+	"true": true, "false": true, "null": true, "in": true,
+	"as": true, "break": true, "const": true, "continue": true, "else": true,
+	"for": true, "function": true, "if": true, "import": true, "let": true,
+	"loop": true, "package": true, "namespace": true, "return": true, "var": true,
+	"void": true, "while": true,
+}
+
+// legacyCondition rewrites conditions written in the original
+// "variable operator value" syntax so they keep their meaning in CEL: the
+// contains operator, values without quotes, which are strings when compared
+// with a string variable, and custom variables whose names are now built-in
+// variables, which are read from vars
+func legacyCondition(condition string, varNames []string) string {
+	custom := make(map[string]bool, len(varNames))
+	for _, name := range varNames {
+		custom[name] = true
+	}
+	variable := func(name string) string {
+		if custom[name] && !legacyStringVariables[name] && isConditionBuiltin(name) {
+			return fmt.Sprintf("vars[%s]", strconv.Quote(name))
+		}
+		return name
+	}
+
+	if m := legacyContains.FindStringSubmatch(condition); m != nil {
+		return fmt.Sprintf("%s.contains(%s)", variable(m[1]), quoteLegacyValue(m[2]))
+	}
+
+	m := legacyComparison.FindStringSubmatch(condition)
+	if m == nil {
+		return condition
+	}
+	name, op, value := m[1], m[2], m[3]
+	if custom[name] || legacyStringVariables[name] {
+		return fmt.Sprintf("%s %s %s", variable(name), op, quoteLegacyValue(value))
+	}
+	// Other built-in variables are not strings: only words are quoted, so
+	// "workerCount == 3" stays a number
+	if isLetter(value[0]) && !celReserved[value] {
+		return fmt.Sprintf("%s %s %s", name, op, strconv.Quote(value))
+	}
+	return condition
+}
+
+// coerceVersionEquality wraps string literals compared with == or != to a
+// version in semver(), so "version == '4.18.3'" compares versions. Ordering
+// is overloaded for a version and a string, but CEL's equality cannot be.
+// A version is a semver() call or a version variable that no comprehension
+// shadows.
+func coerceVersionEquality(ast *cel.Ast) (*cel.Ast, error) {
+	parsed, err := cel.AstToParsedExpr(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	nextID := maxExprID(parsed.GetExpr())
+	var walk func(e *exprpb.Expr, shadowed map[string]bool)
+	walk = func(e *exprpb.Expr, shadowed map[string]bool) {
+		switch k := e.GetExprKind().(type) {
+		case *exprpb.Expr_SelectExpr:
+			walk(k.SelectExpr.GetOperand(), shadowed)
+		case *exprpb.Expr_CallExpr:
+			if k.CallExpr.GetTarget() != nil {
+				walk(k.CallExpr.GetTarget(), shadowed)
+			}
+			args := k.CallExpr.GetArgs()
+			for _, arg := range args {
+				walk(arg, shadowed)
+			}
+			if fn := k.CallExpr.GetFunction(); (fn == operators.Equals || fn == operators.NotEquals) && len(args) == 2 {
+				for _, i := range []int{0, 1} {
+					j := 1 - i
+					if isVersionExpr(args[i], shadowed) && isStringLiteral(args[j]) {
+						nextID++
+						args[j] = &exprpb.Expr{
+							Id: nextID,
+							ExprKind: &exprpb.Expr_CallExpr{CallExpr: &exprpb.Expr_Call{
+								Function: "semver",
+								Args:     []*exprpb.Expr{args[j]},
+							}},
+						}
+						break
+					}
+				}
+			}
+		case *exprpb.Expr_ListExpr:
+			for _, elem := range k.ListExpr.GetElements() {
+				walk(elem, shadowed)
+			}
+		case *exprpb.Expr_StructExpr:
+			for _, entry := range k.StructExpr.GetEntries() {
+				if key := entry.GetMapKey(); key != nil {
+					walk(key, shadowed)
+				}
+				walk(entry.GetValue(), shadowed)
+			}
+		case *exprpb.Expr_ComprehensionExpr:
+			c := k.ComprehensionExpr
+			walk(c.GetIterRange(), shadowed)
+			walk(c.GetAccuInit(), shadowed)
+			inner := map[string]bool{c.GetIterVar(): true, c.GetAccuVar(): true}
+			for name := range shadowed {
+				inner[name] = true
+			}
+			walk(c.GetLoopCondition(), inner)
+			walk(c.GetLoopStep(), inner)
+			walk(c.GetResult(), inner)
+		}
+	}
+	walk(parsed.GetExpr(), map[string]bool{})
+
+	return cel.ParsedExprToAstWithSource(parsed, ast.Source()), nil
+}
+
+// isVersionExpr reports whether e is a version: a version variable or a
+// semver() call
+func isVersionExpr(e *exprpb.Expr, shadowed map[string]bool) bool {
+	if name := e.GetIdentExpr().GetName(); name == "version" || name == "kubernetesVersion" {
+		return !shadowed[name]
+	}
+	call := e.GetCallExpr()
+	return call != nil && call.GetTarget() == nil && call.GetFunction() == "semver"
+}
+
+// isStringLiteral reports whether e is a string constant
+func isStringLiteral(e *exprpb.Expr) bool {
+	_, ok := e.GetConstExpr().GetConstantKind().(*exprpb.Constant_StringValue)
+	return ok
+}
+
+// maxExprID returns the largest expression ID in e, so new expressions can
+// be given unused IDs
+func maxExprID(e *exprpb.Expr) int64 {
+	if e == nil {
+		return 0
+	}
+	id := e.GetId()
+	children := []*exprpb.Expr{}
+	switch k := e.GetExprKind().(type) {
+	case *exprpb.Expr_SelectExpr:
+		children = append(children, k.SelectExpr.GetOperand())
+	case *exprpb.Expr_CallExpr:
+		children = append(append(children, k.CallExpr.GetTarget()), k.CallExpr.GetArgs()...)
+	case *exprpb.Expr_ListExpr:
+		children = append(children, k.ListExpr.GetElements()...)
+	case *exprpb.Expr_StructExpr:
+		for _, entry := range k.StructExpr.GetEntries() {
+			children = append(children, entry.GetMapKey(), entry.GetValue())
+		}
+	case *exprpb.Expr_ComprehensionExpr:
+		c := k.ComprehensionExpr
+		children = append(children, c.GetIterRange(), c.GetAccuInit(), c.GetLoopCondition(), c.GetLoopStep(), c.GetResult())
+	}
+	for _, child := range children {
+		if childID := maxExprID(child); childID > id {
+			id = childID
+		}
+	}
+	return id
+}
+
+// isConditionBuiltin reports whether name is a built-in condition variable
+func isConditionBuiltin(name string) bool {
+	for _, builtin := range conditionBuiltins {
+		if name == builtin {
+			return true
+		}
+	}
+	return false
+}
+
+func isLetter(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+// quoteLegacyValue quotes a legacy value unless it already is a string literal
+func quoteLegacyValue(v string) string {
+	if len(v) >= 2 && (v[0] == '\'' || v[0] == '"') && v[len(v)-1] == v[0] {
+		return v
+	}
+	return strconv.Quote(v)
+}
+
+// Version is a parsed semantic version. Minor and patch default to zero, so
+// "4.18" equals "4.18.0".
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string // e.g. "ec.2", empty for releases
+}
+
+// ParseVersion parses versions such as "4.18", "4.18.3", "v1.31.2" and
+// "4.19.0-ec.2". Build metadata after "+" is ignored.
+func ParseVersion(s string) (Version, error) {
+	raw := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, _ := strings.Cut(s, "-")
+
+	parts := strings.Split(s, ".")
+	if len(parts) < 1 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", raw)
+	}
+	nums := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", raw)
+		}
+		nums[i] = n
+	}
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2], Prerelease: pre}, nil
+}
+
+// Compare returns -1, 0 or 1 as v is older than, the same as or newer than o.
+// A prerelease sorts before its release.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d != 0 {
+			if d < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	default:
+		return comparePrerelease(v.Prerelease, o.Prerelease)
+	}
+}
+
+// comparePrerelease orders prerelease tags as semver 2.0 does: dot-separated
+// parts in order, numeric parts as numbers and before text parts, and a tag
+// that is a prefix of another first. "ec.2" sorts before "ec.10".
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		case as[i] != bs[i]:
+			if as[i] < bs[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// semverVal is the CEL value of a Version
+type semverVal struct {
+	Version
+}
+
+// toSemverVal converts a semver or version string CEL value to a semverVal
+func toSemverVal(v ref.Val) ref.Val {
+	switch val := v.(type) {
+	case semverVal:
+		return val
+	case types.String:
+		parsed, err := ParseVersion(string(val))
+		if err != nil {
+			return types.NewErr("%v", err)
+		}
+		return semverVal{parsed}
+	default:
+		return types.MaybeNoSuchOverloadErr(v)
+	}
+}
+
+func (v semverVal) ConvertToNative(typeDesc reflect.Type) (any, error) {
+	if reflect.TypeOf(v.Version).AssignableTo(typeDesc) {
+		return v.Version, nil
+	}
+	if typeDesc.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	return nil, fmt.Errorf("type conversion error from semver to %v", typeDesc)
+}
+
+func (v semverVal) ConvertToType(typeVal ref.Type) ref.Val {
+	switch typeVal {
+	case semverRuntimeType:
+		return v
+	case types.StringType:
+		return types.String(v.String())
+	case types.TypeType:
+		return semverRuntimeType
+	}
+	return types.NewErr("type conversion error from semver to %v", typeVal)
+}
+
+func (v semverVal) Equal(other ref.Val) ref.Val {
+	o, ok := other.(semverVal)
+	return types.Bool(ok && v.Version.Compare(o.Version) == 0)
+}
+
+func (v semverVal) Compare(other ref.Val) ref.Val {
+	o := toSemverVal(other)
+	if types.IsError(o) {
+		return o
+	}
+	return types.Int(v.Version.Compare(o.(semverVal).Version))
+}
+
+func (v semverVal) Type() ref.Type { return semverRuntimeType }
+
+func (v semverVal) Value() any { return v.Version }
@@ -0,0 +1,189 @@
+package postconfig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func testConditionContext() *TemplateContext {
+	cluster := &types.Cluster{
+		ID:               "c-1",
+		Name:             "demo",
+		ClusterType:      types.ClusterTypeOpenShift,
+		Platform:         types.PlatformAWS,
+		Region:           "us-east-1",
+		Profile:          "aws-standard",
+		Version:          "4.18.3",
+		SelectedAddonIDs: []string{"openshift-virtualization"},
+	}
+	ctx := BuildTemplateContext(cluster, "demo-abc12", map[string]string{"tier": "gold"})
+	ctx.WorkerCount = 3
+	ctx.Features = map[string]bool{"fipsMode": true}
+	return ctx
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	ctx := testConditionContext()
+
+	tests := []struct {
+		condition string
+		want      bool
+	}{
+		{"", true},
+		{"clusterType == 'openshift'", true},
+		{"platform != 'aws'", false},
+		{"platform == aws", true},
+		{"region contains 'us-'", true},
+		{"region contains eu-", false},
+		{"platform == 'aws' && version >= '4.18'", true},
+		{"version >= '4.19'", false},
+		{"version < semver('4.18.10')", true},
+		{"version == semver('4.18.3') && version != semver('4.18')", true},
+		{"version == '4.18.3' && version != '4.18'", true},
+		{"version == '4.19'", false},
+		{"'1.31' == kubernetesVersion", true},
+		{"['4.18.3'].exists(version, version == '4.18.3')", true},
+		{"version.minor() == 18 && version.major() == 4", true},
+		{"kubernetesVersion >= '1.31'", true},
+		{"workerCount >= 3 && features.fipsMode", true},
+		{"workerCount > 3 || !features.fipsMode", false},
+		{"'openshift-virtualization' in addons", true},
+		{"'odf' in addons", false},
+		{"tier == 'gold' && vars.tier == 'gold'", true},
+		{"cluster.name == 'demo' && cluster.infraID.startsWith('demo-')", true},
+		{"has(features.privateCluster) && features.privateCluster", false},
+	}
+	for _, tt := range tests {
+		got, err := EvaluateCondition(tt.condition, ctx)
+		if err != nil {
+			t.Errorf("EvaluateCondition(%q) returned error: %v", tt.condition, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("EvaluateCondition(%q) = %v, want %v", tt.condition, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateCondition_LegacyCustomVariables(t *testing.T) {
+	ctx := testConditionContext()
+	ctx.Variables["replicas"] = "3"
+	ctx.Variables["channel"] = "4.14"
+	ctx.Variables["version"] = "candidate"
+
+	tests := []struct {
+		condition string
+		want      bool
+	}{
+		// Unquoted numbers compare as strings with custom variables
+		{"replicas == 3", true},
+		{"replicas != 3", false},
+		{"channel == 4.14", true},
+		{"channel == 4.15", false},
+		// A custom variable named like a built-in keeps its value in the original syntax
+		{"version == candidate", true},
+		{"version == 'candidate'", true},
+		{"version != 4.18.3", true},
+		{"version contains cand", true},
+		// while CEL expressions use the built-in
+		{"version >= '4.18' && vars.version == 'candidate'", true},
+		// Built-ins that are not strings keep their type
+		{"workerCount == 3", true},
+	}
+	for _, tt := range tests {
+		got, err := EvaluateCondition(tt.condition, ctx)
+		if err != nil {
+			t.Errorf("EvaluateCondition(%q) returned error: %v", tt.condition, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("EvaluateCondition(%q) = %v, want %v", tt.condition, got, tt.want)
+		}
+	}
+
+	// Without a custom variable, version is the built-in semver
+	delete(ctx.Variables, "version")
+	if got, err := EvaluateCondition("version == candidate", ctx); err == nil && got {
+		t.Errorf("EvaluateCondition(%q) = true, want the built-in version", "version == candidate")
+	}
+	if got, err := EvaluateCondition("version >= '4.18'", ctx); err != nil || !got {
+		t.Errorf("EvaluateCondition(%q) = %v, %v, want true", "version >= '4.18'", got, err)
+	}
+}
+
+func TestEvaluateCondition_Errors(t *testing.T) {
+	ctx := testConditionContext()
+
+	for _, condition := range []string{
+		"platform ==",
+		"unknownVar == 'x'",
+		"workerCount",
+		"version >= 'not-a-version'",
+		"version == 'not-a-version'",
+	} {
+		if _, err := EvaluateCondition(condition, ctx); err == nil {
+			t.Errorf("EvaluateCondition(%q) expected an error", condition)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"4.18", "4.18.0", 0},
+		{"4.9", "4.18", -1},
+		{"4.18.3", "4.18.10", -1},
+		{"v1.31.2", "1.31", 1},
+		{"4.19.0-ec.2", "4.19", -1},
+		{"4.19.0-ec.2", "4.19.0-ec.3", -1},
+		{"4.19.0-ec.2", "4.19.0-ec.10", -1},
+		{"4.19.0-rc.10", "4.19.0-rc.9", 1},
+		{"4.19.0-ec.10", "4.19.0-rc.1", -1},
+		{"4.19.0-rc.1", "4.19.0-rc.1.1", -1},
+		{"4.19.0-1", "4.19.0-ec.1", -1},
+		{"4.19.0-ec.3", "4.19.0-ec.3", 0},
+	}
+	for _, tt := range tests {
+		a, err := ParseVersion(tt.a)
+		if err != nil {
+			t.Fatalf("ParseVersion(%q): %v", tt.a, err)
+		}
+		b, err := ParseVersion(tt.b)
+		if err != nil {
+			t.Fatalf("ParseVersion(%q): %v", tt.b, err)
+		}
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestKubernetesVersion(t *testing.T) {
+	if got, err := KubernetesVersion(types.ClusterTypeOpenShift, "4.18.3"); err != nil || got != "1.31" {
+		t.Errorf("expected 1.31 for OpenShift 4.18, got %q, %v", got, err)
+	}
+	if got, err := KubernetesVersion(types.ClusterTypeEKS, "1.30"); err != nil || got != "1.30" {
+		t.Errorf("expected EKS version to pass through, got %q, %v", got, err)
+	}
+	for _, version := range []string{"5.1", "latest"} {
+		if _, err := KubernetesVersion(types.ClusterTypeOpenShift, version); !errors.Is(err, ErrUnknownKubernetesVersion) {
+			t.Errorf("expected ErrUnknownKubernetesVersion for OpenShift %s, got %v", version, err)
+		}
+	}
+}
+
+func TestEvaluateCondition_UnknownKubernetesVersion(t *testing.T) {
+	ctx := BuildTemplateContext(&types.Cluster{ClusterType: types.ClusterTypeOpenShift, Version: "5.1"}, "", nil)
+
+	if got, err := EvaluateCondition("version >= '5.0'", ctx); err != nil || !got {
+		t.Errorf("EvaluateCondition(version) = %v, %v, want true", got, err)
+	}
+	if _, err := EvaluateCondition("kubernetesVersion >= '1.31'", ctx); err == nil || !strings.Contains(err.Error(), "unknown Kubernetes version") {
+		t.Errorf("EvaluateCondition(kubernetesVersion) error = %v, want unknown Kubernetes version", err)
+	}
+}
@@ -0,0 +1,156 @@
+package postconfig
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TaskStatus is the outcome of a task run by Execute
+type TaskStatus string
+
+const (
+	TaskSucceeded TaskStatus = "succeeded"
+	TaskFailed    TaskStatus = "failed"
+	TaskSkipped   TaskStatus = "skipped" // A dependency failed, or the run was cancelled first
+)
+
+// TaskResult records how one task ran
+type TaskResult struct {
+	Task      *TaskNode
+	Status    TaskStatus
+	Err       error     // Task error, or why it was skipped
+	BlockedBy string    // First dependency that failed or was skipped, for skipped tasks
+	StartedAt time.Time // Zero for skipped tasks
+	Duration  time.Duration
+}
+
+// TaskFunc runs a single task
+type TaskFunc func(ctx context.Context, task *TaskNode) error
+
+// Execute runs the DAG's tasks, starting each one as soon as all of its
+// dependencies have succeeded and running at most parallelism tasks at once
+// (values below 1 mean one at a time). When a task fails, every task that
+// depends on it, directly or transitively, is skipped while independent
+// branches run to completion. Once ctx is done no new tasks are started.
+//
+// Ready tasks start in ExecutionOrder, so a parallelism of 1 runs exactly the
+// topological order. Results are returned in ExecutionOrder.
+func (dag *ExecutionDAG) Execute(ctx context.Context, parallelism int, run TaskFunc) []TaskResult {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	order := make(map[string]int, len(dag.ExecutionOrder))
+	for i, name := range dag.ExecutionOrder {
+		order[name] = i
+	}
+	nodes := make(map[string]*TaskNode, len(dag.Nodes))
+	dependents := make(map[string][]string)
+	waiting := make(map[string]int, len(dag.Nodes)) // unfinished dependencies per task
+	for _, node := range dag.Nodes {
+		nodes[node.Name] = node
+		waiting[node.Name] = len(node.Dependencies)
+		for _, dep := range node.Dependencies {
+			dependents[dep] = append(dependents[dep], node.Name)
+		}
+	}
+
+	results := make(map[string]*TaskResult, len(dag.Nodes))
+	var ready []string
+	for _, name := range dag.ExecutionOrder {
+		if waiting[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	// skip marks a task and everything downstream of it as skipped
+	var skip func(name, blockedBy string, err error)
+	skip = func(name, blockedBy string, err error) {
+		if _, done := results[name]; done {
+			return
+		}
+		results[name] = &TaskResult{Task: nodes[name], Status: TaskSkipped, Err: err, BlockedBy: blockedBy}
+		for _, d := range dependents[name] {
+			skip(d, name, fmt.Errorf("dependency %s did not complete", name))
+		}
+	}
+
+	done := make(chan TaskResult)
+	running := 0
+	for len(ready) > 0 || running > 0 {
+		for len(ready) > 0 && running < parallelism && ctx.Err() == nil {
+			name := ready[0]
+			ready = ready[1:]
+			running++
+			go func(node *TaskNode) {
+				start := time.Now()
+				err := run(ctx, node)
+				status := TaskSucceeded
+				if err != nil {
+					status = TaskFailed
+				}
+				done <- TaskResult{Task: node, Status: status, Err: err, StartedAt: start, Duration: time.Since(start)}
+			}(nodes[name])
+		}
+		if running == 0 {
+			break // cancelled with tasks still waiting to start
+		}
+
+		res := <-done
+		running--
+		name := res.Task.Name
+		results[name] = &res
+
+		for _, d := range dependents[name] {
+			if res.Status != TaskSucceeded {
+				skip(d, name, fmt.Errorf("dependency %s failed", name))
+				continue
+			}
+			if _, skipped := results[d]; skipped {
+				continue
+			}
+			waiting[d]--
+			if waiting[d] == 0 {
+				ready = append(ready, d)
+				sort.Slice(ready, func(i, j int) bool { return order[ready[i]] < order[ready[j]] })
+			}
+		}
+	}
+
+	out := make([]TaskResult, 0, len(dag.ExecutionOrder))
+	for _, name := range dag.ExecutionOrder {
+		if res, ok := results[name]; ok {
+			out = append(out, *res)
+			continue
+		}
+		out = append(out, TaskResult{Task: nodes[name], Status: TaskSkipped, Err: fmt.Errorf("not started: %w", ctx.Err())})
+	}
+	return out
+}
+
+// ResultsError summarises the failed tasks in results, or returns nil when
+// none failed. Skipped tasks are not counted; they follow from a failure.
+func ResultsError(results []TaskResult) error {
+	var failed []string
+	for _, res := range results {
+		if res.Status == TaskFailed {
+			failed = append(failed, fmt.Sprintf("%s: %v", res.Task.Name, res.Err))
+		}
+	}
+	switch len(failed) {
+	case 0:
+		for _, res := range results {
+			if res.Status == TaskSkipped {
+				return fmt.Errorf("task %s skipped: %v", res.Task.Name, res.Err)
+			}
+		}
+		return nil
+	case 1:
+		return fmt.Errorf("task %s", failed[0])
+	default:
+		return fmt.Errorf("%d tasks failed: %s", len(failed), strings.Join(failed, "; "))
+	}
+}
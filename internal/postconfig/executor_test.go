@@ -0,0 +1,173 @@
+package postconfig
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func buildTestDAG(t *testing.T, scripts ...types.CustomScriptConfig) *ExecutionDAG {
+	t.Helper()
+	dag, err := BuildExecutionDAG(&types.CustomPostConfig{Scripts: scripts})
+	if err != nil {
+		t.Fatalf("Failed to build DAG: %v", err)
+	}
+	return dag
+}
+
+func resultsByName(results []TaskResult) map[string]TaskResult {
+	m := make(map[string]TaskResult, len(results))
+	for _, r := range results {
+		m[r.Task.Name] = r
+	}
+	return m
+}
+
+func TestExecute_RespectsParallelism(t *testing.T) {
+	var scripts []types.CustomScriptConfig
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		scripts = append(scripts, types.CustomScriptConfig{Name: name})
+	}
+	dag := buildTestDAG(t, scripts...)
+
+	var running, peak int32
+	results := dag.Execute(context.Background(), 2, func(ctx context.Context, task *TaskNode) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+
+	if peak != 2 {
+		t.Errorf("Expected peak concurrency 2, got %d", peak)
+	}
+	for _, r := range results {
+		if r.Status != TaskSucceeded {
+			t.Errorf("Expected %s to succeed, got %s", r.Task.Name, r.Status)
+		}
+	}
+	if err := ResultsError(results); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestExecute_WaitsForDependencies(t *testing.T) {
+	// a -> b -> c, with d independent
+	dag := buildTestDAG(t,
+		types.CustomScriptConfig{Name: "a"},
+		types.CustomScriptConfig{Name: "b", DependsOn: []string{"a"}},
+		types.CustomScriptConfig{Name: "c", DependsOn: []string{"b"}},
+		types.CustomScriptConfig{Name: "d"},
+	)
+
+	var mu sync.Mutex
+	finished := map[string]bool{}
+	dag.Execute(context.Background(), 4, func(ctx context.Context, task *TaskNode) error {
+		mu.Lock()
+		for _, dep := range task.Dependencies {
+			if !finished[dep] {
+				t.Errorf("Task %s started before dependency %s finished", task.Name, dep)
+			}
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		finished[task.Name] = true
+		mu.Unlock()
+		return nil
+	})
+
+	if len(finished) != 4 {
+		t.Errorf("Expected 4 tasks to run, got %d", len(finished))
+	}
+}
+
+func TestExecute_FailureSkipsDependents(t *testing.T) {
+	// a fails; b depends on a and c on b. d and e are independent of a.
+	dag := buildTestDAG(t,
+		types.CustomScriptConfig{Name: "a"},
+		types.CustomScriptConfig{Name: "b", DependsOn: []string{"a"}},
+		types.CustomScriptConfig{Name: "c", DependsOn: []string{"b", "d"}},
+		types.CustomScriptConfig{Name: "d"},
+		types.CustomScriptConfig{Name: "e", DependsOn: []string{"d"}},
+	)
+
+	boom := errors.New("boom")
+	var ran sync.Map
+	results := dag.Execute(context.Background(), 2, func(ctx context.Context, task *TaskNode) error {
+		ran.Store(task.Name, true)
+		if task.Name == "a" {
+			return boom
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+
+	byName := resultsByName(results)
+	expected := map[string]TaskStatus{
+		"a": TaskFailed,
+		"b": TaskSkipped,
+		"c": TaskSkipped,
+		"d": TaskSucceeded,
+		"e": TaskSucceeded,
+	}
+	for name, status := range expected {
+		if got := byName[name].Status; got != status {
+			t.Errorf("Expected %s to be %s, got %s", name, status, got)
+		}
+	}
+	for _, name := range []string{"b", "c"} {
+		if _, ok := ran.Load(name); ok {
+			t.Errorf("Expected %s not to run", name)
+		}
+	}
+	if byName["b"].BlockedBy != "a" {
+		t.Errorf("Expected b to be blocked by a, got %q", byName["b"].BlockedBy)
+	}
+	if !errors.Is(byName["a"].Err, boom) {
+		t.Errorf("Expected a's error to be recorded, got %v", byName["a"].Err)
+	}
+
+	err := ResultsError(results)
+	if err == nil {
+		t.Fatal("Expected an error for the failed task")
+	}
+	if err.Error() != "task a: boom" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestExecute_CancelledContextStopsNewTasks(t *testing.T) {
+	dag := buildTestDAG(t,
+		types.CustomScriptConfig{Name: "a"},
+		types.CustomScriptConfig{Name: "b", DependsOn: []string{"a"}},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := dag.Execute(ctx, 1, func(ctx context.Context, task *TaskNode) error {
+		cancel()
+		return nil
+	})
+
+	byName := resultsByName(results)
+	if byName["a"].Status != TaskSucceeded {
+		t.Errorf("Expected a to succeed, got %s", byName["a"].Status)
+	}
+	if byName["b"].Status != TaskSkipped {
+		t.Errorf("Expected b to be skipped after cancellation, got %s", byName["b"].Status)
+	}
+	if err := ResultsError(results); err == nil {
+		t.Error("Expected an error when tasks were not started")
+	}
+}
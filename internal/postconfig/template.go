@@ -2,10 +2,13 @@ package postconfig
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 	"text/template"
+	"text/template/parse"
 
+	"github.com/tsanders-rh/ocpctl/internal/profile"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
@@ -26,8 +29,28 @@ type TemplateContext struct {
 	// Namespace (for context)
 	Namespace string
 
+	// Versions, as given at cluster creation. KubernetesVersion is derived
+	// from the OpenShift version for OpenShift-based cluster types; when it
+	// cannot be, it is empty and conditions that read it fail with
+	// kubernetesVersionErr.
+	Version              string
+	KubernetesVersion    string
+	kubernetesVersionErr error
+
+	// Profile facts used by conditions
+	WorkerCount  int
+	Features     map[string]bool
+	Capabilities []string
+
+	// Selected addon IDs
+	Addons []string
+
 	// Custom variables from config
 	Variables map[string]string
+
+	// Secret resolves {{ secret "team/name" }} references. References fail
+	// to render when it is nil.
+	Secret func(name string) (string, error)
 }
 
 // BuildTemplateContext creates a template context from cluster information
@@ -40,8 +63,15 @@ func BuildTemplateContext(cluster *types.Cluster, infraID string, customVars map
 		Region:      cluster.Region,
 		Profile:     cluster.Profile,
 		InfraID:     infraID,
+		Version:     cluster.Version,
+		Addons:      make([]string, 0, len(cluster.SelectedAddonIDs)),
 		Variables:   make(map[string]string),
 	}
+	ctx.KubernetesVersion, ctx.kubernetesVersionErr = KubernetesVersion(cluster.ClusterType, cluster.Version)
+	for _, ref := range cluster.SelectedAddonIDs {
+		addonID, _ := types.ParseAddonRef(ref)
+		ctx.Addons = append(ctx.Addons, addonID)
+	}
 
 	// Add base domain if available
 	if cluster.BaseDomain != nil {
@@ -56,6 +86,28 @@ func BuildTemplateContext(cluster *types.Cluster, infraID string, customVars map
 	return ctx
 }
 
+// ApplyProfile fills in the profile facts used by conditions: worker count,
+// capabilities and feature flags. prof should already have the cluster's
+// compute overrides applied.
+func (ctx *TemplateContext) ApplyProfile(prof *profile.Profile) {
+	ctx.WorkerCount = 0
+	for _, shape := range prof.WorkerShapes() {
+		ctx.WorkerCount += shape.Replicas
+	}
+	if prof.Metadata != nil {
+		ctx.Capabilities = prof.Metadata.Capabilities
+	}
+	f := prof.Features
+	ctx.Features = map[string]bool{
+		"offHoursScaling":        f.OffHoursScaling,
+		"fipsMode":               f.FIPSMode,
+		"privateCluster":         f.PrivateCluster,
+		"oidcProvider":           f.OidcProvider,
+		"publicServiceEndpoint":  f.PublicServiceEndpoint,
+		"privateServiceEndpoint": f.PrivateServiceEndpoint,
+	}
+}
+
 // RenderTemplate renders a template string with the given context
 // Supports Go template syntax: {{.Variable}}
 func RenderTemplate(templateStr string, ctx *TemplateContext) (string, error) {
@@ -63,15 +115,8 @@ func RenderTemplate(templateStr string, ctx *TemplateContext) (string, error) {
 		return "", nil
 	}
 
-	// Define helper functions for templates
-	funcMap := template.FuncMap{
-		"contains": strings.Contains,
-		"eq":       func(a, b string) bool { return a == b },
-		"ne":       func(a, b string) bool { return a != b },
-	}
-
 	// Create template with function map
-	tmpl, err := template.New("config").Funcs(funcMap).Parse(templateStr)
+	tmpl, err := template.New("config").Funcs(templateFuncs(ctx)).Parse(templateStr)
 	if err != nil {
 		return "", fmt.Errorf("parse template: %w", err)
 	}
@@ -85,6 +130,116 @@ func RenderTemplate(templateStr string, ctx *TemplateContext) (string, error) {
 	return buf.String(), nil
 }
 
+// templateFuncs returns the helper functions available to templates
+func templateFuncs(ctx *TemplateContext) template.FuncMap {
+	return template.FuncMap{
+		"contains": strings.Contains,
+		"eq":       func(a, b string) bool { return a == b },
+		"ne":       func(a, b string) bool { return a != b },
+		"secret": func(name string) (string, error) {
+			if ctx == nil || ctx.Secret == nil {
+				return "", fmt.Errorf("secret %s: secret references are not available here", name)
+			}
+			return ctx.Secret(name)
+		},
+	}
+}
+
+// SecretRefs returns the secret names a template references with
+// {{ secret "team/name" }}. Names must be quoted literals so references can
+// be checked before the template is rendered.
+func SecretRefs(templateStr string) ([]string, error) {
+	if !strings.Contains(templateStr, "{{") {
+		return nil, nil
+	}
+
+	tmpl, err := template.New("config").Funcs(templateFuncs(nil)).Parse(templateStr)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+
+	var refs []string
+	seen := make(map[string]bool)
+	var walk func(node parse.Node) error
+	walk = func(node parse.Node) error {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return nil
+			}
+			for _, child := range n.Nodes {
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+		case *parse.ActionNode:
+			return walk(n.Pipe)
+		case *parse.TemplateNode:
+			return walk(n.Pipe)
+		case *parse.IfNode:
+			return walkBranch(&n.BranchNode, walk)
+		case *parse.RangeNode:
+			return walkBranch(&n.BranchNode, walk)
+		case *parse.WithNode:
+			return walkBranch(&n.BranchNode, walk)
+		case *parse.PipeNode:
+			if n == nil {
+				return nil
+			}
+			for _, cmd := range n.Cmds {
+				if err := walk(cmd); err != nil {
+					return err
+				}
+			}
+		case *parse.CommandNode:
+			if ident, ok := n.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "secret" {
+				name, ok := secretRefArg(n.Args)
+				if !ok {
+					return fmt.Errorf("secret takes a single quoted name, e.g. {{ secret \"team/name\" }}")
+				}
+				if !seen[name] {
+					seen[name] = true
+					refs = append(refs, name)
+				}
+				return nil
+			}
+			for _, arg := range n.Args {
+				if err := walk(arg); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk(tmpl.Tree.Root); err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// walkBranch walks the pipeline and both lists of an if, range or with action
+func walkBranch(n *parse.BranchNode, walk func(parse.Node) error) error {
+	for _, child := range []parse.Node{n.Pipe, n.List, n.ElseList} {
+		if err := walk(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// secretRefArg returns the quoted name a secret call is given
+func secretRefArg(args []parse.Node) (string, bool) {
+	if len(args) != 2 {
+		return "", false
+	}
+	str, ok := args[1].(*parse.StringNode)
+	if !ok {
+		return "", false
+	}
+	return str.Text, true
+}
+
 // RenderMapValues renders all string values in a map (for env vars, helm values, etc.)
 func RenderMapValues(m map[string]string, ctx *TemplateContext) (map[string]string, error) {
 	if m == nil {
@@ -103,59 +258,33 @@ func RenderMapValues(m map[string]string, ctx *TemplateContext) (map[string]stri
 	return result, nil
 }
 
-// EvaluateCondition evaluates a conditional expression
-// Supports simple comparisons: ==, !=, contains
-// Examples:
-//   - "clusterType == 'openshift'"
-//   - "platform == 'aws'"
-//   - "region contains 'us-'"
-func EvaluateCondition(condition string, ctx *TemplateContext) (bool, error) {
-	if condition == "" {
-		return true, nil // Empty condition always passes
-	}
-
-	// Parse condition (simple implementation for Phase 4)
-	// Format: "variable operator value"
-	parts := strings.Fields(condition)
-	if len(parts) != 3 {
-		return false, fmt.Errorf("invalid condition format: %s (expected 'variable operator value')", condition)
-	}
-
-	variable := strings.TrimSpace(parts[0])
-	operator := strings.TrimSpace(parts[1])
-	expectedValue := strings.Trim(strings.TrimSpace(parts[2]), "'\"")
-
-	// Get actual value from context
-	var actualValue string
-	switch variable {
-	case "clusterType":
-		actualValue = ctx.ClusterType
-	case "platform":
-		actualValue = ctx.Platform
-	case "region":
-		actualValue = ctx.Region
-	case "profile":
-		actualValue = ctx.Profile
-	case "baseDomain":
-		actualValue = ctx.BaseDomain
-	default:
-		// Check custom variables
-		if val, ok := ctx.Variables[variable]; ok {
-			actualValue = val
-		} else {
-			return false, fmt.Errorf("unknown variable: %s", variable)
-		}
-	}
+// ErrUnknownKubernetesVersion is returned for OpenShift versions whose
+// Kubernetes version is not known
+var ErrUnknownKubernetesVersion = errors.New("unknown Kubernetes version")
+
+// openShiftKubernetesMinorOffsets maps an OpenShift major version to the
+// difference between its minor versions and the Kubernetes minor versions
+// they ship (4.18 is 1.31). A new major has to be added here.
+var openShiftKubernetesMinorOffsets = map[int]int{
+	4: 13,
+}
 
-	// Evaluate operator
-	switch operator {
-	case "==":
-		return actualValue == expectedValue, nil
-	case "!=":
-		return actualValue != expectedValue, nil
-	case "contains":
-		return strings.Contains(actualValue, expectedValue), nil
+// KubernetesVersion returns the Kubernetes version of a cluster. Versions of
+// OpenShift-based cluster types are mapped to the Kubernetes minor they ship;
+// other cluster types are versioned by Kubernetes already.
+func KubernetesVersion(clusterType types.ClusterType, version string) (string, error) {
+	switch clusterType {
+	case types.ClusterTypeOpenShift, types.ClusterTypeROSA, types.ClusterTypeARO, types.ClusterTypeHCP:
+		v, err := ParseVersion(version)
+		if err != nil {
+			return "", fmt.Errorf("%w for OpenShift %q: %v", ErrUnknownKubernetesVersion, version, err)
+		}
+		offset, ok := openShiftKubernetesMinorOffsets[v.Major]
+		if !ok {
+			return "", fmt.Errorf("%w for OpenShift %s", ErrUnknownKubernetesVersion, version)
+		}
+		return fmt.Sprintf("1.%d", v.Minor+offset), nil
 	default:
-		return false, fmt.Errorf("unknown operator: %s", operator)
+		return version, nil
 	}
 }
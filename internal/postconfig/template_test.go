@@ -0,0 +1,70 @@
+package postconfig
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplateSecret(t *testing.T) {
+	ctx := &TemplateContext{
+		ClusterName: "dev",
+		Secret: func(name string) (string, error) {
+			if name == "platform/quay-token" {
+				return "s3cret", nil
+			}
+			return "", errors.New("secret not found")
+		},
+	}
+
+	got, err := RenderTemplate(`{{.ClusterName}}:{{ secret "platform/quay-token" }}`, ctx)
+	if err != nil {
+		t.Fatalf("RenderTemplate: %v", err)
+	}
+	if got != "dev:s3cret" {
+		t.Errorf("RenderTemplate = %q, want %q", got, "dev:s3cret")
+	}
+
+	if _, err := RenderTemplate(`{{ secret "platform/missing" }}`, ctx); err == nil || !strings.Contains(err.Error(), "secret not found") {
+		t.Errorf("expected lookup error, got %v", err)
+	}
+	if _, err := RenderTemplate(`{{ secret "platform/quay-token" }}`, &TemplateContext{}); err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("expected error without a secret resolver, got %v", err)
+	}
+}
+
+func TestSecretRefs(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{"plain text", "plain text", nil},
+		{"no references", "{{.ClusterName}}", nil},
+		{"branches", `{{ secret "a/one" }} {{ if eq .Platform "aws" }}{{ secret "a/two" }}{{ else }}{{ secret "a/one" }}{{ end }}`, []string{"a/one", "a/two"}},
+		{"nested pipeline", `{{ range .Addons }}{{ printf "%s" (secret "a/three") }}{{ end }}`, []string{"a/three"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SecretRefs(tt.template)
+			if err != nil {
+				t.Fatalf("SecretRefs: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SecretRefs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, tmpl := range []string{
+		`{{ secret .Variables.name }}`,
+		`{{ "a/one" | secret }}`,
+		`{{ secret "a/one" "a/two" }}`,
+		`{{ secret "a/one"`,
+	} {
+		if _, err := SecretRefs(tmpl); err == nil {
+			t.Errorf("SecretRefs(%q): expected error", tmpl)
+		}
+	}
+}
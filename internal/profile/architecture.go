@@ -0,0 +1,177 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ArchitectureProblem is one invalid architecture setting. Field is relative
+// to the architecture block: "controlPlane", "compute" or "" for the whole.
+type ArchitectureProblem struct {
+	Field   string
+	Message string
+}
+
+// EffectiveArchitecture merges a request-level architecture over the
+// profile's. Control plane and compute are each taken from the request when
+// set there and from the profile otherwise. Returns nil when neither sets
+// anything, which means an all-amd64 cluster.
+func EffectiveArchitecture(p *Profile, req *types.ClusterArchitecture) *types.ClusterArchitecture {
+	var base *types.ClusterArchitecture
+	if p != nil {
+		base = p.Compute.Architecture
+	}
+	if req.IsEmpty() {
+		if base.IsEmpty() {
+			return nil
+		}
+		return base
+	}
+	if base.IsEmpty() {
+		return req
+	}
+
+	merged := *base
+	if req.ControlPlane != "" {
+		merged.ControlPlane = req.ControlPlane
+	}
+	if req.Compute != "" {
+		merged.Compute = req.Compute
+	}
+	return &merged
+}
+
+// NodeArchitectures returns the distinct architectures of the nodes a cluster
+// runs workloads on. Clusters without a self-managed control plane only have
+// compute nodes, so the control plane setting does not count for them.
+func NodeArchitectures(p *Profile, arch *types.ClusterArchitecture) []types.Architecture {
+	if _, ok := p.ControlPlaneShape(); ok {
+		return arch.Architectures()
+	}
+	return []types.Architecture{arch.ComputeArch()}
+}
+
+// InstanceTypeArchitecture returns the CPU architecture of a cloud instance
+// type, going by each provider's naming scheme: AWS Graviton families carry a
+// "g" after the generation (m7g, c6gn, x2gd), Azure Ampere sizes a "p" after
+// the vCPU count (Standard_D4ps_v5), and GCP has the t2a, c4a and n4a
+// families. Anything else, including IBM Cloud profiles, is amd64.
+func InstanceTypeArchitecture(platform types.Platform, instanceType string) types.Architecture {
+	name := strings.ToLower(instanceType)
+	switch platform {
+	case types.PlatformAWS:
+		family, _, _ := strings.Cut(name, ".")
+		if family == "a1" || strings.Contains(familySuffix(family), "g") {
+			return types.ArchitectureARM64
+		}
+	case types.PlatformAzure:
+		size := strings.TrimPrefix(name, "standard_")
+		size, _, _ = strings.Cut(size, "_")
+		if strings.Contains(familySuffix(size), "p") {
+			return types.ArchitectureARM64
+		}
+	case types.PlatformGCP:
+		family, _, _ := strings.Cut(name, "-")
+		switch family {
+		case "t2a", "c4a", "n4a":
+			return types.ArchitectureARM64
+		}
+	}
+	return types.ArchitectureAMD64
+}
+
+// familySuffix returns the letters after the first run of digits in an
+// instance family, e.g. "gn" for "c6gn" and "ps" for "D4ps"
+func familySuffix(family string) string {
+	i := strings.IndexFunc(family, unicode.IsDigit)
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimLeftFunc(family[i:], unicode.IsDigit)
+}
+
+// armClusterTypes lists the cluster types and platforms that can run arm64
+// nodes. OpenShift IPI also supports heterogeneous clusters on these
+// platforms; the managed types only choose the architecture of node pools.
+var armClusterTypes = map[types.ClusterType][]types.Platform{
+	types.ClusterTypeOpenShift: {types.PlatformAWS, types.PlatformAzure, types.PlatformGCP},
+	types.ClusterTypeEKS:       {types.PlatformAWS},
+	types.ClusterTypeGKE:       {types.PlatformGCP},
+	types.ClusterTypeAKS:       {types.PlatformAzure},
+}
+
+// ValidateArchitecture checks the architecture settings against the profile
+// they will be installed with: the cluster type must support them, and every
+// control plane and worker instance type must be of the matching
+// architecture. A nil arch means all amd64, so profiles with arm64 instance
+// types and no architecture set are reported too.
+func ValidateArchitecture(arch *types.ClusterArchitecture, p *Profile) []ArchitectureProblem {
+	var problems []ArchitectureProblem
+	add := func(field, format string, args ...interface{}) {
+		problems = append(problems, ArchitectureProblem{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if arch != nil {
+		for _, f := range []struct {
+			field string
+			value types.Architecture
+		}{{"controlPlane", arch.ControlPlane}, {"compute", arch.Compute}} {
+			if f.value != "" && !types.ValidArchitecture(f.value) {
+				add(f.field, "unsupported architecture %q (must be amd64 or arm64)", f.value)
+			}
+		}
+		if len(problems) > 0 {
+			return problems
+		}
+	}
+
+	clusterType := p.EffectiveClusterType()
+	cp, selfManaged := p.ControlPlaneShape()
+	if arch != nil && arch.ControlPlane != "" && !selfManaged {
+		add("controlPlane", "%s clusters have no self-managed control plane; set compute only", clusterType)
+		return problems
+	}
+
+	if arch.ReleaseArchitecture() != types.ArchitectureAMD64 {
+		supported := false
+		for _, platform := range armClusterTypes[clusterType] {
+			if platform == p.Platform {
+				supported = true
+			}
+		}
+		if !supported {
+			add("", "arm64 nodes are not supported for %s clusters on %s", clusterType, p.Platform)
+			return problems
+		}
+	}
+
+	if selfManaged && cp.InstanceType != "" {
+		if got := InstanceTypeArchitecture(p.Platform, cp.InstanceType); got != arch.ControlPlaneArch() {
+			add("controlPlane", "control plane instance type %s is %s but the control plane architecture is %s",
+				cp.InstanceType, got, arch.ControlPlaneArch())
+		}
+	}
+
+	want := arch.ComputeArch()
+	checkWorker := func(pool, instanceType string) {
+		if instanceType == "" {
+			return
+		}
+		if got := InstanceTypeArchitecture(p.Platform, instanceType); got != want {
+			add("compute", "worker pool %s instance type %s is %s but the compute architecture is %s", pool, instanceType, got, want)
+		}
+	}
+	for _, s := range p.WorkerShapes() {
+		checkWorker(s.Name, s.InstanceType)
+	}
+	for _, pool := range p.SpotPools() {
+		for _, it := range pool.InstanceTypes[1:] {
+			checkWorker(pool.Name, it)
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,113 @@
+package profile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestInstanceTypeArchitecture(t *testing.T) {
+	tests := []struct {
+		platform     types.Platform
+		instanceType string
+		want         types.Architecture
+	}{
+		{types.PlatformAWS, "m7g.2xlarge", types.ArchitectureARM64},
+		{types.PlatformAWS, "c6gn.xlarge", types.ArchitectureARM64},
+		{types.PlatformAWS, "x2gd.large", types.ArchitectureARM64},
+		{types.PlatformAWS, "is4gen.xlarge", types.ArchitectureARM64},
+		{types.PlatformAWS, "a1.large", types.ArchitectureARM64},
+		{types.PlatformAWS, "m6i.xlarge", types.ArchitectureAMD64},
+		{types.PlatformAWS, "g4dn.xlarge", types.ArchitectureAMD64},
+		{types.PlatformAWS, "m5zn.metal", types.ArchitectureAMD64},
+		{types.PlatformAzure, "Standard_D4ps_v5", types.ArchitectureARM64},
+		{types.PlatformAzure, "Standard_E8pds_v5", types.ArchitectureARM64},
+		{types.PlatformAzure, "Standard_D8s_v5", types.ArchitectureAMD64},
+		{types.PlatformAzure, "Standard_NP10s", types.ArchitectureAMD64},
+		{types.PlatformGCP, "t2a-standard-4", types.ArchitectureARM64},
+		{types.PlatformGCP, "c4a-highmem-8", types.ArchitectureARM64},
+		{types.PlatformGCP, "n2-standard-4", types.ArchitectureAMD64},
+		{types.PlatformIBMCloud, "bx2-4x16", types.ArchitectureAMD64},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, profile.InstanceTypeArchitecture(tc.platform, tc.instanceType), tc.instanceType)
+	}
+}
+
+func TestEffectiveArchitecture(t *testing.T) {
+	prof := &profile.Profile{Compute: profile.ComputeConfig{
+		Architecture: &types.ClusterArchitecture{Compute: types.ArchitectureARM64},
+	}}
+
+	assert.Nil(t, profile.EffectiveArchitecture(&profile.Profile{}, nil))
+	assert.Equal(t, prof.Compute.Architecture, profile.EffectiveArchitecture(prof, nil))
+
+	merged := profile.EffectiveArchitecture(prof, &types.ClusterArchitecture{ControlPlane: types.ArchitectureARM64})
+	require.NotNil(t, merged)
+	assert.Equal(t, types.ArchitectureARM64, merged.ControlPlaneArch())
+	assert.Equal(t, types.ArchitectureARM64, merged.ComputeArch())
+	assert.Empty(t, prof.Compute.Architecture.ControlPlane, "profile is not modified")
+
+	var none *types.ClusterArchitecture
+	assert.Equal(t, types.ArchitectureAMD64, none.ReleaseArchitecture())
+	assert.Equal(t, types.ArchitectureMulti, merged.ReleaseArchitecture())
+	assert.True(t, prof.Compute.Architecture.Heterogeneous())
+}
+
+func TestValidateArchitecture(t *testing.T) {
+	openshift := func(cp, workers string) *profile.Profile {
+		return &profile.Profile{
+			Name:     "aws-arm",
+			Platform: types.PlatformAWS,
+			Compute: profile.ComputeConfig{
+				ControlPlane: &profile.ControlPlaneConfig{Replicas: 3, InstanceType: cp},
+				Workers:      &profile.WorkersConfig{Replicas: 3, InstanceType: workers},
+			},
+		}
+	}
+	arm := &types.ClusterArchitecture{ControlPlane: types.ArchitectureARM64, Compute: types.ArchitectureARM64}
+	hetero := &types.ClusterArchitecture{Compute: types.ArchitectureARM64}
+
+	tests := []struct {
+		name      string
+		arch      *types.ClusterArchitecture
+		prof      *profile.Profile
+		wantField string // "" with wantErr false means valid
+		wantErr   bool
+	}{
+		{"amd64 default", nil, openshift("m6i.xlarge", "m6i.2xlarge"), "", false},
+		{"arm64 only", arm, openshift("m7g.xlarge", "m7g.2xlarge"), "", false},
+		{"heterogeneous", hetero, openshift("m6i.xlarge", "m7g.2xlarge"), "", false},
+		{"arm64 workers without architecture", nil, openshift("m6i.xlarge", "m7g.2xlarge"), "compute", true},
+		{"amd64 control plane on arm64 cluster", arm, openshift("m6i.xlarge", "m7g.2xlarge"), "controlPlane", true},
+		{"multi is not a node architecture", &types.ClusterArchitecture{Compute: types.ArchitectureMulti}, openshift("m6i.xlarge", "m6i.2xlarge"), "compute", true},
+		{"ibmcloud has no arm64", hetero, &profile.Profile{Platform: types.PlatformIBMCloud, Compute: profile.ComputeConfig{
+			ControlPlane: &profile.ControlPlaneConfig{Replicas: 3, InstanceType: "bx2-4x16"},
+		}}, "", true},
+		{"spot alternatives must match", hetero, func() *profile.Profile {
+			p := openshift("m6i.xlarge", "m7g.2xlarge")
+			p.Compute.ManagedNodeGroups = nil
+			p.ClusterType = types.ClusterTypeEKS
+			p.Compute.ControlPlane = nil
+			p.Compute.Workers = nil
+			p.Compute.NodeGroups = []profile.NodeGroupConfig{{Name: "spot", InstanceType: "m7g.xlarge",
+				Spot: &profile.SpotConfig{InstanceTypes: []string{"m6g.xlarge", "m6i.xlarge"}}}}
+			return p
+		}(), "compute", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			problems := profile.ValidateArchitecture(tc.arch, tc.prof)
+			if !tc.wantErr {
+				assert.Empty(t, problems)
+				return
+			}
+			require.NotEmpty(t, problems)
+			assert.Equal(t, tc.wantField, problems[0].Field)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package profile
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// hcpInstanceTypePattern matches "<cores>x<memoryGiB>", the instance type
+// convention for KubeVirt NodePools
+var hcpInstanceTypePattern = regexp.MustCompile(`^(\d+)x(\d+)$`)
+
+// ParseHCPInstanceType splits an HCP instance type such as "4x16" into vCPU
+// cores and memory in GiB
+func ParseHCPInstanceType(instanceType string) (cores, memoryGiB int, err error) {
+	m := hcpInstanceTypePattern.FindStringSubmatch(instanceType)
+	if m == nil {
+		return 0, 0, fmt.Errorf("instance type %q must be <cores>x<memoryGiB>, e.g. 4x16", instanceType)
+	}
+	cores, _ = strconv.Atoi(m[1])
+	memoryGiB, _ = strconv.Atoi(m[2])
+	if cores == 0 || memoryGiB == 0 {
+		return 0, 0, fmt.Errorf("instance type %q must have at least one core and 1 GiB of memory", instanceType)
+	}
+	return cores, memoryGiB, nil
+}
@@ -0,0 +1,342 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// FragmentsDir is the subdirectory of the profiles directory holding
+// reusable fragments referenced by `include:`
+const FragmentsDir = "fragments"
+
+// Keys that belong to a single profile file and are never inherited
+var layerOnlyKeys = []string{"name", "abstract", "extends", "include"}
+
+// layer is one parsed YAML document taking part in a resolved profile
+type layer struct {
+	name string // Profile name, or "fragments/<name>" for fragments
+	data map[string]interface{}
+}
+
+// resolveLayers returns the layers that make up a profile, base first: the
+// resolved layers of its `extends` parent, then each `include` fragment in
+// order, then the profile itself. stack holds the profiles being resolved to
+// detect cycles.
+func (l *Loader) resolveLayers(name string, data []byte, stack []string) ([]layer, error) {
+	for _, s := range stack {
+		if s == name {
+			return nil, fmt.Errorf("profile inheritance cycle: %s -> %s", strings.Join(stack, " -> "), name)
+		}
+	}
+	stack = append(stack, name)
+
+	doc, err := parseLayer(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var layers []layer
+	if parent, ok := doc["extends"]; ok && parent != nil {
+		parentName, ok := parent.(string)
+		if !ok || parentName == "" {
+			return nil, fmt.Errorf("%s: extends must be a profile name", name)
+		}
+		if err := checkLayerName(parentName); err != nil {
+			return nil, fmt.Errorf("%s: extends: %w", name, err)
+		}
+		parentData, err := l.readFile(parentName + ".yaml")
+		if err != nil {
+			return nil, fmt.Errorf("%s extends %s: %w", name, parentName, err)
+		}
+		parentLayers, err := l.resolveLayers(parentName, parentData, stack)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, parentLayers...)
+	}
+
+	if include, ok := doc["include"]; ok && include != nil {
+		fragments, ok := include.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: include must be a list of fragment names", name)
+		}
+		for _, f := range fragments {
+			fragment, ok := f.(string)
+			if !ok || fragment == "" {
+				return nil, fmt.Errorf("%s: include must be a list of fragment names", name)
+			}
+			if err := checkLayerName(fragment); err != nil {
+				return nil, fmt.Errorf("%s: include: %w", name, err)
+			}
+			frag, err := l.loadFragment(fragment)
+			if err != nil {
+				return nil, fmt.Errorf("%s includes %s: %w", name, fragment, err)
+			}
+			layers = append(layers, frag)
+		}
+	}
+
+	return append(layers, layer{name: name, data: doc}), nil
+}
+
+// loadFragment reads a fragment. Fragments are partial profiles and cannot
+// themselves extend or include anything.
+func (l *Loader) loadFragment(name string) (layer, error) {
+	layerName := FragmentsDir + "/" + name
+	data, err := l.readFile(filepath.Join(FragmentsDir, name+".yaml"))
+	if err != nil {
+		return layer{}, err
+	}
+	doc, err := parseLayer(layerName, data)
+	if err != nil {
+		return layer{}, err
+	}
+	for _, key := range layerOnlyKeys {
+		if _, ok := doc[key]; ok {
+			return layer{}, fmt.Errorf("%s: fragments cannot set %s", layerName, key)
+		}
+	}
+	return layer{name: layerName, data: doc}, nil
+}
+
+// checkLayerName rejects extends and include values that are not plain file
+// names. Definitions can come from the admin API, so a name must not be able
+// to reach files outside the profiles directory.
+func checkLayerName(name string) error {
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid name %q: must not contain a path separator or '..'", name)
+	}
+	return nil
+}
+
+// readFile reads a file relative to the profiles directory, refusing paths
+// that resolve outside it
+func (l *Loader) readFile(rel string) ([]byte, error) {
+	dir := filepath.Clean(l.profilesDir)
+	filename := filepath.Join(dir, rel)
+	if inside, err := filepath.Rel(dir, filename); err != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("profile file %s is outside the profiles directory", rel)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read profile file %s: %w", filename, err)
+	}
+	return data, nil
+}
+
+func parseLayer(name string, data []byte) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse profile YAML %s: %w", name, err)
+	}
+	return doc, nil
+}
+
+// mergeLayers deep-merges layers in order and records, for every value, the
+// layer that last set it. Merge rules:
+//   - maps merge key by key, recursively
+//   - scalars and lists from a later layer replace the inherited value
+//   - `key+:` with a list appends to the inherited list instead of replacing it
+//   - `key: null` removes the inherited value
+//
+// Keys in layerOnlyKeys are taken from the last layer only.
+func mergeLayers(layers []layer) (map[string]interface{}, map[string]string, error) {
+	merged := map[string]interface{}{}
+	origins := map[string]string{}
+
+	for i, lyr := range layers {
+		data := lyr.data
+		if i < len(layers)-1 {
+			data = make(map[string]interface{}, len(lyr.data))
+			for k, v := range lyr.data {
+				data[k] = v
+			}
+			for _, key := range layerOnlyKeys {
+				delete(data, key)
+			}
+		}
+		if err := mergeMap(merged, data, "", lyr.name, origins); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", lyr.name, err)
+		}
+	}
+
+	return merged, origins, nil
+}
+
+func mergeMap(dst, src map[string]interface{}, prefix, layerName string, origins map[string]string) error {
+	// Sorted so that `key` is applied before `key+` in the same layer
+	keys := make([]string, 0, len(src))
+	for k := range src {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := src[key]
+
+		if strings.HasSuffix(key, "+") {
+			key = strings.TrimSuffix(key, "+")
+			path := joinPath(prefix, key)
+			items, ok := value.([]interface{})
+			if !ok {
+				return fmt.Errorf("%s+ must be a list", path)
+			}
+			existing, _ := dst[key].([]interface{})
+			if dst[key] != nil && existing == nil {
+				return fmt.Errorf("%s+ appends to %s, which is not a list", path, path)
+			}
+			dst[key] = append(append([]interface{}{}, existing...), items...)
+			setOrigin(origins, path, layerName)
+			continue
+		}
+
+		path := joinPath(prefix, key)
+		if value == nil {
+			delete(dst, key)
+			setOrigin(origins, path, layerName)
+			continue
+		}
+
+		if srcMap, ok := value.(map[string]interface{}); ok {
+			dstMap, ok := dst[key].(map[string]interface{})
+			if !ok {
+				dstMap = map[string]interface{}{}
+				clearOrigins(origins, path)
+			}
+			if err := mergeMap(dstMap, srcMap, path, layerName, origins); err != nil {
+				return err
+			}
+			dst[key] = dstMap
+			continue
+		}
+
+		dst[key] = value
+		setOrigin(origins, path, layerName)
+	}
+
+	return nil
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// setOrigin records the layer of path, replacing the origins of anything
+// inherited beneath it
+func setOrigin(origins map[string]string, path, layerName string) {
+	clearOrigins(origins, path)
+	origins[path] = layerName
+}
+
+func clearOrigins(origins map[string]string, path string) {
+	for p := range origins {
+		if p == path || strings.HasPrefix(p, path+".") {
+			delete(origins, p)
+		}
+	}
+}
+
+// Origin returns the layer (profile or fragments/<name>) that set the value
+// at a YAML path such as "compute.controlPlane.replicas", or "" if the path
+// is not set. For values inside lists it returns the layer that set the list.
+func (p *Profile) Origin(path string) string {
+	for path != "" {
+		if layerName, ok := p.origins[path]; ok {
+			return layerName
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return ""
+}
+
+// ValidationError is a failed profile check on one or more YAML paths
+type ValidationError struct {
+	Paths   []string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func fieldError(paths []string, format string, args ...interface{}) error {
+	return &ValidationError{Paths: paths, Message: fmt.Sprintf(format, args...)}
+}
+
+// withOrigins appends the layer each invalid value came from to a validation
+// error of a profile built from more than one layer
+func (p *Profile) withOrigins(err error) error {
+	if len(p.Layers) < 2 {
+		return err
+	}
+
+	var paths []string
+	var fieldErr *ValidationError
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &fieldErr) {
+		paths = fieldErr.Paths
+	} else if errors.As(err, &validationErrs) {
+		for _, fe := range validationErrs {
+			paths = append(paths, yamlPath(fe.StructNamespace()))
+		}
+	}
+
+	var sources []string
+	seen := map[string]bool{}
+	for _, path := range paths {
+		if layerName := p.Origin(path); layerName != "" && !seen[path] {
+			seen[path] = true
+			sources = append(sources, fmt.Sprintf("%s from %s", path, layerName))
+		}
+	}
+	if len(sources) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w (%s)", err, strings.Join(sources, ", "))
+}
+
+// yamlPath converts a validator namespace such as
+// "Profile.Compute.ControlPlane.Replicas" to its YAML path
+// "compute.controlPlane.replicas". List indexes and map keys are dropped.
+func yamlPath(namespace string) string {
+	parts := strings.Split(namespace, ".")
+	t := reflect.TypeOf(Profile{})
+	var path []string
+	for _, part := range parts[1:] {
+		if i := strings.Index(part, "["); i >= 0 {
+			part = part[:i]
+		}
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			break
+		}
+		field, ok := t.FieldByName(part)
+		if !ok {
+			break
+		}
+		key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if key == "" {
+			key = strings.ToLower(part[:1]) + part[1:]
+		}
+		path = append(path, key)
+		t = field.Type
+	}
+	return strings.Join(path, ".")
+}
@@ -0,0 +1,249 @@
+package profile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+)
+
+const baseProfileYAML = `name: aws-base
+displayName: AWS Base
+description: Base profile
+platform: aws
+clusterType: openshift
+abstract: true
+enabled: true
+openshiftVersions:
+  allowlist: ["4.20", "4.21"]
+  default: "4.21"
+regions:
+  allowlist: [us-east-1, us-west-2]
+  default: us-east-1
+baseDomains:
+  allowlist: [labs.example.com]
+  default: labs.example.com
+compute:
+  controlPlane:
+    replicas: 3
+    instanceType: m6i.xlarge
+    schedulable: true
+  workers:
+    replicas: 0
+    minReplicas: 0
+    maxReplicas: 3
+    instanceType: m6i.2xlarge
+lifecycle:
+  maxTTLHours: 72
+  defaultTTLHours: 24
+  allowCustomTTL: true
+tags:
+  required:
+    Environment: production
+  defaults:
+    ManagedBy: cluster-control-plane
+    Purpose: testing
+  allowUserTags: true
+platformConfig:
+  aws:
+    instanceMetadataService: required
+    rootVolume:
+      type: gp3
+      size: 120
+`
+
+func writeProfiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestLoader_Extends(t *testing.T) {
+	dir := writeProfiles(t, map[string]string{
+		"aws-base.yaml": baseProfileYAML,
+		"aws-child.yaml": `name: aws-child
+displayName: AWS Child
+extends: aws-base
+include: [short-ttl]
+openshiftVersions:
+  allowlist: ["4.22.0-ec.5"]
+  default: "4.22.0-ec.5"
+regions:
+  allowlist+: [eu-west-1]
+compute:
+  workers:
+    maxReplicas: 6
+tags:
+  defaults:
+    Purpose: null
+    Track: prerelease
+`,
+		"fragments/short-ttl.yaml": `lifecycle:
+  maxTTLHours: 24
+  defaultTTLHours: 8
+`,
+	})
+	loader := profile.NewLoader(dir)
+
+	prof, err := loader.Load("aws-child")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"aws-base", "fragments/short-ttl", "aws-child"}, prof.Layers)
+	assert.Equal(t, "AWS Child", prof.DisplayName)
+	assert.Equal(t, "Base profile", prof.Description)
+	assert.False(t, prof.Abstract, "abstract is not inherited")
+
+	// Lists replace the inherited list, `key+` appends to it
+	assert.Equal(t, []string{"4.22.0-ec.5"}, prof.OpenshiftVersions.Allowlist)
+	assert.Equal(t, []string{"us-east-1", "us-west-2", "eu-west-1"}, prof.Regions.Allowlist)
+
+	// Maps merge key by key, null removes an inherited key
+	assert.Equal(t, 6, prof.Compute.Workers.MaxReplicas)
+	assert.Equal(t, "m6i.2xlarge", prof.Compute.Workers.InstanceType)
+	assert.Equal(t, map[string]string{"ManagedBy": "cluster-control-plane", "Track": "prerelease"}, prof.Tags.Defaults)
+	assert.Equal(t, 24, prof.Lifecycle.MaxTTLHours)
+	assert.True(t, prof.Lifecycle.AllowCustomTTL)
+	assert.Equal(t, 120, prof.PlatformConfig.AWS.RootVolume.Size)
+
+	assert.Equal(t, "aws-child", prof.Origin("compute.workers.maxReplicas"))
+	assert.Equal(t, "aws-base", prof.Origin("compute.workers.instanceType"))
+	assert.Equal(t, "fragments/short-ttl", prof.Origin("lifecycle.defaultTTLHours"))
+	assert.Equal(t, "aws-child", prof.Origin("regions.allowlist"))
+	assert.Equal(t, "", prof.Origin("networking"))
+
+	t.Run("abstract profiles are skipped by LoadAll", func(t *testing.T) {
+		profiles, err := loader.LoadAll()
+		require.NoError(t, err)
+		require.Len(t, profiles, 1)
+		assert.Equal(t, "aws-child", profiles[0].Name)
+
+		_, err = loader.Load("aws-base")
+		assert.ErrorContains(t, err, "abstract")
+	})
+}
+
+func TestLoader_ExtendsErrors(t *testing.T) {
+	t.Run("cycle", func(t *testing.T) {
+		dir := writeProfiles(t, map[string]string{
+			"aws-a.yaml": "name: aws-a\nextends: aws-b\n",
+			"aws-b.yaml": "name: aws-b\nextends: aws-a\n",
+		})
+		_, err := profile.NewLoader(dir).Load("aws-a")
+		assert.ErrorContains(t, err, "cycle: aws-a -> aws-b -> aws-a")
+	})
+
+	t.Run("missing base", func(t *testing.T) {
+		dir := writeProfiles(t, map[string]string{
+			"aws-a.yaml": "name: aws-a\nextends: aws-missing\n",
+		})
+		_, err := profile.NewLoader(dir).Load("aws-a")
+		assert.ErrorContains(t, err, "aws-a extends aws-missing")
+	})
+
+	t.Run("fragment cannot extend", func(t *testing.T) {
+		dir := writeProfiles(t, map[string]string{
+			"aws-base.yaml":      baseProfileYAML,
+			"aws-a.yaml":         "name: aws-a\nextends: aws-base\ninclude: [bad]\n",
+			"fragments/bad.yaml": "extends: aws-base\n",
+		})
+		_, err := profile.NewLoader(dir).Load("aws-a")
+		assert.ErrorContains(t, err, "fragments/bad: fragments cannot set extends")
+	})
+
+	t.Run("path in names", func(t *testing.T) {
+		dir := writeProfiles(t, map[string]string{
+			"profiles/aws-a.yaml": "name: aws-a\nextends: ../secret\n",
+			"profiles/aws-b.yaml": "name: aws-b\nextends: sub/aws-base\n",
+			"profiles/aws-c.yaml": "name: aws-c\ninclude: [../../secret]\n",
+			"profiles/aws-d.yaml": "name: aws-d\ninclude: ['..']\n",
+			"secret.yaml":         "name: secret\n",
+		})
+		loader := profile.NewLoader(filepath.Join(dir, "profiles"))
+
+		for name, want := range map[string]string{
+			"aws-a": `aws-a: extends: invalid name "../secret"`,
+			"aws-b": `aws-b: extends: invalid name "sub/aws-base"`,
+			"aws-c": `aws-c: include: invalid name "../../secret"`,
+			"aws-d": `aws-d: include: invalid name ".."`,
+		} {
+			_, err := loader.Load(name)
+			assert.ErrorContains(t, err, want, name)
+		}
+
+		// Profile names are checked against the profiles directory too
+		_, err := loader.ReadDefinition("../secret")
+		assert.ErrorContains(t, err, "outside the profiles directory")
+		_, err = loader.Load("../secret")
+		assert.ErrorContains(t, err, "outside the profiles directory")
+	})
+
+	t.Run("append to non-list", func(t *testing.T) {
+		dir := writeProfiles(t, map[string]string{
+			"aws-base.yaml": baseProfileYAML,
+			"aws-a.yaml":    "name: aws-a\nextends: aws-base\nregions:\n  default+: [us-west-2]\n",
+		})
+		_, err := profile.NewLoader(dir).Load("aws-a")
+		assert.ErrorContains(t, err, "regions.default+ appends to regions.default, which is not a list")
+	})
+}
+
+func TestLoader_ValidationReportsLayer(t *testing.T) {
+	dir := writeProfiles(t, map[string]string{
+		"aws-base.yaml": baseProfileYAML,
+		"aws-child.yaml": `name: aws-child
+extends: aws-base
+openshiftVersions:
+  default: "4.19"
+`,
+		"aws-even.yaml": `name: aws-even
+extends: aws-base
+include: [even-control-plane]
+`,
+		"fragments/even-control-plane.yaml": "compute:\n  controlPlane:\n    replicas: 2\n",
+		"gcp-child.yaml":                    "name: gcp-child\nextends: aws-base\n",
+	})
+	loader := profile.NewLoader(dir)
+
+	_, err := loader.Load("aws-child")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "default OpenShift version 4.19 not in allowlist")
+	assert.Contains(t, err.Error(), "openshiftVersions.default from aws-child")
+	assert.Contains(t, err.Error(), "openshiftVersions.allowlist from aws-base")
+
+	var validationErr *profile.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = loader.Load("aws-even")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "compute.controlPlane.replicas from fragments/even-control-plane")
+
+	_, err = loader.Load("gcp-child")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "name from gcp-child")
+}
+
+func TestLoader_DefinitionsUseInheritance(t *testing.T) {
+	loader := profile.NewLoader("definitions")
+
+	prof, err := loader.Load("aws-sno-prerelease")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aws-sno-ga", "fragments/prerelease-track", "aws-sno-prerelease"}, prof.Layers)
+	assert.Equal(t, "prerelease", prof.Track)
+	assert.Equal(t, 24, prof.Lifecycle.MaxTTLHours)
+	assert.Equal(t, "test", prof.Tags.Required["Environment"])
+	assert.Equal(t, 1, prof.Compute.ControlPlane.Replicas)
+	require.NotNil(t, prof.PlatformConfig.AWS)
+
+	prof, err = loader.Load("aws-sno-test")
+	require.NoError(t, err)
+	assert.Empty(t, prof.Track)
+	assert.NotContains(t, prof.Tags.Defaults, "Track")
+}
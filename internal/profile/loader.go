@@ -3,10 +3,10 @@ package profile
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 	"gopkg.in/yaml.v3"
 )
 
@@ -32,25 +32,70 @@ func NewLoader(profilesDir string) *Loader {
 	}
 }
 
-// Load loads a single profile by name
+// Load loads a single profile by name, resolving its base profile and fragments
 func (l *Loader) Load(name string) (*Profile, error) {
-	filename := filepath.Join(l.profilesDir, name+".yaml")
+	data, err := l.readFile(name + ".yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	return l.LoadData(name, data)
+}
 
-	data, err := os.ReadFile(filename)
+// ReadDefinition returns the YAML file of a profile as written, before
+// inheritance is resolved
+func (l *Loader) ReadDefinition(name string) ([]byte, error) {
+	return l.readFile(name + ".yaml")
+}
+
+// LoadData loads a profile from YAML content instead of its file. Base
+// profiles and fragments it references are still read from the profiles
+// directory, so a modified profile can be checked before it is written.
+func (l *Loader) LoadData(name string, data []byte) (*Profile, error) {
+	profile, err := l.resolve(name, data)
 	if err != nil {
-		return nil, fmt.Errorf("read profile file %s: %w", filename, err)
+		return nil, err
 	}
 
-	var profile Profile
-	if err := yaml.Unmarshal(data, &profile); err != nil {
-		return nil, fmt.Errorf("parse profile YAML %s: %w", filename, err)
+	if profile.Abstract {
+		return nil, fmt.Errorf("profile %s is abstract and can only be extended", name)
 	}
 
 	// Validate profile
-	if err := l.Validate(&profile); err != nil {
-		return nil, fmt.Errorf("validate profile %s: %w", name, err)
+	if err := l.Validate(profile); err != nil {
+		return nil, fmt.Errorf("validate profile %s: %w", name, profile.withOrigins(err))
+	}
+
+	return profile, nil
+}
+
+// resolve merges a profile with its base profiles and fragments
+func (l *Loader) resolve(name string, data []byte) (*Profile, error) {
+	layers, err := l.resolveLayers(name, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, origins, err := mergeLayers(layers)
+	if err != nil {
+		return nil, fmt.Errorf("merge profile %s: %w", name, err)
 	}
 
+	resolved, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("merge profile %s: %w", name, err)
+	}
+
+	var profile Profile
+	if err := yaml.Unmarshal(resolved, &profile); err != nil {
+		return nil, fmt.Errorf("parse profile YAML %s: %w", name, err)
+	}
+
+	for _, lyr := range layers {
+		profile.Layers = append(profile.Layers, lyr.name)
+	}
+	profile.origins = origins
+
 	return &profile, nil
 }
 
@@ -79,11 +124,25 @@ func (l *Loader) LoadAll() ([]*Profile, error) {
 		name := strings.TrimSuffix(entry.Name(), ".yaml")
 		name = strings.TrimSuffix(name, ".yml")
 
-		profile, err := l.Load(name)
+		data, err := l.readFile(entry.Name())
 		if err != nil {
 			return nil, fmt.Errorf("load profile %s: %w", name, err)
 		}
 
+		profile, err := l.resolve(name, data)
+		if err != nil {
+			return nil, fmt.Errorf("load profile %s: %w", name, err)
+		}
+
+		// Abstract profiles are bases for others, not profiles of their own
+		if profile.Abstract {
+			continue
+		}
+
+		if err := l.Validate(profile); err != nil {
+			return nil, fmt.Errorf("load profile %s: validate profile %s: %w", name, name, profile.withOrigins(err))
+		}
+
 		profiles = append(profiles, profile)
 	}
 
@@ -105,24 +164,28 @@ func (l *Loader) Validate(profile *Profile) error {
 	// 1. Default version must be in allowlist
 	if profile.OpenshiftVersions != nil {
 		if !contains(profile.OpenshiftVersions.Allowlist, profile.OpenshiftVersions.Default) {
-			return fmt.Errorf("default OpenShift version %s not in allowlist", profile.OpenshiftVersions.Default)
+			return fieldError([]string{"openshiftVersions.default", "openshiftVersions.allowlist"},
+				"default OpenShift version %s not in allowlist", profile.OpenshiftVersions.Default)
 		}
 	}
 	if profile.KubernetesVersions != nil {
 		if !contains(profile.KubernetesVersions.Allowlist, profile.KubernetesVersions.Default) {
-			return fmt.Errorf("default Kubernetes version %s not in allowlist", profile.KubernetesVersions.Default)
+			return fieldError([]string{"kubernetesVersions.default", "kubernetesVersions.allowlist"},
+				"default Kubernetes version %s not in allowlist", profile.KubernetesVersions.Default)
 		}
 	}
 
 	// 2. Default region must be in allowlist
 	if !contains(profile.Regions.Allowlist, profile.Regions.Default) {
-		return fmt.Errorf("default region %s not in allowlist", profile.Regions.Default)
+		return fieldError([]string{"regions.default", "regions.allowlist"},
+			"default region %s not in allowlist", profile.Regions.Default)
 	}
 
 	// 3. Default base domain must be in allowlist (only for OpenShift)
 	if profile.BaseDomains != nil {
 		if !contains(profile.BaseDomains.Allowlist, profile.BaseDomains.Default) {
-			return fmt.Errorf("default base domain %s not in allowlist", profile.BaseDomains.Default)
+			return fieldError([]string{"baseDomains.default", "baseDomains.allowlist"},
+				"default base domain %s not in allowlist", profile.BaseDomains.Default)
 		}
 	}
 
@@ -131,13 +194,13 @@ func (l *Loader) Validate(profile *Profile) error {
 		// OpenShift on AWS requires platformConfig.aws
 		if profile.ClusterType == "" || profile.ClusterType == "openshift" {
 			if profile.PlatformConfig.AWS == nil {
-				return fmt.Errorf("OpenShift on AWS requires platformConfig.aws")
+				return fieldError([]string{"platform", "clusterType"}, "OpenShift on AWS requires platformConfig.aws")
 			}
 		}
 		// EKS requires platformConfig.eks
 		if profile.ClusterType == "eks" {
 			if profile.PlatformConfig.EKS == nil {
-				return fmt.Errorf("EKS cluster requires platformConfig.eks")
+				return fieldError([]string{"platform", "clusterType"}, "EKS cluster requires platformConfig.eks")
 			}
 		}
 	}
@@ -145,19 +208,49 @@ func (l *Loader) Validate(profile *Profile) error {
 		// OpenShift on IBMCloud requires platformConfig.ibmcloud
 		if profile.ClusterType == "" || profile.ClusterType == "openshift" {
 			if profile.PlatformConfig.IBMCloud == nil {
-				return fmt.Errorf("OpenShift on IBMCloud requires platformConfig.ibmcloud")
+				return fieldError([]string{"platform", "clusterType"}, "OpenShift on IBMCloud requires platformConfig.ibmcloud")
 			}
 		}
 		// IKS requires platformConfig.ibmcloud (same config structure)
 		if profile.ClusterType == "iks" {
 			if profile.PlatformConfig.IBMCloud == nil {
-				return fmt.Errorf("IKS cluster requires platformConfig.ibmcloud")
+				return fieldError([]string{"platform", "clusterType"}, "IKS cluster requires platformConfig.ibmcloud")
+			}
+		}
+	}
+
+	// HCP runs on a management cluster regardless of platform and requires
+	// platformConfig.hcp with at least one valid node pool
+	if profile.ClusterType == "hcp" {
+		if profile.PlatformConfig.HCP == nil || len(profile.PlatformConfig.HCP.NodePools) == 0 {
+			return fieldError([]string{"platformConfig.hcp"}, "HCP cluster requires platformConfig.hcp with at least one node pool")
+		}
+		for i, np := range profile.PlatformConfig.HCP.NodePools {
+			if np.Name == "" {
+				return fieldError([]string{fmt.Sprintf("platformConfig.hcp.nodePools[%d].name", i)}, "HCP node pool %d requires a name", i)
+			}
+			if _, _, err := ParseHCPInstanceType(np.InstanceType); err != nil {
+				return fieldError([]string{fmt.Sprintf("platformConfig.hcp.nodePools[%d].instanceType", i)}, "HCP node pool %s: %v", np.Name, err)
 			}
 		}
 	}
 
+	// kind is the only cluster type on the local platform; it needs no cloud
+	// config, just platformConfig.kind
+	if profile.Platform == types.PlatformLocal || profile.ClusterType == types.ClusterTypeKind {
+		if profile.Platform != types.PlatformLocal || profile.ClusterType != types.ClusterTypeKind {
+			return fieldError([]string{"platform", "clusterType"}, "kind clusters run only on the local platform")
+		}
+		if profile.PlatformConfig.Kind == nil {
+			return fieldError([]string{"platformConfig.kind"}, "kind cluster requires platformConfig.kind")
+		}
+		if profile.PlatformConfig.Kind.Workers < 0 {
+			return fieldError([]string{"platformConfig.kind.workers"}, "kind workers must be >= 0")
+		}
+	}
+
 	// 5. Profile name must match platform or cluster type prefix
-	// For managed Kubernetes services, use cluster type prefix (eks-, iks-, gke-, aks-, rosa-, aro-)
+	// For managed Kubernetes services, use cluster type prefix (eks-, iks-, gke-, aks-, rosa-, aro-, hcp-, kind-)
 	// For vanilla OpenShift IPI, use platform prefix (aws-, gcp-, azure-, ibmcloud-)
 	var expectedPrefix string
 	managedClusterTypes := map[string]bool{
@@ -167,6 +260,8 @@ func (l *Loader) Validate(profile *Profile) error {
 		"aks":  true,
 		"rosa": true,
 		"aro":  true,
+		"hcp":  true,
+		"kind": true,
 	}
 	if managedClusterTypes[string(profile.ClusterType)] {
 		expectedPrefix = string(profile.ClusterType) + "-"
@@ -174,27 +269,73 @@ func (l *Loader) Validate(profile *Profile) error {
 		expectedPrefix = string(profile.Platform) + "-"
 	}
 	if !strings.HasPrefix(profile.Name, expectedPrefix) {
-		return fmt.Errorf("profile name %s must start with %s", profile.Name, expectedPrefix)
+		return fieldError([]string{"name"}, "profile name %s must start with %s", profile.Name, expectedPrefix)
 	}
 
 	// 6. Worker max replicas must be >= min replicas (only for profiles with workers)
 	if profile.Compute.Workers != nil {
 		if profile.Compute.Workers.MaxReplicas < profile.Compute.Workers.MinReplicas {
-			return fmt.Errorf("worker maxReplicas (%d) must be >= minReplicas (%d)",
+			return fieldError([]string{"compute.workers.maxReplicas", "compute.workers.minReplicas"}, "worker maxReplicas (%d) must be >= minReplicas (%d)",
 				profile.Compute.Workers.MaxReplicas, profile.Compute.Workers.MinReplicas)
 		}
 
 		// 7. Worker replicas must be within bounds
 		if profile.Compute.Workers.Replicas < profile.Compute.Workers.MinReplicas {
-			return fmt.Errorf("worker replicas (%d) must be >= minReplicas (%d)",
+			return fieldError([]string{"compute.workers.replicas", "compute.workers.minReplicas"}, "worker replicas (%d) must be >= minReplicas (%d)",
 				profile.Compute.Workers.Replicas, profile.Compute.Workers.MinReplicas)
 		}
 		if profile.Compute.Workers.Replicas > profile.Compute.Workers.MaxReplicas {
-			return fmt.Errorf("worker replicas (%d) must be <= maxReplicas (%d)",
+			return fieldError([]string{"compute.workers.replicas", "compute.workers.maxReplicas"}, "worker replicas (%d) must be <= maxReplicas (%d)",
 				profile.Compute.Workers.Replicas, profile.Compute.Workers.MaxReplicas)
 		}
 	}
 
+	// 8. Overridable parameters must be something the platform can apply
+	if o := profile.Overridable; o != nil {
+		if o.Workers == nil && len(o.InstanceTypes) == 0 && o.RootVolumeGB == nil && o.MachinePools == nil {
+			return fieldError([]string{"overridable"}, "overridable must declare at least one of workers, instanceTypes, rootVolumeGB or machinePools")
+		}
+		for i, it := range o.InstanceTypes {
+			if strings.TrimSpace(it) == "" {
+				return fieldError([]string{"overridable.instanceTypes"}, "overridable.instanceTypes[%d] is empty", i)
+			}
+		}
+		if o.RootVolumeGB != nil && (profile.ClusterType == types.ClusterTypeIKS || (profile.Platform == types.PlatformIBMCloud && profile.EffectiveClusterType() == types.ClusterTypeOpenShift)) {
+			return fieldError([]string{"overridable.rootVolumeGB"}, "root volume size cannot be overridden on %s", profile.Platform)
+		}
+		if o.MachinePools != nil && profile.ClusterType == types.ClusterTypeIKS {
+			return fieldError([]string{"overridable.machinePools"}, "extra machine pools are not supported for IKS profiles")
+		}
+		if profile.ClusterType == types.ClusterTypeKind && (len(o.InstanceTypes) > 0 || o.RootVolumeGB != nil || o.MachinePools != nil) {
+			return fieldError([]string{"overridable"}, "kind profiles can only override the worker count")
+		}
+	}
+
+	// 9. Restricted-network settings must be valid for the cluster type
+	if profile.Networking != nil {
+		for _, problem := range ValidateRestrictedNetwork(profile.Networking.Restricted, profile) {
+			path := "networking.restricted"
+			if problem.Field != "" {
+				path += "." + problem.Field
+			}
+			return fieldError([]string{path}, "networking.restricted: %s", problem.Message)
+		}
+	}
+
+	// 10. Spot capacity must be requested where the cluster type supports it
+	if path, msg := validateSpot(profile); msg != "" {
+		return fieldError([]string{path}, "%s", msg)
+	}
+
+	// 11. Instance types must match the control plane and compute architectures
+	for _, problem := range ValidateArchitecture(profile.Compute.Architecture, profile) {
+		path := "compute.architecture"
+		if problem.Field != "" {
+			path += "." + problem.Field
+		}
+		return fieldError([]string{path}, "%s", problem.Message)
+	}
+
 	return nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 func TestLoader_LoadProfile(t *testing.T) {
@@ -60,9 +61,9 @@ func TestLoader_LoadAll(t *testing.T) {
 	for _, prof := range profiles {
 		assert.NotEmpty(t, prof.Name)
 		assert.NotEmpty(t, prof.Platform)
-		// OpenShift-based profiles (openshift, rosa, aro) have OpenshiftVersions
+		// OpenShift-based profiles (openshift, rosa, aro, hcp) have OpenshiftVersions
 		// Kubernetes profiles (eks, gke, iks, aks) have KubernetesVersions
-		if prof.ClusterType == "openshift" || prof.ClusterType == "rosa" || prof.ClusterType == "aro" {
+		if prof.ClusterType == "openshift" || prof.ClusterType == "rosa" || prof.ClusterType == "aro" || prof.ClusterType == "hcp" {
 			require.NotNil(t, prof.OpenshiftVersions, "OpenShift-based profile %s should have OpenshiftVersions", prof.Name)
 			assert.NotEmpty(t, prof.OpenshiftVersions.Allowlist)
 		} else {
@@ -75,6 +76,103 @@ func TestLoader_LoadAll(t *testing.T) {
 func TestLoader_Validate(t *testing.T) {
 	loader := profile.NewLoader("definitions")
 
+	t.Run("validates HCP node pool instance types", func(t *testing.T) {
+		prof, err := loader.Load("hcp-standard")
+		require.NoError(t, err)
+		require.NoError(t, loader.Validate(prof))
+
+		prof.PlatformConfig.HCP.NodePools[0].InstanceType = "m6i.xlarge"
+		err = loader.Validate(prof)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "<cores>x<memoryGiB>")
+
+		prof.PlatformConfig.HCP = nil
+		assert.Error(t, loader.Validate(prof))
+	})
+
+	t.Run("validates kind profiles", func(t *testing.T) {
+		prof, err := loader.Load("kind-local")
+		require.NoError(t, err)
+		require.NoError(t, loader.Validate(prof))
+
+		prof.Overridable.InstanceTypes = []string{"4x16"}
+		err = loader.Validate(prof)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "worker count")
+		prof.Overridable.InstanceTypes = nil
+
+		prof.Platform = "aws"
+		err = loader.Validate(prof)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "local platform")
+
+		prof.Platform = "local"
+		prof.PlatformConfig.Kind = nil
+		assert.Error(t, loader.Validate(prof))
+	})
+
+	t.Run("validates spot placement", func(t *testing.T) {
+		prof, err := loader.Load("aws-standard-ga")
+		require.NoError(t, err)
+		prof.Compute.Workers.Spot = &profile.SpotConfig{MaxPrice: "0.25"}
+		require.NoError(t, loader.Validate(prof))
+
+		prof.Compute.Workers.Spot.MaxPrice = "cheap"
+		err = loader.Validate(prof)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maxPrice")
+
+		prof.Compute.Workers.Spot = &profile.SpotConfig{InstanceTypes: []string{"m6a.2xlarge"}}
+		err = loader.Validate(prof)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "EKS node groups")
+
+		aks, err := loader.Load("aks-standard")
+		require.NoError(t, err)
+		aks.PlatformConfig.AKS.NodePools[0].Spot = &profile.SpotConfig{}
+		err = loader.Validate(aks)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "system pool")
+
+		gke, err := loader.Load("gke-standard")
+		require.NoError(t, err)
+		gke.PlatformConfig.GKE.NodePools[0].Spot = &profile.SpotConfig{}
+		require.NoError(t, loader.Validate(gke))
+		gke.PlatformConfig.GKE.NodePools[0].Spot.MaxPrice = "0.1"
+		assert.Error(t, loader.Validate(gke))
+
+		rosa, err := loader.Load("rosa-standard")
+		require.NoError(t, err)
+		rosa.Compute.Workers = &profile.WorkersConfig{Replicas: 2, MaxReplicas: 2, Spot: &profile.SpotConfig{}}
+		err = loader.Validate(rosa)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only supported for OpenShift on AWS")
+	})
+
+	t.Run("validates architecture", func(t *testing.T) {
+		prof, err := loader.Load("aws-standard-ga")
+		require.NoError(t, err)
+		prof.Compute.Workers.InstanceType = "m7g.2xlarge"
+		err = loader.Validate(prof)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "compute architecture is amd64")
+
+		prof.Compute.Architecture = &types.ClusterArchitecture{Compute: types.ArchitectureARM64}
+		require.NoError(t, loader.Validate(prof), "amd64 control plane with arm64 workers")
+
+		prof.Compute.Architecture.ControlPlane = types.ArchitectureARM64
+		err = loader.Validate(prof)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "control plane instance type")
+
+		eks, err := loader.Load("eks-standard")
+		require.NoError(t, err)
+		eks.Compute.Architecture = &types.ClusterArchitecture{ControlPlane: types.ArchitectureARM64}
+		err = loader.Validate(eks)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no self-managed control plane")
+	})
+
 	t.Run("validates control plane replicas are odd", func(t *testing.T) {
 		prof := &profile.Profile{
 			Name:     "test-invalid",
@@ -0,0 +1,497 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// NodeShape describes one homogeneous group of worker nodes
+type NodeShape struct {
+	Name         string
+	Replicas     int
+	InstanceType string
+	RootVolumeGB int         // 0 when the profile leaves disk sizing to the platform default
+	Spot         *SpotConfig // nil for on-demand nodes
+}
+
+// Clone returns a deep copy of the profile, including layer origins
+func (p *Profile) Clone() (*Profile, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var clone Profile
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	if p.origins != nil {
+		clone.origins = make(map[string]string, len(p.origins))
+		for k, v := range p.origins {
+			clone.origins[k] = v
+		}
+	}
+	return &clone, nil
+}
+
+// EffectiveClusterType returns the profile's cluster type, treating an unset
+// type as self-managed OpenShift
+func (p *Profile) EffectiveClusterType() types.ClusterType {
+	if p.ClusterType == "" {
+		return types.ClusterTypeOpenShift
+	}
+	return p.ClusterType
+}
+
+// ApplyOverrides returns a copy of the profile with the compute overrides
+// applied to every place the platform reads its worker shape from. The
+// original profile is never modified. Bounds are enforced by the policy
+// engine; this only rejects overrides the platform has nowhere to apply.
+//
+// Extra machine pools become node groups/pools for EKS, GKE, AKS and HCP. For
+// OpenShift, ARO and ROSA they are recorded in AdditionalMachinePools and
+// created by the worker once the cluster is up.
+func ApplyOverrides(p *Profile, o *types.ComputeOverrides) (*Profile, error) {
+	if o.IsEmpty() {
+		return p, nil
+	}
+
+	out, err := p.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("clone profile: %w", err)
+	}
+
+	if o.OnDemand {
+		out.clearSpot()
+		shape := *o
+		shape.OnDemand = false
+		if shape.IsEmpty() {
+			return out, nil
+		}
+	}
+
+	switch out.EffectiveClusterType() {
+	case types.ClusterTypeOpenShift:
+		err = out.applyOpenShiftOverrides(o)
+	case types.ClusterTypeROSA:
+		err = out.applyROSAOverrides(o)
+	case types.ClusterTypeEKS:
+		err = out.applyEKSOverrides(o)
+	case types.ClusterTypeIKS:
+		err = out.applyIKSOverrides(o)
+	case types.ClusterTypeGKE:
+		err = out.applyGKEOverrides(o)
+	case types.ClusterTypeARO:
+		err = out.applyAROOverrides(o)
+	case types.ClusterTypeAKS:
+		err = out.applyAKSOverrides(o)
+	case types.ClusterTypeHCP:
+		err = out.applyHCPOverrides(o)
+	case types.ClusterTypeKind:
+		err = out.applyKindOverrides(o)
+	default:
+		err = fmt.Errorf("compute overrides are not supported for cluster type %s", out.ClusterType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// applyWorkers updates the generic workers block, widening min/max so the
+// loader's replica bounds still hold
+func (p *Profile) applyWorkers(o *types.ComputeOverrides) {
+	w := p.Compute.Workers
+	if w == nil {
+		return
+	}
+	if o.WorkerReplicas != nil {
+		w.Replicas = *o.WorkerReplicas
+		if w.MinReplicas > w.Replicas {
+			w.MinReplicas = w.Replicas
+		}
+		if w.MaxReplicas < w.Replicas {
+			w.MaxReplicas = w.Replicas
+		}
+	}
+	if o.WorkerInstanceType != "" {
+		w.InstanceType = o.WorkerInstanceType
+	}
+	if o.RootVolumeGB != nil {
+		w.RootVolumeGB = *o.RootVolumeGB
+	}
+}
+
+func (p *Profile) applyOpenShiftOverrides(o *types.ComputeOverrides) error {
+	if o.RootVolumeGB != nil && p.Platform == types.PlatformIBMCloud {
+		return fmt.Errorf("root volume size cannot be overridden on platform %s", p.Platform)
+	}
+
+	if p.Compute.Workers == nil {
+		p.Compute.Workers = &WorkersConfig{}
+	}
+	p.applyWorkers(o)
+
+	// Keep the platform machine blocks in step with the worker type so
+	// pre-flight checks look at the instance type actually being installed.
+	if o.WorkerInstanceType != "" {
+		if p.PlatformConfig.GCP != nil && p.PlatformConfig.GCP.Compute != nil {
+			p.PlatformConfig.GCP.Compute.MachineType = o.WorkerInstanceType
+		}
+		if p.PlatformConfig.Azure != nil && p.PlatformConfig.Azure.Compute != nil {
+			p.PlatformConfig.Azure.Compute.VMSize = o.WorkerInstanceType
+		}
+	}
+
+	p.AdditionalMachinePools = append(p.AdditionalMachinePools, o.MachinePools...)
+	return nil
+}
+
+func (p *Profile) applyROSAOverrides(o *types.ComputeOverrides) error {
+	if p.Compute.Workers == nil {
+		p.Compute.Workers = &WorkersConfig{}
+	}
+	p.applyWorkers(o)
+
+	if rosa := p.PlatformConfig.ROSA; rosa != nil {
+		if o.WorkerReplicas != nil {
+			rosa.ComputeNodes = *o.WorkerReplicas
+		}
+		if o.WorkerInstanceType != "" {
+			rosa.MachineType = o.WorkerInstanceType
+		}
+	}
+
+	p.AdditionalMachinePools = append(p.AdditionalMachinePools, o.MachinePools...)
+	return nil
+}
+
+func (p *Profile) applyEKSOverrides(o *types.ComputeOverrides) error {
+	// The first node group is the default worker group; managed groups take
+	// precedence because that is what current EKS profiles use.
+	groups := &p.Compute.ManagedNodeGroups
+	if len(*groups) == 0 {
+		groups = &p.Compute.NodeGroups
+	}
+	if len(*groups) == 0 {
+		return fmt.Errorf("profile has no EKS node group to override")
+	}
+
+	ng := &(*groups)[0]
+	if o.WorkerReplicas != nil {
+		ng.DesiredCapacity = *o.WorkerReplicas
+		if ng.MinSize > ng.DesiredCapacity {
+			ng.MinSize = ng.DesiredCapacity
+		}
+		if ng.MaxSize < ng.DesiredCapacity {
+			ng.MaxSize = ng.DesiredCapacity
+		}
+	}
+	if o.WorkerInstanceType != "" {
+		ng.InstanceType = o.WorkerInstanceType
+	}
+	if o.RootVolumeGB != nil {
+		ng.VolumeSize = *o.RootVolumeGB
+	}
+
+	for _, pool := range o.MachinePools {
+		*groups = append(*groups, NodeGroupConfig{
+			Name:            pool.Name,
+			InstanceType:    pool.InstanceType,
+			DesiredCapacity: pool.Replicas,
+			MinSize:         pool.Replicas,
+			MaxSize:         pool.Replicas,
+			VolumeSize:      ng.VolumeSize,
+			VolumeType:      ng.VolumeType,
+			AMIFamily:       ng.AMIFamily,
+		})
+	}
+
+	p.applyWorkers(o)
+	return nil
+}
+
+func (p *Profile) applyIKSOverrides(o *types.ComputeOverrides) error {
+	if o.RootVolumeGB != nil {
+		return fmt.Errorf("root volume size cannot be overridden for IKS clusters")
+	}
+	if len(o.MachinePools) > 0 {
+		return fmt.Errorf("extra machine pools are not supported for IKS clusters")
+	}
+
+	if p.Compute.Workers == nil {
+		p.Compute.Workers = &WorkersConfig{}
+	}
+	if o.WorkerReplicas != nil {
+		p.Compute.Workers.Count = *o.WorkerReplicas
+	}
+	if o.WorkerInstanceType != "" {
+		p.Compute.Workers.MachineType = o.WorkerInstanceType
+	}
+	return nil
+}
+
+func (p *Profile) applyGKEOverrides(o *types.ComputeOverrides) error {
+	if p.PlatformConfig.GKE == nil || len(p.PlatformConfig.GKE.NodePools) == 0 {
+		// Single default node pool built from the workers block
+		if o.RootVolumeGB != nil {
+			return fmt.Errorf("root volume size can only be overridden for GKE profiles with nodePools")
+		}
+		if len(o.MachinePools) > 0 {
+			return fmt.Errorf("extra machine pools can only be added to GKE profiles with nodePools")
+		}
+		if p.Compute.Workers == nil {
+			return fmt.Errorf("profile has no GKE node pool to override")
+		}
+		p.applyWorkers(o)
+		if o.WorkerInstanceType != "" {
+			p.Compute.Workers.MachineType = o.WorkerInstanceType
+		}
+		return nil
+	}
+
+	pools := &p.PlatformConfig.GKE.NodePools
+	np := &(*pools)[0]
+	if o.WorkerReplicas != nil {
+		np.NodeCount = *o.WorkerReplicas
+		if np.MinNodeCount > np.NodeCount {
+			np.MinNodeCount = np.NodeCount
+		}
+		if np.MaxNodeCount != 0 && np.MaxNodeCount < np.NodeCount {
+			np.MaxNodeCount = np.NodeCount
+		}
+	}
+	if o.WorkerInstanceType != "" {
+		np.MachineType = o.WorkerInstanceType
+	}
+	if o.RootVolumeGB != nil {
+		np.DiskSizeGB = *o.RootVolumeGB
+	}
+
+	for _, pool := range o.MachinePools {
+		*pools = append(*pools, GKENodePoolConfig{
+			Name:        pool.Name,
+			MachineType: pool.InstanceType,
+			DiskSizeGB:  np.DiskSizeGB,
+			DiskType:    np.DiskType,
+			NodeCount:   pool.Replicas,
+		})
+	}
+
+	p.applyWorkers(o)
+	return nil
+}
+
+func (p *Profile) applyAROOverrides(o *types.ComputeOverrides) error {
+	if p.PlatformConfig.ARO == nil {
+		return fmt.Errorf("profile missing ARO configuration")
+	}
+	aro := p.PlatformConfig.ARO
+	if o.WorkerReplicas != nil {
+		aro.WorkerCount = *o.WorkerReplicas
+	}
+	if o.WorkerInstanceType != "" {
+		aro.WorkerVMSize = o.WorkerInstanceType
+	}
+	if o.RootVolumeGB != nil {
+		aro.WorkerDiskSizeGB = *o.RootVolumeGB
+	}
+
+	p.AdditionalMachinePools = append(p.AdditionalMachinePools, o.MachinePools...)
+	p.applyWorkers(o)
+	return nil
+}
+
+func (p *Profile) applyAKSOverrides(o *types.ComputeOverrides) error {
+	if p.PlatformConfig.AKS == nil || len(p.PlatformConfig.AKS.NodePools) == 0 {
+		return fmt.Errorf("profile has no AKS node pool to override")
+	}
+
+	pools := &p.PlatformConfig.AKS.NodePools
+	np := &(*pools)[0]
+	if o.WorkerReplicas != nil {
+		np.Count = *o.WorkerReplicas
+		if np.MinCount > np.Count {
+			np.MinCount = np.Count
+		}
+		if np.MaxCount != 0 && np.MaxCount < np.Count {
+			np.MaxCount = np.Count
+		}
+	}
+	if o.WorkerInstanceType != "" {
+		np.VMSize = o.WorkerInstanceType
+	}
+	if o.RootVolumeGB != nil {
+		np.OSDiskSizeGB = *o.RootVolumeGB
+	}
+
+	for _, pool := range o.MachinePools {
+		*pools = append(*pools, AKSNodePoolConfig{
+			Name:         pool.Name,
+			VMSize:       pool.InstanceType,
+			Count:        pool.Replicas,
+			OSDiskSizeGB: np.OSDiskSizeGB,
+		})
+	}
+
+	p.applyWorkers(o)
+	return nil
+}
+
+func (p *Profile) applyHCPOverrides(o *types.ComputeOverrides) error {
+	if p.PlatformConfig.HCP == nil || len(p.PlatformConfig.HCP.NodePools) == 0 {
+		return fmt.Errorf("profile has no HCP node pool to override")
+	}
+
+	pools := &p.PlatformConfig.HCP.NodePools
+	np := &(*pools)[0]
+	if o.WorkerReplicas != nil {
+		np.Replicas = *o.WorkerReplicas
+	}
+	if o.WorkerInstanceType != "" {
+		if _, _, err := ParseHCPInstanceType(o.WorkerInstanceType); err != nil {
+			return err
+		}
+		np.InstanceType = o.WorkerInstanceType
+	}
+	if o.RootVolumeGB != nil {
+		np.RootVolumeGB = *o.RootVolumeGB
+	}
+
+	for _, pool := range o.MachinePools {
+		if _, _, err := ParseHCPInstanceType(pool.InstanceType); err != nil {
+			return fmt.Errorf("machine pool %s: %w", pool.Name, err)
+		}
+		*pools = append(*pools, HCPNodePoolConfig{
+			Name:         pool.Name,
+			Replicas:     pool.Replicas,
+			InstanceType: pool.InstanceType,
+			RootVolumeGB: np.RootVolumeGB,
+		})
+	}
+
+	p.applyWorkers(o)
+	return nil
+}
+
+func (p *Profile) applyKindOverrides(o *types.ComputeOverrides) error {
+	if p.PlatformConfig.Kind == nil {
+		return fmt.Errorf("profile has no kind config to override")
+	}
+	if o.WorkerInstanceType != "" || o.RootVolumeGB != nil || len(o.MachinePools) > 0 {
+		return fmt.Errorf("kind clusters only support overriding the worker count")
+	}
+	if o.WorkerReplicas != nil {
+		p.PlatformConfig.Kind.Workers = *o.WorkerReplicas
+	}
+
+	p.applyWorkers(o)
+	return nil
+}
+
+// WorkerShapes returns the profile's worker node groups, default group first,
+// including any extra pools added by ApplyOverrides
+func (p *Profile) WorkerShapes() []NodeShape {
+	var shapes []NodeShape
+	workers := func() NodeShape {
+		s := NodeShape{Name: "worker"}
+		if w := p.Compute.Workers; w != nil {
+			s.Replicas = w.Replicas
+			s.InstanceType = w.InstanceType
+			s.RootVolumeGB = w.RootVolumeGB
+			s.Spot = w.Spot
+		}
+		return s
+	}
+
+	switch p.EffectiveClusterType() {
+	case types.ClusterTypeOpenShift:
+		s := workers()
+		switch {
+		case s.RootVolumeGB > 0:
+		case p.PlatformConfig.AWS != nil && p.PlatformConfig.AWS.RootVolume != nil:
+			s.RootVolumeGB = p.PlatformConfig.AWS.RootVolume.Size
+		case p.PlatformConfig.GCP != nil && p.PlatformConfig.GCP.Compute != nil:
+			s.RootVolumeGB = p.PlatformConfig.GCP.Compute.DiskSizeGB
+		case p.PlatformConfig.Azure != nil && p.PlatformConfig.Azure.Compute != nil:
+			s.RootVolumeGB = p.PlatformConfig.Azure.Compute.OSDiskSizeGB
+		}
+		shapes = append(shapes, s)
+	case types.ClusterTypeROSA:
+		s := workers()
+		if rosa := p.PlatformConfig.ROSA; rosa != nil {
+			if rosa.ComputeNodes > 0 && s.Replicas == 0 {
+				s.Replicas = rosa.ComputeNodes
+			}
+			if rosa.MachineType != "" && s.InstanceType == "" {
+				s.InstanceType = rosa.MachineType
+			}
+		}
+		shapes = append(shapes, s)
+	case types.ClusterTypeEKS:
+		for _, ng := range append(append([]NodeGroupConfig{}, p.Compute.ManagedNodeGroups...), p.Compute.NodeGroups...) {
+			shapes = append(shapes, NodeShape{Name: ng.Name, Replicas: ng.DesiredCapacity, InstanceType: ng.InstanceType, RootVolumeGB: ng.VolumeSize, Spot: ng.Spot})
+		}
+	case types.ClusterTypeIKS:
+		if w := p.Compute.Workers; w != nil {
+			shapes = append(shapes, NodeShape{Name: "default", Replicas: w.Count, InstanceType: w.MachineType})
+		}
+	case types.ClusterTypeGKE:
+		if p.PlatformConfig.GKE != nil && len(p.PlatformConfig.GKE.NodePools) > 0 {
+			for _, np := range p.PlatformConfig.GKE.NodePools {
+				shapes = append(shapes, NodeShape{Name: np.Name, Replicas: np.NodeCount, InstanceType: np.MachineType, RootVolumeGB: np.DiskSizeGB, Spot: np.Spot})
+			}
+		} else if w := p.Compute.Workers; w != nil {
+			shapes = append(shapes, NodeShape{Name: "default-pool", Replicas: w.Replicas, InstanceType: w.MachineType})
+		}
+	case types.ClusterTypeARO:
+		if aro := p.PlatformConfig.ARO; aro != nil {
+			shapes = append(shapes, NodeShape{Name: "worker", Replicas: aro.WorkerCount, InstanceType: aro.WorkerVMSize, RootVolumeGB: aro.WorkerDiskSizeGB})
+		}
+	case types.ClusterTypeAKS:
+		if p.PlatformConfig.AKS != nil {
+			for _, np := range p.PlatformConfig.AKS.NodePools {
+				shapes = append(shapes, NodeShape{Name: np.Name, Replicas: np.Count, InstanceType: np.VMSize, RootVolumeGB: np.OSDiskSizeGB, Spot: np.Spot})
+			}
+		}
+	case types.ClusterTypeHCP:
+		if p.PlatformConfig.HCP != nil {
+			for _, np := range p.PlatformConfig.HCP.NodePools {
+				shapes = append(shapes, NodeShape{Name: np.Name, Replicas: np.Replicas, InstanceType: np.InstanceType, RootVolumeGB: np.RootVolumeGB})
+			}
+		}
+	case types.ClusterTypeKind:
+		if p.PlatformConfig.Kind != nil {
+			shapes = append(shapes, NodeShape{Name: "worker", Replicas: p.PlatformConfig.Kind.Workers})
+		}
+	}
+
+	for _, pool := range p.AdditionalMachinePools {
+		rootGB := 0
+		if len(shapes) > 0 {
+			rootGB = shapes[0].RootVolumeGB
+		}
+		shapes = append(shapes, NodeShape{Name: pool.Name, Replicas: pool.Replicas, InstanceType: pool.InstanceType, RootVolumeGB: rootGB})
+	}
+
+	return shapes
+}
+
+// ControlPlaneShape returns the self-managed control plane nodes, or false
+// when the control plane is provided by the cloud (ROSA, EKS, GKE, AKS, IKS)
+// or hosted on a management cluster (HCP)
+func (p *Profile) ControlPlaneShape() (NodeShape, bool) {
+	switch p.EffectiveClusterType() {
+	case types.ClusterTypeOpenShift:
+		if cp := p.Compute.ControlPlane; cp != nil {
+			return NodeShape{Name: "master", Replicas: cp.Replicas, InstanceType: cp.InstanceType}, true
+		}
+	case types.ClusterTypeARO:
+		if aro := p.PlatformConfig.ARO; aro != nil {
+			return NodeShape{Name: "master", Replicas: 3, InstanceType: aro.MasterVMSize}, true
+		}
+	}
+	return NodeShape{}, false
+}
@@ -0,0 +1,188 @@
+package profile_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/policy"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestApplyOverrides(t *testing.T) {
+	loader := profile.NewLoader("definitions")
+
+	t.Run("openshift workers and post-install pools", func(t *testing.T) {
+		base, err := loader.Load("aws-standard-ga")
+		require.NoError(t, err)
+
+		shaped, err := profile.ApplyOverrides(base, &types.ComputeOverrides{
+			WorkerReplicas:     intPtr(6),
+			WorkerInstanceType: "m6i.4xlarge",
+			RootVolumeGB:       intPtr(300),
+			MachinePools:       []types.MachinePoolOverride{{Name: "infra", InstanceType: "m6i.xlarge", Replicas: 2}},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 6, shaped.Compute.Workers.Replicas)
+		assert.Equal(t, "m6i.4xlarge", shaped.Compute.Workers.InstanceType)
+		assert.Equal(t, 300, shaped.Compute.Workers.RootVolumeGB)
+		assert.Len(t, shaped.AdditionalMachinePools, 1)
+
+		// The registry's copy is untouched
+		assert.Equal(t, 3, base.Compute.Workers.Replicas)
+		assert.Equal(t, "m6i.2xlarge", base.Compute.Workers.InstanceType)
+		assert.Empty(t, base.AdditionalMachinePools)
+
+		shapes := shaped.WorkerShapes()
+		require.Len(t, shapes, 2)
+		assert.Equal(t, profile.NodeShape{Name: "worker", Replicas: 6, InstanceType: "m6i.4xlarge", RootVolumeGB: 300}, shapes[0])
+		assert.Equal(t, "infra", shapes[1].Name)
+	})
+
+	t.Run("nil overrides return the profile", func(t *testing.T) {
+		base, err := loader.Load("aws-standard-ga")
+		require.NoError(t, err)
+		shaped, err := profile.ApplyOverrides(base, nil)
+		require.NoError(t, err)
+		assert.Same(t, base, shaped)
+	})
+
+	t.Run("EKS node groups", func(t *testing.T) {
+		base, err := loader.Load("eks-standard")
+		require.NoError(t, err)
+
+		shaped, err := profile.ApplyOverrides(base, &types.ComputeOverrides{
+			WorkerReplicas: intPtr(5),
+			MachinePools:   []types.MachinePoolOverride{{Name: "batch", InstanceType: "m6i.xlarge", Replicas: 1}},
+		})
+		require.NoError(t, err)
+
+		groups := shaped.Compute.ManagedNodeGroups
+		if len(groups) == 0 {
+			groups = shaped.Compute.NodeGroups
+		}
+		require.Len(t, groups, 2)
+		assert.Equal(t, 5, groups[0].DesiredCapacity)
+		assert.GreaterOrEqual(t, groups[0].MaxSize, 5)
+		assert.Equal(t, "batch", groups[1].Name)
+	})
+
+	t.Run("AKS node pools", func(t *testing.T) {
+		base, err := loader.Load("aks-standard")
+		require.NoError(t, err)
+
+		shaped, err := profile.ApplyOverrides(base, &types.ComputeOverrides{
+			WorkerInstanceType: "Standard_D8s_v3",
+			MachinePools:       []types.MachinePoolOverride{{Name: "user2", InstanceType: "Standard_D4s_v3", Replicas: 2}},
+		})
+		require.NoError(t, err)
+
+		pools := shaped.PlatformConfig.AKS.NodePools
+		assert.Equal(t, "Standard_D8s_v3", pools[0].VMSize)
+		assert.Equal(t, "user2", pools[len(pools)-1].Name)
+		assert.Len(t, pools, len(base.PlatformConfig.AKS.NodePools)+1)
+	})
+
+	t.Run("HCP node pools", func(t *testing.T) {
+		base, err := loader.Load("hcp-standard")
+		require.NoError(t, err)
+
+		shaped, err := profile.ApplyOverrides(base, &types.ComputeOverrides{
+			WorkerReplicas:     intPtr(4),
+			WorkerInstanceType: "8x32",
+			MachinePools:       []types.MachinePoolOverride{{Name: "gpu", InstanceType: "16x64", Replicas: 1}},
+		})
+		require.NoError(t, err)
+
+		shapes := shaped.WorkerShapes()
+		require.Len(t, shapes, 2)
+		assert.Equal(t, profile.NodeShape{Name: "workers", Replicas: 4, InstanceType: "8x32", RootVolumeGB: 64}, shapes[0])
+		assert.Equal(t, "gpu", shapes[1].Name)
+		assert.Len(t, base.PlatformConfig.HCP.NodePools, 1, "base profile is not modified")
+
+		_, err = profile.ApplyOverrides(base, &types.ComputeOverrides{WorkerInstanceType: "m6i.xlarge"})
+		assert.Error(t, err)
+	})
+
+	t.Run("on-demand clears spot workers", func(t *testing.T) {
+		base, err := loader.Load("eks-standard")
+		require.NoError(t, err)
+		base.Compute.NodeGroups[0].Spot = &profile.SpotConfig{InstanceTypes: []string{"t3a.large"}, OnDemandFallback: true}
+
+		pools := base.SpotPools()
+		require.Len(t, pools, 1)
+		assert.Equal(t, []string{"t3.large", "t3a.large"}, pools[0].InstanceTypes)
+
+		// No overridable section is needed to go on-demand
+		shaped, err := profile.ApplyOverrides(base, &types.ComputeOverrides{OnDemand: true})
+		require.NoError(t, err)
+		assert.Nil(t, shaped.Compute.NodeGroups[0].Spot)
+		assert.Empty(t, shaped.SpotPools())
+		assert.NotNil(t, base.Compute.NodeGroups[0].Spot, "base profile is not modified")
+	})
+
+	t.Run("IKS rejects extra pools", func(t *testing.T) {
+		base, err := loader.Load("iks-standard")
+		require.NoError(t, err)
+
+		_, err = profile.ApplyOverrides(base, &types.ComputeOverrides{
+			MachinePools: []types.MachinePoolOverride{{Name: "extra", InstanceType: "bx2.4x16", Replicas: 1}},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestRenderer_ComputeOverrides(t *testing.T) {
+	registry, err := profile.NewRegistry(profile.NewLoader("definitions"))
+	require.NoError(t, err)
+
+	req := &policy.CreateClusterRequest{
+		Name:       "override-cluster",
+		Platform:   "aws",
+		Version:    "4.20",
+		Profile:    "aws-standard-ga",
+		Region:     "us-east-1",
+		BaseDomain: "mg.dog8code.com",
+		Overrides: &types.ComputeOverrides{
+			WorkerReplicas:     intPtr(6),
+			WorkerInstanceType: "m6i.4xlarge",
+			RootVolumeGB:       intPtr(250),
+		},
+	}
+
+	config, err := profile.NewRenderer(registry).RenderInstallConfig(req, `{"auths":{}}`, nil)
+	require.NoError(t, err)
+
+	var installConfig struct {
+		ControlPlane struct {
+			Platform struct {
+				AWS struct {
+					Type string `yaml:"type"`
+				} `yaml:"aws"`
+			} `yaml:"platform"`
+		} `yaml:"controlPlane"`
+		Compute []struct {
+			Replicas int `yaml:"replicas"`
+			Platform struct {
+				AWS struct {
+					Type       string `yaml:"type"`
+					RootVolume struct {
+						Size int `yaml:"size"`
+					} `yaml:"rootVolume"`
+				} `yaml:"aws"`
+			} `yaml:"platform"`
+		} `yaml:"compute"`
+	}
+	require.NoError(t, yaml.Unmarshal(config, &installConfig))
+
+	require.Len(t, installConfig.Compute, 1)
+	assert.Equal(t, 6, installConfig.Compute[0].Replicas)
+	assert.Equal(t, "m6i.4xlarge", installConfig.Compute[0].Platform.AWS.Type)
+	assert.Equal(t, 250, installConfig.Compute[0].Platform.AWS.RootVolume.Size)
+	assert.Equal(t, "m6i.xlarge", installConfig.ControlPlane.Platform.AWS.Type)
+}
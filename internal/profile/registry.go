@@ -1,17 +1,34 @@
 package profile
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"sync"
+	"time"
 
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
+// DefaultWatchInterval is how often Watch checks the database for profile
+// changes
+const DefaultWatchInterval = 15 * time.Second
+
+// Store is the database a registry loads published profiles from
+type Store interface {
+	ListProfiles(ctx context.Context, platform *types.Platform, track *string, enabledOnly bool) ([]*Profile, error)
+	// ProfilesChangeMarker returns a value that changes whenever a profile is
+	// added, updated or removed
+	ProfilesChangeMarker(ctx context.Context) (string, error)
+}
+
 // Registry provides fast in-memory access to cluster profiles
 type Registry struct {
 	mu       sync.RWMutex
 	profiles map[string]*Profile // keyed by profile name
 	loader   *Loader
+	store    Store
+	marker   string // store change marker of the loaded profiles
 }
 
 // NewRegistry creates a new profile registry and loads all profiles
@@ -28,6 +45,22 @@ func NewRegistry(loader *Loader) (*Registry, error) {
 	return r, nil
 }
 
+// NewRegistryFromStore creates a registry that loads the published profiles
+// from the database instead of the profile files, so that profiles managed
+// through the API are seen by every replica. Call Watch to pick up changes.
+func NewRegistryFromStore(store Store) (*Registry, error) {
+	r := &Registry{
+		profiles: make(map[string]*Profile),
+		store:    store,
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, fmt.Errorf("initial profile load: %w", err)
+	}
+
+	return r, nil
+}
+
 // Get retrieves a profile by name
 func (r *Registry) Get(name string) (*Profile, error) {
 	r.mu.RLock()
@@ -111,13 +144,86 @@ func (r *Registry) Exists(name string) bool {
 	return exists && profile.Enabled
 }
 
-// Reload reloads all profiles from disk
+// Reload reloads all profiles from disk, or from the database for a registry
+// created with NewRegistryFromStore
 func (r *Registry) Reload() error {
+	if r.store != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return r.reloadFromStore(ctx)
+	}
+
 	profiles, err := r.loader.LoadAll()
 	if err != nil {
 		return fmt.Errorf("load profiles: %w", err)
 	}
 
+	r.replace(profiles, "")
+	return nil
+}
+
+func (r *Registry) reloadFromStore(ctx context.Context) error {
+	// Read the marker first so a change made during the load is picked up by
+	// the next poll rather than missed
+	marker, err := r.store.ProfilesChangeMarker(ctx)
+	if err != nil {
+		return fmt.Errorf("read profile change marker: %w", err)
+	}
+
+	profiles, err := r.store.ListProfiles(ctx, nil, nil, false)
+	if err != nil {
+		return fmt.Errorf("load profiles: %w", err)
+	}
+
+	r.replace(profiles, marker)
+	return nil
+}
+
+// Watch polls the database every interval and reloads the registry when a
+// profile changed, until ctx is cancelled. It does nothing for a registry
+// loaded from disk.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	if r.store == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.refresh(ctx); err != nil {
+				log.Printf("Warning: profile registry refresh failed: %v", err)
+			}
+		}
+	}
+}
+
+// refresh reloads from the store if the change marker moved
+func (r *Registry) refresh(ctx context.Context) error {
+	marker, err := r.store.ProfilesChangeMarker(ctx)
+	if err != nil {
+		return fmt.Errorf("read profile change marker: %w", err)
+	}
+
+	r.mu.RLock()
+	unchanged := marker == r.marker
+	r.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	if err := r.reloadFromStore(ctx); err != nil {
+		return err
+	}
+	log.Printf("Reloaded %d profiles after a profile change", r.Count())
+	return nil
+}
+
+func (r *Registry) replace(profiles []*Profile, marker string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -128,8 +234,7 @@ func (r *Registry) Reload() error {
 	for _, profile := range profiles {
 		r.profiles[profile.Name] = profile
 	}
-
-	return nil
+	r.marker = marker
 }
 
 // Count returns the total number of profiles (including disabled)
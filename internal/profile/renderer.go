@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -39,6 +40,11 @@ type InstallConfigData struct {
 	ControlPlaneType     string
 	WorkerReplicas       int
 	WorkerType           string
+	WorkerRootVolumeGB   int // Worker-only root disk size; 0 leaves the platform default
+
+	// Architecture, set only for clusters that are not all amd64
+	ControlPlaneArchitecture types.Architecture
+	WorkerArchitecture       types.Architecture
 
 	// Networking
 	NetworkType     string
@@ -68,6 +74,13 @@ type InstallConfigData struct {
 
 	// Azure-specific
 	AzureBaseDomainResourceGroup string
+
+	// Restricted network
+	Proxy                 *types.ProxyConfig
+	AdditionalTrustBundle string
+	TrustBundlePolicy     string // "Always" when the bundle must reach nodes without a proxy
+	ImageMirrorsKey       string // imageDigestSources (4.14+) or imageContentSources
+	ImageMirrors          []types.ImageMirror
 }
 
 // RenderInstallConfig generates an install-config.yaml file
@@ -78,6 +91,18 @@ func (r *Renderer) RenderInstallConfig(req *types.CreateClusterRequest, pullSecr
 		return nil, fmt.Errorf("get profile: %w", err)
 	}
 
+	return RenderInstallConfigForProfile(prof, req, pullSecret, mergedTags)
+}
+
+// RenderInstallConfigForProfile generates an install-config.yaml file from a
+// profile that has already been looked up, such as one loaded from the database
+func RenderInstallConfigForProfile(prof *Profile, req *types.CreateClusterRequest, pullSecret string, mergedTags map[string]string) ([]byte, error) {
+	// Apply per-cluster compute overrides (validated by the policy engine)
+	prof, err := ApplyOverrides(prof, req.Overrides)
+	if err != nil {
+		return nil, fmt.Errorf("apply compute overrides: %w", err)
+	}
+
 	// Determine publish strategy based on privateCluster setting
 	publishStrategy := "External" // Default to External (public API)
 	if prof.Features.PrivateCluster {
@@ -110,6 +135,7 @@ func (r *Renderer) RenderInstallConfig(req *types.CreateClusterRequest, pullSecr
 		ControlPlaneType:     prof.Compute.ControlPlane.InstanceType,
 		WorkerReplicas:       prof.Compute.Workers.Replicas,
 		WorkerType:           prof.Compute.Workers.InstanceType,
+		WorkerRootVolumeGB:   prof.Compute.Workers.RootVolumeGB,
 		PublishStrategy:      publishStrategy,
 		UserTags:             mergedTags,
 	}
@@ -164,6 +190,28 @@ func (r *Renderer) RenderInstallConfig(req *types.CreateClusterRequest, pullSecr
 		data.UserTags = azureUserTags(mergedTags)
 	}
 
+	// The installer defaults every pool to amd64, so architecture is only
+	// written out for arm64 and heterogeneous clusters
+	if arch := EffectiveArchitecture(prof, req.Architecture); arch.ReleaseArchitecture() != types.ArchitectureAMD64 {
+		data.ControlPlaneArchitecture = arch.ControlPlaneArch()
+		data.WorkerArchitecture = arch.ComputeArch()
+	}
+
+	// Restricted-network settings: request values replace the profile's
+	if rn := EffectiveRestrictedNetwork(prof, req.RestrictedNetwork); rn != nil {
+		if !rn.Proxy.IsEmpty() {
+			data.Proxy = rn.Proxy
+		}
+		data.AdditionalTrustBundle = rn.AdditionalTrustBundle
+		data.ImageMirrors = rn.ImageMirrors
+		data.ImageMirrorsKey = imageMirrorsKey(req.Version)
+		// The default Proxyonly policy only distributes the bundle alongside a
+		// proxy; a mirror registry with a private CA needs it on every node
+		if rn.AdditionalTrustBundle != "" && (len(rn.ImageMirrors) > 0 || data.Proxy == nil) {
+			data.TrustBundlePolicy = "Always"
+		}
+	}
+
 	// Select appropriate template
 	var tmplStr string
 	switch prof.Platform {
@@ -183,8 +231,9 @@ func (r *Renderer) RenderInstallConfig(req *types.CreateClusterRequest, pullSecr
 	funcMap := template.FuncMap{
 		"gcpLabelKey":   gcpLabelKey,   // Convert tag keys to GCP-compliant format
 		"gcpLabelValue": gcpLabelValue, // Convert tag values to GCP-compliant format
+		"indent":        indent,        // Indent multi-line values under a block scalar
 	}
-	tmpl, err := template.New("install-config").Funcs(funcMap).Parse(tmplStr)
+	tmpl, err := template.New("install-config").Funcs(funcMap).Parse(tmplStr + restrictedNetworkTemplate)
 	if err != nil {
 		return nil, fmt.Errorf("parse template: %w", err)
 	}
@@ -203,6 +252,30 @@ func (r *Renderer) RenderInstallConfig(req *types.CreateClusterRequest, pullSecr
 	return buf.Bytes(), nil
 }
 
+// imageMirrorsKey returns the install-config field for image mirrors:
+// imageDigestSources from 4.14 on, the deprecated imageContentSources before
+func imageMirrorsKey(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) >= 2 {
+		major, errMajor := strconv.Atoi(parts[0])
+		minor, errMinor := strconv.Atoi(parts[1])
+		if errMajor == nil && errMinor == nil && major == 4 && minor < 14 {
+			return "imageContentSources"
+		}
+	}
+	return "imageDigestSources"
+}
+
+// indent prefixes every line of s with n spaces
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = pad + line
+	}
+	return strings.Join(lines, "\n")
+}
+
 // stringValue safely dereferences a string pointer
 func stringValue(s *string) string {
 	if s == nil {
@@ -442,15 +515,29 @@ platform:
 controlPlane:
   name: master
   replicas: {{.ControlPlaneReplicas}}
+{{- if .ControlPlaneArchitecture}}
+  architecture: {{.ControlPlaneArchitecture}}
+{{- end}}
   platform:
     aws:
       type: {{.ControlPlaneType}}
 compute:
 - name: worker
   replicas: {{.WorkerReplicas}}
+{{- if .WorkerArchitecture}}
+  architecture: {{.WorkerArchitecture}}
+{{- end}}
   platform:
     aws:
       type: {{.WorkerType}}
+{{- if .WorkerRootVolumeGB}}
+      rootVolume:
+        type: {{if .AWSRootVolumeType}}{{.AWSRootVolumeType}}{{else}}gp3{{end}}
+        size: {{.WorkerRootVolumeGB}}
+{{- if .AWSRootVolumeIOPS}}
+        iops: {{.AWSRootVolumeIOPS}}
+{{- end}}
+{{- end}}
 networking:
   networkType: {{.NetworkType}}
   clusterNetwork:
@@ -460,6 +547,7 @@ networking:
   - {{.ServiceCIDR}}
   machineNetwork:
   - cidr: {{.MachineCIDR}}
+{{- template "restrictedNetwork" .}}
 pullSecret: '{{.PullSecret}}'
 {{- if .SSHKey}}
 sshKey: '{{.SSHKey}}'
@@ -508,6 +596,7 @@ networking:
   - {{.ServiceCIDR}}
   machineNetwork:
   - cidr: {{.MachineCIDR}}
+{{- template "restrictedNetwork" .}}
 pullSecret: '{{.PullSecret}}'
 {{- if .SSHKey}}
 sshKey: '{{.SSHKey}}'
@@ -544,15 +633,25 @@ platform:
 controlPlane:
   name: master
   replicas: {{.ControlPlaneReplicas}}
+{{- if .ControlPlaneArchitecture}}
+  architecture: {{.ControlPlaneArchitecture}}
+{{- end}}
   platform:
     gcp:
       type: {{.ControlPlaneType}}
 compute:
 - name: worker
   replicas: {{.WorkerReplicas}}
+{{- if .WorkerArchitecture}}
+  architecture: {{.WorkerArchitecture}}
+{{- end}}
   platform:
     gcp:
       type: {{.WorkerType}}
+{{- if .WorkerRootVolumeGB}}
+      osDisk:
+        diskSizeGB: {{.WorkerRootVolumeGB}}
+{{- end}}
 networking:
   networkType: {{.NetworkType}}
   clusterNetwork:
@@ -562,6 +661,7 @@ networking:
   - {{.ServiceCIDR}}
   machineNetwork:
   - cidr: {{.MachineCIDR}}
+{{- template "restrictedNetwork" .}}
 pullSecret: '{{.PullSecret}}'
 {{- if .SSHKey}}
 sshKey: '{{.SSHKey}}'
@@ -589,15 +689,25 @@ platform:
 controlPlane:
   name: master
   replicas: {{.ControlPlaneReplicas}}
+{{- if .ControlPlaneArchitecture}}
+  architecture: {{.ControlPlaneArchitecture}}
+{{- end}}
   platform:
     azure:
       type: {{.ControlPlaneType}}
 compute:
 - name: worker
   replicas: {{.WorkerReplicas}}
+{{- if .WorkerArchitecture}}
+  architecture: {{.WorkerArchitecture}}
+{{- end}}
   platform:
     azure:
       type: {{.WorkerType}}
+{{- if .WorkerRootVolumeGB}}
+      osDisk:
+        diskSizeGB: {{.WorkerRootVolumeGB}}
+{{- end}}
 networking:
   networkType: {{.NetworkType}}
   clusterNetwork:
@@ -607,8 +717,43 @@ networking:
   - {{.ServiceCIDR}}
   machineNetwork:
   - cidr: {{.MachineCIDR}}
+{{- template "restrictedNetwork" .}}
 pullSecret: '{{.PullSecret}}'
 {{- if .SSHKey}}
 sshKey: '{{.SSHKey}}'
 {{- end}}
 `
+
+// restrictedNetworkTemplate renders proxy, trust bundle and image mirror
+// settings; it is appended to every platform template
+const restrictedNetworkTemplate = `{{define "restrictedNetwork"}}
+{{- with .Proxy}}
+proxy:
+{{- if .HTTPProxy}}
+  httpProxy: '{{.HTTPProxy}}'
+{{- end}}
+{{- if .HTTPSProxy}}
+  httpsProxy: '{{.HTTPSProxy}}'
+{{- end}}
+{{- if .NoProxy}}
+  noProxy: '{{.NoProxy}}'
+{{- end}}
+{{- end}}
+{{- if .AdditionalTrustBundle}}
+additionalTrustBundle: |
+{{indent 2 .AdditionalTrustBundle}}
+{{- if .TrustBundlePolicy}}
+additionalTrustBundlePolicy: {{.TrustBundlePolicy}}
+{{- end}}
+{{- end}}
+{{- if .ImageMirrors}}
+{{.ImageMirrorsKey}}:
+{{- range .ImageMirrors}}
+- source: {{.Source}}
+  mirrors:
+{{- range .Mirrors}}
+  - {{.}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- end}}`
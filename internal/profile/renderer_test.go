@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tsanders-rh/ocpctl/internal/policy"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 	"gopkg.in/yaml.v3"
 )
 
@@ -142,6 +143,36 @@ func TestRenderer_RenderInstallConfig(t *testing.T) {
 		assert.Contains(t, configStr, "iops: 3000")
 	})
 
+	t.Run("renders node architectures only when not all amd64", func(t *testing.T) {
+		req := &policy.CreateClusterRequest{
+			Name:       "test-cluster-01",
+			Platform:   "aws",
+			Version:    "4.20.3",
+			Profile:    "aws-minimal-test",
+			Region:     "us-east-1",
+			BaseDomain: "labs.example.com",
+			Owner:      "test-user",
+			Team:       "platform-team",
+			CostCenter: "engineering",
+			TTLHours:   24,
+		}
+
+		config, err := renderer.RenderInstallConfig(req, `{"auths":{}}`, map[string]string{})
+		require.NoError(t, err)
+		assert.NotContains(t, string(config), "architecture:")
+
+		req.Architecture = &types.ClusterArchitecture{Compute: types.ArchitectureARM64}
+		config, err = renderer.RenderInstallConfig(req, `{"auths":{}}`, map[string]string{})
+		require.NoError(t, err)
+
+		var installConfig map[string]interface{}
+		require.NoError(t, yaml.Unmarshal(config, &installConfig))
+		controlPlane := installConfig["controlPlane"].(map[string]interface{})
+		assert.Equal(t, "amd64", controlPlane["architecture"])
+		workers := installConfig["compute"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "arm64", workers["architecture"])
+	})
+
 	t.Run("handles missing SSH key", func(t *testing.T) {
 		req := &policy.CreateClusterRequest{
 			Name:         "test-cluster-01",
@@ -347,8 +378,8 @@ func TestRenderer_RenderInstallConfig(t *testing.T) {
 			if prof.Platform != "aws" {
 				continue
 			}
-			// Skip EKS profiles - they use a different template
-			if prof.ClusterType == "eks" {
+			// Skip EKS and HCP profiles - they don't use install-config
+			if prof.ClusterType == "eks" || prof.ClusterType == "hcp" {
 				continue
 			}
 
@@ -0,0 +1,189 @@
+package profile
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// RestrictedNetworkProblem is one invalid restricted-network setting. Field is
+// relative to the restricted-network block, e.g. "proxy.httpProxy".
+type RestrictedNetworkProblem struct {
+	Field   string
+	Message string
+}
+
+// EffectiveRestrictedNetwork merges request-level restricted-network settings
+// over the profile's. Each of proxy, trust bundle and image mirrors is taken
+// from the request when set there and from the profile otherwise. Returns nil
+// when neither configures anything.
+func EffectiveRestrictedNetwork(p *Profile, req *types.RestrictedNetwork) *types.RestrictedNetwork {
+	var base *types.RestrictedNetwork
+	if p != nil && p.Networking != nil {
+		base = p.Networking.Restricted
+	}
+	if req.IsEmpty() {
+		if base.IsEmpty() {
+			return nil
+		}
+		return base
+	}
+	if base.IsEmpty() {
+		return req
+	}
+
+	merged := *base
+	if !req.Proxy.IsEmpty() {
+		merged.Proxy = req.Proxy
+	}
+	if req.AdditionalTrustBundle != "" {
+		merged.AdditionalTrustBundle = req.AdditionalTrustBundle
+	}
+	if len(req.ImageMirrors) > 0 {
+		merged.ImageMirrors = req.ImageMirrors
+	}
+	return &merged
+}
+
+// ValidateRestrictedNetwork checks restricted-network settings against the
+// profile they will be installed with. OpenShift IPI supports everything; ROSA
+// and ARO support the proxy and trust bundle but not image mirrors, and ROSA
+// needs the profile to install into existing subnets; other cluster types
+// support none of it.
+func ValidateRestrictedNetwork(rn *types.RestrictedNetwork, p *Profile) []RestrictedNetworkProblem {
+	if rn.IsEmpty() {
+		return nil
+	}
+
+	var problems []RestrictedNetworkProblem
+	add := func(field, format string, args ...interface{}) {
+		problems = append(problems, RestrictedNetworkProblem{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	clusterType := p.EffectiveClusterType()
+	switch clusterType {
+	case types.ClusterTypeOpenShift:
+	case types.ClusterTypeROSA, types.ClusterTypeARO:
+		if len(rn.ImageMirrors) > 0 {
+			add("imageMirrors", "image mirrors are not supported for %s clusters", clusterType)
+		}
+		if clusterType == types.ClusterTypeROSA && (!rn.Proxy.IsEmpty() || rn.AdditionalTrustBundle != "") &&
+			(p.PlatformConfig.ROSA == nil || len(p.PlatformConfig.ROSA.SubnetIDs) == 0) {
+			add("", "ROSA only supports a proxy or trust bundle in an existing VPC; profile %s must set platformConfig.rosa.subnetIds", p.Name)
+		}
+		// The rosa CLI only takes the proxy on its command line
+		if clusterType == types.ClusterTypeROSA && rn.Proxy != nil && rn.Proxy.CredentialsSecret != "" {
+			add("proxy.credentialsSecret", "proxy credentials are not supported for ROSA clusters")
+		}
+	default:
+		add("", "proxy, trust bundle and image mirror settings are not supported for %s clusters", clusterType)
+		return problems
+	}
+
+	if proxy := rn.Proxy; !proxy.IsEmpty() {
+		if proxy.HTTPProxy == "" && proxy.HTTPSProxy == "" {
+			add("proxy", "proxy requires httpProxy or httpsProxy")
+		}
+		if proxy.HTTPProxy != "" {
+			if err := checkProxyURL(proxy.HTTPProxy, "http"); err != nil {
+				add("proxy.httpProxy", "%v", err)
+			}
+		}
+		if proxy.HTTPSProxy != "" {
+			if err := checkProxyURL(proxy.HTTPSProxy, "http", "https"); err != nil {
+				add("proxy.httpsProxy", "%v", err)
+			}
+		}
+		if proxy.CredentialsSecret != "" {
+			team, name, _ := strings.Cut(proxy.CredentialsSecret, "/")
+			if team == "" || name == "" || strings.Contains(proxy.CredentialsSecret, "..") {
+				add("proxy.credentialsSecret", "credentialsSecret must have the form <team>/<name>")
+			}
+		}
+		for _, entry := range strings.Split(proxy.NoProxy, ",") {
+			if proxy.NoProxy != "" && (entry == "" || strings.TrimSpace(entry) != entry) {
+				add("proxy.noProxy", "noProxy must be a comma-separated list without empty entries or spaces")
+				break
+			}
+		}
+	}
+
+	if rn.AdditionalTrustBundle != "" {
+		if err := checkTrustBundle(rn.AdditionalTrustBundle); err != nil {
+			add("additionalTrustBundle", "%v", err)
+		}
+	}
+
+	for i, m := range rn.ImageMirrors {
+		field := fmt.Sprintf("imageMirrors[%d]", i)
+		if strings.TrimSpace(m.Source) == "" {
+			add(field+".source", "source is required")
+		} else if strings.Contains(m.Source, "://") {
+			add(field+".source", "source must be a repository without a scheme, got %q", m.Source)
+		}
+		if len(m.Mirrors) == 0 {
+			add(field+".mirrors", "at least one mirror is required")
+		}
+		for j, mirror := range m.Mirrors {
+			if strings.TrimSpace(mirror) == "" || strings.Contains(mirror, "://") {
+				add(fmt.Sprintf("%s.mirrors[%d]", field, j), "mirror must be a repository without a scheme, got %q", mirror)
+			}
+		}
+	}
+
+	return problems
+}
+
+// checkProxyURL verifies a proxy URL has a host and one of the allowed schemes.
+// Credentials are rejected, and not echoed back: they would be stored with the
+// cluster or profile, so they come from credentialsSecret instead.
+func checkProxyURL(raw string, schemes ...string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL %q: %v", raw, err)
+	}
+	if u.User != nil {
+		return fmt.Errorf("proxy URL for host %s must not contain credentials; set credentialsSecret instead", u.Host)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("proxy URL %q has no host", raw)
+	}
+	for _, s := range schemes {
+		if u.Scheme == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("proxy URL %q must use scheme %s", raw, strings.Join(schemes, " or "))
+}
+
+// checkTrustBundle verifies the bundle is PEM containing at least one
+// certificate and that every certificate parses
+func checkTrustBundle(bundle string) error {
+	rest := []byte(bundle)
+	count := 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("trust bundle contains a %s block; only CERTIFICATE blocks are allowed", block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("trust bundle certificate %d is invalid: %v", count+1, err)
+		}
+		count++
+	}
+	if count == 0 {
+		return fmt.Errorf("trust bundle must contain at least one PEM-encoded certificate")
+	}
+	if strings.TrimSpace(string(rest)) != "" {
+		return fmt.Errorf("trust bundle has trailing data after the last certificate")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// Definition renders a resolved profile as a standalone YAML definition. The
+// inheritance keys are dropped because the values they pulled in are already
+// merged; keeping them would re-apply the base profile on the next load.
+func Definition(p *Profile) ([]byte, error) {
+	flat := *p
+	flat.Extends = ""
+	flat.Include = nil
+	data, err := yaml.Marshal(&flat)
+	if err != nil {
+		return nil, fmt.Errorf("marshal profile %s: %w", p.Name, err)
+	}
+	return data, nil
+}
+
+// CheckStandalone rejects a definition that uses extends or include.
+// Definitions managed through the API are stored on their own in the
+// database, while bases and fragments are only resolved from the profile
+// files, which can change without a new revision.
+func CheckStandalone(data []byte) error {
+	var keys struct {
+		Extends interface{} `yaml:"extends"`
+		Include interface{} `yaml:"include"`
+	}
+	if err := yaml.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("parse profile definition: %w", err)
+	}
+	if keys.Extends != nil || keys.Include != nil {
+		return errors.New("extends and include are only supported in profile files; submit a standalone definition")
+	}
+	return nil
+}
+
+// Diff returns a unified diff between two resolved profiles, rendered as
+// YAML. from is nil for a new profile. Returns "" if they are identical.
+func Diff(from, to *Profile) (string, error) {
+	var a, b []byte
+	var err error
+	if from != nil {
+		if a, err = yaml.Marshal(from); err != nil {
+			return "", fmt.Errorf("marshal profile %s: %w", from.Name, err)
+		}
+	}
+	if to != nil {
+		if b, err = yaml.Marshal(to); err != nil {
+			return "", fmt.Errorf("marshal profile %s: %w", to.Name, err)
+		}
+	}
+
+	name := ""
+	if to != nil {
+		name = to.Name
+	} else if from != nil {
+		name = from.Name
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(a)),
+		B:        difflib.SplitLines(string(b)),
+		FromFile: "a/" + name + ".yaml",
+		ToFile:   "b/" + name + ".yaml",
+		Context:  3,
+	})
+}
@@ -0,0 +1,125 @@
+package profile_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDiff(t *testing.T) {
+	loader := profile.NewLoader("definitions")
+	from, err := loader.Load("aws-sno-prerelease")
+	require.NoError(t, err)
+	to, err := loader.Load("aws-sno-prerelease")
+	require.NoError(t, err)
+	to.Lifecycle.MaxTTLHours = 12
+
+	diff, err := profile.Diff(from, to)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "--- a/aws-sno-prerelease.yaml")
+	assert.Contains(t, diff, "-    maxTTLHours: 24")
+	assert.Contains(t, diff, "+    maxTTLHours: 12")
+
+	diff, err = profile.Diff(from, from)
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+
+	diff, err = profile.Diff(nil, to)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+name: aws-sno-prerelease")
+}
+
+func TestDefinition_Standalone(t *testing.T) {
+	loader := profile.NewLoader("definitions")
+	prof, err := loader.Load("aws-sno-test")
+	require.NoError(t, err)
+	require.NotEmpty(t, prof.Extends)
+
+	data, err := profile.Definition(prof)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &doc))
+	assert.NotContains(t, doc, "extends")
+	assert.NotContains(t, doc, "include")
+
+	// Loading the flattened definition gives back the same profile, without
+	// re-applying values the child removed from its base
+	reloaded, err := loader.LoadData(prof.Name, data)
+	require.NoError(t, err)
+	assert.Equal(t, prof.Tags, reloaded.Tags)
+	assert.Equal(t, prof.Lifecycle, reloaded.Lifecycle)
+	assert.Empty(t, reloaded.Track)
+}
+
+func TestCheckStandalone(t *testing.T) {
+	assert.NoError(t, profile.CheckStandalone([]byte("name: aws-a\nlifecycle:\n  maxTTLHours: 8\n")))
+	assert.NoError(t, profile.CheckStandalone([]byte(`{"name": "aws-a"}`)))
+
+	for _, def := range []string{
+		"name: aws-a\nextends: aws-sno-ga\n",
+		"name: aws-a\ninclude: [prerelease-track]\n",
+		`{"name": "aws-a", "extends": "aws-sno-ga"}`,
+	} {
+		assert.ErrorContains(t, profile.CheckStandalone([]byte(def)), "only supported in profile files", def)
+	}
+
+	// The standalone rendering of an inheriting profile passes
+	prof, err := profile.NewLoader("definitions").Load("aws-sno-prerelease")
+	require.NoError(t, err)
+	data, err := profile.Definition(prof)
+	require.NoError(t, err)
+	assert.NoError(t, profile.CheckStandalone(data))
+}
+
+// fakeProfileStore is an in-memory profile.Store
+type fakeProfileStore struct {
+	mu       sync.Mutex
+	profiles []*profile.Profile
+	version  int
+}
+
+func (f *fakeProfileStore) ListProfiles(ctx context.Context, platform *types.Platform, track *string, enabledOnly bool) ([]*profile.Profile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*profile.Profile{}, f.profiles...), nil
+}
+
+func (f *fakeProfileStore) ProfilesChangeMarker(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return fmt.Sprintf("%d", f.version), nil
+}
+
+func (f *fakeProfileStore) publish(p *profile.Profile) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.profiles = append(f.profiles, p)
+	f.version++
+}
+
+func TestRegistry_FromStoreWatch(t *testing.T) {
+	st := &fakeProfileStore{}
+	st.publish(&profile.Profile{Name: "aws-a", Platform: types.PlatformAWS, Enabled: true})
+
+	registry, err := profile.NewRegistryFromStore(st)
+	require.NoError(t, err)
+	assert.True(t, registry.Exists("aws-a"))
+	assert.False(t, registry.Exists("aws-b"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go registry.Watch(ctx, 10*time.Millisecond)
+
+	st.publish(&profile.Profile{Name: "aws-b", Platform: types.PlatformAWS, Enabled: true})
+	assert.Eventually(t, func() bool { return registry.Exists("aws-b") }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, registry.Count())
+}
@@ -0,0 +1,140 @@
+package profile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// SpotPool is a worker pool that asks for spot capacity
+type SpotPool struct {
+	Name          string
+	InstanceTypes []string // First entry is the pool's primary instance type
+	Spot          *SpotConfig
+}
+
+// SpotPools returns the profile's spot worker pools in WorkerShapes order.
+// Extra pools added by compute overrides are always on-demand.
+func (p *Profile) SpotPools() []SpotPool {
+	var pools []SpotPool
+	for _, s := range p.WorkerShapes() {
+		if s.Spot == nil {
+			continue
+		}
+		instanceTypes := []string{s.InstanceType}
+		for _, it := range s.Spot.InstanceTypes {
+			if it != s.InstanceType {
+				instanceTypes = append(instanceTypes, it)
+			}
+		}
+		pools = append(pools, SpotPool{Name: s.Name, InstanceTypes: instanceTypes, Spot: s.Spot})
+	}
+	return pools
+}
+
+// clearSpot switches every worker pool in the profile to on-demand capacity
+func (p *Profile) clearSpot() {
+	if p.Compute.Workers != nil {
+		p.Compute.Workers.Spot = nil
+	}
+	for i := range p.Compute.NodeGroups {
+		p.Compute.NodeGroups[i].Spot = nil
+	}
+	for i := range p.Compute.ManagedNodeGroups {
+		p.Compute.ManagedNodeGroups[i].Spot = nil
+	}
+	if p.PlatformConfig.GKE != nil {
+		for i := range p.PlatformConfig.GKE.NodePools {
+			p.PlatformConfig.GKE.NodePools[i].Spot = nil
+		}
+	}
+	if p.PlatformConfig.AKS != nil {
+		for i := range p.PlatformConfig.AKS.NodePools {
+			p.PlatformConfig.AKS.NodePools[i].Spot = nil
+		}
+	}
+}
+
+// ParseSpotMaxPrice returns the hourly price cap of a spot config, or 0 when
+// the price is capped at the on-demand rate
+func ParseSpotMaxPrice(s *SpotConfig) (float64, error) {
+	if s == nil || s.MaxPrice == "" {
+		return 0, nil
+	}
+	price, err := strconv.ParseFloat(s.MaxPrice, 64)
+	if err != nil || price <= 0 {
+		return 0, fmt.Errorf("maxPrice %q must be a positive number of USD per hour", s.MaxPrice)
+	}
+	return price, nil
+}
+
+// validateSpot checks each spot setting is placed where the cluster type can
+// honour it. Returns the offending field path and a message, or "" when valid.
+func validateSpot(p *Profile) (string, string) {
+	ct := p.EffectiveClusterType()
+
+	check := func(path, pools string, s *SpotConfig, allowTypes, allowPrice bool) (string, string) {
+		if s == nil {
+			return "", ""
+		}
+		if len(s.InstanceTypes) > 0 && !allowTypes {
+			return path + ".instanceTypes", "spot instanceTypes are only supported for EKS node groups"
+		}
+		for i, it := range s.InstanceTypes {
+			if strings.TrimSpace(it) == "" {
+				return path + ".instanceTypes", fmt.Sprintf("spot instanceTypes[%d] is empty", i)
+			}
+		}
+		if s.MaxPrice != "" && !allowPrice {
+			return path + ".maxPrice", fmt.Sprintf("spot maxPrice is not supported for %s", pools)
+		}
+		if _, err := ParseSpotMaxPrice(s); err != nil {
+			return path + ".maxPrice", "spot " + err.Error()
+		}
+		return "", ""
+	}
+
+	if w := p.Compute.Workers; w != nil && w.Spot != nil {
+		if ct != types.ClusterTypeOpenShift || p.Platform != types.PlatformAWS {
+			return "compute.workers.spot", fmt.Sprintf("spot workers are only supported for OpenShift on AWS, not %s on %s", ct, p.Platform)
+		}
+		if path, msg := check("compute.workers.spot", "OpenShift workers", w.Spot, false, true); msg != "" {
+			return path, msg
+		}
+	}
+
+	for i, ng := range p.Compute.ManagedNodeGroups {
+		if path, msg := check(fmt.Sprintf("compute.managedNodeGroups[%d].spot", i), "EKS managed node groups", ng.Spot, true, false); msg != "" {
+			return path, msg
+		}
+	}
+	for i, ng := range p.Compute.NodeGroups {
+		if path, msg := check(fmt.Sprintf("compute.nodeGroups[%d].spot", i), "EKS node groups", ng.Spot, true, true); msg != "" {
+			return path, msg
+		}
+	}
+
+	if p.PlatformConfig.GKE != nil {
+		for i, np := range p.PlatformConfig.GKE.NodePools {
+			if path, msg := check(fmt.Sprintf("platformConfig.gke.nodePools[%d].spot", i), "GKE node pools", np.Spot, false, false); msg != "" {
+				return path, msg
+			}
+		}
+	}
+
+	if p.PlatformConfig.AKS != nil {
+		for i, np := range p.PlatformConfig.AKS.NodePools {
+			path := fmt.Sprintf("platformConfig.aks.nodePools[%d].spot", i)
+			if i == 0 && np.Spot != nil {
+				return path, "the first AKS node pool is the system pool and cannot use spot capacity"
+			}
+			if path, msg := check(path, "AKS node pools", np.Spot, false, true); msg != "" {
+				return path, msg
+			}
+		}
+	}
+
+	return "", ""
+}
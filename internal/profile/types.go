@@ -7,7 +7,7 @@ type Profile struct {
 	Name               string                `yaml:"name" validate:"required"`
 	DisplayName        string                `yaml:"displayName" validate:"required"`
 	Description        string                `yaml:"description" validate:"required"`
-	Platform           types.Platform        `yaml:"platform" validate:"required,oneof=aws ibmcloud gcp azure"`
+	Platform           types.Platform        `yaml:"platform" validate:"required,oneof=aws ibmcloud gcp azure local"`
 	ClusterType        types.ClusterType     `yaml:"clusterType,omitempty"`
 	Track              string                `yaml:"track,omitempty" validate:"omitempty,oneof=ga prerelease kube"` // ga, prerelease, or kube
 	Enabled            bool                  `yaml:"enabled"`
@@ -18,6 +18,7 @@ type Profile struct {
 	Zones              *ZoneConfig           `yaml:"zones,omitempty"`
 	BaseDomains        *BaseDomainConfig     `yaml:"baseDomains,omitempty"`
 	Compute            ComputeConfig         `yaml:"compute" validate:"required"`
+	Overridable        *OverridableConfig    `yaml:"overridable,omitempty"`
 	Lifecycle          LifecycleConfig       `yaml:"lifecycle" validate:"required"`
 	Networking         *NetworkingConfig     `yaml:"networking,omitempty"`
 	Tags               TagsConfig            `yaml:"tags" validate:"required"`
@@ -27,6 +28,20 @@ type Profile struct {
 	PostDeployment     *PostDeploymentConfig `yaml:"postDeployment,omitempty"`
 	DefaultAddons      []AddonReference      `yaml:"defaultAddons,omitempty" json:"default_addons,omitempty"`
 	Metadata           *MetadataConfig       `yaml:"metadata,omitempty"`
+
+	// Inheritance (see definitions/SCHEMA.md)
+	Extends  string   `yaml:"extends,omitempty" json:"extends,omitempty"`   // Base profile this one is merged onto
+	Include  []string `yaml:"include,omitempty" json:"include,omitempty"`   // Fragments merged after the base, in order
+	Abstract bool     `yaml:"abstract,omitempty" json:"abstract,omitempty"` // Only used as a base, never offered or validated on its own
+
+	// Layers lists the files merged into this profile, base first. Set by the loader.
+	Layers []string `yaml:"-" json:"layers,omitempty"`
+
+	// AdditionalMachinePools are extra worker pools created after install
+	// (OpenShift, ARO, ROSA). Set by ApplyOverrides, never loaded from YAML.
+	AdditionalMachinePools []types.MachinePoolOverride `yaml:"-" json:"additional_machine_pools,omitempty"`
+
+	origins map[string]string // YAML path -> layer that set it
 }
 
 // VersionConfig defines OpenShift version constraints
@@ -59,6 +74,9 @@ type ComputeConfig struct {
 	Workers           *WorkersConfig      `yaml:"workers,omitempty" json:"workers,omitempty"`
 	NodeGroups        []NodeGroupConfig   `yaml:"nodeGroups,omitempty" json:"node_groups,omitempty"`                // For EKS (unmanaged)
 	ManagedNodeGroups []NodeGroupConfig   `yaml:"managedNodeGroups,omitempty" json:"managed_node_groups,omitempty"` // For EKS (managed)
+
+	// Architecture of the control plane and compute nodes (default amd64)
+	Architecture *types.ClusterArchitecture `yaml:"architecture,omitempty" json:"architecture,omitempty"`
 }
 
 // ControlPlaneConfig defines control plane node configuration
@@ -70,11 +88,13 @@ type ControlPlaneConfig struct {
 
 // WorkersConfig defines worker node configuration
 type WorkersConfig struct {
-	Replicas     int    `yaml:"replicas" json:"replicas" validate:"min=0"`
-	MinReplicas  int    `yaml:"minReplicas" json:"min_replicas" validate:"min=0"`
-	MaxReplicas  int    `yaml:"maxReplicas" json:"max_replicas" validate:"gtefield=MinReplicas"`
-	InstanceType string `yaml:"instanceType,omitempty" json:"instance_type,omitempty"`
-	Autoscaling  bool   `yaml:"autoscaling" json:"autoscaling"`
+	Replicas     int         `yaml:"replicas" json:"replicas" validate:"min=0"`
+	MinReplicas  int         `yaml:"minReplicas" json:"min_replicas" validate:"min=0"`
+	MaxReplicas  int         `yaml:"maxReplicas" json:"max_replicas" validate:"gtefield=MinReplicas"`
+	InstanceType string      `yaml:"instanceType,omitempty" json:"instance_type,omitempty"`
+	RootVolumeGB int         `yaml:"rootVolumeGB,omitempty" json:"root_volume_gb,omitempty"` // Worker-only root disk size (0 = platform default)
+	Autoscaling  bool        `yaml:"autoscaling" json:"autoscaling"`
+	Spot         *SpotConfig `yaml:"spot,omitempty" json:"spot,omitempty"` // OpenShift on AWS only
 	// IKS-specific fields
 	MachineType string `yaml:"machineType,omitempty" json:"machine_type,omitempty"`
 	Count       int    `yaml:"count,omitempty" json:"count,omitempty"`
@@ -84,14 +104,54 @@ type WorkersConfig struct {
 
 // NodeGroupConfig defines EKS node group configuration
 type NodeGroupConfig struct {
-	Name            string `yaml:"name" json:"name" validate:"required"`
-	InstanceType    string `yaml:"instanceType" json:"instance_type" validate:"required"`
-	DesiredCapacity int    `yaml:"desiredCapacity" json:"desired_capacity" validate:"required,min=1"`
-	MinSize         int    `yaml:"minSize" json:"min_size" validate:"required,min=0"`
-	MaxSize         int    `yaml:"maxSize" json:"max_size" validate:"required,gtefield=DesiredCapacity"`
-	VolumeSize      int    `yaml:"volumeSize,omitempty" json:"volume_size,omitempty"`
-	VolumeType      string `yaml:"volumeType,omitempty" json:"volume_type,omitempty"`
-	AMIFamily       string `yaml:"amiFamily,omitempty" json:"ami_family,omitempty"` // For managed node groups (AmazonLinux2023, AmazonLinux2, etc.)
+	Name            string      `yaml:"name" json:"name" validate:"required"`
+	InstanceType    string      `yaml:"instanceType" json:"instance_type" validate:"required"`
+	DesiredCapacity int         `yaml:"desiredCapacity" json:"desired_capacity" validate:"required,min=1"`
+	MinSize         int         `yaml:"minSize" json:"min_size" validate:"required,min=0"`
+	MaxSize         int         `yaml:"maxSize" json:"max_size" validate:"required,gtefield=DesiredCapacity"`
+	VolumeSize      int         `yaml:"volumeSize,omitempty" json:"volume_size,omitempty"`
+	VolumeType      string      `yaml:"volumeType,omitempty" json:"volume_type,omitempty"`
+	AMIFamily       string      `yaml:"amiFamily,omitempty" json:"ami_family,omitempty"` // For managed node groups (AmazonLinux2023, AmazonLinux2, etc.)
+	Spot            *SpotConfig `yaml:"spot,omitempty" json:"spot,omitempty"`
+}
+
+// SpotConfig requests spot (AWS, Azure) or preemptible (GCP) capacity for a
+// worker pool. Spot nodes can be reclaimed at any time, so this suits
+// short-lived test clusters.
+type SpotConfig struct {
+	// MaxPrice is the highest hourly price to pay per node, in USD. Empty
+	// caps the price at the on-demand rate. Not supported for GKE node pools
+	// or EKS managed node groups, which always cap at on-demand.
+	MaxPrice string `yaml:"maxPrice,omitempty" json:"max_price,omitempty"`
+	// InstanceTypes are additional instance types an EKS node group may
+	// use, making it a mixed-instance group. More types improve the odds of
+	// finding spot capacity.
+	InstanceTypes []string `yaml:"instanceTypes,omitempty" json:"instance_types,omitempty"`
+	// OnDemandFallback creates the cluster with on-demand workers when
+	// preflight finds no spot capacity, instead of failing the create.
+	OnDemandFallback bool `yaml:"onDemandFallback,omitempty" json:"on_demand_fallback,omitempty"`
+}
+
+// OverridableConfig declares which compute parameters a create request may
+// override, and within what bounds. A profile without this section has a
+// fixed compute shape.
+type OverridableConfig struct {
+	Workers       *IntRange                `yaml:"workers,omitempty" json:"workers,omitempty"`              // Worker replica range
+	InstanceTypes []string                 `yaml:"instanceTypes,omitempty" json:"instance_types,omitempty"` // Allowed worker/pool instance types
+	RootVolumeGB  *IntRange                `yaml:"rootVolumeGB,omitempty" json:"root_volume_gb,omitempty"`  // Worker root disk size range in GB
+	MachinePools  *MachinePoolsOverridable `yaml:"machinePools,omitempty" json:"machine_pools,omitempty"`   // Extra worker pools
+}
+
+// IntRange is an inclusive integer range
+type IntRange struct {
+	Min int `yaml:"min" json:"min" validate:"min=0"`
+	Max int `yaml:"max" json:"max" validate:"gtefield=Min"`
+}
+
+// MachinePoolsOverridable bounds the extra machine pools a request may add
+type MachinePoolsOverridable struct {
+	MaxPools    int `yaml:"maxPools" json:"max_pools" validate:"min=1"`
+	MaxReplicas int `yaml:"maxReplicas" json:"max_replicas" validate:"min=1"` // Per pool
 }
 
 // LifecycleConfig defines cluster lifecycle policies
@@ -111,6 +171,9 @@ type NetworkingConfig struct {
 	// EKS-specific fields
 	VpcCIDR    string `yaml:"vpcCIDR,omitempty" json:"vpc_cidr,omitempty"`
 	NatGateway string `yaml:"natGateway,omitempty" json:"nat_gateway,omitempty"` // "Single" or "HighlyAvailable"
+	// Restricted-egress settings: proxy, additional trust bundle, image mirrors.
+	// A create request may replace any of the three.
+	Restricted *types.RestrictedNetwork `yaml:"restricted,omitempty" json:"restricted,omitempty"`
 }
 
 // ClusterNetworkConfig defines cluster network CIDR
@@ -162,6 +225,8 @@ type PlatformConfig struct {
 	Azure    *AzureConfig    `yaml:"azure,omitempty"`
 	ARO      *AROConfig      `yaml:"aro,omitempty"`
 	AKS      *AKSConfig      `yaml:"aks,omitempty"`
+	HCP      *HCPConfig      `yaml:"hcp,omitempty"`
+	Kind     *KindConfig     `yaml:"kind,omitempty"`
 }
 
 // AWSConfig contains AWS-specific settings
@@ -184,6 +249,9 @@ type ROSAConfig struct {
 	ComputeNodes int    `yaml:"computeNodes" json:"compute_nodes"`           // Number of compute nodes
 	MachineType  string `yaml:"machineType" json:"machine_type"`             // Compute instance type
 	MultiAZ      bool   `yaml:"multiAZ,omitempty" json:"multi_az,omitempty"` // Multi-AZ deployment
+	// Existing VPC subnets to install into. Required for a cluster-wide proxy
+	// or additional trust bundle, which ROSA only supports in a BYO VPC.
+	SubnetIDs []string `yaml:"subnetIds,omitempty" json:"subnet_ids,omitempty"`
 }
 
 // IBMCloudConfig contains IBM Cloud-specific settings
@@ -231,14 +299,15 @@ type GKEConfig struct {
 
 // GKENodePoolConfig defines a GKE node pool configuration
 type GKENodePoolConfig struct {
-	Name            string `yaml:"name" json:"name"`
-	MachineType     string `yaml:"machineType" json:"machine_type"`
-	DiskSizeGB      int    `yaml:"diskSizeGB" json:"disk_size_gb"`
-	DiskType        string `yaml:"diskType,omitempty" json:"disk_type,omitempty"`
-	NodeCount       int    `yaml:"nodeCount" json:"node_count"`
-	MinNodeCount    int    `yaml:"minNodeCount,omitempty" json:"min_node_count,omitempty"`
-	MaxNodeCount    int    `yaml:"maxNodeCount,omitempty" json:"max_node_count,omitempty"`
-	EnableAutoScale bool   `yaml:"enableAutoScale,omitempty" json:"enable_auto_scale,omitempty"`
+	Name            string      `yaml:"name" json:"name"`
+	MachineType     string      `yaml:"machineType" json:"machine_type"`
+	DiskSizeGB      int         `yaml:"diskSizeGB" json:"disk_size_gb"`
+	DiskType        string      `yaml:"diskType,omitempty" json:"disk_type,omitempty"`
+	NodeCount       int         `yaml:"nodeCount" json:"node_count"`
+	MinNodeCount    int         `yaml:"minNodeCount,omitempty" json:"min_node_count,omitempty"`
+	MaxNodeCount    int         `yaml:"maxNodeCount,omitempty" json:"max_node_count,omitempty"`
+	EnableAutoScale bool        `yaml:"enableAutoScale,omitempty" json:"enable_auto_scale,omitempty"`
+	Spot            *SpotConfig `yaml:"spot,omitempty" json:"spot,omitempty"`
 }
 
 // AzureConfig contains Azure-specific settings for self-managed OpenShift
@@ -264,6 +333,7 @@ type AROConfig struct {
 	MasterVMSize     string `yaml:"masterVMSize" json:"master_vm_size"` // e.g., "Standard_D8s_v3"
 	WorkerVMSize     string `yaml:"workerVMSize" json:"worker_vm_size"` // e.g., "Standard_D4s_v3"
 	WorkerCount      int    `yaml:"workerCount" json:"worker_count"`
+	WorkerDiskSizeGB int    `yaml:"workerDiskSizeGB,omitempty" json:"worker_disk_size_gb,omitempty"` // 0 = ARO default
 	OpenShiftVersion string `yaml:"openshiftVersion,omitempty" json:"openshift_version,omitempty"`
 	PullSecretPath   string `yaml:"pullSecretPath,omitempty" json:"pull_secret_path,omitempty"`
 }
@@ -278,13 +348,38 @@ type AKSConfig struct {
 
 // AKSNodePoolConfig defines an AKS node pool configuration
 type AKSNodePoolConfig struct {
-	Name            string `yaml:"name" json:"name"`
-	VMSize          string `yaml:"vmSize" json:"vm_size"` // e.g., "Standard_D4s_v3"
-	Count           int    `yaml:"count" json:"count"`
-	MinCount        int    `yaml:"minCount,omitempty" json:"min_count,omitempty"`
-	MaxCount        int    `yaml:"maxCount,omitempty" json:"max_count,omitempty"`
-	EnableAutoScale bool   `yaml:"enableAutoScale,omitempty" json:"enable_auto_scale,omitempty"`
-	OSDiskSizeGB    int    `yaml:"osDiskSizeGB,omitempty" json:"os_disk_size_gb,omitempty"`
+	Name            string      `yaml:"name" json:"name"`
+	VMSize          string      `yaml:"vmSize" json:"vm_size"` // e.g., "Standard_D4s_v3"
+	Count           int         `yaml:"count" json:"count"`
+	MinCount        int         `yaml:"minCount,omitempty" json:"min_count,omitempty"`
+	MaxCount        int         `yaml:"maxCount,omitempty" json:"max_count,omitempty"`
+	EnableAutoScale bool        `yaml:"enableAutoScale,omitempty" json:"enable_auto_scale,omitempty"`
+	OSDiskSizeGB    int         `yaml:"osDiskSizeGB,omitempty" json:"os_disk_size_gb,omitempty"`
+	Spot            *SpotConfig `yaml:"spot,omitempty" json:"spot,omitempty"` // User pools only; the first pool is the system pool
+}
+
+// HCPConfig contains settings for hosted control planes (HyperShift). The
+// control plane runs as pods on the management cluster; workers are KubeVirt
+// VMs in NodePools.
+type HCPConfig struct {
+	Namespace                    string              `yaml:"namespace,omitempty" json:"namespace,omitempty"`                                         // HostedCluster namespace, default "clusters"
+	ControllerAvailabilityPolicy string              `yaml:"controllerAvailabilityPolicy,omitempty" json:"controller_availability_policy,omitempty"` // SingleReplica, HighlyAvailable
+	NodePools                    []HCPNodePoolConfig `yaml:"nodePools" json:"node_pools"`
+}
+
+// HCPNodePoolConfig defines a hosted cluster NodePool
+type HCPNodePoolConfig struct {
+	Name         string `yaml:"name" json:"name"`
+	Replicas     int    `yaml:"replicas" json:"replicas"`
+	InstanceType string `yaml:"instanceType" json:"instance_type"` // "<cores>x<memoryGiB>", e.g. "4x16"
+	RootVolumeGB int    `yaml:"rootVolumeGB,omitempty" json:"root_volume_gb,omitempty"`
+}
+
+// KindConfig contains settings for kind clusters on the local platform. The
+// control plane and workers are containers on the worker host.
+type KindConfig struct {
+	NodeImage string `yaml:"nodeImage,omitempty" json:"node_image,omitempty"` // Overrides kindest/node:v<version>
+	Workers   int    `yaml:"workers" json:"workers"`                          // Worker node containers besides the control plane
 }
 
 // PostDeploymentConfig defines automated post-deployment configuration
@@ -299,11 +394,12 @@ type PostDeploymentConfig struct {
 
 // OperatorConfig defines an operator to install post-deployment
 type OperatorConfig struct {
-	Name           string                `yaml:"name" json:"name" validate:"required"`
-	Namespace      string                `yaml:"namespace" json:"namespace" validate:"required"`
-	Source         string                `yaml:"source,omitempty" json:"source,omitempty"` // e.g. "redhat-operators" (optional - OLM will search all catalogs if omitted)
-	Channel        string                `yaml:"channel" json:"channel" validate:"required"`
-	CustomResource *CustomResourceConfig `yaml:"customResource,omitempty" json:"custom_resource,omitempty"`
+	Name           string                 `yaml:"name" json:"name" validate:"required"`
+	Namespace      string                 `yaml:"namespace" json:"namespace" validate:"required"`
+	Source         string                 `yaml:"source,omitempty" json:"source,omitempty"` // e.g. "redhat-operators" (optional - OLM will search all catalogs if omitted)
+	Channel        string                 `yaml:"channel" json:"channel" validate:"required"`
+	CustomResource *CustomResourceConfig  `yaml:"customResource,omitempty" json:"custom_resource,omitempty"`
+	Readiness      []types.ReadinessCheck `yaml:"readiness,omitempty" json:"readiness,omitempty"` // Checks that must pass before the operator is marked installed
 }
 
 // CustomResourceConfig defines a custom resource to create after operator installation
@@ -317,27 +413,32 @@ type CustomResourceConfig struct {
 
 // ScriptConfig defines a script to execute post-deployment
 type ScriptConfig struct {
-	Name        string            `yaml:"name" json:"name" validate:"required"`
-	Path        string            `yaml:"path" json:"path" validate:"required"`
-	Description string            `yaml:"description,omitempty" json:"description,omitempty"`
-	Env         map[string]string `yaml:"env,omitempty" json:"env,omitempty"` // Additional environment variables
+	Name        string                 `yaml:"name" json:"name" validate:"required"`
+	Path        string                 `yaml:"path" json:"path" validate:"required"`
+	Description string                 `yaml:"description,omitempty" json:"description,omitempty"`
+	Env         map[string]string      `yaml:"env,omitempty" json:"env,omitempty"` // Additional environment variables
+	Readiness   []types.ReadinessCheck `yaml:"readiness,omitempty" json:"readiness,omitempty"`
 }
 
 // ManifestConfig defines a manifest file to apply post-deployment
 type ManifestConfig struct {
-	Name        string `yaml:"name" json:"name" validate:"required"`
-	Path        string `yaml:"path,omitempty" json:"path,omitempty"` // Local file path
-	URL         string `yaml:"url,omitempty" json:"url,omitempty"`   // Remote URL (e.g. GitHub raw URL)
-	Description string `yaml:"description,omitempty" json:"description,omitempty"`
-	Namespace   string `yaml:"namespace,omitempty" json:"namespace,omitempty"` // Target namespace for the manifest
+	Name        string                 `yaml:"name" json:"name" validate:"required"`
+	Path        string                 `yaml:"path,omitempty" json:"path,omitempty"` // Local file path
+	URL         string                 `yaml:"url,omitempty" json:"url,omitempty"`   // Remote URL (e.g. GitHub raw URL)
+	Description string                 `yaml:"description,omitempty" json:"description,omitempty"`
+	Namespace   string                 `yaml:"namespace,omitempty" json:"namespace,omitempty"` // Target namespace for the manifest
+	Readiness   []types.ReadinessCheck `yaml:"readiness,omitempty" json:"readiness,omitempty"`
 }
 
 // HelmChartConfig defines a Helm chart to install post-deployment
 type HelmChartConfig struct {
-	Name   string                 `yaml:"name" json:"name" validate:"required"`
-	Repo   string                 `yaml:"repo" json:"repo" validate:"required"`
-	Chart  string                 `yaml:"chart" json:"chart" validate:"required"`
-	Values map[string]interface{} `yaml:"values,omitempty" json:"values,omitempty"`
+	Name      string                 `yaml:"name" json:"name" validate:"required"`
+	Repo      string                 `yaml:"repo" json:"repo" validate:"required"`
+	Chart     string                 `yaml:"chart" json:"chart" validate:"required"`
+	Version   string                 `yaml:"version,omitempty" json:"version,omitempty"`
+	Digest    string                 `yaml:"digest,omitempty" json:"digest,omitempty"` // sha256 digest an OCI chart must match
+	Values    map[string]interface{} `yaml:"values,omitempty" json:"values,omitempty"`
+	Readiness []types.ReadinessCheck `yaml:"readiness,omitempty" json:"readiness,omitempty"`
 }
 
 // AddonReference references an addon to be installed by default
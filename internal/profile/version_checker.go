@@ -37,6 +37,10 @@ type ProfileVersionStatus struct {
 	NewVersions       []string  `json:"new_versions"` // Versions not in current list
 	UpdateCount       int       `json:"update_count"`
 	LastChecked       time.Time `json:"last_checked"`
+
+	// AddonGaps lists add-ons with no version compatible with some of the
+	// current or new versions
+	AddonGaps []types.AddonCoverageGap `json:"addon_gaps,omitempty"`
 }
 
 // VersionChecker checks for profile version updates
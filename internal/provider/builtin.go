@@ -0,0 +1,89 @@
+package provider
+
+import "github.com/tsanders-rh/ocpctl/pkg/types"
+
+// builtin holds the capabilities of every cluster type ocpctl knows about. It
+// is the single source of truth for the API, the policy engine and the worker;
+// worker providers return these from Capabilities.
+var builtin = map[types.ClusterType]Capabilities{
+	types.ClusterTypeOpenShift: {
+		DisplayName:   "OpenShift",
+		Platforms:     []types.Platform{types.PlatformAWS, types.PlatformGCP, types.PlatformIBMCloud, types.PlatformAzure},
+		BaseDomain:    BaseDomainRequired,
+		Versions:      VersionTrackOpenShift,
+		Hibernate:     true,
+		PostConfigure: true,
+		Addons:        true,
+	},
+	types.ClusterTypeROSA: {
+		DisplayName: "ROSA",
+		Platforms:   []types.Platform{types.PlatformAWS},
+		BaseDomain:  BaseDomainUnsupported,
+		ManagedDNS:  "AWS-managed DNS",
+		Hibernate:   true,
+	},
+	types.ClusterTypeEKS: {
+		DisplayName:   "EKS",
+		Platforms:     []types.Platform{types.PlatformAWS},
+		BaseDomain:    BaseDomainOptional,
+		Versions:      VersionTrackKubernetes,
+		Hibernate:     true,
+		PostConfigure: true,
+	},
+	types.ClusterTypeIKS: {
+		DisplayName:   "IKS",
+		Platforms:     []types.Platform{types.PlatformIBMCloud},
+		BaseDomain:    BaseDomainOptional,
+		Versions:      VersionTrackKubernetes,
+		Hibernate:     true,
+		PostConfigure: true,
+	},
+	types.ClusterTypeGKE: {
+		DisplayName:   "GKE",
+		Platforms:     []types.Platform{types.PlatformGCP},
+		BaseDomain:    BaseDomainOptional,
+		Hibernate:     true,
+		PostConfigure: true,
+	},
+	types.ClusterTypeARO: {
+		DisplayName: "ARO",
+		Platforms:   []types.Platform{types.PlatformAzure},
+		BaseDomain:  BaseDomainUnsupported,
+		ManagedDNS:  "Azure-managed DNS",
+		Hibernate:   true,
+	},
+	types.ClusterTypeAKS: {
+		DisplayName: "AKS",
+		Platforms:   []types.Platform{types.PlatformAzure},
+		BaseDomain:  BaseDomainUnsupported,
+		ManagedDNS:  "Azure-managed DNS",
+		Hibernate:   true,
+	},
+	// HCP runs on the management cluster, so it is valid wherever that
+	// cluster is. It publishes its API and apps routes under base_domain.
+	types.ClusterTypeHCP: {
+		DisplayName:   "HCP",
+		Platforms:     []types.Platform{types.PlatformAWS, types.PlatformGCP, types.PlatformIBMCloud, types.PlatformAzure},
+		BaseDomain:    BaseDomainRequired,
+		Versions:      VersionTrackOpenShift,
+		Hibernate:     true,
+		PostConfigure: true,
+		Addons:        true,
+	},
+	// kind runs on the worker host and has no DNS at all
+	types.ClusterTypeKind: {
+		DisplayName:   "kind",
+		Platforms:     []types.Platform{types.PlatformLocal},
+		BaseDomain:    BaseDomainUnsupported,
+		Versions:      VersionTrackKubernetes,
+		Hibernate:     true,
+		PostConfigure: true,
+		Addons:        true,
+	},
+}
+
+// Builtin returns the built-in capabilities of a cluster type
+func Builtin(clusterType types.ClusterType) (Capabilities, bool) {
+	caps, ok := builtin[clusterType]
+	return caps, ok
+}
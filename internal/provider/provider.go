@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ClusterProvider runs the lifecycle of one cluster type. The worker registers
+// one implementation per cluster type; job handlers look the provider up by
+// the cluster's type instead of switching on it.
+type ClusterProvider interface {
+	// ClusterType is the cluster type the provider handles
+	ClusterType() types.ClusterType
+
+	// Capabilities describes what the cluster type supports
+	Capabilities() Capabilities
+
+	// Preflight checks that a create can succeed before anything is
+	// provisioned. Failures should be types.PreflightCheckError so the job is
+	// not retried.
+	Preflight(ctx context.Context, cluster *types.Cluster) error
+
+	// Create provisions the cluster and records its outputs
+	Create(ctx context.Context, job *types.Job, cluster *types.Cluster) error
+
+	// Destroy removes the cluster and its cloud resources
+	Destroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error
+
+	// VerifyDestroyed returns an error if resources of a destroyed cluster are
+	// still present
+	VerifyDestroyed(ctx context.Context, cluster *types.Cluster) error
+
+	// Hibernate stops the cluster's compute to save cost
+	Hibernate(ctx context.Context, job *types.Job, cluster *types.Cluster) error
+
+	// Resume brings a hibernated cluster back to READY
+	Resume(ctx context.Context, job *types.Job, cluster *types.Cluster) error
+
+	// FetchOutputs returns the cluster's access details (API URL, kubeconfig
+	// location, console)
+	FetchOutputs(ctx context.Context, cluster *types.Cluster) (*types.ClusterOutputs, error)
+
+	// CleanupPartial removes whatever a failed create left behind so the job
+	// can be retried from a clean slate. It is best effort and only logs
+	// failures.
+	CleanupPartial(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string)
+}
+
+// BaseDomainPolicy says whether a cluster type takes a base_domain
+type BaseDomainPolicy string
+
+const (
+	// BaseDomainRequired means the cluster publishes DNS under base_domain
+	BaseDomainRequired BaseDomainPolicy = "required"
+	// BaseDomainOptional means base_domain is accepted but not used
+	BaseDomainOptional BaseDomainPolicy = "optional"
+	// BaseDomainUnsupported means DNS is managed elsewhere and base_domain is rejected
+	BaseDomainUnsupported BaseDomainPolicy = "unsupported"
+)
+
+// VersionTrack names the profile version allowlist a cluster type is checked
+// against
+type VersionTrack string
+
+const (
+	// VersionTrackOpenShift checks against the profile's openshiftVersions
+	VersionTrackOpenShift VersionTrack = "openshift"
+	// VersionTrackKubernetes checks against the profile's kubernetesVersions
+	VersionTrackKubernetes VersionTrack = "kubernetes"
+	// VersionTrackNone means the version is not checked against the profile
+	VersionTrackNone VersionTrack = ""
+)
+
+// Capabilities describes what a cluster type supports. The API and policy
+// engine validate requests against it and the worker checks it before
+// running a job.
+type Capabilities struct {
+	DisplayName string           // Name used in messages, e.g. "ROSA"
+	Platforms   []types.Platform // Platforms the cluster type can be created on
+
+	BaseDomain BaseDomainPolicy
+	ManagedDNS string // Who manages DNS when base_domain is unsupported, for messages
+
+	Versions VersionTrack
+
+	Hibernate     bool // Hibernate and resume jobs are supported
+	PostConfigure bool // Custom post-config and add-ons can be applied
+	Addons        bool // Add-ons can be installed, upgraded and uninstalled after creation, and checked for drift
+}
+
+// SupportsPlatform reports whether the cluster type can be created on a
+// platform
+func (c Capabilities) SupportsPlatform(platform types.Platform) bool {
+	for _, p := range c.Platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
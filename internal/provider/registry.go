@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ErrNoProvider is returned when no provider is registered for a cluster type
+var ErrNoProvider = errors.New("no provider registered")
+
+// Registry maps cluster types to their providers and capabilities. A new
+// registry knows the built-in capabilities, so the API can validate requests
+// without any providers registered; the worker registers the implementations.
+type Registry struct {
+	mu           sync.RWMutex
+	capabilities map[types.ClusterType]Capabilities
+	providers    map[types.ClusterType]ClusterProvider
+}
+
+// NewRegistry creates a registry seeded with the built-in capabilities
+func NewRegistry() *Registry {
+	r := &Registry{
+		capabilities: make(map[types.ClusterType]Capabilities, len(builtin)),
+		providers:    make(map[types.ClusterType]ClusterProvider),
+	}
+	for ct, caps := range builtin {
+		r.capabilities[ct] = caps
+	}
+	return r
+}
+
+// Register adds a provider, replacing any earlier one for the same cluster
+// type. The provider's capabilities replace the registry's entry.
+func (r *Registry) Register(p ClusterProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[p.ClusterType()] = p
+	r.capabilities[p.ClusterType()] = p.Capabilities()
+}
+
+// Get returns the provider for a cluster type
+func (r *Registry) Get(clusterType types.ClusterType) (ClusterProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[clusterType]
+	if !ok {
+		return nil, fmt.Errorf("%w for cluster type %s", ErrNoProvider, clusterType)
+	}
+	return p, nil
+}
+
+// Capabilities returns the capabilities of a cluster type
+func (r *Registry) Capabilities(clusterType types.ClusterType) (Capabilities, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	caps, ok := r.capabilities[clusterType]
+	return caps, ok
+}
+
+// ClusterTypes returns the known cluster types in name order
+func (r *Registry) ClusterTypes() []types.ClusterType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]types.ClusterType, 0, len(r.capabilities))
+	for ct := range r.capabilities {
+		out = append(out, ct)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// ClusterTypesFor returns the cluster types that can be created on a platform,
+// in name order
+func (r *Registry) ClusterTypesFor(platform types.Platform) []types.ClusterType {
+	var out []types.ClusterType
+	for _, ct := range r.ClusterTypes() {
+		if caps, _ := r.Capabilities(ct); caps.SupportsPlatform(platform) {
+			out = append(out, ct)
+		}
+	}
+	return out
+}
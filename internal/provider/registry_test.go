@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// fakeProvider is a ClusterProvider that does nothing
+type fakeProvider struct {
+	clusterType  types.ClusterType
+	capabilities Capabilities
+}
+
+func (f *fakeProvider) ClusterType() types.ClusterType { return f.clusterType }
+func (f *fakeProvider) Capabilities() Capabilities     { return f.capabilities }
+func (f *fakeProvider) Preflight(context.Context, *types.Cluster) error {
+	return nil
+}
+func (f *fakeProvider) Create(context.Context, *types.Job, *types.Cluster) error  { return nil }
+func (f *fakeProvider) Destroy(context.Context, *types.Job, *types.Cluster) error { return nil }
+func (f *fakeProvider) VerifyDestroyed(context.Context, *types.Cluster) error {
+	return nil
+}
+func (f *fakeProvider) Hibernate(context.Context, *types.Job, *types.Cluster) error { return nil }
+func (f *fakeProvider) Resume(context.Context, *types.Job, *types.Cluster) error    { return nil }
+func (f *fakeProvider) FetchOutputs(context.Context, *types.Cluster) (*types.ClusterOutputs, error) {
+	return nil, nil
+}
+func (f *fakeProvider) CleanupPartial(context.Context, *types.Job, *types.Cluster, string) {}
+
+func TestBuiltin_CoversEveryClusterType(t *testing.T) {
+	for _, ct := range []types.ClusterType{
+		types.ClusterTypeOpenShift, types.ClusterTypeROSA, types.ClusterTypeEKS,
+		types.ClusterTypeIKS, types.ClusterTypeGKE, types.ClusterTypeARO,
+		types.ClusterTypeAKS, types.ClusterTypeHCP, types.ClusterTypeKind,
+	} {
+		caps, ok := Builtin(ct)
+		require.True(t, ok, "no built-in capabilities for %s", ct)
+		assert.NotEmpty(t, caps.DisplayName, ct)
+		assert.NotEmpty(t, caps.Platforms, ct)
+		assert.NotEmpty(t, caps.BaseDomain, ct)
+		if caps.BaseDomain != BaseDomainUnsupported {
+			assert.Empty(t, caps.ManagedDNS, ct)
+		}
+		// Add-ons are applied by the post-configure tasks
+		if caps.Addons {
+			assert.True(t, caps.PostConfigure, ct)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	// Capabilities are known before any provider is registered
+	caps, ok := r.Capabilities(types.ClusterTypeKind)
+	require.True(t, ok)
+	assert.True(t, caps.SupportsPlatform(types.PlatformLocal))
+	assert.False(t, caps.SupportsPlatform(types.PlatformAWS))
+
+	_, err := r.Get(types.ClusterTypeKind)
+	assert.ErrorIs(t, err, ErrNoProvider)
+
+	_, ok = r.Capabilities("unknown")
+	assert.False(t, ok)
+
+	assert.Equal(t, []types.ClusterType{types.ClusterTypeKind}, r.ClusterTypesFor(types.PlatformLocal))
+	assert.Equal(t,
+		[]types.ClusterType{types.ClusterTypeEKS, types.ClusterTypeHCP, types.ClusterTypeOpenShift, types.ClusterTypeROSA},
+		r.ClusterTypesFor(types.PlatformAWS))
+	assert.Empty(t, r.ClusterTypesFor("mainframe"))
+
+	// A registered provider's capabilities replace the built-in entry
+	p := &fakeProvider{
+		clusterType:  types.ClusterTypeKind,
+		capabilities: Capabilities{DisplayName: "kind", Platforms: []types.Platform{types.PlatformLocal}},
+	}
+	r.Register(p)
+
+	got, err := r.Get(types.ClusterTypeKind)
+	require.NoError(t, err)
+	assert.Same(t, p, got)
+	caps, _ = r.Capabilities(types.ClusterTypeKind)
+	assert.False(t, caps.Hibernate)
+
+	// New cluster types can be added by registering a provider
+	r.Register(&fakeProvider{clusterType: "k3s", capabilities: Capabilities{Platforms: []types.Platform{types.PlatformLocal}}})
+	assert.Equal(t, []types.ClusterType{"k3s", types.ClusterTypeKind}, r.ClusterTypesFor(types.PlatformLocal))
+	assert.Contains(t, r.ClusterTypes(), types.ClusterType("k3s"))
+}
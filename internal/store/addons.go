@@ -213,6 +213,9 @@ func (s *PostConfigAddonStore) GetByAddonID(ctx context.Context, addonID string)
 		&addon.UpdatedAt,
 	)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
 		return nil, fmt.Errorf("get add-on by addon_id: %w", err)
 	}
 
@@ -0,0 +1,311 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+const (
+	// EventActorSystem is recorded when a transition has no explicit source
+	EventActorSystem = "system"
+	// EventActorWorker is recorded for transitions made while processing a job
+	EventActorWorker = "worker"
+
+	// maxEventReasonLength bounds how much of an error message is kept as a reason
+	maxEventReasonLength = 1000
+)
+
+// EventSource describes who caused a cluster state change and why.
+// It travels on the context so that the ClusterStore and PoolStore methods
+// that change state can record it without extra parameters.
+type EventSource struct {
+	Actor  string // User email, EventActorWorker or EventActorSystem
+	Reason string
+	JobID  string
+}
+
+type eventSourceKey struct{}
+
+// WithEventSource returns a context whose cluster state changes are attributed to src
+func WithEventSource(ctx context.Context, src EventSource) context.Context {
+	return context.WithValue(ctx, eventSourceKey{}, src)
+}
+
+// WithEventReason returns a context that keeps the current event source but
+// records a different reason
+func WithEventReason(ctx context.Context, reason string) context.Context {
+	src := EventSourceFromContext(ctx)
+	src.Reason = reason
+	return WithEventSource(ctx, src)
+}
+
+// EventSourceFromContext returns the event source on ctx, defaulting the actor
+// to EventActorSystem
+func EventSourceFromContext(ctx context.Context) EventSource {
+	src, _ := ctx.Value(eventSourceKey{}).(EventSource)
+	if src.Actor == "" {
+		src.Actor = EventActorSystem
+	}
+	if len(src.Reason) > maxEventReasonLength {
+		src.Reason = src.Reason[:maxEventReasonLength]
+	}
+	return src
+}
+
+// eventArgs returns the actor, reason and job ID query arguments for src,
+// with empty optional values converted to NULL
+func eventArgs(src EventSource) (string, *string, *string) {
+	var reason, jobID *string
+	if src.Reason != "" {
+		reason = &src.Reason
+	}
+	if src.JobID != "" {
+		jobID = &src.JobID
+	}
+	return src.Actor, reason, jobID
+}
+
+// ClusterEventStore handles cluster lifecycle event queries. Events are
+// written by ClusterStore and PoolStore as part of each state change.
+type ClusterEventStore struct {
+	pool *pgxpool.Pool
+}
+
+const clusterEventColumns = `
+	id, cluster_id, cluster_name, event_type, COALESCE(from_status, ''), to_status,
+	actor, COALESCE(reason, ''), COALESCE(job_id, ''), created_at
+`
+
+// scanClusterEvent scans one cluster event, followed by any extra columns
+func scanClusterEvent(rows pgx.Rows, extra ...any) (*types.ClusterEvent, error) {
+	e := &types.ClusterEvent{}
+	dest := append([]any{
+		&e.ID,
+		&e.ClusterID,
+		&e.ClusterName,
+		&e.Type,
+		&e.FromStatus,
+		&e.ToStatus,
+		&e.Actor,
+		&e.Reason,
+		&e.JobID,
+		&e.CreatedAt,
+	}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, fmt.Errorf("scan cluster event: %w", err)
+	}
+	return e, nil
+}
+
+// scanClusterEvents collects cluster events from rows
+func scanClusterEvents(rows pgx.Rows) ([]*types.ClusterEvent, error) {
+	defer rows.Close()
+
+	events := []*types.ClusterEvent{}
+	for rows.Next() {
+		e, err := scanClusterEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate cluster events: %w", err)
+	}
+
+	return events, nil
+}
+
+// ListByCluster returns a cluster's events in order, starting after afterID
+func (s *ClusterEventStore) ListByCluster(ctx context.Context, clusterID string, afterID int64, limit int) ([]*types.ClusterEvent, error) {
+	query := `SELECT ` + clusterEventColumns + `
+		FROM cluster_events
+		WHERE cluster_id = $1 AND id > $2
+		ORDER BY id ASC
+		LIMIT $3
+	`
+
+	rows, err := s.pool.Query(ctx, query, clusterID, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list cluster events: %w", err)
+	}
+
+	return scanClusterEvents(rows)
+}
+
+// EventCursor is a position in the stream of events across all clusters.
+// Events are written inside state change transactions that can commit out of
+// ID order, so the stream is ordered by the writing transaction and then by
+// ID, and only includes events whose transactions are older than every
+// transaction still running. An event that commits late therefore always
+// sorts after the cursor of a reader that could not yet see it.
+type EventCursor struct {
+	TxID int64
+	ID   int64
+}
+
+// eventHorizon limits a query to events whose transactions can no longer be
+// followed by an earlier-ordered commit
+const eventHorizon = `tx_id < pg_snapshot_xmin(pg_current_snapshot())`
+
+// ListSince returns up to limit events after the cursor, with the cursor of
+// the last event returned. Events from transactions that started before a
+// still-running one are held back until it finishes. If ownerID is non-empty
+// only events for clusters owned by that user are returned.
+func (s *ClusterEventStore) ListSince(ctx context.Context, after EventCursor, ownerID string, limit int) ([]*types.ClusterEvent, EventCursor, error) {
+	var owner *string
+	if ownerID != "" {
+		owner = &ownerID
+	}
+
+	query := `SELECT ` + clusterEventColumns + `, tx_id::text::bigint
+		FROM cluster_events
+		WHERE (tx_id, id) > ($1::bigint::text::xid8, $2)
+			AND ` + eventHorizon + `
+			AND ($3::uuid IS NULL OR cluster_id IN (SELECT id FROM clusters WHERE owner_id = $3::uuid))
+		ORDER BY tx_id ASC, id ASC
+		LIMIT $4
+	`
+
+	rows, err := s.pool.Query(ctx, query, after.TxID, after.ID, owner, limit)
+	if err != nil {
+		return nil, after, fmt.Errorf("list cluster events since %d: %w", after.ID, err)
+	}
+	defer rows.Close()
+
+	events := []*types.ClusterEvent{}
+	for rows.Next() {
+		var txID int64
+		e, err := scanClusterEvent(rows, &txID)
+		if err != nil {
+			return nil, after, err
+		}
+		events = append(events, e)
+		after = EventCursor{TxID: txID, ID: e.ID}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, after, fmt.Errorf("iterate cluster events: %w", err)
+	}
+
+	return events, after, nil
+}
+
+// CursorAt returns the stream position of an event, or of the closest
+// earlier event if it no longer exists. The zero cursor precedes every event.
+func (s *ClusterEventStore) CursorAt(ctx context.Context, eventID int64) (EventCursor, error) {
+	var cursor EventCursor
+	err := s.pool.QueryRow(ctx, `
+		SELECT tx_id::text::bigint, id FROM cluster_events
+		WHERE id <= $1
+		ORDER BY id DESC
+		LIMIT 1
+	`, eventID).Scan(&cursor.TxID, &cursor.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return EventCursor{}, fmt.Errorf("get cluster event cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// LatestCursor returns the position after the last event that ListSince can
+// return now. Events committed just before the call by transactions newer
+// than a running one are returned after it.
+func (s *ClusterEventStore) LatestCursor(ctx context.Context) (EventCursor, error) {
+	var cursor EventCursor
+	err := s.pool.QueryRow(ctx, `
+		SELECT tx_id::text::bigint, id FROM cluster_events
+		WHERE `+eventHorizon+`
+		ORDER BY tx_id DESC, id DESC
+		LIMIT 1
+	`).Scan(&cursor.TxID, &cursor.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return EventCursor{}, fmt.Errorf("get latest cluster event cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// publishLease is how long claimed events are reserved for the worker
+// publishing them. It covers a full batch of slow publishes; events claimed by
+// a worker that stops mid-batch are picked up by another once it expires.
+const publishLease = 10 * time.Minute
+
+// PublishPending calls publish for up to limit unpublished events, oldest
+// first, and records the outcome of each call. Events that have failed
+// maxAttempts times are skipped. Events are claimed with a lease that is
+// committed before publishing, so several workers can run the publisher
+// without sending an event twice and no transaction stays open while
+// publish runs. Events are selected by publish state rather than an ID
+// cursor, so one whose transaction commits after later IDs were published is
+// still picked up. Returns the number of events published successfully.
+func (s *ClusterEventStore) PublishPending(ctx context.Context, limit, maxAttempts int, publish func(context.Context, *types.ClusterEvent) error) (int, error) {
+	query := `UPDATE cluster_events
+		SET publishing_until = NOW() + make_interval(secs => $3)
+		WHERE id IN (
+			SELECT id FROM cluster_events
+			WHERE published_at IS NULL AND publish_attempts < $1
+				AND (publishing_until IS NULL OR publishing_until < NOW())
+			ORDER BY id ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + clusterEventColumns
+
+	rows, err := s.pool.Query(ctx, query, maxAttempts, limit, publishLease.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("claim unpublished cluster events: %w", err)
+	}
+
+	events, err := scanClusterEvents(rows)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+
+	publishErrs := make([]error, len(events))
+	for i, e := range events {
+		publishErrs[i] = publish(ctx, e)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin publish result transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	published := 0
+	for i, e := range events {
+		if pubErr := publishErrs[i]; pubErr != nil {
+			_, err = tx.Exec(ctx, `
+				UPDATE cluster_events
+				SET publish_attempts = publish_attempts + 1, last_publish_error = $1, publishing_until = NULL
+				WHERE id = $2
+			`, pubErr.Error(), e.ID)
+		} else {
+			published++
+			_, err = tx.Exec(ctx, `
+				UPDATE cluster_events
+				SET published_at = NOW(), publish_attempts = publish_attempts + 1, last_publish_error = NULL, publishing_until = NULL
+				WHERE id = $1
+			`, e.ID)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("record publish result for cluster event %d: %w", e.ID, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit publish results: %w", err)
+	}
+
+	return published, nil
+}
@@ -0,0 +1,216 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/dbtest"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestClusterEventStore_RecordsTransitions(t *testing.T) {
+	s := dbtest.New(t)
+	ctx := context.Background()
+
+	cluster := &types.Cluster{
+		ID:          uuid.New().String(),
+		Name:        "events-" + uuid.New().String()[:8],
+		Platform:    types.PlatformAWS,
+		ClusterType: types.ClusterTypeOpenShift,
+		Version:     "4.20",
+		Profile:     "aws-sno-ga",
+		Region:      "us-east-1",
+		Owner:       "owner@example.com",
+		Team:        "test",
+		CostCenter:  "test",
+		Status:      types.ClusterStatusPending,
+		RequestedBy: "owner@example.com",
+		TTLHours:    24,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	require.NoError(t, s.Clusters.Create(ctx, cluster))
+
+	jobCtx := store.WithEventSource(ctx, store.EventSource{
+		Actor:  store.EventActorWorker,
+		Reason: "CREATE job",
+		JobID:  "job-1",
+	})
+	require.NoError(t, s.Clusters.UpdateStatus(jobCtx, nil, cluster.ID, types.ClusterStatusCreating))
+	// Setting the same status again is not a transition
+	require.NoError(t, s.Clusters.UpdateStatus(jobCtx, nil, cluster.ID, types.ClusterStatusCreating))
+	require.NoError(t, s.Clusters.UpdateStatus(store.WithEventReason(jobCtx, "install finished"), nil, cluster.ID, types.ClusterStatusReady))
+	require.NoError(t, s.Clusters.MarkDestroyed(ctx, cluster.ID))
+
+	require.ErrorIs(t, s.Clusters.UpdateStatus(ctx, nil, uuid.New().String(), types.ClusterStatusReady), store.ErrNotFound)
+
+	events, err := s.ClusterEvents.ListByCluster(ctx, cluster.ID, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	require.Equal(t, types.ClusterEventCreated, events[0].Type)
+	require.Equal(t, "", events[0].FromStatus)
+	require.Equal(t, "PENDING", events[0].ToStatus)
+	require.Equal(t, "owner@example.com", events[0].Actor)
+
+	require.Equal(t, types.ClusterEventStatusChanged, events[1].Type)
+	require.Equal(t, "PENDING", events[1].FromStatus)
+	require.Equal(t, "CREATING", events[1].ToStatus)
+	require.Equal(t, store.EventActorWorker, events[1].Actor)
+	require.Equal(t, "CREATE job", events[1].Reason)
+	require.Equal(t, "job-1", events[1].JobID)
+
+	require.Equal(t, "READY", events[2].ToStatus)
+	require.Equal(t, "install finished", events[2].Reason)
+
+	require.Equal(t, "DESTROYED", events[3].ToStatus)
+	require.Equal(t, store.EventActorSystem, events[3].Actor)
+
+	// Cursor pagination resumes after the given event
+	rest, err := s.ClusterEvents.ListByCluster(ctx, cluster.ID, events[1].ID, 100)
+	require.NoError(t, err)
+	require.Len(t, rest, 2)
+}
+
+// createEventTestCluster creates a PENDING cluster, recording its CREATED event
+func createEventTestCluster(t *testing.T, s *store.Store) *types.Cluster {
+	t.Helper()
+	cluster := &types.Cluster{
+		ID:          uuid.New().String(),
+		Name:        "events-" + uuid.New().String()[:8],
+		Platform:    types.PlatformAWS,
+		ClusterType: types.ClusterTypeOpenShift,
+		Version:     "4.20",
+		Profile:     "aws-sno-ga",
+		Region:      "us-east-1",
+		Owner:       "owner@example.com",
+		Team:        "test",
+		CostCenter:  "test",
+		Status:      types.ClusterStatusPending,
+		RequestedBy: "owner@example.com",
+		TTLHours:    24,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	require.NoError(t, s.Clusters.Create(context.Background(), cluster))
+	return cluster
+}
+
+func TestClusterEventStore_ListSinceWaitsForEarlierTransactions(t *testing.T) {
+	s := dbtest.New(t)
+	ctx := context.Background()
+	slow, fast := createEventTestCluster(t, s), createEventTestCluster(t, s)
+
+	cursor, err := s.ClusterEvents.LatestCursor(ctx)
+	require.NoError(t, err)
+
+	// The slow transaction takes the lower event ID but commits last
+	tx, err := s.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+	require.NoError(t, s.Clusters.UpdateStatus(ctx, tx, slow.ID, types.ClusterStatusCreating))
+	require.NoError(t, s.Clusters.UpdateStatus(ctx, nil, fast.ID, types.ClusterStatusCreating))
+
+	events, next, err := s.ClusterEvents.ListSince(ctx, cursor, "", 100)
+	require.NoError(t, err)
+	require.Empty(t, events, "events committed after a running transaction's are held back")
+	require.Equal(t, cursor, next)
+
+	require.NoError(t, tx.Commit(ctx))
+
+	events, next, err = s.ClusterEvents.ListSince(ctx, cursor, "", 100)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, slow.ID, events[0].ClusterID)
+	require.Equal(t, fast.ID, events[1].ClusterID)
+	require.Less(t, events[0].ID, events[1].ID)
+
+	events, _, err = s.ClusterEvents.ListSince(ctx, next, "", 100)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// Resuming from an event ID continues at the same position
+	resumed, err := s.ClusterEvents.CursorAt(ctx, next.ID)
+	require.NoError(t, err)
+	require.Equal(t, next, resumed)
+}
+
+func TestClusterEventStore_PublishPendingPublishesLateCommits(t *testing.T) {
+	s := dbtest.New(t)
+	ctx := context.Background()
+
+	published := map[int64]bool{}
+	drain := func() {
+		for {
+			n, err := s.ClusterEvents.PublishPending(ctx, 100, 5, func(_ context.Context, e *types.ClusterEvent) error {
+				published[e.ID] = true
+				return nil
+			})
+			require.NoError(t, err)
+			if n == 0 {
+				return
+			}
+		}
+	}
+	drain()
+
+	// The status change takes the lower event ID but commits after a later
+	// event has been published
+	cluster := createEventTestCluster(t, s)
+	tx, err := s.BeginTx(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+	require.NoError(t, s.Clusters.UpdateStatus(ctx, tx, cluster.ID, types.ClusterStatusCreating))
+	later := createEventTestCluster(t, s)
+	drain()
+
+	require.NoError(t, tx.Commit(ctx))
+	drain()
+
+	for _, id := range []string{cluster.ID, later.ID} {
+		events, err := s.ClusterEvents.ListByCluster(ctx, id, 0, 100)
+		require.NoError(t, err)
+		for _, e := range events {
+			require.True(t, published[e.ID], "event %d should be published", e.ID)
+		}
+	}
+}
+
+func TestClusterEventStore_PublishPendingDoesNotHoldBackStream(t *testing.T) {
+	s := dbtest.New(t)
+	ctx := context.Background()
+	createEventTestCluster(t, s)
+
+	// Events written while a publish is in flight are readable from the
+	// stream right away, and the event being published is not handed out twice
+	var during *types.Cluster
+	_, err := s.ClusterEvents.PublishPending(ctx, 1, 5, func(_ context.Context, e *types.ClusterEvent) error {
+		if during != nil {
+			return nil
+		}
+		during = createEventTestCluster(t, s)
+
+		events, err := s.ClusterEvents.ListByCluster(ctx, during.ID, 0, 1)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		want, err := s.ClusterEvents.CursorAt(ctx, events[0].ID)
+		require.NoError(t, err)
+		latest, err := s.ClusterEvents.LatestCursor(ctx)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, latest.TxID, want.TxID, "event written during publish should be past the horizon")
+
+		n, err := s.ClusterEvents.PublishPending(ctx, 100, 5, func(_ context.Context, other *types.ClusterEvent) error {
+			require.NotEqual(t, e.ID, other.ID, "claimed event should not be published again")
+			return nil
+		})
+		require.NoError(t, err)
+		require.Positive(t, n)
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, during)
+}
@@ -0,0 +1,322 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ClusterSpecStore handles declarative cluster spec database operations
+type ClusterSpecStore struct {
+	pool *pgxpool.Pool
+}
+
+const clusterSpecColumns = `
+	id, name, source, git_url, git_ref, git_path, sync_interval_minutes, prune,
+	document, revision, last_plan, last_sync_status, last_sync_error, last_synced_at,
+	owner_id, created_at, updated_at
+`
+
+// scanClusterSpec scans a row selected with clusterSpecColumns
+func scanClusterSpec(row pgx.Row) (*types.ClusterSpec, error) {
+	spec := &types.ClusterSpec{}
+	err := row.Scan(
+		&spec.ID,
+		&spec.Name,
+		&spec.Source,
+		&spec.GitURL,
+		&spec.GitRef,
+		&spec.GitPath,
+		&spec.SyncIntervalMinutes,
+		&spec.Prune,
+		&spec.Document,
+		&spec.Revision,
+		&spec.LastPlan,
+		&spec.LastSyncStatus,
+		&spec.LastSyncError,
+		&spec.LastSyncedAt,
+		&spec.OwnerID,
+		&spec.CreatedAt,
+		&spec.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// listClusterSpecs runs a query selecting clusterSpecColumns
+func (s *ClusterSpecStore) listClusterSpecs(ctx context.Context, query string, args ...interface{}) ([]*types.ClusterSpec, error) {
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list cluster specs: %w", err)
+	}
+	defer rows.Close()
+
+	specs := []*types.ClusterSpec{}
+	for rows.Next() {
+		spec, err := scanClusterSpec(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan cluster spec: %w", err)
+		}
+		specs = append(specs, spec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate cluster specs: %w", err)
+	}
+
+	return specs, nil
+}
+
+// Create inserts a new cluster spec. Returns ErrConflict if the name is taken.
+func (s *ClusterSpecStore) Create(ctx context.Context, spec *types.ClusterSpec) error {
+	query := `
+		INSERT INTO cluster_specs (
+			name, source, git_url, git_ref, git_path, sync_interval_minutes, prune,
+			document, revision, owner_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		RETURNING id, last_sync_status, created_at, updated_at
+	`
+
+	err := s.pool.QueryRow(ctx, query,
+		spec.Name,
+		spec.Source,
+		spec.GitURL,
+		spec.GitRef,
+		spec.GitPath,
+		spec.SyncIntervalMinutes,
+		spec.Prune,
+		spec.Document,
+		spec.Revision,
+		spec.OwnerID,
+	).Scan(&spec.ID, &spec.LastSyncStatus, &spec.CreatedAt, &spec.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrConflict
+		}
+		return fmt.Errorf("create cluster spec: %w", err)
+	}
+
+	return nil
+}
+
+// GetByName retrieves a cluster spec by name
+func (s *ClusterSpecStore) GetByName(ctx context.Context, name string) (*types.ClusterSpec, error) {
+	query := `SELECT ` + clusterSpecColumns + ` FROM cluster_specs WHERE name = $1`
+
+	spec, err := scanClusterSpec(s.pool.QueryRow(ctx, query, name))
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get cluster spec: %w", err)
+	}
+
+	return spec, nil
+}
+
+// List returns cluster specs ordered by name. If ownerID is non-empty only
+// that user's specs are returned.
+func (s *ClusterSpecStore) List(ctx context.Context, ownerID string) ([]*types.ClusterSpec, error) {
+	if ownerID == "" {
+		return s.listClusterSpecs(ctx, `SELECT `+clusterSpecColumns+` FROM cluster_specs ORDER BY name`)
+	}
+	return s.listClusterSpecs(ctx, `SELECT `+clusterSpecColumns+` FROM cluster_specs WHERE owner_id = $1 ORDER BY name`, ownerID)
+}
+
+// ListDueForSync returns git specs whose sync interval has elapsed and that
+// are not being synced by another replica
+func (s *ClusterSpecStore) ListDueForSync(ctx context.Context) ([]*types.ClusterSpec, error) {
+	query := `SELECT ` + clusterSpecColumns + `
+		FROM cluster_specs
+		WHERE source = 'git'
+			AND (last_synced_at IS NULL OR last_synced_at < NOW() - make_interval(mins => sync_interval_minutes))
+			AND (sync_started_at IS NULL OR sync_started_at < NOW() - interval '15 minutes')
+		ORDER BY last_synced_at NULLS FIRST
+	`
+	return s.listClusterSpecs(ctx, query)
+}
+
+// Update saves the source and sync settings of a cluster spec
+func (s *ClusterSpecStore) Update(ctx context.Context, spec *types.ClusterSpec) error {
+	query := `
+		UPDATE cluster_specs
+		SET git_url = $1, git_ref = $2, git_path = $3, sync_interval_minutes = $4, prune = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING updated_at
+	`
+
+	err := s.pool.QueryRow(ctx, query,
+		spec.GitURL,
+		spec.GitRef,
+		spec.GitPath,
+		spec.SyncIntervalMinutes,
+		spec.Prune,
+		spec.ID,
+	).Scan(&spec.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update cluster spec: %w", err)
+	}
+
+	return nil
+}
+
+// SetDocument stores a new desired-state document and its revision
+func (s *ClusterSpecStore) SetDocument(ctx context.Context, id string, doc *types.ClusterSpecDocument, revision string) error {
+	query := `
+		UPDATE cluster_specs
+		SET document = $1, revision = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	result, err := s.pool.Exec(ctx, query, doc, revision, id)
+	if err != nil {
+		return fmt.Errorf("set cluster spec document: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// TryBeginSync marks a spec as being synced. It returns false if another
+// replica started a sync less than 15 minutes ago.
+func (s *ClusterSpecStore) TryBeginSync(ctx context.Context, id string) (bool, error) {
+	query := `
+		UPDATE cluster_specs
+		SET sync_started_at = NOW()
+		WHERE id = $1
+			AND (sync_started_at IS NULL OR sync_started_at < NOW() - interval '15 minutes')
+	`
+
+	result, err := s.pool.Exec(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("begin cluster spec sync: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// FinishSync records the outcome of a sync and releases the sync marker
+func (s *ClusterSpecStore) FinishSync(ctx context.Context, id string, status types.ClusterSpecSyncStatus, plan *types.ClusterSpecPlan, syncErr *string) error {
+	query := `
+		UPDATE cluster_specs
+		SET last_sync_status = $1, last_plan = $2, last_sync_error = $3,
+			last_synced_at = NOW(), sync_started_at = NULL
+		WHERE id = $4
+	`
+
+	if _, err := s.pool.Exec(ctx, query, status, plan, syncErr, id); err != nil {
+		return fmt.Errorf("finish cluster spec sync: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a cluster spec. Resources it created are left running.
+func (s *ClusterSpecStore) Delete(ctx context.Context, id string) error {
+	result, err := s.pool.Exec(ctx, `DELETE FROM cluster_specs WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete cluster spec: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListResources returns the resources managed by a spec
+func (s *ClusterSpecStore) ListResources(ctx context.Context, specID string) ([]*types.ClusterSpecResource, error) {
+	query := `
+		SELECT spec_id, kind, name, resource_id, created_at
+		FROM cluster_spec_resources
+		WHERE spec_id = $1
+		ORDER BY kind, name
+	`
+
+	rows, err := s.pool.Query(ctx, query, specID)
+	if err != nil {
+		return nil, fmt.Errorf("list cluster spec resources: %w", err)
+	}
+	defer rows.Close()
+
+	resources := []*types.ClusterSpecResource{}
+	for rows.Next() {
+		r := &types.ClusterSpecResource{}
+		if err := rows.Scan(&r.SpecID, &r.Kind, &r.Name, &r.ResourceID, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan cluster spec resource: %w", err)
+		}
+		resources = append(resources, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate cluster spec resources: %w", err)
+	}
+
+	return resources, nil
+}
+
+// PutResource records that a spec manages a resource, replacing any previous
+// record with the same kind and name
+func (s *ClusterSpecStore) PutResource(ctx context.Context, r *types.ClusterSpecResource) error {
+	query := `
+		INSERT INTO cluster_spec_resources (spec_id, kind, name, resource_id, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (spec_id, kind, name)
+		DO UPDATE SET resource_id = EXCLUDED.resource_id, created_at = NOW()
+		RETURNING created_at
+	`
+
+	if err := s.pool.QueryRow(ctx, query, r.SpecID, r.Kind, r.Name, r.ResourceID).Scan(&r.CreatedAt); err != nil {
+		return fmt.Errorf("put cluster spec resource: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteResource stops a spec from managing a resource
+func (s *ClusterSpecStore) DeleteResource(ctx context.Context, specID string, kind types.ClusterSpecResourceKind, name string) error {
+	query := `DELETE FROM cluster_spec_resources WHERE spec_id = $1 AND kind = $2 AND name = $3`
+	if _, err := s.pool.Exec(ctx, query, specID, kind, name); err != nil {
+		return fmt.Errorf("delete cluster spec resource: %w", err)
+	}
+	return nil
+}
+
+// FindActiveClusterIDs maps each name to the ID of a cluster with that name
+// that is not DESTROYED. Names without such a cluster are omitted.
+func (s *ClusterSpecStore) FindActiveClusterIDs(ctx context.Context, names []string) (map[string]string, error) {
+	ids := make(map[string]string, len(names))
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	query := `SELECT name, id FROM clusters WHERE name = ANY($1) AND status <> 'DESTROYED'`
+
+	rows, err := s.pool.Query(ctx, query, names)
+	if err != nil {
+		return nil, fmt.Errorf("find active clusters by name: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, id string
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, fmt.Errorf("scan cluster id: %w", err)
+		}
+		ids[name] = id
+	}
+
+	return ids, rows.Err()
+}
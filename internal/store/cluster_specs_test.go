@@ -0,0 +1,128 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/dbtest"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestClusterSpecStore_Lifecycle(t *testing.T) {
+	s := dbtest.New(t)
+	ctx := context.Background()
+
+	id := uuid.New().String()
+	owner := &types.User{
+		ID:           id,
+		Email:        id + "@example.com",
+		Username:     id,
+		PasswordHash: "x",
+		Role:         types.RoleUser,
+		Timezone:     "UTC",
+		WorkDays:     62,
+		Active:       true,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	require.NoError(t, s.Users.Create(ctx, owner))
+
+	gitURL := "https://git.example.com/org/specs.git"
+	spec := &types.ClusterSpec{
+		Name:                "spec-" + uuid.New().String()[:8],
+		Source:              types.ClusterSpecSourceGit,
+		GitURL:              &gitURL,
+		SyncIntervalMinutes: 5,
+		OwnerID:             owner.ID,
+	}
+	require.NoError(t, s.ClusterSpecs.Create(ctx, spec))
+	require.Equal(t, types.ClusterSpecSyncPending, spec.LastSyncStatus)
+	require.ErrorIs(t, s.ClusterSpecs.Create(ctx, &types.ClusterSpec{
+		Name: spec.Name, Source: types.ClusterSpecSourceUpload, SyncIntervalMinutes: 5, OwnerID: owner.ID,
+	}), store.ErrConflict)
+
+	// Never-synced git specs are due, and only one sync can run at a time
+	due, err := s.ClusterSpecs.ListDueForSync(ctx)
+	require.NoError(t, err)
+	require.Contains(t, specNames(due), spec.Name)
+
+	ok, err := s.ClusterSpecs.TryBeginSync(ctx, spec.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = s.ClusterSpecs.TryBeginSync(ctx, spec.ID)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	doc := &types.ClusterSpecDocument{
+		APIVersion: types.ClusterSpecAPIVersion,
+		Kind:       types.ClusterSpecKind,
+		Metadata:   types.ClusterSpecMetadata{Name: "team-a"},
+	}
+	require.NoError(t, s.ClusterSpecs.SetDocument(ctx, spec.ID, doc, "abc123"))
+	plan := &types.ClusterSpecPlan{Revision: "abc123", Actions: []types.PlanAction{}, Unchanged: 2}
+	require.NoError(t, s.ClusterSpecs.FinishSync(ctx, spec.ID, types.ClusterSpecSyncSucceeded, plan, nil))
+
+	got, err := s.ClusterSpecs.GetByName(ctx, spec.Name)
+	require.NoError(t, err)
+	require.Equal(t, "team-a", got.Document.Metadata.Name)
+	require.Equal(t, "abc123", *got.Revision)
+	require.Equal(t, 2, got.LastPlan.Unchanged)
+	require.Equal(t, types.ClusterSpecSyncSucceeded, got.LastSyncStatus)
+	require.NotNil(t, got.LastSyncedAt)
+
+	due, err = s.ClusterSpecs.ListDueForSync(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, specNames(due), spec.Name)
+
+	// Managed resources
+	cluster := &types.Cluster{
+		ID:          uuid.New().String(),
+		Name:        "spec-cluster-" + uuid.New().String()[:8],
+		Platform:    types.PlatformAWS,
+		ClusterType: types.ClusterTypeOpenShift,
+		Version:     "4.20",
+		Profile:     "aws-sno-ga",
+		Region:      "us-east-1",
+		Owner:       owner.Email,
+		Team:        "test",
+		CostCenter:  "test",
+		Status:      types.ClusterStatusPending,
+		TTLHours:    24,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	require.NoError(t, s.Clusters.Create(ctx, cluster))
+
+	ids, err := s.ClusterSpecs.FindActiveClusterIDs(ctx, []string{cluster.Name, "no-such-cluster"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{cluster.Name: cluster.ID}, ids)
+
+	require.NoError(t, s.ClusterSpecs.PutResource(ctx, &types.ClusterSpecResource{
+		SpecID: spec.ID, Kind: types.ClusterSpecResourceCluster, Name: cluster.Name, ResourceID: cluster.ID,
+	}))
+	resources, err := s.ClusterSpecs.ListResources(ctx, spec.ID)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	require.Equal(t, cluster.ID, resources[0].ResourceID)
+
+	require.NoError(t, s.ClusterSpecs.DeleteResource(ctx, spec.ID, types.ClusterSpecResourceCluster, cluster.Name))
+	resources, err = s.ClusterSpecs.ListResources(ctx, spec.ID)
+	require.NoError(t, err)
+	require.Empty(t, resources)
+
+	require.NoError(t, s.ClusterSpecs.Delete(ctx, spec.ID))
+	_, err = s.ClusterSpecs.GetByName(ctx, spec.Name)
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func specNames(specs []*types.ClusterSpec) []string {
+	names := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		names = append(names, spec.Name)
+	}
+	return names
+}
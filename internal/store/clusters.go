@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
@@ -17,22 +16,27 @@ type ClusterStore struct {
 	pool *pgxpool.Pool
 }
 
-// Create inserts a new cluster record into the database.
+// Create inserts a new cluster record into the database and records a CREATED event.
 // Returns an error if the cluster ID already exists or if the database operation fails.
 func (s *ClusterStore) Create(ctx context.Context, cluster *types.Cluster) error {
 	query := `
+		WITH inserted AS (
 		INSERT INTO clusters (
 			id, name, platform, cluster_type, version, profile, region, base_domain,
 			owner, owner_id, team, cost_center, status, requested_by, ttl_hours,
 			destroy_at, request_tags, effective_tags, ssh_public_key,
 			offhours_opt_in, work_hours_enabled, work_hours_start, work_hours_end, work_days,
-			skip_post_deployment, custom_post_config, selected_addon_ids, post_deploy_status, preserve_on_failure, credentials_mode, custom_pull_secret,
+			skip_post_deployment, custom_post_config, selected_addon_ids, post_deploy_status, preserve_on_failure, credentials_mode, custom_pull_secret, compute_overrides, restricted_network, architecture,
 			pool_id, pool_state
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
 			$16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30,
-			$31, $32, $33
+			$31, $32, $33, $34, $35, $36
 		)
+		RETURNING id, name, status
+		)
+		INSERT INTO cluster_events (cluster_id, cluster_name, event_type, to_status, actor, reason, job_id)
+		SELECT id, name, 'CREATED', status, $37, $38, $39 FROM inserted
 	`
 
 	// Convert empty OwnerID to NULL for system-managed clusters
@@ -43,6 +47,13 @@ func (s *ClusterStore) Create(ctx context.Context, cluster *types.Cluster) error
 		ownerID = cluster.OwnerID
 	}
 
+	// Attribute the creation to the requester unless the caller says otherwise
+	src := EventSourceFromContext(ctx)
+	if src.Actor == EventActorSystem && cluster.RequestedBy != "" {
+		src.Actor = cluster.RequestedBy
+	}
+	actor, reason, jobID := eventArgs(src)
+
 	_, err := s.pool.Exec(ctx, query,
 		cluster.ID,
 		cluster.Name,
@@ -75,8 +86,14 @@ func (s *ClusterStore) Create(ctx context.Context, cluster *types.Cluster) error
 		cluster.PreserveOnFailure,
 		cluster.CredentialsMode,
 		cluster.CustomPullSecret,
+		cluster.ComputeOverrides,
+		cluster.RestrictedNetwork,
+		cluster.Architecture,
 		cluster.PoolID,    // Pool ID for cluster pools
 		cluster.PoolState, // Pool state for cluster pools
+		actor,
+		reason,
+		jobID,
 	)
 
 	if err != nil {
@@ -95,7 +112,7 @@ func (s *ClusterStore) GetByID(ctx context.Context, id string) (*types.Cluster,
 			destroy_at, created_at, updated_at, destroyed_at,
 			request_tags, effective_tags, ssh_public_key, offhours_opt_in,
 			work_hours_enabled, work_hours_start, work_hours_end, work_days, last_work_hours_check,
-			skip_post_deployment, custom_post_config, selected_addon_ids, post_deploy_status, preserve_on_failure, credentials_mode, custom_pull_secret,
+			skip_post_deployment, custom_post_config, selected_addon_ids, post_deploy_status, preserve_on_failure, credentials_mode, custom_pull_secret, compute_overrides, restricted_network, architecture,
 			pool_id, pool_state, leased_by, leased_at, lease_expires_at, lease_metadata,
 			pool_generation, last_cleaned_at
 		FROM clusters
@@ -139,6 +156,9 @@ func (s *ClusterStore) GetByID(ctx context.Context, id string) (*types.Cluster,
 		&cluster.PreserveOnFailure,
 		&cluster.CredentialsMode,
 		&cluster.CustomPullSecret,
+		&cluster.ComputeOverrides,
+		&cluster.RestrictedNetwork,
+		&cluster.Architecture,
 		&cluster.PoolID,
 		&cluster.PoolState,
 		&cluster.LeasedBy,
@@ -172,7 +192,7 @@ func (s *ClusterStore) GetByIDs(ctx context.Context, ids []string) ([]*types.Clu
 			destroy_at, created_at, updated_at, destroyed_at,
 			request_tags, effective_tags, ssh_public_key, offhours_opt_in,
 			work_hours_enabled, work_hours_start, work_hours_end, work_days, last_work_hours_check,
-			skip_post_deployment, custom_post_config, post_deploy_status, preserve_on_failure, credentials_mode, custom_pull_secret,
+			skip_post_deployment, custom_post_config, post_deploy_status, preserve_on_failure, credentials_mode, custom_pull_secret, compute_overrides, restricted_network, architecture,
 			pool_id, pool_state, leased_by, leased_at, lease_expires_at, lease_metadata,
 			pool_generation, last_cleaned_at
 		FROM clusters
@@ -223,6 +243,9 @@ func (s *ClusterStore) GetByIDs(ctx context.Context, ids []string) ([]*types.Clu
 			&cluster.PreserveOnFailure,
 			&cluster.CredentialsMode,
 			&cluster.CustomPullSecret,
+			&cluster.ComputeOverrides,
+			&cluster.RestrictedNetwork,
+			&cluster.Architecture,
 			&cluster.PoolID,
 			&cluster.PoolState,
 			&cluster.LeasedBy,
@@ -254,7 +277,7 @@ func (s *ClusterStore) GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id strin
 			destroy_at, created_at, updated_at, destroyed_at,
 			request_tags, effective_tags, ssh_public_key, offhours_opt_in,
 			work_hours_enabled, work_hours_start, work_hours_end, work_days, last_work_hours_check,
-			skip_post_deployment, custom_post_config, post_deploy_status, preserve_on_failure, credentials_mode, custom_pull_secret,
+			skip_post_deployment, custom_post_config, post_deploy_status, preserve_on_failure, credentials_mode, custom_pull_secret, compute_overrides, restricted_network, architecture,
 			pool_id, pool_state, leased_by, leased_at, lease_expires_at, lease_metadata,
 			pool_generation, last_cleaned_at
 		FROM clusters
@@ -299,6 +322,9 @@ func (s *ClusterStore) GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id strin
 		&cluster.PreserveOnFailure,
 		&cluster.CredentialsMode,
 		&cluster.CustomPullSecret,
+		&cluster.ComputeOverrides,
+		&cluster.RestrictedNetwork,
+		&cluster.Architecture,
 		&cluster.PoolID,
 		&cluster.PoolState,
 		&cluster.LeasedBy,
@@ -359,7 +385,7 @@ func (s *ClusterStore) List(ctx context.Context, filters ListFilters) ([]*types.
 			c.destroy_at, c.created_at, c.updated_at, c.destroyed_at,
 			c.request_tags, c.effective_tags, c.ssh_public_key, c.offhours_opt_in,
 			c.work_hours_enabled, c.work_hours_start, c.work_hours_end, c.work_days, c.last_work_hours_check,
-			c.skip_post_deployment, c.custom_post_config, c.post_deploy_status, c.preserve_on_failure, c.credentials_mode, c.custom_pull_secret,
+			c.skip_post_deployment, c.custom_post_config, c.post_deploy_status, c.preserve_on_failure, c.credentials_mode, c.custom_pull_secret, c.compute_overrides, c.restricted_network, c.architecture,
 			c.pool_id, c.pool_state, c.leased_by, c.leased_at, c.lease_expires_at, c.lease_metadata,
 			c.pool_generation, c.last_cleaned_at,
 			co.api_url, co.console_url
@@ -487,6 +513,9 @@ func (s *ClusterStore) List(ctx context.Context, filters ListFilters) ([]*types.
 			&cluster.PreserveOnFailure,
 			&cluster.CredentialsMode,
 			&cluster.CustomPullSecret,
+			&cluster.ComputeOverrides,
+			&cluster.RestrictedNetwork,
+			&cluster.Architecture,
 			&cluster.PoolID,
 			&cluster.PoolState,
 			&cluster.LeasedBy,
@@ -519,7 +548,7 @@ func (s *ClusterStore) GetPoolClusters(ctx context.Context, poolID string, poolS
 			c.destroy_at, c.created_at, c.updated_at, c.destroyed_at,
 			c.request_tags, c.effective_tags, c.ssh_public_key, c.offhours_opt_in,
 			c.work_hours_enabled, c.work_hours_start, c.work_hours_end, c.work_days, c.last_work_hours_check,
-			c.skip_post_deployment, c.custom_post_config, c.post_deploy_status, c.preserve_on_failure, c.credentials_mode, c.custom_pull_secret,
+			c.skip_post_deployment, c.custom_post_config, c.post_deploy_status, c.preserve_on_failure, c.credentials_mode, c.custom_pull_secret, c.compute_overrides, c.restricted_network, c.architecture,
 			c.pool_id, c.pool_state, c.leased_by, c.leased_at, c.lease_expires_at, c.lease_metadata,
 			c.pool_generation, c.last_cleaned_at,
 			co.api_url, co.console_url
@@ -581,6 +610,9 @@ func (s *ClusterStore) GetPoolClusters(ctx context.Context, poolID string, poolS
 			&cluster.PreserveOnFailure,
 			&cluster.CredentialsMode,
 			&cluster.CustomPullSecret,
+			&cluster.ComputeOverrides,
+			&cluster.RestrictedNetwork,
+			&cluster.Architecture,
 			&cluster.PoolID,
 			&cluster.PoolState,
 			&cluster.LeasedBy,
@@ -606,37 +638,52 @@ func (s *ClusterStore) GetPoolClusters(ctx context.Context, poolID string, poolS
 }
 
 // UpdateStatus updates a cluster's status and automatically updates the updated_at timestamp.
+// If the status actually changes, a STATUS_CHANGED event attributed to the
+// context's EventSource is recorded in the same statement.
 // Can be called with or without a transaction (tx can be nil for non-transactional updates).
 // Returns ErrNotFound if the cluster does not exist.
 func (s *ClusterStore) UpdateStatus(ctx context.Context, tx pgx.Tx, id string, status types.ClusterStatus) error {
 	// When cluster becomes READY, update pool_state from PROVISIONING to READY (for pool clusters)
 	// When cluster is DESTROYING, DESTROYED or FAILED, clear pool_state (remove from pool statistics)
 	query := `
-		UPDATE clusters
-		SET status = $1::varchar,
-			pool_state = CASE
-				WHEN $1::varchar = 'READY' AND pool_id IS NOT NULL AND pool_state = 'PROVISIONING' THEN 'READY'
-				WHEN $1::varchar IN ('DESTROYING', 'DESTROYED', 'FAILED') AND pool_id IS NOT NULL THEN NULL
-				ELSE pool_state
-			END,
-			updated_at = NOW()
-		WHERE id = $2
+		WITH prev AS (
+			SELECT id, status FROM clusters WHERE id = $2 FOR UPDATE
+		), updated AS (
+			UPDATE clusters c
+			SET status = $1::varchar,
+				pool_state = CASE
+					WHEN $1::varchar = 'READY' AND pool_id IS NOT NULL AND pool_state = 'PROVISIONING' THEN 'READY'
+					WHEN $1::varchar IN ('DESTROYING', 'DESTROYED', 'FAILED') AND pool_id IS NOT NULL THEN NULL
+					ELSE pool_state
+				END,
+				updated_at = NOW()
+			FROM prev
+			WHERE c.id = prev.id
+			RETURNING c.id, c.name, prev.status AS from_status, c.status AS to_status
+		), event AS (
+			INSERT INTO cluster_events (cluster_id, cluster_name, event_type, from_status, to_status, actor, reason, job_id)
+			SELECT id, name, 'STATUS_CHANGED', from_status, to_status, $3, $4, $5
+			FROM updated
+			WHERE from_status IS DISTINCT FROM to_status
+		)
+		SELECT COUNT(*) FROM updated
 	`
 
-	var result pgconn.CommandTag
-	var err error
+	actor, reason, jobID := eventArgs(EventSourceFromContext(ctx))
 
+	var row pgx.Row
 	if tx != nil {
-		result, err = tx.Exec(ctx, query, status, id)
+		row = tx.QueryRow(ctx, query, status, id, actor, reason, jobID)
 	} else {
-		result, err = s.pool.Exec(ctx, query, status, id)
+		row = s.pool.QueryRow(ctx, query, status, id, actor, reason, jobID)
 	}
 
-	if err != nil {
+	var updated int
+	if err := row.Scan(&updated); err != nil {
 		return fmt.Errorf("update cluster status: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
+	if updated == 0 {
 		return ErrNotFound
 	}
 
@@ -648,20 +695,35 @@ func (s *ClusterStore) UpdateStatus(ctx context.Context, tx pgx.Tx, id string, s
 // Returns ErrNotFound if the cluster does not exist.
 func (s *ClusterStore) MarkDestroyed(ctx context.Context, id string) error {
 	query := `
-		UPDATE clusters
-		SET status = $1,
-			destroyed_at = NOW(),
-			updated_at = NOW(),
-			pool_state = NULL
-		WHERE id = $2
+		WITH prev AS (
+			SELECT id, status FROM clusters WHERE id = $2 FOR UPDATE
+		), updated AS (
+			UPDATE clusters c
+			SET status = $1,
+				destroyed_at = NOW(),
+				updated_at = NOW(),
+				pool_state = NULL
+			FROM prev
+			WHERE c.id = prev.id
+			RETURNING c.id, c.name, prev.status AS from_status, c.status AS to_status
+		), event AS (
+			INSERT INTO cluster_events (cluster_id, cluster_name, event_type, from_status, to_status, actor, reason, job_id)
+			SELECT id, name, 'STATUS_CHANGED', from_status, to_status, $3, $4, $5
+			FROM updated
+			WHERE from_status IS DISTINCT FROM to_status
+		)
+		SELECT COUNT(*) FROM updated
 	`
 
-	result, err := s.pool.Exec(ctx, query, types.ClusterStatusDestroyed, id)
+	actor, reason, jobID := eventArgs(EventSourceFromContext(ctx))
+
+	var updated int
+	err := s.pool.QueryRow(ctx, query, types.ClusterStatusDestroyed, id, actor, reason, jobID).Scan(&updated)
 	if err != nil {
 		return fmt.Errorf("mark cluster destroyed: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
+	if updated == 0 {
 		return ErrNotFound
 	}
 
@@ -1250,7 +1312,7 @@ func (s *ClusterStore) GetLongRunningClusters(ctx context.Context, minHours int)
 			c.ssh_public_key, c.offhours_opt_in, c.work_hours_enabled,
 			c.work_hours_start, c.work_hours_end, c.work_days, c.last_work_hours_check,
 			c.skip_post_deployment, c.custom_post_config, c.post_deploy_status,
-			c.preserve_on_failure, c.credentials_mode, c.custom_pull_secret,
+			c.preserve_on_failure, c.credentials_mode, c.custom_pull_secret, c.compute_overrides, c.restricted_network, c.architecture,
 			EXTRACT(EPOCH FROM (NOW() - c.created_at)) / 3600 as running_duration_hours,
 			(
 				SELECT MAX(j.ended_at)
@@ -1319,6 +1381,9 @@ func (s *ClusterStore) GetLongRunningClusters(ctx context.Context, minHours int)
 			&lrc.Cluster.PreserveOnFailure,
 			&lrc.Cluster.CredentialsMode,
 			&lrc.Cluster.CustomPullSecret,
+			&lrc.Cluster.ComputeOverrides,
+			&lrc.Cluster.RestrictedNetwork,
+			&lrc.Cluster.Architecture,
 			&lrc.RunningDurationHours,
 			&lrc.LastHibernatedAt,
 		)
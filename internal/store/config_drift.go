@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ConfigDriftStore handles post-config drift report database operations
+type ConfigDriftStore struct {
+	pool *pgxpool.Pool
+}
+
+// Upsert records the result of a drift check, replacing the cluster's previous report
+func (s *ConfigDriftStore) Upsert(ctx context.Context, report *types.ConfigDriftReport) error {
+	items := report.Items
+	if items == nil {
+		items = []types.ConfigDriftItem{}
+	}
+
+	query := `
+		INSERT INTO cluster_config_drift (cluster_id, job_id, status, checked, items, error, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (cluster_id) DO UPDATE SET
+			job_id = EXCLUDED.job_id,
+			status = EXCLUDED.status,
+			checked = EXCLUDED.checked,
+			items = EXCLUDED.items,
+			error = EXCLUDED.error,
+			checked_at = EXCLUDED.checked_at
+		RETURNING checked_at
+	`
+
+	err := s.pool.QueryRow(ctx, query,
+		report.ClusterID,
+		report.JobID,
+		report.Status,
+		report.Checked,
+		items,
+		report.Error,
+	).Scan(&report.CheckedAt)
+	if err != nil {
+		return fmt.Errorf("upsert config drift report: %w", err)
+	}
+
+	return nil
+}
+
+// GetByClusterID retrieves the latest drift report for a cluster. Returns
+// ErrNotFound if the cluster has never been checked.
+func (s *ConfigDriftStore) GetByClusterID(ctx context.Context, clusterID string) (*types.ConfigDriftReport, error) {
+	query := `
+		SELECT cluster_id, job_id, status, checked, items, error, checked_at
+		FROM cluster_config_drift
+		WHERE cluster_id = $1
+	`
+
+	report := &types.ConfigDriftReport{}
+	err := s.pool.QueryRow(ctx, query, clusterID).Scan(
+		&report.ClusterID,
+		&report.JobID,
+		&report.Status,
+		&report.Checked,
+		&report.Items,
+		&report.Error,
+		&report.CheckedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get config drift report: %w", err)
+	}
+
+	return report, nil
+}
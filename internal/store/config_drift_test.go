@@ -0,0 +1,68 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/dbtest"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestConfigDriftStore_Upsert(t *testing.T) {
+	s := dbtest.New(t)
+	ctx := context.Background()
+
+	cluster := &types.Cluster{
+		ID:          uuid.New().String(),
+		Name:        "drift-" + uuid.New().String()[:8],
+		Platform:    types.PlatformAWS,
+		ClusterType: types.ClusterTypeOpenShift,
+		Version:     "4.20",
+		Profile:     "aws-sno-ga",
+		Region:      "us-east-1",
+		Owner:       "owner@example.com",
+		Team:        "test",
+		CostCenter:  "test",
+		Status:      types.ClusterStatusReady,
+		RequestedBy: "owner@example.com",
+		TTLHours:    24,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	require.NoError(t, s.Clusters.Create(ctx, cluster))
+
+	_, err := s.ConfigDrift.GetByClusterID(ctx, cluster.ID)
+	require.ErrorIs(t, err, store.ErrNotFound)
+
+	report := &types.ConfigDriftReport{
+		ClusterID: cluster.ID,
+		Status:    types.ConfigDriftDrifted,
+		Checked:   3,
+		Items: []types.ConfigDriftItem{
+			{Task: "oadp", Type: types.ConfigTypeOperator, Source: "oadp", Reason: "subscription oadp not found in openshift-adp"},
+		},
+	}
+	require.NoError(t, s.ConfigDrift.Upsert(ctx, report))
+	require.False(t, report.CheckedAt.IsZero())
+
+	got, err := s.ConfigDrift.GetByClusterID(ctx, cluster.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.ConfigDriftDrifted, got.Status)
+	require.Equal(t, 3, got.Checked)
+	require.Equal(t, report.Items, got.Items)
+
+	// A later check replaces the report
+	require.NoError(t, s.ConfigDrift.Upsert(ctx, &types.ConfigDriftReport{
+		ClusterID: cluster.ID,
+		Status:    types.ConfigDriftInSync,
+		Checked:   3,
+	}))
+	got, err = s.ConfigDrift.GetByClusterID(ctx, cluster.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.ConfigDriftInSync, got.Status)
+	require.Empty(t, got.Items)
+}
@@ -24,7 +24,8 @@ func (s *IdempotencyStore) Store(ctx context.Context, key types.IdempotencyKey)
 		)
 		ON CONFLICT (key) DO UPDATE
 		SET response_status_code = EXCLUDED.response_status_code,
-			response_body = EXCLUDED.response_body
+			response_body = EXCLUDED.response_body,
+			reserved_until = NULL
 	`
 
 	_, err := s.pool.Exec(ctx, query,
@@ -43,11 +44,47 @@ func (s *IdempotencyStore) Store(ctx context.Context, key types.IdempotencyKey)
 	return nil
 }
 
+// Reserve records an idempotency key before its request runs. It returns
+// false if an unexpired entry for the key already exists, unless the entry is
+// a reservation without a response whose reserved_until has passed.
+func (s *IdempotencyStore) Reserve(ctx context.Context, key types.IdempotencyKey) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (id, key, request_hash, expires_at, reserved_until)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (key) DO UPDATE
+		SET id = EXCLUDED.id,
+			request_hash = EXCLUDED.request_hash,
+			response_status_code = NULL,
+			response_body = NULL,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at,
+			reserved_until = EXCLUDED.reserved_until
+		WHERE idempotency_keys.expires_at <= NOW()
+			OR (idempotency_keys.response_status_code IS NULL AND idempotency_keys.reserved_until <= NOW())
+	`
+
+	result, err := s.pool.Exec(ctx, query, key.ID, key.Key, key.RequestHash, key.ExpiresAt, key.ReservedUntil)
+	if err != nil {
+		return false, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// Delete removes an idempotency key so the request can be retried
+func (s *IdempotencyStore) Delete(ctx context.Context, key string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("delete idempotency key: %w", err)
+	}
+	return nil
+}
+
 // Get retrieves an idempotency key if it exists and hasn't expired
 func (s *IdempotencyStore) Get(ctx context.Context, key string) (*types.IdempotencyKey, error) {
 	query := `
 		SELECT id, key, request_hash, response_status_code, response_body,
-			created_at, expires_at
+			created_at, expires_at, reserved_until
 		FROM idempotency_keys
 		WHERE key = $1 AND expires_at > NOW()
 	`
@@ -61,6 +98,7 @@ func (s *IdempotencyStore) Get(ctx context.Context, key string) (*types.Idempote
 		&ikey.ResponseBody,
 		&ikey.CreatedAt,
 		&ikey.ExpiresAt,
+		&ikey.ReservedUntil,
 	)
 
 	if err == pgx.ErrNoRows {
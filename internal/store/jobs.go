@@ -2,10 +2,12 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
@@ -18,6 +20,8 @@ type JobStore struct {
 // Create inserts a new job record into the database.
 // The job is initialized with PENDING status and attempt counter at 1.
 // Can be called with or without a transaction (tx can be nil for non-transactional inserts).
+// Returns ErrConflict if the cluster already has an add-on changing job
+// (POST_CONFIGURE, ADDON_*, DRIFT_CHECK) in progress and job is one too.
 func (s *JobStore) Create(ctx context.Context, tx pgx.Tx, job *types.Job) error {
 	query := `
 		INSERT INTO jobs (
@@ -51,6 +55,11 @@ func (s *JobStore) Create(ctx context.Context, tx pgx.Tx, job *types.Job) error
 	}
 
 	if err != nil {
+		// Only one add-on changing job may be in progress per cluster
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_jobs_active_addon_job" {
+			return ErrConflict
+		}
 		return fmt.Errorf("insert job: %w", err)
 	}
 
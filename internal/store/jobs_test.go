@@ -0,0 +1,50 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/dbtest"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestJobStore_CreateOneActiveAddonJob(t *testing.T) {
+	s := dbtest.New(t)
+	ctx := context.Background()
+	cluster := createEventTestCluster(t, s)
+
+	newJob := func(jobType types.JobType) *types.Job {
+		return &types.Job{
+			ID:          uuid.New().String(),
+			ClusterID:   cluster.ID,
+			JobType:     jobType,
+			Status:      types.JobStatusPending,
+			Attempt:     1,
+			MaxAttempts: 3,
+			Metadata:    types.JobMetadata{},
+		}
+	}
+
+	install := newJob(types.JobTypeAddonInstall)
+	require.NoError(t, s.Jobs.Create(ctx, nil, install))
+
+	// Any other add-on changing job conflicts while one is in progress
+	for _, jobType := range []types.JobType{
+		types.JobTypeAddonInstall,
+		types.JobTypeAddonUninstall,
+		types.JobTypeDriftCheck,
+		types.JobTypePostConfigure,
+	} {
+		require.ErrorIs(t, s.Jobs.Create(ctx, nil, newJob(jobType)), store.ErrConflict, jobType)
+	}
+
+	// Other job types are not affected
+	require.NoError(t, s.Jobs.Create(ctx, nil, newJob(types.JobTypeHibernate)))
+
+	// Once the job finishes another can be queued
+	require.NoError(t, s.Jobs.MarkSucceeded(ctx, install.ID, nil))
+	require.NoError(t, s.Jobs.Create(ctx, nil, newJob(types.JobTypeDriftCheck)))
+}
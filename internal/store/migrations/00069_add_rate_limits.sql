@@ -0,0 +1,33 @@
+-- +goose Up
+-- Admin-configured rate limit overrides. A policy applies to every request made
+-- by the matching principal: an API key (by id), a user (by id) or a role.
+CREATE TABLE rate_limit_policies (
+  id VARCHAR(64) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+  scope VARCHAR(20) NOT NULL CHECK (scope IN ('api_key', 'user', 'role')),
+  subject VARCHAR(255) NOT NULL,
+  requests_per_minute INTEGER NOT NULL CHECK (requests_per_minute > 0),
+  burst INTEGER NOT NULL CHECK (burst > 0),
+  description TEXT,
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  UNIQUE (scope, subject)
+);
+
+COMMENT ON TABLE rate_limit_policies IS 'Per-role, per-user and per-API-key overrides of the global API rate limit';
+
+-- Shared fixed-window counters used when API replicas run with
+-- RATE_LIMIT_BACKEND=postgres. Counters are disposable, so the table is
+-- unlogged to keep the per-request upsert cheap.
+CREATE UNLOGGED TABLE rate_limit_counters (
+  bucket_key VARCHAR(512) NOT NULL,
+  window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+  request_count INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (bucket_key, window_start)
+);
+
+CREATE INDEX idx_rate_limit_counters_window ON rate_limit_counters(window_start);
+
+-- +goose Down
+DROP TABLE IF EXISTS rate_limit_counters;
+DROP TABLE IF EXISTS rate_limit_policies;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Append-only history of cluster lifecycle transitions. Rows are written in
+-- the same statement or transaction as the change they describe, and are
+-- published as CloudEvents by the worker when a sink is configured.
+CREATE TABLE cluster_events (
+  id BIGSERIAL PRIMARY KEY,
+  cluster_id VARCHAR(64) NOT NULL REFERENCES clusters(id) ON DELETE CASCADE,
+  cluster_name VARCHAR(255) NOT NULL,
+  event_type VARCHAR(32) NOT NULL CHECK (event_type IN ('CREATED', 'STATUS_CHANGED', 'LEASED', 'RELEASED')),
+  from_status VARCHAR(50),
+  to_status VARCHAR(50) NOT NULL,
+  actor VARCHAR(255) NOT NULL,
+  reason TEXT,
+  job_id VARCHAR(64),
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  published_at TIMESTAMP WITH TIME ZONE,
+  publish_attempts INTEGER NOT NULL DEFAULT 0,
+  last_publish_error TEXT
+);
+
+COMMENT ON TABLE cluster_events IS 'Cluster lifecycle transitions: who changed what and why';
+COMMENT ON COLUMN cluster_events.actor IS 'User email, "worker" or "system"';
+
+CREATE INDEX idx_cluster_events_cluster_id ON cluster_events(cluster_id, id);
+CREATE INDEX idx_cluster_events_unpublished ON cluster_events(id) WHERE published_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS cluster_events;
@@ -0,0 +1,45 @@
+-- +goose Up
+-- Declarative desired-state documents ("cluster specs") that ocpctl reconciles
+-- clusters, pools and storage links against. Git specs are fetched and synced
+-- by the worker; upload specs are reconciled when a document is uploaded.
+CREATE TABLE cluster_specs (
+  id VARCHAR(64) PRIMARY KEY DEFAULT gen_random_uuid()::text,
+  name VARCHAR(63) NOT NULL UNIQUE,
+  source VARCHAR(20) NOT NULL CHECK (source IN ('upload', 'git')),
+  git_url TEXT,
+  git_ref VARCHAR(255),
+  git_path TEXT,
+  sync_interval_minutes INTEGER NOT NULL DEFAULT 5 CHECK (sync_interval_minutes > 0),
+  prune BOOLEAN NOT NULL DEFAULT FALSE,
+  document JSONB,
+  revision VARCHAR(255),
+  last_plan JSONB,
+  last_sync_status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (last_sync_status IN ('PENDING', 'SUCCEEDED', 'FAILED')),
+  last_sync_error TEXT,
+  last_synced_at TIMESTAMP WITH TIME ZONE,
+  sync_started_at TIMESTAMP WITH TIME ZONE,
+  owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  CHECK (source <> 'git' OR git_url IS NOT NULL)
+);
+
+COMMENT ON TABLE cluster_specs IS 'Declarative desired state for clusters, pools and storage links';
+COMMENT ON COLUMN cluster_specs.sync_started_at IS 'Set while a replica is reconciling the spec; stale values are ignored after 15 minutes';
+
+-- Resources created by a spec. Only recorded resources are updated or
+-- destroyed by reconciliation, so a spec never touches clusters it did not create.
+CREATE TABLE cluster_spec_resources (
+  spec_id VARCHAR(64) NOT NULL REFERENCES cluster_specs(id) ON DELETE CASCADE,
+  kind VARCHAR(20) NOT NULL CHECK (kind IN ('cluster', 'pool', 'storage_link')),
+  name VARCHAR(255) NOT NULL,
+  resource_id VARCHAR(64) NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  PRIMARY KEY (spec_id, kind, name)
+);
+
+CREATE INDEX idx_cluster_spec_resources_resource ON cluster_spec_resources(kind, resource_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS cluster_spec_resources;
+DROP TABLE IF EXISTS cluster_specs;
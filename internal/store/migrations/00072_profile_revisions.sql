@@ -0,0 +1,40 @@
+-- +goose Up
+-- Profile revision history. Every create, edit, version update, rollback and
+-- file sync of a profile saves a revision; the profiles table holds the
+-- published one, which is what the API and workers load.
+ALTER TABLE profiles ADD COLUMN source VARCHAR(10) NOT NULL DEFAULT 'file' CHECK (source IN ('file', 'api'));
+ALTER TABLE profiles ADD COLUMN revision INTEGER;
+
+COMMENT ON COLUMN profiles.source IS 'file: synced from profile YAML at startup; api: managed through the admin API, never overwritten by the file sync';
+COMMENT ON COLUMN profiles.revision IS 'Published revision in profile_revisions';
+
+-- Not a foreign key to profiles: a draft of a new profile has no profiles row
+-- until it is published, and history is kept when a profile is deleted.
+CREATE TABLE profile_revisions (
+  profile_name VARCHAR(255) NOT NULL,
+  revision INTEGER NOT NULL CHECK (revision > 0),
+  status VARCHAR(20) NOT NULL CHECK (status IN ('draft', 'published', 'superseded')),
+  source VARCHAR(10) NOT NULL CHECK (source IN ('file', 'api')),
+  definition TEXT NOT NULL,
+  profile_data JSONB NOT NULL,
+  diff TEXT,
+  message TEXT,
+  author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  author_email VARCHAR(255),
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  published_at TIMESTAMP WITH TIME ZONE,
+  PRIMARY KEY (profile_name, revision)
+);
+
+-- At most one published revision per profile
+CREATE UNIQUE INDEX idx_profile_revisions_published ON profile_revisions(profile_name) WHERE status = 'published';
+
+COMMENT ON TABLE profile_revisions IS 'Revision history of cluster profiles with drafts, authors and diffs';
+COMMENT ON COLUMN profile_revisions.definition IS 'Profile YAML as submitted';
+COMMENT ON COLUMN profile_revisions.profile_data IS 'Resolved and validated profile, copied to profiles.profile_data on publish';
+COMMENT ON COLUMN profile_revisions.diff IS 'Unified diff against the profile that was published when the revision was saved';
+
+-- +goose Down
+DROP TABLE IF EXISTS profile_revisions;
+ALTER TABLE profiles DROP COLUMN IF EXISTS revision;
+ALTER TABLE profiles DROP COLUMN IF EXISTS source;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Per-cluster compute overrides (worker count, instance type, root volume size,
+-- extra machine pools) validated against the profile's overridable schema.
+ALTER TABLE clusters ADD COLUMN IF NOT EXISTS compute_overrides JSONB;
+
+COMMENT ON COLUMN clusters.compute_overrides IS 'Compute shape overrides requested at create time; NULL means the profile defaults were used';
+
+-- +goose Down
+ALTER TABLE clusters DROP COLUMN IF EXISTS compute_overrides;
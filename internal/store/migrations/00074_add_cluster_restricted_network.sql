@@ -0,0 +1,10 @@
+-- +goose Up
+-- Restricted-network settings (cluster-wide proxy, additional trust bundle,
+-- image mirrors) supplied on the create request. The profile's own settings
+-- are merged underneath at install time.
+ALTER TABLE clusters ADD COLUMN IF NOT EXISTS restricted_network JSONB;
+
+COMMENT ON COLUMN clusters.restricted_network IS 'Request-level proxy, trust bundle and image mirror settings; NULL means only the profile settings apply';
+
+-- +goose Down
+ALTER TABLE clusters DROP COLUMN IF EXISTS restricted_network;
@@ -0,0 +1,8 @@
+-- 00075_add_hcp_cluster_type.sql
+-- Add the HCP cluster type for hosted control planes (HyperShift)
+
+-- +goose Up
+ALTER TYPE cluster_type ADD VALUE IF NOT EXISTS 'hcp';
+
+-- +goose Down
+-- PostgreSQL does not support removing enum values; 'hcp' is left in place.
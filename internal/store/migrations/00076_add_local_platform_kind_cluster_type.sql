@@ -0,0 +1,14 @@
+-- Add the local platform and kind cluster type for end-to-end testing
+
+-- +goose Up
+ALTER TABLE clusters DROP CONSTRAINT IF EXISTS clusters_platform_check;
+ALTER TABLE clusters ADD CONSTRAINT clusters_platform_check
+  CHECK (platform IN ('aws', 'ibmcloud', 'gcp', 'azure', 'local'));
+
+ALTER TYPE cluster_type ADD VALUE IF NOT EXISTS 'kind';
+
+-- +goose Down
+ALTER TABLE clusters DROP CONSTRAINT IF EXISTS clusters_platform_check;
+ALTER TABLE clusters ADD CONSTRAINT clusters_platform_check
+  CHECK (platform IN ('aws', 'ibmcloud', 'gcp', 'azure'));
+-- PostgreSQL does not support removing enum values; 'kind' is left in place.
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Control plane and compute architectures (amd64, arm64) supplied on the
+-- create request. The profile's own architecture is merged underneath.
+ALTER TABLE clusters ADD COLUMN IF NOT EXISTS architecture JSONB;
+
+COMMENT ON COLUMN clusters.architecture IS 'Request-level control plane and compute architectures; NULL means the profile architecture (amd64 by default) applies';
+
+-- +goose Down
+ALTER TABLE clusters DROP COLUMN IF EXISTS architecture;
@@ -0,0 +1,54 @@
+-- +goose Up
+-- Add day-2 add-on job types to jobs_job_type_check constraint
+
+-- Drop the existing constraint
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_job_type_check;
+
+-- Recreate the constraint with ADDON_INSTALL, ADDON_UPGRADE and ADDON_UNINSTALL added
+ALTER TABLE jobs ADD CONSTRAINT jobs_job_type_check CHECK (
+    job_type IN (
+        'CREATE',
+        'DESTROY',
+        'SCALE_WORKERS',
+        'JANITOR_DESTROY',
+        'ORPHAN_SWEEP',
+        'CONFIGURE_EFS',
+        'PROVISION_SHARED_STORAGE',
+        'UNLINK_SHARED_STORAGE',
+        'HIBERNATE',
+        'RESUME',
+        'POST_CONFIGURE',
+        'POOL_REPLENISH',
+        'POOL_CLEAN',
+        'CREATE_WINDOWS_SNAPSHOT',
+        'ADDON_INSTALL',
+        'ADDON_UPGRADE',
+        'ADDON_UNINSTALL'
+    )
+);
+
+-- +goose Down
+-- Remove day-2 add-on job types from jobs_job_type_check constraint
+
+-- Drop the constraint
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_job_type_check;
+
+-- Recreate the constraint without the add-on job types
+ALTER TABLE jobs ADD CONSTRAINT jobs_job_type_check CHECK (
+    job_type IN (
+        'CREATE',
+        'DESTROY',
+        'SCALE_WORKERS',
+        'JANITOR_DESTROY',
+        'ORPHAN_SWEEP',
+        'CONFIGURE_EFS',
+        'PROVISION_SHARED_STORAGE',
+        'UNLINK_SHARED_STORAGE',
+        'HIBERNATE',
+        'RESUME',
+        'POST_CONFIGURE',
+        'POOL_REPLENISH',
+        'POOL_CLEAN',
+        'CREATE_WINDOWS_SNAPSHOT'
+    )
+);
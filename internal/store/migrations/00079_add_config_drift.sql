@@ -0,0 +1,77 @@
+-- +goose Up
+-- Post-config drift detection: DRIFT_CHECK jobs compare a cluster's installed
+-- operators, manifests and Helm releases with its recorded post-config and
+-- store the latest result per cluster.
+
+-- Drop the existing constraint
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_job_type_check;
+
+-- Recreate the constraint with DRIFT_CHECK added
+ALTER TABLE jobs ADD CONSTRAINT jobs_job_type_check CHECK (
+    job_type IN (
+        'CREATE',
+        'DESTROY',
+        'SCALE_WORKERS',
+        'JANITOR_DESTROY',
+        'ORPHAN_SWEEP',
+        'CONFIGURE_EFS',
+        'PROVISION_SHARED_STORAGE',
+        'UNLINK_SHARED_STORAGE',
+        'HIBERNATE',
+        'RESUME',
+        'POST_CONFIGURE',
+        'POOL_REPLENISH',
+        'POOL_CLEAN',
+        'CREATE_WINDOWS_SNAPSHOT',
+        'ADDON_INSTALL',
+        'ADDON_UPGRADE',
+        'ADDON_UNINSTALL',
+        'DRIFT_CHECK'
+    )
+);
+
+CREATE TABLE cluster_config_drift (
+  cluster_id VARCHAR(64) PRIMARY KEY REFERENCES clusters(id) ON DELETE CASCADE,
+  job_id VARCHAR(64),
+  status VARCHAR(20) NOT NULL CHECK (status IN ('IN_SYNC', 'DRIFTED', 'RECONCILED', 'FAILED')),
+  checked INTEGER NOT NULL DEFAULT 0,
+  items JSONB NOT NULL DEFAULT '[]',
+  error TEXT,
+  checked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE cluster_config_drift IS 'Latest post-config drift check result per cluster';
+
+-- Pools can re-apply drifted post-config while cleaning a released cluster
+ALTER TABLE cluster_pools ADD COLUMN reconcile_drift_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE cluster_pools DROP COLUMN IF EXISTS reconcile_drift_enabled;
+
+DROP TABLE IF EXISTS cluster_config_drift;
+
+-- Drop the constraint
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_job_type_check;
+
+-- Recreate the constraint without DRIFT_CHECK
+ALTER TABLE jobs ADD CONSTRAINT jobs_job_type_check CHECK (
+    job_type IN (
+        'CREATE',
+        'DESTROY',
+        'SCALE_WORKERS',
+        'JANITOR_DESTROY',
+        'ORPHAN_SWEEP',
+        'CONFIGURE_EFS',
+        'PROVISION_SHARED_STORAGE',
+        'UNLINK_SHARED_STORAGE',
+        'HIBERNATE',
+        'RESUME',
+        'POST_CONFIGURE',
+        'POOL_REPLENISH',
+        'POOL_CLEAN',
+        'CREATE_WINDOWS_SNAPSHOT',
+        'ADDON_INSTALL',
+        'ADDON_UPGRADE',
+        'ADDON_UNINSTALL'
+    )
+);
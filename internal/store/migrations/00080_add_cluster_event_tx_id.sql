@@ -0,0 +1,14 @@
+-- +goose Up
+-- Record the transaction that wrote each cluster event. Events are written
+-- inside the state change transactions, which can commit out of ID order, so
+-- the event stream orders by transaction and only reads events whose
+-- transactions are older than every transaction still running.
+ALTER TABLE cluster_events ADD COLUMN tx_id xid8 NOT NULL DEFAULT pg_current_xact_id();
+
+COMMENT ON COLUMN cluster_events.tx_id IS 'Transaction that wrote the event; orders the event stream by commit';
+
+CREATE INDEX idx_cluster_events_tx_id ON cluster_events(tx_id, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_cluster_events_tx_id;
+ALTER TABLE cluster_events DROP COLUMN IF EXISTS tx_id;
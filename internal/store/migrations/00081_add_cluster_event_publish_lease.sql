@@ -0,0 +1,11 @@
+-- +goose Up
+-- Lease cluster events while they are being published. Workers claim events
+-- by setting publishing_until and commit before calling the publisher, so a
+-- slow publish does not hold a transaction open and stall the event stream,
+-- which only reads events older than every running transaction.
+ALTER TABLE cluster_events ADD COLUMN publishing_until TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN cluster_events.publishing_until IS 'Set while a worker publishes the event; other workers skip it until then';
+
+-- +goose Down
+ALTER TABLE cluster_events DROP COLUMN IF EXISTS publishing_until;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Allow one add-on changing job (POST_CONFIGURE, ADDON_*, DRIFT_CHECK) in
+-- progress per cluster. The API checks for one before queueing a job, but two
+-- requests can pass that check together; the index makes the second insert
+-- fail instead.
+
+-- Fail all but the oldest of any jobs already in progress together
+UPDATE jobs
+SET status = 'FAILED',
+    error_message = 'Another add-on job was already in progress for this cluster',
+    ended_at = NOW()
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY cluster_id ORDER BY created_at, id) AS n
+        FROM jobs
+        WHERE job_type IN ('POST_CONFIGURE', 'ADDON_INSTALL', 'ADDON_UPGRADE', 'ADDON_UNINSTALL', 'DRIFT_CHECK')
+          AND status IN ('PENDING', 'RUNNING', 'RETRYING')
+    ) active
+    WHERE n > 1
+);
+
+CREATE UNIQUE INDEX idx_jobs_active_addon_job ON jobs(cluster_id)
+WHERE job_type IN ('POST_CONFIGURE', 'ADDON_INSTALL', 'ADDON_UPGRADE', 'ADDON_UNINSTALL', 'DRIFT_CHECK')
+  AND status IN ('PENDING', 'RUNNING', 'RETRYING');
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_jobs_active_addon_job;
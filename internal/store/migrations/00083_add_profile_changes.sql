@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Counter bumped by every write to profiles, in the writing transaction.
+-- Registries poll it to reload profiles across replicas. MAX(updated_at) is
+-- the transaction start time, so an edit whose transaction started before a
+-- newer one but committed after it would not move that marker; concurrent
+-- writers queue on this row instead, so the counter rises in commit order.
+CREATE TABLE profile_changes (
+  id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+  version BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO profile_changes (id, version) VALUES (TRUE, 0);
+
+CREATE OR REPLACE FUNCTION bump_profile_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE profile_changes SET version = version + 1;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER profiles_changes_trigger
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON profiles
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION bump_profile_changes();
+
+COMMENT ON TABLE profile_changes IS 'Single-row counter of profile writes, polled by profile registries';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS profiles_changes_trigger ON profiles;
+DROP FUNCTION IF EXISTS bump_profile_changes();
+DROP TABLE IF EXISTS profile_changes;
+
+-- +goose StatementEnd
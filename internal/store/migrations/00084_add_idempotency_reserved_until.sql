@@ -0,0 +1,11 @@
+-- +goose Up
+-- Lease idempotency reservations. A key is reserved before its request runs;
+-- if the API crashes or is redeployed before the response is recorded, the
+-- reservation can be taken over once reserved_until passes instead of
+-- blocking retries until the key expires.
+ALTER TABLE idempotency_keys ADD COLUMN reserved_until TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN idempotency_keys.reserved_until IS 'While the response is NULL, a retry may take over the key after this time';
+
+-- +goose Down
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS reserved_until;
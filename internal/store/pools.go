@@ -24,16 +24,16 @@ func (s *PoolStore) Create(ctx context.Context, tx pgx.Tx, pool *types.ClusterPo
 			id, name, display_name, description, profile,
 			target_size, min_size, max_size,
 			default_lease_duration_hours, max_lease_duration_hours, auto_release_enabled,
-			max_cluster_age_days, auto_refresh_enabled,
+			max_cluster_age_days, auto_refresh_enabled, reconcile_drift_enabled,
 			scheduled_mode, schedule_timezone, schedule_start_hour, schedule_end_hour, schedule_days_of_week,
 			cluster_config, enabled, created_by
 		) VALUES (
 			gen_random_uuid(), $1, $2, $3, $4,
 			$5, $6, $7,
 			$8, $9, $10,
-			$11, $12,
-			$13, $14, $15, $16, $17,
-			$18, $19, $20
+			$11, $12, $13,
+			$14, $15, $16, $17, $18,
+			$19, $20, $21
 		)
 		RETURNING id, created_at, updated_at
 	`
@@ -44,7 +44,7 @@ func (s *PoolStore) Create(ctx context.Context, tx pgx.Tx, pool *types.ClusterPo
 			pool.Name, pool.DisplayName, pool.Description, pool.Profile,
 			pool.TargetSize, pool.MinSize, pool.MaxSize,
 			pool.DefaultLeaseDurationHours, pool.MaxLeaseDurationHours, pool.AutoReleaseEnabled,
-			pool.MaxClusterAgeDays, pool.AutoRefreshEnabled,
+			pool.MaxClusterAgeDays, pool.AutoRefreshEnabled, pool.ReconcileDriftEnabled,
 			pool.ScheduledMode, pool.ScheduleTimezone, pool.ScheduleStartHour, pool.ScheduleEndHour, pool.ScheduleDaysOfWeek,
 			pool.ClusterConfig, pool.Enabled, pool.CreatedBy,
 		)
@@ -53,7 +53,7 @@ func (s *PoolStore) Create(ctx context.Context, tx pgx.Tx, pool *types.ClusterPo
 			pool.Name, pool.DisplayName, pool.Description, pool.Profile,
 			pool.TargetSize, pool.MinSize, pool.MaxSize,
 			pool.DefaultLeaseDurationHours, pool.MaxLeaseDurationHours, pool.AutoReleaseEnabled,
-			pool.MaxClusterAgeDays, pool.AutoRefreshEnabled,
+			pool.MaxClusterAgeDays, pool.AutoRefreshEnabled, pool.ReconcileDriftEnabled,
 			pool.ScheduledMode, pool.ScheduleTimezone, pool.ScheduleStartHour, pool.ScheduleEndHour, pool.ScheduleDaysOfWeek,
 			pool.ClusterConfig, pool.Enabled, pool.CreatedBy,
 		)
@@ -69,7 +69,7 @@ func (s *PoolStore) GetByID(ctx context.Context, poolID string) (*types.ClusterP
 			id, name, display_name, description, profile,
 			target_size, min_size, max_size,
 			default_lease_duration_hours, max_lease_duration_hours, auto_release_enabled,
-			max_cluster_age_days, auto_refresh_enabled,
+			max_cluster_age_days, auto_refresh_enabled, reconcile_drift_enabled,
 			scheduled_mode, schedule_timezone, schedule_start_hour, schedule_end_hour, schedule_days_of_week,
 			cluster_config, enabled, created_at, updated_at, created_by
 		FROM cluster_pools
@@ -83,7 +83,7 @@ func (s *PoolStore) GetByID(ctx context.Context, poolID string) (*types.ClusterP
 		&pool.ID, &pool.Name, &pool.DisplayName, &pool.Description, &pool.Profile,
 		&pool.TargetSize, &pool.MinSize, &pool.MaxSize,
 		&pool.DefaultLeaseDurationHours, &pool.MaxLeaseDurationHours, &pool.AutoReleaseEnabled,
-		&pool.MaxClusterAgeDays, &pool.AutoRefreshEnabled,
+		&pool.MaxClusterAgeDays, &pool.AutoRefreshEnabled, &pool.ReconcileDriftEnabled,
 		&pool.ScheduledMode, &pool.ScheduleTimezone, &pool.ScheduleStartHour, &pool.ScheduleEndHour, &pool.ScheduleDaysOfWeek,
 		&pool.ClusterConfig, &pool.Enabled, &pool.CreatedAt, &pool.UpdatedAt, &pool.CreatedBy,
 	)
@@ -105,7 +105,7 @@ func (s *PoolStore) GetByName(ctx context.Context, name string) (*types.ClusterP
 			id, name, display_name, description, profile,
 			target_size, min_size, max_size,
 			default_lease_duration_hours, max_lease_duration_hours, auto_release_enabled,
-			max_cluster_age_days, auto_refresh_enabled,
+			max_cluster_age_days, auto_refresh_enabled, reconcile_drift_enabled,
 			scheduled_mode, schedule_timezone, schedule_start_hour, schedule_end_hour, schedule_days_of_week,
 			cluster_config, enabled, created_at, updated_at, created_by
 		FROM cluster_pools
@@ -119,7 +119,7 @@ func (s *PoolStore) GetByName(ctx context.Context, name string) (*types.ClusterP
 		&pool.ID, &pool.Name, &pool.DisplayName, &pool.Description, &pool.Profile,
 		&pool.TargetSize, &pool.MinSize, &pool.MaxSize,
 		&pool.DefaultLeaseDurationHours, &pool.MaxLeaseDurationHours, &pool.AutoReleaseEnabled,
-		&pool.MaxClusterAgeDays, &pool.AutoRefreshEnabled,
+		&pool.MaxClusterAgeDays, &pool.AutoRefreshEnabled, &pool.ReconcileDriftEnabled,
 		&pool.ScheduledMode, &pool.ScheduleTimezone, &pool.ScheduleStartHour, &pool.ScheduleEndHour, &pool.ScheduleDaysOfWeek,
 		&pool.ClusterConfig, &pool.Enabled, &pool.CreatedAt, &pool.UpdatedAt, &pool.CreatedBy,
 	)
@@ -141,7 +141,7 @@ func (s *PoolStore) List(ctx context.Context, enabledOnly bool) ([]*types.Cluste
 			cp.id, cp.name, cp.display_name, cp.description, cp.profile,
 			cp.target_size, cp.min_size, cp.max_size,
 			cp.default_lease_duration_hours, cp.max_lease_duration_hours, cp.auto_release_enabled,
-			cp.max_cluster_age_days, cp.auto_refresh_enabled,
+			cp.max_cluster_age_days, cp.auto_refresh_enabled, cp.reconcile_drift_enabled,
 			cp.scheduled_mode, cp.schedule_timezone, cp.schedule_start_hour, cp.schedule_end_hour, cp.schedule_days_of_week,
 			cp.cluster_config, cp.enabled, cp.created_at, cp.updated_at,
 			COALESCE(u.username, cp.created_by) as created_by
@@ -169,7 +169,7 @@ func (s *PoolStore) List(ctx context.Context, enabledOnly bool) ([]*types.Cluste
 			&pool.ID, &pool.Name, &pool.DisplayName, &pool.Description, &pool.Profile,
 			&pool.TargetSize, &pool.MinSize, &pool.MaxSize,
 			&pool.DefaultLeaseDurationHours, &pool.MaxLeaseDurationHours, &pool.AutoReleaseEnabled,
-			&pool.MaxClusterAgeDays, &pool.AutoRefreshEnabled,
+			&pool.MaxClusterAgeDays, &pool.AutoRefreshEnabled, &pool.ReconcileDriftEnabled,
 			&pool.ScheduledMode, &pool.ScheduleTimezone, &pool.ScheduleStartHour, &pool.ScheduleEndHour, &pool.ScheduleDaysOfWeek,
 			&pool.ClusterConfig, &pool.Enabled, &pool.CreatedAt, &pool.UpdatedAt, &pool.CreatedBy,
 		)
@@ -325,8 +325,9 @@ func (s *PoolStore) LeaseCluster(ctx context.Context, poolName string, request *
 		leaseDurationHours = *request.Duration
 	}
 
-	// Atomically find and lease a READY cluster
+	// Atomically find and lease a READY cluster, recording a LEASED event
 	query := `
+		WITH leased AS (
 		UPDATE clusters
 		SET pool_state = 'LEASED',
 			leased_by = $1,
@@ -350,13 +351,20 @@ func (s *PoolStore) LeaseCluster(ctx context.Context, poolName string, request *
 			work_hours_enabled, work_hours_start, work_hours_end, work_days, last_work_hours_check,
 			post_deploy_status, post_deploy_completed_at,
 			skip_post_deployment, custom_post_config, storage_config,
-			preserve_on_failure, credentials_mode, custom_pull_secret,
+			preserve_on_failure, credentials_mode, custom_pull_secret, compute_overrides, restricted_network, architecture,
 			pool_id, pool_state, leased_by, leased_at, lease_expires_at, lease_metadata,
 			pool_generation, last_cleaned_at
+		), event AS (
+			INSERT INTO cluster_events (cluster_id, cluster_name, event_type, from_status, to_status, actor, reason, job_id)
+			SELECT id, name, 'LEASED', 'READY', 'LEASED', $1, $5, $6 FROM leased
+		)
+		SELECT * FROM leased
 	`
 
+	_, reason, jobID := eventArgs(EventSourceFromContext(ctx))
+
 	cluster := &types.Cluster{}
-	row := s.pool.QueryRow(ctx, query, request.LeasedBy, leaseDurationHours, request.Metadata, pool.ID)
+	row := s.pool.QueryRow(ctx, query, request.LeasedBy, leaseDurationHours, request.Metadata, pool.ID, reason, jobID)
 
 	err = scanCluster(row, cluster)
 	if err == pgx.ErrNoRows {
@@ -369,27 +377,37 @@ func (s *PoolStore) LeaseCluster(ctx context.Context, poolName string, request *
 	return cluster, nil
 }
 
-// ReleaseCluster releases a leased cluster back to the pool
+// ReleaseCluster releases a leased cluster back to the pool and records a
+// RELEASED event attributed to the context's EventSource
 func (s *PoolStore) ReleaseCluster(ctx context.Context, clusterID string) error {
 	// Transition cluster to CLEANING state and clear lease information
 	query := `
-		UPDATE clusters
-		SET pool_state = 'CLEANING',
-			leased_by = NULL,
-			leased_at = NULL,
-			lease_expires_at = NULL,
-			lease_metadata = NULL,
-			updated_at = NOW()
-		WHERE id = $1
-		AND pool_state = 'LEASED'
+		WITH released AS (
+			UPDATE clusters
+			SET pool_state = 'CLEANING',
+				leased_by = NULL,
+				leased_at = NULL,
+				lease_expires_at = NULL,
+				lease_metadata = NULL,
+				updated_at = NOW()
+			WHERE id = $1
+			AND pool_state = 'LEASED'
+			RETURNING id, name
+		), event AS (
+			INSERT INTO cluster_events (cluster_id, cluster_name, event_type, from_status, to_status, actor, reason, job_id)
+			SELECT id, name, 'RELEASED', 'LEASED', 'CLEANING', $2, $3, $4 FROM released
+		)
+		SELECT COUNT(*) FROM released
 	`
 
-	result, err := s.pool.Exec(ctx, query, clusterID)
-	if err != nil {
+	actor, reason, jobID := eventArgs(EventSourceFromContext(ctx))
+
+	var released int
+	if err := s.pool.QueryRow(ctx, query, clusterID, actor, reason, jobID).Scan(&released); err != nil {
 		return err
 	}
 
-	if result.RowsAffected() == 0 {
+	if released == 0 {
 		return fmt.Errorf("cluster %s is not leased or does not exist", clusterID)
 	}
 
@@ -410,6 +428,9 @@ func scanCluster(row pgx.Row, cluster *types.Cluster) error {
 		&cluster.LastWorkHoursCheck, &cluster.PostDeployStatus, &cluster.PostDeployCompletedAt,
 		&cluster.SkipPostDeployment, &cluster.CustomPostConfig, &cluster.StorageConfig,
 		&cluster.PreserveOnFailure, &cluster.CredentialsMode, &cluster.CustomPullSecret,
+		&cluster.ComputeOverrides,
+		&cluster.RestrictedNetwork,
+		&cluster.Architecture,
 		&cluster.PoolID, &cluster.PoolState, &cluster.LeasedBy, &cluster.LeasedAt,
 		&cluster.LeaseExpiresAt, &cluster.LeaseMetadata, &cluster.PoolGeneration, &cluster.LastCleanedAt,
 	)
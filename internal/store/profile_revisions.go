@@ -0,0 +1,358 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+const profileRevisionColumns = `
+	profile_name, revision, status, source, definition, profile_data, COALESCE(diff, ''),
+	COALESCE(message, ''), author_id, author_email, created_at, published_at
+`
+
+// scanProfileRevision scans a row selected with profileRevisionColumns
+func scanProfileRevision(row pgx.Row) (*types.ProfileRevision, error) {
+	rev := &types.ProfileRevision{}
+	err := row.Scan(
+		&rev.ProfileName,
+		&rev.Revision,
+		&rev.Status,
+		&rev.Source,
+		&rev.Definition,
+		&rev.ProfileData,
+		&rev.Diff,
+		&rev.Message,
+		&rev.AuthorID,
+		&rev.AuthorEmail,
+		&rev.CreatedAt,
+		&rev.PublishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// CreateProfileRevision saves a new revision of a profile, numbered after the
+// latest one. A revision with status published replaces the published
+// profile in the same transaction. Returns ErrConflict if another revision
+// of the profile was saved concurrently.
+func (s *Store) CreateProfileRevision(ctx context.Context, rev *types.ProfileRevision) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertProfileRevision(ctx, tx, rev); err != nil {
+		return err
+	}
+
+	if rev.Status == types.ProfileRevisionPublished {
+		if err := publishProfileRevision(ctx, tx, rev); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit profile revision: %w", err)
+	}
+	return nil
+}
+
+func insertProfileRevision(ctx context.Context, tx pgx.Tx, rev *types.ProfileRevision) error {
+	query := `
+		INSERT INTO profile_revisions (
+			profile_name, revision, status, source, definition, profile_data, diff,
+			message, author_id, author_email, created_at
+		)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, NOW()
+		FROM profile_revisions
+		WHERE profile_name = $1
+		RETURNING revision, created_at
+	`
+
+	err := tx.QueryRow(ctx, query,
+		rev.ProfileName,
+		types.ProfileRevisionDraft,
+		rev.Source,
+		rev.Definition,
+		rev.ProfileData,
+		rev.Diff,
+		rev.Message,
+		rev.AuthorID,
+		rev.AuthorEmail,
+	).Scan(&rev.Revision, &rev.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrConflict
+		}
+		return fmt.Errorf("create profile revision: %w", err)
+	}
+	return nil
+}
+
+// publishProfileRevision supersedes the published revision, marks rev
+// published and copies it into the profiles table
+func publishProfileRevision(ctx context.Context, tx pgx.Tx, rev *types.ProfileRevision) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE profile_revisions SET status = $2
+		WHERE profile_name = $1 AND status = $3
+	`, rev.ProfileName, types.ProfileRevisionSuperseded, types.ProfileRevisionPublished)
+	if err != nil {
+		return fmt.Errorf("supersede published revision: %w", err)
+	}
+
+	var publishedAt time.Time
+	err = tx.QueryRow(ctx, `
+		UPDATE profile_revisions SET status = $3, published_at = NOW()
+		WHERE profile_name = $1 AND revision = $2
+		RETURNING published_at
+	`, rev.ProfileName, rev.Revision, types.ProfileRevisionPublished).Scan(&publishedAt)
+	if err != nil {
+		return fmt.Errorf("publish profile revision: %w", err)
+	}
+	rev.Status = types.ProfileRevisionPublished
+	rev.PublishedAt = &publishedAt
+
+	var p profile.Profile
+	if err := json.Unmarshal(rev.ProfileData, &p); err != nil {
+		return fmt.Errorf("unmarshal profile data: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO profiles (name, display_name, description, platform, cluster_type, track, enabled, profile_data, source, revision)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (name) DO UPDATE SET
+			display_name = EXCLUDED.display_name,
+			description = EXCLUDED.description,
+			platform = EXCLUDED.platform,
+			cluster_type = EXCLUDED.cluster_type,
+			track = EXCLUDED.track,
+			enabled = EXCLUDED.enabled,
+			profile_data = EXCLUDED.profile_data,
+			source = EXCLUDED.source,
+			revision = EXCLUDED.revision,
+			updated_at = NOW()
+	`,
+		p.Name,
+		p.DisplayName,
+		p.Description,
+		p.Platform,
+		p.ClusterType,
+		p.Track,
+		p.Enabled,
+		rev.ProfileData,
+		rev.Source,
+		rev.Revision,
+	)
+	if err != nil {
+		return fmt.Errorf("update published profile: %w", err)
+	}
+	return nil
+}
+
+// PublishProfileRevision publishes a draft revision, superseding the
+// published one. Returns ErrNotFound if the revision does not exist and
+// ErrConflict if it is not a draft.
+func (s *Store) PublishProfileRevision(ctx context.Context, name string, revision int) (*types.ProfileRevision, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `SELECT ` + profileRevisionColumns + `
+		FROM profile_revisions
+		WHERE profile_name = $1 AND revision = $2
+		FOR UPDATE`
+	rev, err := scanProfileRevision(tx.QueryRow(ctx, query, name, revision))
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get profile revision: %w", err)
+	}
+	if rev.Status != types.ProfileRevisionDraft {
+		return nil, ErrConflict
+	}
+
+	if err := publishProfileRevision(ctx, tx, rev); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit profile revision: %w", err)
+	}
+	return rev, nil
+}
+
+// GetProfileRevision retrieves one revision of a profile
+func (s *Store) GetProfileRevision(ctx context.Context, name string, revision int) (*types.ProfileRevision, error) {
+	query := `SELECT ` + profileRevisionColumns + ` FROM profile_revisions WHERE profile_name = $1 AND revision = $2`
+
+	rev, err := scanProfileRevision(s.pool.QueryRow(ctx, query, name, revision))
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get profile revision: %w", err)
+	}
+
+	return rev, nil
+}
+
+// ListProfileRevisions returns the revisions of a profile, newest first
+func (s *Store) ListProfileRevisions(ctx context.Context, name string) ([]*types.ProfileRevision, error) {
+	query := `SELECT ` + profileRevisionColumns + `
+		FROM profile_revisions
+		WHERE profile_name = $1
+		ORDER BY revision DESC`
+
+	rows, err := s.pool.Query(ctx, query, name)
+	if err != nil {
+		return nil, fmt.Errorf("list profile revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*types.ProfileRevision{}
+	for rows.Next() {
+		rev, err := scanProfileRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan profile revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate profile revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetProfileSource returns where a profile is managed and its published
+// revision (0 for profiles published before revisions were recorded)
+func (s *Store) GetProfileSource(ctx context.Context, name string) (types.ProfileSource, int, error) {
+	var source types.ProfileSource
+	var revision *int
+	err := s.pool.QueryRow(ctx, `SELECT source, revision FROM profiles WHERE name = $1`, name).Scan(&source, &revision)
+	if err == pgx.ErrNoRows {
+		return "", 0, ErrNotFound
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("get profile source: %w", err)
+	}
+	if revision == nil {
+		return source, 0, nil
+	}
+	return source, *revision, nil
+}
+
+// SyncFileProfile publishes a profile loaded from the profile files, with
+// definition as the file content. Nothing is written if the profile is
+// unchanged or has been taken over by the API; changed reports whether a
+// revision was published. Concurrent syncs from several replicas publish a
+// change once.
+func (s *Store) SyncFileProfile(ctx context.Context, p *profile.Profile, definition string) (bool, error) {
+	profileData, err := json.Marshal(p)
+	if err != nil {
+		return false, fmt.Errorf("marshal profile: %w", err)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize replicas syncing the same profile, including its first sync
+	// when there is no row to lock yet
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('profile:' || $1))`, p.Name); err != nil {
+		return false, fmt.Errorf("lock profile: %w", err)
+	}
+
+	var source types.ProfileSource
+	var currentData []byte
+	var unchanged bool
+	err = tx.QueryRow(ctx,
+		`SELECT source, profile_data, profile_data = $2::jsonb FROM profiles WHERE name = $1`,
+		p.Name, profileData,
+	).Scan(&source, &currentData, &unchanged)
+	exists := err == nil
+	if err != nil && err != pgx.ErrNoRows {
+		return false, fmt.Errorf("get profile: %w", err)
+	}
+	if exists && (source == types.ProfileSourceAPI || unchanged) {
+		return false, nil
+	}
+
+	var current *profile.Profile
+	if exists {
+		current = &profile.Profile{}
+		if err := json.Unmarshal(currentData, current); err != nil {
+			return false, fmt.Errorf("unmarshal profile data: %w", err)
+		}
+	}
+	diff, err := profile.Diff(current, p)
+	if err != nil {
+		return false, err
+	}
+
+	rev := &types.ProfileRevision{
+		ProfileName: p.Name,
+		Status:      types.ProfileRevisionPublished,
+		Source:      types.ProfileSourceFile,
+		Definition:  definition,
+		ProfileData: profileData,
+		Diff:        diff,
+		Message:     "Synced from profile files",
+	}
+	if err := insertProfileRevision(ctx, tx, rev); err != nil {
+		return false, err
+	}
+	if err := publishProfileRevision(ctx, tx, rev); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit profile sync: %w", err)
+	}
+	return true, nil
+}
+
+// ProfilesChangeMarker returns a value that changes whenever a profile is
+// added, updated or removed. Registries poll it to reload published profiles
+// across replicas. It reads the profile_changes counter, which every write to
+// profiles bumps in the same transaction.
+func (s *Store) ProfilesChangeMarker(ctx context.Context) (string, error) {
+	var version int64
+	err := s.pool.QueryRow(ctx, `SELECT version FROM profile_changes`).Scan(&version)
+	if err != nil {
+		return "", fmt.Errorf("get profile change marker: %w", err)
+	}
+	return fmt.Sprintf("%d", version), nil
+}
+
+// CountProfileUsage returns the number of clusters that are not destroyed and
+// the number of pools that reference a profile
+func (s *Store) CountProfileUsage(ctx context.Context, name string) (clusters int, pools int, err error) {
+	err = s.pool.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM clusters WHERE profile = $1 AND status NOT IN ('DESTROYED', 'FAILED')),
+			(SELECT COUNT(*) FROM cluster_pools WHERE profile = $1)
+	`, name).Scan(&clusters, &pools)
+	if err != nil {
+		return 0, 0, fmt.Errorf("count profile usage: %w", err)
+	}
+	return clusters, pools, nil
+}
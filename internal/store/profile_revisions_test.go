@@ -0,0 +1,97 @@
+package store_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/dbtest"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestProfileRevisions_Lifecycle(t *testing.T) {
+	s := dbtest.New(t)
+	ctx := context.Background()
+
+	p, err := profile.NewLoader("../profile/definitions").Load("aws-sno-test")
+	require.NoError(t, err)
+	p.Name = "test-profile-" + uuid.New().String()[:8]
+
+	marker, err := s.ProfilesChangeMarker(ctx)
+	require.NoError(t, err)
+
+	// The first file sync publishes revision 1, an identical sync is a no-op
+	changed, err := s.SyncFileProfile(ctx, p, "name: "+p.Name+"\n")
+	require.NoError(t, err)
+	require.True(t, changed)
+	changed, err = s.SyncFileProfile(ctx, p, "name: "+p.Name+"\n")
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	next, err := s.ProfilesChangeMarker(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, marker, next)
+
+	source, revision, err := s.GetProfileSource(ctx, p.Name)
+	require.NoError(t, err)
+	assert.Equal(t, types.ProfileSourceFile, source)
+	assert.Equal(t, 1, revision)
+
+	// A draft does not change the published profile until it is published
+	p.Lifecycle.MaxTTLHours = 12
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	draft := &types.ProfileRevision{
+		ProfileName: p.Name,
+		Status:      types.ProfileRevisionDraft,
+		Source:      types.ProfileSourceAPI,
+		Definition:  "name: " + p.Name + "\n",
+		ProfileData: data,
+		Message:     "shorter TTL",
+	}
+	require.NoError(t, s.CreateProfileRevision(ctx, draft))
+	assert.Equal(t, 2, draft.Revision)
+
+	published, err := s.GetProfile(ctx, p.Name)
+	require.NoError(t, err)
+	assert.NotEqual(t, 12, published.Lifecycle.MaxTTLHours)
+
+	rev, err := s.PublishProfileRevision(ctx, p.Name, draft.Revision)
+	require.NoError(t, err)
+	assert.Equal(t, types.ProfileRevisionPublished, rev.Status)
+	require.NotNil(t, rev.PublishedAt)
+	_, err = s.PublishProfileRevision(ctx, p.Name, draft.Revision)
+	assert.ErrorIs(t, err, store.ErrConflict)
+
+	published, err = s.GetProfile(ctx, p.Name)
+	require.NoError(t, err)
+	assert.Equal(t, 12, published.Lifecycle.MaxTTLHours)
+
+	revisions, err := s.ListProfileRevisions(ctx, p.Name)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, types.ProfileRevisionPublished, revisions[0].Status)
+	assert.Equal(t, types.ProfileRevisionSuperseded, revisions[1].Status)
+
+	// The profile now belongs to the API and file syncs leave it alone
+	changed, err = s.SyncFileProfile(ctx, p, "name: "+p.Name+"\n")
+	require.NoError(t, err)
+	assert.False(t, changed)
+	source, revision, err = s.GetProfileSource(ctx, p.Name)
+	require.NoError(t, err)
+	assert.Equal(t, types.ProfileSourceAPI, source)
+	assert.Equal(t, 2, revision)
+
+	// Deleting keeps the history
+	require.NoError(t, s.DeleteProfile(ctx, p.Name))
+	assert.ErrorIs(t, s.DeleteProfile(ctx, p.Name), store.ErrNotFound)
+	revisions, err = s.ListProfileRevisions(ctx, p.Name)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, types.ProfileRevisionSuperseded, revisions[0].Status)
+}
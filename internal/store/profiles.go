@@ -149,25 +149,43 @@ func (s *Store) UpsertProfile(ctx context.Context, p *profile.Profile) error {
 	return nil
 }
 
-// DeleteProfile deletes a profile from the database
+// DeleteProfile deletes a profile from the database. Its revisions are kept,
+// with the published one marked superseded, so it can be restored later.
 func (s *Store) DeleteProfile(ctx context.Context, name string) error {
-	query := `DELETE FROM profiles WHERE name = $1`
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	result, err := s.pool.Exec(ctx, query, name)
+	result, err := tx.Exec(ctx, `DELETE FROM profiles WHERE name = $1`, name)
 	if err != nil {
 		return fmt.Errorf("failed to delete profile: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("profile not found: %s", name)
+		return ErrNotFound
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE profile_revisions SET status = $2
+		WHERE profile_name = $1 AND status = $3
+	`, name, types.ProfileRevisionSuperseded, types.ProfileRevisionPublished)
+	if err != nil {
+		return fmt.Errorf("failed to supersede profile revisions: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit profile delete: %w", err)
 	}
 
 	return nil
 }
 
-// DeleteProfilesNotIn removes every profile whose name is not in keep, returning
-// the number deleted. The startup sync calls this after upserting the current
-// YAML profiles so that a profile whose source file was renamed or removed does
+// DeleteProfilesNotIn removes every file-synced profile whose name is not in
+// keep, returning the number deleted. Profiles managed through the API are
+// never pruned. The startup sync calls this after syncing the current YAML
+// profiles so that a profile whose source file was renamed or removed does
 // not linger as a stale row. Without it, renaming azure-aro-standard.yaml to
 // aro-standard.yaml left both rows in the table and the UI rendered two identical
 // ARO cards.
@@ -178,11 +196,19 @@ func (s *Store) DeleteProfilesNotIn(ctx context.Context, keep []string) (int, er
 		return 0, nil
 	}
 
-	result, err := s.pool.Exec(ctx, `DELETE FROM profiles WHERE name != ALL($1)`, keep)
+	result, err := s.pool.Exec(ctx, `DELETE FROM profiles WHERE source = $2 AND name != ALL($1)`, keep, types.ProfileSourceFile)
 	if err != nil {
 		return 0, fmt.Errorf("failed to prune profiles: %w", err)
 	}
 
+	_, err = s.pool.Exec(ctx, `
+		UPDATE profile_revisions SET status = $1
+		WHERE status = $2 AND profile_name NOT IN (SELECT name FROM profiles)
+	`, types.ProfileRevisionSuperseded, types.ProfileRevisionPublished)
+	if err != nil {
+		return 0, fmt.Errorf("failed to supersede pruned profile revisions: %w", err)
+	}
+
 	return int(result.RowsAffected()), nil
 }
 
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// RateLimitStore handles rate limit policies and the shared request counters
+// used by the Postgres rate limit backend
+type RateLimitStore struct {
+	pool *pgxpool.Pool
+}
+
+// IncrementCounter atomically increments the counter for key in the window
+// starting at windowStart and returns the new count. Every API replica writes
+// to the same row, so the count is global across replicas.
+func (s *RateLimitStore) IncrementCounter(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	query := `
+		INSERT INTO rate_limit_counters (bucket_key, window_start, request_count)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (bucket_key, window_start)
+		DO UPDATE SET request_count = rate_limit_counters.request_count + 1
+		RETURNING request_count
+	`
+
+	var count int
+	if err := s.pool.QueryRow(ctx, query, key, windowStart).Scan(&count); err != nil {
+		return 0, fmt.Errorf("increment rate limit counter: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteCountersBefore removes counters for windows that started before the given time
+func (s *RateLimitStore) DeleteCountersBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.pool.Exec(ctx, `DELETE FROM rate_limit_counters WHERE window_start < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired rate limit counters: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ListPolicies returns all rate limit policies
+func (s *RateLimitStore) ListPolicies(ctx context.Context) ([]*types.RateLimitPolicy, error) {
+	query := `
+		SELECT id, scope, subject, requests_per_minute, burst, description, created_by, created_at, updated_at
+		FROM rate_limit_policies
+		ORDER BY scope, subject
+	`
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list rate limit policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := []*types.RateLimitPolicy{}
+	for rows.Next() {
+		p := &types.RateLimitPolicy{}
+		if err := rows.Scan(
+			&p.ID,
+			&p.Scope,
+			&p.Subject,
+			&p.RequestsPerMinute,
+			&p.Burst,
+			&p.Description,
+			&p.CreatedBy,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan rate limit policy: %w", err)
+		}
+		policies = append(policies, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rate limit policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+// GetPolicy retrieves a rate limit policy by ID
+func (s *RateLimitStore) GetPolicy(ctx context.Context, id string) (*types.RateLimitPolicy, error) {
+	query := `
+		SELECT id, scope, subject, requests_per_minute, burst, description, created_by, created_at, updated_at
+		FROM rate_limit_policies
+		WHERE id = $1
+	`
+
+	p := &types.RateLimitPolicy{}
+	err := s.pool.QueryRow(ctx, query, id).Scan(
+		&p.ID,
+		&p.Scope,
+		&p.Subject,
+		&p.RequestsPerMinute,
+		&p.Burst,
+		&p.Description,
+		&p.CreatedBy,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get rate limit policy: %w", err)
+	}
+
+	return p, nil
+}
+
+// CreatePolicy inserts a new rate limit policy. It returns ErrConflict if a
+// policy already exists for the same scope and subject.
+func (s *RateLimitStore) CreatePolicy(ctx context.Context, p *types.RateLimitPolicy) error {
+	query := `
+		INSERT INTO rate_limit_policies (scope, subject, requests_per_minute, burst, description, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+
+	err := s.pool.QueryRow(ctx, query,
+		p.Scope,
+		p.Subject,
+		p.RequestsPerMinute,
+		p.Burst,
+		p.Description,
+		p.CreatedBy,
+	).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrConflict
+		}
+		return fmt.Errorf("create rate limit policy: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePolicy updates the limits and description of an existing policy
+func (s *RateLimitStore) UpdatePolicy(ctx context.Context, p *types.RateLimitPolicy) error {
+	query := `
+		UPDATE rate_limit_policies
+		SET requests_per_minute = $1, burst = $2, description = $3, updated_at = NOW()
+		WHERE id = $4
+		RETURNING updated_at
+	`
+
+	err := s.pool.QueryRow(ctx, query, p.RequestsPerMinute, p.Burst, p.Description, p.ID).Scan(&p.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update rate limit policy: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePolicy removes a rate limit policy
+func (s *RateLimitStore) DeletePolicy(ctx context.Context, id string) error {
+	result, err := s.pool.Exec(ctx, `DELETE FROM rate_limit_policies WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete rate limit policy: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
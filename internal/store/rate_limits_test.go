@@ -0,0 +1,77 @@
+package store_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/dbtest"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestRateLimitStore_IncrementCounter_IsAtomic(t *testing.T) {
+	s := dbtest.New(t)
+	ctx := context.Background()
+	key := "test|user:" + uuid.New().String()
+	window := time.Now().Truncate(time.Minute)
+
+	// Simulate several API replicas counting the same bucket concurrently
+	const requests = 20
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.RateLimits.IncrementCounter(ctx, key, window)
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	count, err := s.RateLimits.IncrementCounter(ctx, key, window)
+	require.NoError(t, err)
+	require.Equal(t, requests+1, count)
+
+	// A new window starts a fresh count
+	count, err = s.RateLimits.IncrementCounter(ctx, key, window.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestRateLimitStore_PolicyCRUD(t *testing.T) {
+	s := dbtest.New(t)
+	ctx := context.Background()
+	subject := uuid.New().String()
+
+	policy := &types.RateLimitPolicy{
+		Scope:             types.RateLimitScopeUser,
+		Subject:           subject,
+		RequestsPerMinute: 1000,
+		Burst:             100,
+	}
+	require.NoError(t, s.RateLimits.CreatePolicy(ctx, policy))
+	require.NotEmpty(t, policy.ID)
+
+	duplicate := &types.RateLimitPolicy{
+		Scope:             types.RateLimitScopeUser,
+		Subject:           subject,
+		RequestsPerMinute: 10,
+		Burst:             1,
+	}
+	require.ErrorIs(t, s.RateLimits.CreatePolicy(ctx, duplicate), store.ErrConflict)
+
+	policy.RequestsPerMinute = 2000
+	require.NoError(t, s.RateLimits.UpdatePolicy(ctx, policy))
+
+	got, err := s.RateLimits.GetPolicy(ctx, policy.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2000, got.RequestsPerMinute)
+
+	require.NoError(t, s.RateLimits.DeletePolicy(ctx, policy.ID))
+	_, err = s.RateLimits.GetPolicy(ctx, policy.ID)
+	require.ErrorIs(t, err, store.ErrNotFound)
+}
@@ -62,6 +62,10 @@ type Store struct {
 	TeamMemberships          *TeamMembershipStore
 	Pools                    *PoolStore
 	Reports                  *ReportStore
+	RateLimits               *RateLimitStore
+	ClusterEvents            *ClusterEventStore
+	ClusterSpecs             *ClusterSpecStore
+	ConfigDrift              *ConfigDriftStore
 }
 
 // New creates a new Store with all sub-stores initialized using the provided database connection pool.
@@ -103,6 +107,10 @@ func New(pool *pgxpool.Pool) *Store {
 	s.TeamMemberships = &TeamMembershipStore{db: pool}
 	s.Pools = &PoolStore{pool: pool}
 	s.Reports = &ReportStore{pool: pool}
+	s.RateLimits = &RateLimitStore{pool: pool}
+	s.ClusterEvents = &ClusterEventStore{pool: pool}
+	s.ClusterSpecs = &ClusterSpecStore{pool: pool}
+	s.ConfigDrift = &ConfigDriftStore{pool: pool}
 
 	return s
 }
@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+)
+
+// ValidateSecureHost checks that a host does not resolve to a private,
+// loopback or link-local address
+func ValidateSecureHost(hostname string) error {
+	// Check for localhost/loopback addresses
+	if hostname == "localhost" || hostname == "127.0.0.1" || hostname == "::1" {
+		return fmt.Errorf("localhost URLs are not allowed")
+	}
+
+	// Resolve DNS to get IP addresses
+	ips, err := net.LookupHost(hostname)
+	if err != nil {
+		return fmt.Errorf("failed to resolve hostname %s: %w", hostname, err)
+	}
+
+	// Check each resolved IP
+	for _, ipStr := range ips {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			continue
+		}
+
+		// Block private IP ranges (10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16)
+		if ip.IsPrivate() {
+			return fmt.Errorf("private IP addresses are not allowed: %s", ipStr)
+		}
+
+		// Block loopback addresses
+		if ip.IsLoopback() {
+			return fmt.Errorf("loopback addresses are not allowed: %s", ipStr)
+		}
+
+		// Block link-local addresses (169.254.0.0/16 for IPv4, fe80::/10 for IPv6)
+		if ip.IsLinkLocalUnicast() {
+			return fmt.Errorf("link-local addresses are not allowed: %s", ipStr)
+		}
+
+		// Block AWS metadata endpoint specifically (169.254.169.254)
+		if ipStr == "169.254.169.254" {
+			return fmt.Errorf("AWS metadata endpoint is blocked")
+		}
+	}
+
+	return nil
+}
@@ -3,9 +3,13 @@ package validation
 import (
 	"fmt"
 	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
@@ -31,6 +35,23 @@ const (
 
 	// MaxHelmChartsPerCluster is the maximum number of custom Helm charts per cluster
 	MaxHelmChartsPerCluster = 5
+
+	// MaxReadinessTimeout is the maximum timeout for a single readiness check
+	MaxReadinessTimeout = 1 * time.Hour
+
+	// MaxReadinessChecksPerTask is the maximum number of readiness checks on one task
+	MaxReadinessChecksPerTask = 10
+)
+
+var (
+	// scpLikeGitURL matches the user@host:path form git accepts for SSH remotes
+	scpLikeGitURL = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:[^/]`)
+
+	// chartDigestPattern matches the content digest of an OCI chart
+	chartDigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+	// secretNamePattern matches the names the secrets backend accepts
+	secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_+=.@-]+(/[A-Za-z0-9_+=.@-]+)*$`)
 )
 
 // PostConfigValidationError represents a validation error for custom post-config
@@ -108,9 +129,129 @@ func ValidateCustomPostConfig(config *types.CustomPostConfig) []error {
 		}
 	}
 
+	errors = append(errors, validateSecretRefs(config)...)
+
+	return errors
+}
+
+// validateSecretRefs checks the secret references in the config's templated
+// fields. Names have the form <team>/<name>; the team a cluster may read
+// from is checked by ValidateSecretRefTeams.
+func validateSecretRefs(config *types.CustomPostConfig) []error {
+	var errors []error
+	for _, field := range templateFields(config) {
+		refs, err := postconfig.SecretRefs(field.template)
+		if err != nil {
+			errors = append(errors, &PostConfigValidationError{
+				Field:   field.path,
+				Message: fmt.Sprintf("invalid template: %v", err),
+			})
+			continue
+		}
+		for _, ref := range refs {
+			team, name, _ := strings.Cut(ref, "/")
+			if team == "" || name == "" || !isValidSecretName(ref) {
+				errors = append(errors, &PostConfigValidationError{
+					Field:   field.path,
+					Message: fmt.Sprintf("invalid secret reference %q (use <team>/<name>)", ref),
+				})
+			}
+		}
+	}
+	return errors
+}
+
+// ValidateSecretRefTeams checks that every secret the config references,
+// including Git credentials, belongs to team, the team of the cluster the
+// config is applied to
+func ValidateSecretRefTeams(config *types.CustomPostConfig, team string) []error {
+	if config == nil {
+		return nil
+	}
+
+	var errors []error
+	for i, manifest := range config.Manifests {
+		if manifest.Git == nil || manifest.Git.CredentialsSecret == "" {
+			continue
+		}
+		ref := manifest.Git.CredentialsSecret
+		if refTeam, _, _ := strings.Cut(ref, "/"); refTeam != team {
+			errors = append(errors, &PostConfigValidationError{
+				Field:   fmt.Sprintf("manifests[%d].git.credentialsSecret", i),
+				Message: fmt.Sprintf("secret %s belongs to team %s; clusters of team %s can only reference %s/ secrets", ref, refTeam, team, team),
+			})
+		}
+	}
+	for _, field := range templateFields(config) {
+		refs, err := postconfig.SecretRefs(field.template)
+		if err != nil {
+			continue // Reported by ValidateCustomPostConfig
+		}
+		for _, ref := range refs {
+			if refTeam, _, _ := strings.Cut(ref, "/"); refTeam != team {
+				errors = append(errors, &PostConfigValidationError{
+					Field:   field.path,
+					Message: fmt.Sprintf("secret %s belongs to team %s; clusters of team %s can only reference %s/ secrets", ref, refTeam, team, team),
+				})
+			}
+		}
+	}
 	return errors
 }
 
+// templateField is a config field rendered as a template by the worker
+type templateField struct {
+	path     string
+	template string
+}
+
+// templateFields lists the config's templated fields, including variables,
+// which may reference secrets
+func templateFields(config *types.CustomPostConfig) []templateField {
+	var fields []templateField
+	add := func(path, template string) {
+		if template != "" {
+			fields = append(fields, templateField{path: path, template: template})
+		}
+	}
+	addMap := func(prefix string, m map[string]string) {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			add(prefix+"."+k, m[k])
+		}
+	}
+
+	for i, script := range config.Scripts {
+		prefix := fmt.Sprintf("scripts[%d]", i)
+		add(prefix+".content", script.Content)
+		addMap(prefix+".env", script.Env)
+		addMap(prefix+".variables", script.Variables)
+	}
+	for i, manifest := range config.Manifests {
+		prefix := fmt.Sprintf("manifests[%d]", i)
+		add(prefix+".content", manifest.Content)
+		add(prefix+".namespace", manifest.Namespace)
+		addMap(prefix+".variables", manifest.Variables)
+	}
+	for i, chart := range config.HelmCharts {
+		prefix := fmt.Sprintf("helmCharts[%d]", i)
+		add(prefix+".namespace", chart.Namespace)
+		values := make(map[string]string)
+		for k, v := range chart.Values {
+			if str, ok := v.(string); ok {
+				values[k] = str
+			}
+		}
+		addMap(prefix+".values", values)
+		addMap(prefix+".variables", chart.Variables)
+	}
+	return fields
+}
+
 func validateOperator(op types.CustomOperatorConfig, index int) []error {
 	var errors []error
 	prefix := fmt.Sprintf("operators[%d]", index)
@@ -138,6 +279,9 @@ func validateOperator(op types.CustomOperatorConfig, index int) []error {
 		})
 	}
 
+	errors = append(errors, validateCondition(op.Condition, nil, prefix)...)
+	errors = append(errors, validateReadiness(op.Readiness, prefix)...)
+
 	return errors
 }
 
@@ -246,6 +390,9 @@ func validateScript(script types.CustomScriptConfig, index int) []error {
 		}
 	}
 
+	errors = append(errors, validateCondition(script.Condition, script.Variables, prefix)...)
+	errors = append(errors, validateReadiness(script.Readiness, prefix)...)
+
 	return errors
 }
 
@@ -260,21 +407,29 @@ func validateManifest(manifest types.CustomManifestConfig, index int) []error {
 		})
 	}
 
-	// Must have either content or URL, but not both
+	// Must have exactly one of: content, URL, or git
 	hasContent := manifest.Content != ""
 	hasURL := manifest.URL != ""
+	hasGit := manifest.Git != nil
+
+	count := 0
+	for _, set := range []bool{hasContent, hasURL, hasGit} {
+		if set {
+			count++
+		}
+	}
 
-	if !hasContent && !hasURL {
+	if count == 0 {
 		errors = append(errors, &PostConfigValidationError{
 			Field:   prefix,
-			Message: "manifest must have either 'content' or 'url'",
+			Message: "manifest must have either 'content', 'url', or 'git'",
 		})
 	}
 
-	if hasContent && hasURL {
+	if count > 1 {
 		errors = append(errors, &PostConfigValidationError{
 			Field:   prefix,
-			Message: "manifest cannot have both 'content' and 'url'",
+			Message: "manifest cannot have more than one of 'content', 'url', or 'git'",
 		})
 	}
 
@@ -296,6 +451,21 @@ func validateManifest(manifest types.CustomManifestConfig, index int) []error {
 		}
 	}
 
+	if hasGit {
+		errors = append(errors, validateGitSource(manifest.Git, prefix+".git")...)
+	}
+
+	// Kustomize builds a directory, so it needs a checkout to build from
+	if manifest.Kustomize && !hasGit {
+		errors = append(errors, &PostConfigValidationError{
+			Field:   prefix + ".kustomize",
+			Message: "kustomize requires a 'git' source",
+		})
+	}
+
+	errors = append(errors, validateCondition(manifest.Condition, manifest.Variables, prefix)...)
+	errors = append(errors, validateReadiness(manifest.Readiness, prefix)...)
+
 	return errors
 }
 
@@ -315,7 +485,7 @@ func validateHelmChart(chart types.CustomHelmChartConfig, index int) []error {
 			Field:   prefix + ".repo",
 			Message: "Helm chart repo is required",
 		})
-	} else if err := validateURL(chart.Repo); err != nil {
+	} else if err := validateChartRepo(chart.Repo); err != nil {
 		errors = append(errors, &PostConfigValidationError{
 			Field:   prefix + ".repo",
 			Message: fmt.Sprintf("invalid repo URL: %v", err),
@@ -329,6 +499,141 @@ func validateHelmChart(chart types.CustomHelmChartConfig, index int) []error {
 		})
 	}
 
+	// Digests are only recorded for charts pulled from OCI registries
+	if chart.Digest != "" {
+		if !isOCIChartRepo(chart.Repo) {
+			errors = append(errors, &PostConfigValidationError{
+				Field:   prefix + ".digest",
+				Message: "digest is only supported for oci:// chart repositories",
+			})
+		} else if !chartDigestPattern.MatchString(chart.Digest) {
+			errors = append(errors, &PostConfigValidationError{
+				Field:   prefix + ".digest",
+				Message: "digest must have the form sha256:<64 hex characters>",
+			})
+		}
+	}
+
+	errors = append(errors, validateCondition(chart.Condition, chart.Variables, prefix)...)
+	errors = append(errors, validateReadiness(chart.Readiness, prefix)...)
+
+	return errors
+}
+
+// validateCondition checks that a task condition compiles as a boolean
+// expression over the condition context and the task's custom variables
+func validateCondition(condition string, variables map[string]string, prefix string) []error {
+	if condition == "" {
+		return nil
+	}
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	if err := postconfig.CompileCondition(condition, names); err != nil {
+		return []error{&PostConfigValidationError{
+			Field:   prefix + ".condition",
+			Message: err.Error(),
+		}}
+	}
+	return nil
+}
+
+// ValidateReadiness validates the readiness checks of every task in a
+// post-config, without the per-cluster limits of ValidateCustomPostConfig.
+// Add-on definitions are validated with it.
+func ValidateReadiness(config *types.CustomPostConfig) []error {
+	if config == nil {
+		return nil
+	}
+	var errors []error
+	for i, op := range config.Operators {
+		errors = append(errors, validateReadiness(op.Readiness, fmt.Sprintf("operators[%d]", i))...)
+	}
+	for i, script := range config.Scripts {
+		errors = append(errors, validateReadiness(script.Readiness, fmt.Sprintf("scripts[%d]", i))...)
+	}
+	for i, manifest := range config.Manifests {
+		errors = append(errors, validateReadiness(manifest.Readiness, fmt.Sprintf("manifests[%d]", i))...)
+	}
+	for i, chart := range config.HelmCharts {
+		errors = append(errors, validateReadiness(chart.Readiness, fmt.Sprintf("helmCharts[%d]", i))...)
+	}
+	return errors
+}
+
+// validateReadiness checks a task's readiness checks: each has a known type,
+// the fields that type needs and a valid timeout
+func validateReadiness(checks []types.ReadinessCheck, prefix string) []error {
+	var errors []error
+
+	if len(checks) > MaxReadinessChecksPerTask {
+		errors = append(errors, &PostConfigValidationError{
+			Field:   prefix + ".readiness",
+			Message: fmt.Sprintf("maximum %d readiness checks allowed per task", MaxReadinessChecksPerTask),
+		})
+	}
+
+	for i, check := range checks {
+		field := fmt.Sprintf("%s.readiness[%d]", prefix, i)
+		invalid := func(name, message string) {
+			errors = append(errors, &PostConfigValidationError{Field: field + name, Message: message})
+		}
+
+		switch check.Type {
+		case types.ReadinessCheckCondition:
+			if check.Kind == "" {
+				invalid(".kind", "resource kind is required for condition checks")
+			}
+			if check.Name == "" {
+				invalid(".name", "resource name is required for condition checks")
+			}
+			switch {
+			case check.ConditionType == "" && check.JSONPath == "":
+				invalid("", "condition check must have either 'conditionType' or 'jsonPath'")
+			case check.ConditionType != "" && check.JSONPath != "":
+				invalid("", "condition check cannot have both 'conditionType' and 'jsonPath'")
+			case check.JSONPath != "":
+				if !strings.HasPrefix(check.JSONPath, "{") || !strings.HasSuffix(check.JSONPath, "}") {
+					invalid(".jsonPath", "JSONPath must be wrapped in braces, e.g. '{.status.phase}'")
+				}
+				if check.Value == "" {
+					invalid(".value", "expected value is required with 'jsonPath'")
+				}
+			}
+		case types.ReadinessCheckRollout:
+			if len(check.Deployments) == 0 {
+				invalid(".deployments", "at least one deployment is required for rollout checks")
+			}
+			for _, name := range check.Deployments {
+				if name == "" {
+					invalid(".deployments", "deployment names cannot be empty")
+					break
+				}
+			}
+		case types.ReadinessCheckScript:
+			if check.Script == "" {
+				invalid(".script", "script is required for script checks")
+			} else if len(check.Script) > MaxScriptSize {
+				invalid(".script", fmt.Sprintf("script exceeds maximum size of %d bytes", MaxScriptSize))
+			}
+		default:
+			invalid(".type", fmt.Sprintf("unknown readiness check type %q (must be condition, rollout or script)", check.Type))
+		}
+
+		if check.Timeout != "" {
+			duration, err := time.ParseDuration(check.Timeout)
+			switch {
+			case err != nil:
+				invalid(".timeout", fmt.Sprintf("invalid timeout format: %v (use duration string like '5m')", err))
+			case duration <= 0:
+				invalid(".timeout", "timeout must be positive")
+			case duration > MaxReadinessTimeout:
+				invalid(".timeout", fmt.Sprintf("timeout exceeds maximum of %v", MaxReadinessTimeout))
+			}
+		}
+	}
+
 	return errors
 }
 
@@ -351,6 +656,120 @@ func validateURL(urlStr string) error {
 	return nil
 }
 
+// isOCIChartRepo reports whether a Helm chart repository is an OCI registry path
+func isOCIChartRepo(repo string) bool {
+	return strings.HasPrefix(repo, "oci://")
+}
+
+// validateChartRepo checks a Helm chart repository, which is either an HTTP
+// chart repository URL or an oci:// registry path
+func validateChartRepo(repo string) error {
+	if !isOCIChartRepo(repo) {
+		return validateURL(repo)
+	}
+
+	parsedURL, err := url.Parse(repo)
+	if err != nil {
+		return err
+	}
+	if parsedURL.Host == "" {
+		return fmt.Errorf("OCI repository must have a registry host")
+	}
+	if parsedURL.RawQuery != "" || parsedURL.Fragment != "" {
+		return fmt.Errorf("OCI repository cannot have a query or fragment")
+	}
+	return nil
+}
+
+func validateGitSource(git *types.GitSourceConfig, prefix string) []error {
+	var errors []error
+
+	if git.Repo == "" {
+		errors = append(errors, &PostConfigValidationError{
+			Field:   prefix + ".repo",
+			Message: "git repo is required",
+		})
+	} else if err := validateGitURL(git.Repo); err != nil {
+		errors = append(errors, &PostConfigValidationError{
+			Field:   prefix + ".repo",
+			Message: fmt.Sprintf("invalid repo URL: %v", err),
+		})
+	}
+
+	if strings.HasPrefix(git.Ref, "-") || strings.ContainsAny(git.Ref, " \t\n") {
+		errors = append(errors, &PostConfigValidationError{
+			Field:   prefix + ".ref",
+			Message: fmt.Sprintf("%q is not a valid branch, tag or commit", git.Ref),
+		})
+	}
+
+	if git.Path != "" {
+		if err := validateRelativePath(git.Path); err != nil {
+			errors = append(errors, &PostConfigValidationError{
+				Field:   prefix + ".path",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if git.CredentialsSecret != "" {
+		team, name, _ := strings.Cut(git.CredentialsSecret, "/")
+		if team == "" || name == "" || !isValidSecretName(git.CredentialsSecret) {
+			errors = append(errors, &PostConfigValidationError{
+				Field:   prefix + ".credentialsSecret",
+				Message: "secret name must have the form <team>/<name>, may only contain letters, digits and /_+=.@- and cannot contain '..'",
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateGitURL checks that a repository URL uses HTTPS or SSH. Credentials
+// belong in the secrets backend, not in the URL.
+func validateGitURL(repo string) error {
+	if strings.HasPrefix(repo, "-") {
+		return fmt.Errorf("URL cannot start with '-'")
+	}
+	if scpLikeGitURL.MatchString(repo) {
+		return nil
+	}
+
+	parsedURL, err := url.Parse(repo)
+	if err != nil {
+		return err
+	}
+	if parsedURL.Scheme != "https" && parsedURL.Scheme != "ssh" {
+		return fmt.Errorf("URL must use https or ssh scheme")
+	}
+	if parsedURL.Host == "" {
+		return fmt.Errorf("URL must have a host")
+	}
+	if _, hasPassword := parsedURL.User.Password(); hasPassword {
+		return fmt.Errorf("URL cannot contain a password; use credentialsSecret")
+	}
+	return nil
+}
+
+// validateRelativePath checks that a path stays inside the directory it is
+// resolved against
+func validateRelativePath(path string) error {
+	if filepath.IsAbs(path) {
+		return fmt.Errorf("path must be relative to the repository root")
+	}
+	clean := filepath.Clean(path)
+	if clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path cannot leave the repository")
+	}
+	return nil
+}
+
+// isValidSecretName reports whether name can be used to look up a secret in
+// the secrets backend
+func isValidSecretName(name string) bool {
+	return secretNamePattern.MatchString(name) && !strings.Contains(name, "..")
+}
+
 func isValidEnvVarName(name string) bool {
 	// Environment variable names must match: [A-Za-z_][A-Za-z0-9_]*
 	if len(name) == 0 {
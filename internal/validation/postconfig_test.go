@@ -186,6 +186,21 @@ func TestValidateManifest(t *testing.T) {
 		{"neither content nor url", types.CustomManifestConfig{Name: "m"}, "manifests[0]"},
 		{"both content and url", types.CustomManifestConfig{Name: "m", Content: "x", URL: "https://e.io/m.yaml"}, "manifests[0]"},
 		{"bad url", types.CustomManifestConfig{Name: "m", URL: "ftp://e.io/m.yaml"}, "manifests[0].url"},
+		{"valid git", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{
+			Repo: "https://github.com/org/config.git", Ref: "v1.2", Path: "overlays/dev", CredentialsSecret: "platform/git-token",
+		}, Kustomize: true}, ""},
+		{"valid scp-like git", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{Repo: "git@github.com:org/config.git"}}, ""},
+		{"both url and git", types.CustomManifestConfig{Name: "m", URL: "https://e.io/m.yaml", Git: &types.GitSourceConfig{Repo: "https://e.io/r.git"}}, "manifests[0]"},
+		{"missing git repo", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{Path: "base"}}, "manifests[0].git.repo"},
+		{"http git repo", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{Repo: "http://e.io/r.git"}}, "manifests[0].git.repo"},
+		{"local git repo", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{Repo: "file:///srv/repo"}}, "manifests[0].git.repo"},
+		{"password in git repo", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{Repo: "https://u:p@e.io/r.git"}}, "manifests[0].git.repo"},
+		{"option as git ref", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{Repo: "https://e.io/r.git", Ref: "--upload-pack=x"}}, "manifests[0].git.ref"},
+		{"git path leaves repo", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{Repo: "https://e.io/r.git", Path: "base/../../etc"}}, "manifests[0].git.path"},
+		{"absolute git path", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{Repo: "https://e.io/r.git", Path: "/etc"}}, "manifests[0].git.path"},
+		{"bad credentials secret", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{Repo: "https://e.io/r.git", CredentialsSecret: "a/../b"}}, "manifests[0].git.credentialsSecret"},
+		{"credentials secret without team", types.CustomManifestConfig{Name: "m", Git: &types.GitSourceConfig{Repo: "https://e.io/r.git", CredentialsSecret: "git-token"}}, "manifests[0].git.credentialsSecret"},
+		{"kustomize without git", types.CustomManifestConfig{Name: "m", URL: "https://e.io/m.yaml", Kustomize: true}, "manifests[0].kustomize"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -222,6 +237,12 @@ func TestValidateHelmChart(t *testing.T) {
 		{"missing repo", types.CustomHelmChartConfig{Name: "h", Chart: "c"}, "helmCharts[0].repo"},
 		{"bad repo url", types.CustomHelmChartConfig{Name: "h", Repo: "not a url", Chart: "c"}, "helmCharts[0].repo"},
 		{"missing chart", types.CustomHelmChartConfig{Name: "h", Repo: "https://charts.io"}, "helmCharts[0].chart"},
+		{"valid oci", types.CustomHelmChartConfig{Name: "h", Repo: "oci://quay.io/org/charts", Chart: "c", Version: "1.2.0",
+			Digest: "sha256:" + strings.Repeat("ab", 32)}, ""},
+		{"oci without host", types.CustomHelmChartConfig{Name: "h", Repo: "oci:///charts", Chart: "c"}, "helmCharts[0].repo"},
+		{"bad digest", types.CustomHelmChartConfig{Name: "h", Repo: "oci://quay.io/org/charts", Chart: "c", Digest: "sha256:abc"}, "helmCharts[0].digest"},
+		{"digest on http repo", types.CustomHelmChartConfig{Name: "h", Repo: "https://charts.io", Chart: "c",
+			Digest: "sha256:" + strings.Repeat("ab", 32)}, "helmCharts[0].digest"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -239,6 +260,140 @@ func TestValidateHelmChart(t *testing.T) {
 	}
 }
 
+func TestValidateCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		variables map[string]string
+		wantError bool
+	}{
+		{"empty", "", nil, false},
+		{"legacy equality", "clusterType == 'openshift'", nil, false},
+		{"legacy contains", "region contains 'us-'", nil, false},
+		{"boolean and version", "platform == 'aws' && version >= '4.18'", nil, false},
+		{"features and addons", "features.fipsMode || 'odf' in addons", nil, false},
+		{"custom variable", "tier == 'gold'", map[string]string{"tier": "gold"}, false},
+		{"syntax error", "platform == 'aws' &&", nil, true},
+		{"unknown variable", "tier == 'gold'", nil, true},
+		{"not a bool", "workerCount + 1", nil, true},
+		{"type mismatch", "workerCount == 'three'", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateCondition(tt.condition, tt.variables, "scripts[0]")
+			if tt.wantError && !hasFieldError(errs, "scripts[0].condition") {
+				t.Fatalf("expected condition error, got %v", errs)
+			}
+			if !tt.wantError && len(errs) != 0 {
+				t.Fatalf("expected no errors, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidateReadiness(t *testing.T) {
+	tests := []struct {
+		name      string
+		check     types.ReadinessCheck
+		wantField string // empty for a valid check
+	}{
+		{"condition type", types.ReadinessCheck{Type: "condition", Kind: "DataProtectionApplication", Name: "velero", ConditionType: "Reconciled"}, ""},
+		{"jsonpath", types.ReadinessCheck{Type: "condition", Kind: "HyperConverged", Name: "hco", JSONPath: "{.status.phase}", Value: "Deployed", Timeout: "30m"}, ""},
+		{"rollout", types.ReadinessCheck{Type: "rollout", Deployments: []string{"velero"}, Namespace: "openshift-adp"}, ""},
+		{"script", types.ReadinessCheck{Type: "script", Script: "oc get pods", Timeout: "2m"}, ""},
+		{"unknown type", types.ReadinessCheck{Type: "http"}, "operators[0].readiness[0].type"},
+		{"condition without kind", types.ReadinessCheck{Type: "condition", Name: "x", ConditionType: "Ready"}, "operators[0].readiness[0].kind"},
+		{"condition without name", types.ReadinessCheck{Type: "condition", Kind: "Pod", ConditionType: "Ready"}, "operators[0].readiness[0].name"},
+		{"condition without target", types.ReadinessCheck{Type: "condition", Kind: "Pod", Name: "x"}, "operators[0].readiness[0]"},
+		{"condition with both", types.ReadinessCheck{Type: "condition", Kind: "Pod", Name: "x", ConditionType: "Ready", JSONPath: "{.status.phase}", Value: "Running"}, "operators[0].readiness[0]"},
+		{"jsonpath without braces", types.ReadinessCheck{Type: "condition", Kind: "Pod", Name: "x", JSONPath: ".status.phase", Value: "Running"}, "operators[0].readiness[0].jsonPath"},
+		{"jsonpath without value", types.ReadinessCheck{Type: "condition", Kind: "Pod", Name: "x", JSONPath: "{.status.phase}"}, "operators[0].readiness[0].value"},
+		{"rollout without deployments", types.ReadinessCheck{Type: "rollout"}, "operators[0].readiness[0].deployments"},
+		{"rollout with empty name", types.ReadinessCheck{Type: "rollout", Deployments: []string{""}}, "operators[0].readiness[0].deployments"},
+		{"script without content", types.ReadinessCheck{Type: "script"}, "operators[0].readiness[0].script"},
+		{"invalid timeout", types.ReadinessCheck{Type: "script", Script: "true", Timeout: "soon"}, "operators[0].readiness[0].timeout"},
+		{"timeout too long", types.ReadinessCheck{Type: "script", Script: "true", Timeout: "2h"}, "operators[0].readiness[0].timeout"},
+		{"negative timeout", types.ReadinessCheck{Type: "script", Script: "true", Timeout: "-1m"}, "operators[0].readiness[0].timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &types.CustomPostConfig{Operators: []types.CustomOperatorConfig{
+				{Name: "op", Namespace: "ns", Channel: "stable", Readiness: []types.ReadinessCheck{tt.check}},
+			}}
+			errs := ValidateCustomPostConfig(cfg)
+			if tt.wantField == "" && len(errs) != 0 {
+				t.Fatalf("expected no errors, got %v", errs)
+			}
+			if tt.wantField != "" && !hasFieldError(errs, tt.wantField) {
+				t.Fatalf("expected error on %s, got %v", tt.wantField, errs)
+			}
+			if got := ValidateReadiness(cfg); len(got) != len(errs) {
+				t.Fatalf("ValidateReadiness returned %v, ValidateCustomPostConfig %v", got, errs)
+			}
+		})
+	}
+}
+
+func TestValidateSecretRefs(t *testing.T) {
+	tests := []struct {
+		name      string
+		script    types.CustomScriptConfig
+		wantField string // empty for a valid config
+	}{
+		{"env reference", types.CustomScriptConfig{Name: "s", Content: "echo", Env: map[string]string{"TOKEN": `{{ secret "platform/quay-token" }}`}}, ""},
+		{"variable reference", types.CustomScriptConfig{Name: "s", Content: "echo {{.Variables.key}}", Variables: map[string]string{"key": `{{ secret "platform/s3/access-key" }}`}}, ""},
+		{"content reference", types.CustomScriptConfig{Name: "s", Content: `curl -H "Authorization: Bearer {{ secret "platform/api" }}"`}, ""},
+		{"no team", types.CustomScriptConfig{Name: "s", Content: "echo", Env: map[string]string{"TOKEN": `{{ secret "quay-token" }}`}}, "scripts[0].env.TOKEN"},
+		{"invalid name", types.CustomScriptConfig{Name: "s", Content: "echo", Env: map[string]string{"TOKEN": `{{ secret "platform/../x" }}`}}, "scripts[0].env.TOKEN"},
+		{"dynamic name", types.CustomScriptConfig{Name: "s", Content: "echo", Env: map[string]string{"TOKEN": `{{ secret .Variables.name }}`}}, "scripts[0].env.TOKEN"},
+		{"bad template", types.CustomScriptConfig{Name: "s", Content: "echo", Variables: map[string]string{"key": `{{ secret "platform/x"`}}, "scripts[0].variables.key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateCustomPostConfig(&types.CustomPostConfig{Scripts: []types.CustomScriptConfig{tt.script}})
+			if tt.wantField == "" && len(errs) != 0 {
+				t.Fatalf("expected no errors, got %v", errs)
+			}
+			if tt.wantField != "" && !hasFieldError(errs, tt.wantField) {
+				t.Fatalf("expected error on %s, got %v", tt.wantField, errs)
+			}
+		})
+	}
+}
+
+func TestValidateSecretRefTeams(t *testing.T) {
+	cfg := &types.CustomPostConfig{
+		Manifests: []types.CustomManifestConfig{
+			{Name: "pull", Content: `token: {{ secret "platform/quay-token" }}`},
+		},
+		HelmCharts: []types.CustomHelmChartConfig{
+			{Name: "app", Repo: "https://charts.example.com", Chart: "app", Values: map[string]interface{}{
+				"s3.secretKey": `{{ secret "storage/s3-key" }}`,
+				"replicas":     2,
+			}},
+		},
+	}
+
+	if errs := ValidateSecretRefTeams(cfg, "platform"); len(errs) != 1 || !hasFieldError(errs, "helmCharts[0].values.s3.secretKey") {
+		t.Fatalf("expected one error on the other team's secret, got %v", errs)
+	}
+	if errs := ValidateSecretRefTeams(cfg, "storage"); len(errs) != 1 || !hasFieldError(errs, "manifests[0].content") {
+		t.Fatalf("expected one error on the other team's secret, got %v", errs)
+	}
+	gitCfg := &types.CustomPostConfig{
+		Manifests: []types.CustomManifestConfig{
+			{Name: "own", Git: &types.GitSourceConfig{Repo: "https://e.io/r.git", CredentialsSecret: "platform/git-token"}},
+			{Name: "other", Git: &types.GitSourceConfig{Repo: "https://e.io/r.git", CredentialsSecret: "storage/git-token"}},
+		},
+	}
+	if errs := ValidateSecretRefTeams(gitCfg, "platform"); len(errs) != 1 || !hasFieldError(errs, "manifests[1].git.credentialsSecret") {
+		t.Fatalf("expected one error on the other team's credentials, got %v", errs)
+	}
+	if errs := ValidateSecretRefTeams(nil, "platform"); len(errs) != 0 {
+		t.Fatalf("expected no errors for nil config, got %v", errs)
+	}
+}
+
 func TestValidateURL(t *testing.T) {
 	tests := []struct {
 		url     string
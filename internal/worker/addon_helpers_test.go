@@ -250,6 +250,37 @@ func TestAddonConfigMergeWithDependencies(t *testing.T) {
 		assert.Equal(t, []string{"base-script"}, merged.Operators[0].DependsOn)
 		assert.Equal(t, []string{"base-operator"}, merged.Operators[1].DependsOn)
 	})
+
+	t.Run("orders tasks after tasks of required addons", func(t *testing.T) {
+		addons := []types.PostConfigAddon{
+			{
+				AddonID: "cert-manager",
+				Config: types.CustomPostConfig{
+					Operators: []types.CustomOperatorConfig{{Name: "cert-manager-operator"}},
+					Scripts:   []types.CustomScriptConfig{{Name: "issuer", DependsOn: []string{"cert-manager-operator"}}},
+				},
+			},
+			{
+				AddonID:  "oadp",
+				Metadata: &types.AddonMetadata{Requires: []types.AddonRequirement{{ID: "cert-manager"}}},
+				Config: types.CustomPostConfig{
+					Operators: []types.CustomOperatorConfig{{Name: "oadp-operator"}},
+					Manifests: []types.CustomManifestConfig{{Name: "dpa", DependsOn: []string{"oadp-operator"}}},
+				},
+			},
+		}
+
+		handler := &PostConfigureHandler{}
+		merged, err := handler.mergeAddonConfigs(addons)
+		require.NoError(t, err)
+
+		assert.Empty(t, merged.Operators[0].DependsOn)
+		assert.Equal(t, []string{"cert-manager-operator", "issuer"}, merged.Operators[1].DependsOn)
+		assert.Equal(t, []string{"oadp-operator", "cert-manager-operator", "issuer"}, merged.Manifests[0].DependsOn)
+
+		// The source addon config is not modified
+		assert.Equal(t, []string{"oadp-operator"}, addons[1].Config.Manifests[0].DependsOn)
+	})
 }
 
 func TestAddonSelectionFormat(t *testing.T) {
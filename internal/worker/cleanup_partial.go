@@ -0,0 +1,205 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// cleanupOpenShiftDeployment cleans up partial OpenShift deployment
+func (h *DestroyHandler) cleanupOpenShiftDeployment(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	// Self-managed OpenShift is the only cluster type that owns DNS records
+	// under its base domain
+	if cluster.BaseDomain != nil && *cluster.BaseDomain != "" {
+		log.Printf("Cleaning up DNS records for cluster %s.%s", cluster.Name, *cluster.BaseDomain)
+		dnsCleaner := NewDNSCleaner(cluster.Region)
+		if err := dnsCleaner.CleanupClusterDNS(ctx, cluster.Name, *cluster.BaseDomain); err != nil {
+			log.Printf("Warning: DNS cleanup failed: %v", err)
+		} else {
+			log.Printf("Successfully cleaned up DNS records")
+		}
+	}
+
+	// Check if work directory exists
+	if _, err := os.Stat(workDir); os.IsNotExist(err) {
+		log.Printf("Work directory %s does not exist, skipping openshift-install destroy", workDir)
+		return
+	}
+
+	// Use the destroy-tolerant installer: cleanup runs `openshift-install destroy`
+	// against metadata.json, so it must not hard-fail on a version outside the
+	// create-time allowlist — otherwise partial infrastructure from a failed
+	// create of such a version leaks with nothing to clean it up.
+	inst := installer.NewInstallerForDestroy(cluster.Version)
+
+	// Run openshift-install destroy to clean up partial infrastructure
+	output, err := inst.DestroyCluster(ctx, workDir)
+	if err != nil {
+		log.Printf("Warning: openshift-install destroy failed for job %s: %v\nOutput: %s", job.ID, err, output)
+	} else {
+		log.Printf("Successfully cleaned up OpenShift deployment for job %s", job.ID)
+	}
+}
+
+// cleanupEKSDeployment cleans up partial EKS deployment
+func (h *DestroyHandler) cleanupEKSDeployment(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	log.Printf("Cleaning up partial EKS deployment for cluster %s in region %s", cluster.Name, cluster.Region)
+
+	eksInstaller := installer.NewEKSInstaller()
+	output, err := eksInstaller.DestroyCluster(ctx, cluster.Name, cluster.Region)
+	if err != nil {
+		log.Printf("Warning: eksctl delete failed for job %s: %v\nOutput: %s", job.ID, err, output)
+	} else {
+		log.Printf("Successfully cleaned up EKS deployment for job %s", job.ID)
+	}
+}
+
+// cleanupIKSDeployment cleans up partial IKS deployment
+func (h *DestroyHandler) cleanupIKSDeployment(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	log.Printf("Cleaning up partial IKS deployment for cluster %s", cluster.Name)
+
+	iksInstaller := installer.NewIKSInstaller()
+
+	// Login to IBM Cloud
+	apiKey := os.Getenv("IBMCLOUD_API_KEY")
+	if apiKey == "" {
+		log.Printf("Warning: IBMCLOUD_API_KEY not set, cannot cleanup IKS cluster")
+		return
+	}
+
+	// Use empty resource group - Login will query for available resource groups
+	if err := iksInstaller.Login(ctx, apiKey, cluster.Region, ""); err != nil {
+		log.Printf("Warning: IBM Cloud login failed: %v", err)
+		return
+	}
+
+	output, err := iksInstaller.DestroyCluster(ctx, cluster.Name)
+	if err != nil {
+		log.Printf("Warning: IKS cluster destroy failed for job %s: %v\nOutput: %s", job.ID, err, output)
+	} else {
+		log.Printf("Successfully cleaned up IKS deployment for job %s", job.ID)
+	}
+}
+
+// cleanupARODeployment cleans up partial ARO deployment
+func (h *DestroyHandler) cleanupARODeployment(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	log.Printf("Cleaning up partial ARO deployment for cluster %s in region %s", cluster.Name, cluster.Region)
+
+	aroInstaller := installer.NewAROInstaller()
+
+	// Load metadata to get resource group name
+	metadata, err := aroInstaller.LoadMetadata(workDir)
+	if err != nil {
+		// If metadata not available, construct resource group name from cluster name
+		log.Printf("Warning: failed to load metadata, constructing resource group name")
+		metadata = map[string]string{
+			"resource_group": fmt.Sprintf("ocpctl-%s-rg", cluster.Name),
+		}
+	}
+
+	resourceGroup := metadata["resource_group"]
+
+	// Try to delete ARO cluster
+	log.Printf("Attempting to delete ARO cluster from resource group: %s", resourceGroup)
+	output, err := aroInstaller.DestroyCluster(ctx, resourceGroup, cluster.Name)
+	if err != nil {
+		// Check if resource not found - this means cluster doesn't exist
+		if isResourceNotFoundError(output) {
+			log.Printf("ARO cluster not found, already cleaned up")
+		} else {
+			log.Printf("Warning: ARO cluster delete failed for job %s: %v\nOutput: %s", job.ID, err, output)
+		}
+	} else {
+		log.Printf("Successfully deleted ARO cluster for job %s", job.ID)
+	}
+
+	// Try to delete resource group
+	log.Printf("Attempting to delete resource group: %s", resourceGroup)
+	if err := aroInstaller.DeleteResourceGroup(ctx, resourceGroup); err != nil {
+		if isResourceNotFoundError(err.Error()) {
+			log.Printf("Resource group not found, already cleaned up")
+		} else {
+			log.Printf("Warning: Resource group delete failed for job %s: %v", job.ID, err)
+		}
+	} else {
+		log.Printf("Successfully deleted resource group for job %s", job.ID)
+	}
+}
+
+// cleanupAKSDeployment cleans up partial AKS deployment
+func (h *DestroyHandler) cleanupAKSDeployment(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	log.Printf("Cleaning up partial AKS deployment for cluster %s in region %s", cluster.Name, cluster.Region)
+
+	aksInstaller := installer.NewAKSInstaller()
+	aroInstaller := installer.NewAROInstaller()
+
+	// Load metadata to get resource group name
+	metadata, err := aroInstaller.LoadMetadata(workDir)
+	if err != nil {
+		// If metadata not available, construct resource group name from cluster name
+		log.Printf("Warning: failed to load metadata, constructing resource group name")
+		metadata = map[string]string{
+			"resource_group": fmt.Sprintf("ocpctl-%s-rg", cluster.Name),
+		}
+	}
+
+	resourceGroup := metadata["resource_group"]
+
+	// Try to delete AKS cluster
+	log.Printf("Attempting to delete AKS cluster from resource group: %s", resourceGroup)
+	output, err := aksInstaller.DestroyCluster(ctx, resourceGroup, cluster.Name)
+	if err != nil {
+		// Check if resource not found - this means cluster doesn't exist
+		if isResourceNotFoundError(output) {
+			log.Printf("AKS cluster not found, already cleaned up")
+		} else {
+			log.Printf("Warning: AKS cluster delete failed for job %s: %v\nOutput: %s", job.ID, err, output)
+		}
+	} else {
+		log.Printf("Successfully deleted AKS cluster for job %s", job.ID)
+	}
+
+	// Try to delete resource group
+	log.Printf("Attempting to delete resource group: %s", resourceGroup)
+	if err := aroInstaller.DeleteResourceGroup(ctx, resourceGroup); err != nil {
+		if isResourceNotFoundError(err.Error()) {
+			log.Printf("Resource group not found, already cleaned up")
+		} else {
+			log.Printf("Warning: Resource group delete failed for job %s: %v", job.ID, err)
+		}
+	} else {
+		log.Printf("Successfully deleted resource group for job %s", job.ID)
+	}
+}
+
+// cleanupHCPDeployment deletes a partially created HostedCluster and its
+// NodePools from the management cluster
+func (h *DestroyHandler) cleanupHCPDeployment(ctx context.Context, job *types.Job, cluster *types.Cluster) {
+	log.Printf("Cleaning up partial HCP deployment for cluster %s", cluster.Name)
+
+	hcpInstaller, err := hcpInstallerFor(h.registry, cluster)
+	if err != nil {
+		log.Printf("Warning: cannot clean up HCP cluster for job %s: %v", job.ID, err)
+		return
+	}
+	if err := hcpInstaller.DestroyCluster(ctx, cluster.Name); err != nil {
+		log.Printf("Warning: HCP cluster delete failed for job %s: %v", job.ID, err)
+		return
+	}
+	log.Printf("Successfully deleted HCP cluster for job %s", job.ID)
+}
+
+// cleanupKindDeployment deletes a partially created kind cluster
+func (h *DestroyHandler) cleanupKindDeployment(ctx context.Context, job *types.Job, cluster *types.Cluster) {
+	log.Printf("Cleaning up partial kind deployment for cluster %s", cluster.Name)
+
+	if output, err := installer.NewKindInstaller().DeleteCluster(ctx, cluster.Name); err != nil {
+		log.Printf("Warning: kind cluster delete failed for job %s: %v\nOutput: %s", job.ID, err, output)
+		return
+	}
+	log.Printf("Successfully deleted kind cluster for job %s", job.ID)
+}
@@ -0,0 +1,130 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// userCABundleConfigMap is the openshift-config ConfigMap the cluster proxy
+// references for additional trusted CAs
+const userCABundleConfigMap = "user-ca-bundle"
+
+// applyClusterProxy configures the cluster-wide proxy and additional trust
+// bundle on a running cluster, for installers that cannot set them at install
+// time (ARO). The trust bundle goes into the user-ca-bundle ConfigMap and is
+// referenced from proxy/cluster, which distributes it to every node.
+func applyClusterProxy(ctx context.Context, kubeconfigPath string, rn *types.RestrictedNetwork) error {
+	spec := map[string]interface{}{}
+
+	if rn.AdditionalTrustBundle != "" {
+		configMap, err := json.Marshal(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      userCABundleConfigMap,
+				"namespace": "openshift-config",
+			},
+			"data": map[string]string{"ca-bundle.crt": rn.AdditionalTrustBundle},
+		})
+		if err != nil {
+			return err
+		}
+		cmd := exec.CommandContext(ctx, "oc", "--kubeconfig", kubeconfigPath, "apply", "-f", "-")
+		cmd.Stdin = bytes.NewReader(configMap)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("apply %s ConfigMap: %w\nOutput: %s", userCABundleConfigMap, err, string(output))
+		}
+		spec["trustedCA"] = map[string]string{"name": userCABundleConfigMap}
+	}
+
+	if proxy := rn.Proxy; !proxy.IsEmpty() {
+		spec["httpProxy"] = proxy.HTTPProxy
+		spec["httpsProxy"] = proxy.HTTPSProxy
+		spec["noProxy"] = proxy.NoProxy
+	}
+
+	if len(spec) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"spec": spec})
+	if err != nil {
+		return err
+	}
+
+	// The proxy URLs may carry credentials, so the patch is passed in a file
+	// rather than on the command line
+	patchFile, err := os.CreateTemp("", "ocpctl-proxy-patch-*.json")
+	if err != nil {
+		return fmt.Errorf("create proxy patch file: %w", err)
+	}
+	defer os.Remove(patchFile.Name())
+	_, err = patchFile.Write(patch)
+	if closeErr := patchFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write proxy patch file: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "oc", "--kubeconfig", kubeconfigPath,
+		"patch", "proxy/cluster", "--type=merge", "--patch-file", patchFile.Name())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("patch proxy/cluster: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// proxyCredentials is the value of a proxy credentialsSecret
+type proxyCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// withProxyCredentials returns rn with the username and password from its
+// proxy's credentialsSecret added to the proxy URLs. The secret must belong to
+// the cluster's team, and the credentials are masked in the job's logs. rn is
+// returned unchanged when it has no credentials secret.
+func withProxyCredentials(ctx context.Context, config *Config, cluster *types.Cluster, rn *types.RestrictedNetwork) (*types.RestrictedNetwork, error) {
+	if rn == nil || rn.Proxy == nil || rn.Proxy.CredentialsSecret == "" {
+		return rn, nil
+	}
+
+	value, err := readTeamSecret(ctx, config, cluster, rn.Proxy.CredentialsSecret)
+	if err != nil {
+		return nil, fmt.Errorf("proxy credentials: %w", err)
+	}
+	var creds proxyCredentials
+	if err := json.Unmarshal([]byte(strings.TrimSpace(value)), &creds); err != nil || creds.Username == "" {
+		return nil, fmt.Errorf("proxy credentials secret %s must be JSON with username and password", rn.Proxy.CredentialsSecret)
+	}
+	userinfo := url.UserPassword(creds.Username, creds.Password)
+
+	proxy := *rn.Proxy
+	mask := secretMaskFromContext(ctx)
+	for _, target := range []*string{&proxy.HTTPProxy, &proxy.HTTPSProxy} {
+		if *target == "" {
+			continue
+		}
+		u, err := url.Parse(*target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", *target, err)
+		}
+		u.User = userinfo
+		*target = u.String()
+		mask.add(*target)
+	}
+	mask.add(creds.Password)
+	mask.add(userinfo.String())
+
+	out := *rn
+	out.Proxy = &proxy
+	return &out, nil
+}
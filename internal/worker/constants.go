@@ -21,6 +21,8 @@ const (
 	DNSPropagationCheckInterval = 10 * time.Second // Interval between DNS propagation checks
 	PostConfigWaitTimeout       = 10 * time.Minute // Timeout for post-configuration operations
 	PostConfigPollInterval      = 10 * time.Second // Interval between post-config status polls
+	ReadinessProbeTimeout       = 1 * time.Minute  // Timeout for a single run of a readiness script probe
+	HCPReadyTimeout             = 45 * time.Minute // Timeout for a HostedCluster and its NodePools to become ready
 
 	// Sleep/delay constants
 	LogBatchFlushDelay     = 500 * time.Millisecond // Delay to allow final log batch to flush
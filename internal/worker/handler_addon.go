@@ -0,0 +1,560 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/internal/addon"
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// protectedNamespaces are never deleted when an add-on is uninstalled, even
+// when an add-on installs an operator into them.
+var protectedNamespaces = map[string]bool{
+	"default":               true,
+	"openshift":             true,
+	"openshift-config":      true,
+	"openshift-marketplace": true,
+	"openshift-operators":   true,
+}
+
+// isProtectedNamespace reports whether a namespace belongs to the platform and
+// must survive add-on uninstalls
+func isProtectedNamespace(namespace string) bool {
+	return protectedNamespaces[namespace] || strings.HasPrefix(namespace, "kube-")
+}
+
+// replaceAddonRef returns refs with the entry for addonID replaced by newRef,
+// appending newRef if the add-on is not present. An empty newRef removes the
+// entry instead.
+func replaceAddonRef(refs []string, addonID, newRef string) []string {
+	result := make([]string, 0, len(refs)+1)
+	replaced := false
+	for _, ref := range refs {
+		if id, _ := types.ParseAddonRef(ref); id == addonID {
+			if newRef != "" && !replaced {
+				result = append(result, newRef)
+			}
+			replaced = true
+			continue
+		}
+		result = append(result, ref)
+	}
+	if !replaced && newRef != "" {
+		result = append(result, newRef)
+	}
+	return result
+}
+
+// operatorNamespaces returns the set of namespaces the add-ons install operators into
+func operatorNamespaces(addons []types.PostConfigAddon) map[string]bool {
+	namespaces := make(map[string]bool)
+	for _, addon := range addons {
+		for _, op := range addon.Config.Operators {
+			namespaces[op.Namespace] = true
+		}
+	}
+	return namespaces
+}
+
+// clusterOperatorNamespaces returns the set of namespaces the profile's
+// post-deployment config and the cluster's custom post-config install
+// operators into
+func clusterOperatorNamespaces(prof *profile.Profile, cluster *types.Cluster) map[string]bool {
+	namespaces := make(map[string]bool)
+	if prof.PostDeployment != nil && prof.PostDeployment.Enabled {
+		for _, op := range prof.PostDeployment.Operators {
+			namespaces[op.Namespace] = true
+		}
+	}
+	if cluster.CustomPostConfig != nil {
+		for _, op := range cluster.CustomPostConfig.Operators {
+			namespaces[op.Namespace] = true
+		}
+	}
+	return namespaces
+}
+
+// HandleAddonJob installs, upgrades or uninstalls a single add-on on a running
+// cluster (ADDON_INSTALL, ADDON_UPGRADE and ADDON_UNINSTALL jobs). The add-on
+// and target version come from the job's addon_id and version metadata. On
+// success the cluster's selected add-ons are updated to match.
+func (h *PostConfigureHandler) HandleAddonJob(ctx context.Context, job *types.Job) error {
+	ctx = context.WithValue(ctx, jobIDContextKey, job.ID)
+
+	addonID, _ := job.Metadata["addon_id"].(string)
+	version, _ := job.Metadata["version"].(string)
+	if addonID == "" {
+		return fmt.Errorf("job metadata missing addon_id")
+	}
+
+	cluster, err := h.store.Clusters.GetByID(ctx, job.ClusterID)
+	if err != nil {
+		return fmt.Errorf("get cluster: %w", err)
+	}
+
+	if caps, ok := h.providers.Capabilities(cluster.ClusterType); !ok || !caps.Addons {
+		return fmt.Errorf("add-on jobs are not supported for cluster type %s", cluster.ClusterType)
+	}
+
+	if cluster.Status != types.ClusterStatusReady {
+		return fmt.Errorf("cluster %s must be READY for add-on changes (current: %s)", cluster.Name, cluster.Status)
+	}
+
+	target, err := h.resolveAddon(ctx, cluster, addonID, version)
+	if err != nil {
+		return fmt.Errorf("resolve addon %s: %w", types.AddonRef(addonID, version), err)
+	}
+
+	// Re-check requirements and conflicts against what is installed now; the
+	// cluster may have changed since the job was queued.
+	installed, err := h.resolveInstalledAddons(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	condCtx := h.templateContext(cluster, "", nil)
+
+	// Add-ons to install along with an installed or upgraded add-on
+	var toInstall []types.PostConfigAddon
+	if job.JobType == types.JobTypeAddonUninstall {
+		if dependent := addon.RequiredBy(target.AddonID, installed, condCtx); dependent != "" {
+			return fmt.Errorf("addon %s is required by installed addon %s", target.AddonID, dependent)
+		}
+	} else {
+		selection := make([]types.PostConfigAddon, 0, len(installed)+1)
+		for _, a := range installed {
+			if a.AddonID != target.AddonID {
+				selection = append(selection, a)
+			}
+		}
+		selection = append(selection, *target)
+
+		resolution, err := addon.Resolve(ctx, addon.NewStoreCatalog(h.store.PostConfigAddons, cluster.OwnerID), selection, condCtx)
+		if err != nil {
+			return fmt.Errorf("resolve addon dependencies: %w", err)
+		}
+		added := make(map[string]bool, len(resolution.Added))
+		for _, dep := range resolution.Added {
+			added[dep.AddonID] = true
+		}
+		if job.JobType == types.JobTypeAddonUpgrade && len(resolution.Added) > 0 {
+			return fmt.Errorf("addon %s version %s requires %s, which is not installed; install it first",
+				target.AddonID, target.Version, resolution.Added[0].AddonID)
+		}
+		for _, a := range resolution.Addons {
+			if added[a.AddonID] || a.AddonID == target.AddonID {
+				toInstall = append(toInstall, a)
+			}
+		}
+	}
+
+	if err := h.ensureArtifactsAvailable(ctx, cluster.ID); err != nil {
+		return fmt.Errorf("ensure artifacts available: %w", err)
+	}
+
+	workDir := filepath.Join(h.config.WorkDir, cluster.ID)
+	kubeconfigPath := filepath.Join(workDir, "auth", "kubeconfig")
+	if _, err := os.Stat(kubeconfigPath); os.IsNotExist(err) {
+		return fmt.Errorf("kubeconfig not found at %s", kubeconfigPath)
+	}
+
+	// Start log streaming for job output visibility
+	logWriter, closeLog := h.newJobLogWriter(ctx, job, fmt.Sprintf("addon-%s.log", target.AddonID))
+	defer closeLog()
+
+	infraID, _, err := h.getClusterInfraDetails(ctx, cluster, kubeconfigPath)
+	if err != nil {
+		log.Printf("Warning: failed to get infra details for templating: %v", err)
+		infraID = "" // Continue without infra ID
+	}
+
+	switch job.JobType {
+	case types.JobTypeAddonInstall:
+		return h.installAddon(ctx, cluster, kubeconfigPath, infraID, target, toInstall, logWriter)
+	case types.JobTypeAddonUpgrade:
+		return h.upgradeAddon(ctx, cluster, kubeconfigPath, infraID, target, logWriter)
+	case types.JobTypeAddonUninstall:
+		return h.uninstallAddon(ctx, cluster, kubeconfigPath, infraID, target, logWriter)
+	default:
+		return fmt.Errorf("unsupported add-on job type: %s", job.JobType)
+	}
+}
+
+// installAddon installs an add-on together with the add-ons it requires that
+// are not installed yet. toInstall is in dependency order and includes the
+// target add-on; their configs are merged and run through the post-config DAG.
+func (h *PostConfigureHandler) installAddon(ctx context.Context, cluster *types.Cluster, kubeconfigPath, infraID string,
+	target *types.PostConfigAddon, toInstall []types.PostConfigAddon, logWriter func(string, ...interface{})) error {
+	logWriter("Installing add-on %s (version=%s) on cluster %s", target.AddonID, target.Version, cluster.Name)
+	for _, a := range toInstall {
+		if a.AddonID != target.AddonID {
+			logWriter("Installing required add-on %s (version=%s)", a.AddonID, a.Version)
+		}
+	}
+
+	merged, err := h.mergeAddonConfigs(toInstall)
+	if err != nil {
+		return fmt.Errorf("merge addon configs: %w", err)
+	}
+
+	dag, err := postconfig.BuildExecutionDAG(merged)
+	if err != nil {
+		return fmt.Errorf("build addon execution DAG: %w", err)
+	}
+	logWriter("Addon execution order: %v", dag.ExecutionOrder)
+
+	if err := h.runPostConfigDAG(ctx, cluster, kubeconfigPath, infraID, dag, "addon", logWriter); err != nil {
+		return err
+	}
+
+	for _, a := range toInstall {
+		if err := h.setAddonRef(ctx, cluster, a.AddonID, types.AddonRef(a.AddonID, a.Version)); err != nil {
+			return err
+		}
+	}
+
+	logWriter("Successfully installed add-on %s on cluster %s", target.AddonID, cluster.Name)
+	return nil
+}
+
+// upgradeAddon moves each of the add-on's operator Subscriptions to the
+// channel and source of the target version and waits for the new CSV.
+// Operators the target version adds are installed from scratch. Scripts,
+// manifests and Helm charts are left as installed.
+func (h *PostConfigureHandler) upgradeAddon(ctx context.Context, cluster *types.Cluster, kubeconfigPath, infraID string,
+	addon *types.PostConfigAddon, logWriter func(string, ...interface{})) error {
+	logWriter("Upgrading add-on %s to version %s on cluster %s", addon.AddonID, addon.Version, cluster.Name)
+
+	for _, op := range addon.Config.Operators {
+		if !h.addonConditionMet(cluster, infraID, op.Condition, nil) {
+			logWriter("[CONDITIONAL] Skipping operator %s (condition not met: %s)", op.Name, op.Condition)
+			continue
+		}
+
+		exists, err := h.subscriptionExists(ctx, kubeconfigPath, op.Name, op.Namespace)
+		if err != nil {
+			return fmt.Errorf("check subscription %s: %w", op.Name, err)
+		}
+		if !exists {
+			logWriter("Operator %s is new in version %s, installing", op.Name, addon.Version)
+			if err := h.installCustomOperator(ctx, cluster, kubeconfigPath, op); err != nil {
+				return fmt.Errorf("install operator %s: %w", op.Name, err)
+			}
+			continue
+		}
+
+		logWriter("Updating subscription %s/%s to channel %s", op.Namespace, op.Name, op.Channel)
+		if err := h.upgradeSubscription(ctx, cluster, kubeconfigPath, op); err != nil {
+			return fmt.Errorf("upgrade operator %s: %w", op.Name, err)
+		}
+	}
+
+	if err := h.setAddonRef(ctx, cluster, addon.AddonID, types.AddonRef(addon.AddonID, addon.Version)); err != nil {
+		return err
+	}
+
+	logWriter("Successfully upgraded add-on %s to version %s", addon.AddonID, addon.Version)
+	return nil
+}
+
+// upgradeSubscription patches an operator Subscription's channel and source,
+// then waits for OLM to roll out the new CSV
+func (h *PostConfigureHandler) upgradeSubscription(ctx context.Context, cluster *types.Cluster, kubeconfigPath string, op types.CustomOperatorConfig) error {
+	configID, err := h.createConfigTask(ctx, cluster.ID, types.ConfigTypeOperator, op.Name)
+	if err != nil {
+		return fmt.Errorf("create config task: %w", err)
+	}
+	_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusInstalling, nil)
+
+	source := op.Source
+	if source == "" {
+		source = "redhat-operators"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]string{
+			"channel": op.Channel,
+			"source":  source,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal subscription patch: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "oc", "--kubeconfig", kubeconfigPath,
+		"patch", "subscription", op.Name, "-n", op.Namespace, "--type", "merge", "-p", string(patch))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		errMsg := fmt.Sprintf("oc patch subscription failed: %v\nOutput: %s", err, string(output))
+		_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errMsg)
+		return fmt.Errorf("%s", errMsg)
+	}
+
+	profileOp := profile.OperatorConfig{
+		Name:      op.Name,
+		Namespace: op.Namespace,
+		Source:    op.Source,
+		Channel:   op.Channel,
+	}
+	if err := h.waitForOperatorReady(ctx, kubeconfigPath, profileOp); err != nil {
+		errMsg := err.Error()
+		_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errMsg)
+		return fmt.Errorf("wait for operator: %w", err)
+	}
+	if err := h.runReadinessChecks(ctx, cluster, kubeconfigPath, op.Name, op.Namespace, op.Readiness); err != nil {
+		errMsg := err.Error()
+		_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errMsg)
+		return err
+	}
+
+	_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusCompleted, nil)
+	return nil
+}
+
+// uninstallAddon removes what an add-on installed, in reverse of install
+// order: Helm releases, manifests, operator custom resources, Subscriptions
+// and CSVs, and finally OperatorGroups and namespaces no other installed
+// add-on, profile operator or custom post-config operator uses. Scripts cannot be reverted and are only logged.
+func (h *PostConfigureHandler) uninstallAddon(ctx context.Context, cluster *types.Cluster, kubeconfigPath, infraID string,
+	addon *types.PostConfigAddon, logWriter func(string, ...interface{})) error {
+	logWriter("Uninstalling add-on %s (version=%s) from cluster %s", addon.AddonID, addon.Version, cluster.Name)
+
+	cfg := addon.Config
+
+	for _, chart := range cfg.HelmCharts {
+		if !h.addonConditionMet(cluster, infraID, chart.Condition, chart.Variables) {
+			continue
+		}
+		logWriter("Uninstalling Helm release %s", chart.Name)
+		cmd := exec.CommandContext(ctx, "helm", "uninstall", chart.Name)
+		cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+		if output, err := cmd.CombinedOutput(); err != nil && !strings.Contains(string(output), "not found") {
+			return fmt.Errorf("helm uninstall %s failed: %v\nOutput: %s", chart.Name, err, string(output))
+		}
+	}
+
+	for _, manifest := range cfg.Manifests {
+		if !h.addonConditionMet(cluster, infraID, manifest.Condition, manifest.Variables) {
+			continue
+		}
+		logWriter("Deleting manifest %s", manifest.Name)
+		if err := h.deleteAddonManifest(ctx, cluster, kubeconfigPath, infraID, manifest); err != nil {
+			return fmt.Errorf("delete manifest %s: %w", manifest.Name, err)
+		}
+	}
+
+	for _, script := range cfg.Scripts {
+		logWriter("Script %s cannot be reverted automatically; remove any resources it created manually", script.Name)
+	}
+
+	// Delete custom resources first so operators can run their finalizers
+	var operators []types.CustomOperatorConfig
+	for _, op := range cfg.Operators {
+		if !h.addonConditionMet(cluster, infraID, op.Condition, nil) {
+			continue
+		}
+		operators = append(operators, op)
+		if cr := op.CustomResource; cr != nil {
+			namespace := cr.Namespace
+			if namespace == "" {
+				namespace = "default"
+			}
+			logWriter("Deleting custom resource %s/%s", cr.Kind, cr.Name)
+			yamlContent := fmt.Sprintf(`apiVersion: %s
+kind: %s
+metadata:
+  name: %s
+  namespace: %s
+`, cr.APIVersion, cr.Kind, cr.Name, namespace)
+			if err := h.deleteYAML(ctx, kubeconfigPath, yamlContent); err != nil {
+				return fmt.Errorf("delete custom resource %s: %w", cr.Name, err)
+			}
+		}
+	}
+
+	for _, op := range operators {
+		logWriter("Removing operator %s from namespace %s", op.Name, op.Namespace)
+		if err := h.deleteSubscriptionAndCSV(ctx, kubeconfigPath, op.Name, op.Namespace); err != nil {
+			return fmt.Errorf("remove operator %s: %w", op.Name, err)
+		}
+	}
+
+	// Namespaces shared with another installed add-on, the profile's
+	// post-deployment operators or the cluster's custom post-config stay in
+	// place
+	others, err := h.resolveInstalledAddons(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	remaining := make([]types.PostConfigAddon, 0, len(others))
+	for _, other := range others {
+		if other.AddonID != addon.AddonID {
+			remaining = append(remaining, other)
+		}
+	}
+	prof, err := h.registry.Get(cluster.Profile)
+	if err != nil {
+		return fmt.Errorf("get profile: %w", err)
+	}
+	inUse := operatorNamespaces(remaining)
+	for ns := range clusterOperatorNamespaces(prof, cluster) {
+		inUse[ns] = true
+	}
+
+	deleted := make(map[string]bool)
+	for _, op := range operators {
+		ns := op.Namespace
+		if deleted[ns] {
+			continue
+		}
+		if isProtectedNamespace(ns) || inUse[ns] {
+			logWriter("Keeping namespace %s (protected or used by other post-config)", ns)
+			continue
+		}
+		deleted[ns] = true
+
+		logWriter("Deleting OperatorGroup and namespace %s", ns)
+		if err := h.runOC(ctx, kubeconfigPath, "delete", "operatorgroup", ns+"-operator-group", "-n", ns, "--ignore-not-found"); err != nil {
+			return fmt.Errorf("delete operator group in %s: %w", ns, err)
+		}
+		if err := h.runOC(ctx, kubeconfigPath, "delete", "namespace", ns, "--ignore-not-found", "--timeout=10m"); err != nil {
+			return fmt.Errorf("delete namespace %s: %w", ns, err)
+		}
+	}
+
+	if err := h.setAddonRef(ctx, cluster, addon.AddonID, ""); err != nil {
+		return err
+	}
+
+	logWriter("Successfully uninstalled add-on %s from cluster %s", addon.AddonID, cluster.Name)
+	return nil
+}
+
+// deleteAddonManifest deletes the resources of a manifest, rendering inline
+// content with the same template context used to apply it. Git sources are
+// read again at the configured ref.
+func (h *PostConfigureHandler) deleteAddonManifest(ctx context.Context, cluster *types.Cluster, kubeconfigPath, infraID string, manifest types.CustomManifestConfig) error {
+	content, url, err := h.manifestSource(ctx, cluster, infraID, manifest)
+	if err != nil {
+		return err
+	}
+	if url != "" {
+		return h.runOC(ctx, kubeconfigPath, "delete", "-f", url, "--ignore-not-found")
+	}
+	return h.deleteYAML(ctx, kubeconfigPath, content)
+}
+
+// deleteSubscriptionAndCSV removes an operator's Subscription and the CSV it installed
+func (h *PostConfigureHandler) deleteSubscriptionAndCSV(ctx context.Context, kubeconfigPath, name, namespace string) error {
+	cmd := exec.CommandContext(ctx, "oc", "--kubeconfig", kubeconfigPath,
+		"get", "subscription", name, "-n", namespace, "--ignore-not-found", "-o", "jsonpath={.status.installedCSV}")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("get subscription failed: %v\nOutput: %s", err, string(output))
+	}
+	csv := strings.TrimSpace(string(output))
+
+	if err := h.runOC(ctx, kubeconfigPath, "delete", "subscription", name, "-n", namespace, "--ignore-not-found"); err != nil {
+		return err
+	}
+	if csv != "" {
+		if err := h.runOC(ctx, kubeconfigPath, "delete", "csv", csv, "-n", namespace, "--ignore-not-found"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscriptionExists reports whether an operator Subscription is present
+func (h *PostConfigureHandler) subscriptionExists(ctx context.Context, kubeconfigPath, name, namespace string) (bool, error) {
+	cmd := exec.CommandContext(ctx, "oc", "--kubeconfig", kubeconfigPath,
+		"get", "subscription", name, "-n", namespace, "--ignore-not-found", "-o", "name")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return false, fmt.Errorf("oc get subscription failed: %v\nOutput: %s", err, string(output))
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
+// deleteYAML deletes the resources described by YAML content, ignoring ones already gone
+func (h *PostConfigureHandler) deleteYAML(ctx context.Context, kubeconfigPath, yamlContent string) error {
+	cmd := exec.CommandContext(ctx, "oc", "--kubeconfig", kubeconfigPath, "delete", "-f", "-", "--ignore-not-found")
+	cmd.Stdin = strings.NewReader(yamlContent)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("oc delete failed: %v\nOutput: %s", err, string(output))
+	}
+
+	log.Printf("Deleted YAML successfully: %s", strings.TrimSpace(string(output)))
+	return nil
+}
+
+// runOC runs an oc command against the cluster
+func (h *PostConfigureHandler) runOC(ctx context.Context, kubeconfigPath string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "oc", append([]string{"--kubeconfig", kubeconfigPath}, args...)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("oc %s failed: %v\nOutput: %s", args[0], err, string(output))
+	}
+	log.Printf("oc %s: %s", strings.Join(args, " "), strings.TrimSpace(string(output)))
+	return nil
+}
+
+// addonConditionMet evaluates a task condition the same way install did. A
+// condition that fails to evaluate counts as met so cleanup is still attempted.
+func (h *PostConfigureHandler) addonConditionMet(cluster *types.Cluster, infraID, condition string, vars map[string]string) bool {
+	if condition == "" {
+		return true
+	}
+	ok, err := postconfig.EvaluateCondition(condition, h.templateContext(cluster, infraID, vars))
+	if err != nil {
+		log.Printf("Warning: failed to evaluate condition %q: %v", condition, err)
+		return true
+	}
+	return ok
+}
+
+// resolveAddon fetches an add-on by ID and version, falling back to its
+// default version. Draft add-ons are visible to the cluster owner.
+func (h *PostConfigureHandler) resolveAddon(ctx context.Context, cluster *types.Cluster, addonID, version string) (*types.PostConfigAddon, error) {
+	if version == "" {
+		return h.store.PostConfigAddons.GetByAddonID(ctx, addonID)
+	}
+	return h.store.PostConfigAddons.GetByAddonIDAndVersionForUser(ctx, addonID, version, cluster.OwnerID)
+}
+
+// resolveInstalledAddons resolves the cluster's selected add-ons, skipping
+// ones that no longer exist in the catalog
+func (h *PostConfigureHandler) resolveInstalledAddons(ctx context.Context, cluster *types.Cluster) ([]types.PostConfigAddon, error) {
+	installed := make([]types.PostConfigAddon, 0, len(cluster.SelectedAddonIDs))
+	for _, ref := range cluster.SelectedAddonIDs {
+		addonID, version := types.ParseAddonRef(ref)
+		addon, err := h.resolveAddon(ctx, cluster, addonID, version)
+		if err != nil {
+			log.Printf("Warning: failed to resolve installed addon %s on cluster %s: %v", ref, cluster.ID, err)
+			continue
+		}
+		installed = append(installed, *addon)
+	}
+	return installed, nil
+}
+
+// setAddonRef records an add-on change in the cluster's selected add-ons.
+// An empty ref removes the add-on.
+func (h *PostConfigureHandler) setAddonRef(ctx context.Context, cluster *types.Cluster, addonID, ref string) error {
+	refs := replaceAddonRef(cluster.SelectedAddonIDs, addonID, ref)
+	if err := h.store.Clusters.Update(ctx, cluster.ID, map[string]interface{}{
+		"selected_addon_ids": refs,
+	}); err != nil {
+		return fmt.Errorf("update selected addons: %w", err)
+	}
+	cluster.SelectedAddonIDs = refs
+	return nil
+}
@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestReplaceAddonRef(t *testing.T) {
+	refs := []string{"oadp:stable", "mtc", "cnv:candidate"}
+
+	t.Run("replaces existing entry in place", func(t *testing.T) {
+		got := replaceAddonRef(refs, "mtc", "mtc:release-v1.8")
+		assert.Equal(t, []string{"oadp:stable", "mtc:release-v1.8", "cnv:candidate"}, got)
+	})
+
+	t.Run("appends missing add-on", func(t *testing.T) {
+		got := replaceAddonRef(refs, "odf", "odf:stable-4.18")
+		assert.Equal(t, []string{"oadp:stable", "mtc", "cnv:candidate", "odf:stable-4.18"}, got)
+	})
+
+	t.Run("removes entry when new ref is empty", func(t *testing.T) {
+		got := replaceAddonRef(refs, "oadp", "")
+		assert.Equal(t, []string{"mtc", "cnv:candidate"}, got)
+	})
+
+	t.Run("does not match add-on ID prefixes", func(t *testing.T) {
+		got := replaceAddonRef([]string{"oadp-dev:stable"}, "oadp", "")
+		assert.Equal(t, []string{"oadp-dev:stable"}, got)
+	})
+
+	t.Run("does not modify input", func(t *testing.T) {
+		replaceAddonRef(refs, "oadp", "")
+		assert.Equal(t, []string{"oadp:stable", "mtc", "cnv:candidate"}, refs)
+	})
+}
+
+func TestIsProtectedNamespace(t *testing.T) {
+	for _, ns := range []string{"openshift-operators", "openshift-marketplace", "default", "kube-system", "kube-public", "openshift", "openshift-config"} {
+		assert.True(t, isProtectedNamespace(ns), ns)
+	}
+	for _, ns := range []string{"openshift-adp", "openshift-cnv", "openshift-migration", "kubevirt-hyperconverged"} {
+		assert.False(t, isProtectedNamespace(ns), ns)
+	}
+}
+
+func TestOperatorNamespaces(t *testing.T) {
+	addons := []types.PostConfigAddon{
+		{AddonID: "oadp", Config: types.CustomPostConfig{Operators: []types.CustomOperatorConfig{{Name: "redhat-oadp-operator", Namespace: "openshift-adp"}}}},
+		{AddonID: "cnv", Config: types.CustomPostConfig{Operators: []types.CustomOperatorConfig{
+			{Name: "kubevirt-hyperconverged", Namespace: "openshift-cnv"},
+			{Name: "mtv-operator", Namespace: "openshift-mtv"},
+		}}},
+		{AddonID: "script-only", Config: types.CustomPostConfig{Scripts: []types.CustomScriptConfig{{Name: "setup"}}}},
+	}
+
+	assert.Equal(t, map[string]bool{"openshift-adp": true, "openshift-cnv": true, "openshift-mtv": true}, operatorNamespaces(addons))
+}
+
+func TestClusterOperatorNamespaces(t *testing.T) {
+	prof := &profile.Profile{PostDeployment: &profile.PostDeploymentConfig{
+		Enabled:   true,
+		Operators: []profile.OperatorConfig{{Name: "redhat-oadp-operator", Namespace: "openshift-adp"}},
+	}}
+	cluster := &types.Cluster{CustomPostConfig: &types.CustomPostConfig{
+		Operators: []types.CustomOperatorConfig{{Name: "mtv-operator", Namespace: "openshift-mtv"}},
+	}}
+
+	assert.Equal(t, map[string]bool{"openshift-adp": true, "openshift-mtv": true}, clusterOperatorNamespaces(prof, cluster))
+
+	prof.PostDeployment.Enabled = false
+	assert.Equal(t, map[string]bool{"openshift-mtv": true}, clusterOperatorNamespaces(prof, cluster))
+	assert.Empty(t, clusterOperatorNamespaces(&profile.Profile{}, &types.Cluster{}))
+}
+
+func TestFindAddonConflict(t *testing.T) {
+	installed := []types.PostConfigAddon{
+		{AddonID: "oadp", Metadata: &types.AddonMetadata{ConflictsWith: []string{"velero"}}},
+		{AddonID: "mtc"},
+	}
+
+	t.Run("installed add-on declares conflict", func(t *testing.T) {
+		addon := &types.PostConfigAddon{AddonID: "velero"}
+		assert.Equal(t, "oadp", types.FindAddonConflict(addon, installed))
+	})
+
+	t.Run("new add-on declares conflict", func(t *testing.T) {
+		addon := &types.PostConfigAddon{AddonID: "mtc-legacy", Metadata: &types.AddonMetadata{ConflictsWith: []string{"mtc"}}}
+		assert.Equal(t, "mtc", types.FindAddonConflict(addon, installed))
+	})
+
+	t.Run("no conflict", func(t *testing.T) {
+		addon := &types.PostConfigAddon{AddonID: "cnv"}
+		assert.Empty(t, types.FindAddonConflict(addon, installed))
+	})
+
+	t.Run("ignores installed copy of the same add-on", func(t *testing.T) {
+		addon := &types.PostConfigAddon{AddonID: "oadp", Metadata: &types.AddonMetadata{ConflictsWith: []string{"oadp"}}}
+		assert.Empty(t, types.FindAddonConflict(addon, installed))
+	})
+}
+
+func TestParseAddonRef(t *testing.T) {
+	id, channel := types.ParseAddonRef("oadp:stable-1.4")
+	assert.Equal(t, "oadp", id)
+	assert.Equal(t, "stable-1.4", channel)
+
+	id, channel = types.ParseAddonRef("mtc")
+	assert.Equal(t, "mtc", id)
+	assert.Empty(t, channel)
+
+	assert.Equal(t, "oadp:stable", types.AddonRef("oadp", "stable"))
+	assert.Equal(t, "mtc", types.AddonRef("mtc", ""))
+}
@@ -19,9 +19,11 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/google/uuid"
+	"github.com/tsanders-rh/ocpctl/internal/clusterconfig"
 	"github.com/tsanders-rh/ocpctl/internal/installer"
 	"github.com/tsanders-rh/ocpctl/internal/k8s"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
 	"github.com/tsanders-rh/ocpctl/internal/store"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 	"gopkg.in/yaml.v3"
@@ -29,34 +31,19 @@ import (
 
 // CreateHandler handles cluster creation jobs
 type CreateHandler struct {
-	config   *Config
-	store    *store.Store
-	registry *profile.Registry
+	config    *Config
+	store     *store.Store
+	registry  *profile.Registry
+	providers *provider.Registry
 }
 
 // NewCreateHandler creates a new create handler
-func NewCreateHandler(config *Config, st *store.Store) *CreateHandler {
-	// Load profile registry
-	profilesDir := os.Getenv("PROFILES_DIR")
-	if profilesDir == "" {
-		profilesDir = "internal/profile/definitions"
-	}
-
-	loader := profile.NewLoader(profilesDir)
-	registry, err := profile.NewRegistry(loader)
-	if err != nil {
-		log.Fatalf("Failed to load profile registry: %v", err)
-	}
-
-	return &CreateHandler{
-		config:   config,
-		store:    st,
-		registry: registry,
-	}
+func NewCreateHandler(config *Config, st *store.Store, registry *profile.Registry) *CreateHandler {
+	return newClusterHandlers(config, st, registry).create
 }
 
 // Handle handles a cluster creation job by provisioning infrastructure via platform-specific installers.
-// Routes to the appropriate handler based on cluster type (OpenShift, EKS, or IKS).
+// Looks up the provider for the cluster type, runs its pre-flight checks and then its create.
 // Streams deployment logs to the database in real-time and stores artifacts (kubeconfig, metadata) upon completion.
 func (h *CreateHandler) Handle(ctx context.Context, job *types.Job) error {
 	// Get cluster details
@@ -68,38 +55,23 @@ func (h *CreateHandler) Handle(ctx context.Context, job *types.Job) error {
 	log.Printf("Creating cluster %s (platform=%s, cluster_type=%s, version=%s, profile=%s)",
 		cluster.Name, cluster.Platform, cluster.ClusterType, cluster.Version, cluster.Profile)
 
-	// Route to appropriate handler based on cluster type
-	switch cluster.ClusterType {
-	case types.ClusterTypeOpenShift:
-		return h.handleOpenShiftCreate(ctx, job, cluster)
-	case types.ClusterTypeROSA:
-		return h.handleROSACreate(ctx, job, cluster)
-	case types.ClusterTypeEKS:
-		return h.handleEKSCreate(ctx, job, cluster)
-	case types.ClusterTypeIKS:
-		return h.handleIKSCreate(ctx, job, cluster)
-	case types.ClusterTypeGKE:
-		return h.handleGKECreate(ctx, job, cluster)
-	case types.ClusterTypeARO:
-		return h.handleAROCreate(ctx, job, cluster)
-	case types.ClusterTypeAKS:
-		return h.handleAKSCreate(ctx, job, cluster)
-	default:
-		return fmt.Errorf("unsupported cluster type: %s", cluster.ClusterType)
+	p, err := h.providers.Get(cluster.ClusterType)
+	if err != nil {
+		return fmt.Errorf("unsupported cluster type: %w", err)
 	}
-}
-
-// handleOpenShiftCreate handles OpenShift cluster creation
-func (h *CreateHandler) handleOpenShiftCreate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
-	log.Printf("Starting OpenShift cluster creation for %s", cluster.Name)
 
-	// Update cluster status to CREATING
-	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusCreating); err != nil {
-		return fmt.Errorf("update cluster status: %w", err)
+	// Pre-flight checks fail the job before anything is provisioned
+	if err := p.Preflight(ctx, cluster); err != nil {
+		return err
 	}
 
-	// Platform-specific pre-flight checks
-	prof, err := h.registry.Get(cluster.Profile)
+	return p.Create(ctx, job, cluster)
+}
+
+// preflightOpenShift checks cloud capacity, quotas and mirror registries
+// before openshift-install provisions anything
+func (h *CreateHandler) preflightOpenShift(ctx context.Context, cluster *types.Cluster) error {
+	prof, err := h.clusterProfile(cluster)
 	if err != nil {
 		return fmt.Errorf("get profile for pre-flight check: %w", err)
 	}
@@ -112,7 +84,7 @@ func (h *CreateHandler) handleOpenShiftCreate(ctx context.Context, job *types.Jo
 			log.Printf("Warning: failed to create AWS pre-flight checker: %v", err)
 			// Don't fail cluster creation if pre-flight check setup fails
 		} else {
-			if err := checker.CheckInstanceTypeAvailability(ctx, prof); err != nil {
+			if err := checker.CheckInstanceTypeAvailability(ctx, prof, profile.EffectiveArchitecture(prof, cluster.Architecture)); err != nil {
 				// Pre-flight check failed - fail immediately before starting installation
 				// Use PreflightCheckError to prevent retries and provide clear error code
 				return types.NewPreflightCheckError("AWS capacity pre-flight check failed: %v", err)
@@ -122,6 +94,9 @@ func (h *CreateHandler) handleOpenShiftCreate(ctx context.Context, job *types.Jo
 			if err := checker.CheckQuotas(ctx, prof); err != nil {
 				return types.NewPreflightCheckError("AWS quota pre-flight check failed: %v", err)
 			}
+			if err := h.preflightSpot(ctx, cluster, prof, checker); err != nil {
+				return err
+			}
 		}
 	} else if cluster.Platform == types.PlatformGCP {
 		// GCP-specific pre-flight checks (machine type availability)
@@ -139,6 +114,32 @@ func (h *CreateHandler) handleOpenShiftCreate(ctx context.Context, job *types.Jo
 		}
 	}
 
+	// Restricted-network installs pull release images from the mirrors, so
+	// an unreachable mirror would only surface deep into bootstrap
+	if rn := profile.EffectiveRestrictedNetwork(prof, cluster.RestrictedNetwork); rn != nil && len(rn.ImageMirrors) > 0 {
+		log.Printf("Checking mirror registry reachability for cluster %s", cluster.Name)
+		if err := CheckMirrorRegistries(ctx, rn); err != nil {
+			return types.NewPreflightCheckError("mirror registry pre-flight check failed: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// handleOpenShiftCreate handles OpenShift cluster creation
+func (h *CreateHandler) handleOpenShiftCreate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	log.Printf("Starting OpenShift cluster creation for %s", cluster.Name)
+
+	// Update cluster status to CREATING
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusCreating); err != nil {
+		return fmt.Errorf("update cluster status: %w", err)
+	}
+
+	prof, err := h.clusterProfile(cluster)
+	if err != nil {
+		return fmt.Errorf("get profile: %w", err)
+	}
+
 	// Create work directory for this cluster with secure permissions
 	workDir, err := ensureSecureWorkDir(h.config.WorkDir, cluster.ID)
 	if err != nil {
@@ -166,28 +167,14 @@ func (h *CreateHandler) handleOpenShiftCreate(ctx context.Context, job *types.Jo
 	}
 
 	// Build create cluster request for renderer
-	// Convert BaseDomain pointer to string
-	baseDomain := ""
-	if cluster.BaseDomain != nil {
-		baseDomain = *cluster.BaseDomain
-	}
-
-	createReq := &types.CreateClusterRequest{
-		Name:            cluster.Name,
-		Platform:        string(cluster.Platform),
-		Version:         cluster.Version,
-		Profile:         cluster.Profile,
-		Region:          cluster.Region,
-		BaseDomain:      baseDomain,
-		Owner:           cluster.Owner,
-		Team:            cluster.Team,
-		CostCenter:      cluster.CostCenter,
-		TTLHours:        cluster.TTLHours,
-		SSHPublicKey:    cluster.SSHPublicKey,
-		ExtraTags:       cluster.RequestTags,
-		OffhoursOptIn:   cluster.OffhoursOptIn,
-		CredentialsMode: cluster.CredentialsMode,
+	createReq := clusterconfig.InstallConfigRequest(cluster)
+
+	// Proxy credentials are read from the secrets backend for the install only
+	rn, err := withProxyCredentials(ctx, h.config, cluster, profile.EffectiveRestrictedNetwork(prof, cluster.RestrictedNetwork))
+	if err != nil {
+		return err
 	}
+	createReq.RestrictedNetwork = rn
 
 	installConfig, err := renderer.RenderInstallConfig(createReq, pullSecret, cluster.EffectiveTags)
 	if err != nil {
@@ -214,9 +201,11 @@ func (h *CreateHandler) handleOpenShiftCreate(ctx context.Context, job *types.Jo
 		log.Printf("Warning: failed to start log streaming: %v", err)
 	}
 
-	// Create version-specific installer for this cluster
-	log.Printf("Creating installer for OpenShift version %s", cluster.Version)
-	inst, err := installer.NewInstallerForVersion(cluster.Version)
+	// Create version-specific installer for this cluster; arm64 and
+	// heterogeneous clusters need the multi-architecture payload's installer
+	releaseArch := profile.EffectiveArchitecture(prof, cluster.Architecture).ReleaseArchitecture()
+	log.Printf("Creating installer for OpenShift version %s (%s payload)", cluster.Version, releaseArch)
+	inst, err := installer.NewInstallerForArchitecture(cluster.Version, releaseArch)
 	if err != nil {
 		return fmt.Errorf("create installer for version %s: %w", cluster.Version, err)
 	}
@@ -227,6 +216,12 @@ func (h *CreateHandler) handleOpenShiftCreate(ctx context.Context, job *types.Jo
 		log.Printf("Set credentials mode: %s", *cluster.CredentialsMode)
 	}
 
+	// Spot workers are set on the rendered MachineSets, not in install-config
+	if w := prof.Compute.Workers; w != nil && w.Spot != nil && cluster.Platform == types.PlatformAWS {
+		inst.SetSpotWorkers(&installer.SpotMarketOptions{MaxPrice: w.Spot.MaxPrice})
+		log.Printf("Worker machines for cluster %s will run on spot capacity", cluster.Name)
+	}
+
 	// Platform-specific pre-installation steps
 	if cluster.Platform == types.PlatformIBMCloud {
 		// IBM Cloud requires CCO manual mode - run ccoctl before cluster creation
@@ -356,6 +351,11 @@ func (h *CreateHandler) handleOpenShiftCreate(ctx context.Context, job *types.Jo
 		}
 	}
 
+	// Add extra machine pools requested through compute overrides
+	if err := h.createAdditionalMachinePools(ctx, cluster, prof, workDir); err != nil {
+		return fmt.Errorf("create additional machine pools: %w", err)
+	}
+
 	// Update cluster status to READY
 	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusReady); err != nil {
 		return fmt.Errorf("update cluster status to ready: %w", err)
@@ -756,100 +756,22 @@ func (h *CreateHandler) handleEKSCreate(ctx context.Context, job *types.Job, clu
 	eksInstaller := installer.NewEKSInstaller()
 
 	// Get profile to extract configuration
-	prof, err := h.registry.Get(cluster.Profile)
+	prof, err := h.clusterProfile(cluster)
 	if err != nil {
 		return fmt.Errorf("get profile: %w", err)
 	}
 
-	// Build eksctl configuration
-	eksConfig := &installer.EKSClusterConfig{
-		APIVersion: "eksctl.io/v1alpha5",
-		Kind:       "ClusterConfig",
-		Metadata: installer.EKSMetadata{
-			Name:    cluster.Name,
-			Region:  cluster.Region,
-			Version: cluster.Version,
-			Tags:    cluster.EffectiveTags,
-		},
-		IAM: &installer.EKSIAM{
-			WithOIDC: true, // Enable OIDC provider for IRSA
-		},
-		NodeGroups: []installer.EKSNodeGroup{},
-	}
-
-	// Add node groups from profile (unmanaged)
-	if len(prof.Compute.NodeGroups) > 0 {
-		for _, ng := range prof.Compute.NodeGroups {
-			eksNodeGroup := installer.EKSNodeGroup{
-				Name:            ng.Name,
-				InstanceType:    ng.InstanceType,
-				DesiredCapacity: ng.DesiredCapacity,
-				MinSize:         ng.MinSize,
-				MaxSize:         ng.MaxSize,
-				VolumeSize:      ng.VolumeSize,
-				VolumeType:      ng.VolumeType,
-				Tags:            cluster.EffectiveTags,
-			}
-
-			// Add SSH configuration if public key provided
-			if cluster.SSHPublicKey != nil && *cluster.SSHPublicKey != "" {
-				sshKeyPath := filepath.Join(workDir, "ssh-key.pub")
-				if err := os.WriteFile(sshKeyPath, []byte(*cluster.SSHPublicKey), 0600); err != nil {
-					return fmt.Errorf("write SSH public key: %w", err)
-				}
-				eksNodeGroup.SSH = &installer.EKSNodeGroupSSH{
-					Allow:         true,
-					PublicKeyPath: sshKeyPath,
-				}
-			}
-
-			eksConfig.NodeGroups = append(eksConfig.NodeGroups, eksNodeGroup)
-		}
-	}
-
-	// Add managed node groups from profile (EKS-managed)
-	if len(prof.Compute.ManagedNodeGroups) > 0 {
-		for _, ng := range prof.Compute.ManagedNodeGroups {
-			eksManagedNodeGroup := installer.EKSManagedNodeGroup{
-				Name:            ng.Name,
-				InstanceType:    ng.InstanceType,
-				DesiredCapacity: ng.DesiredCapacity,
-				MinSize:         ng.MinSize,
-				MaxSize:         ng.MaxSize,
-				VolumeSize:      ng.VolumeSize,
-				VolumeType:      ng.VolumeType,
-				AMIFamily:       ng.AMIFamily,
-				Tags:            cluster.EffectiveTags,
-			}
-
-			// Add SSH configuration if public key provided
-			if cluster.SSHPublicKey != nil && *cluster.SSHPublicKey != "" {
-				sshKeyPath := filepath.Join(workDir, "ssh-key.pub")
-				if err := os.WriteFile(sshKeyPath, []byte(*cluster.SSHPublicKey), 0600); err != nil {
-					return fmt.Errorf("write SSH public key: %w", err)
-				}
-				eksManagedNodeGroup.SSH = &installer.EKSNodeGroupSSH{
-					Allow:         true,
-					PublicKeyPath: sshKeyPath,
-				}
-			}
-
-			eksConfig.ManagedNodeGroups = append(eksConfig.ManagedNodeGroups, eksManagedNodeGroup)
+	// Write the SSH public key for node group access if provided
+	sshKeyPath := ""
+	if cluster.SSHPublicKey != nil && *cluster.SSHPublicKey != "" {
+		sshKeyPath = filepath.Join(workDir, "ssh-key.pub")
+		if err := os.WriteFile(sshKeyPath, []byte(*cluster.SSHPublicKey), 0600); err != nil {
+			return fmt.Errorf("write SSH public key: %w", err)
 		}
 	}
 
-	// Set VPC configuration from profile
-	if prof.Networking.VpcCIDR != "" {
-		eksConfig.VPC = &installer.EKSVPC{
-			CIDR: prof.Networking.VpcCIDR,
-		}
-		// Set NAT gateway mode
-		if prof.Networking.NatGateway != "" {
-			eksConfig.VPC.NAT = &installer.EKSNAT{
-				Gateway: prof.Networking.NatGateway,
-			}
-		}
-	}
+	// Build eksctl configuration
+	eksConfig := clusterconfig.EKS(cluster, prof, sshKeyPath)
 
 	// Write eksctl configuration
 	if err := eksInstaller.WriteConfig(workDir, eksConfig); err != nil {
@@ -964,7 +886,7 @@ func (h *CreateHandler) handleIKSCreate(ctx context.Context, job *types.Job, clu
 	}
 
 	// Get profile to extract configuration
-	prof, err := h.registry.Get(cluster.Profile)
+	prof, err := h.clusterProfile(cluster)
 	if err != nil {
 		return fmt.Errorf("get profile: %w", err)
 	}
@@ -990,60 +912,31 @@ func (h *CreateHandler) handleIKSCreate(ctx context.Context, job *types.Job, clu
 	}
 
 	// Build IKS cluster create options
-	zone := prof.Zones.Default
-	if zone == "" {
-		return fmt.Errorf("no default zone configured in profile")
-	}
-
-	machineType := "bx2.4x16" // Default machine type
-	workerCount := 2          // Default worker count
-
-	publicVLAN := ""
-	privateVLAN := ""
-
-	if prof.Compute.Workers != nil {
-		if prof.Compute.Workers.MachineType != "" {
-			machineType = prof.Compute.Workers.MachineType
-		}
-		if prof.Compute.Workers.Count > 0 {
-			workerCount = prof.Compute.Workers.Count
-		}
-		publicVLAN = prof.Compute.Workers.PublicVLAN
-		privateVLAN = prof.Compute.Workers.PrivateVLAN
+	createOpts, err := clusterconfig.IKS(cluster, prof)
+	if err != nil {
+		return err
 	}
 
 	// Resolve VLANs dynamically if not specified or set to "auto"
-	if publicVLAN == "" || publicVLAN == "auto" || privateVLAN == "" || privateVLAN == "auto" {
-		log.Printf("Resolving classic VLANs for zone %s...", zone)
-		resolved, err := iksInstaller.ResolveClassicVLANs(ctx, zone)
+	if clusterconfig.NeedsVLANResolution(createOpts.PublicVLAN) || clusterconfig.NeedsVLANResolution(createOpts.PrivateVLAN) {
+		log.Printf("Resolving classic VLANs for zone %s...", createOpts.Zone)
+		resolved, err := iksInstaller.ResolveClassicVLANs(ctx, createOpts.Zone)
 		if err != nil {
-			return fmt.Errorf("resolve classic VLANs for zone %s: %w", zone, err)
+			return fmt.Errorf("resolve classic VLANs for zone %s: %w", createOpts.Zone, err)
 		}
 
-		if publicVLAN == "" || publicVLAN == "auto" {
-			publicVLAN = resolved.PublicVLAN
+		if clusterconfig.NeedsVLANResolution(createOpts.PublicVLAN) {
+			createOpts.PublicVLAN = resolved.PublicVLAN
 		}
-		if privateVLAN == "" || privateVLAN == "auto" {
-			privateVLAN = resolved.PrivateVLAN
+		if clusterconfig.NeedsVLANResolution(createOpts.PrivateVLAN) {
+			createOpts.PrivateVLAN = resolved.PrivateVLAN
 		}
 
-		log.Printf("Resolved VLANs: public=%s, private=%s", publicVLAN, privateVLAN)
-	}
-
-	createOpts := &installer.IKSClusterCreateOptions{
-		Name:                   cluster.Name,
-		Zone:                   zone,
-		MachineType:            machineType,
-		Workers:                workerCount,
-		KubeVersion:            cluster.Version,
-		PublicVLAN:             publicVLAN,
-		PrivateVLAN:            privateVLAN,
-		PublicServiceEndpoint:  prof.Features.PublicServiceEndpoint,
-		PrivateServiceEndpoint: prof.Features.PrivateServiceEndpoint,
+		log.Printf("Resolved VLANs: public=%s, private=%s", createOpts.PublicVLAN, createOpts.PrivateVLAN)
 	}
 
 	log.Printf("Creating IKS cluster: zone=%s, machine=%s, workers=%d, publicVLAN=%s, privateVLAN=%s",
-		zone, machineType, workerCount, publicVLAN, privateVLAN)
+		createOpts.Zone, createOpts.MachineType, createOpts.Workers, createOpts.PublicVLAN, createOpts.PrivateVLAN)
 
 	// Start log streaming before running ibmcloud CLI
 	// This will tail ibmcloud-ks.log and stream to database in real-time
@@ -1406,7 +1299,7 @@ func (h *CreateHandler) handleGKECreate(ctx context.Context, job *types.Job, clu
 	}
 
 	// Get profile to extract configuration
-	prof, err := h.registry.Get(cluster.Profile)
+	prof, err := h.clusterProfile(cluster)
 	if err != nil {
 		return fmt.Errorf("get profile: %w", err)
 	}
@@ -1461,7 +1354,7 @@ func (h *CreateHandler) handleROSACreate(ctx context.Context, job *types.Job, cl
 	rosaInstaller := installer.NewROSAInstaller()
 
 	// Get profile to extract configuration
-	prof, err := h.registry.Get(cluster.Profile)
+	prof, err := h.clusterProfile(cluster)
 	if err != nil {
 		return fmt.Errorf("get profile: %w", err)
 	}
@@ -1477,38 +1370,17 @@ func (h *CreateHandler) handleROSACreate(ctx context.Context, job *types.Job, cl
 		log.Printf("Resolved ROSA version %s -> %s for cluster %s", cluster.Version, resolvedVersion, cluster.Name)
 	}
 
-	// Build rosa create cluster command arguments
-	args := []string{
-		"--cluster-name", cluster.Name,
-		"--region", cluster.Region,
-		"--version", resolvedVersion,
-		"--yes", // Auto-approve
-	}
-
-	// Add compute configuration from profile
-	if prof.Compute.Workers.Replicas > 0 {
-		args = append(args, "--replicas", fmt.Sprintf("%d", prof.Compute.Workers.Replicas))
-	}
-	if prof.Compute.Workers.InstanceType != "" {
-		args = append(args, "--compute-machine-type", prof.Compute.Workers.InstanceType)
-	}
-
-	// Add multi-AZ if specified in profile
-	if prof.PlatformConfig.ROSA != nil && prof.PlatformConfig.ROSA.MultiAZ {
-		args = append(args, "--multi-az")
-	}
-
-	// Add tags (ROSA format: 'key value, key2 value2')
-	if len(cluster.EffectiveTags) > 0 {
-		tagStrs := make([]string, 0, len(cluster.EffectiveTags))
-		for k, v := range cluster.EffectiveTags {
-			tagStrs = append(tagStrs, fmt.Sprintf("%s %s", k, v))
+	// rosa takes the additional trust bundle as a file
+	trustBundlePath := ""
+	if rn := profile.EffectiveRestrictedNetwork(prof, cluster.RestrictedNetwork); rn != nil && rn.AdditionalTrustBundle != "" {
+		trustBundlePath = filepath.Join(workDir, clusterconfig.TrustBundleFile)
+		if err := os.WriteFile(trustBundlePath, []byte(rn.AdditionalTrustBundle), 0600); err != nil {
+			return fmt.Errorf("write additional trust bundle: %w", err)
 		}
-		args = append(args, "--tags", strings.Join(tagStrs, ", "))
 	}
 
-	// Add STS mode with auto mode (ROSA auto-creates operator roles)
-	args = append(args, "--sts", "--mode", "auto")
+	// Build rosa create cluster command arguments
+	args := clusterconfig.ROSAArgs(cluster, prof, resolvedVersion, trustBundlePath)
 
 	log.Printf("Creating ROSA cluster with args: %v", args)
 
@@ -1667,6 +1539,11 @@ func (h *CreateHandler) handleROSACreate(ctx context.Context, job *types.Job, cl
 		return fmt.Errorf("store artifacts: %w", err)
 	}
 
+	// Add extra machine pools requested through compute overrides
+	if err := h.createAdditionalMachinePools(ctx, cluster, prof, workDir); err != nil {
+		return fmt.Errorf("create additional machine pools: %w", err)
+	}
+
 	// Update cluster status to READY
 	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusReady); err != nil {
 		return fmt.Errorf("update cluster status to ready: %w", err)
@@ -10,7 +10,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/tsanders-rh/ocpctl/internal/clusterconfig"
 	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
@@ -25,7 +27,7 @@ func (h *CreateHandler) handleAROCreate(ctx context.Context, job *types.Job, clu
 	}
 
 	// Get profile configuration
-	prof, err := h.registry.Get(cluster.Profile)
+	prof, err := h.clusterProfile(cluster)
 	if err != nil {
 		return fmt.Errorf("get profile: %w", err)
 	}
@@ -35,8 +37,6 @@ func (h *CreateHandler) handleAROCreate(ctx context.Context, job *types.Job, clu
 		return fmt.Errorf("profile missing ARO configuration")
 	}
 
-	aroConfig := prof.PlatformConfig.ARO
-
 	// Create secure work directory
 	workDir, err := ensureSecureWorkDir(h.config.WorkDir, cluster.ID)
 	if err != nil {
@@ -64,7 +64,7 @@ func (h *CreateHandler) handleAROCreate(ctx context.Context, job *types.Job, clu
 	}
 
 	// Generate resource group name
-	resourceGroup := fmt.Sprintf("ocpctl-%s-rg", cluster.Name)
+	resourceGroup := clusterconfig.AzureResourceGroup(cluster.Name)
 
 	// Create resource group
 	log.Printf("[JOB %s] Creating resource group: %s", job.ID, resourceGroup)
@@ -73,9 +73,9 @@ func (h *CreateHandler) handleAROCreate(ctx context.Context, job *types.Job, clu
 	}
 
 	// Generate VNet and subnet names
-	vnetName := fmt.Sprintf("%s-vnet", cluster.Name)
-	masterSubnetName := "master-subnet"
-	workerSubnetName := "worker-subnet"
+	vnetName := clusterconfig.AROVNet(cluster.Name)
+	masterSubnetName := clusterconfig.AROMasterSubnet
+	workerSubnetName := clusterconfig.AROWorkerSubnet
 
 	// Create VNet
 	log.Printf("[JOB %s] Creating VNet: %s", job.ID, vnetName)
@@ -147,19 +147,13 @@ func (h *CreateHandler) handleAROCreate(ctx context.Context, job *types.Job, clu
 	}
 
 	// Build ARO cluster config
-	clusterConfig := &installer.AROClusterConfig{
-		Name:             cluster.Name,
-		ResourceGroup:    resourceGroup,
-		Region:           cluster.Region,
-		MasterVMSize:     aroConfig.MasterVMSize,
-		WorkerVMSize:     aroConfig.WorkerVMSize,
-		WorkerCount:      aroConfig.WorkerCount,
-		OpenShiftVersion: resolvedVersion,
-		PullSecret:       fmt.Sprintf("@%s", pullSecretPath),
-		Tags:             cluster.EffectiveTags,
-		ClientID:         clientID,
-		ClientSecret:     clientSecret,
+	clusterConfig, err := clusterconfig.ARO(cluster, prof, resolvedVersion)
+	if err != nil {
+		return err
 	}
+	clusterConfig.PullSecret = fmt.Sprintf("@%s", pullSecretPath)
+	clusterConfig.ClientID = clientID
+	clusterConfig.ClientSecret = clientSecret
 
 	// Create log file
 	logFilePath := filepath.Join(workDir, ".aro_install.log")
@@ -276,6 +270,24 @@ func (h *CreateHandler) handleAROCreate(ctx context.Context, job *types.Job, clu
 		log.Printf("Warning: failed to store artifacts: %v", err)
 	}
 
+	// az aro create has no proxy flags; configure the cluster-wide proxy and
+	// trust bundle through the API once the cluster is up
+	if rn := profile.EffectiveRestrictedNetwork(prof, cluster.RestrictedNetwork); rn != nil {
+		log.Printf("[JOB %s] Applying cluster-wide proxy configuration", job.ID)
+		rn, err := withProxyCredentials(ctx, h.config, cluster, rn)
+		if err != nil {
+			return err
+		}
+		if err := applyClusterProxy(ctx, kubeconfigPath, rn); err != nil {
+			return fmt.Errorf("apply cluster proxy: %w", err)
+		}
+	}
+
+	// Add extra machine pools requested through compute overrides
+	if err := h.createAdditionalMachinePools(ctx, cluster, prof, workDir); err != nil {
+		return fmt.Errorf("create additional machine pools: %w", err)
+	}
+
 	// Update cluster status to READY
 	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusReady); err != nil {
 		return fmt.Errorf("update cluster status: %w", err)
@@ -306,18 +318,17 @@ func (h *CreateHandler) handleAKSCreate(ctx context.Context, job *types.Job, clu
 	}
 
 	// Get profile configuration
-	prof, err := h.registry.Get(cluster.Profile)
+	prof, err := h.clusterProfile(cluster)
 	if err != nil {
 		return fmt.Errorf("get profile: %w", err)
 	}
 
-	// Validate AKS config in profile
-	if prof.PlatformConfig.AKS == nil {
-		return fmt.Errorf("profile missing AKS configuration")
+	// Build AKS cluster config
+	clusterConfig, err := clusterconfig.AKS(cluster, prof)
+	if err != nil {
+		return err
 	}
 
-	aksConfig := prof.PlatformConfig.AKS
-
 	// Create secure work directory
 	workDir, err := ensureSecureWorkDir(h.config.WorkDir, cluster.ID)
 	if err != nil {
@@ -328,7 +339,7 @@ func (h *CreateHandler) handleAKSCreate(ctx context.Context, job *types.Job, clu
 	aksInstaller := installer.NewAKSInstaller()
 
 	// Generate resource group name
-	resourceGroup := fmt.Sprintf("ocpctl-%s-rg", cluster.Name)
+	resourceGroup := clusterconfig.AzureResourceGroup(cluster.Name)
 
 	// Create resource group using ARO installer (shares resource group creation logic)
 	aroInstaller := installer.NewAROInstaller()
@@ -337,31 +348,6 @@ func (h *CreateHandler) handleAKSCreate(ctx context.Context, job *types.Job, clu
 		return fmt.Errorf("create resource group: %w", err)
 	}
 
-	// Build AKS cluster config
-	clusterConfig := &installer.AKSClusterConfig{
-		Name:              cluster.Name,
-		ResourceGroup:     resourceGroup,
-		Region:            cluster.Region,
-		KubernetesVersion: cluster.Version,
-		NetworkPlugin:     aksConfig.NetworkPlugin,
-		NetworkPolicy:     aksConfig.NetworkPolicy,
-		NodePools:         []installer.AKSNodePoolConfig{},
-		Tags:              cluster.EffectiveTags,
-	}
-
-	// Convert profile node pools to installer node pools
-	for _, pool := range aksConfig.NodePools {
-		clusterConfig.NodePools = append(clusterConfig.NodePools, installer.AKSNodePoolConfig{
-			Name:            pool.Name,
-			VMSize:          pool.VMSize,
-			Count:           pool.Count,
-			MinCount:        pool.MinCount,
-			MaxCount:        pool.MaxCount,
-			EnableAutoScale: pool.EnableAutoScale,
-			OSDiskSizeGB:    pool.OSDiskSizeGB,
-		})
-	}
-
 	// Create log file
 	logFilePath := filepath.Join(workDir, ".aks_install.log")
 
@@ -7,9 +7,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
+	"github.com/tsanders-rh/ocpctl/internal/clusterconfig"
 	"github.com/tsanders-rh/ocpctl/internal/installer"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
@@ -29,7 +29,7 @@ func (h *CreateHandler) HandleGKECreate(ctx context.Context, job *types.Job, clu
 	gkeInstaller := installer.NewGKEInstaller()
 
 	// Build GKE cluster configuration
-	config := h.buildGKEClusterConfig(cluster, prof)
+	config := clusterconfig.GKE(cluster, prof)
 
 	// Create log file path and ensure it exists before starting log streamer
 	logPath := filepath.Join(workDir, "gke-create.log")
@@ -127,176 +127,9 @@ func (h *CreateHandler) HandleGCPOpenShiftCreate(ctx context.Context, job *types
 	return nil
 }
 
-// buildGKEClusterConfig builds GKE cluster configuration from profile and cluster
-func (h *CreateHandler) buildGKEClusterConfig(cluster *types.Cluster, prof *profile.Profile) *installer.GKEClusterConfig {
-	config := &installer.GKEClusterConfig{
-		Name:    cluster.Name,
-		Project: getGCPProject(prof),
-		Region:  cluster.Region,
-		Labels:  make(map[string]string),
-	}
-
-	// Add GKE-specific configuration from profile
-	if prof.PlatformConfig.GKE != nil {
-		gkeConfig := prof.PlatformConfig.GKE
-
-		// Set cluster version (Kubernetes version)
-		if cluster.Version != "" {
-			config.ClusterVersion = cluster.Version
-		}
-
-		// Set release channel
-		if gkeConfig.ReleaseChannel != "" {
-			config.ReleaseChannel = gkeConfig.ReleaseChannel
-		}
-
-		// Enable Workload Identity
-		if gkeConfig.EnableWorkloadIdentity {
-			config.EnableWorkloadIdentity = true
-		}
-
-		// Set logging
-		if len(gkeConfig.EnabledClusterLogTypes) > 0 {
-			config.EnableClusterLogging = gkeConfig.EnabledClusterLogTypes
-			// Also enable monitoring if logging is enabled
-			config.EnableClusterMonitoring = []string{"SYSTEM_COMPONENTS"}
-		}
-
-		// Set access configuration
-		config.PublicAccess = gkeConfig.PublicAccess
-		config.PrivateAccess = gkeConfig.PrivateAccess
-
-		// Configure node pools
-		if len(gkeConfig.NodePools) > 0 {
-			config.NodePools = make([]installer.GKENodePoolConfig, len(gkeConfig.NodePools))
-			for i, pool := range gkeConfig.NodePools {
-				config.NodePools[i] = installer.GKENodePoolConfig{
-					Name:              pool.Name,
-					MachineType:       pool.MachineType,
-					DiskType:          pool.DiskType,
-					DiskSize:          pool.DiskSizeGB,
-					NumNodes:          pool.NodeCount,
-					MinNodes:          pool.MinNodeCount,
-					MaxNodes:          pool.MaxNodeCount,
-					EnableAutoscaling: pool.EnableAutoScale,
-					Labels:            make(map[string]string),
-				}
-
-				// Add cluster tags to node pool labels (sanitized for GCP)
-				if cluster.EffectiveTags != nil {
-					for k, v := range cluster.EffectiveTags {
-						labelKey := sanitizeGCPLabel(k)
-						labelValue := sanitizeGCPLabel(v)
-						config.NodePools[i].Labels[labelKey] = labelValue
-					}
-				}
-			}
-		}
-	}
-
-	// Add compute configuration from profile workers
-	if prof.Compute.Workers != nil {
-		workers := prof.Compute.Workers
-
-		// Set default node pool configuration if no node pools specified
-		if len(config.NodePools) == 0 {
-			config.MachineType = workers.MachineType
-			if workers.Autoscaling {
-				config.EnableAutoscaling = true
-				config.MinNodes = workers.MinReplicas
-				config.MaxNodes = workers.MaxReplicas
-				config.NumNodes = workers.MinReplicas // Start at minimum
-			} else {
-				config.NumNodes = workers.Replicas
-			}
-		}
-	}
-
-	// Add cluster labels for cost tracking and management
-	if cluster.EffectiveTags != nil {
-		for k, v := range cluster.EffectiveTags {
-			// GCP labels have restrictions: lowercase, alphanumeric, hyphens
-			// Convert to valid GCP label format
-			labelKey := sanitizeGCPLabel(k)
-			labelValue := sanitizeGCPLabel(v)
-			config.Labels[labelKey] = labelValue
-		}
-	}
-
-	// Add standard ocpctl labels
-	config.Labels["managed-by"] = "ocpctl"
-	config.Labels["cluster-id"] = cluster.ID
-	config.Labels["profile"] = sanitizeGCPLabel(cluster.Profile)
-
-	return config
-}
-
-// sanitizeGCPLabel converts a string to a valid GCP label value
-// GCP labels must be lowercase alphanumeric plus hyphens and underscores, max 63 chars
-// Keys must start with a lowercase letter
-func sanitizeGCPLabel(s string) string {
-	if s == "" {
-		return ""
-	}
-
-	// Convert to lowercase
-	s = strings.ToLower(s)
-
-	// Replace invalid characters with hyphens
-	// Valid characters: lowercase letters, numbers, hyphens, underscores
-	var result strings.Builder
-	for i, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
-			result.WriteRune(r)
-		} else {
-			// Replace invalid characters with hyphen
-			result.WriteRune('-')
-		}
-
-		// For keys (first position), ensure it starts with a letter
-		// If first character is not a letter, prepend 'x'
-		if i == 0 && !(r >= 'a' && r <= 'z') {
-			// Move to beginning
-			temp := result.String()
-			result.Reset()
-			result.WriteString("x")
-			result.WriteString(temp)
-		}
-	}
-
-	// Truncate to 63 characters max
-	sanitized := result.String()
-	if len(sanitized) > 63 {
-		sanitized = sanitized[:63]
-	}
-
-	// Remove trailing hyphens or underscores (GCP requirement)
-	sanitized = strings.TrimRight(sanitized, "-_")
-
-	return sanitized
-}
-
 // getGCPProject extracts the GCP project ID from profile configuration
 func getGCPProject(prof *profile.Profile) string {
-	// Check GKE config first
-	if prof.PlatformConfig.GKE != nil {
-		// GKE config doesn't have a project field in the current schema
-		// Will need to be added or pulled from environment
-	}
-
-	// Check GCP config (for OpenShift on GCP)
-	if prof.PlatformConfig.GCP != nil && prof.PlatformConfig.GCP.Project != "" {
-		return prof.PlatformConfig.GCP.Project
-	}
-
-	// Fall back to environment variable
-	if project := os.Getenv("GCP_PROJECT"); project != "" {
-		return project
-	}
-
-	// Default to empty - will cause error in GCP API calls
-	log.Printf("Warning: GCP project ID not found in profile or GCP_PROJECT environment variable")
-	return ""
+	return clusterconfig.GCPProject(prof)
 }
 
 // saveGKEMetadata saves GKE cluster metadata to a JSON file
@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsanders-rh/ocpctl/internal/clusterconfig"
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// hcpInstallerFor returns an HCP installer for the cluster's HostedCluster
+// namespace on the management cluster. A profile that has since been removed
+// falls back to the default namespace so destroy still works.
+func hcpInstallerFor(registry *profile.Registry, cluster *types.Cluster) (*installer.HCPInstaller, error) {
+	namespace := installer.HCPDefaultNamespace
+	if prof, err := registry.Get(cluster.Profile); err == nil {
+		namespace = clusterconfig.HCPNamespace(prof)
+	} else {
+		log.Printf("Warning: profile %s not found for HCP cluster %s, using namespace %s", cluster.Profile, cluster.Name, namespace)
+	}
+	return installer.NewHCPInstallerFromEnv(namespace)
+}
+
+// handleHCPCreate provisions a hosted control plane cluster by applying
+// HostedCluster and NodePool resources to the management cluster
+func (h *CreateHandler) handleHCPCreate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	log.Printf("[JOB %s] Starting HCP cluster creation: %s (version: %s)", job.ID, cluster.Name, cluster.Version)
+
+	// Update cluster status to CREATING
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusCreating); err != nil {
+		return fmt.Errorf("update cluster status: %w", err)
+	}
+
+	// Get profile configuration
+	prof, err := h.clusterProfile(cluster)
+	if err != nil {
+		return fmt.Errorf("get profile: %w", err)
+	}
+
+	clusterConfig, err := clusterconfig.HCP(cluster, prof)
+	if err != nil {
+		return err
+	}
+
+	// Get pull secret from environment, merged with the cluster's custom one
+	pullSecret := os.Getenv("OPENSHIFT_PULL_SECRET")
+	if pullSecret == "" {
+		return fmt.Errorf("OPENSHIFT_PULL_SECRET environment variable not set")
+	}
+	if cluster.CustomPullSecret != nil && *cluster.CustomPullSecret != "" {
+		merged, err := mergePullSecrets(pullSecret, *cluster.CustomPullSecret)
+		if err != nil {
+			return fmt.Errorf("merge pull secrets: %w", err)
+		}
+		pullSecret = merged
+	}
+	clusterConfig.PullSecret = pullSecret
+
+	// Create secure work directory
+	workDir, err := ensureSecureWorkDir(h.config.WorkDir, cluster.ID)
+	if err != nil {
+		return err
+	}
+
+	hcpInstaller, err := installer.NewHCPInstallerFromEnv(clusterconfig.HCPNamespace(prof))
+	if err != nil {
+		return types.NewPreflightCheckError("%v", err)
+	}
+
+	log.Printf("[JOB %s] Applying HostedCluster %s/%s with %d node pool(s)",
+		job.ID, hcpInstaller.Namespace(), cluster.Name, len(clusterConfig.NodePools))
+	if err := hcpInstaller.CreateCluster(ctx, clusterConfig); err != nil {
+		return fmt.Errorf("create HCP cluster: %w", err)
+	}
+
+	log.Printf("[JOB %s] Waiting for hosted control plane and node pools (up to %s)", job.ID, HCPReadyTimeout)
+	waitCtx, cancel := context.WithTimeout(ctx, HCPReadyTimeout)
+	defer cancel()
+	clusterInfo, err := hcpInstaller.WaitForReady(waitCtx, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("wait for HCP cluster: %w", err)
+	}
+
+	// Write kubeconfig and kubeadmin password where the other installers put them
+	authDir := filepath.Join(workDir, "auth")
+	if err := os.MkdirAll(authDir, 0700); err != nil {
+		return fmt.Errorf("create auth directory: %w", err)
+	}
+	kubeconfig, err := hcpInstaller.GetKubeconfig(ctx, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("get kubeconfig: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(authDir, "kubeconfig"), kubeconfig, 0600); err != nil {
+		return fmt.Errorf("write kubeconfig: %w", err)
+	}
+	hasPassword := false
+	if password, err := hcpInstaller.GetKubeadminPassword(ctx, cluster.Name); err != nil {
+		log.Printf("[JOB %s] Warning: failed to fetch HCP kubeadmin password: %v", job.ID, err)
+	} else if err := os.WriteFile(filepath.Join(authDir, "kubeadmin-password"), []byte(password), 0600); err != nil {
+		return fmt.Errorf("write kubeadmin password: %w", err)
+	} else {
+		hasPassword = true
+	}
+
+	// Create cluster outputs record
+	kubeconfigS3URI := fmt.Sprintf("s3://%s/clusters/%s/artifacts/auth/kubeconfig", h.config.S3BucketName, cluster.ID)
+	outputs := &types.ClusterOutputs{
+		ID:              uuid.New().String(),
+		ClusterID:       cluster.ID,
+		APIURL:          &clusterInfo.APIURL,
+		KubeconfigS3URI: &kubeconfigS3URI,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	if clusterInfo.ConsoleURL != "" {
+		outputs.ConsoleURL = &clusterInfo.ConsoleURL
+	}
+	if hasPassword {
+		kubeadminS3URI := fmt.Sprintf("s3://%s/clusters/%s/artifacts/auth/kubeadmin-password", h.config.S3BucketName, cluster.ID)
+		outputs.KubeadminSecretRef = &kubeadminS3URI
+	}
+	if err := h.store.ClusterOutputs.Upsert(ctx, outputs); err != nil {
+		return fmt.Errorf("create cluster outputs: %w", err)
+	}
+
+	// Upload kubeconfig and kubeadmin password to S3
+	if err := h.storeArtifacts(ctx, workDir, cluster.ID, false); err != nil {
+		return fmt.Errorf("store artifacts: %w", err)
+	}
+
+	// Create ServiceAccount for pool clusters
+	if cluster.PoolID != nil {
+		if err := h.createPoolLeaseServiceAccount(ctx, cluster, workDir, clusterInfo.APIURL); err != nil {
+			log.Printf("[JOB %s] Warning: failed to create ServiceAccount for pool cluster: %v", job.ID, err)
+		}
+	}
+
+	// Update cluster status to READY
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusReady); err != nil {
+		return fmt.Errorf("update cluster status: %w", err)
+	}
+
+	// Set grace period to prevent immediate hibernation after installation
+	gracePeriodExpiry := time.Now().Add(WorkHoursGracePeriod)
+	if err := h.store.Clusters.SetLastWorkHoursCheck(ctx, cluster.ID, gracePeriodExpiry); err != nil {
+		log.Printf("Warning: failed to set work hours grace period for cluster %s: %v", cluster.Name, err)
+	}
+
+	log.Printf("[JOB %s] HCP cluster creation completed successfully (API: %s)", job.ID, clusterInfo.APIURL)
+
+	// Handle post-deployment configuration if enabled
+	h.handlePostDeployment(ctx, cluster)
+
+	return nil
+}
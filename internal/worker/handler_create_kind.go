@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsanders-rh/ocpctl/internal/clusterconfig"
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// handleKindCreate provisions a kind cluster on the worker host. The
+// kubeconfig stays in the work directory and is referenced by a file:// URI,
+// so local clusters need neither S3 nor DNS.
+func (h *CreateHandler) handleKindCreate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	log.Printf("[JOB %s] Starting kind cluster creation: %s (version: %s)", job.ID, cluster.Name, cluster.Version)
+
+	// Update cluster status to CREATING
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusCreating); err != nil {
+		return fmt.Errorf("update cluster status: %w", err)
+	}
+
+	// Get profile configuration
+	prof, err := h.clusterProfile(cluster)
+	if err != nil {
+		return fmt.Errorf("get profile: %w", err)
+	}
+
+	clusterConfig, err := clusterconfig.Kind(cluster, prof)
+	if err != nil {
+		return err
+	}
+
+	// Create secure work directory
+	workDir, err := ensureSecureWorkDir(h.config.WorkDir, cluster.ID)
+	if err != nil {
+		return err
+	}
+	kubeconfigPath := filepath.Join(workDir, "auth", "kubeconfig")
+
+	kindInstaller := installer.NewKindInstaller()
+
+	// A retried job finds the cluster from the earlier attempt; reuse it
+	exists, err := kindInstaller.ClusterExists(ctx, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("check kind cluster: %w", err)
+	}
+	if exists {
+		log.Printf("[JOB %s] kind cluster %s already exists, exporting kubeconfig", job.ID, cluster.Name)
+		if err := kindInstaller.GetKubeconfig(ctx, cluster.Name, kubeconfigPath); err != nil {
+			return fmt.Errorf("get kubeconfig: %w", err)
+		}
+	} else {
+		log.Printf("[JOB %s] Creating kind cluster %s with %d worker(s) on %s",
+			job.ID, cluster.Name, clusterConfig.Workers, clusterConfig.NodeImage)
+		logFile := filepath.Join(workDir, "kind-create.log")
+		output, err := kindInstaller.CreateCluster(ctx, clusterConfig, workDir, kubeconfigPath, logFile)
+		if err != nil {
+			log.Printf("[JOB %s] kind create output: %s", job.ID, output)
+			return fmt.Errorf("create kind cluster: %w", err)
+		}
+	}
+
+	// Create cluster outputs record
+	outputs, err := kindOutputs(cluster.ID, kubeconfigPath)
+	if err != nil {
+		return err
+	}
+	if err := h.store.ClusterOutputs.Upsert(ctx, outputs); err != nil {
+		return fmt.Errorf("create cluster outputs: %w", err)
+	}
+	apiURL := *outputs.APIURL
+
+	// Create ServiceAccount for pool clusters
+	if cluster.PoolID != nil {
+		if err := h.createPoolLeaseServiceAccount(ctx, cluster, workDir, apiURL); err != nil {
+			log.Printf("[JOB %s] Warning: failed to create ServiceAccount for pool cluster: %v", job.ID, err)
+		}
+	}
+
+	// Update cluster status to READY
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusReady); err != nil {
+		return fmt.Errorf("update cluster status: %w", err)
+	}
+
+	// Set grace period to prevent immediate hibernation after installation
+	gracePeriodExpiry := time.Now().Add(WorkHoursGracePeriod)
+	if err := h.store.Clusters.SetLastWorkHoursCheck(ctx, cluster.ID, gracePeriodExpiry); err != nil {
+		log.Printf("Warning: failed to set work hours grace period for cluster %s: %v", cluster.Name, err)
+	}
+
+	log.Printf("[JOB %s] kind cluster creation completed successfully (API: %s)", job.ID, apiURL)
+
+	// Handle post-deployment configuration if enabled
+	h.handlePostDeployment(ctx, cluster)
+
+	return nil
+}
+
+// kindOutputs builds a kind cluster's outputs from its kubeconfig, which is
+// referenced in place by a file:// URI
+func kindOutputs(clusterID, kubeconfigPath string) (*types.ClusterOutputs, error) {
+	kubeconfig, err := os.ReadFile(kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("read kubeconfig: %w", err)
+	}
+	apiURL, err := installer.KubeconfigServer(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfigURI := "file://" + kubeconfigPath
+	return &types.ClusterOutputs{
+		ID:              uuid.New().String(),
+		ClusterID:       clusterID,
+		APIURL:          &apiURL,
+		KubeconfigS3URI: &kubeconfigURI,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/tsanders-rh/ocpctl/internal/installer"
 	"github.com/tsanders-rh/ocpctl/internal/metrics"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
 	"github.com/tsanders-rh/ocpctl/internal/store"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
@@ -22,35 +23,13 @@ type DestroyHandler struct {
 	config           *Config
 	store            *store.Store
 	registry         *profile.Registry
+	providers        *provider.Registry
 	metricsPublisher *metrics.Publisher
 }
 
 // NewDestroyHandler creates a new destroy handler
-func NewDestroyHandler(config *Config, st *store.Store) *DestroyHandler {
-	// Load profile registry
-	profilesDir := os.Getenv("PROFILES_DIR")
-	if profilesDir == "" {
-		profilesDir = "internal/profile/definitions"
-	}
-
-	loader := profile.NewLoader(profilesDir)
-	registry, err := profile.NewRegistry(loader)
-	if err != nil {
-		log.Fatalf("Failed to load profile registry: %v", err)
-	}
-
-	// Create metrics publisher (best effort - don't fail if it can't be created)
-	metricsPublisher, err := metrics.NewPublisher(context.Background())
-	if err != nil {
-		log.Printf("Warning: failed to create metrics publisher for destroy handler: %v", err)
-	}
-
-	return &DestroyHandler{
-		config:           config,
-		store:            st,
-		registry:         registry,
-		metricsPublisher: metricsPublisher,
-	}
+func NewDestroyHandler(config *Config, st *store.Store, registry *profile.Registry) *DestroyHandler {
+	return newClusterHandlers(config, st, registry).destroy
 }
 
 // Handle handles a cluster destruction job
@@ -63,25 +42,20 @@ func (h *DestroyHandler) Handle(ctx context.Context, job *types.Job) error {
 
 	log.Printf("Destroying cluster %s (platform=%s, cluster_type=%s)", cluster.Name, cluster.Platform, cluster.ClusterType)
 
-	// Route to appropriate handler based on cluster type
-	switch cluster.ClusterType {
-	case types.ClusterTypeOpenShift:
-		return h.handleOpenShiftDestroy(ctx, job, cluster)
-	case types.ClusterTypeROSA:
-		return h.handleROSADestroy(ctx, job, cluster)
-	case types.ClusterTypeEKS:
-		return h.handleEKSDestroy(ctx, job, cluster)
-	case types.ClusterTypeIKS:
-		return h.handleIKSDestroy(ctx, job, cluster)
-	case types.ClusterTypeGKE:
-		return h.handleGKEDestroy(ctx, job, cluster)
-	case types.ClusterTypeARO:
-		return h.handleARODestroy(ctx, job, cluster)
-	case types.ClusterTypeAKS:
-		return h.handleAKSDestroy(ctx, job, cluster)
-	default:
-		return fmt.Errorf("unsupported cluster type: %s", cluster.ClusterType)
+	p, err := h.providers.Get(cluster.ClusterType)
+	if err != nil {
+		return fmt.Errorf("unsupported cluster type: %w", err)
+	}
+
+	if err := p.Destroy(ctx, job, cluster); err != nil {
+		return err
+	}
+
+	// Fail the job, so it is retried, while the provider still finds resources
+	if err := p.VerifyDestroyed(ctx, cluster); err != nil {
+		return fmt.Errorf("verify cluster destroyed: %w", err)
 	}
+	return nil
 }
 
 // handleOpenShiftDestroy handles OpenShift cluster destruction
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// handleHCPDestroy deletes the HostedCluster and its NodePools from the
+// management cluster. HyperShift tears down the control plane namespace and
+// the KubeVirt worker VMs; a cluster that is already gone counts as destroyed.
+func (h *DestroyHandler) handleHCPDestroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	log.Printf("[JOB %s] Starting HCP cluster destruction: %s", job.ID, cluster.Name)
+
+	// Update cluster status to DESTROYING
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusDestroying); err != nil {
+		return fmt.Errorf("update cluster status: %w", err)
+	}
+
+	hcpInstaller, err := hcpInstallerFor(h.registry, cluster)
+	if err != nil {
+		return err
+	}
+
+	destroyCtx, cancel := context.WithTimeout(ctx, DestroyOperationTimeout)
+	defer cancel()
+	if err := hcpInstaller.DestroyCluster(destroyCtx, cluster.Name); err != nil {
+		return fmt.Errorf("destroy HCP cluster: %w", err)
+	}
+
+	// Update cluster status to DESTROYED
+	if err := h.store.Clusters.MarkDestroyed(ctx, cluster.ID); err != nil {
+		return fmt.Errorf("mark cluster destroyed: %w", err)
+	}
+
+	log.Printf("[JOB %s] HCP cluster destruction completed successfully", job.ID)
+	return nil
+}
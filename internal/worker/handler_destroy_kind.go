@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// handleKindDestroy deletes a kind cluster's node containers and the work
+// directory holding its kubeconfig. kind treats a missing cluster as deleted.
+func (h *DestroyHandler) handleKindDestroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	log.Printf("[JOB %s] Starting kind cluster destruction: %s", job.ID, cluster.Name)
+
+	// Update cluster status to DESTROYING
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusDestroying); err != nil {
+		return fmt.Errorf("update cluster status: %w", err)
+	}
+
+	destroyCtx, cancel := context.WithTimeout(ctx, DestroyOperationTimeout)
+	defer cancel()
+	if output, err := installer.NewKindInstaller().DeleteCluster(destroyCtx, cluster.Name); err != nil {
+		return fmt.Errorf("destroy kind cluster: %w (output: %s)", err, output)
+	}
+
+	workDir := filepath.Join(h.config.WorkDir, cluster.ID)
+	if err := os.RemoveAll(workDir); err != nil {
+		log.Printf("[JOB %s] Warning: failed to remove work directory %s: %v", job.ID, workDir, err)
+	}
+
+	// Update cluster status to DESTROYED
+	if err := h.store.Clusters.MarkDestroyed(ctx, cluster.ID); err != nil {
+		return fmt.Errorf("mark cluster destroyed: %w", err)
+	}
+
+	log.Printf("[JOB %s] kind cluster destruction completed successfully", job.ID)
+	return nil
+}
@@ -0,0 +1,548 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/internal/addon"
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// driftSourceCustom is the drift item source for tasks of a cluster's own
+// custom post-config
+const driftSourceCustom = "custom"
+
+// HandleDriftCheck compares a cluster's installed post-config with the
+// configuration recorded for it (DRIFT_CHECK jobs) and stores the report.
+// With the job's reconcile metadata set, drifted tasks are re-applied.
+func (h *PostConfigureHandler) HandleDriftCheck(ctx context.Context, job *types.Job) error {
+	ctx = context.WithValue(ctx, jobIDContextKey, job.ID)
+	reconcile, _ := job.Metadata["reconcile"].(bool)
+
+	cluster, err := h.store.Clusters.GetByID(ctx, job.ClusterID)
+	if err != nil {
+		return fmt.Errorf("get cluster: %w", err)
+	}
+
+	if caps, ok := h.providers.Capabilities(cluster.ClusterType); !ok || !caps.Addons {
+		return fmt.Errorf("drift checks are not supported for cluster type %s", cluster.ClusterType)
+	}
+
+	if cluster.Status != types.ClusterStatusReady {
+		return fmt.Errorf("cluster %s must be READY for a drift check (current: %s)", cluster.Name, cluster.Status)
+	}
+
+	if err := h.ensureArtifactsAvailable(ctx, cluster.ID); err != nil {
+		return fmt.Errorf("ensure artifacts available: %w", err)
+	}
+
+	workDir := filepath.Join(h.config.WorkDir, cluster.ID)
+	kubeconfigPath := filepath.Join(workDir, "auth", "kubeconfig")
+	if _, err := os.Stat(kubeconfigPath); os.IsNotExist(err) {
+		return fmt.Errorf("kubeconfig not found at %s", kubeconfigPath)
+	}
+
+	// Start log streaming for job output visibility
+	logWriter, closeLog := h.newJobLogWriter(ctx, job, "drift-check.log")
+	defer closeLog()
+
+	report := h.CheckDrift(ctx, cluster, kubeconfigPath, reconcile, logWriter)
+	report.JobID = &job.ID
+	if err := h.store.ConfigDrift.Upsert(ctx, report); err != nil {
+		return fmt.Errorf("store drift report: %w", err)
+	}
+
+	if report.Status == types.ConfigDriftFailed {
+		return fmt.Errorf("drift check failed: %s", *report.Error)
+	}
+	return nil
+}
+
+// CheckDrift compares the operators, manifests and Helm releases of the
+// cluster's installed add-ons and custom post-config with the cluster and,
+// if reconcile is set, re-applies the tasks that drifted. Tasks whose
+// condition is not met are skipped, as they were at install time. Failures
+// are reported in the returned report rather than as an error.
+func (h *PostConfigureHandler) CheckDrift(ctx context.Context, cluster *types.Cluster, kubeconfigPath string, reconcile bool,
+	logWriter func(string, ...interface{})) *types.ConfigDriftReport {
+	report := &types.ConfigDriftReport{
+		ClusterID: cluster.ID,
+		Status:    types.ConfigDriftInSync,
+		Items:     []types.ConfigDriftItem{},
+	}
+	mask := secretMaskFromContext(ctx)
+	fail := func(err error) *types.ConfigDriftReport {
+		msg := mask.Mask(err.Error())
+		logWriter("[DRIFT] %s", msg)
+		report.Status = types.ConfigDriftFailed
+		report.Error = &msg
+		return report
+	}
+
+	addonConfig, sources, err := h.installedAddonConfig(ctx, cluster)
+	if err != nil {
+		return fail(err)
+	}
+
+	infraID, _, err := h.getClusterInfraDetails(ctx, cluster, kubeconfigPath)
+	if err != nil {
+		log.Printf("Warning: failed to get infra details for templating: %v", err)
+		infraID = "" // Continue without infra ID
+	}
+
+	// Add-on tasks run before custom tasks at install time, so they are
+	// checked and re-applied in the same order
+	groups := []struct {
+		kind string
+		cfg  *types.CustomPostConfig
+	}{
+		{"addon", addonConfig},
+		{"custom", cluster.CustomPostConfig},
+	}
+
+	drifted := make(map[string]map[string]bool, len(groups))
+	for _, group := range groups {
+		if group.cfg == nil {
+			continue
+		}
+		dag, err := postconfig.BuildExecutionDAG(group.cfg)
+		if err != nil {
+			return fail(fmt.Errorf("build %s execution DAG: %w", group.kind, err))
+		}
+
+		for _, task := range dag.GetTasksByExecutionOrder() {
+			configType, ok := dagTaskConfigTypes[task.Type]
+			if !ok || task.Type == "script" {
+				continue // Scripts leave nothing to compare against
+			}
+			met, err := h.taskConditionMet(cluster, infraID, task)
+			if err != nil {
+				return fail(fmt.Errorf("evaluate condition of %s: %w", task.Name, err))
+			}
+			if !met {
+				continue
+			}
+
+			report.Checked++
+			reason, err := h.taskDrift(ctx, cluster, kubeconfigPath, infraID, task)
+			if err != nil {
+				return fail(fmt.Errorf("check %s %s: %w", task.Type, task.Name, err))
+			}
+			if reason == "" {
+				continue
+			}
+
+			source := driftSourceCustom
+			if group.kind == "addon" {
+				source = sources[task.Name]
+			}
+			reason = mask.Mask(reason)
+			logWriter("[DRIFT] %s %s (%s): %s", task.Type, task.Name, source, reason)
+			report.Items = append(report.Items, types.ConfigDriftItem{
+				Task:   task.Name,
+				Type:   configType,
+				Source: source,
+				Reason: reason,
+			})
+			if drifted[group.kind] == nil {
+				drifted[group.kind] = make(map[string]bool)
+			}
+			drifted[group.kind][task.Name] = true
+		}
+	}
+
+	logWriter("[DRIFT] Checked %d task(s) on cluster %s, %d drifted", report.Checked, cluster.Name, len(report.Items))
+	if len(report.Items) == 0 {
+		return report
+	}
+
+	report.Status = types.ConfigDriftDrifted
+	if !reconcile {
+		return report
+	}
+
+	for _, group := range groups {
+		if len(drifted[group.kind]) == 0 {
+			continue
+		}
+		dag, err := postconfig.BuildExecutionDAG(subsetPostConfig(group.cfg, drifted[group.kind]))
+		if err != nil {
+			return fail(fmt.Errorf("build %s reconcile DAG: %w", group.kind, err))
+		}
+		logWriter("[DRIFT] Re-applying %s task(s): %v", group.kind, dag.ExecutionOrder)
+		if err := h.runPostConfigDAG(ctx, cluster, kubeconfigPath, infraID, dag, group.kind, logWriter); err != nil {
+			return fail(fmt.Errorf("reconcile drift: %w", err))
+		}
+	}
+
+	for i := range report.Items {
+		report.Items[i].Reconciled = true
+	}
+	report.Status = types.ConfigDriftReconciled
+	logWriter("[DRIFT] Reconciled %d drifted task(s) on cluster %s", len(report.Items), cluster.Name)
+	return report
+}
+
+// hasRecordedPostConfig reports whether a cluster has add-ons or custom
+// post-config a drift check can compare against
+func hasRecordedPostConfig(cluster *types.Cluster) bool {
+	return len(cluster.SelectedAddonIDs) > 0 || cluster.CustomPostConfig != nil
+}
+
+// installedAddonConfig resolves the cluster's installed add-ons, with the
+// add-ons they require, into the merged config POST_CONFIGURE ran. sources
+// maps each task name to the add-on it came from.
+func (h *PostConfigureHandler) installedAddonConfig(ctx context.Context, cluster *types.Cluster) (*types.CustomPostConfig, map[string]string, error) {
+	selected, err := h.resolveSelectedAddons(ctx, cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(selected) == 0 {
+		return nil, nil, nil
+	}
+
+	resolution, err := addon.Resolve(ctx, addon.NewStoreCatalog(h.store.PostConfigAddons, cluster.OwnerID),
+		selected, h.templateContext(cluster, "", nil))
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve addon dependencies: %w", err)
+	}
+
+	merged, err := h.mergeAddonConfigs(resolution.Addons)
+	if err != nil {
+		return nil, nil, fmt.Errorf("merge addon configs: %w", err)
+	}
+
+	sources := make(map[string]string)
+	for _, a := range resolution.Addons {
+		for _, name := range configTaskNames(&a.Config) {
+			sources[name] = a.AddonID
+		}
+	}
+	return merged, sources, nil
+}
+
+// taskConditionMet evaluates a DAG task's condition with the same template
+// context used to install it
+func (h *PostConfigureHandler) taskConditionMet(cluster *types.Cluster, infraID string, task *postconfig.TaskNode) (bool, error) {
+	var condition string
+	var vars map[string]string
+	switch cfg := task.Config.(type) {
+	case types.CustomOperatorConfig:
+		condition = cfg.Condition
+	case types.CustomManifestConfig:
+		condition, vars = cfg.Condition, cfg.Variables
+	case types.CustomHelmChartConfig:
+		condition, vars = cfg.Condition, cfg.Variables
+	}
+	if condition == "" {
+		return true, nil
+	}
+	return postconfig.EvaluateCondition(condition, h.templateContext(cluster, infraID, vars))
+}
+
+// taskDrift compares one operator, manifest or Helm chart task with the
+// cluster, then runs each of its readiness checks once. It returns why the
+// task drifted, or "" if it matches. Errors mean the cluster could not be
+// inspected.
+func (h *PostConfigureHandler) taskDrift(ctx context.Context, cluster *types.Cluster, kubeconfigPath, infraID string, task *postconfig.TaskNode) (string, error) {
+	var reason, namespace string
+	var checks []types.ReadinessCheck
+	var err error
+
+	switch cfg := task.Config.(type) {
+	case types.CustomOperatorConfig:
+		reason, err = h.operatorDrift(ctx, kubeconfigPath, cfg)
+		namespace, checks = cfg.Namespace, cfg.Readiness
+	case types.CustomManifestConfig:
+		reason, err = h.manifestDrift(ctx, cluster, kubeconfigPath, infraID, cfg)
+		namespace, checks = cfg.Namespace, cfg.Readiness
+	case types.CustomHelmChartConfig:
+		reason, err = h.helmChartDrift(ctx, kubeconfigPath, cfg)
+		namespace, checks = cfg.Namespace, cfg.Readiness
+	default:
+		return "", fmt.Errorf("unsupported task type %s", task.Type)
+	}
+	if err != nil || reason != "" {
+		return reason, err
+	}
+
+	for i, check := range checks {
+		if check.Namespace == "" {
+			check.Namespace = namespace
+		}
+		probe, _, err := h.readinessProbeFor(cluster, kubeconfigPath, task.Name, i, check)
+		if err != nil {
+			return "", err
+		}
+		if ready, detail := probe(ctx); !ready {
+			return fmt.Sprintf("readiness check %d (%s) is not passing: %s", i+1, describeReadinessCheck(check), detail), nil
+		}
+	}
+	return "", nil
+}
+
+// operatorDrift checks that an operator's Subscription is on the configured
+// channel, its installed CSV has succeeded and its custom resource exists
+func (h *PostConfigureHandler) operatorDrift(ctx context.Context, kubeconfigPath string, op types.CustomOperatorConfig) (string, error) {
+	status, err := h.ocOutput(ctx, nil, kubeconfigPath, "get", "subscription", op.Name, "-n", op.Namespace,
+		"--ignore-not-found", "-o", `jsonpath={.metadata.name}{" "}{.spec.channel}{" "}{.status.installedCSV}`)
+	if err != nil {
+		return "", err
+	}
+	reason, csv := subscriptionDrift(status, op)
+	if reason != "" {
+		return reason, nil
+	}
+
+	phase, err := h.ocOutput(ctx, nil, kubeconfigPath, "get", "csv", csv, "-n", op.Namespace,
+		"--ignore-not-found", "-o", "jsonpath={.status.phase}")
+	if err != nil {
+		return "", err
+	}
+	switch strings.TrimSpace(phase) {
+	case "":
+		return fmt.Sprintf("CSV %s not found", csv), nil
+	case "Succeeded":
+	default:
+		return fmt.Sprintf("CSV %s is in phase %s", csv, strings.TrimSpace(phase)), nil
+	}
+
+	if cr := op.CustomResource; cr != nil {
+		namespace := cr.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+		yamlContent := fmt.Sprintf(`apiVersion: %s
+kind: %s
+metadata:
+  name: %s
+  namespace: %s
+`, cr.APIVersion, cr.Kind, cr.Name, namespace)
+		found, err := h.ocOutput(ctx, strings.NewReader(yamlContent), kubeconfigPath, "get", "-f", "-", "--ignore-not-found", "-o", "name")
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(found) == "" {
+			return fmt.Sprintf("custom resource %s/%s not found in %s", cr.Kind, cr.Name, namespace), nil
+		}
+	}
+	return "", nil
+}
+
+// subscriptionDrift checks a Subscription's name, channel and installed CSV,
+// separated by spaces, against an operator's config. It returns why the
+// Subscription drifted, or the installed CSV if it matches.
+func subscriptionDrift(status string, op types.CustomOperatorConfig) (reason, csv string) {
+	fields := strings.Split(strings.TrimSpace(status), " ")
+	if fields[0] == "" {
+		return fmt.Sprintf("subscription %s not found in %s", op.Name, op.Namespace), ""
+	}
+	var channel string
+	if len(fields) > 1 {
+		channel = fields[1]
+	}
+	if len(fields) > 2 {
+		csv = fields[2]
+	}
+	if op.Channel != "" && channel != op.Channel {
+		return fmt.Sprintf("subscription %s is on channel %q, expected %q", op.Name, channel, op.Channel), ""
+	}
+	if csv == "" {
+		return fmt.Sprintf("subscription %s has no installed CSV", op.Name), ""
+	}
+	return "", csv
+}
+
+// manifestDrift diffs a manifest, rendered the way it was applied, against
+// the cluster with "oc diff"
+func (h *PostConfigureHandler) manifestDrift(ctx context.Context, cluster *types.Cluster, kubeconfigPath, infraID string, manifest types.CustomManifestConfig) (string, error) {
+	content, url, err := h.manifestSource(ctx, cluster, infraID, manifest)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"--kubeconfig", kubeconfigPath, "diff", "-f", "-"}
+	if url != "" {
+		args[len(args)-1] = url
+	}
+	cmd := exec.CommandContext(ctx, "oc", args...)
+	if url == "" {
+		cmd.Stdin = strings.NewReader(content)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return "", nil
+	}
+	// oc diff exits 1 when there are differences and >1 on errors. Applying
+	// into a namespace that was deleted fails the dry-run with NotFound,
+	// which is drift as well.
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		if objects := diffObjects(string(output)); len(objects) > 0 {
+			return fmt.Sprintf("cluster differs from manifest: %s", strings.Join(objects, ", ")), nil
+		}
+		return "cluster differs from manifest", nil
+	case strings.Contains(string(output), "NotFound") || strings.Contains(string(output), "not found"):
+		return fmt.Sprintf("cannot be applied as recorded: %s", truncateOutput(output)), nil
+	default:
+		return "", fmt.Errorf("oc diff failed: %v\nOutput: %s", err, string(output))
+	}
+}
+
+// diffObjects lists the objects "oc diff" reports differences for. Each diff
+// header names the compared files after the object, e.g.
+// "apps.v1.Deployment.demo.web".
+func diffObjects(output string) []string {
+	var objects []string
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "diff ") {
+			continue
+		}
+		fields := strings.Fields(line)
+		objects = append(objects, path.Base(fields[len(fields)-1]))
+	}
+	return objects
+}
+
+// manifestSource returns a manifest's content rendered with the template
+// context used to apply it, or its URL for URL manifests. Git sources are
+// read again at the configured ref.
+func (h *PostConfigureHandler) manifestSource(ctx context.Context, cluster *types.Cluster, infraID string, manifest types.CustomManifestConfig) (content, url string, err error) {
+	if manifest.URL != "" {
+		if err := validateSecureURL(manifest.URL); err != nil {
+			return "", "", err
+		}
+		return "", manifest.URL, nil
+	}
+	if manifest.Git != nil {
+		content, _, err := h.fetchGitManifest(ctx, cluster, manifest)
+		if err != nil {
+			return "", "", fmt.Errorf("read manifest from git: %w", err)
+		}
+		return content, "", nil
+	}
+
+	renderCtx, err := h.renderContext(ctx, cluster, infraID, manifest.Variables)
+	if err != nil {
+		return "", "", err
+	}
+	rendered, err := postconfig.RenderTemplate(manifest.Content, renderCtx)
+	if err != nil {
+		return "", "", fmt.Errorf("render manifest content: %w", err)
+	}
+	return rendered, "", nil
+}
+
+// helmChartDrift checks that a chart's Helm release is deployed at the
+// configured chart version
+func (h *PostConfigureHandler) helmChartDrift(ctx context.Context, kubeconfigPath string, chart types.CustomHelmChartConfig) (string, error) {
+	cmd := exec.CommandContext(ctx, "helm", "list", "--all", "--filter", "^"+regexp.QuoteMeta(chart.Name)+"$", "-o", "json")
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("helm list failed: %v", err)
+	}
+	return helmReleaseDrift(output, chart)
+}
+
+// helmReleaseDrift checks "helm list -o json" output for a chart's release
+func helmReleaseDrift(output []byte, chart types.CustomHelmChartConfig) (string, error) {
+	var releases []struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Chart  string `json:"chart"`
+	}
+	if err := json.Unmarshal(output, &releases); err != nil {
+		return "", fmt.Errorf("parse helm list output: %w", err)
+	}
+
+	for _, release := range releases {
+		if release.Name != chart.Name {
+			continue
+		}
+		if release.Status != "deployed" {
+			return fmt.Sprintf("Helm release %s is %s", chart.Name, release.Status), nil
+		}
+		// The release's chart is "<chart>-<version>"; a leading "v" on
+		// either version is ignored
+		if chart.Version != "" {
+			want := strings.TrimPrefix(chart.Version, "v")
+			got := strings.TrimPrefix(strings.TrimPrefix(release.Chart, chart.Chart+"-"), "v")
+			if got != want {
+				return fmt.Sprintf("Helm release %s runs chart %s, expected version %s", chart.Name, release.Chart, chart.Version), nil
+			}
+		}
+		return "", nil
+	}
+	return fmt.Sprintf("Helm release %s not found", chart.Name), nil
+}
+
+// subsetPostConfig returns the tasks of cfg named in names, keeping only
+// dependencies within the subset; the others are already in place
+func subsetPostConfig(cfg *types.CustomPostConfig, names map[string]bool) *types.CustomPostConfig {
+	deps := func(dependsOn []string) []string {
+		var kept []string
+		for _, dep := range dependsOn {
+			if names[dep] {
+				kept = append(kept, dep)
+			}
+		}
+		return kept
+	}
+
+	subset := &types.CustomPostConfig{}
+	for _, op := range cfg.Operators {
+		if names[op.Name] {
+			op.DependsOn = deps(op.DependsOn)
+			subset.Operators = append(subset.Operators, op)
+		}
+	}
+	for _, script := range cfg.Scripts {
+		if names[script.Name] {
+			script.DependsOn = deps(script.DependsOn)
+			subset.Scripts = append(subset.Scripts, script)
+		}
+	}
+	for _, manifest := range cfg.Manifests {
+		if names[manifest.Name] {
+			manifest.DependsOn = deps(manifest.DependsOn)
+			subset.Manifests = append(subset.Manifests, manifest)
+		}
+	}
+	for _, chart := range cfg.HelmCharts {
+		if names[chart.Name] {
+			chart.DependsOn = deps(chart.DependsOn)
+			subset.HelmCharts = append(subset.HelmCharts, chart)
+		}
+	}
+	return subset
+}
+
+// ocOutput runs an oc command against the cluster and returns its standard
+// output. stdin may be nil.
+func (h *PostConfigureHandler) ocOutput(ctx context.Context, stdin io.Reader, kubeconfigPath string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "oc", append([]string{"--kubeconfig", kubeconfigPath}, args...)...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("oc %s failed: %v\nOutput: %s", args[0], err, stderr.String())
+	}
+	return string(output), nil
+}
@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestSubscriptionDrift(t *testing.T) {
+	op := types.CustomOperatorConfig{Name: "redhat-oadp-operator", Namespace: "openshift-adp", Channel: "stable-1.4"}
+
+	tests := []struct {
+		name   string
+		status string
+		reason string
+		csv    string
+	}{
+		{"in sync", "redhat-oadp-operator stable-1.4 oadp-operator.v1.4.2", "", "oadp-operator.v1.4.2"},
+		{"deleted", "", "subscription redhat-oadp-operator not found in openshift-adp", ""},
+		{"channel changed", "redhat-oadp-operator stable-1.3 oadp-operator.v1.3.5",
+			`subscription redhat-oadp-operator is on channel "stable-1.3", expected "stable-1.4"`, ""},
+		{"no installed CSV", "redhat-oadp-operator stable-1.4 ", "subscription redhat-oadp-operator has no installed CSV", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, csv := subscriptionDrift(tt.status, op)
+			assert.Equal(t, tt.reason, reason)
+			assert.Equal(t, tt.csv, csv)
+		})
+	}
+}
+
+func TestDiffObjects(t *testing.T) {
+	output := "diff -u -N /tmp/LIVE-1234/apps.v1.Deployment.demo.web /tmp/MERGED-5678/apps.v1.Deployment.demo.web\n" +
+		"--- /tmp/LIVE-1234/apps.v1.Deployment.demo.web\n" +
+		"+++ /tmp/MERGED-5678/apps.v1.Deployment.demo.web\n" +
+		"@@ -6,7 +6,7 @@\n" +
+		"-  replicas: 1\n" +
+		"+  replicas: 3\n" +
+		"diff -u -N /tmp/LIVE-1234/v1.ConfigMap.demo.settings /tmp/MERGED-5678/v1.ConfigMap.demo.settings\n"
+
+	assert.Equal(t, []string{"apps.v1.Deployment.demo.web", "v1.ConfigMap.demo.settings"}, diffObjects(output))
+	assert.Empty(t, diffObjects(""))
+}
+
+func TestHelmReleaseDrift(t *testing.T) {
+	chart := types.CustomHelmChartConfig{Name: "podinfo", Chart: "podinfo", Version: "6.5.0"}
+	release := func(status, chartVersion string) []byte {
+		return []byte(`[{"name":"podinfo","namespace":"default","revision":"2","status":"` + status +
+			`","chart":"` + chartVersion + `","app_version":"6.5.0"}]`)
+	}
+
+	reason, err := helmReleaseDrift(release("deployed", "podinfo-6.5.0"), chart)
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+
+	chart.Version = "v6.5.0"
+	reason, err = helmReleaseDrift(release("deployed", "podinfo-6.5.0"), chart)
+	require.NoError(t, err)
+	assert.Empty(t, reason, "a leading v is ignored")
+
+	reason, err = helmReleaseDrift(release("deployed", "podinfo-6.4.1"), chart)
+	require.NoError(t, err)
+	assert.Equal(t, "Helm release podinfo runs chart podinfo-6.4.1, expected version v6.5.0", reason)
+
+	reason, err = helmReleaseDrift(release("failed", "podinfo-6.5.0"), chart)
+	require.NoError(t, err)
+	assert.Equal(t, "Helm release podinfo is failed", reason)
+
+	reason, err = helmReleaseDrift([]byte("[]"), chart)
+	require.NoError(t, err)
+	assert.Equal(t, "Helm release podinfo not found", reason)
+
+	chart.Version = ""
+	reason, err = helmReleaseDrift(release("deployed", "podinfo-7.0.0"), chart)
+	require.NoError(t, err)
+	assert.Empty(t, reason, "unpinned charts accept any version")
+
+	_, err = helmReleaseDrift([]byte("not json"), chart)
+	assert.Error(t, err)
+}
+
+func TestSubsetPostConfig(t *testing.T) {
+	cfg := &types.CustomPostConfig{
+		Operators: []types.CustomOperatorConfig{
+			{Name: "oadp", Namespace: "openshift-adp", Channel: "stable"},
+		},
+		Manifests: []types.CustomManifestConfig{
+			{Name: "dpa", Content: "kind: DataProtectionApplication", DependsOn: []string{"oadp"}},
+			{Name: "schedule", Content: "kind: Schedule", DependsOn: []string{"dpa", "oadp"}},
+		},
+		HelmCharts: []types.CustomHelmChartConfig{
+			{Name: "ui", Repo: "https://charts.example.com", Chart: "ui", DependsOn: []string{"oadp"}},
+		},
+	}
+
+	subset := subsetPostConfig(cfg, map[string]bool{"dpa": true, "schedule": true})
+	assert.Empty(t, subset.Operators)
+	assert.Empty(t, subset.HelmCharts)
+	require.Len(t, subset.Manifests, 2)
+	assert.Empty(t, subset.Manifests[0].DependsOn, "dependencies outside the subset are dropped")
+	assert.Equal(t, []string{"dpa"}, subset.Manifests[1].DependsOn)
+	assert.Equal(t, []string{"dpa", "oadp"}, cfg.Manifests[1].DependsOn, "input must not be modified")
+
+	dag, err := postconfig.BuildExecutionDAG(subset)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dpa", "schedule"}, dag.ExecutionOrder)
+}
+
+func TestHasRecordedPostConfig(t *testing.T) {
+	assert.False(t, hasRecordedPostConfig(&types.Cluster{}))
+	assert.True(t, hasRecordedPostConfig(&types.Cluster{SelectedAddonIDs: []string{"oadp"}}))
+	assert.True(t, hasRecordedPostConfig(&types.Cluster{CustomPostConfig: &types.CustomPostConfig{}}))
+}
@@ -17,36 +17,22 @@ import (
 	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
 	"github.com/tsanders-rh/ocpctl/internal/installer"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
 	"github.com/tsanders-rh/ocpctl/internal/store"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 // HibernateHandler handles cluster hibernation jobs
 type HibernateHandler struct {
-	config   *Config
-	store    *store.Store
-	registry *profile.Registry
+	config    *Config
+	store     *store.Store
+	registry  *profile.Registry
+	providers *provider.Registry
 }
 
 // NewHibernateHandler creates a new hibernate handler
-func NewHibernateHandler(cfg *Config, st *store.Store) *HibernateHandler {
-	// Load profile registry
-	profilesDir := os.Getenv("PROFILES_DIR")
-	if profilesDir == "" {
-		profilesDir = "internal/profile/definitions"
-	}
-
-	loader := profile.NewLoader(profilesDir)
-	registry, err := profile.NewRegistry(loader)
-	if err != nil {
-		log.Fatalf("Failed to load profile registry: %v", err)
-	}
-
-	return &HibernateHandler{
-		config:   cfg,
-		store:    st,
-		registry: registry,
-	}
+func NewHibernateHandler(cfg *Config, st *store.Store, registry *profile.Registry) *HibernateHandler {
+	return newClusterHandlers(cfg, st, registry).hibernate
 }
 
 // Handle handles a cluster hibernation job
@@ -59,25 +45,15 @@ func (h *HibernateHandler) Handle(ctx context.Context, job *types.Job) error {
 
 	log.Printf("Hibernating cluster %s (platform=%s, cluster_type=%s)", cluster.Name, cluster.Platform, cluster.ClusterType)
 
-	// Route by cluster type
-	switch cluster.ClusterType {
-	case types.ClusterTypeOpenShift:
-		return h.hibernateOpenShift(ctx, cluster, job)
-	case types.ClusterTypeROSA:
-		return h.hibernateROSA(ctx, cluster, job)
-	case types.ClusterTypeEKS:
-		return h.hibernateEKS(ctx, cluster, job)
-	case types.ClusterTypeIKS:
-		return h.hibernateIKS(ctx, cluster, job)
-	case types.ClusterTypeGKE:
-		return h.hibernateGKE(ctx, cluster, job)
-	case types.ClusterTypeARO:
-		return h.hibernateARO(ctx, cluster, job)
-	case types.ClusterTypeAKS:
-		return h.hibernateAKS(ctx, cluster, job)
-	default:
-		return fmt.Errorf("unsupported cluster type for hibernation: %s", cluster.ClusterType)
+	p, err := h.providers.Get(cluster.ClusterType)
+	if err != nil {
+		return fmt.Errorf("unsupported cluster type for hibernation: %w", err)
 	}
+	if caps := p.Capabilities(); !caps.Hibernate {
+		return fmt.Errorf("hibernation is not supported for %s clusters", caps.DisplayName)
+	}
+
+	return p.Hibernate(ctx, job, cluster)
 }
 
 // hibernateOpenShift hibernates an OpenShift cluster (AWS, IBMCloud, or GCP)
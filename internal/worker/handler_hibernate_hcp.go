@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// hibernateHCP hibernates a hosted cluster by scaling its NodePools to zero.
+// The hosted control plane keeps running on the management cluster.
+func (h *HibernateHandler) hibernateHCP(ctx context.Context, cluster *types.Cluster, job *types.Job) error {
+	log.Printf("Hibernating HCP cluster %s by scaling node pools to 0", cluster.Name)
+
+	// Update cluster status to HIBERNATING
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusHibernating); err != nil {
+		return fmt.Errorf("update cluster status to HIBERNATING: %w", err)
+	}
+
+	hcpInstaller, err := hcpInstallerFor(h.registry, cluster)
+	if err != nil {
+		return err
+	}
+
+	replicas, err := hcpInstaller.Hibernate(ctx, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("scale node pools to 0: %w", err)
+	}
+
+	// Save original replicas to job metadata for resume
+	replicasJSON, err := json.Marshal(replicas)
+	if err != nil {
+		return fmt.Errorf("marshal node pool replicas: %w", err)
+	}
+	if job.Metadata == nil {
+		job.Metadata = make(types.JobMetadata)
+	}
+	job.Metadata["hcp_nodepool_replicas"] = string(replicasJSON)
+
+	// Update cluster status to HIBERNATED
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusHibernated); err != nil {
+		return fmt.Errorf("update cluster status to HIBERNATED: %w", err)
+	}
+
+	log.Printf("HCP cluster %s hibernated successfully (%d node pools scaled to 0)", cluster.Name, len(replicas))
+	return nil
+}
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// hibernateKind hibernates a kind cluster by stopping its node containers.
+// Containers keep their state, so resume only has to start them again.
+func (h *HibernateHandler) hibernateKind(ctx context.Context, cluster *types.Cluster, job *types.Job) error {
+	log.Printf("Hibernating kind cluster %s by stopping node containers", cluster.Name)
+
+	// Update cluster status to HIBERNATING
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusHibernating); err != nil {
+		return fmt.Errorf("update cluster status to HIBERNATING: %w", err)
+	}
+
+	nodes, err := installer.NewKindInstaller().StopNodes(ctx, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("stop node containers: %w", err)
+	}
+
+	// Update cluster status to HIBERNATED
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusHibernated); err != nil {
+		return fmt.Errorf("update cluster status to HIBERNATED: %w", err)
+	}
+
+	log.Printf("kind cluster %s hibernated successfully (%d node containers stopped)", cluster.Name, len(nodes))
+	return nil
+}
@@ -20,15 +20,17 @@ import (
 
 // PoolCleanHandler handles cluster cleaning/sanitization for pools
 type PoolCleanHandler struct {
-	config *Config
-	store  *store.Store
+	config     *Config
+	store      *store.Store
+	postConfig *PostConfigureHandler // Checks post-config drift before a cluster returns to the pool
 }
 
 // NewPoolCleanHandler creates a new pool clean handler
-func NewPoolCleanHandler(config *Config, st *store.Store) *PoolCleanHandler {
+func NewPoolCleanHandler(config *Config, st *store.Store, postConfig *PostConfigureHandler) *PoolCleanHandler {
 	return &PoolCleanHandler{
-		config: config,
-		store:  st,
+		config:     config,
+		store:      st,
+		postConfig: postConfig,
 	}
 }
 
@@ -105,6 +107,12 @@ func (h *PoolCleanHandler) Handle(ctx context.Context, job *types.Job) error {
 			log.Printf("Warning: Failed to rotate kubeadmin password for cluster %s: %v", cluster.Name, err)
 			// Don't fail the cleanup job - cluster can still be used with old password
 		}
+
+		// Check post-config drift left by the previous lease, re-applying it
+		// if the pool asks for that
+		if converged := h.checkPostConfigDrift(ctx, job, cluster, kubeconfigPath); !converged {
+			return h.markClusterExpired(ctx, cluster, "post-config drift could not be reconciled")
+		}
 	}
 
 	// Reset cluster metadata and mark as READY
@@ -425,6 +433,36 @@ func generateRandomPassword(length int) (string, error) {
 	return string(b), nil
 }
 
+// checkPostConfigDrift compares the cluster's post-config with what is
+// installed, which the previous leasee may have changed and namespace cleanup
+// may have removed, and records the report. Pools with drift reconciliation
+// enabled re-apply drifted tasks, and false is returned if such a pool's
+// cluster could not be checked or converged. For other pools drift is only
+// reported.
+func (h *PoolCleanHandler) checkPostConfigDrift(ctx context.Context, job *types.Job, cluster *types.Cluster, kubeconfigPath string) bool {
+	if h.postConfig == nil || !hasRecordedPostConfig(cluster) {
+		return true
+	}
+
+	pool, err := h.store.Pools.GetByID(ctx, *cluster.PoolID)
+	if err != nil {
+		log.Printf("Warning: Failed to get pool for drift check of cluster %s: %v", cluster.Name, err)
+		return true
+	}
+
+	// Re-applied tasks may log resolved secrets, which the job log writer masks
+	logWriter, closeLog := h.postConfig.newJobLogWriter(ctx, job, "drift-check.log")
+	defer closeLog()
+
+	report := h.postConfig.CheckDrift(ctx, cluster, kubeconfigPath, pool.ReconcileDriftEnabled, logWriter)
+	report.JobID = &job.ID
+	if err := h.store.ConfigDrift.Upsert(ctx, report); err != nil {
+		log.Printf("Warning: Failed to store drift report for cluster %s: %v", cluster.Name, err)
+	}
+
+	return report.Status != types.ConfigDriftFailed || !pool.ReconcileDriftEnabled
+}
+
 // markClusterExpired marks a cluster as EXPIRED when cleanup cannot be performed
 func (h *PoolCleanHandler) markClusterExpired(ctx context.Context, cluster *types.Cluster, reason string) error {
 	log.Printf("Marking cluster %s as EXPIRED: %s", cluster.Name, reason)
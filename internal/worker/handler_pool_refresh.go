@@ -15,15 +15,17 @@ import (
 
 // PoolRefreshHandler handles cluster refresh for pools (replacing expired clusters)
 type PoolRefreshHandler struct {
-	config *Config
-	store  *store.Store
+	config   *Config
+	store    *store.Store
+	registry *profile.Registry
 }
 
 // NewPoolRefreshHandler creates a new pool refresh handler
-func NewPoolRefreshHandler(config *Config, st *store.Store) *PoolRefreshHandler {
+func NewPoolRefreshHandler(config *Config, st *store.Store, registry *profile.Registry) *PoolRefreshHandler {
 	return &PoolRefreshHandler{
-		config: config,
-		store:  st,
+		config:   config,
+		store:    st,
+		registry: registry,
 	}
 }
 
@@ -72,13 +74,8 @@ func (h *PoolRefreshHandler) Handle(ctx context.Context, job *types.Job) error {
 	// This ensures pool capacity is maintained
 	newClusterName := fmt.Sprintf("%s-%s", pool.Name, uuid.New().String()[:8])
 
-	// Load profile registry
-	profileRegistry, err := h.loadProfileRegistry()
-	if err != nil {
-		return fmt.Errorf("failed to load profile registry: %w", err)
-	}
-
-	prof, err := profileRegistry.Get(pool.Profile)
+	// Get profile details for cluster creation
+	prof, err := h.registry.Get(pool.Profile)
 	if err != nil {
 		return fmt.Errorf("profile %s not found: %w", pool.Profile, err)
 	}
@@ -211,14 +208,3 @@ func (h *PoolRefreshHandler) Handle(ctx context.Context, job *types.Job) error {
 
 	return nil
 }
-
-// loadProfileRegistry loads the profile registry
-func (h *PoolRefreshHandler) loadProfileRegistry() (*profile.Registry, error) {
-	// Get profiles directory from environment (same as API and other workers)
-	profilesDir := os.Getenv("PROFILES_DIR")
-	if profilesDir == "" {
-		profilesDir = "/opt/ocpctl/profiles" // Default path
-	}
-	loader := profile.NewLoader(profilesDir)
-	return profile.NewRegistry(loader)
-}
@@ -17,15 +17,17 @@ import (
 type PoolReplenishHandler struct {
 	config        *Config
 	store         *store.Store
+	registry      *profile.Registry
 	createHandler *CreateHandler
 }
 
 // NewPoolReplenishHandler creates a new pool replenish handler
-func NewPoolReplenishHandler(config *Config, st *store.Store) *PoolReplenishHandler {
+func NewPoolReplenishHandler(config *Config, st *store.Store, registry *profile.Registry) *PoolReplenishHandler {
 	return &PoolReplenishHandler{
 		config:        config,
 		store:         st,
-		createHandler: NewCreateHandler(config, st),
+		registry:      registry,
+		createHandler: NewCreateHandler(config, st, registry),
 	}
 }
 
@@ -135,19 +137,14 @@ func (h *PoolReplenishHandler) Handle(ctx context.Context, job *types.Job) error
 	}
 
 	// Get profile details for cluster creation
-	profileRegistry, err := h.loadProfileRegistry()
-	if err != nil {
-		return fmt.Errorf("failed to load profile registry: %w", err)
-	}
-
-	prof, err := profileRegistry.Get(pool.Profile)
+	prof, err := h.registry.Get(pool.Profile)
 	if err != nil {
 		return fmt.Errorf("profile %s not found: %w", pool.Profile, err)
 	}
 
 	// Get default version based on cluster type
 	var defaultVersion string
-	if prof.ClusterType == types.ClusterTypeOpenShift || prof.ClusterType == types.ClusterTypeROSA || prof.ClusterType == types.ClusterTypeARO {
+	if prof.ClusterType == types.ClusterTypeOpenShift || prof.ClusterType == types.ClusterTypeROSA || prof.ClusterType == types.ClusterTypeARO || prof.ClusterType == types.ClusterTypeHCP {
 		if prof.OpenshiftVersions != nil {
 			defaultVersion = prof.OpenshiftVersions.Default
 		}
@@ -162,7 +159,16 @@ func (h *PoolReplenishHandler) Handle(ctx context.Context, job *types.Job) error
 	if baseDomainStr == "" {
 		baseDomainStr = "mg.dog8code.com" // Default domain
 	}
+	// HCP routes are published under a domain the management cluster serves,
+	// so use the profile's rather than the IPI default
+	if prof.ClusterType == types.ClusterTypeHCP && prof.BaseDomains != nil {
+		baseDomainStr = prof.BaseDomains.Default
+	}
 	baseDomain := &baseDomainStr
+	// kind clusters live on the worker host and have no DNS
+	if prof.ClusterType == types.ClusterTypeKind {
+		baseDomain = nil
+	}
 
 	// Provision clusters
 	for i := 0; i < clustersNeeded; i++ {
@@ -236,14 +242,3 @@ func (h *PoolReplenishHandler) Handle(ctx context.Context, job *types.Job) error
 	log.Printf("Pool replenishment complete for %s: provisioned %d cluster(s)", pool.Name, clustersNeeded)
 	return nil
 }
-
-// loadProfileRegistry loads the profile registry
-func (h *PoolReplenishHandler) loadProfileRegistry() (*profile.Registry, error) {
-	// Get profiles directory from environment (same as API and other workers)
-	profilesDir := os.Getenv("PROFILES_DIR")
-	if profilesDir == "" {
-		profilesDir = "/opt/ocpctl/profiles" // Default path
-	}
-	loader := profile.NewLoader(profilesDir)
-	return profile.NewRegistry(loader)
-}
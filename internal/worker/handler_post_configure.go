@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -15,12 +14,16 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/tsanders-rh/ocpctl/internal/addon"
 	"github.com/tsanders-rh/ocpctl/internal/installer"
 	"github.com/tsanders-rh/ocpctl/internal/postconfig"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
 	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/internal/validation"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
@@ -31,17 +34,19 @@ const jobIDContextKey contextKey = "jobID"
 
 // PostConfigureHandler handles post-configuration tasks (e.g., installing dashboards)
 type PostConfigureHandler struct {
-	config   *Config
-	store    *store.Store
-	registry *profile.Registry
+	config    *Config
+	store     *store.Store
+	registry  *profile.Registry
+	providers *provider.Registry
 }
 
 // NewPostConfigureHandler creates a new post-configure handler
 func NewPostConfigureHandler(config *Config, st *store.Store, profileRegistry *profile.Registry) *PostConfigureHandler {
 	return &PostConfigureHandler{
-		config:   config,
-		store:    st,
-		registry: profileRegistry,
+		config:    config,
+		store:     st,
+		registry:  profileRegistry,
+		providers: provider.NewRegistry(),
 	}
 }
 
@@ -69,7 +74,10 @@ func (h *PostConfigureHandler) Handle(ctx context.Context, job *types.Job) error
 		return h.handleIKSPostConfigure(ctx, job, cluster)
 	case types.ClusterTypeGKE:
 		return h.handleGKEPostConfigure(ctx, job, cluster)
-	case types.ClusterTypeOpenShift:
+	case types.ClusterTypeOpenShift, types.ClusterTypeHCP, types.ClusterTypeKind:
+		// HCP guest clusters are OpenShift with OLM; only the kubeconfig differs.
+		// kind keeps its kubeconfig in the work directory, so manifests, Helm
+		// charts and scripts run the same way (operators need OLM installed).
 		return h.handleOpenShiftPostConfigure(ctx, job, cluster)
 	default:
 		return fmt.Errorf("unsupported cluster type: %s", cluster.ClusterType)
@@ -1251,6 +1259,51 @@ func (h *PostConfigureHandler) handleGKEPostConfigure(ctx context.Context, job *
 	return nil
 }
 
+// newJobLogWriter creates a log writer for a job that writes to the worker
+// log and to name in the cluster's work directory, which is streamed to the
+// job's stored logs. Secret values resolved by the job are masked before a
+// message reaches any of them. DAG tasks log concurrently, so file writes are
+// serialized. The returned function flushes the stream and closes the file.
+func (h *PostConfigureHandler) newJobLogWriter(ctx context.Context, job *types.Job, name string) (func(string, ...interface{}), func()) {
+	logPath := filepath.Join(h.config.WorkDir, job.ClusterID, name)
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		log.Printf("Warning: failed to create log file: %v", err)
+	}
+
+	mask := secretMaskFromContext(ctx)
+	var logMu sync.Mutex
+	logWriter := func(format string, args ...interface{}) {
+		msg := mask.Mask(fmt.Sprintf(format, args...))
+		log.Print(msg)
+		if logFile != nil {
+			logMu.Lock()
+			fmt.Fprintln(logFile, msg)
+			logFile.Sync() // Flush to disk so LogStreamer can read it
+			logMu.Unlock()
+		}
+	}
+
+	// Start log streaming to database
+	streamer := NewLogStreamer(h.store, job.ClusterID, job.ID, logPath)
+	streamCtx, streamCancel := context.WithCancel(ctx)
+	if err := streamer.Start(streamCtx); err != nil {
+		logWriter("Warning: failed to start log streaming: %v", err)
+	}
+
+	closeLog := func() {
+		streamCancel()
+		time.Sleep(LogBatchFlushDelay) // Allow final batch to flush
+		if stopErr := streamer.Stop(); stopErr != nil {
+			log.Printf("Warning: error stopping log streamer: %v", stopErr)
+		}
+		if logFile != nil {
+			logFile.Close()
+		}
+	}
+	return logWriter, closeLog
+}
+
 // handleOpenShiftPostConfigure handles profile-driven post-deployment for OpenShift clusters
 func (h *PostConfigureHandler) handleOpenShiftPostConfigure(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
 	log.Printf("Running post-deployment for OpenShift cluster %s", cluster.Name)
@@ -1298,12 +1351,7 @@ func (h *PostConfigureHandler) handleOpenShiftPostConfigure(ctx context.Context,
 		// Resolve addons by addon_id (SelectedAddonIDs contains addon_id strings like "cnv" or "cnv:stable")
 		for _, addonRef := range addonNames {
 			// Parse addon reference format: "addonID" or "addonID:channel"
-			parts := strings.SplitN(addonRef, ":", 2)
-			addonID := parts[0]
-			channel := ""
-			if len(parts) == 2 {
-				channel = parts[1]
-			}
+			addonID, channel := types.ParseAddonRef(addonRef)
 
 			var addon *types.PostConfigAddon
 			var err error
@@ -1330,7 +1378,17 @@ func (h *PostConfigureHandler) handleOpenShiftPostConfigure(ctx context.Context,
 			log.Printf("Resolved addon: %s (version=%s, name=%s)", addonID, addon.Version, addon.Name)
 		}
 		if len(selectedAddons) > 0 {
-			var err error
+			// Pull in required addons and order each after its requirements
+			resolution, err := addon.Resolve(ctx, addon.NewStoreCatalog(h.store.PostConfigAddons, cluster.OwnerID),
+				selectedAddons, h.templateContext(cluster, "", nil))
+			if err != nil {
+				return fmt.Errorf("failed to resolve addon dependencies: %w", err)
+			}
+			for _, dep := range resolution.Added {
+				log.Printf("Adding addon %s (version=%s) required by %s", dep.AddonID, dep.Version, dep.RequiredBy)
+			}
+			selectedAddons = resolution.Addons
+
 			selectedAddonsConfig, err = h.mergeAddonConfigs(selectedAddons)
 			if err != nil {
 				return fmt.Errorf("failed to merge addon configs: %w", err)
@@ -1358,41 +1416,8 @@ func (h *PostConfigureHandler) handleOpenShiftPostConfigure(ctx context.Context,
 	}
 
 	// Start log streaming for job output visibility
-	logPath := filepath.Join(workDir, "post-configure.log")
-	logFile, err := os.Create(logPath)
-	if err != nil {
-		log.Printf("Warning: failed to create log file: %v", err)
-	} else {
-		defer logFile.Close()
-	}
-
-	// Create log writer that writes to both stdout and file
-	logWriter := func(format string, args ...interface{}) {
-		msg := fmt.Sprintf(format, args...)
-		log.Print(msg)
-		if logFile != nil {
-			fmt.Fprintln(logFile, msg)
-			logFile.Sync() // Flush to disk so LogStreamer can read it
-		}
-	}
-
-	// Start log streaming to database
-	streamer := NewLogStreamer(h.store, cluster.ID, job.ID, logPath)
-	streamCtx, streamCancel := context.WithCancel(ctx)
-	defer streamCancel()
-
-	if err := streamer.Start(streamCtx); err != nil {
-		logWriter("Warning: failed to start log streaming: %v", err)
-	}
-
-	// Ensure final logs are flushed when function returns
-	defer func() {
-		streamCancel()
-		time.Sleep(LogBatchFlushDelay) // Allow final batch to flush
-		if stopErr := streamer.Stop(); stopErr != nil {
-			log.Printf("Warning: error stopping log streamer: %v", stopErr)
-		}
-	}()
+	logWriter, closeLog := h.newJobLogWriter(ctx, job, "post-configure.log")
+	defer closeLog()
 
 	logWriter("Starting post-deployment configuration for OpenShift cluster %s", cluster.Name)
 
@@ -1453,41 +1478,10 @@ func (h *PostConfigureHandler) handleOpenShiftPostConfigure(ctx context.Context,
 			infraID = "" // Continue without infra ID
 		}
 
-		// Execute tasks in dependency order
-		for _, task := range dag.GetTasksByExecutionOrder() {
-			logWriter("[Addon DAG] Executing task: %s (type=%s, dependencies=%v)", task.Name, task.Type, task.Dependencies)
-
-			// Execute based on task type
-			switch task.Type {
-			case "operator":
-				op := task.Config.(types.CustomOperatorConfig)
-				if err := h.executeCustomOperatorWithFeatures(ctx, cluster, kubeconfigPath, op, infraID); err != nil {
-					_ = h.updatePostDeployStatus(ctx, cluster.ID, "failed")
-					return fmt.Errorf("install addon operator %s: %w", op.Name, err)
-				}
-			case "script":
-				script := task.Config.(types.CustomScriptConfig)
-				if err := h.executeCustomScriptWithFeatures(ctx, cluster, kubeconfigPath, script, infraID); err != nil {
-					_ = h.updatePostDeployStatus(ctx, cluster.ID, "failed")
-					return fmt.Errorf("execute addon script %s: %w", script.Name, err)
-				}
-			case "manifest":
-				manifest := task.Config.(types.CustomManifestConfig)
-				if err := h.executeCustomManifestWithFeatures(ctx, cluster, kubeconfigPath, manifest, infraID); err != nil {
-					_ = h.updatePostDeployStatus(ctx, cluster.ID, "failed")
-					return fmt.Errorf("apply addon manifest %s: %w", manifest.Name, err)
-				}
-			case "helmChart":
-				chart := task.Config.(types.CustomHelmChartConfig)
-				if err := h.executeCustomHelmChartWithFeatures(ctx, cluster, kubeconfigPath, chart, infraID); err != nil {
-					_ = h.updatePostDeployStatus(ctx, cluster.ID, "failed")
-					return fmt.Errorf("install addon helm chart %s: %w", chart.Name, err)
-				}
-			default:
-				return fmt.Errorf("unknown task type: %s", task.Type)
-			}
-
-			logWriter("[Addon DAG] Task %s completed successfully", task.Name)
+		// Execute tasks concurrently as their dependencies complete
+		if err := h.runPostConfigDAG(ctx, cluster, kubeconfigPath, infraID, dag, "addon", logWriter); err != nil {
+			_ = h.updatePostDeployStatus(ctx, cluster.ID, "failed")
+			return err
 		}
 	}
 
@@ -1511,41 +1505,10 @@ func (h *PostConfigureHandler) handleOpenShiftPostConfigure(ctx context.Context,
 			infraID = "" // Continue without infra ID
 		}
 
-		// Execute tasks in dependency order
-		for _, task := range dag.GetTasksByExecutionOrder() {
-			logWriter("[DAG] Executing task: %s (type=%s, dependencies=%v)", task.Name, task.Type, task.Dependencies)
-
-			// Execute based on task type
-			switch task.Type {
-			case "operator":
-				op := task.Config.(types.CustomOperatorConfig)
-				if err := h.executeCustomOperatorWithFeatures(ctx, cluster, kubeconfigPath, op, infraID); err != nil {
-					_ = h.updatePostDeployStatus(ctx, cluster.ID, "failed")
-					return fmt.Errorf("install custom operator %s: %w", op.Name, err)
-				}
-			case "script":
-				script := task.Config.(types.CustomScriptConfig)
-				if err := h.executeCustomScriptWithFeatures(ctx, cluster, kubeconfigPath, script, infraID); err != nil {
-					_ = h.updatePostDeployStatus(ctx, cluster.ID, "failed")
-					return fmt.Errorf("execute custom script %s: %w", script.Name, err)
-				}
-			case "manifest":
-				manifest := task.Config.(types.CustomManifestConfig)
-				if err := h.executeCustomManifestWithFeatures(ctx, cluster, kubeconfigPath, manifest, infraID); err != nil {
-					_ = h.updatePostDeployStatus(ctx, cluster.ID, "failed")
-					return fmt.Errorf("apply custom manifest %s: %w", manifest.Name, err)
-				}
-			case "helmChart":
-				chart := task.Config.(types.CustomHelmChartConfig)
-				if err := h.executeCustomHelmChartWithFeatures(ctx, cluster, kubeconfigPath, chart, infraID); err != nil {
-					_ = h.updatePostDeployStatus(ctx, cluster.ID, "failed")
-					return fmt.Errorf("install custom helm chart %s: %w", chart.Name, err)
-				}
-			default:
-				return fmt.Errorf("unknown task type: %s", task.Type)
-			}
-
-			logWriter("[DAG] Task %s completed successfully", task.Name)
+		// Execute tasks concurrently as their dependencies complete
+		if err := h.runPostConfigDAG(ctx, cluster, kubeconfigPath, infraID, dag, "custom", logWriter); err != nil {
+			_ = h.updatePostDeployStatus(ctx, cluster.ID, "failed")
+			return err
 		}
 	}
 
@@ -1558,6 +1521,99 @@ func (h *PostConfigureHandler) handleOpenShiftPostConfigure(ctx context.Context,
 	return nil
 }
 
+// runPostConfigDAG executes a post-config DAG, running tasks whose
+// dependencies have completed concurrently up to the worker's
+// PostConfigParallelism. A failed task causes its dependents to be skipped
+// while independent branches finish; skipped tasks are recorded as failed
+// configurations so they show up alongside the task that blocked them.
+// kind ("addon" or "custom") prefixes log lines and errors.
+func (h *PostConfigureHandler) runPostConfigDAG(ctx context.Context, cluster *types.Cluster, kubeconfigPath, infraID string,
+	dag *postconfig.ExecutionDAG, kind string, logWriter func(string, ...interface{})) error {
+	prefix := "[DAG]"
+	if kind == "addon" {
+		prefix = "[Addon DAG]"
+	}
+
+	run := func(ctx context.Context, task *postconfig.TaskNode) error {
+		logWriter("%s Executing task: %s (type=%s, dependencies=%v)", prefix, task.Name, task.Type, task.Dependencies)
+
+		var err error
+		switch task.Type {
+		case "operator":
+			op := task.Config.(types.CustomOperatorConfig)
+			if err = h.executeCustomOperatorWithFeatures(ctx, cluster, kubeconfigPath, op, infraID); err != nil {
+				err = fmt.Errorf("install %s operator %s: %w", kind, op.Name, err)
+			}
+		case "script":
+			script := task.Config.(types.CustomScriptConfig)
+			if err = h.executeCustomScriptWithFeatures(ctx, cluster, kubeconfigPath, script, infraID); err != nil {
+				err = fmt.Errorf("execute %s script %s: %w", kind, script.Name, err)
+			}
+		case "manifest":
+			manifest := task.Config.(types.CustomManifestConfig)
+			if err = h.executeCustomManifestWithFeatures(ctx, cluster, kubeconfigPath, manifest, infraID); err != nil {
+				err = fmt.Errorf("apply %s manifest %s: %w", kind, manifest.Name, err)
+			}
+		case "helmChart":
+			chart := task.Config.(types.CustomHelmChartConfig)
+			if err = h.executeCustomHelmChartWithFeatures(ctx, cluster, kubeconfigPath, chart, infraID); err != nil {
+				err = fmt.Errorf("install %s helm chart %s: %w", kind, chart.Name, err)
+			}
+		default:
+			err = fmt.Errorf("unknown task type: %s", task.Type)
+		}
+
+		if err != nil {
+			logWriter("%s Task %s failed: %v", prefix, task.Name, err)
+			return err
+		}
+		logWriter("%s Task %s completed successfully", prefix, task.Name)
+		return nil
+	}
+
+	parallelism := h.config.PostConfigParallelism
+	logWriter("%s Running %d task(s) with parallelism %d", prefix, len(dag.Nodes), parallelism)
+	results := dag.Execute(ctx, parallelism, run)
+
+	for _, res := range results {
+		switch res.Status {
+		case postconfig.TaskSkipped:
+			logWriter("%s Task %s skipped: %v", prefix, res.Task.Name, res.Err)
+			h.recordSkippedTask(ctx, cluster.ID, res)
+		default:
+			logWriter("%s Task %s %s in %s", prefix, res.Task.Name, res.Status, res.Duration.Round(time.Second))
+		}
+	}
+
+	return postconfig.ResultsError(results)
+}
+
+// dagTaskConfigTypes maps DAG task types to configuration task types
+var dagTaskConfigTypes = map[string]types.ConfigType{
+	"operator":  types.ConfigTypeOperator,
+	"script":    types.ConfigTypeScript,
+	"manifest":  types.ConfigTypeManifest,
+	"helmChart": types.ConfigTypeHelm,
+}
+
+// recordSkippedTask records a DAG task that never ran as a failed
+// configuration task, naming the dependency that blocked it
+func (h *PostConfigureHandler) recordSkippedTask(ctx context.Context, clusterID string, res postconfig.TaskResult) {
+	configType, ok := dagTaskConfigTypes[res.Task.Type]
+	if !ok {
+		return
+	}
+	configID, err := h.createConfigTask(ctx, clusterID, configType, res.Task.Name)
+	if err != nil {
+		log.Printf("Warning: failed to record skipped task %s: %v", res.Task.Name, err)
+		return
+	}
+	msg := fmt.Sprintf("skipped: %v", res.Err)
+	if err := h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &msg); err != nil {
+		log.Printf("Warning: failed to update skipped task %s: %v", res.Task.Name, err)
+	}
+}
+
 // ensureArtifactsAvailable downloads cluster artifacts from S3 if they don't exist locally
 func (h *PostConfigureHandler) ensureArtifactsAvailable(ctx context.Context, clusterID string) error {
 	workDir := filepath.Join(h.config.WorkDir, clusterID)
@@ -1636,6 +1692,12 @@ func (h *PostConfigureHandler) installOperator(ctx context.Context, cluster *typ
 		}
 	}
 
+	if err := h.runReadinessChecks(ctx, cluster, kubeconfigPath, op.Name, op.Namespace, op.Readiness); err != nil {
+		errMsg := err.Error()
+		_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errMsg)
+		return err
+	}
+
 	// Mark as completed
 	if err := h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusCompleted, nil); err != nil {
 		return fmt.Errorf("update config status: %w", err)
@@ -1845,8 +1907,10 @@ func (h *PostConfigureHandler) executeScript(ctx context.Context, cluster *types
 			continue
 		}
 
-		// Validate environment variable value to prevent command injection
-		if !isValidEnvVarValue(value) {
+		// Validate environment variable value to prevent command injection.
+		// Secret values come from the secrets backend, not the request, and
+		// are exempt.
+		if !isValidEnvVarValue(secretMaskFromContext(ctx).replace(value, "")) {
 			log.Printf("Warning: blocked environment variable with potentially dangerous value: %s (contains shell metacharacters)", key)
 			continue
 		}
@@ -1917,6 +1981,13 @@ func (h *PostConfigureHandler) executeScript(ctx context.Context, cluster *types
 	}
 
 	log.Printf("Script %s completed successfully", script.Name)
+
+	if err := h.runReadinessChecks(ctx, cluster, kubeconfigPath, script.Name, "", script.Readiness); err != nil {
+		errMsg := err.Error()
+		_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errMsg)
+		return err
+	}
+
 	_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusCompleted, nil)
 	return nil
 }
@@ -2014,6 +2085,12 @@ func (h *PostConfigureHandler) applyOpenShiftManifest(ctx context.Context, clust
 		return fmt.Errorf("apply manifest: %w", err)
 	}
 
+	if err := h.runReadinessChecks(ctx, cluster, kubeconfigPath, manifest.Name, manifest.Namespace, manifest.Readiness); err != nil {
+		errMsg := err.Error()
+		_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errMsg)
+		return err
+	}
+
 	_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusCompleted, nil)
 	return nil
 }
@@ -2030,38 +2107,71 @@ func (h *PostConfigureHandler) installHelmChart(ctx context.Context, cluster *ty
 
 	_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusInstalling, nil)
 
-	// 1. Add Helm repository
-	repoName := fmt.Sprintf("%s-repo", chart.Name)
-	log.Printf("Adding Helm repository: %s", repoName)
-
-	addRepoCmd := exec.CommandContext(ctx, "helm", "repo", "add", repoName, chart.Repo)
-	addRepoCmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+	if chart.Digest != "" && !isOCIChartRepo(chart.Repo) {
+		errorMsg := "digest pinning requires an oci:// chart repository"
+		_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errorMsg)
+		return fmt.Errorf("%s", errorMsg)
+	}
 
-	output, err := addRepoCmd.CombinedOutput()
-	if err != nil {
-		// Check if repo already exists (not a fatal error)
-		if !strings.Contains(string(output), "already exists") {
-			errorMsg := fmt.Sprintf("helm repo add failed: %v\nOutput: %s", err, string(output))
+	var installArgs []string
+	if isOCIChartRepo(chart.Repo) {
+		// 1. Pull the chart from the OCI registry, verifying its digest
+		chartDir, err := os.MkdirTemp("", fmt.Sprintf("helm-chart-%s-*", chart.Name))
+		if err != nil {
+			errorMsg := fmt.Sprintf("create chart directory: %v", err)
 			_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errorMsg)
 			return fmt.Errorf("%s", errorMsg)
 		}
-		log.Printf("Helm repository %s already exists, continuing...", repoName)
-	}
+		defer os.RemoveAll(chartDir)
 
-	// 2. Update Helm repositories
-	log.Printf("Updating Helm repositories...")
-	updateCmd := exec.CommandContext(ctx, "helm", "repo", "update")
-	updateCmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+		log.Printf("Pulling Helm chart %s from %s", chart.Chart, chart.Repo)
+		chartArchive, err := pullOCIChart(ctx, chartDir, chart)
+		if err != nil {
+			errorMsg := err.Error()
+			_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errorMsg)
+			return err
+		}
 
-	if output, err := updateCmd.CombinedOutput(); err != nil {
-		log.Printf("WARNING: helm repo update failed (non-fatal): %v\nOutput: %s", err, string(output))
-	}
+		// 2. Install the pulled chart archive
+		log.Printf("Installing Helm chart: %s", chartArchive)
+		installArgs = []string{"upgrade", "--install", chart.Name, chartArchive}
+	} else {
+		// 1. Add Helm repository
+		repoName := fmt.Sprintf("%s-repo", chart.Name)
+		log.Printf("Adding Helm repository: %s", repoName)
+
+		addRepoCmd := exec.CommandContext(ctx, "helm", "repo", "add", repoName, chart.Repo)
+		addRepoCmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
 
-	// 3. Install Helm chart
-	chartRef := fmt.Sprintf("%s/%s", repoName, chart.Chart)
-	log.Printf("Installing Helm chart: %s", chartRef)
+		output, err := addRepoCmd.CombinedOutput()
+		if err != nil {
+			// Check if repo already exists (not a fatal error)
+			if !strings.Contains(string(output), "already exists") {
+				errorMsg := fmt.Sprintf("helm repo add failed: %v\nOutput: %s", err, string(output))
+				_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errorMsg)
+				return fmt.Errorf("%s", errorMsg)
+			}
+			log.Printf("Helm repository %s already exists, continuing...", repoName)
+		}
+
+		// 2. Update Helm repositories
+		log.Printf("Updating Helm repositories...")
+		updateCmd := exec.CommandContext(ctx, "helm", "repo", "update")
+		updateCmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+
+		if output, err := updateCmd.CombinedOutput(); err != nil {
+			log.Printf("WARNING: helm repo update failed (non-fatal): %v\nOutput: %s", err, string(output))
+		}
+
+		// 3. Install Helm chart
+		chartRef := fmt.Sprintf("%s/%s", repoName, chart.Chart)
+		log.Printf("Installing Helm chart: %s", chartRef)
 
-	installArgs := []string{"install", chart.Name, chartRef}
+		installArgs = []string{"upgrade", "--install", chart.Name, chartRef}
+		if chart.Version != "" {
+			installArgs = append(installArgs, "--version", chart.Version)
+		}
+	}
 
 	// Add custom values if provided
 	if len(chart.Values) > 0 {
@@ -2093,11 +2203,12 @@ func (h *PostConfigureHandler) installHelmChart(ctx context.Context, cluster *ty
 		installArgs = append(installArgs, "-f", valuesFile.Name())
 	}
 
-	// Execute helm install
+	// Execute helm upgrade --install so re-applying a chart whose release
+	// already exists (e.g. when reconciling drift) updates it in place
 	installCmd := exec.CommandContext(ctx, "helm", installArgs...)
 	installCmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
 
-	output, err = installCmd.CombinedOutput()
+	output, err := installCmd.CombinedOutput()
 	if err != nil {
 		errorMsg := fmt.Sprintf("helm install failed: %v\nOutput: %s", err, string(output))
 		_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errorMsg)
@@ -2107,6 +2218,12 @@ func (h *PostConfigureHandler) installHelmChart(ctx context.Context, cluster *ty
 	log.Printf("Helm chart %s installed successfully", chart.Name)
 	log.Printf("Output: %s", strings.TrimSpace(string(output)))
 
+	if err := h.runReadinessChecks(ctx, cluster, kubeconfigPath, chart.Name, "", chart.Readiness); err != nil {
+		errMsg := err.Error()
+		_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusFailed, &errMsg)
+		return err
+	}
+
 	_ = h.updateConfigTaskStatus(ctx, configID, types.ConfigStatusCompleted, nil)
 	return nil
 }
@@ -2137,6 +2254,10 @@ func (h *PostConfigureHandler) createConfigTask(ctx context.Context, clusterID s
 
 // updateConfigTaskStatus updates a configuration task's status
 func (h *PostConfigureHandler) updateConfigTaskStatus(ctx context.Context, configID string, status types.ConfigStatus, errorMessage *string) error {
+	if errorMessage != nil {
+		masked := secretMaskFromContext(ctx).Mask(*errorMessage)
+		errorMessage = &masked
+	}
 	return h.store.ClusterConfigurations.UpdateStatus(ctx, configID, status, errorMessage)
 }
 
@@ -2257,46 +2378,7 @@ func validateSecureURL(urlStr string) error {
 		return fmt.Errorf("URL has no hostname")
 	}
 
-	// Check for localhost/loopback addresses
-	if hostname == "localhost" || hostname == "127.0.0.1" || hostname == "::1" {
-		return fmt.Errorf("localhost URLs are not allowed")
-	}
-
-	// Resolve DNS to get IP addresses
-	ips, err := net.LookupHost(hostname)
-	if err != nil {
-		return fmt.Errorf("failed to resolve hostname %s: %w", hostname, err)
-	}
-
-	// Check each resolved IP
-	for _, ipStr := range ips {
-		ip := net.ParseIP(ipStr)
-		if ip == nil {
-			continue
-		}
-
-		// Block private IP ranges (10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16)
-		if ip.IsPrivate() {
-			return fmt.Errorf("private IP addresses are not allowed: %s", ipStr)
-		}
-
-		// Block loopback addresses
-		if ip.IsLoopback() {
-			return fmt.Errorf("loopback addresses are not allowed: %s", ipStr)
-		}
-
-		// Block link-local addresses (169.254.0.0/16 for IPv4, fe80::/10 for IPv6)
-		if ip.IsLinkLocalUnicast() {
-			return fmt.Errorf("link-local addresses are not allowed: %s", ipStr)
-		}
-
-		// Block AWS metadata endpoint specifically (169.254.169.254)
-		if ipStr == "169.254.169.254" {
-			return fmt.Errorf("AWS metadata endpoint is blocked")
-		}
-	}
-
-	return nil
+	return validation.ValidateSecureHost(hostname)
 }
 
 // Custom post-config handlers with user tracking
@@ -2311,6 +2393,7 @@ func (h *PostConfigureHandler) installCustomOperator(ctx context.Context, cluste
 		Namespace: customOp.Namespace,
 		Source:    customOp.Source,
 		Channel:   customOp.Channel,
+		Readiness: customOp.Readiness,
 	}
 
 	// Convert CustomResource if provided
@@ -2428,6 +2511,7 @@ func (h *PostConfigureHandler) executeCustomScript(ctx context.Context, cluster
 		Path:        scriptPath,
 		Description: customScript.Description,
 		Env:         customScript.Env,
+		Readiness:   customScript.Readiness,
 	}
 
 	// Call existing executeScript method (creates its own tracking)
@@ -2457,8 +2541,25 @@ func (h *PostConfigureHandler) applyCustomManifest(ctx context.Context, cluster
 	} else if customManifest.URL != "" {
 		// URL-based manifest - will be handled by applyOpenShiftManifest
 		manifestPath = customManifest.URL
+	} else if customManifest.Git != nil {
+		// Git source - check out, build with kustomize if requested, and write
+		// the result to the work dir like inline content
+		content, _, err := h.fetchGitManifest(ctx, cluster, customManifest)
+		if err != nil {
+			return fmt.Errorf("read manifest from git: %w", err)
+		}
+
+		manifestsDir := filepath.Join(workDir, "custom-manifests")
+		if err := os.MkdirAll(manifestsDir, 0700); err != nil {
+			return fmt.Errorf("create manifests dir: %w", err)
+		}
+
+		manifestPath = filepath.Join(manifestsDir, customManifest.Name+".yaml")
+		if err := os.WriteFile(manifestPath, []byte(content), 0600); err != nil {
+			return fmt.Errorf("write manifest file: %w", err)
+		}
 	} else {
-		return fmt.Errorf("manifest must have either content or url")
+		return fmt.Errorf("manifest must have either content, url or git")
 	}
 
 	// Convert to profile ManifestConfig
@@ -2466,6 +2567,7 @@ func (h *PostConfigureHandler) applyCustomManifest(ctx context.Context, cluster
 		Name:        customManifest.Name,
 		Description: customManifest.Description,
 		Namespace:   customManifest.Namespace,
+		Readiness:   customManifest.Readiness,
 	}
 
 	// Set path or URL based on which was provided
@@ -2484,12 +2586,21 @@ func (h *PostConfigureHandler) applyCustomManifest(ctx context.Context, cluster
 func (h *PostConfigureHandler) installCustomHelmChart(ctx context.Context, cluster *types.Cluster, kubeconfigPath string, customChart types.CustomHelmChartConfig) error {
 	log.Printf("[CUSTOM POST-CONFIG] Installing custom Helm chart: %s from repo %s (user-defined, owner: %s)", customChart.Name, customChart.Repo, cluster.OwnerID)
 
+	// Validate repository to prevent SSRF attacks
+	if err := validateSecureChartRepo(customChart.Repo); err != nil {
+		return fmt.Errorf("invalid chart repo: %w", err)
+	}
+
 	// Convert to profile HelmChartConfig
 	profileChart := profile.HelmChartConfig{
-		Name:   customChart.Name,
-		Repo:   customChart.Repo,
-		Chart:  customChart.Chart,
-		Values: customChart.Values,
+		Name:    customChart.Name,
+		Repo:    customChart.Repo,
+		Chart:   customChart.Chart,
+		Version: customChart.Version,
+		Digest:  customChart.Digest,
+		Values:  customChart.Values,
+		// Readiness checks that name no namespace default to the chart's namespace
+		Readiness: readinessInNamespace(customChart.Readiness, customChart.Namespace),
 	}
 
 	// Call existing installHelmChart method
@@ -2499,10 +2610,28 @@ func (h *PostConfigureHandler) installCustomHelmChart(ctx context.Context, clust
 
 // Phase 4: Advanced execution methods with template rendering, conditional execution, and variable support
 
+// templateContext builds the template and condition context for a task,
+// adding the worker count and feature flags of the cluster's profile. Profile
+// lookup failures are logged and leave those facts unset.
+func (h *PostConfigureHandler) templateContext(cluster *types.Cluster, infraID string, customVars map[string]string) *postconfig.TemplateContext {
+	templateCtx := postconfig.BuildTemplateContext(cluster, infraID, customVars)
+
+	prof, err := h.registry.Get(cluster.Profile)
+	if err != nil {
+		log.Printf("Warning: profile %s unavailable for condition context: %v", cluster.Profile, err)
+		return templateCtx
+	}
+	if effective, err := profile.ApplyOverrides(prof, cluster.ComputeOverrides); err == nil {
+		prof = effective
+	}
+	templateCtx.ApplyProfile(prof)
+	return templateCtx
+}
+
 // executeCustomOperatorWithFeatures installs an operator with conditional execution support
 func (h *PostConfigureHandler) executeCustomOperatorWithFeatures(ctx context.Context, cluster *types.Cluster, kubeconfigPath string, op types.CustomOperatorConfig, infraID string) error {
 	// Build template context
-	templateCtx := postconfig.BuildTemplateContext(cluster, infraID, nil)
+	templateCtx := h.templateContext(cluster, infraID, nil)
 
 	// Evaluate condition
 	if op.Condition != "" {
@@ -2524,7 +2653,7 @@ func (h *PostConfigureHandler) executeCustomOperatorWithFeatures(ctx context.Con
 // executeCustomScriptWithFeatures executes a script with template rendering, variables, and conditional execution
 func (h *PostConfigureHandler) executeCustomScriptWithFeatures(ctx context.Context, cluster *types.Cluster, kubeconfigPath string, script types.CustomScriptConfig, infraID string) error {
 	// Build template context with custom variables
-	templateCtx := postconfig.BuildTemplateContext(cluster, infraID, script.Variables)
+	templateCtx := h.templateContext(cluster, infraID, script.Variables)
 
 	// Evaluate condition
 	if script.Condition != "" {
@@ -2539,10 +2668,16 @@ func (h *PostConfigureHandler) executeCustomScriptWithFeatures(ctx context.Conte
 		log.Printf("[CONDITIONAL] Script %s condition met: %s", script.Name, script.Condition)
 	}
 
+	// Secret references are only resolved once the script is known to run
+	renderCtx, err := h.renderContext(ctx, cluster, infraID, script.Variables)
+	if err != nil {
+		return err
+	}
+
 	// Render template for script content
 	renderedContent := script.Content
 	if script.Content != "" {
-		rendered, err := postconfig.RenderTemplate(script.Content, templateCtx)
+		rendered, err := postconfig.RenderTemplate(script.Content, renderCtx)
 		if err != nil {
 			return fmt.Errorf("render script content: %w", err)
 		}
@@ -2551,7 +2686,7 @@ func (h *PostConfigureHandler) executeCustomScriptWithFeatures(ctx context.Conte
 	}
 
 	// Render environment variables
-	renderedEnv, err := postconfig.RenderMapValues(script.Env, templateCtx)
+	renderedEnv, err := postconfig.RenderMapValues(script.Env, renderCtx)
 	if err != nil {
 		return fmt.Errorf("render environment variables: %w", err)
 	}
@@ -2568,7 +2703,7 @@ func (h *PostConfigureHandler) executeCustomScriptWithFeatures(ctx context.Conte
 // executeCustomManifestWithFeatures applies a manifest with template rendering and conditional execution
 func (h *PostConfigureHandler) executeCustomManifestWithFeatures(ctx context.Context, cluster *types.Cluster, kubeconfigPath string, manifest types.CustomManifestConfig, infraID string) error {
 	// Build template context with custom variables
-	templateCtx := postconfig.BuildTemplateContext(cluster, infraID, manifest.Variables)
+	templateCtx := h.templateContext(cluster, infraID, manifest.Variables)
 
 	// Evaluate condition
 	if manifest.Condition != "" {
@@ -2583,10 +2718,16 @@ func (h *PostConfigureHandler) executeCustomManifestWithFeatures(ctx context.Con
 		log.Printf("[CONDITIONAL] Manifest %s condition met: %s", manifest.Name, manifest.Condition)
 	}
 
+	// Secret references are only resolved once the manifest is known to apply
+	renderCtx, err := h.renderContext(ctx, cluster, infraID, manifest.Variables)
+	if err != nil {
+		return err
+	}
+
 	// Render template for manifest content
 	renderedContent := manifest.Content
 	if manifest.Content != "" {
-		rendered, err := postconfig.RenderTemplate(manifest.Content, templateCtx)
+		rendered, err := postconfig.RenderTemplate(manifest.Content, renderCtx)
 		if err != nil {
 			return fmt.Errorf("render manifest content: %w", err)
 		}
@@ -2597,7 +2738,7 @@ func (h *PostConfigureHandler) executeCustomManifestWithFeatures(ctx context.Con
 	// Render namespace
 	renderedNamespace := manifest.Namespace
 	if manifest.Namespace != "" {
-		rendered, err := postconfig.RenderTemplate(manifest.Namespace, templateCtx)
+		rendered, err := postconfig.RenderTemplate(manifest.Namespace, renderCtx)
 		if err != nil {
 			return fmt.Errorf("render namespace: %w", err)
 		}
@@ -2616,7 +2757,7 @@ func (h *PostConfigureHandler) executeCustomManifestWithFeatures(ctx context.Con
 // executeCustomHelmChartWithFeatures installs a Helm chart with template rendering and conditional execution
 func (h *PostConfigureHandler) executeCustomHelmChartWithFeatures(ctx context.Context, cluster *types.Cluster, kubeconfigPath string, chart types.CustomHelmChartConfig, infraID string) error {
 	// Build template context with custom variables
-	templateCtx := postconfig.BuildTemplateContext(cluster, infraID, chart.Variables)
+	templateCtx := h.templateContext(cluster, infraID, chart.Variables)
 
 	// Evaluate condition
 	if chart.Condition != "" {
@@ -2631,10 +2772,16 @@ func (h *PostConfigureHandler) executeCustomHelmChartWithFeatures(ctx context.Co
 		log.Printf("[CONDITIONAL] Helm chart %s condition met: %s", chart.Name, chart.Condition)
 	}
 
+	// Secret references are only resolved once the chart is known to install
+	renderCtx, err := h.renderContext(ctx, cluster, infraID, chart.Variables)
+	if err != nil {
+		return err
+	}
+
 	// Render namespace
 	renderedNamespace := chart.Namespace
 	if chart.Namespace != "" {
-		rendered, err := postconfig.RenderTemplate(chart.Namespace, templateCtx)
+		rendered, err := postconfig.RenderTemplate(chart.Namespace, renderCtx)
 		if err != nil {
 			return fmt.Errorf("render namespace: %w", err)
 		}
@@ -2645,7 +2792,7 @@ func (h *PostConfigureHandler) executeCustomHelmChartWithFeatures(ctx context.Co
 	renderedValues := make(map[string]interface{})
 	for k, v := range chart.Values {
 		if strVal, ok := v.(string); ok {
-			rendered, err := postconfig.RenderTemplate(strVal, templateCtx)
+			rendered, err := postconfig.RenderTemplate(strVal, renderCtx)
 			if err != nil {
 				return fmt.Errorf("render helm value %s: %w", k, err)
 			}
@@ -2777,12 +2924,7 @@ func (h *PostConfigureHandler) resolveSelectedAddons(ctx context.Context, cluste
 	addons := make([]types.PostConfigAddon, 0, len(cluster.SelectedAddonIDs))
 	for _, addonRef := range cluster.SelectedAddonIDs {
 		// Parse addon reference format: "addonID" or "addonID:channel"
-		parts := strings.SplitN(addonRef, ":", 2)
-		addonID := parts[0]
-		channel := ""
-		if len(parts) == 2 {
-			channel = parts[1]
-		}
+		addonID, channel := types.ParseAddonRef(addonRef)
 
 		var addon *types.PostConfigAddon
 		var err error
@@ -2835,7 +2977,9 @@ func (h *PostConfigureHandler) validateAddonConflicts(addons []types.PostConfigA
 	return nil
 }
 
-// mergeAddonConfigs combines multiple addon configs into a single CustomPostConfig
+// mergeAddonConfigs combines multiple addon configs into a single CustomPostConfig.
+// Every task of an addon is made to depend on every task of the addons it
+// requires, so the DAG runs requirements first.
 func (h *PostConfigureHandler) mergeAddonConfigs(addons []types.PostConfigAddon) (*types.CustomPostConfig, error) {
 	if len(addons) == 0 {
 		return nil, nil
@@ -2853,16 +2997,64 @@ func (h *PostConfigureHandler) mergeAddonConfigs(addons []types.PostConfigAddon)
 		HelmCharts: []types.CustomHelmChartConfig{},
 	}
 
+	tasksByAddon := make(map[string][]string, len(addons))
 	for _, addon := range addons {
+		tasksByAddon[addon.AddonID] = configTaskNames(&addon.Config)
+	}
+
+	for _, addon := range addons {
+		var required []string
+		if addon.Metadata != nil {
+			for _, req := range addon.Metadata.Requires {
+				required = append(required, tasksByAddon[req.ID]...)
+			}
+		}
+		withRequired := func(deps []string) []string {
+			if len(required) == 0 {
+				return deps
+			}
+			return append(append([]string{}, deps...), required...)
+		}
+
 		// Merge operators
-		merged.Operators = append(merged.Operators, addon.Config.Operators...)
+		for _, op := range addon.Config.Operators {
+			op.DependsOn = withRequired(op.DependsOn)
+			merged.Operators = append(merged.Operators, op)
+		}
 		// Merge scripts
-		merged.Scripts = append(merged.Scripts, addon.Config.Scripts...)
+		for _, script := range addon.Config.Scripts {
+			script.DependsOn = withRequired(script.DependsOn)
+			merged.Scripts = append(merged.Scripts, script)
+		}
 		// Merge manifests
-		merged.Manifests = append(merged.Manifests, addon.Config.Manifests...)
+		for _, manifest := range addon.Config.Manifests {
+			manifest.DependsOn = withRequired(manifest.DependsOn)
+			merged.Manifests = append(merged.Manifests, manifest)
+		}
 		// Merge helm charts
-		merged.HelmCharts = append(merged.HelmCharts, addon.Config.HelmCharts...)
+		for _, chart := range addon.Config.HelmCharts {
+			chart.DependsOn = withRequired(chart.DependsOn)
+			merged.HelmCharts = append(merged.HelmCharts, chart)
+		}
 	}
 
 	return merged, nil
 }
+
+// configTaskNames returns the names of every task in a post-config
+func configTaskNames(cfg *types.CustomPostConfig) []string {
+	var names []string
+	for _, op := range cfg.Operators {
+		names = append(names, op.Name)
+	}
+	for _, script := range cfg.Scripts {
+		names = append(names, script.Name)
+	}
+	for _, manifest := range cfg.Manifests {
+		names = append(names, manifest.Name)
+	}
+	for _, chart := range cfg.HelmCharts {
+		names = append(names, chart.Name)
+	}
+	return names
+}
@@ -20,41 +20,26 @@ import (
 	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing/types"
 	"github.com/tsanders-rh/ocpctl/internal/installer"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
 	"github.com/tsanders-rh/ocpctl/internal/store"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 // ResumeHandler handles cluster resume jobs
 type ResumeHandler struct {
-	config   *Config
-	store    *store.Store
-	registry *profile.Registry
+	config    *Config
+	store     *store.Store
+	registry  *profile.Registry
+	providers *provider.Registry
 }
 
 // NewResumeHandler creates a new resume handler
-func NewResumeHandler(cfg *Config, st *store.Store) *ResumeHandler {
-	// Load profile registry
-	profilesDir := os.Getenv("PROFILES_DIR")
-	if profilesDir == "" {
-		profilesDir = "internal/profile/definitions"
-	}
-
-	loader := profile.NewLoader(profilesDir)
-	registry, err := profile.NewRegistry(loader)
-	if err != nil {
-		log.Fatalf("Failed to load profile registry: %v", err)
-	}
-
-	return &ResumeHandler{
-		config:   cfg,
-		store:    st,
-		registry: registry,
-	}
+func NewResumeHandler(cfg *Config, st *store.Store, registry *profile.Registry) *ResumeHandler {
+	return newClusterHandlers(cfg, st, registry).resume
 }
 
 // Handle handles a cluster resume job by starting stopped instances or scaling node groups back up.
-// Routes to the appropriate platform-specific resume handler based on cluster type.
-// Supports OpenShift (AWS), EKS, and IKS cluster types.
+// Routes to the provider for the cluster type, which must support hibernation.
 func (h *ResumeHandler) Handle(ctx context.Context, job *types.Job) error {
 	// Get cluster details
 	cluster, err := h.store.Clusters.GetByID(ctx, job.ClusterID)
@@ -64,25 +49,15 @@ func (h *ResumeHandler) Handle(ctx context.Context, job *types.Job) error {
 
 	log.Printf("Resuming cluster %s (platform=%s, cluster_type=%s)", cluster.Name, cluster.Platform, cluster.ClusterType)
 
-	// Route by cluster type
-	switch cluster.ClusterType {
-	case types.ClusterTypeOpenShift:
-		return h.resumeOpenShift(ctx, cluster, job)
-	case types.ClusterTypeROSA:
-		return h.resumeROSA(ctx, cluster, job)
-	case types.ClusterTypeEKS:
-		return h.resumeEKS(ctx, cluster, job)
-	case types.ClusterTypeIKS:
-		return h.resumeIKS(ctx, cluster, job)
-	case types.ClusterTypeGKE:
-		return h.resumeGKE(ctx, cluster, job)
-	case types.ClusterTypeARO:
-		return h.resumeARO(ctx, cluster, job)
-	case types.ClusterTypeAKS:
-		return h.resumeAKS(ctx, cluster, job)
-	default:
-		return fmt.Errorf("unsupported cluster type for resume: %s", cluster.ClusterType)
+	p, err := h.providers.Get(cluster.ClusterType)
+	if err != nil {
+		return fmt.Errorf("unsupported cluster type for resume: %w", err)
 	}
+	if caps := p.Capabilities(); !caps.Hibernate {
+		return fmt.Errorf("resume is not supported for %s clusters", caps.DisplayName)
+	}
+
+	return p.Resume(ctx, job, cluster)
 }
 
 // resumeOpenShift resumes an OpenShift cluster (AWS, IBMCloud, or GCP)
@@ -260,7 +235,7 @@ func (h *ResumeHandler) resumeAWS(ctx context.Context, cluster *types.Cluster, j
 // getInfraID extracts the infrastructure ID from metadata.json
 // Reuses the same implementation as HibernateHandler
 func (h *ResumeHandler) getInfraID(cluster *types.Cluster) (string, error) {
-	hibernateHandler := NewHibernateHandler(h.config, h.store)
+	hibernateHandler := NewHibernateHandler(h.config, h.store, h.registry)
 	return hibernateHandler.getInfraID(cluster)
 }
 
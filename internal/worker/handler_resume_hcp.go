@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// resumeHCP resumes a hosted cluster by restoring its NodePool replicas from
+// the most recent successful hibernate job
+func (h *ResumeHandler) resumeHCP(ctx context.Context, cluster *types.Cluster, job *types.Job) error {
+	log.Printf("Resuming HCP cluster %s by restoring node pool replicas", cluster.Name)
+
+	// Update cluster status to RESUMING
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusResuming); err != nil {
+		return fmt.Errorf("update cluster status to RESUMING: %w", err)
+	}
+
+	// Get the most recent successful hibernate job to retrieve original replicas
+	hibernateJobs, err := h.store.Jobs.GetByClusterIDAndType(ctx, cluster.ID, types.JobTypeHibernate)
+	if err != nil {
+		return fmt.Errorf("get hibernate jobs: %w", err)
+	}
+	var hibernateJob *types.Job
+	for i := len(hibernateJobs) - 1; i >= 0; i-- {
+		if hibernateJobs[i].Status == types.JobStatusSucceeded {
+			hibernateJob = hibernateJobs[i]
+			break
+		}
+	}
+	if hibernateJob == nil || hibernateJob.Metadata == nil {
+		return fmt.Errorf("no successful hibernate job with node pool replicas found")
+	}
+
+	replicasJSON, ok := hibernateJob.Metadata["hcp_nodepool_replicas"].(string)
+	if !ok {
+		return fmt.Errorf("hibernate job metadata missing hcp_nodepool_replicas")
+	}
+	var replicas map[string]int
+	if err := json.Unmarshal([]byte(replicasJSON), &replicas); err != nil {
+		return fmt.Errorf("parse node pool replicas: %w", err)
+	}
+
+	hcpInstaller, err := hcpInstallerFor(h.registry, cluster)
+	if err != nil {
+		return err
+	}
+	if err := hcpInstaller.Resume(ctx, replicas); err != nil {
+		return fmt.Errorf("restore node pool replicas: %w", err)
+	}
+
+	// Update cluster status to READY
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusReady); err != nil {
+		return fmt.Errorf("update cluster status to READY: %w", err)
+	}
+
+	log.Printf("HCP cluster %s resumed successfully (%d node pools restored)", cluster.Name, len(replicas))
+	return nil
+}
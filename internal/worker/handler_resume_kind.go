@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// resumeKind resumes a kind cluster by starting its node containers and
+// waiting for the API server to answer again
+func (h *ResumeHandler) resumeKind(ctx context.Context, cluster *types.Cluster, job *types.Job) error {
+	log.Printf("Resuming kind cluster %s by starting node containers", cluster.Name)
+
+	// Update cluster status to RESUMING
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusResuming); err != nil {
+		return fmt.Errorf("update cluster status to RESUMING: %w", err)
+	}
+
+	if err := installer.NewKindInstaller().StartNodes(ctx, cluster.Name); err != nil {
+		return fmt.Errorf("start node containers: %w", err)
+	}
+
+	kubeconfigPath := filepath.Join(h.config.WorkDir, cluster.ID, "auth", "kubeconfig")
+	if err := h.waitForAPIServer(ctx, kubeconfigPath); err != nil {
+		return fmt.Errorf("wait for API server: %w", err)
+	}
+
+	// Update cluster status to READY
+	if err := h.store.Clusters.UpdateStatus(ctx, nil, cluster.ID, types.ClusterStatusReady); err != nil {
+		return fmt.Errorf("update cluster status to READY: %w", err)
+	}
+
+	log.Printf("kind cluster %s resumed successfully", cluster.Name)
+	return nil
+}
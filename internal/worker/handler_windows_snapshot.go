@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
 	"github.com/tsanders-rh/ocpctl/internal/store"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
@@ -24,11 +25,11 @@ type WindowsSnapshotHandler struct {
 }
 
 // NewWindowsSnapshotHandler creates a new Windows snapshot handler
-func NewWindowsSnapshotHandler(config *Config, st *store.Store) *WindowsSnapshotHandler {
+func NewWindowsSnapshotHandler(config *Config, st *store.Store, registry *profile.Registry) *WindowsSnapshotHandler {
 	return &WindowsSnapshotHandler{
 		config:         config,
 		store:          st,
-		destroyHandler: NewDestroyHandler(config, st),
+		destroyHandler: NewDestroyHandler(config, st, registry),
 	}
 }
 
@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/dbtest"
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// TestKindClusterLifecycle drives a kind cluster through create, post-configure,
+// hibernate, resume and destroy using the real job handlers and dbtest's
+// Postgres. It needs kind, a container runtime, oc and kubectl on the host, so
+// it only runs when OCPCTL_E2E_KIND is set.
+func TestKindClusterLifecycle(t *testing.T) {
+	if os.Getenv("OCPCTL_E2E_KIND") == "" {
+		t.Skip("OCPCTL_E2E_KIND not set; skipping kind end-to-end test")
+	}
+	for _, bin := range []string{"kind", "oc", "kubectl"} {
+		if _, err := exec.LookPath(bin); err != nil {
+			t.Skipf("%s not found on PATH; skipping kind end-to-end test", bin)
+		}
+	}
+
+	s := dbtest.New(t)
+	ctx := context.Background()
+
+	registry, err := profile.NewRegistry(profile.NewLoader("../profile/definitions"))
+	require.NoError(t, err)
+	cfg := &Config{WorkDir: t.TempDir()}
+
+	cluster := &types.Cluster{
+		ID:          uuid.New().String(),
+		Name:        "e2e-" + uuid.New().String()[:8],
+		Platform:    types.PlatformLocal,
+		ClusterType: types.ClusterTypeKind,
+		Version:     "1.33.1",
+		Profile:     "kind-local",
+		Region:      "local",
+		Owner:       "owner@example.com",
+		Team:        "test",
+		CostCenter:  "test",
+		Status:      types.ClusterStatusPending,
+		RequestedBy: "owner@example.com",
+		TTLHours:    1,
+		CustomPostConfig: &types.CustomPostConfig{
+			Manifests: []types.CustomManifestConfig{{
+				Name: "e2e-marker",
+				Content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: e2e-marker
+  namespace: default
+data:
+  cluster: "{{.ClusterName}}"
+`,
+			}},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, s.Clusters.Create(ctx, cluster))
+	t.Cleanup(func() {
+		_, _ = installer.NewKindInstaller().DeleteCluster(context.Background(), cluster.Name)
+	})
+
+	run := func(jobType types.JobType, handle func(context.Context, *types.Job) error) {
+		t.Helper()
+		job := &types.Job{
+			ID:          uuid.New().String(),
+			ClusterID:   cluster.ID,
+			JobType:     jobType,
+			Status:      types.JobStatusRunning,
+			Attempt:     1,
+			MaxAttempts: 1,
+		}
+		require.NoError(t, s.Jobs.Create(ctx, nil, job))
+		require.NoError(t, handle(ctx, job), "%s job", jobType)
+		require.NoError(t, s.Jobs.MarkSucceeded(ctx, job.ID, job.Metadata))
+	}
+	requireStatus := func(want types.ClusterStatus) {
+		t.Helper()
+		got, err := s.Clusters.GetByID(ctx, cluster.ID)
+		require.NoError(t, err)
+		require.Equal(t, want, got.Status)
+	}
+
+	// Create produces a reachable cluster with a real kubeconfig in the outputs
+	run(types.JobTypeCreate, NewCreateHandler(cfg, s, registry).Handle)
+	requireStatus(types.ClusterStatusReady)
+
+	outputs, err := s.ClusterOutputs.GetByClusterID(ctx, cluster.ID)
+	require.NoError(t, err)
+	require.NotNil(t, outputs.KubeconfigS3URI)
+	kubeconfigPath := strings.TrimPrefix(*outputs.KubeconfigS3URI, "file://")
+	require.Equal(t, filepath.Join(cfg.WorkDir, cluster.ID, "auth", "kubeconfig"), kubeconfigPath)
+	require.NotNil(t, outputs.APIURL)
+	require.True(t, strings.HasPrefix(*outputs.APIURL, "https://"), *outputs.APIURL)
+
+	// Create queued a post-configure job for the custom manifest
+	postJobs, err := s.Jobs.GetByClusterIDAndType(ctx, cluster.ID, types.JobTypePostConfigure)
+	require.NoError(t, err)
+	require.Len(t, postJobs, 1)
+	require.NoError(t, NewPostConfigureHandler(cfg, s, registry).Handle(ctx, postJobs[0]))
+
+	out, err := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeconfigPath,
+		"get", "configmap", "e2e-marker", "-n", "default", "-o", "jsonpath={.data.cluster}").Output()
+	require.NoError(t, err)
+	require.Equal(t, cluster.Name, string(out))
+
+	run(types.JobTypeHibernate, NewHibernateHandler(cfg, s, registry).Handle)
+	requireStatus(types.ClusterStatusHibernated)
+
+	run(types.JobTypeResume, NewResumeHandler(cfg, s, registry).Handle)
+	requireStatus(types.ClusterStatusReady)
+
+	run(types.JobTypeDestroy, NewDestroyHandler(cfg, s, registry).Handle)
+	requireStatus(types.ClusterStatusDestroyed)
+
+	exists, err := installer.NewKindInstaller().ClusterExists(ctx, cluster.Name)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
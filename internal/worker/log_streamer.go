@@ -27,6 +27,9 @@ type LogStreamer struct {
 	batchSize     int
 	flushInterval time.Duration
 
+	// mask removes the job's secret values from lines before they are stored
+	mask *secretMask
+
 	// Regular expression for parsing openshift-install log format
 	// Example: time="2024-03-01T10:30:00Z" level=info msg="Creating cluster..."
 	logRegex *regexp.Regexp
@@ -53,7 +56,9 @@ func NewLogStreamer(store *store.Store, clusterID, jobID, logPath string) *LogSt
 // Start begins tailing the log file and streaming entries to the database.
 // This is non-blocking and runs in a goroutine. The streamer will wait up to 30 seconds for the log file to be created.
 // Call Stop() to gracefully shutdown the streamer and flush remaining log entries.
+// Secret values resolved by the job in ctx are masked in the stored entries.
 func (ls *LogStreamer) Start(ctx context.Context) error {
+	ls.mask = secretMaskFromContext(ctx)
 	ls.wg.Add(1)
 	go ls.tailLogFile(ctx)
 	return nil
@@ -124,7 +129,7 @@ func (ls *LogStreamer) tailLogFile(ctx context.Context) {
 					Sequence:  ls.sequence,
 					Timestamp: time.Now(),
 					LogLevel:  level,
-					Message:   line,
+					Message:   ls.mask.Mask(line),
 					Source:    types.DeploymentLogSourceInstaller,
 				}
 
@@ -169,7 +174,7 @@ func (ls *LogStreamer) tailLogFile(ctx context.Context) {
 					Sequence:  ls.sequence,
 					Timestamp: time.Now(),
 					LogLevel:  level,
-					Message:   line,
+					Message:   ls.mask.Mask(line),
 					Source:    types.DeploymentLogSourceInstaller,
 				}
 
@@ -214,7 +219,7 @@ func (ls *LogStreamer) tailLogFile(ctx context.Context) {
 				Sequence:  ls.sequence,
 				Timestamp: time.Now(),
 				LogLevel:  level,
-				Message:   line,
+				Message:   ls.mask.Mask(line),
 				Source:    types.DeploymentLogSourceInstaller,
 			}
 
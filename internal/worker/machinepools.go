@@ -0,0 +1,180 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// clusterProfile returns the cluster's profile with its compute overrides
+// applied, so every create path installs the shape that was requested
+func (h *CreateHandler) clusterProfile(cluster *types.Cluster) (*profile.Profile, error) {
+	prof, err := h.registry.Get(cluster.Profile)
+	if err != nil {
+		return nil, err
+	}
+	shaped, err := profile.ApplyOverrides(prof, cluster.ComputeOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("apply compute overrides: %w", err)
+	}
+	return shaped, nil
+}
+
+// createAdditionalMachinePools adds the extra worker pools requested through
+// compute overrides once the cluster is up. OpenShift and ARO get a MachineSet
+// cloned from the first worker MachineSet; ROSA gets a rosa machine pool.
+// Failures are returned so the caller can decide whether they are fatal.
+func (h *CreateHandler) createAdditionalMachinePools(ctx context.Context, cluster *types.Cluster, prof *profile.Profile, workDir string) error {
+	if len(prof.AdditionalMachinePools) == 0 {
+		return nil
+	}
+
+	log.Printf("Creating %d additional machine pool(s) for cluster %s", len(prof.AdditionalMachinePools), cluster.Name)
+
+	if prof.EffectiveClusterType() == types.ClusterTypeROSA {
+		rosaInstaller := installer.NewROSAInstaller()
+		for _, pool := range prof.AdditionalMachinePools {
+			if err := rosaInstaller.CreateMachinePool(ctx, cluster.Name, pool.Name, pool.InstanceType, pool.Replicas, nil); err != nil {
+				return fmt.Errorf("create ROSA machine pool %s: %w", pool.Name, err)
+			}
+			log.Printf("Created ROSA machine pool %s (%d x %s)", pool.Name, pool.Replicas, pool.InstanceType)
+		}
+		return nil
+	}
+
+	kubeconfigPath := filepath.Join(workDir, "auth", "kubeconfig")
+	template, err := workerMachineSetTemplate(ctx, kubeconfigPath)
+	if err != nil {
+		return err
+	}
+
+	for _, pool := range prof.AdditionalMachinePools {
+		ms, err := machineSetForPool(template, pool, cluster.Platform)
+		if err != nil {
+			return fmt.Errorf("build MachineSet for pool %s: %w", pool.Name, err)
+		}
+
+		cmd := exec.CommandContext(ctx, "oc", "--kubeconfig", kubeconfigPath, "apply", "-f", "-")
+		cmd.Stdin = bytes.NewReader(ms)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("apply MachineSet for pool %s: %w\nOutput: %s", pool.Name, err, string(output))
+		}
+		log.Printf("Created MachineSet for pool %s (%d x %s)", pool.Name, pool.Replicas, pool.InstanceType)
+	}
+
+	return nil
+}
+
+// workerMachineSetTemplate returns the first worker MachineSet of the cluster
+func workerMachineSetTemplate(ctx context.Context, kubeconfigPath string) (map[string]interface{}, error) {
+	cmd := exec.CommandContext(ctx, "oc", "--kubeconfig", kubeconfigPath,
+		"get", "machineset", "-n", "openshift-machine-api",
+		"-l", "machine.openshift.io/cluster-api-machine-role=worker",
+		"-o", "json")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("list worker machinesets: %w", err)
+	}
+
+	var list struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("parse machinesets: %w", err)
+	}
+	if len(list.Items) == 0 {
+		return nil, fmt.Errorf("no worker machinesets found")
+	}
+	return list.Items[0], nil
+}
+
+// machineSetForPool derives a MachineSet for an extra pool from a worker
+// MachineSet: new name and selector labels, the pool's replicas, and the
+// pool's instance type in the platform-specific providerSpec field
+func machineSetForPool(template map[string]interface{}, pool types.MachinePoolOverride, platform types.Platform) ([]byte, error) {
+	// Deep copy through JSON so the template can be reused for every pool
+	data, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+	var ms map[string]interface{}
+	if err := json.Unmarshal(data, &ms); err != nil {
+		return nil, err
+	}
+	delete(ms, "status")
+
+	metadata, _ := ms["metadata"].(map[string]interface{})
+	labels, _ := metadata["labels"].(map[string]interface{})
+	infraID, _ := labels["machine.openshift.io/cluster-api-cluster"].(string)
+	if infraID == "" {
+		return nil, fmt.Errorf("worker MachineSet has no cluster-api-cluster label")
+	}
+	name := fmt.Sprintf("%s-%s", infraID, pool.Name)
+	ms["metadata"] = map[string]interface{}{
+		"name":      name,
+		"namespace": "openshift-machine-api",
+		"labels":    labels,
+	}
+
+	spec, _ := ms["spec"].(map[string]interface{})
+	if spec == nil {
+		return nil, fmt.Errorf("worker MachineSet has no spec")
+	}
+	spec["replicas"] = pool.Replicas
+
+	selector, _ := spec["selector"].(map[string]interface{})
+	matchLabels, _ := selector["matchLabels"].(map[string]interface{})
+	if matchLabels == nil {
+		return nil, fmt.Errorf("worker MachineSet has no selector")
+	}
+	matchLabels["machine.openshift.io/cluster-api-machineset"] = name
+
+	tmpl, _ := spec["template"].(map[string]interface{})
+	tmplMeta, _ := tmpl["metadata"].(map[string]interface{})
+	tmplLabels, _ := tmplMeta["labels"].(map[string]interface{})
+	if tmplLabels == nil {
+		return nil, fmt.Errorf("worker MachineSet has no template labels")
+	}
+	tmplLabels["machine.openshift.io/cluster-api-machineset"] = name
+
+	// Label the nodes so workloads can target the pool
+	tmplSpec, _ := tmpl["spec"].(map[string]interface{})
+	if tmplSpec == nil {
+		return nil, fmt.Errorf("worker MachineSet has no template spec")
+	}
+	tmplSpec["metadata"] = map[string]interface{}{
+		"labels": map[string]interface{}{
+			"ocpctl.io/machine-pool": pool.Name,
+		},
+	}
+
+	providerSpec, _ := tmplSpec["providerSpec"].(map[string]interface{})
+	value, _ := providerSpec["value"].(map[string]interface{})
+	if value == nil {
+		return nil, fmt.Errorf("worker MachineSet has no providerSpec")
+	}
+	switch platform {
+	case types.PlatformAWS:
+		value["instanceType"] = pool.InstanceType
+		// Extra pools are on-demand even when the default workers are spot
+		delete(value, "spotMarketOptions")
+	case types.PlatformGCP:
+		value["machineType"] = pool.InstanceType
+	case types.PlatformAzure:
+		value["vmSize"] = pool.InstanceType
+	case types.PlatformIBMCloud:
+		value["profile"] = pool.InstanceType
+	default:
+		return nil, fmt.Errorf("unsupported platform %s", platform)
+	}
+
+	return json.Marshal(ms)
+}
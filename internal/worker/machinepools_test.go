@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+const workerMachineSetJSON = `{
+  "apiVersion": "machine.openshift.io/v1beta1",
+  "kind": "MachineSet",
+  "metadata": {
+    "name": "demo-x7k2p-worker-us-east-1a",
+    "namespace": "openshift-machine-api",
+    "resourceVersion": "12345",
+    "uid": "abc",
+    "labels": {"machine.openshift.io/cluster-api-cluster": "demo-x7k2p"}
+  },
+  "spec": {
+    "replicas": 1,
+    "selector": {"matchLabels": {
+      "machine.openshift.io/cluster-api-cluster": "demo-x7k2p",
+      "machine.openshift.io/cluster-api-machineset": "demo-x7k2p-worker-us-east-1a"
+    }},
+    "template": {
+      "metadata": {"labels": {
+        "machine.openshift.io/cluster-api-cluster": "demo-x7k2p",
+        "machine.openshift.io/cluster-api-machineset": "demo-x7k2p-worker-us-east-1a"
+      }},
+      "spec": {"providerSpec": {"value": {"instanceType": "m6i.2xlarge"}}}
+    }
+  },
+  "status": {"replicas": 1}
+}`
+
+func TestMachineSetForPool(t *testing.T) {
+	var template map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(workerMachineSetJSON), &template))
+
+	data, err := machineSetForPool(template, types.MachinePoolOverride{Name: "infra", InstanceType: "m6i.xlarge", Replicas: 2}, types.PlatformAWS)
+	require.NoError(t, err)
+
+	var ms struct {
+		Metadata struct {
+			Name            string `json:"name"`
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+		Spec struct {
+			Replicas int `json:"replicas"`
+			Selector struct {
+				MatchLabels map[string]string `json:"matchLabels"`
+			} `json:"selector"`
+			Template struct {
+				Metadata struct {
+					Labels map[string]string `json:"labels"`
+				} `json:"metadata"`
+				Spec struct {
+					Metadata struct {
+						Labels map[string]string `json:"labels"`
+					} `json:"metadata"`
+					ProviderSpec struct {
+						Value map[string]interface{} `json:"value"`
+					} `json:"providerSpec"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+		Status interface{} `json:"status"`
+	}
+	require.NoError(t, json.Unmarshal(data, &ms))
+
+	assert.Equal(t, "demo-x7k2p-infra", ms.Metadata.Name)
+	assert.Empty(t, ms.Metadata.ResourceVersion)
+	assert.Nil(t, ms.Status)
+	assert.Equal(t, 2, ms.Spec.Replicas)
+	assert.Equal(t, "demo-x7k2p-infra", ms.Spec.Selector.MatchLabels["machine.openshift.io/cluster-api-machineset"])
+	assert.Equal(t, "demo-x7k2p-infra", ms.Spec.Template.Metadata.Labels["machine.openshift.io/cluster-api-machineset"])
+	assert.Equal(t, "infra", ms.Spec.Template.Spec.Metadata.Labels["ocpctl.io/machine-pool"])
+	assert.Equal(t, "m6i.xlarge", ms.Spec.Template.Spec.ProviderSpec.Value["instanceType"])
+
+	// The template is reusable for the next pool
+	assert.Equal(t, "m6i.2xlarge", template["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["providerSpec"].(map[string]interface{})["value"].(map[string]interface{})["instanceType"])
+}
@@ -0,0 +1,391 @@
+package worker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/internal/gitops"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/validation"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// MaxGitManifestSize caps the YAML read from a Git source (10MB, the same
+// limit as downloaded scripts)
+const MaxGitManifestSize = 10 * 1024 * 1024
+
+// kustomizationFiles are the file names kustomize reads a kustomization from
+var kustomizationFiles = map[string]bool{
+	"kustomization.yaml": true,
+	"kustomization.yml":  true,
+	"Kustomization":      true,
+}
+
+// gitCredentials is the layout of a Git credentials secret. A secret that is
+// not a JSON object is used as a token.
+type gitCredentials struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Token         string `json:"token,omitempty"`
+	SSHPrivateKey string `json:"sshPrivateKey,omitempty"`
+	KnownHosts    string `json:"knownHosts,omitempty"`
+}
+
+// fetchGitManifest checks out a manifest's Git source and returns the YAML to
+// apply with the commit it was read from. With kustomize the path is built as
+// a kustomization; otherwise the path is a manifest file or a directory whose
+// .yaml, .yml and .json files are applied in name order.
+func (h *PostConfigureHandler) fetchGitManifest(ctx context.Context, cluster *types.Cluster, manifest types.CustomManifestConfig) (string, string, error) {
+	src := manifest.Git
+	if err := gitops.ValidateGitURL(src.Repo); err != nil {
+		return "", "", fmt.Errorf("invalid git repo: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "ocpctl-git-source-")
+	if err != nil {
+		return "", "", fmt.Errorf("create checkout directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Resolve the temp dir so paths in the checkout compare against its real location
+	if tmpDir, err = filepath.EvalSymlinks(tmpDir); err != nil {
+		return "", "", fmt.Errorf("resolve checkout directory: %w", err)
+	}
+
+	// Credentials files live beside the checkout, never inside it
+	repoDir := filepath.Join(tmpDir, "repo")
+	authDir := filepath.Join(tmpDir, "auth")
+	for _, dir := range []string{repoDir, authDir} {
+		if err := os.Mkdir(dir, 0700); err != nil {
+			return "", "", fmt.Errorf("create checkout directory: %w", err)
+		}
+	}
+
+	env, err := h.gitCredentialEnv(ctx, cluster, src.CredentialsSecret, authDir)
+	if err != nil {
+		return "", "", err
+	}
+
+	revision, err := gitops.Checkout(ctx, repoDir, src.Repo, src.Ref, env)
+	if err != nil {
+		return "", "", fmt.Errorf("fetch %s: %w", src.Repo, err)
+	}
+	log.Printf("[CUSTOM POST-CONFIG] Checked out %s at %s for manifest %s", src.Repo, revision, manifest.Name)
+
+	target, err := gitops.ResolveCheckoutPath(repoDir, src.Path)
+	if err != nil {
+		return "", "", err
+	}
+
+	var content string
+	if manifest.Kustomize {
+		content, err = kustomizeBuild(ctx, repoDir, target)
+	} else {
+		content, err = readManifestFiles(repoDir, target)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("%s at %s: %w", displayGitPath(src.Path), revision, err)
+	}
+	return content, revision, nil
+}
+
+// gitCredentialEnv reads a credentials secret of the cluster's team and
+// returns the git environment that authenticates with it. Token and password
+// credentials are sent as an HTTP header and SSH keys are written to authDir,
+// so neither appears on a command line. The credentials are masked in the
+// job's logs.
+func (h *PostConfigureHandler) gitCredentialEnv(ctx context.Context, cluster *types.Cluster, secretName, authDir string) ([]string, error) {
+	if secretName == "" {
+		return nil, nil
+	}
+
+	value, err := h.resolveSecretRef(ctx, cluster, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("git credentials: %w", err)
+	}
+
+	creds, err := parseGitCredentials(value)
+	if err != nil {
+		return nil, fmt.Errorf("credentials secret %s: %w", secretName, err)
+	}
+	mask := secretMaskFromContext(ctx)
+	for _, v := range []string{creds.Token, creds.Password, creds.SSHPrivateKey, creds.basicAuth()} {
+		mask.add(v)
+	}
+	return creds.env(authDir)
+}
+
+// parseGitCredentials parses a credentials secret value
+func parseGitCredentials(value string) (*gitCredentials, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "{") {
+		return &gitCredentials{Token: value}, nil
+	}
+
+	var creds gitCredentials
+	if err := json.Unmarshal([]byte(value), &creds); err != nil {
+		return nil, fmt.Errorf("parse credentials: %w", err)
+	}
+	return &creds, nil
+}
+
+// env returns the git environment for the credentials, writing SSH key
+// material to authDir
+func (c *gitCredentials) env(authDir string) ([]string, error) {
+	switch {
+	case c.SSHPrivateKey != "":
+		keyPath := filepath.Join(authDir, "id_key")
+		key := strings.TrimSpace(c.SSHPrivateKey) + "\n"
+		if err := os.WriteFile(keyPath, []byte(key), 0600); err != nil {
+			return nil, fmt.Errorf("write ssh key: %w", err)
+		}
+
+		sshCommand := fmt.Sprintf("ssh -i '%s' -o IdentitiesOnly=yes -o BatchMode=yes", keyPath)
+		if c.KnownHosts != "" {
+			knownHostsPath := filepath.Join(authDir, "known_hosts")
+			if err := os.WriteFile(knownHostsPath, []byte(c.KnownHosts), 0600); err != nil {
+				return nil, fmt.Errorf("write known hosts: %w", err)
+			}
+			sshCommand += fmt.Sprintf(" -o UserKnownHostsFile='%s' -o StrictHostKeyChecking=yes", knownHostsPath)
+		} else {
+			// Without known hosts the host key is accepted on first use
+			sshCommand += fmt.Sprintf(" -o UserKnownHostsFile='%s' -o StrictHostKeyChecking=accept-new", filepath.Join(authDir, "known_hosts"))
+		}
+		return []string{"GIT_SSH_COMMAND=" + sshCommand}, nil
+
+	case c.Token != "" || c.Password != "":
+		return []string{
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic " + c.basicAuth(),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("credentials must have a token, password or sshPrivateKey")
+	}
+}
+
+// basicAuth returns the HTTP basic credentials for a token or password, or
+// an empty string if there is neither
+func (c *gitCredentials) basicAuth() string {
+	if c.Token == "" && c.Password == "" {
+		return ""
+	}
+	username := c.Username
+	if username == "" {
+		username = "git"
+	}
+	password := c.Password
+	if password == "" {
+		password = c.Token
+	}
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+// readManifestFiles reads a manifest file, or the manifest files directly in
+// a directory, as one multi-document YAML stream
+func readManifestFiles(root, target string) (string, error) {
+	info, err := os.Stat(target)
+	if err != nil {
+		return "", err
+	}
+
+	files := []string{target}
+	if info.IsDir() {
+		entries, err := os.ReadDir(target)
+		if err != nil {
+			return "", err
+		}
+		files = nil
+		for _, entry := range entries {
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".yaml", ".yml", ".json":
+				files = append(files, filepath.Join(target, entry.Name()))
+			}
+		}
+		if len(files) == 0 {
+			return "", fmt.Errorf("no .yaml, .yml or .json files found")
+		}
+	}
+
+	var docs []string
+	size := 0
+	for _, file := range files {
+		// Each file is resolved again so a symlink cannot read outside the checkout
+		name := mustRel(root, file)
+		resolved, err := gitops.ResolveCheckoutPath(root, name)
+		if err != nil {
+			return "", err
+		}
+		if info, err := os.Stat(resolved); err != nil || info.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(resolved)
+		if err != nil {
+			return "", fmt.Errorf("read %s: %w", name, err)
+		}
+		size += len(data)
+		if size > MaxGitManifestSize {
+			return "", fmt.Errorf("manifests exceed maximum size of %d bytes", MaxGitManifestSize)
+		}
+		docs = append(docs, strings.TrimSpace(string(data)))
+	}
+
+	return strings.Join(docs, "\n---\n") + "\n", nil
+}
+
+// kustomizeBuild builds the kustomization in dir. Kustomize fetches remote
+// bases itself, bypassing the URL checks applied to other sources, so every
+// kustomization in the checkout must only reference files in it.
+func kustomizeBuild(ctx context.Context, root, dir string) (string, error) {
+	if err := checkLocalKustomizations(root); err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "oc", "kustomize", dir)
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.ReplaceAll(strings.TrimSpace(stderr.String()), root+string(filepath.Separator), "")
+		return "", fmt.Errorf("kustomize build failed: %v: %s", err, msg)
+	}
+	if stdout.Len() > MaxGitManifestSize {
+		return "", fmt.Errorf("kustomize output exceeds maximum size of %d bytes", MaxGitManifestSize)
+	}
+	return stdout.String(), nil
+}
+
+// checkLocalKustomizations rejects kustomizations whose resources, bases or
+// components are not files or directories inside the checkout
+func checkLocalKustomizations(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !kustomizationFiles[d.Name()] {
+			return nil
+		}
+
+		rel := mustRel(root, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", rel, err)
+		}
+		var kustomization struct {
+			Resources  []string `yaml:"resources"`
+			Bases      []string `yaml:"bases"`
+			Components []string `yaml:"components"`
+		}
+		if err := yaml.Unmarshal(data, &kustomization); err != nil {
+			return fmt.Errorf("parse %s: %w", rel, err)
+		}
+
+		refs := append(append(kustomization.Resources, kustomization.Bases...), kustomization.Components...)
+		for _, ref := range refs {
+			if strings.Contains(ref, "://") || filepath.IsAbs(ref) {
+				return fmt.Errorf("%s: remote resource %q is not allowed", rel, ref)
+			}
+			if _, err := gitops.ResolveCheckoutPath(root, filepath.Join(filepath.Dir(rel), ref)); err != nil {
+				return fmt.Errorf("%s: resource %q is not a file or directory in the repository", rel, ref)
+			}
+		}
+		return nil
+	})
+}
+
+// validateSecureChartRepo checks a Helm chart repository: an HTTPS chart
+// repository URL, or an oci:// registry whose host is not a private address
+func validateSecureChartRepo(repo string) error {
+	if !isOCIChartRepo(repo) {
+		return validateSecureURL(repo)
+	}
+
+	parsedURL, err := url.Parse(repo)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsedURL.Hostname() == "" {
+		return fmt.Errorf("URL has no hostname")
+	}
+	return validation.ValidateSecureHost(parsedURL.Hostname())
+}
+
+// isOCIChartRepo reports whether a chart repository is an OCI registry path
+func isOCIChartRepo(repo string) bool {
+	return strings.HasPrefix(repo, "oci://")
+}
+
+// pullOCIChart pulls a chart from an OCI registry into dir and returns the
+// path of the chart archive. A chart that pins a digest must match the
+// digest helm reports for the pulled artifact.
+func pullOCIChart(ctx context.Context, dir string, chart profile.HelmChartConfig) (string, error) {
+	chartRef := strings.TrimSuffix(chart.Repo, "/") + "/" + chart.Chart
+	args := []string{"pull", chartRef, "--destination", dir}
+	if chart.Version != "" {
+		args = append(args, "--version", chart.Version)
+	}
+
+	output, err := exec.CommandContext(ctx, "helm", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("helm pull failed: %v\nOutput: %s", err, string(output))
+	}
+
+	if chart.Digest != "" {
+		digest := pulledChartDigest(string(output))
+		if digest != chart.Digest {
+			if digest == "" {
+				digest = "unknown"
+			}
+			return "", fmt.Errorf("chart %s has digest %s, expected %s", chartRef, digest, chart.Digest)
+		}
+	}
+
+	archives, err := filepath.Glob(filepath.Join(dir, "*.tgz"))
+	if err != nil || len(archives) != 1 {
+		return "", fmt.Errorf("helm pull of %s did not produce a chart archive", chartRef)
+	}
+	return archives[0], nil
+}
+
+// pulledChartDigest returns the artifact digest reported by helm pull
+func pulledChartDigest(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if digest, ok := strings.CutPrefix(strings.TrimSpace(line), "Digest:"); ok {
+			return strings.TrimSpace(digest)
+		}
+	}
+	return ""
+}
+
+func mustRel(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+func displayGitPath(path string) string {
+	if path == "" {
+		return "repository root"
+	}
+	return path
+}
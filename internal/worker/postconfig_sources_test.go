@@ -0,0 +1,195 @@
+package worker
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// fakeSecrets serves secret values from memory
+type fakeSecrets map[string]string
+
+func (f fakeSecrets) GetSecret(_ context.Context, name string) (string, error) {
+	if v, ok := f[name]; ok {
+		return v, nil
+	}
+	return "", errors.New("secret not found")
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+}
+
+func TestGitCredentialEnv(t *testing.T) {
+	ctx := withSecretMask(context.Background())
+	h := &PostConfigureHandler{config: &Config{
+		PostConfigSecretPrefix: "ocpctl/post-config/",
+		Secrets: fakeSecrets{
+			"ocpctl/post-config/platform/token":    "ghp_abc\n",
+			"ocpctl/post-config/platform/basic":    `{"username": "deploy", "password": "s3cret"}`,
+			"ocpctl/post-config/platform/ssh":      `{"sshPrivateKey": "KEY", "knownHosts": "github.com ssh-ed25519 AAAA"}`,
+			"ocpctl/post-config/platform/empty":    `{"username": "deploy"}`,
+			"ocpctl/post-config/platform/bad-json": `{"token": `,
+			"ocpctl/post-config/storage/token":     "ghp_other",
+		},
+	}}
+	cluster := &types.Cluster{Name: "dev", Team: "platform"}
+
+	t.Run("no secret", func(t *testing.T) {
+		env, err := h.gitCredentialEnv(ctx, cluster, "", t.TempDir())
+		require.NoError(t, err)
+		assert.Empty(t, env)
+	})
+
+	t.Run("plain token", func(t *testing.T) {
+		env, err := h.gitCredentialEnv(ctx, cluster, "platform/token", t.TempDir())
+		require.NoError(t, err)
+		auth := base64.StdEncoding.EncodeToString([]byte("git:ghp_abc"))
+		assert.Equal(t, []string{
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic " + auth,
+		}, env)
+	})
+
+	t.Run("username and password", func(t *testing.T) {
+		env, err := h.gitCredentialEnv(ctx, cluster, "platform/basic", t.TempDir())
+		require.NoError(t, err)
+		auth := base64.StdEncoding.EncodeToString([]byte("deploy:s3cret"))
+		assert.Contains(t, env, "GIT_CONFIG_VALUE_0=Authorization: Basic "+auth)
+
+		// The credentials and the header built from them are masked in logs
+		mask := secretMaskFromContext(ctx)
+		assert.Equal(t, "password *** header Basic ***", mask.Mask("password s3cret header Basic "+auth))
+	})
+
+	t.Run("ssh key", func(t *testing.T) {
+		authDir := t.TempDir()
+		env, err := h.gitCredentialEnv(ctx, cluster, "platform/ssh", authDir)
+		require.NoError(t, err)
+		require.Len(t, env, 1)
+		assert.True(t, strings.HasPrefix(env[0], "GIT_SSH_COMMAND=ssh -i '"+filepath.Join(authDir, "id_key")+"'"))
+		assert.Contains(t, env[0], "StrictHostKeyChecking=yes")
+
+		key, err := os.ReadFile(filepath.Join(authDir, "id_key"))
+		require.NoError(t, err)
+		assert.Equal(t, "KEY\n", string(key))
+		info, err := os.Stat(filepath.Join(authDir, "id_key"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, name := range []string{"platform/missing", "platform/empty", "platform/bad-json"} {
+			_, err := h.gitCredentialEnv(ctx, cluster, name, t.TempDir())
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("secrets of other teams are rejected", func(t *testing.T) {
+		for _, name := range []string{"storage/token", "token", "platform/../storage/token", "ocpctl/post-config/platform/token"} {
+			_, err := h.gitCredentialEnv(ctx, cluster, name, t.TempDir())
+			assert.Error(t, err, name)
+		}
+		_, err := h.gitCredentialEnv(ctx, cluster, "storage/token", t.TempDir())
+		assert.ErrorContains(t, err, "belongs to team platform")
+	})
+
+	t.Run("no secrets backend", func(t *testing.T) {
+		noSecrets := &PostConfigureHandler{config: &Config{}}
+		_, err := noSecrets.gitCredentialEnv(ctx, cluster, "platform/token", t.TempDir())
+		assert.ErrorContains(t, err, "no secrets backend")
+	})
+}
+
+func TestReadManifestFiles(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	writeFiles(t, root, map[string]string{
+		"app/01-namespace.yaml":   "kind: Namespace\n",
+		"app/02-deployment.yml":   "kind: Deployment\n",
+		"app/03-service.json":     `{"kind": "Service"}`,
+		"app/README.md":           "docs",
+		"app/nested/config.yaml":  "kind: ConfigMap",
+		"single/route.yaml":       "kind: Route",
+		"empty/notes.txt":         "",
+		"escape/placeholder.yaml": "kind: ConfigMap",
+	})
+	outside := t.TempDir()
+	writeFiles(t, outside, map[string]string{"secret.yaml": "kind: Secret"})
+	require.NoError(t, os.Symlink(filepath.Join(outside, "secret.yaml"), filepath.Join(root, "escape", "secret.yaml")))
+
+	content, err := readManifestFiles(root, filepath.Join(root, "app"))
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Namespace\n---\nkind: Deployment\n---\n{\"kind\": \"Service\"}\n", content)
+
+	content, err = readManifestFiles(root, filepath.Join(root, "single", "route.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Route\n", content)
+
+	_, err = readManifestFiles(root, filepath.Join(root, "empty"))
+	assert.ErrorContains(t, err, "no .yaml")
+
+	_, err = readManifestFiles(root, filepath.Join(root, "escape"))
+	assert.ErrorContains(t, err, "links outside the repository")
+}
+
+func TestCheckLocalKustomizations(t *testing.T) {
+	t.Run("local references", func(t *testing.T) {
+		root, err := filepath.EvalSymlinks(t.TempDir())
+		require.NoError(t, err)
+		writeFiles(t, root, map[string]string{
+			"base/kustomization.yaml":           "resources:\n- deployment.yaml\n",
+			"base/deployment.yaml":              "kind: Deployment",
+			"components/tls/kustomization.yaml": "kind: Component\n",
+			"overlays/dev/kustomization.yaml":   "resources:\n- ../../base\ncomponents:\n- ../../components/tls\n",
+		})
+		assert.NoError(t, checkLocalKustomizations(root))
+	})
+
+	tests := map[string]string{
+		"remote URL":            "resources:\n- https://github.com/org/repo//base?ref=v1\n",
+		"remote without scheme": "resources:\n- github.com/org/repo/base?ref=v1\n",
+		"outside checkout":      "bases:\n- ../../../etc\n",
+		"absolute path":         "resources:\n- /etc/kubernetes/admin.yaml\n",
+	}
+	for name, kustomization := range tests {
+		t.Run(name, func(t *testing.T) {
+			root, err := filepath.EvalSymlinks(t.TempDir())
+			require.NoError(t, err)
+			writeFiles(t, root, map[string]string{"overlays/dev/kustomization.yaml": kustomization})
+			assert.Error(t, checkLocalKustomizations(root))
+		})
+	}
+}
+
+func TestValidateSecureChartRepo(t *testing.T) {
+	for _, repo := range []string{
+		"oci://localhost/charts",
+		"oci://10.1.2.3/charts",
+		"oci:///charts",
+		"http://charts.example.com",
+	} {
+		assert.Error(t, validateSecureChartRepo(repo), repo)
+	}
+}
+
+func TestPulledChartDigest(t *testing.T) {
+	output := "Pulled: quay.io/org/charts/app:1.2.0\n" +
+		"Digest: sha256:3f0c1f7c2b1e8a1d5c9f0e6b7a4d2c8e1f3a5b7c9d0e2f4a6b8c0d1e3f5a7b9c\n"
+	assert.Equal(t, "sha256:3f0c1f7c2b1e8a1d5c9f0e6b7a4d2c8e1f3a5b7c9d0e2f4a6b8c0d1e3f5a7b9c", pulledChartDigest(output))
+	assert.Empty(t, pulledChartDigest("Pulled: quay.io/org/charts/app:1.2.0\n"))
+}
@@ -13,6 +13,7 @@ import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
 
 // AWSPreflightChecker validates AWS resource availability before cluster creation
@@ -37,8 +38,9 @@ func NewAWSPreflightChecker(ctx context.Context, region string) (*AWSPreflightCh
 }
 
 // CheckInstanceTypeAvailability validates that required instance types are available in sufficient zones
-// for high-availability cluster deployment. Returns error if insufficient availability detected.
-func (c *AWSPreflightChecker) CheckInstanceTypeAvailability(ctx context.Context, prof *profile.Profile) error {
+// for high-availability cluster deployment, and that each runs the architecture its nodes need.
+// Returns error if insufficient availability or an architecture mismatch is detected.
+func (c *AWSPreflightChecker) CheckInstanceTypeAvailability(ctx context.Context, prof *profile.Profile, arch *types.ClusterArchitecture) error {
 	// Extract instance types from profile
 	instanceTypes := c.extractInstanceTypes(prof)
 	if len(instanceTypes) == 0 {
@@ -46,6 +48,10 @@ func (c *AWSPreflightChecker) CheckInstanceTypeAvailability(ctx context.Context,
 		return nil
 	}
 
+	if err := checkInstanceArchitectures(ctx, c.ec2Client, expectedArchitectures(prof, arch)); err != nil {
+		return err
+	}
+
 	log.Printf("Pre-flight check: validating availability for instance types: %v in region %s",
 		instanceTypes, c.region)
 
@@ -114,6 +120,83 @@ func (c *AWSPreflightChecker) extractInstanceTypes(prof *profile.Profile) []stri
 	return types
 }
 
+// ec2InstanceTypesAPI is the subset of the EC2 client used by the
+// architecture check. It is satisfied by *ec2.Client and mocked in tests.
+type ec2InstanceTypesAPI interface {
+	DescribeInstanceTypes(context.Context, *ec2.DescribeInstanceTypesInput, ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error)
+}
+
+// expectedArchitectures maps each control plane and worker instance type in
+// the profile to the architecture its nodes must run
+func expectedArchitectures(prof *profile.Profile, arch *types.ClusterArchitecture) map[string]types.Architecture {
+	want := make(map[string]types.Architecture)
+	for _, s := range prof.WorkerShapes() {
+		if s.InstanceType != "" {
+			want[s.InstanceType] = arch.ComputeArch()
+		}
+	}
+	for _, pool := range prof.SpotPools() {
+		for _, it := range pool.InstanceTypes {
+			want[it] = arch.ComputeArch()
+		}
+	}
+	if cp, ok := prof.ControlPlaneShape(); ok && cp.InstanceType != "" {
+		want[cp.InstanceType] = arch.ControlPlaneArch()
+	}
+	return want
+}
+
+// ec2Architecture is the EC2 name of a node architecture
+func ec2Architecture(a types.Architecture) ec2types.ArchitectureType {
+	if a == types.ArchitectureARM64 {
+		return ec2types.ArchitectureTypeArm64
+	}
+	return ec2types.ArchitectureTypeX8664
+}
+
+// checkInstanceArchitectures confirms with EC2 that every instance type
+// supports the architecture expected of it. The lookup is best effort: errors
+// are logged, and only a confirmed mismatch fails the check.
+func checkInstanceArchitectures(ctx context.Context, ec2c ec2InstanceTypesAPI, want map[string]types.Architecture) error {
+	if len(want) == 0 {
+		return nil
+	}
+	input := &ec2.DescribeInstanceTypesInput{}
+	for it := range want {
+		input.InstanceTypes = append(input.InstanceTypes, ec2types.InstanceType(it))
+	}
+	sort.Slice(input.InstanceTypes, func(i, j int) bool { return input.InstanceTypes[i] < input.InstanceTypes[j] })
+
+	out, err := ec2c.DescribeInstanceTypes(ctx, input)
+	if err != nil {
+		log.Printf("Warning: instance type architecture pre-flight check skipped: %v", err)
+		return nil
+	}
+
+	var mismatched []string
+	for _, info := range out.InstanceTypes {
+		expected, ok := want[string(info.InstanceType)]
+		if !ok || info.ProcessorInfo == nil {
+			continue
+		}
+		supported := false
+		for _, a := range info.ProcessorInfo.SupportedArchitectures {
+			if a == ec2Architecture(expected) {
+				supported = true
+			}
+		}
+		if !supported {
+			mismatched = append(mismatched, fmt.Sprintf("%s (needs %s)", info.InstanceType, expected))
+		}
+	}
+	if len(mismatched) > 0 {
+		sort.Strings(mismatched)
+		return fmt.Errorf("instance types do not support the cluster architecture: %s", strings.Join(mismatched, ", "))
+	}
+	log.Printf("Pre-flight check: ✓ Instance types match the cluster architecture")
+	return nil
+}
+
 // getAvailableZones queries AWS EC2 API for zones where the instance type is available
 func (c *AWSPreflightChecker) getAvailableZones(ctx context.Context, instanceType string) ([]string, error) {
 	input := &ec2.DescribeInstanceTypeOfferingsInput{
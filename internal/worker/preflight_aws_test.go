@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+type mockInstanceTypesEC2 struct {
+	archs map[string]ec2types.ArchitectureType // instance type -> supported architecture
+	err   error
+}
+
+func (m *mockInstanceTypesEC2) DescribeInstanceTypes(_ context.Context, in *ec2.DescribeInstanceTypesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out ec2.DescribeInstanceTypesOutput
+	for _, it := range in.InstanceTypes {
+		if a, ok := m.archs[string(it)]; ok {
+			out.InstanceTypes = append(out.InstanceTypes, ec2types.InstanceTypeInfo{
+				InstanceType:  it,
+				ProcessorInfo: &ec2types.ProcessorInfo{SupportedArchitectures: []ec2types.ArchitectureType{a}},
+			})
+		}
+	}
+	return &out, nil
+}
+
+func TestCheckInstanceArchitectures(t *testing.T) {
+	ec2c := &mockInstanceTypesEC2{archs: map[string]ec2types.ArchitectureType{
+		"m6i.xlarge":  ec2types.ArchitectureTypeX8664,
+		"m6i.2xlarge": ec2types.ArchitectureTypeX8664,
+		"m7g.2xlarge": ec2types.ArchitectureTypeArm64,
+	}}
+	hetero := &types.ClusterArchitecture{Compute: types.ArchitectureARM64}
+
+	prof := standardProfile()
+	require.NoError(t, checkInstanceArchitectures(context.Background(), ec2c, expectedArchitectures(prof, nil)))
+
+	err := checkInstanceArchitectures(context.Background(), ec2c, expectedArchitectures(prof, hetero))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "m6i.2xlarge (needs arm64)")
+	assert.NotContains(t, err.Error(), "m6i.xlarge ")
+
+	prof.Compute.Workers.InstanceType = "m7g.2xlarge"
+	assert.NoError(t, checkInstanceArchitectures(context.Background(), ec2c, expectedArchitectures(prof, hetero)))
+
+	// Lookup errors never fail the check
+	assert.NoError(t, checkInstanceArchitectures(context.Background(),
+		&mockInstanceTypesEC2{err: errors.New("UnauthorizedOperation")}, expectedArchitectures(prof, nil)))
+}
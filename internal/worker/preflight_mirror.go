@@ -0,0 +1,112 @@
+package worker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// mirrorRegistryTimeout bounds each registry probe
+const mirrorRegistryTimeout = 15 * time.Second
+
+// CheckMirrorRegistries verifies every image mirror registry answers the
+// registry API (GET /v2/) over TLS from the worker. The additional trust
+// bundle is trusted alongside the system roots, so mirrors signed by a lab CA
+// pass. A 200 or an auth challenge (401/403) counts as reachable.
+func CheckMirrorRegistries(ctx context.Context, rn *types.RestrictedNetwork) error {
+	hosts := mirrorRegistryHosts(rn)
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	client, err := mirrorRegistryClient(rn.AdditionalTrustBundle)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, host := range hosts {
+		if err := probeRegistry(ctx, client, host); err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		log.Printf("Mirror registry %s is reachable", host)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("mirror registry unreachable: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// mirrorRegistryHosts returns the distinct registry hosts (host[:port]) the
+// image mirrors point at, sorted
+func mirrorRegistryHosts(rn *types.RestrictedNetwork) []string {
+	if rn == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var hosts []string
+	for _, m := range rn.ImageMirrors {
+		for _, mirror := range m.Mirrors {
+			host := strings.SplitN(mirror, "/", 2)[0]
+			if host == "" || seen[host] {
+				continue
+			}
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// mirrorRegistryClient returns an HTTP client trusting the system roots plus
+// the additional trust bundle
+func mirrorRegistryClient(trustBundle string) (*http.Client, error) {
+	roots, err := x509.SystemCertPool()
+	if err != nil || roots == nil {
+		roots = x509.NewCertPool()
+	}
+	if trustBundle != "" && !roots.AppendCertsFromPEM([]byte(trustBundle)) {
+		return nil, fmt.Errorf("additional trust bundle contains no usable certificates")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   mirrorRegistryTimeout,
+		// Registries redirect /v2/ to token endpoints; the first answer is enough
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}, nil
+}
+
+// probeRegistry checks that host answers the registry API
+func probeRegistry(ctx context.Context, client *http.Client, host string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+host+"/v2/", nil)
+	if err != nil {
+		return fmt.Errorf("%s: %v", host, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %v", host, err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 400,
+		resp.StatusCode == http.StatusUnauthorized,
+		resp.StatusCode == http.StatusForbidden:
+		return nil
+	default:
+		return fmt.Errorf("%s: /v2/ returned HTTP %d, not a container registry", host, resp.StatusCode)
+	}
+}
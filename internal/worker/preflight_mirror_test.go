@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func mirrorsTo(hosts ...string) *types.RestrictedNetwork {
+	rn := &types.RestrictedNetwork{}
+	for _, host := range hosts {
+		rn.ImageMirrors = append(rn.ImageMirrors, types.ImageMirror{
+			Source:  "quay.io/openshift-release-dev/ocp-release",
+			Mirrors: []string{host + "/ocp/release"},
+		})
+	}
+	return rn
+}
+
+func TestMirrorRegistryHosts(t *testing.T) {
+	rn := mirrorsTo("registry.lab:5000", "mirror.corp", "registry.lab:5000")
+	got := mirrorRegistryHosts(rn)
+	want := []string{"mirror.corp", "registry.lab:5000"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("mirrorRegistryHosts = %v, want %v", got, want)
+	}
+	if hosts := mirrorRegistryHosts(nil); hosts != nil {
+		t.Errorf("mirrorRegistryHosts(nil) = %v, want nil", hosts)
+	}
+}
+
+func TestCheckMirrorRegistries(t *testing.T) {
+	registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer registry.Close()
+	host := strings.TrimPrefix(registry.URL, "https://")
+	bundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: registry.Certificate().Raw}))
+
+	t.Run("reachable with trust bundle", func(t *testing.T) {
+		rn := mirrorsTo(host)
+		rn.AdditionalTrustBundle = bundle
+		if err := CheckMirrorRegistries(context.Background(), rn); err != nil {
+			t.Errorf("expected registry to be reachable, got %v", err)
+		}
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		if err := CheckMirrorRegistries(context.Background(), mirrorsTo(host)); err == nil {
+			t.Error("expected TLS verification to fail without the trust bundle")
+		}
+	})
+
+	t.Run("not a registry", func(t *testing.T) {
+		web := httptest.NewTLSServer(http.NotFoundHandler())
+		defer web.Close()
+		rn := mirrorsTo(strings.TrimPrefix(web.URL, "https://"))
+		rn.AdditionalTrustBundle = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: web.Certificate().Raw}))
+		err := CheckMirrorRegistries(context.Background(), rn)
+		if err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+			t.Errorf("expected HTTP 404 failure, got %v", err)
+		}
+	})
+
+	t.Run("no mirrors", func(t *testing.T) {
+		if err := CheckMirrorRegistries(context.Background(), &types.RestrictedNetwork{}); err != nil {
+			t.Errorf("expected no-op, got %v", err)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
 	"github.com/tsanders-rh/ocpctl/internal/store"
 	"github.com/tsanders-rh/ocpctl/pkg/types"
 )
@@ -25,25 +26,30 @@ type JobProcessor struct {
 	poolCleanHandler              *PoolCleanHandler
 	poolRefreshHandler            *PoolRefreshHandler
 	windowsSnapshotHandler        *WindowsSnapshotHandler
+	providers                     *provider.Registry
 }
 
 // NewJobProcessor creates a new job processor
 func NewJobProcessor(config *Config, st *store.Store, profileRegistry *profile.Registry) *JobProcessor {
+	lifecycle := newClusterHandlers(config, st, profileRegistry)
+	postConfigure := NewPostConfigureHandler(config, st, profileRegistry)
+	postConfigure.providers = lifecycle.providers
 	return &JobProcessor{
 		config:                        config,
 		store:                         st,
-		createHandler:                 NewCreateHandler(config, st),
-		destroyHandler:                NewDestroyHandler(config, st),
+		createHandler:                 lifecycle.create,
+		destroyHandler:                lifecycle.destroy,
 		configureEFSHandler:           NewConfigureEFSHandler(config, st),
 		provisionSharedStorageHandler: NewProvisionSharedStorageHandler(config, st),
 		unlinkSharedStorageHandler:    NewUnlinkSharedStorageHandler(config, st),
-		hibernateHandler:              NewHibernateHandler(config, st),
-		resumeHandler:                 NewResumeHandler(config, st),
-		postConfigureHandler:          NewPostConfigureHandler(config, st, profileRegistry),
-		poolReplenishHandler:          NewPoolReplenishHandler(config, st),
-		poolCleanHandler:              NewPoolCleanHandler(config, st),
-		poolRefreshHandler:            NewPoolRefreshHandler(config, st),
-		windowsSnapshotHandler:        NewWindowsSnapshotHandler(config, st),
+		hibernateHandler:              lifecycle.hibernate,
+		resumeHandler:                 lifecycle.resume,
+		postConfigureHandler:          postConfigure,
+		poolReplenishHandler:          NewPoolReplenishHandler(config, st, profileRegistry),
+		poolCleanHandler:              NewPoolCleanHandler(config, st, postConfigure),
+		poolRefreshHandler:            NewPoolRefreshHandler(config, st, profileRegistry),
+		windowsSnapshotHandler:        NewWindowsSnapshotHandler(config, st, profileRegistry),
+		providers:                     lifecycle.providers,
 	}
 }
 
@@ -77,6 +83,12 @@ func (p *JobProcessor) Process(ctx context.Context, job *types.Job) error {
 	case types.JobTypePostConfigure:
 		return p.postConfigureHandler.Handle(ctx, job)
 
+	case types.JobTypeAddonInstall, types.JobTypeAddonUpgrade, types.JobTypeAddonUninstall:
+		return p.postConfigureHandler.HandleAddonJob(ctx, job)
+
+	case types.JobTypeDriftCheck:
+		return p.postConfigureHandler.HandleDriftCheck(ctx, job)
+
 	case types.JobTypePoolReplenish:
 		return p.poolReplenishHandler.Handle(ctx, job)
 
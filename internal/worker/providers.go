@@ -0,0 +1,365 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tsanders-rh/ocpctl/internal/installer"
+	"github.com/tsanders-rh/ocpctl/internal/metrics"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
+	"github.com/tsanders-rh/ocpctl/internal/store"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// clusterHandlers is the set of lifecycle handlers behind the cluster
+// providers. The handlers route jobs to the providers and the providers call
+// back into the handlers' per-type methods, so the set is built as a unit.
+type clusterHandlers struct {
+	create    *CreateHandler
+	destroy   *DestroyHandler
+	hibernate *HibernateHandler
+	resume    *ResumeHandler
+	providers *provider.Registry
+}
+
+// newClusterHandlers creates the lifecycle handlers and registers a provider
+// for every cluster type
+func newClusterHandlers(config *Config, st *store.Store, registry *profile.Registry) *clusterHandlers {
+	// Create metrics publisher (best effort - don't fail if it can't be created)
+	metricsPublisher, err := metrics.NewPublisher(context.Background())
+	if err != nil {
+		log.Printf("Warning: failed to create metrics publisher for destroy handler: %v", err)
+	}
+
+	providers := provider.NewRegistry()
+	hs := &clusterHandlers{
+		create:    &CreateHandler{config: config, store: st, registry: registry, providers: providers},
+		destroy:   &DestroyHandler{config: config, store: st, registry: registry, providers: providers, metricsPublisher: metricsPublisher},
+		hibernate: &HibernateHandler{config: config, store: st, registry: registry, providers: providers},
+		resume:    &ResumeHandler{config: config, store: st, registry: registry, providers: providers},
+		providers: providers,
+	}
+
+	for _, p := range []provider.ClusterProvider{
+		&openshiftProvider{baseProvider{hs, types.ClusterTypeOpenShift}},
+		&rosaProvider{baseProvider{hs, types.ClusterTypeROSA}},
+		&eksProvider{baseProvider{hs, types.ClusterTypeEKS}},
+		&iksProvider{baseProvider{hs, types.ClusterTypeIKS}},
+		&gkeProvider{baseProvider{hs, types.ClusterTypeGKE}},
+		&aroProvider{baseProvider{hs, types.ClusterTypeARO}},
+		&aksProvider{baseProvider{hs, types.ClusterTypeAKS}},
+		&hcpProvider{baseProvider{hs, types.ClusterTypeHCP}},
+		&kindProvider{baseProvider{hs, types.ClusterTypeKind}},
+	} {
+		providers.Register(p)
+	}
+	return hs
+}
+
+// baseProvider holds what the providers share: the handlers and defaults for
+// the steps most cluster types don't customize
+type baseProvider struct {
+	*clusterHandlers
+	clusterType types.ClusterType
+}
+
+// ClusterType returns the cluster type the provider handles
+func (b baseProvider) ClusterType() types.ClusterType {
+	return b.clusterType
+}
+
+// Capabilities returns the built-in capabilities of the cluster type
+func (b baseProvider) Capabilities() provider.Capabilities {
+	caps, _ := provider.Builtin(b.clusterType)
+	return caps
+}
+
+// Preflight has nothing to check by default; installers validate their own
+// input when they start
+func (b baseProvider) Preflight(ctx context.Context, cluster *types.Cluster) error {
+	return nil
+}
+
+// VerifyDestroyed trusts the destroy by default. EKS, GKE and openshift-install
+// destroys confirm removal themselves.
+func (b baseProvider) VerifyDestroyed(ctx context.Context, cluster *types.Cluster) error {
+	return nil
+}
+
+// FetchOutputs returns the outputs the create job recorded
+func (b baseProvider) FetchOutputs(ctx context.Context, cluster *types.Cluster) (*types.ClusterOutputs, error) {
+	return b.create.store.ClusterOutputs.GetByClusterID(ctx, cluster.ID)
+}
+
+// CleanupPartial has nothing to clean up by default
+func (b baseProvider) CleanupPartial(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	log.Printf("No partial deployment cleanup for %s cluster %s", b.clusterType, cluster.Name)
+}
+
+// openshiftProvider installs self-managed OpenShift with openshift-install
+type openshiftProvider struct{ baseProvider }
+
+func (p *openshiftProvider) Preflight(ctx context.Context, cluster *types.Cluster) error {
+	return p.create.preflightOpenShift(ctx, cluster)
+}
+
+func (p *openshiftProvider) Create(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.create.handleOpenShiftCreate(ctx, job, cluster)
+}
+
+func (p *openshiftProvider) Destroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.destroy.handleOpenShiftDestroy(ctx, job, cluster)
+}
+
+func (p *openshiftProvider) Hibernate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.hibernate.hibernateOpenShift(ctx, cluster, job)
+}
+
+func (p *openshiftProvider) Resume(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.resume.resumeOpenShift(ctx, cluster, job)
+}
+
+func (p *openshiftProvider) CleanupPartial(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	p.destroy.cleanupOpenShiftDeployment(ctx, job, cluster, workDir)
+}
+
+// rosaProvider manages ROSA clusters through the rosa CLI
+type rosaProvider struct{ baseProvider }
+
+func (p *rosaProvider) Create(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.create.handleROSACreate(ctx, job, cluster)
+}
+
+func (p *rosaProvider) Destroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.destroy.handleROSADestroy(ctx, job, cluster)
+}
+
+func (p *rosaProvider) Hibernate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.hibernate.hibernateROSA(ctx, cluster, job)
+}
+
+func (p *rosaProvider) Resume(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.resume.resumeROSA(ctx, cluster, job)
+}
+
+// eksProvider manages EKS clusters through eksctl
+type eksProvider struct{ baseProvider }
+
+func (p *eksProvider) Preflight(ctx context.Context, cluster *types.Cluster) error {
+	return p.create.preflightEKS(ctx, cluster)
+}
+
+func (p *eksProvider) Create(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.create.handleEKSCreate(ctx, job, cluster)
+}
+
+func (p *eksProvider) Destroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.destroy.handleEKSDestroy(ctx, job, cluster)
+}
+
+func (p *eksProvider) Hibernate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.hibernate.hibernateEKS(ctx, cluster, job)
+}
+
+func (p *eksProvider) Resume(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.resume.resumeEKS(ctx, cluster, job)
+}
+
+func (p *eksProvider) CleanupPartial(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	p.destroy.cleanupEKSDeployment(ctx, job, cluster, workDir)
+}
+
+// iksProvider manages IBM Cloud Kubernetes Service clusters
+type iksProvider struct{ baseProvider }
+
+func (p *iksProvider) Create(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.create.handleIKSCreate(ctx, job, cluster)
+}
+
+func (p *iksProvider) Destroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.destroy.handleIKSDestroy(ctx, job, cluster)
+}
+
+func (p *iksProvider) Hibernate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.hibernate.hibernateIKS(ctx, cluster, job)
+}
+
+func (p *iksProvider) Resume(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.resume.resumeIKS(ctx, cluster, job)
+}
+
+func (p *iksProvider) CleanupPartial(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	p.destroy.cleanupIKSDeployment(ctx, job, cluster, workDir)
+}
+
+// gkeProvider manages GKE clusters through gcloud
+type gkeProvider struct{ baseProvider }
+
+func (p *gkeProvider) Create(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.create.handleGKECreate(ctx, job, cluster)
+}
+
+func (p *gkeProvider) Destroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.destroy.handleGKEDestroy(ctx, job, cluster)
+}
+
+func (p *gkeProvider) Hibernate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.hibernate.hibernateGKE(ctx, cluster, job)
+}
+
+func (p *gkeProvider) Resume(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.resume.resumeGKE(ctx, cluster, job)
+}
+
+// aroProvider manages Azure Red Hat OpenShift clusters through the az CLI
+type aroProvider struct{ baseProvider }
+
+func (p *aroProvider) Create(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.create.handleAROCreate(ctx, job, cluster)
+}
+
+func (p *aroProvider) Destroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.destroy.handleARODestroy(ctx, job, cluster)
+}
+
+func (p *aroProvider) Hibernate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.hibernate.hibernateARO(ctx, cluster, job)
+}
+
+func (p *aroProvider) Resume(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.resume.resumeARO(ctx, cluster, job)
+}
+
+func (p *aroProvider) CleanupPartial(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	p.destroy.cleanupARODeployment(ctx, job, cluster, workDir)
+}
+
+// aksProvider manages AKS clusters through the az CLI
+type aksProvider struct{ baseProvider }
+
+func (p *aksProvider) Create(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.create.handleAKSCreate(ctx, job, cluster)
+}
+
+func (p *aksProvider) Destroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.destroy.handleAKSDestroy(ctx, job, cluster)
+}
+
+func (p *aksProvider) Hibernate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.hibernate.hibernateAKS(ctx, cluster, job)
+}
+
+func (p *aksProvider) Resume(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.resume.resumeAKS(ctx, cluster, job)
+}
+
+func (p *aksProvider) CleanupPartial(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	p.destroy.cleanupAKSDeployment(ctx, job, cluster, workDir)
+}
+
+// hcpProvider manages hosted control plane clusters on a management cluster
+type hcpProvider struct{ baseProvider }
+
+// Preflight checks that the management cluster is configured
+func (p *hcpProvider) Preflight(ctx context.Context, cluster *types.Cluster) error {
+	if _, err := hcpInstallerFor(p.create.registry, cluster); err != nil {
+		return types.NewPreflightCheckError("%v", err)
+	}
+	return nil
+}
+
+func (p *hcpProvider) Create(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.create.handleHCPCreate(ctx, job, cluster)
+}
+
+func (p *hcpProvider) Destroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.destroy.handleHCPDestroy(ctx, job, cluster)
+}
+
+// VerifyDestroyed checks the HostedCluster is gone from the management cluster
+func (p *hcpProvider) VerifyDestroyed(ctx context.Context, cluster *types.Cluster) error {
+	hcpInstaller, err := hcpInstallerFor(p.destroy.registry, cluster)
+	if err != nil {
+		return err
+	}
+	_, err = hcpInstaller.GetClusterInfo(ctx, cluster.Name)
+	if errors.Is(err, installer.ErrHCPNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("hosted cluster %s still exists", cluster.Name)
+}
+
+func (p *hcpProvider) Hibernate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.hibernate.hibernateHCP(ctx, cluster, job)
+}
+
+func (p *hcpProvider) Resume(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.resume.resumeHCP(ctx, cluster, job)
+}
+
+func (p *hcpProvider) CleanupPartial(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	p.destroy.cleanupHCPDeployment(ctx, job, cluster)
+}
+
+// kindProvider manages kind clusters on the worker host
+type kindProvider struct{ baseProvider }
+
+// Preflight checks that kind and its container runtime are usable
+func (p *kindProvider) Preflight(ctx context.Context, cluster *types.Cluster) error {
+	if _, err := installer.NewKindInstaller().ClusterExists(ctx, cluster.Name); err != nil {
+		return types.NewPreflightCheckError("kind is not available on this worker: %v", err)
+	}
+	return nil
+}
+
+func (p *kindProvider) Create(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.create.handleKindCreate(ctx, job, cluster)
+}
+
+func (p *kindProvider) Destroy(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.destroy.handleKindDestroy(ctx, job, cluster)
+}
+
+// VerifyDestroyed checks kind no longer knows the cluster
+func (p *kindProvider) VerifyDestroyed(ctx context.Context, cluster *types.Cluster) error {
+	exists, err := installer.NewKindInstaller().ClusterExists(ctx, cluster.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("kind cluster %s still exists", cluster.Name)
+	}
+	return nil
+}
+
+func (p *kindProvider) Hibernate(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.hibernate.hibernateKind(ctx, cluster, job)
+}
+
+func (p *kindProvider) Resume(ctx context.Context, job *types.Job, cluster *types.Cluster) error {
+	return p.resume.resumeKind(ctx, cluster, job)
+}
+
+// FetchOutputs reads the outputs from the cluster's kubeconfig, exporting it
+// from kind again if the work directory lost it
+func (p *kindProvider) FetchOutputs(ctx context.Context, cluster *types.Cluster) (*types.ClusterOutputs, error) {
+	kubeconfigPath := filepath.Join(p.create.config.WorkDir, cluster.ID, "auth", "kubeconfig")
+	if _, err := os.Stat(kubeconfigPath); os.IsNotExist(err) {
+		if err := installer.NewKindInstaller().GetKubeconfig(ctx, cluster.Name, kubeconfigPath); err != nil {
+			return nil, fmt.Errorf("get kubeconfig: %w", err)
+		}
+	}
+	return kindOutputs(cluster.ID, kubeconfigPath)
+}
+
+func (p *kindProvider) CleanupPartial(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
+	p.destroy.cleanupKindDeployment(ctx, job, cluster)
+}
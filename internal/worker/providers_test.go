@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/internal/provider"
+)
+
+// TestClusterProviders checks every known cluster type has a provider and that
+// the handlers share the registry
+func TestClusterProviders(t *testing.T) {
+	hs := newClusterHandlers(&Config{WorkDir: t.TempDir()}, nil, nil)
+
+	for _, ct := range provider.NewRegistry().ClusterTypes() {
+		p, err := hs.providers.Get(ct)
+		require.NoError(t, err, ct)
+		assert.Equal(t, ct, p.ClusterType())
+
+		want, _ := provider.Builtin(ct)
+		assert.Equal(t, want, p.Capabilities(), ct)
+	}
+
+	assert.Same(t, hs.providers, hs.create.providers)
+	assert.Same(t, hs.providers, hs.destroy.providers)
+	assert.Same(t, hs.providers, hs.hibernate.providers)
+	assert.Same(t, hs.providers, hs.resume.providers)
+}
@@ -0,0 +1,324 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// readinessOutputLimit caps how much probe output is kept for error messages
+const readinessOutputLimit = 500
+
+// readinessProbe reports whether a check passes, with a description of the
+// state it observed
+type readinessProbe func(ctx context.Context) (bool, string)
+
+// runReadinessChecks runs a task's readiness checks in order, after the task
+// has been applied. Checks that do not name a namespace use namespace. The
+// returned error names the failing check and the last state it observed, and
+// is meant to be stored on the task's configuration record as-is.
+func (h *PostConfigureHandler) runReadinessChecks(ctx context.Context, cluster *types.Cluster, kubeconfigPath, taskName, namespace string, checks []types.ReadinessCheck) error {
+	for i, check := range checks {
+		if check.Namespace == "" {
+			check.Namespace = namespace
+		}
+
+		timeout := PostConfigWaitTimeout
+		if check.Timeout != "" {
+			d, err := time.ParseDuration(check.Timeout)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("readiness check %d (%s): invalid timeout %q", i+1, describeReadinessCheck(check), check.Timeout)
+			}
+			timeout = d
+		}
+
+		probe, diagnose, err := h.readinessProbeFor(cluster, kubeconfigPath, taskName, i, check)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("[READINESS] %s: waiting for %s (timeout: %s)", taskName, describeReadinessCheck(check), timeout)
+		if err := waitForReadiness(ctx, timeout, probe, diagnose); err != nil {
+			return fmt.Errorf("readiness check %d (%s) failed: %w", i+1, describeReadinessCheck(check), err)
+		}
+		log.Printf("[READINESS] %s: %s passed", taskName, describeReadinessCheck(check))
+	}
+	return nil
+}
+
+// readinessProbeFor returns the probe for the i-th readiness check of a task
+// and, for check types that have one, a function that diagnoses why it fails
+func (h *PostConfigureHandler) readinessProbeFor(cluster *types.Cluster, kubeconfigPath, taskName string, i int, check types.ReadinessCheck) (readinessProbe, func(ctx context.Context) string, error) {
+	switch check.Type {
+	case types.ReadinessCheckCondition:
+		return h.conditionProbe(kubeconfigPath, check), h.conditionDiagnostics(kubeconfigPath, check), nil
+	case types.ReadinessCheckRollout:
+		return h.rolloutProbe(kubeconfigPath, check), h.rolloutDiagnostics(kubeconfigPath, check), nil
+	case types.ReadinessCheckScript:
+		probe, err := h.scriptProbe(cluster, kubeconfigPath, taskName, i, check)
+		if err != nil {
+			return nil, nil, fmt.Errorf("readiness check %d (%s): %w", i+1, describeReadinessCheck(check), err)
+		}
+		return probe, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("readiness check %d: unknown type %q", i+1, check.Type)
+	}
+}
+
+// waitForReadiness polls probe until it passes or timeout elapses. On timeout
+// the error carries the last observed state and, when diagnose is set, what
+// it reports about the failure.
+func waitForReadiness(ctx context.Context, timeout time.Duration, probe readinessProbe, diagnose func(ctx context.Context) string) error {
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(PostConfigPollInterval)
+	defer ticker.Stop()
+
+	var detail string
+	for {
+		var ready bool
+		if ready, detail = probe(ctx); ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline:
+			msg := fmt.Sprintf("not ready after %s: %s", timeout, detail)
+			if diagnose != nil {
+				if extra := diagnose(ctx); extra != "" {
+					msg += "; " + extra
+				}
+			}
+			return fmt.Errorf("%s", msg)
+		case <-ticker.C:
+		}
+	}
+}
+
+// describeReadinessCheck summarizes a check for logs and error messages
+func describeReadinessCheck(check types.ReadinessCheck) string {
+	in := ""
+	if check.Namespace != "" {
+		in = " in " + check.Namespace
+	}
+	switch check.Type {
+	case types.ReadinessCheckCondition:
+		if check.JSONPath != "" {
+			return fmt.Sprintf("%s %s/%s%s %s == %q", check.Type, check.Kind, check.Name, in, check.JSONPath, check.Value)
+		}
+		return fmt.Sprintf("%s %s=%s on %s/%s%s", check.Type, check.ConditionType, expectedConditionValue(check), check.Kind, check.Name, in)
+	case types.ReadinessCheckRollout:
+		return fmt.Sprintf("%s of deployment %s%s", check.Type, strings.Join(check.Deployments, ", "), in)
+	default:
+		return string(check.Type) + " probe"
+	}
+}
+
+// expectedConditionValue is the value a condition check waits for
+func expectedConditionValue(check types.ReadinessCheck) string {
+	if check.Value != "" {
+		return check.Value
+	}
+	if check.JSONPath == "" {
+		return "True"
+	}
+	return ""
+}
+
+// conditionJSONPath is the JSONPath a condition check reads
+func conditionJSONPath(check types.ReadinessCheck) string {
+	if check.JSONPath != "" {
+		return check.JSONPath
+	}
+	return fmt.Sprintf(`{.status.conditions[?(@.type=="%s")].status}`, check.ConditionType)
+}
+
+// ocGetArgs builds "oc get" arguments for a check's resource
+func ocGetArgs(kubeconfigPath, namespace string, resource ...string) []string {
+	args := append([]string{"--kubeconfig", kubeconfigPath, "get"}, resource...)
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	return args
+}
+
+func (h *PostConfigureHandler) conditionProbe(kubeconfigPath string, check types.ReadinessCheck) readinessProbe {
+	path := conditionJSONPath(check)
+	want := expectedConditionValue(check)
+	return func(ctx context.Context) (bool, string) {
+		args := append(ocGetArgs(kubeconfigPath, check.Namespace, check.Kind, check.Name), "-o", "jsonpath="+path)
+		output, err := exec.CommandContext(ctx, "oc", args...).CombinedOutput()
+		if err != nil {
+			return false, fmt.Sprintf("cannot read %s/%s: %s", check.Kind, check.Name, truncateOutput(output))
+		}
+		got := strings.TrimSpace(string(output))
+		if got == want {
+			return true, ""
+		}
+		if got == "" {
+			return false, fmt.Sprintf("%s is not set yet, want %q", path, want)
+		}
+		return false, fmt.Sprintf("%s is %q, want %q", path, got, want)
+	}
+}
+
+// conditionDiagnostics reports the resource's status conditions, which
+// usually carry the reason it is not ready
+func (h *PostConfigureHandler) conditionDiagnostics(kubeconfigPath string, check types.ReadinessCheck) func(ctx context.Context) string {
+	return func(ctx context.Context) string {
+		args := append(ocGetArgs(kubeconfigPath, check.Namespace, check.Kind, check.Name), "-o",
+			`jsonpath={range .status.conditions[*]}{.type}={.status} {.reason}: {.message}{"\n"}{end}`)
+		output, err := exec.CommandContext(ctx, "oc", args...).CombinedOutput()
+		if err != nil {
+			return ""
+		}
+		conditions := strings.TrimSpace(string(output))
+		if conditions == "" {
+			return ""
+		}
+		return "conditions: " + truncateOutput([]byte(strings.ReplaceAll(conditions, "\n", "; ")))
+	}
+}
+
+// rolloutStatusJSONPath reads the Deployment fields rolloutComplete checks
+const rolloutStatusJSONPath = `jsonpath={.metadata.generation} {.status.observedGeneration} {.spec.replicas} {.status.updatedReplicas} {.status.availableReplicas}`
+
+func (h *PostConfigureHandler) rolloutProbe(kubeconfigPath string, check types.ReadinessCheck) readinessProbe {
+	return func(ctx context.Context) (bool, string) {
+		for _, name := range check.Deployments {
+			args := append(ocGetArgs(kubeconfigPath, check.Namespace, "deployment", name), "-o", rolloutStatusJSONPath)
+			output, err := exec.CommandContext(ctx, "oc", args...).CombinedOutput()
+			if err != nil {
+				return false, fmt.Sprintf("cannot read deployment %s: %s", name, truncateOutput(output))
+			}
+			if done, detail := rolloutComplete(string(output)); !done {
+				return false, fmt.Sprintf("deployment %s: %s", name, detail)
+			}
+		}
+		return true, ""
+	}
+}
+
+// rolloutComplete reports whether a Deployment has finished rolling out,
+// given its generation, observed generation, desired, updated and available
+// replica counts separated by single spaces. The API server omits status
+// counts that are zero, which leaves their fields empty.
+func rolloutComplete(status string) (bool, string) {
+	fields := strings.Split(strings.TrimSpace(status), " ")
+	values := make([]int, 5)
+	for i := 0; i < len(fields) && i < len(values); i++ {
+		values[i], _ = strconv.Atoi(fields[i])
+	}
+	generation, observed, desired, updated, available := values[0], values[1], values[2], values[3], values[4]
+
+	switch {
+	case observed < generation:
+		return false, "waiting for the deployment spec update to be observed"
+	case updated < desired:
+		return false, fmt.Sprintf("%d of %d replicas updated", updated, desired)
+	case available < desired:
+		return false, fmt.Sprintf("%d of %d replicas available", available, desired)
+	}
+	return true, ""
+}
+
+// rolloutDiagnostics reports pods of the checked Deployments whose containers
+// are waiting, e.g. in CrashLoopBackOff or ImagePullBackOff
+func (h *PostConfigureHandler) rolloutDiagnostics(kubeconfigPath string, check types.ReadinessCheck) func(ctx context.Context) string {
+	return func(ctx context.Context) string {
+		args := append(ocGetArgs(kubeconfigPath, check.Namespace, "pods"), "-o",
+			`jsonpath={range .items[*]}{.metadata.name}{" "}{range .status.containerStatuses[*]}{.state.waiting.reason}{" "}{end}{"\n"}{end}`)
+		output, err := exec.CommandContext(ctx, "oc", args...).CombinedOutput()
+		if err != nil {
+			return ""
+		}
+		var pods []string
+		for _, name := range check.Deployments {
+			pods = append(pods, waitingPods(string(output), name)...)
+		}
+		if len(pods) == 0 {
+			return ""
+		}
+		return "pods: " + strings.Join(pods, ", ")
+	}
+}
+
+// waitingPods picks the pods of a Deployment, by name prefix, that have
+// waiting containers out of "oc get pods" lines of a pod name followed by its
+// containers' waiting reasons
+func waitingPods(output, deployment string) []string {
+	var pods []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], deployment+"-") {
+			continue
+		}
+		pods = append(pods, fmt.Sprintf("%s (%s)", fields[0], strings.Join(fields[1:], ", ")))
+	}
+	return pods
+}
+
+// scriptProbe writes a script check to the cluster work directory and
+// returns a probe that runs it, passing when it exits 0
+func (h *PostConfigureHandler) scriptProbe(cluster *types.Cluster, kubeconfigPath, taskName string, index int, check types.ReadinessCheck) (readinessProbe, error) {
+	probesDir := filepath.Join(h.config.WorkDir, cluster.ID, "readiness-probes")
+	if err := os.MkdirAll(probesDir, 0700); err != nil {
+		return nil, fmt.Errorf("create probes dir: %w", err)
+	}
+	// Task names come from profiles and add-ons and must not escape the directory
+	probePath, err := validateSecurePath(filepath.Join(probesDir, fmt.Sprintf("%s-%d.sh", taskName, index+1)), probesDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid probe script path: %w", err)
+	}
+	if err := os.WriteFile(probePath, []byte(check.Script), 0700); err != nil {
+		return nil, fmt.Errorf("write probe script: %w", err)
+	}
+
+	return func(ctx context.Context) (bool, string) {
+		runCtx, cancel := context.WithTimeout(ctx, ReadinessProbeTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(runCtx, "bash", probePath)
+		cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfigPath, "CLUSTER_ID="+cluster.ID, "CLUSTER_NAME="+cluster.Name)
+		cmd.Dir = probesDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return false, fmt.Sprintf("probe failed: %v; output: %s", err, truncateOutput(output))
+		}
+		return true, ""
+	}, nil
+}
+
+// truncateOutput trims command output to its last readinessOutputLimit bytes
+func truncateOutput(output []byte) string {
+	s := strings.TrimSpace(string(output))
+	if len(s) > readinessOutputLimit {
+		s = "..." + s[len(s)-readinessOutputLimit:]
+	}
+	return s
+}
+
+// readinessInNamespace returns checks with namespace filled in where they do
+// not name one
+func readinessInNamespace(checks []types.ReadinessCheck, namespace string) []types.ReadinessCheck {
+	if len(checks) == 0 || namespace == "" {
+		return checks
+	}
+	out := make([]types.ReadinessCheck, len(checks))
+	for i, check := range checks {
+		if check.Namespace == "" {
+			check.Namespace = namespace
+		}
+		out[i] = check
+	}
+	return out
+}
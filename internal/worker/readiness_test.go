@@ -0,0 +1,136 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func TestRolloutComplete(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		done   bool
+		detail string
+	}{
+		{"complete", "3 3 2 2 2", true, ""},
+		{"spec update not observed", "4 3 2 2 2", false, "waiting for the deployment spec update to be observed"},
+		{"replicas updating", "3 3 3 1 3", false, "1 of 3 replicas updated"},
+		{"replicas unavailable", "3 3 1 1 ", false, "0 of 1 replicas available"},
+		{"new deployment without status", "1  1  ", false, "waiting for the deployment spec update to be observed"},
+		{"scaled to zero", "2 2 0  ", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, detail := rolloutComplete(tt.status)
+			assert.Equal(t, tt.done, done)
+			assert.Equal(t, tt.detail, detail)
+		})
+	}
+}
+
+func TestWaitingPods(t *testing.T) {
+	output := "velero-6d9f7c-abcde CrashLoopBackOff \n" +
+		"velero-6d9f7c-fghij \n" +
+		"node-agent-xyz ImagePullBackOff \n" +
+		"velero-ui-123-456 ErrImagePull \n"
+
+	assert.Equal(t, []string{"velero-6d9f7c-abcde (CrashLoopBackOff)", "velero-ui-123-456 (ErrImagePull)"}, waitingPods(output, "velero"))
+	assert.Empty(t, waitingPods(output, "openshift-adp-controller-manager"))
+}
+
+func TestDescribeReadinessCheck(t *testing.T) {
+	assert.Equal(t, "condition Reconciled=True on DataProtectionApplication/velero in openshift-adp",
+		describeReadinessCheck(types.ReadinessCheck{
+			Type: types.ReadinessCheckCondition, Kind: "DataProtectionApplication", Name: "velero",
+			Namespace: "openshift-adp", ConditionType: "Reconciled",
+		}))
+	assert.Equal(t, `condition HyperConverged/kubevirt-hyperconverged {.status.phase} == "Deployed"`,
+		describeReadinessCheck(types.ReadinessCheck{
+			Type: types.ReadinessCheckCondition, Kind: "HyperConverged", Name: "kubevirt-hyperconverged",
+			JSONPath: "{.status.phase}", Value: "Deployed",
+		}))
+	assert.Equal(t, "rollout of deployment velero, node-agent in openshift-adp",
+		describeReadinessCheck(types.ReadinessCheck{
+			Type: types.ReadinessCheckRollout, Deployments: []string{"velero", "node-agent"}, Namespace: "openshift-adp",
+		}))
+	assert.Equal(t, "script probe", describeReadinessCheck(types.ReadinessCheck{Type: types.ReadinessCheckScript}))
+}
+
+func TestConditionJSONPath(t *testing.T) {
+	check := types.ReadinessCheck{ConditionType: "Available"}
+	assert.Equal(t, `{.status.conditions[?(@.type=="Available")].status}`, conditionJSONPath(check))
+	assert.Equal(t, "True", expectedConditionValue(check))
+
+	check.Value = "False"
+	assert.Equal(t, "False", expectedConditionValue(check))
+
+	check = types.ReadinessCheck{JSONPath: "{.status.phase}", Value: "Succeeded"}
+	assert.Equal(t, "{.status.phase}", conditionJSONPath(check))
+	assert.Equal(t, "Succeeded", expectedConditionValue(check))
+}
+
+func TestReadinessInNamespace(t *testing.T) {
+	checks := []types.ReadinessCheck{
+		{Type: types.ReadinessCheckRollout, Deployments: []string{"app"}},
+		{Type: types.ReadinessCheckRollout, Deployments: []string{"db"}, Namespace: "data"},
+	}
+
+	got := readinessInNamespace(checks, "apps")
+	assert.Equal(t, "apps", got[0].Namespace)
+	assert.Equal(t, "data", got[1].Namespace)
+	assert.Empty(t, checks[0].Namespace, "input must not be modified")
+
+	assert.Equal(t, checks, readinessInNamespace(checks, ""))
+}
+
+func TestWaitForReadiness(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("passes when probe is ready", func(t *testing.T) {
+		calls := 0
+		err := waitForReadiness(ctx, time.Minute, func(context.Context) (bool, string) {
+			calls++
+			return true, ""
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("times out with last state and diagnostics", func(t *testing.T) {
+		err := waitForReadiness(ctx, 10*time.Millisecond, func(context.Context) (bool, string) {
+			return false, "deployment velero: 0 of 1 replicas available"
+		}, func(context.Context) string {
+			return "pods: velero-abc (CrashLoopBackOff)"
+		})
+		require.Error(t, err)
+		assert.Equal(t, "not ready after 10ms: deployment velero: 0 of 1 replicas available; pods: velero-abc (CrashLoopBackOff)", err.Error())
+	})
+
+	t.Run("stops when context is cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		err := waitForReadiness(cancelled, time.Minute, func(context.Context) (bool, string) {
+			return false, "waiting"
+		}, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestScriptProbePath(t *testing.T) {
+	h := &PostConfigureHandler{config: &Config{WorkDir: t.TempDir()}}
+	cluster := &types.Cluster{ID: "c-1", Name: "demo"}
+	check := types.ReadinessCheck{Script: "exit 0"}
+
+	probe, err := h.scriptProbe(cluster, "kubeconfig", "oadp", 0, check)
+	require.NoError(t, err)
+	ready, _ := probe(context.Background())
+	assert.True(t, ready)
+
+	_, err = h.scriptProbe(cluster, "kubeconfig", "../../../escape", 0, check)
+	assert.ErrorContains(t, err, "invalid probe script path")
+}
@@ -0,0 +1,168 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tsanders-rh/ocpctl/internal/postconfig"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// secretMaskContextKey holds the job's secretMask
+const secretMaskContextKey contextKey = "secretMask"
+
+// maskedSecret replaces secret values in logs and errors
+const maskedSecret = "***"
+
+// secretMask collects the secret values a job resolves so they can be
+// removed from logs and errors before they are stored. A nil mask masks
+// nothing.
+type secretMask struct {
+	mu     sync.RWMutex
+	values []string
+}
+
+// withSecretMask returns a context carrying a new, empty secret mask
+func withSecretMask(ctx context.Context) context.Context {
+	return context.WithValue(ctx, secretMaskContextKey, &secretMask{})
+}
+
+// secretMaskFromContext returns the job's secret mask, or nil if there is none
+func secretMaskFromContext(ctx context.Context) *secretMask {
+	mask, _ := ctx.Value(secretMaskContextKey).(*secretMask)
+	return mask
+}
+
+// add records a secret value. Each line of a multi-line value is masked on
+// its own too, since logs are stored a line at a time.
+func (m *secretMask) add(value string) {
+	if m == nil {
+		return
+	}
+
+	candidates := []string{value}
+	if strings.Contains(value, "\n") {
+		for _, line := range strings.Split(value, "\n") {
+			candidates = append(candidates, strings.TrimSpace(line))
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, v := range candidates {
+		if strings.TrimSpace(v) == "" || containsString(m.values, v) {
+			continue
+		}
+		m.values = append(m.values, v)
+	}
+	// Replace longer values first so a value containing another is fully masked
+	sort.Slice(m.values, func(i, j int) bool { return len(m.values[i]) > len(m.values[j]) })
+}
+
+// replace substitutes every recorded secret value in s with replacement
+func (m *secretMask) replace(s, replacement string) string {
+	if m == nil {
+		return s
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, v := range m.values {
+		s = strings.ReplaceAll(s, v, replacement)
+	}
+	return s
+}
+
+// Mask replaces every recorded secret value in s
+func (m *secretMask) Mask(s string) string {
+	return m.replace(s, maskedSecret)
+}
+
+// MaskError returns err with recorded secret values removed from its message.
+// The original error is still reachable with errors.Is and errors.As.
+func (m *secretMask) MaskError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := m.Mask(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+	return &maskedError{msg: msg, err: err}
+}
+
+// maskedError is an error whose message has had secret values masked
+type maskedError struct {
+	msg string
+	err error
+}
+
+func (e *maskedError) Error() string { return e.msg }
+func (e *maskedError) Unwrap() error { return e.err }
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSecretRef reads a {{ secret "team/name" }} reference from the secrets
+// backend and records its value in the job's secret mask. A cluster can only
+// read its own team's secrets, which live under PostConfigSecretPrefix.
+func (h *PostConfigureHandler) resolveSecretRef(ctx context.Context, cluster *types.Cluster, name string) (string, error) {
+	return readTeamSecret(ctx, h.config, cluster, name)
+}
+
+// readTeamSecret reads a <team>/<name> secret of the cluster's team from the
+// secrets backend and records its value in the job's secret mask
+func readTeamSecret(ctx context.Context, config *Config, cluster *types.Cluster, name string) (string, error) {
+	team, _, ok := strings.Cut(name, "/")
+	if !ok || team == "" || strings.Contains(name, "..") {
+		return "", fmt.Errorf("secret %s: names have the form <team>/<name>", name)
+	}
+	if team != cluster.Team {
+		return "", fmt.Errorf("secret %s: cluster %s belongs to team %s", name, cluster.Name, cluster.Team)
+	}
+	if config.Secrets == nil {
+		return "", fmt.Errorf("secret %s: no secrets backend is configured", name)
+	}
+
+	value, err := config.Secrets.GetSecret(ctx, config.PostConfigSecretPrefix+name)
+	if err != nil {
+		return "", fmt.Errorf("read secret %s: %w", name, err)
+	}
+	secretMaskFromContext(ctx).add(value)
+	return value, nil
+}
+
+// renderContext returns the template context a task's content is rendered
+// with. Unlike templateContext it resolves secret references, and the task's
+// variables are rendered first so they can reference secrets too. Variables
+// are rendered without access to each other.
+func (h *PostConfigureHandler) renderContext(ctx context.Context, cluster *types.Cluster, infraID string, customVars map[string]string) (*postconfig.TemplateContext, error) {
+	templateCtx := h.templateContext(cluster, infraID, nil)
+	templateCtx.Secret = func(name string) (string, error) {
+		return h.resolveSecretRef(ctx, cluster, name)
+	}
+
+	vars := make(map[string]string, len(customVars))
+	for k, v := range customVars {
+		if !strings.Contains(v, "{{") {
+			vars[k] = v
+			continue
+		}
+		rendered, err := postconfig.RenderTemplate(v, templateCtx)
+		if err != nil {
+			return nil, fmt.Errorf("render variable %s: %w", k, err)
+		}
+		vars[k] = rendered
+	}
+	templateCtx.Variables = vars
+	return templateCtx, nil
+}
@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ec2SpotAPI is the subset of the EC2 client used by the spot preflight. It is
+// satisfied by *ec2.Client and mocked in tests.
+type ec2SpotAPI interface {
+	DescribeSpotPriceHistory(context.Context, *ec2.DescribeSpotPriceHistoryInput, ...func(*ec2.Options)) (*ec2.DescribeSpotPriceHistoryOutput, error)
+}
+
+// CheckSpotCapacity reports whether the profile's spot worker pools can get
+// spot capacity in the region. It returns true when every pool short of
+// capacity allows falling back to on-demand, and an error when one does not.
+//
+// AWS offers no way to reserve spot capacity ahead of a launch, so a pool
+// counts as short when no instance type in it has a current spot offer, or
+// every offer is above the pool's maxPrice. Lookup errors are logged and the
+// pool is assumed to have capacity.
+func (c *AWSPreflightChecker) CheckSpotCapacity(ctx context.Context, prof *profile.Profile) (bool, error) {
+	return runSpotChecks(ctx, c.region, c.ec2Client, prof)
+}
+
+// runSpotChecks is the testable core of CheckSpotCapacity
+func runSpotChecks(ctx context.Context, region string, ec2c ec2SpotAPI, prof *profile.Profile) (bool, error) {
+	var short []string
+	fallback := true
+	for _, pool := range prof.SpotPools() {
+		maxPrice, _ := profile.ParseSpotMaxPrice(pool.Spot)
+		ok, err := spotOffered(ctx, ec2c, pool.InstanceTypes, maxPrice)
+		if err != nil {
+			log.Printf("Warning: spot pre-flight check for pool %s skipped: %v", pool.Name, err)
+			continue
+		}
+		if ok {
+			log.Printf("Pre-flight check: spot capacity offered for pool %s (%s)", pool.Name, strings.Join(pool.InstanceTypes, ", "))
+			continue
+		}
+		short = append(short, fmt.Sprintf("%s (%s)", pool.Name, strings.Join(pool.InstanceTypes, ", ")))
+		if !pool.Spot.OnDemandFallback {
+			fallback = false
+		}
+	}
+
+	if len(short) == 0 {
+		return false, nil
+	}
+	if !fallback {
+		return false, fmt.Errorf("no spot capacity in region %s for worker pool(s) %s; set spot.onDemandFallback to use on-demand instead",
+			region, strings.Join(short, ", "))
+	}
+	log.Printf("Pre-flight check: no spot capacity in region %s for worker pool(s) %s, falling back to on-demand",
+		region, strings.Join(short, ", "))
+	return true, nil
+}
+
+// spotOffered reports whether any of the instance types has a current Linux
+// spot price at or below maxPrice (0 means any price)
+func spotOffered(ctx context.Context, ec2c ec2SpotAPI, instanceTypes []string, maxPrice float64) (bool, error) {
+	input := &ec2.DescribeSpotPriceHistoryInput{
+		ProductDescriptions: []string{"Linux/UNIX"},
+		StartTime:           aws.Time(time.Now()),
+	}
+	for _, it := range instanceTypes {
+		input.InstanceTypes = append(input.InstanceTypes, ec2types.InstanceType(it))
+	}
+
+	out, err := ec2c.DescribeSpotPriceHistory(ctx, input)
+	if err != nil {
+		return false, fmt.Errorf("describe spot prices: %w", err)
+	}
+	for _, sp := range out.SpotPriceHistory {
+		if maxPrice == 0 {
+			return true, nil
+		}
+		price, err := strconv.ParseFloat(aws.ToString(sp.SpotPrice), 64)
+		if err == nil && price <= maxPrice {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fallBackToOnDemand records that the cluster's spot workers should be
+// created on-demand, so retries and cost reports see the shape installed
+func (h *CreateHandler) fallBackToOnDemand(ctx context.Context, cluster *types.Cluster) error {
+	overrides := types.ComputeOverrides{}
+	if cluster.ComputeOverrides != nil {
+		overrides = *cluster.ComputeOverrides
+	}
+	overrides.OnDemand = true
+
+	if err := h.store.Clusters.Update(ctx, cluster.ID, map[string]interface{}{"compute_overrides": &overrides}); err != nil {
+		return fmt.Errorf("record on-demand fallback: %w", err)
+	}
+	cluster.ComputeOverrides = &overrides
+	return nil
+}
+
+// preflightSpot runs the spot capacity check for AWS clusters with spot
+// workers and applies the on-demand fallback when the profile allows it
+func (h *CreateHandler) preflightSpot(ctx context.Context, cluster *types.Cluster, prof *profile.Profile, checker *AWSPreflightChecker) error {
+	if len(prof.SpotPools()) == 0 {
+		return nil
+	}
+	fallback, err := checker.CheckSpotCapacity(ctx, prof)
+	if err != nil {
+		return types.NewPreflightCheckError("AWS spot capacity pre-flight check failed: %v", err)
+	}
+	if fallback {
+		return h.fallBackToOnDemand(ctx, cluster)
+	}
+	return nil
+}
+
+// preflightEKS checks spot capacity for EKS spot node groups before eksctl
+// provisions anything
+func (h *CreateHandler) preflightEKS(ctx context.Context, cluster *types.Cluster) error {
+	prof, err := h.clusterProfile(cluster)
+	if err != nil {
+		return fmt.Errorf("get profile for pre-flight check: %w", err)
+	}
+	if len(prof.SpotPools()) == 0 {
+		return nil
+	}
+
+	checker, err := NewAWSPreflightChecker(ctx, cluster.Region)
+	if err != nil {
+		log.Printf("Warning: failed to create AWS pre-flight checker: %v", err)
+		return nil
+	}
+	return h.preflightSpot(ctx, cluster, prof, checker)
+}
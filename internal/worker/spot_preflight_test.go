@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/tsanders-rh/ocpctl/internal/profile"
+)
+
+type mockSpotEC2 struct {
+	prices map[string]string // instance type -> current spot price
+	err    error
+}
+
+func (m *mockSpotEC2) DescribeSpotPriceHistory(_ context.Context, in *ec2.DescribeSpotPriceHistoryInput, _ ...func(*ec2.Options)) (*ec2.DescribeSpotPriceHistoryOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out ec2.DescribeSpotPriceHistoryOutput
+	for _, it := range in.InstanceTypes {
+		if p, ok := m.prices[string(it)]; ok {
+			out.SpotPriceHistory = append(out.SpotPriceHistory, ec2types.SpotPrice{InstanceType: it, SpotPrice: aws.String(p)})
+		}
+	}
+	return &out, nil
+}
+
+func spotProfile(spot *profile.SpotConfig) *profile.Profile {
+	p := standardProfile()
+	p.Platform = "aws"
+	p.Compute.Workers.Spot = spot
+	return p
+}
+
+func TestRunSpotChecks(t *testing.T) {
+	tests := []struct {
+		name         string
+		prof         *profile.Profile
+		ec2          *mockSpotEC2
+		wantFallback bool
+		wantErr      string
+	}{
+		{"no spot pools", standardProfile(), &mockSpotEC2{}, false, ""},
+		{"spot offered", spotProfile(&profile.SpotConfig{}), &mockSpotEC2{prices: map[string]string{"m6i.2xlarge": "0.15"}}, false, ""},
+		{"offer under max price", spotProfile(&profile.SpotConfig{MaxPrice: "0.20"}),
+			&mockSpotEC2{prices: map[string]string{"m6i.2xlarge": "0.15"}}, false, ""},
+		{"offer over max price", spotProfile(&profile.SpotConfig{MaxPrice: "0.10"}),
+			&mockSpotEC2{prices: map[string]string{"m6i.2xlarge": "0.15"}}, false, "no spot capacity"},
+		{"no offer with fallback", spotProfile(&profile.SpotConfig{OnDemandFallback: true}), &mockSpotEC2{}, true, ""},
+		{"no offer without fallback", spotProfile(&profile.SpotConfig{}), &mockSpotEC2{}, false, "onDemandFallback"},
+		{"lookup error is not fatal", spotProfile(&profile.SpotConfig{}), &mockSpotEC2{err: errors.New("UnauthorizedOperation")}, false, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fallback, err := runSpotChecks(context.Background(), "us-east-1", tc.ec2, tc.prof)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("runSpotChecks() error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("runSpotChecks() error = %v, want containing %q", err, tc.wantErr)
+			}
+			if fallback != tc.wantFallback {
+				t.Fatalf("runSpotChecks() fallback = %v, want %v", fallback, tc.wantFallback)
+			}
+		})
+	}
+}
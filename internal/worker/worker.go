@@ -13,7 +13,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/tsanders-rh/ocpctl/internal/installer"
 	"github.com/tsanders-rh/ocpctl/internal/metrics"
 	"github.com/tsanders-rh/ocpctl/internal/profile"
 	"github.com/tsanders-rh/ocpctl/internal/store"
@@ -33,6 +32,21 @@ type Config struct {
 	MaxConcurrent int
 	RetryBackoff  time.Duration
 	MaxRetries    int
+
+	// PostConfigParallelism caps how many independent post-config DAG tasks
+	// run at once for a single cluster
+	PostConfigParallelism int
+
+	// Secrets reads post-config credentials from the secrets backend. Post-config
+	// tasks can only read secrets whose names start with PostConfigSecretPrefix,
+	// which is prepended to the name a task gives.
+	Secrets                SecretGetter
+	PostConfigSecretPrefix string
+}
+
+// SecretGetter reads a secret value by name
+type SecretGetter interface {
+	GetSecret(ctx context.Context, secretName string) (string, error)
 }
 
 // DefaultConfig returns default worker configuration
@@ -60,6 +74,9 @@ func DefaultConfig() *Config {
 		MaxConcurrent: 3,
 		RetryBackoff:  30 * time.Second,
 		MaxRetries:    3,
+
+		PostConfigParallelism:  4,
+		PostConfigSecretPrefix: "ocpctl/post-config/",
 	}
 }
 
@@ -543,6 +560,13 @@ func (w *Worker) processJob(ctx context.Context, job *types.Job, cluster *types.
 
 	span.SetAttributes(attrs...)
 
+	// Attribute cluster state changes made while processing this job
+	ctx = store.WithEventSource(ctx, store.EventSource{
+		Actor:  store.EventActorWorker,
+		Reason: fmt.Sprintf("%s job", job.JobType),
+		JobID:  job.ID,
+	})
+
 	// Check if context is already cancelled (worker shutting down)
 	select {
 	case <-ctx.Done():
@@ -609,8 +633,10 @@ func (w *Worker) processJob(ctx context.Context, job *types.Job, cluster *types.
 	// Track job start time for duration metrics
 	startTime := time.Now()
 
-	// Process the job
-	err = w.processor.Process(ctx, job)
+	// Process the job. Secrets the job resolves are recorded in its context
+	// and masked in the error so they never reach the job record.
+	ctx = withSecretMask(ctx)
+	err = secretMaskFromContext(ctx).MaskError(w.processor.Process(ctx, job))
 
 	// Calculate job duration
 	duration := time.Since(startTime)
@@ -735,6 +761,9 @@ func (w *Worker) handleJobSuccess(ctx context.Context, job *types.Job, cluster *
 
 // handleJobFailure handles job failure with retry logic
 func (w *Worker) handleJobFailure(ctx context.Context, job *types.Job, cluster *types.Cluster, jobErr error, duration time.Duration) {
+	// Record the failure as the reason for any status change made below
+	ctx = store.WithEventReason(ctx, fmt.Sprintf("%s job failed: %v", job.JobType, jobErr))
+
 	// Check if this is a "not ready" error - defer without incrementing attempts
 	if types.IsNotReadyError(jobErr) {
 		log.Printf("Job %s deferred: %v (will retry when ready)", job.ID, jobErr)
@@ -1042,33 +1071,13 @@ func (w *Worker) cleanupPartialDeployment(ctx context.Context, job *types.Job) {
 
 	log.Printf("Cleaning up partial deployment for job %s (cluster %s, type %s)", job.ID, job.ClusterID, cluster.ClusterType)
 
-	// Clean up DNS records for OpenShift clusters (have base domain)
-	if cluster.BaseDomain != nil && *cluster.BaseDomain != "" {
-		log.Printf("Cleaning up DNS records for cluster %s.%s", cluster.Name, *cluster.BaseDomain)
-		dnsCleaner := NewDNSCleaner(cluster.Region)
-		if err := dnsCleaner.CleanupClusterDNS(ctx, cluster.Name, *cluster.BaseDomain); err != nil {
-			log.Printf("Warning: DNS cleanup failed: %v", err)
-		} else {
-			log.Printf("Successfully cleaned up DNS records")
-		}
-	}
-
 	workDir := fmt.Sprintf("%s/%s", w.config.WorkDir, job.ClusterID)
 
-	// Route to appropriate cleanup based on cluster type
-	switch cluster.ClusterType {
-	case types.ClusterTypeOpenShift:
-		w.cleanupOpenShiftDeployment(ctx, job, cluster, workDir)
-	case types.ClusterTypeEKS:
-		w.cleanupEKSDeployment(ctx, job, cluster, workDir)
-	case types.ClusterTypeIKS:
-		w.cleanupIKSDeployment(ctx, job, cluster, workDir)
-	case types.ClusterTypeARO:
-		w.cleanupARODeployment(ctx, job, cluster, workDir)
-	case types.ClusterTypeAKS:
-		w.cleanupAKSDeployment(ctx, job, cluster, workDir)
-	default:
-		log.Printf("Unknown cluster type %s, skipping cleanup", cluster.ClusterType)
+	// The cluster type's provider knows what a failed create leaves behind
+	if p, err := w.processor.providers.Get(cluster.ClusterType); err != nil {
+		log.Printf("Warning: %v, skipping cleanup", err)
+	} else {
+		p.CleanupPartial(ctx, job, cluster, workDir)
 	}
 
 	// Remove work directory to ensure clean slate for retry
@@ -1077,160 +1086,6 @@ func (w *Worker) cleanupPartialDeployment(ctx context.Context, job *types.Job) {
 	}
 }
 
-// cleanupOpenShiftDeployment cleans up partial OpenShift deployment
-func (w *Worker) cleanupOpenShiftDeployment(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
-	// Check if work directory exists
-	if _, err := os.Stat(workDir); os.IsNotExist(err) {
-		log.Printf("Work directory %s does not exist, skipping openshift-install destroy", workDir)
-		return
-	}
-
-	// Use the destroy-tolerant installer: cleanup runs `openshift-install destroy`
-	// against metadata.json, so it must not hard-fail on a version outside the
-	// create-time allowlist — otherwise partial infrastructure from a failed
-	// create of such a version leaks with nothing to clean it up.
-	inst := installer.NewInstallerForDestroy(cluster.Version)
-
-	// Run openshift-install destroy to clean up partial infrastructure
-	output, err := inst.DestroyCluster(ctx, workDir)
-	if err != nil {
-		log.Printf("Warning: openshift-install destroy failed for job %s: %v\nOutput: %s", job.ID, err, output)
-	} else {
-		log.Printf("Successfully cleaned up OpenShift deployment for job %s", job.ID)
-	}
-}
-
-// cleanupEKSDeployment cleans up partial EKS deployment
-func (w *Worker) cleanupEKSDeployment(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
-	log.Printf("Cleaning up partial EKS deployment for cluster %s in region %s", cluster.Name, cluster.Region)
-
-	eksInstaller := installer.NewEKSInstaller()
-	output, err := eksInstaller.DestroyCluster(ctx, cluster.Name, cluster.Region)
-	if err != nil {
-		log.Printf("Warning: eksctl delete failed for job %s: %v\nOutput: %s", job.ID, err, output)
-	} else {
-		log.Printf("Successfully cleaned up EKS deployment for job %s", job.ID)
-	}
-}
-
-// cleanupIKSDeployment cleans up partial IKS deployment
-func (w *Worker) cleanupIKSDeployment(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
-	log.Printf("Cleaning up partial IKS deployment for cluster %s", cluster.Name)
-
-	iksInstaller := installer.NewIKSInstaller()
-
-	// Login to IBM Cloud
-	apiKey := os.Getenv("IBMCLOUD_API_KEY")
-	if apiKey == "" {
-		log.Printf("Warning: IBMCLOUD_API_KEY not set, cannot cleanup IKS cluster")
-		return
-	}
-
-	// Use empty resource group - Login will query for available resource groups
-	if err := iksInstaller.Login(ctx, apiKey, cluster.Region, ""); err != nil {
-		log.Printf("Warning: IBM Cloud login failed: %v", err)
-		return
-	}
-
-	output, err := iksInstaller.DestroyCluster(ctx, cluster.Name)
-	if err != nil {
-		log.Printf("Warning: IKS cluster destroy failed for job %s: %v\nOutput: %s", job.ID, err, output)
-	} else {
-		log.Printf("Successfully cleaned up IKS deployment for job %s", job.ID)
-	}
-}
-
-// cleanupARODeployment cleans up partial ARO deployment
-func (w *Worker) cleanupARODeployment(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
-	log.Printf("Cleaning up partial ARO deployment for cluster %s in region %s", cluster.Name, cluster.Region)
-
-	aroInstaller := installer.NewAROInstaller()
-
-	// Load metadata to get resource group name
-	metadata, err := aroInstaller.LoadMetadata(workDir)
-	if err != nil {
-		// If metadata not available, construct resource group name from cluster name
-		log.Printf("Warning: failed to load metadata, constructing resource group name")
-		metadata = map[string]string{
-			"resource_group": fmt.Sprintf("ocpctl-%s-rg", cluster.Name),
-		}
-	}
-
-	resourceGroup := metadata["resource_group"]
-
-	// Try to delete ARO cluster
-	log.Printf("Attempting to delete ARO cluster from resource group: %s", resourceGroup)
-	output, err := aroInstaller.DestroyCluster(ctx, resourceGroup, cluster.Name)
-	if err != nil {
-		// Check if resource not found - this means cluster doesn't exist
-		if isResourceNotFoundError(output) {
-			log.Printf("ARO cluster not found, already cleaned up")
-		} else {
-			log.Printf("Warning: ARO cluster delete failed for job %s: %v\nOutput: %s", job.ID, err, output)
-		}
-	} else {
-		log.Printf("Successfully deleted ARO cluster for job %s", job.ID)
-	}
-
-	// Try to delete resource group
-	log.Printf("Attempting to delete resource group: %s", resourceGroup)
-	if err := aroInstaller.DeleteResourceGroup(ctx, resourceGroup); err != nil {
-		if isResourceNotFoundError(err.Error()) {
-			log.Printf("Resource group not found, already cleaned up")
-		} else {
-			log.Printf("Warning: Resource group delete failed for job %s: %v", job.ID, err)
-		}
-	} else {
-		log.Printf("Successfully deleted resource group for job %s", job.ID)
-	}
-}
-
-// cleanupAKSDeployment cleans up partial AKS deployment
-func (w *Worker) cleanupAKSDeployment(ctx context.Context, job *types.Job, cluster *types.Cluster, workDir string) {
-	log.Printf("Cleaning up partial AKS deployment for cluster %s in region %s", cluster.Name, cluster.Region)
-
-	aksInstaller := installer.NewAKSInstaller()
-	aroInstaller := installer.NewAROInstaller()
-
-	// Load metadata to get resource group name
-	metadata, err := aroInstaller.LoadMetadata(workDir)
-	if err != nil {
-		// If metadata not available, construct resource group name from cluster name
-		log.Printf("Warning: failed to load metadata, constructing resource group name")
-		metadata = map[string]string{
-			"resource_group": fmt.Sprintf("ocpctl-%s-rg", cluster.Name),
-		}
-	}
-
-	resourceGroup := metadata["resource_group"]
-
-	// Try to delete AKS cluster
-	log.Printf("Attempting to delete AKS cluster from resource group: %s", resourceGroup)
-	output, err := aksInstaller.DestroyCluster(ctx, resourceGroup, cluster.Name)
-	if err != nil {
-		// Check if resource not found - this means cluster doesn't exist
-		if isResourceNotFoundError(output) {
-			log.Printf("AKS cluster not found, already cleaned up")
-		} else {
-			log.Printf("Warning: AKS cluster delete failed for job %s: %v\nOutput: %s", job.ID, err, output)
-		}
-	} else {
-		log.Printf("Successfully deleted AKS cluster for job %s", job.ID)
-	}
-
-	// Try to delete resource group
-	log.Printf("Attempting to delete resource group: %s", resourceGroup)
-	if err := aroInstaller.DeleteResourceGroup(ctx, resourceGroup); err != nil {
-		if isResourceNotFoundError(err.Error()) {
-			log.Printf("Resource group not found, already cleaned up")
-		} else {
-			log.Printf("Warning: Resource group delete failed for job %s: %v", job.ID, err)
-		}
-	} else {
-		log.Printf("Successfully deleted resource group for job %s", job.ID)
-	}
-}
-
 // cleanupTempFiles removes temporary files created by openshift-install
 func (w *Worker) cleanupTempFiles() {
 	tmpDir := os.Getenv("TMPDIR")
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ListAddonsOptions filters add-on listings
+type ListAddonsOptions struct {
+	Category string
+	Platform string
+	Profile  string // Only used by ListAddons
+	Search   string
+}
+
+func (o *ListAddonsOptions) query() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	setString(q, "category", o.Category)
+	setString(q, "platform", o.Platform)
+	setString(q, "profile", o.Profile)
+	setString(q, "search", o.Search)
+	return q
+}
+
+// ListAddons returns published add-ons grouped with their versions, as offered
+// at cluster creation
+func (c *Client) ListAddons(ctx context.Context, opts *ListAddonsOptions) (*types.AddonsListResponse, error) {
+	var out types.AddonsListResponse
+	if err := c.get(ctx, "/post-config/addons", opts.query(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAllAddons returns every add-on version as a flat list
+func (c *Client) ListAllAddons(ctx context.Context, opts *ListAddonsOptions) ([]*types.PostConfigAddon, error) {
+	q := opts.query()
+	q.Del("profile")
+	var out []*types.PostConfigAddon
+	if err := c.get(ctx, "/post-config/addons/all", q, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListMyAddons returns the custom add-ons created by the caller
+func (c *Client) ListMyAddons(ctx context.Context) ([]*types.PostConfigAddon, error) {
+	var out []*types.PostConfigAddon
+	if err := c.get(ctx, "/post-config/addons/my", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAddon returns an add-on by database ID
+func (c *Client) GetAddon(ctx context.Context, id string) (*types.PostConfigAddon, error) {
+	return c.addonCall(ctx, "GET", "/post-config/addons/%s", id, nil)
+}
+
+// CreateAddon creates a draft custom add-on
+func (c *Client) CreateAddon(ctx context.Context, req *types.CreateAddonRequest) (*types.PostConfigAddon, error) {
+	var out types.PostConfigAddon
+	if err := c.post(ctx, "/post-config/addons", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAddon changes a draft add-on
+func (c *Client) UpdateAddon(ctx context.Context, id string, req *types.UpdateAddonRequest) (*types.PostConfigAddon, error) {
+	return c.addonCall(ctx, "PUT", "/post-config/addons/%s", id, req)
+}
+
+// PublishAddon makes a draft add-on available for cluster creation
+func (c *Client) PublishAddon(ctx context.Context, id string) (*types.PostConfigAddon, error) {
+	return c.addonCall(ctx, "POST", "/post-config/addons/%s/publish", id, nil)
+}
+
+// CloneAddon creates a new draft version from an existing add-on
+func (c *Client) CloneAddon(ctx context.Context, id string) (*types.PostConfigAddon, error) {
+	return c.addonCall(ctx, "POST", "/post-config/addons/%s/clone", id, nil)
+}
+
+// DeleteAddon deletes an add-on
+func (c *Client) DeleteAddon(ctx context.Context, id string) error {
+	path, err := endpoint("/post-config/addons/%s", id)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+func (c *Client) addonCall(ctx context.Context, method, format, id string, in interface{}) (*types.PostConfigAddon, error) {
+	path, err := endpoint(format, id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.PostConfigAddon
+	if err := c.do(ctx, method, path, nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// InstallClusterAddon queues installation of an add-on on a READY cluster.
+// An empty version installs the add-on's default version.
+func (c *Client) InstallClusterAddon(ctx context.Context, clusterID, addonID, version string) (*types.JobAcceptedResponse, error) {
+	return c.clusterAddonCall(ctx, "POST", clusterID, addonID, &types.ClusterAddonRequest{Version: version})
+}
+
+// UpgradeClusterAddon queues a change of an installed add-on to another version/channel
+func (c *Client) UpgradeClusterAddon(ctx context.Context, clusterID, addonID, version string) (*types.JobAcceptedResponse, error) {
+	return c.clusterAddonCall(ctx, "PATCH", clusterID, addonID, &types.ClusterAddonRequest{Version: version})
+}
+
+// UninstallClusterAddon queues removal of an installed add-on from a cluster
+func (c *Client) UninstallClusterAddon(ctx context.Context, clusterID, addonID string) (*types.JobAcceptedResponse, error) {
+	return c.clusterAddonCall(ctx, "DELETE", clusterID, addonID, nil)
+}
+
+func (c *Client) clusterAddonCall(ctx context.Context, method, clusterID, addonID string, in interface{}) (*types.JobAcceptedResponse, error) {
+	path, err := endpoint("/clusters/%s/addons/%s", clusterID, addonID)
+	if err != nil {
+		return nil, err
+	}
+	var out types.JobAcceptedResponse
+	if err := c.do(ctx, method, path, nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetClusterDrift returns the result of a cluster's most recent post-config
+// drift check
+func (c *Client) GetClusterDrift(ctx context.Context, clusterID string) (*types.ConfigDriftReport, error) {
+	path, err := endpoint("/clusters/%s/drift", clusterID)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ConfigDriftReport
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CheckClusterDrift queues a drift check of a cluster's add-ons and custom
+// post-config. With reconcile set, drifted tasks are re-applied.
+func (c *Client) CheckClusterDrift(ctx context.Context, clusterID string, reconcile bool) (*types.JobAcceptedResponse, error) {
+	path, err := endpoint("/clusters/%s/drift-check", clusterID)
+	if err != nil {
+		return nil, err
+	}
+	var out types.JobAcceptedResponse
+	if err := c.post(ctx, path, &types.DriftCheckRequest{Reconcile: reconcile}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ValidatePostConfig validates a custom post-configuration without applying it
+func (c *Client) ValidatePostConfig(ctx context.Context, req *types.ValidatePostConfigRequest) (*types.ValidatePostConfigResponse, error) {
+	var out types.ValidatePostConfigResponse
+	if err := c.post(ctx, "/post-config/validate", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ListRateLimitPolicies returns the per-principal rate limit policies (admin only)
+func (c *Client) ListRateLimitPolicies(ctx context.Context) ([]*types.RateLimitPolicy, error) {
+	var out struct {
+		Policies []*types.RateLimitPolicy `json:"policies"`
+	}
+	if err := c.get(ctx, "/admin/rate-limits", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Policies, nil
+}
+
+// CreateRateLimitPolicy creates a rate limit policy (admin only)
+func (c *Client) CreateRateLimitPolicy(ctx context.Context, req *types.CreateRateLimitPolicyRequest) (*types.RateLimitPolicy, error) {
+	var out types.RateLimitPolicy
+	if err := c.post(ctx, "/admin/rate-limits", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateRateLimitPolicy changes a rate limit policy (admin only)
+func (c *Client) UpdateRateLimitPolicy(ctx context.Context, id string, req *types.UpdateRateLimitPolicyRequest) (*types.RateLimitPolicy, error) {
+	path, err := endpoint("/admin/rate-limits/%s", id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.RateLimitPolicy
+	if err := c.patch(ctx, path, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteRateLimitPolicy deletes a rate limit policy (admin only)
+func (c *Client) DeleteRateLimitPolicy(ctx context.Context, id string) error {
+	path, err := endpoint("/admin/rate-limits/%s", id)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+// ListOrphanedResourcesOptions filters ListOrphanedResources
+type ListOrphanedResourcesOptions struct {
+	Status types.OrphanedResourceStatus
+	Type   types.OrphanedResourceType
+	Region string
+	Limit  int
+	Offset int
+}
+
+// ListOrphanedResources returns cloud resources left behind by destroyed clusters (admin only)
+func (c *Client) ListOrphanedResources(ctx context.Context, opts *ListOrphanedResourcesOptions) (*types.OrphanedResourceListResponse, error) {
+	q := url.Values{}
+	if opts != nil {
+		setString(q, "status", string(opts.Status))
+		setString(q, "type", string(opts.Type))
+		setString(q, "region", opts.Region)
+		setInt(q, "limit", opts.Limit)
+		setInt(q, "offset", opts.Offset)
+	}
+	var out types.OrphanedResourceListResponse
+	if err := c.get(ctx, "/admin/orphaned-resources", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOrphanedResourceStats returns orphaned resource counts (admin only)
+func (c *Client) GetOrphanedResourceStats(ctx context.Context) (*types.OrphanedResourceStats, error) {
+	var out types.OrphanedResourceStats
+	if err := c.get(ctx, "/admin/orphaned-resources/stats", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResolveOrphanedResource marks an orphaned resource as cleaned up (admin only)
+func (c *Client) ResolveOrphanedResource(ctx context.Context, id string, req *types.MarkResolvedRequest) (*types.OrphanedResource, error) {
+	return c.orphanedCall(ctx, "PATCH", "/admin/orphaned-resources/%s/resolve", id, req)
+}
+
+// IgnoreOrphanedResource marks an orphaned resource as intentionally kept (admin only)
+func (c *Client) IgnoreOrphanedResource(ctx context.Context, id string, req *types.MarkIgnoredRequest) (*types.OrphanedResource, error) {
+	return c.orphanedCall(ctx, "PATCH", "/admin/orphaned-resources/%s/ignore", id, req)
+}
+
+// DeleteOrphanedResource deletes the cloud resource behind an orphaned resource record (admin only)
+func (c *Client) DeleteOrphanedResource(ctx context.Context, id string) (*types.OrphanedResource, error) {
+	return c.orphanedCall(ctx, "DELETE", "/admin/orphaned-resources/%s", id, nil)
+}
+
+func (c *Client) orphanedCall(ctx context.Context, method, format, id string, in interface{}) (*types.OrphanedResource, error) {
+	path, err := endpoint(format, id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.OrphanedResource
+	if err := c.do(ctx, method, path, nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMetrics returns a snapshot of API, worker, job and cluster metrics (admin only)
+func (c *Client) GetMetrics(ctx context.Context) (*types.MetricsSnapshot, error) {
+	var out types.MetricsSnapshot
+	if err := c.get(ctx, "/admin/metrics/current", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetInfrastructure returns the worker fleet and autoscaling state (admin only)
+func (c *Client) GetInfrastructure(ctx context.Context) (*types.InfrastructureInfo, error) {
+	var out types.InfrastructureInfo
+	if err := c.get(ctx, "/admin/system/infrastructure", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetInstalledVersions returns the installer and CLI versions on the workers (admin only)
+func (c *Client) GetInstalledVersions(ctx context.Context) (*types.InstalledVersionsResponse, error) {
+	var out types.InstalledVersionsResponse
+	if err := c.get(ctx, "/admin/installed-versions", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUsageReport returns cluster usage and cost between start and end,
+// inclusive (admin only). Zero times use the server's default of the last 30 days.
+func (c *Client) GetUsageReport(ctx context.Context, start, end time.Time) (*types.UsageReport, error) {
+	q := url.Values{}
+	if !start.IsZero() {
+		q.Set("start_date", start.Format("2006-01-02"))
+	}
+	if !end.IsZero() {
+		q.Set("end_date", end.Format("2006-01-02"))
+	}
+	var out types.UsageReport
+	if err := c.get(ctx, "/admin/reports/usage", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListWindowsSnapshots returns Windows AMI snapshots, optionally filtered by
+// region and status (admin only)
+func (c *Client) ListWindowsSnapshots(ctx context.Context, region string, status types.WindowsSnapshotStatus) ([]*types.WindowsSnapshot, error) {
+	q := url.Values{}
+	setString(q, "region", region)
+	setString(q, "status", string(status))
+	var out struct {
+		Snapshots []*types.WindowsSnapshot `json:"snapshots"`
+	}
+	if err := c.get(ctx, "/admin/windows-snapshots", q, &out); err != nil {
+		return nil, err
+	}
+	return out.Snapshots, nil
+}
+
+// GetWindowsSnapshotCoverage reports which regions have a snapshot of version (admin only)
+func (c *Client) GetWindowsSnapshotCoverage(ctx context.Context, version string) (*types.WindowsSnapshotCoverage, error) {
+	q := url.Values{}
+	setString(q, "version", version)
+	var out types.WindowsSnapshotCoverage
+	if err := c.get(ctx, "/admin/windows-snapshots/coverage", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWindowsSnapshot returns a Windows snapshot (admin only)
+func (c *Client) GetWindowsSnapshot(ctx context.Context, id string) (*types.WindowsSnapshot, error) {
+	path, err := endpoint("/admin/windows-snapshots/%s", id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.WindowsSnapshot
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateWindowsSnapshot starts building a Windows snapshot in a region (admin only)
+func (c *Client) CreateWindowsSnapshot(ctx context.Context, req *types.CreateWindowsSnapshotRequest) (*types.CreateWindowsSnapshotResponse, error) {
+	var out types.CreateWindowsSnapshotResponse
+	if err := c.post(ctx, "/admin/windows-snapshots", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteWindowsSnapshot deletes a Windows snapshot (admin only)
+func (c *Client) DeleteWindowsSnapshot(ctx context.Context, id string) error {
+	path, err := endpoint("/admin/windows-snapshots/%s", id)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
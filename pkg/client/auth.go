@@ -0,0 +1,190 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// Authenticator adds credentials to outgoing requests
+type Authenticator interface {
+	// Authorize is called for every attempt of a request. body is the
+	// request payload, for authenticators that sign it.
+	Authorize(ctx context.Context, req *http.Request, body []byte) error
+}
+
+// BearerToken authenticates with a fixed bearer token, such as an access
+// token obtained elsewhere
+type BearerToken string
+
+// Authorize implements Authenticator
+func (t BearerToken) Authorize(_ context.Context, req *http.Request, _ []byte) error {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// APIKey authenticates with an ocpctl API key ("ocpctl_...")
+func APIKey(key string) Authenticator {
+	return BearerToken(key)
+}
+
+// tokenRefreshMargin refreshes access tokens this long before they expire
+const tokenRefreshMargin = 30 * time.Second
+
+// PasswordAuth logs in with an email and password and keeps the resulting
+// access token fresh. Tokens are renewed with the refresh-token cookie and,
+// when that has expired, by logging in again.
+type PasswordAuth struct {
+	email    string
+	password string
+
+	mu         sync.Mutex
+	client     *Client
+	httpClient *http.Client
+	token      string
+	expiresAt  time.Time
+}
+
+// NewPasswordAuth creates an authenticator for a local ocpctl account
+func NewPasswordAuth(email, password string) *PasswordAuth {
+	return &PasswordAuth{email: email, password: password}
+}
+
+// bind is called by New so the authenticator can reach the auth endpoints
+func (a *PasswordAuth) bind(c *Client) {
+	jar, _ := cookiejar.New(nil)
+	a.client = c
+	a.httpClient = &http.Client{
+		Transport: c.httpClient.Transport,
+		Timeout:   c.httpClient.Timeout,
+		Jar:       jar,
+	}
+}
+
+// Authorize implements Authenticator
+func (a *PasswordAuth) Authorize(ctx context.Context, req *http.Request, _ []byte) error {
+	token, err := a.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// invalidate drops the cached token after the server rejected it
+func (a *PasswordAuth) invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = ""
+}
+
+// Logout revokes the refresh token and forgets the access token
+func (a *PasswordAuth) Logout(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.client == nil {
+		return nil
+	}
+	a.token = ""
+	_, err := a.call(ctx, "/auth/logout", nil)
+	return err
+}
+
+func (a *PasswordAuth) accessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.client == nil {
+		return "", fmt.Errorf("password auth is not attached to a client; pass it to New with WithAuth")
+	}
+	if a.token != "" && time.Until(a.expiresAt) > tokenRefreshMargin {
+		return a.token, nil
+	}
+
+	resp, err := a.call(ctx, "/auth/refresh", nil)
+	if err != nil {
+		resp, err = a.call(ctx, "/auth/login", &types.LoginRequest{Email: a.email, Password: a.password})
+		if err != nil {
+			return "", fmt.Errorf("log in as %s: %w", a.email, err)
+		}
+	}
+
+	a.token = resp.AccessToken
+	a.expiresAt = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	return a.token, nil
+}
+
+// call posts to an auth endpoint with the cookie jar that holds the refresh token
+func (a *PasswordAuth) call(ctx context.Context, path string, in interface{}) (*types.LoginResponse, error) {
+	var body []byte
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.client.url(path, nil), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", a.client.userAgent)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var out types.LoginResponse
+	if err := decodeResponse(resp, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SigV4Service is the service name requests are signed for, matching
+// curl --aws-sigv4 "aws:amz:<region>:execute-api"
+const SigV4Service = "execute-api"
+
+// SigV4Auth signs requests with AWS credentials for ocpctl's IAM authentication
+type SigV4Auth struct {
+	credentials aws.CredentialsProvider
+	region      string
+	signer      *v4.Signer
+}
+
+// NewSigV4Auth creates an authenticator that signs requests with AWS SigV4.
+// Use config.LoadDefaultConfig to obtain credentials from the environment,
+// shared config or an instance role.
+func NewSigV4Auth(credentials aws.CredentialsProvider, region string) *SigV4Auth {
+	return &SigV4Auth{
+		credentials: credentials,
+		region:      region,
+		signer:      v4.NewSigner(),
+	}
+}
+
+// Authorize implements Authenticator
+func (a *SigV4Auth) Authorize(ctx context.Context, req *http.Request, body []byte) error {
+	creds, err := a.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieve AWS credentials: %w", err)
+	}
+
+	hash := sha256.Sum256(body)
+	return a.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), SigV4Service, a.region, time.Now())
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ListCatalogPackages returns the packages of an OperatorHub catalog for an
+// OpenShift version, e.g. "4.18". A non-empty search filters by name, display
+// name and description. The API answers 503 while the catalog is still being
+// cached; those responses are retried like other unavailable errors.
+func (c *Client) ListCatalogPackages(ctx context.Context, catalog, version, search string) (*types.CatalogPackagesResponse, error) {
+	path, err := endpoint("/post-config/catalog/%s/packages", catalog)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	setString(q, "version", version)
+	setString(q, "search", search)
+	var out types.CatalogPackagesResponse
+	if err := c.get(ctx, path, q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCatalogPackage returns a catalog package with its channels and the
+// examples of one channel. An empty channel selects the package's default.
+func (c *Client) GetCatalogPackage(ctx context.Context, catalog, pkg, version, channel string) (*types.CatalogPackageDetail, error) {
+	path, err := endpoint("/post-config/catalog/%s/packages/%s", catalog, pkg)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	setString(q, "version", version)
+	setString(q, "channel", channel)
+	var out types.CatalogPackageDetail
+	if err := c.get(ctx, path, q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GenerateCatalogAddon creates a draft add-on that installs an operator from
+// an OperatorHub catalog
+func (c *Client) GenerateCatalogAddon(ctx context.Context, req *types.GenerateCatalogAddonRequest) (*types.PostConfigAddon, error) {
+	var out types.PostConfigAddon
+	if err := c.post(ctx, "/post-config/catalog/addons", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
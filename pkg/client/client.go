@@ -0,0 +1,374 @@
+// Package client is a Go client for the ocpctl REST API.
+//
+// It wraps the /api/v1 routes with the request and response types from
+// pkg/types, authenticates with a password (JWT with automatic refresh), an
+// API key or AWS SigV4, retries transient failures and sends an
+// Idempotency-Key with every POST so retried creates are not duplicated.
+//
+//	c, err := client.New("https://ocpctl.example.com", client.WithAuth(client.APIKey(key)))
+//	cluster, err := c.CreateCluster(ctx, &types.CreateClusterAPIRequest{...})
+//	cluster, err = c.WaitForClusterStatus(ctx, cluster.ID, []types.ClusterStatus{types.ClusterStatusReady})
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// APIVersion is the API version this client targets
+	APIVersion = "v1"
+
+	// DefaultUserAgent is sent when WithUserAgent is not used
+	DefaultUserAgent = "ocpctl-go-client/" + APIVersion
+
+	headerIdempotencyKey = "Idempotency-Key"
+)
+
+// RetryPolicy controls how transient failures are retried.
+//
+// Connection errors, 502, 503 and 504 responses, and 409 responses for an
+// Idempotency-Key still in use are retried for requests that are safe to
+// repeat: every method except POST, and POST requests that carry an
+// Idempotency-Key. 429 responses are always retried after the delay the
+// server asks for.
+type RetryPolicy struct {
+	MaxRetries int           // Retries after the first attempt; 0 disables retries
+	MinBackoff time.Duration // Delay before the first retry
+	MaxBackoff time.Duration // Upper bound for any single delay
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: 500 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// Client is an ocpctl API client. It is safe for concurrent use.
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	auth        Authenticator
+	retry       RetryPolicy
+	userAgent   string
+	idempotency bool
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithAuth sets how requests are authenticated
+func WithAuth(a Authenticator) Option {
+	return func(c *Client) { c.auth = a }
+}
+
+// WithRetryPolicy replaces the default retry policy
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithUserAgent sets the User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// WithoutIdempotencyKeys stops the client generating an Idempotency-Key for
+// POST requests. Keys set with WithIdempotencyKey are still sent.
+func WithoutIdempotencyKeys() Option {
+	return func(c *Client) { c.idempotency = false }
+}
+
+// New creates a client for the ocpctl server at baseURL, e.g.
+// "https://ocpctl.example.com". The /api/v1 prefix is added if missing.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("parse base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("base URL must be http or https, got %q", baseURL)
+	}
+	if !strings.HasSuffix(u.Path, "/api/"+APIVersion) {
+		u.Path += "/api/" + APIVersion
+	}
+
+	c := &Client{
+		baseURL:     u,
+		httpClient:  &http.Client{Timeout: 60 * time.Second},
+		retry:       DefaultRetryPolicy(),
+		userAgent:   DefaultUserAgent,
+		idempotency: true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if b, ok := c.auth.(interface{ bind(*Client) }); ok {
+		b.bind(c)
+	}
+
+	return c, nil
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context that sends key as the Idempotency-Key
+// of the next request made with it. Use a stable key (for example derived
+// from a resource's desired name) to make a create safe to repeat across
+// process restarts; otherwise the client generates one per call.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the key set with WithIdempotencyKey, or ""
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// rawBody is a request body sent as-is instead of being encoded as JSON
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+// get, post, put, patch and delete are shorthands for do
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+func (c *Client) post(ctx context.Context, path string, in, out interface{}) error {
+	return c.do(ctx, http.MethodPost, path, nil, in, out)
+}
+
+func (c *Client) put(ctx context.Context, path string, in, out interface{}) error {
+	return c.do(ctx, http.MethodPut, path, nil, in, out)
+}
+
+func (c *Client) patch(ctx context.Context, path string, in, out interface{}) error {
+	return c.do(ctx, http.MethodPatch, path, nil, in, out)
+}
+
+func (c *Client) delete(ctx context.Context, path string, out interface{}) error {
+	return c.do(ctx, http.MethodDelete, path, nil, nil, out)
+}
+
+// do sends a request to path (relative to /api/v1) and decodes the JSON
+// response into out. out may be nil, or a *[]byte to receive the raw body.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	var body []byte
+	contentType := ""
+	switch v := in.(type) {
+	case nil:
+	case rawBody:
+		body, contentType = v.data, v.contentType
+	default:
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		body, contentType = data, "application/json"
+	}
+
+	idempotencyKey := ""
+	if method == http.MethodPost {
+		if key := IdempotencyKeyFromContext(ctx); key != "" {
+			idempotencyKey = key
+		} else if c.idempotency {
+			idempotencyKey = uuid.New().String()
+		}
+	}
+	safeToRepeat := method != http.MethodPost || idempotencyKey != ""
+
+	target := c.url(path, query)
+	reauthenticated := false
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("build request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if idempotencyKey != "" {
+			req.Header.Set(headerIdempotencyKey, idempotencyKey)
+		}
+		if c.auth != nil {
+			if err := c.auth.Authorize(ctx, req, body); err != nil {
+				return fmt.Errorf("authenticate: %w", err)
+			}
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if safeToRepeat && attempt < c.retry.MaxRetries {
+				if err := sleep(ctx, c.backoff(attempt)); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("%s %s: %w", method, path, err)
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			if r, ok := c.auth.(interface{ invalidate() }); ok {
+				drain(resp)
+				r.invalidate()
+				reauthenticated = true
+				attempt--
+				continue
+			}
+		}
+
+		if attempt < c.retry.MaxRetries && retryable(resp, safeToRepeat) {
+			delay := c.backoff(attempt)
+			if after, ok := retryAfter(resp); ok {
+				delay = min(after, c.retry.MaxBackoff)
+			}
+			drain(resp)
+			if err := sleep(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		return decodeResponse(resp, out)
+	}
+}
+
+func (c *Client) url(path string, query url.Values) string {
+	u := *c.baseURL
+	u.Path = strings.TrimRight(u.Path, "/") + path
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.retry.MinBackoff << attempt
+	if d <= 0 || d > c.retry.MaxBackoff {
+		d = c.retry.MaxBackoff
+	}
+	// Equal jitter: half fixed, half random, so retries from many clients spread out
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+func retryable(resp *http.Response, safeToRepeat bool) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		// Rate limiting rejects a request before it is handled
+		return true
+	case http.StatusConflict:
+		// The server is still handling an earlier attempt with the same Idempotency-Key
+		return safeToRepeat && resp.Header.Get("Retry-After") != ""
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return safeToRepeat
+	}
+	return false
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+func decodeResponse(resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return newAPIError(resp, data)
+	}
+
+	switch v := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*v = data
+		return nil
+	}
+
+	if resp.StatusCode == http.StatusNoContent || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+// endpoint builds an API path from format, escaping each path segment.
+// Empty segments are rejected so a missing ID cannot address a collection.
+func endpoint(format string, segments ...string) (string, error) {
+	args := make([]interface{}, len(segments))
+	for i, seg := range segments {
+		if seg == "" {
+			return "", errors.New("path parameter must not be empty")
+		}
+		args[i] = url.PathEscape(seg)
+	}
+	return fmt.Sprintf(format, args...), nil
+}
@@ -0,0 +1,443 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+func fastRetries() Option {
+	return WithRetryPolicy(RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestNewAddsAPIPrefix(t *testing.T) {
+	c, err := New("https://ocpctl.example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://ocpctl.example.com/api/v1/clusters", c.url("/clusters", nil))
+
+	c, err = New("https://ocpctl.example.com/api/v1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://ocpctl.example.com/api/v1/clusters", c.url("/clusters", nil))
+
+	_, err = New("ftp://ocpctl.example.com")
+	assert.Error(t, err)
+}
+
+func TestCreateClusterRetriesWithSameIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/clusters", r.URL.Path)
+		assert.Equal(t, "Bearer ocpctl_key", r.Header.Get("Authorization"))
+
+		var req types.CreateClusterAPIRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "my-cluster", req.Name)
+
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		attempt := len(keys)
+		mu.Unlock()
+
+		if attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, http.StatusCreated, types.Cluster{ID: "c-1", Name: req.Name, Status: types.ClusterStatusPending})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithAuth(APIKey("ocpctl_key")), fastRetries())
+	require.NoError(t, err)
+
+	cluster, err := c.CreateCluster(context.Background(), &types.CreateClusterAPIRequest{Name: "my-cluster"})
+	require.NoError(t, err)
+	assert.Equal(t, "c-1", cluster.ID)
+
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+}
+
+func TestPostWithoutIdempotencyKeyIsNotRetried(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		assert.Empty(t, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithoutIdempotencyKeys(), fastRetries())
+	require.NoError(t, err)
+
+	_, err = c.HibernateCluster(context.Background(), "c-1")
+	assert.Equal(t, http.StatusBadGateway, StatusCode(err))
+	assert.Equal(t, 1, attempts)
+}
+
+func TestWithIdempotencyKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "create-team-a", r.Header.Get("Idempotency-Key"))
+		writeJSON(w, http.StatusCreated, types.Team{Name: "team-a"})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	ctx := WithIdempotencyKey(context.Background(), "create-team-a")
+	_, err = c.CreateTeam(ctx, &types.CreateTeamRequest{Name: "team-a"})
+	require.NoError(t, err)
+}
+
+func TestRetryAfterOnRateLimit(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"message": "rate limit exceeded"})
+			return
+		}
+		writeJSON(w, http.StatusOK, types.Job{ID: "j-1", Status: types.JobStatusRunning})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, fastRetries())
+	require.NoError(t, err)
+
+	job, err := c.GetJob(context.Background(), "j-1")
+	require.NoError(t, err)
+	assert.Equal(t, types.JobStatusRunning, job.Status)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not_found", "message": "Cluster not found"})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	_, err = c.GetCluster(context.Background(), "missing")
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+
+	apiErr, ok := err.(*APIError)
+	require.True(t, ok)
+	assert.Equal(t, "not_found", apiErr.Code)
+	assert.Equal(t, "Cluster not found", apiErr.Message)
+	assert.Equal(t, "req-1", apiErr.RequestID)
+}
+
+func TestEmptyPathParameterRejected(t *testing.T) {
+	c, err := New("https://ocpctl.example.com")
+	require.NoError(t, err)
+
+	_, err = c.DeleteCluster(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestPasswordAuthRefreshesAfterUnauthorized(t *testing.T) {
+	var mu sync.Mutex
+	logins, refreshes := 0, 0
+	valid := "token-2"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/v1/auth/login":
+			logins++
+			var req types.LoginRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "dev@example.com", req.Email)
+			http.SetCookie(w, &http.Cookie{Name: "refresh_token", Value: "rt", Path: "/api/v1/auth"})
+			writeJSON(w, http.StatusOK, types.LoginResponse{AccessToken: "token-1", ExpiresIn: 900})
+		case "/api/v1/auth/refresh":
+			if _, err := r.Cookie("refresh_token"); err != nil {
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			refreshes++
+			writeJSON(w, http.StatusOK, types.LoginResponse{AccessToken: valid, ExpiresIn: 900})
+		case "/api/v1/auth/me":
+			if r.Header.Get("Authorization") != "Bearer "+valid {
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			writeJSON(w, http.StatusOK, types.UserResponse{Email: "dev@example.com"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithAuth(NewPasswordAuth("dev@example.com", "secret")))
+	require.NoError(t, err)
+
+	// The first token is rejected, so the client refreshes with the cookie and retries
+	me, err := c.GetMe(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "dev@example.com", me.Email)
+	assert.Equal(t, 1, logins)
+	assert.Equal(t, 1, refreshes)
+}
+
+func TestSigV4AuthSignsRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authz := r.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(authz, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), authz)
+		assert.Contains(t, authz, "/us-east-1/execute-api/aws4_request")
+		assert.NotEmpty(t, r.Header.Get("X-Amz-Date"))
+		writeJSON(w, http.StatusOK, types.UserResponse{Email: "role@example.com"})
+	}))
+	defer srv.Close()
+
+	creds := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, nil
+	})
+	c, err := New(srv.URL, WithAuth(NewSigV4Auth(creds, "us-east-1")))
+	require.NoError(t, err)
+
+	_, err = c.GetMe(context.Background())
+	require.NoError(t, err)
+}
+
+func TestWaitForClusterStatus(t *testing.T) {
+	statuses := []types.ClusterStatus{types.ClusterStatusPending, types.ClusterStatusCreating, types.ClusterStatusReady}
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[min(polls, len(statuses)-1)]
+		polls++
+		writeJSON(w, http.StatusOK, types.Cluster{ID: "c-1", Status: status})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	cluster, err := c.WaitForClusterStatus(context.Background(), "c-1", []types.ClusterStatus{types.ClusterStatusReady}, WithPollInterval(time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, types.ClusterStatusReady, cluster.Status)
+	assert.Equal(t, 3, polls)
+}
+
+func TestWaitForClusterStatusFailed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, types.Cluster{ID: "c-1", Status: types.ClusterStatusFailed})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	_, err = c.WaitForClusterStatus(context.Background(), "c-1", []types.ClusterStatus{types.ClusterStatusReady}, WithPollInterval(time.Millisecond))
+	var statusErr *UnexpectedStatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, "FAILED", statusErr.Status)
+}
+
+func TestWaitForClusterDestroyedTreatsNotFoundAsDone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not_found"})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	cluster, err := c.WaitForClusterStatus(context.Background(), "c-1", []types.ClusterStatus{types.ClusterStatusDestroyed}, WithPollInterval(time.Millisecond))
+	require.NoError(t, err)
+	assert.Nil(t, cluster)
+}
+
+func TestWaitForJobStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		msg := "install timed out"
+		writeJSON(w, http.StatusOK, types.Job{ID: "j-1", Status: types.JobStatusFailed, ErrorMessage: &msg})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	_, err = c.WaitForJobStatus(context.Background(), "j-1", []types.JobStatus{types.JobStatusSucceeded}, WithPollInterval(time.Millisecond))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "install timed out")
+}
+
+func TestStreamEventsResumesFromLastEventID(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		conn := len(lastEventIDs)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for id := int64(conn*2 - 1); id <= int64(conn*2); id++ {
+			data, _ := json.Marshal(types.ClusterEvent{ID: id, ClusterID: "c-1", Type: types.ClusterEventStatusChanged})
+			fmt.Fprintf(w, ": heartbeat\n\nid: %d\nevent: %s\ndata: %s\n\n", id, types.ClusterEventStatusChanged, data)
+		}
+		// Returning closes the connection, which the client treats as a dropped stream
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, fastRetries())
+	require.NoError(t, err)
+
+	var got []int64
+	err = c.StreamEvents(context.Background(), -1, func(e *types.ClusterEvent) error {
+		got = append(got, e.ID)
+		if len(got) == 4 {
+			return ErrStopStream
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4}, got)
+	assert.Equal(t, []string{"", "2"}, lastEventIDs)
+}
+
+func TestPutClusterSpecDocumentSendsRawBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v1/cluster-specs/team-a/document", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("dry_run"))
+		assert.Equal(t, ContentTypeYAML, r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "kind: ClusterSpec\n", string(body))
+		writeJSON(w, http.StatusOK, types.ClusterSpecSyncResult{})
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	_, err = c.PutClusterSpecDocument(context.Background(), "team-a", []byte("kind: ClusterSpec\n"), ContentTypeYAML, true)
+	require.NoError(t, err)
+}
+
+func TestCheckClusterDriftRetriesWithSameIdempotencyKey(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/clusters/c-1/drift-check":
+			assert.Equal(t, http.MethodPost, r.Method)
+			var req types.DriftCheckRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.True(t, req.Reconcile)
+
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			if len(keys) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			writeJSON(w, http.StatusAccepted, types.JobAcceptedResponse{Message: "Drift check queued", JobID: "j-1"})
+		case "/api/v1/clusters/c-1/drift":
+			assert.Equal(t, http.MethodGet, r.Method)
+			writeJSON(w, http.StatusOK, types.ConfigDriftReport{ClusterID: "c-1", Checked: 3})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, fastRetries())
+	require.NoError(t, err)
+
+	accepted, err := c.CheckClusterDrift(context.Background(), "c-1", true)
+	require.NoError(t, err)
+	assert.Equal(t, "j-1", accepted.JobID)
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+
+	report, err := c.GetClusterDrift(context.Background(), "c-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Checked)
+}
+
+func TestCatalogCalls(t *testing.T) {
+	var listAttempts int
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/post-config/catalog/redhat-operators/packages":
+			assert.Equal(t, "4.18", r.URL.Query().Get("version"))
+			assert.Equal(t, "oadp", r.URL.Query().Get("search"))
+			listAttempts++
+			if listAttempts == 1 {
+				// The catalog is still being cached
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			writeJSON(w, http.StatusOK, types.CatalogPackagesResponse{Catalog: "redhat-operators", Total: 1})
+		case "/api/v1/post-config/catalog/redhat-operators/packages/redhat-oadp-operator":
+			assert.Equal(t, "4.18", r.URL.Query().Get("version"))
+			assert.False(t, r.URL.Query().Has("channel"))
+			writeJSON(w, http.StatusOK, types.CatalogPackageDetail{Channel: "stable"})
+		case "/api/v1/post-config/catalog/addons":
+			var req types.GenerateCatalogAddonRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "redhat-oadp-operator", req.Package)
+			keys = append(keys, r.Header.Get("Idempotency-Key"))
+			if len(keys) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			writeJSON(w, http.StatusCreated, types.PostConfigAddon{AddonID: "redhat-oadp-operator"})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, fastRetries())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	packages, err := c.ListCatalogPackages(ctx, "redhat-operators", "4.18", "oadp")
+	require.NoError(t, err)
+	assert.Equal(t, 1, packages.Total)
+	assert.Equal(t, 2, listAttempts)
+
+	detail, err := c.GetCatalogPackage(ctx, "redhat-operators", "redhat-oadp-operator", "4.18", "")
+	require.NoError(t, err)
+	assert.Equal(t, "stable", detail.Channel)
+
+	addon, err := c.GenerateCatalogAddon(ctx, &types.GenerateCatalogAddonRequest{
+		Version: "4.18", Catalog: "redhat-operators", Package: "redhat-oadp-operator", Category: "backup",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "redhat-oadp-operator", addon.AddonID)
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// Content types accepted for cluster spec documents
+const (
+	ContentTypeYAML   = "application/yaml"
+	ContentTypeJSON   = "application/json"
+	ContentTypeBundle = "application/gzip" // .tar.gz of spec files
+)
+
+// PlanClusterSpec computes the plan for a document without registering it.
+// contentType is one of ContentTypeYAML, ContentTypeJSON or ContentTypeBundle.
+func (c *Client) PlanClusterSpec(ctx context.Context, document []byte, contentType string) (*types.ClusterSpecSyncResult, error) {
+	var out types.ClusterSpecSyncResult
+	if err := c.post(ctx, "/cluster-specs/plan", rawBody{contentType: contentType, data: document}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListClusterSpecs returns the cluster specs visible to the caller
+func (c *Client) ListClusterSpecs(ctx context.Context) ([]*types.ClusterSpec, error) {
+	var out struct {
+		Specs []*types.ClusterSpec `json:"specs"`
+	}
+	if err := c.get(ctx, "/cluster-specs", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Specs, nil
+}
+
+// CreateClusterSpec registers a cluster spec
+func (c *Client) CreateClusterSpec(ctx context.Context, req *types.CreateClusterSpecRequest) (*types.ClusterSpec, error) {
+	var out types.ClusterSpec
+	if err := c.post(ctx, "/cluster-specs", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetClusterSpec returns a spec with its last plan and managed resources
+func (c *Client) GetClusterSpec(ctx context.Context, name string) (*types.ClusterSpecDetail, error) {
+	path, err := endpoint("/cluster-specs/%s", name)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ClusterSpecDetail
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateClusterSpec changes a spec's git and sync settings
+func (c *Client) UpdateClusterSpec(ctx context.Context, name string, req *types.UpdateClusterSpecRequest) (*types.ClusterSpec, error) {
+	path, err := endpoint("/cluster-specs/%s", name)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ClusterSpec
+	if err := c.patch(ctx, path, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteClusterSpec unregisters a spec. Its resources are left in place.
+func (c *Client) DeleteClusterSpec(ctx context.Context, name string) error {
+	path, err := endpoint("/cluster-specs/%s", name)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+// PutClusterSpecDocument uploads a new document for a spec and applies it, or
+// only plans it when dryRun is set
+func (c *Client) PutClusterSpecDocument(ctx context.Context, name string, document []byte, contentType string, dryRun bool) (*types.ClusterSpecSyncResult, error) {
+	path, err := endpoint("/cluster-specs/%s/document", name)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	setBool(q, "dry_run", dryRun)
+	var out types.ClusterSpecSyncResult
+	if err := c.do(ctx, "PUT", path, q, rawBody{contentType: contentType, data: document}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SyncClusterSpec reconciles a spec now, or only plans it when dryRun is set
+func (c *Client) SyncClusterSpec(ctx context.Context, name string, dryRun bool) (*types.ClusterSpecSyncResult, error) {
+	path, err := endpoint("/cluster-specs/%s/sync", name)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	setBool(q, "dry_run", dryRun)
+	var out types.ClusterSpecSyncResult
+	if err := c.do(ctx, "POST", path, q, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,252 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ListClustersOptions filters and paginates ListClusters
+type ListClustersOptions struct {
+	Page       int
+	PerPage    int // At most 100
+	Platform   string
+	Profile    string
+	Owner      string
+	Team       string
+	CostCenter string
+	Status     types.ClusterStatus
+}
+
+func (o *ListClustersOptions) query() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	setInt(q, "page", o.Page)
+	setInt(q, "per_page", o.PerPage)
+	setString(q, "platform", o.Platform)
+	setString(q, "profile", o.Profile)
+	setString(q, "owner", o.Owner)
+	setString(q, "team", o.Team)
+	setString(q, "cost_center", o.CostCenter)
+	setString(q, "status", string(o.Status))
+	return q
+}
+
+// ClusterList is a page of clusters
+type ClusterList struct {
+	Data       []*types.Cluster     `json:"data"`
+	Pagination types.PaginationMeta `json:"pagination"`
+}
+
+// CreateCluster requests a new cluster. The cluster is returned in PENDING
+// status; use WaitForClusterStatus to wait for it to become READY.
+func (c *Client) CreateCluster(ctx context.Context, req *types.CreateClusterAPIRequest) (*types.Cluster, error) {
+	var out types.Cluster
+	if err := c.post(ctx, "/clusters", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PreviewCluster validates a create request and returns what would be
+// submitted to the installer, without creating the cluster
+func (c *Client) PreviewCluster(ctx context.Context, req *types.CreateClusterAPIRequest) (*types.ClusterPreview, error) {
+	var out types.ClusterPreview
+	if err := c.post(ctx, "/clusters/preview", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListClusters returns one page of the clusters visible to the caller
+func (c *Client) ListClusters(ctx context.Context, opts *ListClustersOptions) (*ClusterList, error) {
+	var out ClusterList
+	if err := c.get(ctx, "/clusters", opts.query(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAllClusters follows pagination and returns every matching cluster
+func (c *Client) ListAllClusters(ctx context.Context, opts *ListClustersOptions) ([]*types.Cluster, error) {
+	page := ListClustersOptions{PerPage: 100}
+	if opts != nil {
+		page = *opts
+		if page.PerPage == 0 {
+			page.PerPage = 100
+		}
+	}
+	page.Page = 1
+
+	var clusters []*types.Cluster
+	for {
+		list, err := c.ListClusters(ctx, &page)
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, list.Data...)
+		if page.Page >= list.Pagination.TotalPages || len(list.Data) == 0 {
+			return clusters, nil
+		}
+		page.Page++
+	}
+}
+
+// GetCluster returns a cluster by ID
+func (c *Client) GetCluster(ctx context.Context, id string) (*types.Cluster, error) {
+	return c.clusterCall(ctx, "GET", "/clusters/%s", id, nil)
+}
+
+// DeleteCluster starts destroying a cluster and returns it in DESTROYING status
+func (c *Client) DeleteCluster(ctx context.Context, id string) (*types.Cluster, error) {
+	return c.clusterCall(ctx, "DELETE", "/clusters/%s", id, nil)
+}
+
+// ExtendCluster extends a cluster's TTL
+func (c *Client) ExtendCluster(ctx context.Context, id string, req *types.ExtendClusterRequest) (*types.Cluster, error) {
+	return c.clusterCall(ctx, "PATCH", "/clusters/%s/extend", id, req)
+}
+
+// HibernateCluster stops a cluster's instances
+func (c *Client) HibernateCluster(ctx context.Context, id string) (*types.Cluster, error) {
+	return c.clusterCall(ctx, "POST", "/clusters/%s/hibernate", id, nil)
+}
+
+// ResumeCluster starts a hibernated cluster
+func (c *Client) ResumeCluster(ctx context.Context, id string) (*types.Cluster, error) {
+	return c.clusterCall(ctx, "POST", "/clusters/%s/resume", id, nil)
+}
+
+func (c *Client) clusterCall(ctx context.Context, method, format, id string, in interface{}) (*types.Cluster, error) {
+	path, err := endpoint(format, id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.Cluster
+	if err := c.do(ctx, method, path, nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RefreshClusterOutputs re-reads a cluster's outputs from its install artifacts
+func (c *Client) RefreshClusterOutputs(ctx context.Context, id string) (*types.ClusterOutputs, error) {
+	path, err := endpoint("/clusters/%s/refresh-outputs", id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ClusterOutputs
+	if err := c.post(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetClusterOutputs returns a cluster's API and console URLs and credentials
+func (c *Client) GetClusterOutputs(ctx context.Context, id string) (*types.ClusterOutputsResponse, error) {
+	path, err := endpoint("/clusters/%s/outputs", id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ClusterOutputsResponse
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetKubeconfig downloads a cluster's kubeconfig
+func (c *Client) GetKubeconfig(ctx context.Context, id string) ([]byte, error) {
+	path, err := endpoint("/clusters/%s/kubeconfig", id)
+	if err != nil {
+		return nil, err
+	}
+	var out []byte
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetKubeconfigDownloadURL returns a short-lived URL for a cluster's kubeconfig
+func (c *Client) GetKubeconfigDownloadURL(ctx context.Context, id string) (*types.KubeconfigDownload, error) {
+	path, err := endpoint("/clusters/%s/kubeconfig/download-url", id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.KubeconfigDownload
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListClusterInstances returns the cloud instances backing a cluster
+func (c *Client) ListClusterInstances(ctx context.Context, id string) ([]types.ClusterInstance, error) {
+	path, err := endpoint("/clusters/%s/instances", id)
+	if err != nil {
+		return nil, err
+	}
+	var out []types.ClusterInstance
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListClusterStorageClasses returns the storage classes of a running cluster
+func (c *Client) ListClusterStorageClasses(ctx context.Context, id string) ([]types.StorageClass, error) {
+	path, err := endpoint("/clusters/%s/storage-classes", id)
+	if err != nil {
+		return nil, err
+	}
+	var out []types.StorageClass
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetClusterStatistics returns cluster counts and costs (admin only)
+func (c *Client) GetClusterStatistics(ctx context.Context) (*types.ClusterStatistics, error) {
+	var out types.ClusterStatistics
+	if err := c.get(ctx, "/admin/clusters/statistics", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListLongRunningClusters returns clusters running for at least minHours, with
+// cost estimates (admin only). The response is returned as raw JSON.
+func (c *Client) ListLongRunningClusters(ctx context.Context, minHours int) (json.RawMessage, error) {
+	q := url.Values{}
+	setInt(q, "min_hours", minHours)
+	var out []byte
+	if err := c.get(ctx, "/admin/clusters/long-running", q, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func setString(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
+
+func setInt(q url.Values, key string, value int) {
+	if value > 0 {
+		q.Set(key, strconv.Itoa(value))
+	}
+}
+
+func setBool(q url.Values, key string, value bool) {
+	if value {
+		q.Set(key, "true")
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the server responds with a 4xx or 5xx status
+type APIError struct {
+	StatusCode int    // HTTP status code
+	Code       string // Error code, e.g. "not_found" or "conflict", when the server sends one
+	Message    string // Human-readable message
+	RequestID  string // X-Request-Id of the failed request, for support
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("ocpctl API error %d: %s (request %s)", e.StatusCode, msg, e.RequestID)
+	}
+	return fmt.Sprintf("ocpctl API error %d: %s", e.StatusCode, msg)
+}
+
+// newAPIError builds an APIError from an error response. The API returns
+// {"error": code, "message": msg} from handlers and {"message": msg} from
+// middleware.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+
+	var payload struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		apiErr.Code = payload.Error
+		apiErr.Message = payload.Message
+		if apiErr.Message == "" {
+			apiErr.Message = payload.Error
+		}
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	return apiErr
+}
+
+// StatusCode returns the HTTP status of an APIError in err's chain, or 0
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool { return StatusCode(err) == http.StatusNotFound }
+
+// IsConflict reports whether err is a 409 response
+func IsConflict(err error) bool { return StatusCode(err) == http.StatusConflict }
+
+// IsForbidden reports whether err is a 403 response
+func IsForbidden(err error) bool { return StatusCode(err) == http.StatusForbidden }
+
+// IsUnauthorized reports whether err is a 401 response
+func IsUnauthorized(err error) bool { return StatusCode(err) == http.StatusUnauthorized }
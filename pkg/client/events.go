@@ -0,0 +1,181 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ListClusterEvents returns a cluster's lifecycle events after afterID.
+// limit defaults to 100 on the server.
+func (c *Client) ListClusterEvents(ctx context.Context, clusterID string, afterID int64, limit int) (*types.ClusterEventList, error) {
+	path, err := endpoint("/clusters/%s/events", clusterID)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	if afterID > 0 {
+		q.Set("after_id", strconv.FormatInt(afterID, 10))
+	}
+	setInt(q, "limit", limit)
+	var out types.ClusterEventList
+	if err := c.get(ctx, path, q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetClusterLogsOptions selects which deployment logs GetClusterLogs returns
+type GetClusterLogsOptions struct {
+	JobID         string // Defaults to the cluster's latest job
+	AfterID       int64
+	AfterSequence int64
+	Limit         int // Defaults to 500 on the server
+}
+
+// GetClusterLogs returns deployment log lines for a cluster
+func (c *Client) GetClusterLogs(ctx context.Context, clusterID string, opts *GetClusterLogsOptions) (*types.ClusterLogs, error) {
+	path, err := endpoint("/clusters/%s/logs", clusterID)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	if opts != nil {
+		setString(q, "job_id", opts.JobID)
+		if opts.AfterID > 0 {
+			q.Set("after_id", strconv.FormatInt(opts.AfterID, 10))
+		}
+		if opts.AfterSequence > 0 {
+			q.Set("after_sequence", strconv.FormatInt(opts.AfterSequence, 10))
+		}
+		setInt(q, "limit", opts.Limit)
+	}
+	var out types.ClusterLogs
+	if err := c.get(ctx, path, q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ErrStopStream can be returned by a StreamEvents handler to end the stream
+// without an error
+var ErrStopStream = errors.New("stop stream")
+
+// StreamEvents follows the cluster lifecycle event stream and calls handle
+// for each event. Admins receive events for every cluster, other users for
+// the clusters they own. Pass afterID < 0 to receive only new events.
+//
+// Dropped connections are resumed from the last event received. StreamEvents
+// returns when ctx is cancelled, handle returns an error, or the server
+// rejects the request.
+func (c *Client) StreamEvents(ctx context.Context, afterID int64, handle func(*types.ClusterEvent) error) error {
+	lastID := afterID
+	// The stream is long-lived, so the client's request timeout must not apply
+	hc := *c.httpClient
+	hc.Timeout = 0
+
+	for attempt := 0; ; attempt++ {
+		received, err := c.streamOnce(ctx, &hc, &lastID, handle)
+		switch {
+		case errors.Is(err, ErrStopStream):
+			return nil
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil && !errors.Is(err, errStreamDropped):
+			return err
+		}
+		if received {
+			attempt = 0
+		}
+		if err := sleep(ctx, c.backoff(attempt)); err != nil {
+			return err
+		}
+	}
+}
+
+// errStreamDropped marks a stream that ended in a way worth reconnecting after
+var errStreamDropped = errors.New("event stream dropped")
+
+func (c *Client) streamOnce(ctx context.Context, hc *http.Client, lastID *int64, handle func(*types.ClusterEvent) error) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url("/events/stream", nil), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("User-Agent", c.userAgent)
+	if *lastID >= 0 {
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(*lastID, 10))
+	}
+	if c.auth != nil {
+		if err := c.auth.Authorize(ctx, req, nil); err != nil {
+			return false, fmt.Errorf("authenticate: %w", err)
+		}
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", errStreamDropped, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if retryable(resp, true) {
+			drain(resp)
+			return false, errStreamDropped
+		}
+		if resp.StatusCode == http.StatusUnauthorized {
+			if r, ok := c.auth.(interface{ invalidate() }); ok {
+				r.invalidate()
+			}
+		}
+		return false, decodeResponse(resp, nil)
+	}
+	defer resp.Body.Close()
+
+	received := false
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+
+	var id int64 = -1
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line ends an event
+			if data.Len() > 0 {
+				var event types.ClusterEvent
+				if err := json.Unmarshal([]byte(data.String()), &event); err != nil {
+					return received, fmt.Errorf("decode event: %w", err)
+				}
+				if err := handle(&event); err != nil {
+					return received, err
+				}
+				received = true
+				if id >= 0 {
+					*lastID = id
+				}
+			}
+			id = -1
+			data.Reset()
+		case strings.HasPrefix(line, ":"):
+			// Comment, used for heartbeats
+		case strings.HasPrefix(line, "id:"):
+			if v, err := strconv.ParseInt(strings.TrimSpace(line[3:]), 10, 64); err == nil {
+				id = v
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(line[5:], " "))
+		}
+	}
+	return received, errStreamDropped
+}
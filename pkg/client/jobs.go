@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ListJobsOptions filters and paginates ListJobs
+type ListJobsOptions struct {
+	Page      int
+	PerPage   int // At most 100
+	ClusterID string
+	Type      types.JobType
+	Status    types.JobStatus
+}
+
+func (o *ListJobsOptions) query() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	setInt(q, "page", o.Page)
+	setInt(q, "per_page", o.PerPage)
+	setString(q, "cluster_id", o.ClusterID)
+	setString(q, "type", string(o.Type))
+	setString(q, "status", string(o.Status))
+	return q
+}
+
+// JobList is a page of jobs
+type JobList struct {
+	Data       []*types.Job         `json:"data"`
+	Pagination types.PaginationMeta `json:"pagination"`
+}
+
+// ListJobs returns one page of jobs
+func (c *Client) ListJobs(ctx context.Context, opts *ListJobsOptions) (*JobList, error) {
+	var out JobList
+	if err := c.get(ctx, "/jobs", opts.query(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetJob returns a job by ID
+func (c *Client) GetJob(ctx context.Context, id string) (*types.Job, error) {
+	path, err := endpoint("/jobs/%s", id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.Job
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ListPools returns the enabled cluster pools with their current statistics
+func (c *Client) ListPools(ctx context.Context) ([]*types.PoolWithStats, error) {
+	return c.listPools(ctx, "/pools")
+}
+
+// ListAllPools returns every cluster pool, including disabled ones (admin only)
+func (c *Client) ListAllPools(ctx context.Context) ([]*types.PoolWithStats, error) {
+	return c.listPools(ctx, "/admin/pools")
+}
+
+func (c *Client) listPools(ctx context.Context, path string) ([]*types.PoolWithStats, error) {
+	var out struct {
+		Pools []*types.PoolWithStats `json:"pools"`
+	}
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Pools, nil
+}
+
+// GetPool returns a pool and its statistics (admin only)
+func (c *Client) GetPool(ctx context.Context, name string) (*types.PoolWithStats, error) {
+	path, err := endpoint("/admin/pools/%s", name)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Pool  *types.ClusterPool      `json:"pool"`
+		Stats *types.ClusterPoolStats `json:"stats"`
+	}
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	if out.Pool == nil {
+		return nil, &APIError{StatusCode: 404, Message: "pool not found"}
+	}
+	return &types.PoolWithStats{ClusterPool: *out.Pool, Stats: out.Stats}, nil
+}
+
+// CreatePool creates a cluster pool (admin only)
+func (c *Client) CreatePool(ctx context.Context, req *types.CreatePoolRequest) (*types.ClusterPool, error) {
+	var out types.ClusterPool
+	if err := c.post(ctx, "/admin/pools", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdatePool changes a pool's settings (admin only)
+func (c *Client) UpdatePool(ctx context.Context, name string, req *types.UpdatePoolRequest) (*types.ClusterPool, error) {
+	path, err := endpoint("/admin/pools/%s", name)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ClusterPool
+	if err := c.patch(ctx, path, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeletePool deletes a pool (admin only)
+func (c *Client) DeletePool(ctx context.Context, name string) error {
+	path, err := endpoint("/admin/pools/%s", name)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+// GetPoolStats returns a pool's current statistics
+func (c *Client) GetPoolStats(ctx context.Context, poolName string) (*types.ClusterPoolStats, error) {
+	path, err := endpoint("/pools/%s/stats", poolName)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ClusterPoolStats
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPoolClusters returns the clusters that belong to a pool
+func (c *Client) ListPoolClusters(ctx context.Context, poolName string) ([]*types.Cluster, error) {
+	path, err := endpoint("/pools/%s/clusters", poolName)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Clusters []*types.Cluster `json:"clusters"`
+	}
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Clusters, nil
+}
+
+// LeaseCluster leases a ready cluster from a pool
+func (c *Client) LeaseCluster(ctx context.Context, poolName string, req *types.LeaseRequest) (*types.LeaseResponse, error) {
+	path, err := endpoint("/pools/%s/lease", poolName)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		req = &types.LeaseRequest{}
+	}
+	var out types.LeaseResponse
+	if err := c.post(ctx, path, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReleaseCluster returns a leased cluster to its pool
+func (c *Client) ReleaseCluster(ctx context.Context, clusterID string) error {
+	path, err := endpoint("/pools/clusters/%s/release", clusterID)
+	if err != nil {
+		return err
+	}
+	return c.post(ctx, path, nil, nil)
+}
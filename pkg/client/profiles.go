@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ListProfiles returns the enabled cluster profiles. platform and track are
+// optional filters.
+func (c *Client) ListProfiles(ctx context.Context, platform, track string) ([]*types.ProfileSummary, error) {
+	q := url.Values{}
+	setString(q, "platform", platform)
+	setString(q, "track", track)
+	var out []*types.ProfileSummary
+	if err := c.get(ctx, "/profiles", q, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetProfile returns a cluster profile by name
+func (c *Client) GetProfile(ctx context.Context, name string) (*types.ProfileSummary, error) {
+	path, err := endpoint("/profiles/%s", name)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ProfileSummary
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CheckProfileVersions reports profiles with newer OpenShift or Kubernetes
+// versions available (admin only). The response is returned as raw JSON.
+func (c *Client) CheckProfileVersions(ctx context.Context, refresh bool) (json.RawMessage, error) {
+	q := url.Values{}
+	setBool(q, "refresh", refresh)
+	var out []byte
+	if err := c.get(ctx, "/admin/profiles/version-check", q, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateProfileVersions changes the versions offered by a profile (admin only).
+// The response is returned as raw JSON.
+func (c *Client) UpdateProfileVersions(ctx context.Context, name string, req *types.UpdateVersionsRequest) (json.RawMessage, error) {
+	path, err := endpoint("/admin/profiles/%s/update-versions", name)
+	if err != nil {
+		return nil, err
+	}
+	var out []byte
+	if err := c.post(ctx, path, req, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateProfile creates a profile from a YAML definition (admin only)
+func (c *Client) CreateProfile(ctx context.Context, req *types.SaveProfileRequest) (*types.ProfileRevision, error) {
+	var out types.ProfileRevision
+	if err := c.post(ctx, "/admin/profiles", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateProfile saves a new revision of a profile (admin only). The revision
+// is published unless req.Draft is set.
+func (c *Client) UpdateProfile(ctx context.Context, name string, req *types.SaveProfileRequest) (*types.ProfileRevision, error) {
+	path, err := endpoint("/admin/profiles/%s", name)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ProfileRevision
+	if err := c.put(ctx, path, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteProfile deletes a profile that no cluster or pool uses (admin only)
+func (c *Client) DeleteProfile(ctx context.Context, name string) error {
+	path, err := endpoint("/admin/profiles/%s", name)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+// ListProfileRevisions returns the revisions of a profile, newest first
+// (admin only)
+func (c *Client) ListProfileRevisions(ctx context.Context, name string) ([]*types.ProfileRevision, error) {
+	path, err := endpoint("/admin/profiles/%s/revisions", name)
+	if err != nil {
+		return nil, err
+	}
+	var out []*types.ProfileRevision
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetProfileRevision returns one revision of a profile (admin only)
+func (c *Client) GetProfileRevision(ctx context.Context, name string, revision int) (*types.ProfileRevision, error) {
+	path, err := endpoint("/admin/profiles/%s/revisions/%s", name, strconv.Itoa(revision))
+	if err != nil {
+		return nil, err
+	}
+	var out types.ProfileRevision
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PublishProfileRevision publishes a draft revision (admin only)
+func (c *Client) PublishProfileRevision(ctx context.Context, name string, revision int) (*types.ProfileRevision, error) {
+	path, err := endpoint("/admin/profiles/%s/revisions/%s/publish", name, strconv.Itoa(revision))
+	if err != nil {
+		return nil, err
+	}
+	var out types.ProfileRevision
+	if err := c.post(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RollbackProfile publishes the content of an earlier revision as a new
+// revision (admin only). A nil req or zero req.Revision restores the
+// revision published before the current one.
+func (c *Client) RollbackProfile(ctx context.Context, name string, req *types.RollbackProfileRequest) (*types.ProfileRevision, error) {
+	path, err := endpoint("/admin/profiles/%s/rollback", name)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		req = &types.RollbackProfileRequest{}
+	}
+	var out types.ProfileRevision
+	if err := c.post(ctx, path, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReloadProfiles reloads the published profiles on the replica serving the
+// request (admin only)
+func (c *Client) ReloadProfiles(ctx context.Context) (*types.ReloadProfilesResponse, error) {
+	var out types.ReloadProfilesResponse
+	if err := c.post(ctx, "/admin/profiles/reload", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// LinkStorage starts provisioning shared storage between a cluster and the
+// target cluster in req. JobID is empty when the clusters are already linked.
+func (c *Client) LinkStorage(ctx context.Context, clusterID string, req *types.LinkStorageRequest) (*types.JobAcceptedResponse, error) {
+	path, err := endpoint("/clusters/%s/storage/link", clusterID)
+	if err != nil {
+		return nil, err
+	}
+	var out types.JobAcceptedResponse
+	if err := c.post(ctx, path, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListClusterStorage returns the storage groups a cluster is linked to
+func (c *Client) ListClusterStorage(ctx context.Context, clusterID string) ([]*types.StorageGroupResponse, error) {
+	path, err := endpoint("/clusters/%s/storage", clusterID)
+	if err != nil {
+		return nil, err
+	}
+	var out []*types.StorageGroupResponse
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UnlinkStorage starts removing a cluster from a storage group
+func (c *Client) UnlinkStorage(ctx context.Context, clusterID, groupID string) (*types.JobAcceptedResponse, error) {
+	path, err := endpoint("/clusters/%s/storage/link/%s", clusterID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	var out types.JobAcceptedResponse
+	if err := c.delete(ctx, path, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListClusterConfigurations returns the post-deployment configuration steps of a cluster
+func (c *Client) ListClusterConfigurations(ctx context.Context, clusterID string) (*types.ClusterConfigurationList, error) {
+	path, err := endpoint("/clusters/%s/configurations", clusterID)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ClusterConfigurationList
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConfigureCluster starts post-deployment configuration of a READY cluster
+func (c *Client) ConfigureCluster(ctx context.Context, clusterID string) (*types.JobAcceptedResponse, error) {
+	path, err := endpoint("/clusters/%s/configure", clusterID)
+	if err != nil {
+		return nil, err
+	}
+	var out types.JobAcceptedResponse
+	if err := c.post(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RetryConfiguration retries a failed configuration step
+func (c *Client) RetryConfiguration(ctx context.Context, clusterID, configID string) (*types.JobAcceptedResponse, error) {
+	path, err := endpoint("/clusters/%s/configurations/%s/retry", clusterID, configID)
+	if err != nil {
+		return nil, err
+	}
+	var out types.JobAcceptedResponse
+	if err := c.patch(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
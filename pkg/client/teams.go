@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ListTeams returns all teams (admin or team admin)
+func (c *Client) ListTeams(ctx context.Context) ([]*types.Team, error) {
+	var out struct {
+		Teams []*types.Team `json:"teams"`
+	}
+	if err := c.get(ctx, "/admin/teams", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Teams, nil
+}
+
+// GetTeam returns a team
+func (c *Client) GetTeam(ctx context.Context, name string) (*types.Team, error) {
+	return c.teamCall(ctx, "GET", "/admin/teams/%s", name, nil)
+}
+
+// CreateTeam creates a team (admin only)
+func (c *Client) CreateTeam(ctx context.Context, req *types.CreateTeamRequest) (*types.Team, error) {
+	var out types.Team
+	if err := c.post(ctx, "/admin/teams", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateTeam changes a team (admin only)
+func (c *Client) UpdateTeam(ctx context.Context, name string, req *types.UpdateTeamRequest) (*types.Team, error) {
+	return c.teamCall(ctx, "PATCH", "/admin/teams/%s", name, req)
+}
+
+// DeleteTeam deletes a team (admin only)
+func (c *Client) DeleteTeam(ctx context.Context, name string) error {
+	path, err := endpoint("/admin/teams/%s", name)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+func (c *Client) teamCall(ctx context.Context, method, format, name string, in interface{}) (*types.Team, error) {
+	path, err := endpoint(format, name)
+	if err != nil {
+		return nil, err
+	}
+	var out types.Team
+	if err := c.do(ctx, method, path, nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTeamAdmins returns the team admins of a team (admin only)
+func (c *Client) ListTeamAdmins(ctx context.Context, team string) ([]*types.TeamAdminResponse, error) {
+	path, err := endpoint("/admin/teams/%s/admins", team)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Admins []*types.TeamAdminResponse `json:"admins"`
+	}
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Admins, nil
+}
+
+// GrantTeamAdmin makes a user a team admin (admin only)
+func (c *Client) GrantTeamAdmin(ctx context.Context, team string, req *types.GrantTeamAdminRequest) error {
+	path, err := endpoint("/admin/teams/%s/admins", team)
+	if err != nil {
+		return err
+	}
+	return c.post(ctx, path, req, nil)
+}
+
+// RevokeTeamAdmin removes a user's team admin role (admin only)
+func (c *Client) RevokeTeamAdmin(ctx context.Context, team, userID string) error {
+	path, err := endpoint("/admin/teams/%s/admins/%s", team, userID)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+// ListTeamMembers returns the members of a team
+func (c *Client) ListTeamMembers(ctx context.Context, team string) ([]*types.TeamMember, error) {
+	path, err := endpoint("/admin/teams/%s/members", team)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Members []*types.TeamMember `json:"members"`
+	}
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Members, nil
+}
+
+// ListEligibleTeamUsers returns the users that can be added to a team
+func (c *Client) ListEligibleTeamUsers(ctx context.Context, team string) ([]*types.UserResponse, error) {
+	path, err := endpoint("/admin/teams/%s/eligible-users", team)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		Users []*types.UserResponse `json:"users"`
+	}
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Users, nil
+}
+
+// AddTeamMember adds a user to a team
+func (c *Client) AddTeamMember(ctx context.Context, team string, req *types.AddUserToTeamRequest) error {
+	path, err := endpoint("/admin/teams/%s/members", team)
+	if err != nil {
+		return err
+	}
+	return c.post(ctx, path, req, nil)
+}
+
+// RemoveTeamMember removes a user from a team
+func (c *Client) RemoveTeamMember(ctx context.Context, team, userID string) error {
+	path, err := endpoint("/admin/teams/%s/members/%s", team, userID)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+// GetTeamAllowedProfiles returns the profiles a team may use; empty means all
+func (c *Client) GetTeamAllowedProfiles(ctx context.Context, team string) ([]string, error) {
+	path, err := endpoint("/admin/teams/%s/allowed-profiles", team)
+	if err != nil {
+		return nil, err
+	}
+	var out struct {
+		AllowedProfiles []string `json:"allowed_profiles"`
+	}
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.AllowedProfiles, nil
+}
+
+// UpdateTeamAllowedProfiles replaces the profiles a team may use
+func (c *Client) UpdateTeamAllowedProfiles(ctx context.Context, team string, req *types.UpdateAllowedProfilesRequest) (*types.Team, error) {
+	return c.teamCall(ctx, "PATCH", "/admin/teams/%s/allowed-profiles", team, req)
+}
+
+// GetTeamCosts returns a team's cost summary
+func (c *Client) GetTeamCosts(ctx context.Context, team string) (*types.TeamCostSummary, error) {
+	path, err := endpoint("/teams/%s/costs", team)
+	if err != nil {
+		return nil, err
+	}
+	var out types.TeamCostSummary
+	if err := c.get(ctx, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// ListTemplates returns post-configuration templates. When publicOnly is set
+// only public templates are returned; tags filters by any of the given tags.
+func (c *Client) ListTemplates(ctx context.Context, publicOnly bool, tags ...string) ([]*types.PostConfigTemplate, error) {
+	q := url.Values{}
+	setBool(q, "public", publicOnly)
+	setString(q, "tags", strings.Join(tags, ","))
+	var out struct {
+		Templates []*types.PostConfigTemplate `json:"templates"`
+	}
+	if err := c.get(ctx, "/templates", q, &out); err != nil {
+		return nil, err
+	}
+	return out.Templates, nil
+}
+
+// GetTemplate returns a post-configuration template
+func (c *Client) GetTemplate(ctx context.Context, id string) (*types.PostConfigTemplate, error) {
+	return c.templateCall(ctx, "GET", id, nil)
+}
+
+// CreateTemplate saves a post-configuration template
+func (c *Client) CreateTemplate(ctx context.Context, req *types.CreateTemplateRequest) (*types.PostConfigTemplate, error) {
+	var out types.PostConfigTemplate
+	if err := c.post(ctx, "/templates", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateTemplate changes a post-configuration template
+func (c *Client) UpdateTemplate(ctx context.Context, id string, req *types.UpdateTemplateRequest) (*types.PostConfigTemplate, error) {
+	return c.templateCall(ctx, "PATCH", id, req)
+}
+
+// DeleteTemplate deletes a post-configuration template
+func (c *Client) DeleteTemplate(ctx context.Context, id string) error {
+	path, err := endpoint("/templates/%s", id)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+func (c *Client) templateCall(ctx context.Context, method, id string, in interface{}) (*types.PostConfigTemplate, error) {
+	path, err := endpoint("/templates/%s", id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.PostConfigTemplate
+	if err := c.do(ctx, method, path, nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListClusterTemplates returns the cluster templates visible to the caller
+func (c *Client) ListClusterTemplates(ctx context.Context) ([]*types.ClusterTemplate, error) {
+	var out struct {
+		Templates []*types.ClusterTemplate `json:"templates"`
+	}
+	if err := c.get(ctx, "/cluster-templates", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Templates, nil
+}
+
+// GetClusterTemplate returns a cluster template
+func (c *Client) GetClusterTemplate(ctx context.Context, id string) (*types.ClusterTemplate, error) {
+	return c.clusterTemplateCall(ctx, "GET", id, nil)
+}
+
+// CreateClusterTemplate saves a cluster template
+func (c *Client) CreateClusterTemplate(ctx context.Context, req *types.ClusterTemplateRequest) (*types.ClusterTemplate, error) {
+	var out types.ClusterTemplate
+	if err := c.post(ctx, "/cluster-templates", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateClusterTemplate changes a cluster template
+func (c *Client) UpdateClusterTemplate(ctx context.Context, id string, req *types.ClusterTemplateRequest) (*types.ClusterTemplate, error) {
+	return c.clusterTemplateCall(ctx, "PATCH", id, req)
+}
+
+// DeleteClusterTemplate deletes a cluster template
+func (c *Client) DeleteClusterTemplate(ctx context.Context, id string) error {
+	path, err := endpoint("/cluster-templates/%s", id)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+func (c *Client) clusterTemplateCall(ctx context.Context, method, id string, in interface{}) (*types.ClusterTemplate, error) {
+	path, err := endpoint("/cluster-templates/%s", id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.ClusterTemplate
+	if err := c.do(ctx, method, path, nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// GetMe returns the authenticated user
+func (c *Client) GetMe(ctx context.Context) (*types.UserResponse, error) {
+	var out types.UserResponse
+	if err := c.get(ctx, "/auth/me", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateMe changes the authenticated user's profile
+func (c *Client) UpdateMe(ctx context.Context, req *types.UpdateMeRequest) (*types.UserResponse, error) {
+	var out types.UserResponse
+	if err := c.patch(ctx, "/auth/me", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ChangePassword changes the authenticated user's password
+func (c *Client) ChangePassword(ctx context.Context, req *types.ChangePasswordRequest) error {
+	return c.post(ctx, "/auth/password", req, nil)
+}
+
+// UserList is a page of users
+type UserList struct {
+	Users  []*types.UserResponse `json:"users"`
+	Total  int                   `json:"total"`
+	Limit  int                   `json:"limit"`
+	Offset int                   `json:"offset"`
+}
+
+// ListUsers returns a page of users (admin only)
+func (c *Client) ListUsers(ctx context.Context, limit, offset int) (*UserList, error) {
+	q := url.Values{}
+	setInt(q, "limit", limit)
+	setInt(q, "offset", offset)
+	var out UserList
+	if err := c.get(ctx, "/users", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUser returns a user by ID (admin only)
+func (c *Client) GetUser(ctx context.Context, id string) (*types.UserResponse, error) {
+	return c.userCall(ctx, "GET", id, nil)
+}
+
+// CreateUser creates a local user (admin only)
+func (c *Client) CreateUser(ctx context.Context, req *types.CreateUserRequest) (*types.UserResponse, error) {
+	var out types.UserResponse
+	if err := c.post(ctx, "/users", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateUser changes a user (admin only)
+func (c *Client) UpdateUser(ctx context.Context, id string, req *types.UpdateUserRequest) (*types.UserResponse, error) {
+	return c.userCall(ctx, "PATCH", id, req)
+}
+
+// DeleteUser deletes a user (admin only)
+func (c *Client) DeleteUser(ctx context.Context, id string) error {
+	path, err := endpoint("/users/%s", id)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
+
+func (c *Client) userCall(ctx context.Context, method, id string, in interface{}) (*types.UserResponse, error) {
+	path, err := endpoint("/users/%s", id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.UserResponse
+	if err := c.do(ctx, method, path, nil, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAPIKeys returns the caller's API keys
+func (c *Client) ListAPIKeys(ctx context.Context) ([]*types.APIKeyResponse, error) {
+	var out []*types.APIKeyResponse
+	if err := c.get(ctx, "/api-keys", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateAPIKey creates an API key. The key itself is only returned here.
+func (c *Client) CreateAPIKey(ctx context.Context, req *types.CreateAPIKeyRequest) (*types.CreateAPIKeyResponse, error) {
+	var out types.CreateAPIKeyResponse
+	if err := c.post(ctx, "/api-keys", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAPIKey changes an API key's name or scope
+func (c *Client) UpdateAPIKey(ctx context.Context, id string, req *types.UpdateAPIKeyRequest) (*types.APIKeyResponse, error) {
+	path, err := endpoint("/api-keys/%s", id)
+	if err != nil {
+		return nil, err
+	}
+	var out types.APIKeyResponse
+	if err := c.patch(ctx, path, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeAPIKey revokes an API key, keeping it for audit
+func (c *Client) RevokeAPIKey(ctx context.Context, id string) error {
+	path, err := endpoint("/api-keys/%s/revoke", id)
+	if err != nil {
+		return err
+	}
+	return c.post(ctx, path, nil, nil)
+}
+
+// DeleteAPIKey deletes an API key
+func (c *Client) DeleteAPIKey(ctx context.Context, id string) error {
+	path, err := endpoint("/api-keys/%s", id)
+	if err != nil {
+		return err
+	}
+	return c.delete(ctx, path, nil)
+}
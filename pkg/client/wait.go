@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tsanders-rh/ocpctl/pkg/types"
+)
+
+// DefaultPollInterval is how often the WaitFor helpers poll by default
+const DefaultPollInterval = 15 * time.Second
+
+// WaitOption configures the WaitFor helpers
+type WaitOption func(*waitConfig)
+
+type waitConfig struct {
+	interval time.Duration
+}
+
+// WithPollInterval sets how often the WaitFor helpers poll
+func WithPollInterval(d time.Duration) WaitOption {
+	return func(w *waitConfig) { w.interval = d }
+}
+
+// UnexpectedStatusError is returned by the WaitFor helpers when the resource
+// reaches a terminal status other than the one waited for
+type UnexpectedStatusError struct {
+	Kind   string // "cluster" or "job"
+	ID     string
+	Status string
+	Reason string
+}
+
+func (e *UnexpectedStatusError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("%s %s reached status %s: %s", e.Kind, e.ID, e.Status, e.Reason)
+	}
+	return fmt.Sprintf("%s %s reached status %s", e.Kind, e.ID, e.Status)
+}
+
+// failedClusterStatuses end a wait unless they were asked for
+var failedClusterStatuses = []types.ClusterStatus{
+	types.ClusterStatusFailed,
+	types.ClusterStatusDestroyFailed,
+}
+
+// WaitForClusterStatus polls a cluster until it reaches one of targets and
+// returns it. It fails with an UnexpectedStatusError if the cluster reaches
+// FAILED or DESTROY_FAILED first. A cluster that is no longer found counts as
+// DESTROYED, in which case the returned cluster is nil.
+//
+// Use a context deadline to bound the wait; cluster installs take 30-60 minutes.
+func (c *Client) WaitForClusterStatus(ctx context.Context, id string, targets []types.ClusterStatus, opts ...WaitOption) (*types.Cluster, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no target status given")
+	}
+
+	return poll(ctx, opts, func() (*types.Cluster, bool, error) {
+		cluster, err := c.GetCluster(ctx, id)
+		if err != nil {
+			if IsNotFound(err) && slices.Contains(targets, types.ClusterStatusDestroyed) {
+				return nil, true, nil
+			}
+			return nil, false, err
+		}
+		if slices.Contains(targets, cluster.Status) {
+			return cluster, true, nil
+		}
+		if slices.Contains(failedClusterStatuses, cluster.Status) ||
+			(cluster.Status == types.ClusterStatusDestroyed && !slices.Contains(targets, types.ClusterStatusDestroyed)) {
+			return cluster, true, &UnexpectedStatusError{Kind: "cluster", ID: id, Status: string(cluster.Status)}
+		}
+		return cluster, false, nil
+	})
+}
+
+// WaitForJobStatus polls a job until it reaches one of targets and returns it.
+// It fails with an UnexpectedStatusError if the job ends in another terminal
+// status (SUCCEEDED or FAILED).
+func (c *Client) WaitForJobStatus(ctx context.Context, id string, targets []types.JobStatus, opts ...WaitOption) (*types.Job, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no target status given")
+	}
+
+	return poll(ctx, opts, func() (*types.Job, bool, error) {
+		job, err := c.GetJob(ctx, id)
+		if err != nil {
+			return nil, false, err
+		}
+		if slices.Contains(targets, job.Status) {
+			return job, true, nil
+		}
+		if job.Status == types.JobStatusSucceeded || job.Status == types.JobStatusFailed {
+			reason := ""
+			if job.ErrorMessage != nil {
+				reason = *job.ErrorMessage
+			}
+			return job, true, &UnexpectedStatusError{Kind: "job", ID: id, Status: string(job.Status), Reason: reason}
+		}
+		return job, false, nil
+	})
+}
+
+// poll calls check every interval until it reports done or fails. Transient
+// API errors have already been retried by the request retry policy.
+func poll[T any](ctx context.Context, opts []WaitOption, check func() (T, bool, error)) (T, error) {
+	cfg := waitConfig{interval: DefaultPollInterval}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+
+	for {
+		result, done, err := check()
+		if done || err != nil {
+			return result, err
+		}
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
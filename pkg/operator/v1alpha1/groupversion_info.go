@@ -0,0 +1,22 @@
+// Package v1alpha1 contains the ocpctl operator API: the OcpctlCluster and
+// OcpctlPoolLease custom resources.
+//
+// +kubebuilder:object:generate=true
+// +groupName=ocpctl.io
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is the group and version of the ocpctl custom resources
+	GroupVersion = schema.GroupVersion{Group: "ocpctl.io", Version: "v1alpha1"}
+
+	// SchemeBuilder registers the ocpctl types with a runtime.Scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the ocpctl types to a runtime.Scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
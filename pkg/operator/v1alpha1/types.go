@@ -0,0 +1,211 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types set on ocpctl resources
+const (
+	// ConditionReady is True once the cluster is usable and its credentials Secret is written
+	ConditionReady = "Ready"
+	// ConditionSynced is False when the last call to the ocpctl API failed
+	ConditionSynced = "Synced"
+	// ConditionTTLApplied is False when spec.ttlHours is lower than the
+	// cluster's TTL, which can only be extended
+	ConditionTTLApplied = "TTLApplied"
+)
+
+// DeletionPolicy controls what happens to an ocpctl cluster when its resource is deleted
+// +kubebuilder:validation:Enum=Delete;Orphan
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete destroys the cluster
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan leaves the cluster running; it is still destroyed at TTL expiry
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// OcpctlClusterSpec is the requested cluster. Fields match POST /api/v1/clusters.
+type OcpctlClusterSpec struct {
+	// ClusterName is the ocpctl cluster name. Defaults to the resource name.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// +kubebuilder:validation:Enum=aws;ibmcloud;gcp;azure
+	Platform string `json:"platform"`
+	// +kubebuilder:validation:Enum=openshift;rosa;eks;iks;gke;aro;aks
+	ClusterType string `json:"clusterType"`
+	Version     string `json:"version"`
+	Profile     string `json:"profile"`
+	Region      string `json:"region"`
+	// BaseDomain is required for OpenShift clusters
+	// +optional
+	BaseDomain string `json:"baseDomain,omitempty"`
+	Owner      string `json:"owner"`
+	Team       string `json:"team"`
+	CostCenter string `json:"costCenter"`
+
+	// TTLHours defaults to the profile's TTL. Raising it extends a running
+	// cluster; a running cluster's TTL cannot be lowered.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTLHours *int `json:"ttlHours,omitempty"`
+	// +optional
+	SSHPublicKey string `json:"sshPublicKey,omitempty"`
+	// +optional
+	ExtraTags map[string]string `json:"extraTags,omitempty"`
+	// +optional
+	OffhoursOptIn bool `json:"offhoursOptIn,omitempty"`
+	// +optional
+	SkipPostDeployment bool `json:"skipPostDeployment,omitempty"`
+	// Addons to install after the cluster is ready
+	// +optional
+	Addons []AddonRef `json:"addons,omitempty"`
+
+	// WriteConnectionSecretTo names the Secret that receives the kubeconfig and
+	// credentials. Defaults to "<name>-kubeconfig".
+	// +optional
+	WriteConnectionSecretTo string `json:"writeConnectionSecretTo,omitempty"`
+
+	// DeletionPolicy controls whether deleting this resource destroys the cluster
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// AddonRef selects an add-on and version
+type AddonRef struct {
+	ID string `json:"id"`
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// OcpctlClusterStatus mirrors the ocpctl cluster
+type OcpctlClusterStatus struct {
+	// ClusterID is the ocpctl cluster ID
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+	// Phase is the ocpctl cluster status, e.g. CREATING or READY
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+	// +optional
+	ConsoleURL string `json:"consoleURL,omitempty"`
+	// +optional
+	DestroyAt *metav1.Time `json:"destroyAt,omitempty"`
+	// SecretName is the Secret holding the kubeconfig, once written
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// OcpctlCluster is an ephemeral cluster provisioned by ocpctl
+//
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=occ,categories=ocpctl
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.status.clusterID`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type OcpctlCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OcpctlClusterSpec   `json:"spec,omitempty"`
+	Status OcpctlClusterStatus `json:"status,omitempty"`
+}
+
+// OcpctlClusterList is a list of OcpctlCluster
+//
+// +kubebuilder:object:root=true
+type OcpctlClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OcpctlCluster `json:"items"`
+}
+
+// OcpctlPoolLeaseSpec requests a cluster from an ocpctl pool
+type OcpctlPoolLeaseSpec struct {
+	// PoolName is the pool to lease from
+	PoolName string `json:"poolName"`
+	// DurationHours overrides the pool's default lease duration
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DurationHours *int `json:"durationHours,omitempty"`
+	// Metadata is recorded with the lease, e.g. a pipeline run name
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// WriteConnectionSecretTo names the Secret that receives the kubeconfig and
+	// service account token. Defaults to "<name>-kubeconfig".
+	// +optional
+	WriteConnectionSecretTo string `json:"writeConnectionSecretTo,omitempty"`
+}
+
+// OcpctlPoolLeaseStatus describes the leased cluster
+type OcpctlPoolLeaseStatus struct {
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+	// +optional
+	ConsoleURL string `json:"consoleURL,omitempty"`
+	// +optional
+	LeasedAt *metav1.Time `json:"leasedAt,omitempty"`
+	// +optional
+	LeaseExpiresAt *metav1.Time `json:"leaseExpiresAt,omitempty"`
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// LeaseAttempts counts lease requests rejected because the pool was missing
+	// or empty
+	// +optional
+	LeaseAttempts int `json:"leaseAttempts,omitempty"`
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// OcpctlPoolLease leases a ready cluster from an ocpctl pool and releases it
+// when deleted
+//
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ocl,categories=ocpctl
+// +kubebuilder:printcolumn:name="Pool",type=string,JSONPath=`.spec.poolName`
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.status.clusterName`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.leaseExpiresAt`
+type OcpctlPoolLease struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec cannot change once created; delete and recreate the lease instead
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+	Spec   OcpctlPoolLeaseSpec   `json:"spec,omitempty"`
+	Status OcpctlPoolLeaseStatus `json:"status,omitempty"`
+}
+
+// OcpctlPoolLeaseList is a list of OcpctlPoolLease
+//
+// +kubebuilder:object:root=true
+type OcpctlPoolLeaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OcpctlPoolLease `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OcpctlCluster{}, &OcpctlClusterList{}, &OcpctlPoolLease{}, &OcpctlPoolLeaseList{})
+}
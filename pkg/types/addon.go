@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // PostConfigAddon represents a pre-defined add-on configuration
 type PostConfigAddon struct {
@@ -39,6 +42,113 @@ type AddonMetadata struct {
 	ConflictsWith        []string `json:"conflictsWith,omitempty"` // List of addon IDs that conflict with this addon
 	Notes                []string `json:"notes,omitempty"`
 	Warnings             []string `json:"warnings,omitempty"`
+
+	SupportedArchitectures []Architecture `json:"supportedArchitectures,omitempty"` // Node architectures the add-on runs on (empty = any)
+
+	Requires   []AddonRequirement `json:"requires,omitempty"`   // Add-ons installed along with this one
+	Recommends []AddonRequirement `json:"recommends,omitempty"` // Add-ons suggested alongside this one, never installed automatically
+
+	Compatibility *AddonCompatibility `json:"compatibility,omitempty"` // Cluster types and versions this add-on version supports (nil = any)
+}
+
+// AddonCompatibility declares the clusters an add-on version can be installed
+// on. Version ranges are constraints such as ">=4.16, <=4.18"; a bound
+// without a patch version covers every patch release, so "<=4.18" includes
+// 4.18.12.
+type AddonCompatibility struct {
+	OpenShiftVersions  string        `json:"openshiftVersions,omitempty" yaml:"openshiftVersions,omitempty"`   // Checked on OpenShift-based cluster types (empty = any)
+	KubernetesVersions string        `json:"kubernetesVersions,omitempty" yaml:"kubernetesVersions,omitempty"` // Checked on Kubernetes cluster types such as EKS and GKE (empty = any)
+	ClusterTypes       []ClusterType `json:"clusterTypes,omitempty" yaml:"clusterTypes,omitempty"`             // Cluster types supported (empty = any)
+}
+
+// AddonRequirement names another add-on an add-on depends on or recommends
+type AddonRequirement struct {
+	ID        string `json:"id" yaml:"id"`
+	Version   string `json:"version,omitempty" yaml:"version,omitempty"`     // Channel name, or constraint such as ">=1.4" on the version in the channel (empty = any)
+	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"` // Only applies when this condition holds, e.g. "!('bare-metal' in capabilities)"
+}
+
+// AddonCoverageGap is an add-on with no version compatible with some of a
+// profile's cluster versions
+type AddonCoverageGap struct {
+	AddonID  string   `json:"addon_id"`
+	Versions []string `json:"versions"`
+}
+
+// AddonDependency is an add-on pulled in or suggested by another add-on
+type AddonDependency struct {
+	AddonID    string `json:"addon_id"`
+	Version    string `json:"version,omitempty"`
+	RequiredBy string `json:"required_by"`
+}
+
+// SupportsArchitectures reports whether the add-on runs on every one of the
+// given node architectures. Add-ons that declare none run anywhere.
+func (m *AddonMetadata) SupportsArchitectures(archs []Architecture) bool {
+	if m == nil || len(m.SupportedArchitectures) == 0 {
+		return true
+	}
+	for _, want := range archs {
+		found := false
+		for _, a := range m.SupportedArchitectures {
+			if a == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ConflictsWithAddon reports whether the add-on declares a conflict with addonID.
+func (m *AddonMetadata) ConflictsWithAddon(addonID string) bool {
+	if m == nil {
+		return false
+	}
+	for _, id := range m.ConflictsWith {
+		if id == addonID {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAddonConflict returns the ID of the first add-on in installed that
+// conflicts with addon, checking ConflictsWith in both directions, or "" if
+// there is none. An installed entry with the same add-on ID is ignored so the
+// check can be reused when changing the version of an installed add-on.
+func FindAddonConflict(addon *PostConfigAddon, installed []PostConfigAddon) string {
+	for i := range installed {
+		other := &installed[i]
+		if other.AddonID == addon.AddonID {
+			continue
+		}
+		if addon.Metadata.ConflictsWithAddon(other.AddonID) || other.Metadata.ConflictsWithAddon(addon.AddonID) {
+			return other.AddonID
+		}
+	}
+	return ""
+}
+
+// ParseAddonRef splits a selected add-on reference of the form "addonID" or
+// "addonID:channel" into its ID and channel. The channel is empty when the
+// reference selects the add-on's default version.
+func ParseAddonRef(ref string) (addonID, channel string) {
+	if i := strings.Index(ref, ":"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// AddonRef builds a selected add-on reference from an add-on ID and channel.
+func AddonRef(addonID, channel string) string {
+	if channel == "" {
+		return addonID
+	}
+	return addonID + ":" + channel
 }
 
 // AddonSelection represents a user's selection of an add-on with a specific version
@@ -59,3 +169,69 @@ const (
 	AddonCategoryStorage    AddonCategory = "storage"
 	AddonCategoryNetworking AddonCategory = "networking"
 )
+
+// AddonWithVersions represents an add-on with all its versions
+type AddonWithVersions struct {
+	ID                 string         `json:"id" example:"oadp"`
+	Name               string         `json:"name" example:"OpenShift API for Data Protection (OADP)"`
+	Description        string         `json:"description" example:"Backup and restore OpenShift clusters and applications"`
+	Category           string         `json:"category" example:"backup"`
+	SupportedPlatforms []string       `json:"supportedPlatforms" example:"openshift"`
+	Enabled            bool           `json:"enabled" example:"true"`
+	Versions           VersionsInfo   `json:"versions"`
+	Metadata           *AddonMetadata `json:"metadata,omitempty"`
+	AddonSource        string         `json:"addonSource" example:"system"`
+	IsPublished        bool           `json:"isPublished" example:"true"`
+}
+
+// VersionsInfo contains version information
+type VersionsInfo struct {
+	Allowed []VersionOption `json:"allowed"`
+	Default string          `json:"default" example:"stable"`
+}
+
+// VersionOption represents a single version option
+type VersionOption struct {
+	Channel     string `json:"channel" example:"stable"`
+	DisplayName string `json:"displayName" example:"OADP 1.5 (Stable)"`
+}
+
+// AddonsListResponse represents the response from the list addons endpoint
+type AddonsListResponse struct {
+	Addons     []AddonWithVersions            `json:"addons"`
+	Categories map[string][]AddonWithVersions `json:"categories"`
+	Total      int                            `json:"total" example:"3"`
+}
+
+// CreateAddonRequest represents the request to create a new user addon
+type CreateAddonRequest struct {
+	AddonID            string           `json:"addonId" validate:"required,min=1,max=100"`
+	Name               string           `json:"name" validate:"required,min=1,max=200"`
+	Description        string           `json:"description" validate:"required"`
+	Category           string           `json:"category" validate:"required,oneof=backup migration cicd monitoring security storage networking virtualization"`
+	Config             CustomPostConfig `json:"config" validate:"required"`
+	SupportedPlatforms []string         `json:"supportedPlatforms" validate:"required,min=1"`
+	Version            string           `json:"version" validate:"required"`
+	DisplayName        string           `json:"displayName" validate:"required"`
+	IsDefault          bool             `json:"isDefault"`
+	Metadata           *AddonMetadata   `json:"metadata,omitempty"`
+}
+
+// UpdateAddonRequest represents the request to update an addon
+type UpdateAddonRequest struct {
+	Name               *string           `json:"name,omitempty" validate:"omitempty,min=1,max=200"`
+	Description        *string           `json:"description,omitempty"`
+	Category           *string           `json:"category,omitempty" validate:"omitempty,oneof=backup migration cicd monitoring security storage networking virtualization"`
+	Config             *CustomPostConfig `json:"config,omitempty"`
+	SupportedPlatforms []string          `json:"supportedPlatforms,omitempty" validate:"omitempty,min=1"`
+	Version            *string           `json:"version,omitempty"`
+	DisplayName        *string           `json:"displayName,omitempty"`
+	IsDefault          *bool             `json:"isDefault,omitempty"`
+	Metadata           *AddonMetadata    `json:"metadata,omitempty"`
+}
+
+// ClusterAddonRequest represents the request to install or change the version
+// of an add-on on a running cluster
+type ClusterAddonRequest struct {
+	Version string `json:"version,omitempty" example:"stable"` // Add-on version/channel (install: defaults to the add-on's default version)
+}
@@ -0,0 +1,43 @@
+package types
+
+import "encoding/json"
+
+// PaginationMeta holds pagination metadata for list responses
+type PaginationMeta struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// JobAcceptedResponse is returned by endpoints that start an async job
+type JobAcceptedResponse struct {
+	Message string `json:"message"`
+	JobID   string `json:"job_id"`
+}
+
+// ProfileSummary is a cluster profile as returned by GET /profiles.
+// Configuration sections are kept as raw JSON because their schema is owned
+// by the server's profile package and changes with it.
+type ProfileSummary struct {
+	Name               string                    `json:"name"`
+	DisplayName        string                    `json:"display_name"`
+	Description        string                    `json:"description"`
+	Platform           string                    `json:"platform"`
+	Track              string                    `json:"track,omitempty"`
+	Enabled            bool                      `json:"enabled"`
+	CredentialsMode    string                    `json:"credentials_mode,omitempty"`
+	OpenshiftVersions  json.RawMessage           `json:"openshift_versions,omitempty"`
+	KubernetesVersions json.RawMessage           `json:"kubernetes_versions,omitempty"`
+	Regions            json.RawMessage           `json:"regions,omitempty"`
+	BaseDomains        json.RawMessage           `json:"base_domains,omitempty"`
+	Compute            json.RawMessage           `json:"compute,omitempty"`
+	Lifecycle          json.RawMessage           `json:"lifecycle,omitempty"`
+	Networking         json.RawMessage           `json:"networking,omitempty"`
+	Tags               json.RawMessage           `json:"tags,omitempty"`
+	Features           json.RawMessage           `json:"features,omitempty"`
+	CostControls       json.RawMessage           `json:"cost_controls,omitempty"`
+	PostDeployment     json.RawMessage           `json:"post_deployment,omitempty"`
+	DefaultAddons      json.RawMessage           `json:"default_addons,omitempty"`
+	DeploymentMetrics  *ProfileDeploymentMetrics `json:"deployment_metrics,omitempty"`
+}
@@ -0,0 +1,73 @@
+package types
+
+// Architecture is a CPU architecture, named the way OpenShift and Kubernetes
+// name them
+type Architecture string
+
+const (
+	ArchitectureAMD64 Architecture = "amd64"
+	ArchitectureARM64 Architecture = "arm64"
+
+	// ArchitectureMulti is the multi-architecture release payload used when a
+	// cluster is not all amd64. It is never a node architecture.
+	ArchitectureMulti Architecture = "multi"
+)
+
+// ValidArchitecture reports whether a is a node architecture
+func ValidArchitecture(a Architecture) bool {
+	return a == ArchitectureAMD64 || a == ArchitectureARM64
+}
+
+// ClusterArchitecture selects the CPU architecture of the control plane and
+// compute nodes. Unset fields default to amd64; setting them differently gives
+// a heterogeneous cluster, e.g. an amd64 control plane with arm64 workers.
+type ClusterArchitecture struct {
+	ControlPlane Architecture `json:"control_plane,omitempty" yaml:"controlPlane,omitempty"`
+	Compute      Architecture `json:"compute,omitempty" yaml:"compute,omitempty"`
+}
+
+// IsEmpty reports whether no architecture is configured
+func (a *ClusterArchitecture) IsEmpty() bool {
+	return a == nil || (a.ControlPlane == "" && a.Compute == "")
+}
+
+// ControlPlaneArch returns the control plane architecture, amd64 when unset
+func (a *ClusterArchitecture) ControlPlaneArch() Architecture {
+	if a == nil || a.ControlPlane == "" {
+		return ArchitectureAMD64
+	}
+	return a.ControlPlane
+}
+
+// ComputeArch returns the compute architecture, amd64 when unset
+func (a *ClusterArchitecture) ComputeArch() Architecture {
+	if a == nil || a.Compute == "" {
+		return ArchitectureAMD64
+	}
+	return a.Compute
+}
+
+// Heterogeneous reports whether the control plane and compute nodes run
+// different architectures
+func (a *ClusterArchitecture) Heterogeneous() bool {
+	return a.ControlPlaneArch() != a.ComputeArch()
+}
+
+// Architectures returns the distinct node architectures in the cluster,
+// control plane first
+func (a *ClusterArchitecture) Architectures() []Architecture {
+	if a.Heterogeneous() {
+		return []Architecture{a.ControlPlaneArch(), a.ComputeArch()}
+	}
+	return []Architecture{a.ControlPlaneArch()}
+}
+
+// ReleaseArchitecture returns the OpenShift release payload the cluster needs:
+// amd64 for all-amd64 clusters and multi otherwise, since single-arch arm64
+// and heterogeneous installs both use the multi payload's installer
+func (a *ClusterArchitecture) ReleaseArchitecture() Architecture {
+	if a.ControlPlaneArch() == ArchitectureAMD64 && a.ComputeArch() == ArchitectureAMD64 {
+		return ArchitectureAMD64
+	}
+	return ArchitectureMulti
+}
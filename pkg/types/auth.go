@@ -67,4 +67,7 @@ type IdempotencyKey struct {
 	ResponseBody       []byte    `db:"response_body"`
 	CreatedAt          time.Time `db:"created_at"`
 	ExpiresAt          time.Time `db:"expires_at"`
+	// ReservedUntil bounds how long a reservation without a response blocks
+	// retries, in case its request never finishes
+	ReservedUntil *time.Time `db:"reserved_until"`
 }
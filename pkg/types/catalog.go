@@ -0,0 +1,59 @@
+package types
+
+// CatalogPackage is an operator package in an OperatorHub catalog
+type CatalogPackage struct {
+	Name           string           `json:"name" example:"redhat-oadp-operator"`
+	Catalog        string           `json:"catalog" example:"redhat-operators"`
+	DisplayName    string           `json:"displayName" example:"OADP Operator"`
+	Description    string           `json:"description,omitempty"`
+	Provider       string           `json:"provider,omitempty" example:"Red Hat"`
+	DefaultChannel string           `json:"defaultChannel" example:"stable"`
+	Channels       []CatalogChannel `json:"channels"`
+}
+
+// CatalogChannel is a package's update channel and the bundle at its head
+type CatalogChannel struct {
+	Name       string `json:"name" example:"stable"`
+	CurrentCSV string `json:"currentCSV" example:"oadp-operator.v1.5.1"`
+	Version    string `json:"version,omitempty" example:"1.5.1"`
+}
+
+// CatalogPackageDetail describes the bundle at the head of one of a
+// package's channels
+type CatalogPackageDetail struct {
+	CatalogPackage
+	Channel            string           `json:"channel" example:"stable"`
+	InstallModes       []string         `json:"installModes,omitempty" example:"OwnNamespace,AllNamespaces"`
+	SuggestedNamespace string           `json:"suggestedNamespace,omitempty" example:"openshift-adp"`
+	Examples           []CatalogExample `json:"examples"` // The CSV's alm-examples
+}
+
+// CatalogExample is an example custom resource published by an operator
+type CatalogExample struct {
+	APIVersion string                 `json:"apiVersion" example:"oadp.openshift.io/v1alpha1"`
+	Kind       string                 `json:"kind" example:"DataProtectionApplication"`
+	Name       string                 `json:"name,omitempty" example:"velero-sample"`
+	Namespace  string                 `json:"namespace,omitempty"`
+	Spec       map[string]interface{} `json:"spec,omitempty"`
+}
+
+// CatalogPackagesResponse lists the packages of an OperatorHub catalog
+type CatalogPackagesResponse struct {
+	Catalog  string           `json:"catalog" example:"redhat-operators"`
+	Version  string           `json:"version" example:"4.18"`
+	Packages []CatalogPackage `json:"packages"`
+	Total    int              `json:"total" example:"1"`
+}
+
+// GenerateCatalogAddonRequest generates a draft add-on that installs an
+// operator from an OperatorHub catalog
+type GenerateCatalogAddonRequest struct {
+	Version     string `json:"version" validate:"required" example:"4.18"` // OpenShift version whose catalog is read
+	Catalog     string `json:"catalog" validate:"required" example:"redhat-operators"`
+	Package     string `json:"package" validate:"required" example:"redhat-oadp-operator"`
+	Channel     string `json:"channel,omitempty" example:"stable"` // Defaults to the package's default channel
+	Category    string `json:"category" validate:"required,oneof=backup migration cicd monitoring security storage networking virtualization"`
+	AddonID     string `json:"addonId,omitempty" validate:"omitempty,min=1,max=100"`      // Defaults to the package name
+	Namespace   string `json:"namespace,omitempty"`                                       // Defaults to the operator's suggested namespace
+	ExampleKind string `json:"exampleKind,omitempty" example:"DataProtectionApplication"` // alm-example created as the operator's custom resource
+}
@@ -31,6 +31,7 @@ const (
 	PlatformIBMCloud Platform = "ibmcloud"
 	PlatformGCP      Platform = "gcp"
 	PlatformAzure    Platform = "azure"
+	PlatformLocal    Platform = "local" // Clusters on the worker host, for end-to-end testing
 )
 
 // ClusterType represents the type of Kubernetes cluster
@@ -44,6 +45,8 @@ const (
 	ClusterTypeGKE       ClusterType = "gke"       // Google Kubernetes Engine
 	ClusterTypeARO       ClusterType = "aro"       // Azure Red Hat OpenShift (managed)
 	ClusterTypeAKS       ClusterType = "aks"       // Azure Kubernetes Service
+	ClusterTypeHCP       ClusterType = "hcp"       // Hosted control planes (HyperShift) on a management cluster
+	ClusterTypeKind      ClusterType = "kind"      // Kubernetes in Docker on the worker host
 )
 
 // Tags is a map of key-value pairs stored as JSONB
@@ -108,43 +111,46 @@ func ProvenanceTags(clusterID, clusterName string, createdAt time.Time) map[stri
 
 // Cluster represents a cluster record in the database
 type Cluster struct {
-	ID                    string            `db:"id" json:"id"`
-	Name                  string            `db:"name" json:"name"`
-	Platform              Platform          `db:"platform" json:"platform"`
-	ClusterType           ClusterType       `db:"cluster_type" json:"cluster_type"`
-	Version               string            `db:"version" json:"version"`
-	Profile               string            `db:"profile" json:"profile"`
-	Region                string            `db:"region" json:"region"`
-	BaseDomain            *string           `db:"base_domain" json:"base_domain,omitempty"`
-	Owner                 string            `db:"owner" json:"owner"`       // Email for display/metadata
-	OwnerID               string            `db:"owner_id" json:"owner_id"` // Foreign key to users table
-	Team                  string            `db:"team" json:"team"`
-	CostCenter            string            `db:"cost_center" json:"cost_center"`
-	Status                ClusterStatus     `db:"status" json:"status"`
-	RequestedBy           string            `db:"requested_by" json:"requested_by"` // IAM principal ARN
-	TTLHours              int               `db:"ttl_hours" json:"ttl_hours"`
-	DestroyAt             *time.Time        `db:"destroy_at" json:"destroy_at"`
-	CreatedAt             time.Time         `db:"created_at" json:"created_at"`
-	UpdatedAt             time.Time         `db:"updated_at" json:"updated_at"`
-	DestroyedAt           *time.Time        `db:"destroyed_at" json:"destroyed_at"`
-	RequestTags           Tags              `db:"request_tags" json:"request_tags"`
-	EffectiveTags         Tags              `db:"effective_tags" json:"effective_tags"`
-	SSHPublicKey          *string           `db:"ssh_public_key" json:"ssh_public_key"`
-	OffhoursOptIn         bool              `db:"offhours_opt_in" json:"offhours_opt_in"`
-	WorkHoursEnabled      *bool             `db:"work_hours_enabled" json:"work_hours_enabled"` // NULL = use user default
-	WorkHoursStart        *time.Time        `db:"work_hours_start" json:"work_hours_start"`
-	WorkHoursEnd          *time.Time        `db:"work_hours_end" json:"work_hours_end"`
-	WorkDays              *int16            `db:"work_days" json:"work_days"`
-	LastWorkHoursCheck    *time.Time        `db:"last_work_hours_check" json:"last_work_hours_check"`
-	PostDeployStatus      *string           `db:"post_deploy_status" json:"post_deploy_status,omitempty"`
-	PostDeployCompletedAt *time.Time        `db:"post_deploy_completed_at" json:"post_deploy_completed_at,omitempty"`
-	SkipPostDeployment    bool              `db:"skip_post_deployment" json:"skip_post_deployment"`
-	CustomPostConfig      *CustomPostConfig `db:"custom_post_config" json:"custom_post_config,omitempty"`
-	SelectedAddonIDs      []string          `db:"selected_addon_ids" json:"selected_addon_ids,omitempty"`
-	StorageConfig         *StorageConfig    `db:"storage_config" json:"storage_config,omitempty"`
-	PreserveOnFailure     bool              `db:"preserve_on_failure" json:"preserve_on_failure"`
-	CredentialsMode       *string           `db:"credentials_mode" json:"credentials_mode,omitempty"`
-	CustomPullSecret      *string           `db:"custom_pull_secret" json:"custom_pull_secret,omitempty"` // Optional custom pull secret JSON to merge
+	ID                    string               `db:"id" json:"id"`
+	Name                  string               `db:"name" json:"name"`
+	Platform              Platform             `db:"platform" json:"platform"`
+	ClusterType           ClusterType          `db:"cluster_type" json:"cluster_type"`
+	Version               string               `db:"version" json:"version"`
+	Profile               string               `db:"profile" json:"profile"`
+	Region                string               `db:"region" json:"region"`
+	BaseDomain            *string              `db:"base_domain" json:"base_domain,omitempty"`
+	Owner                 string               `db:"owner" json:"owner"`       // Email for display/metadata
+	OwnerID               string               `db:"owner_id" json:"owner_id"` // Foreign key to users table
+	Team                  string               `db:"team" json:"team"`
+	CostCenter            string               `db:"cost_center" json:"cost_center"`
+	Status                ClusterStatus        `db:"status" json:"status"`
+	RequestedBy           string               `db:"requested_by" json:"requested_by"` // IAM principal ARN
+	TTLHours              int                  `db:"ttl_hours" json:"ttl_hours"`
+	DestroyAt             *time.Time           `db:"destroy_at" json:"destroy_at"`
+	CreatedAt             time.Time            `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time            `db:"updated_at" json:"updated_at"`
+	DestroyedAt           *time.Time           `db:"destroyed_at" json:"destroyed_at"`
+	RequestTags           Tags                 `db:"request_tags" json:"request_tags"`
+	EffectiveTags         Tags                 `db:"effective_tags" json:"effective_tags"`
+	SSHPublicKey          *string              `db:"ssh_public_key" json:"ssh_public_key"`
+	OffhoursOptIn         bool                 `db:"offhours_opt_in" json:"offhours_opt_in"`
+	WorkHoursEnabled      *bool                `db:"work_hours_enabled" json:"work_hours_enabled"` // NULL = use user default
+	WorkHoursStart        *time.Time           `db:"work_hours_start" json:"work_hours_start"`
+	WorkHoursEnd          *time.Time           `db:"work_hours_end" json:"work_hours_end"`
+	WorkDays              *int16               `db:"work_days" json:"work_days"`
+	LastWorkHoursCheck    *time.Time           `db:"last_work_hours_check" json:"last_work_hours_check"`
+	PostDeployStatus      *string              `db:"post_deploy_status" json:"post_deploy_status,omitempty"`
+	PostDeployCompletedAt *time.Time           `db:"post_deploy_completed_at" json:"post_deploy_completed_at,omitempty"`
+	SkipPostDeployment    bool                 `db:"skip_post_deployment" json:"skip_post_deployment"`
+	CustomPostConfig      *CustomPostConfig    `db:"custom_post_config" json:"custom_post_config,omitempty"`
+	SelectedAddonIDs      []string             `db:"selected_addon_ids" json:"selected_addon_ids,omitempty"`
+	StorageConfig         *StorageConfig       `db:"storage_config" json:"storage_config,omitempty"`
+	PreserveOnFailure     bool                 `db:"preserve_on_failure" json:"preserve_on_failure"`
+	CredentialsMode       *string              `db:"credentials_mode" json:"credentials_mode,omitempty"`
+	CustomPullSecret      *string              `db:"custom_pull_secret" json:"custom_pull_secret,omitempty"` // Optional custom pull secret JSON to merge
+	ComputeOverrides      *ComputeOverrides    `db:"compute_overrides" json:"compute_overrides,omitempty"`   // Per-cluster compute shape (nil = profile defaults)
+	RestrictedNetwork     *RestrictedNetwork   `db:"restricted_network" json:"restricted_network,omitempty"` // Request-level proxy, trust bundle and mirrors (merged over the profile's)
+	Architecture          *ClusterArchitecture `db:"architecture" json:"architecture,omitempty"`             // Request-level node architectures (merged over the profile's)
 
 	// Cluster pool tracking
 	PoolID         *string                `db:"pool_id" json:"pool_id,omitempty"`
@@ -266,3 +272,120 @@ type DestroyAudit struct {
 	CreatedAt            time.Time   `db:"created_at" json:"created_at"`
 	CompletedAt          *time.Time  `db:"completed_at" json:"completed_at,omitempty"`
 }
+
+// CreateClusterAPIRequest represents the API request to create a cluster
+type CreateClusterAPIRequest struct {
+	Name               string               `json:"name" validate:"required,min=3,max=63,cluster_name"`
+	Platform           string               `json:"platform" validate:"required,oneof=aws ibmcloud gcp azure local"`
+	ClusterType        string               `json:"cluster_type" validate:"required,oneof=openshift rosa eks iks gke aro aks hcp kind"`
+	Version            string               `json:"version" validate:"required"`
+	Profile            string               `json:"profile" validate:"required"`
+	Region             string               `json:"region" validate:"required"`
+	BaseDomain         string               `json:"base_domain,omitempty"` // Only required for OpenShift
+	Owner              string               `json:"owner" validate:"required,email"`
+	Team               string               `json:"team" validate:"required"`
+	CostCenter         string               `json:"cost_center" validate:"required"`
+	TTLHours           *int                 `json:"ttl_hours,omitempty"`
+	SSHPublicKey       *string              `json:"ssh_public_key,omitempty"`
+	ExtraTags          map[string]string    `json:"extra_tags,omitempty"`
+	OffhoursOptIn      bool                 `json:"offhours_opt_in,omitempty"`
+	WorkHoursEnabled   *bool                `json:"work_hours_enabled,omitempty"`
+	WorkHours          *WorkHoursSchedule   `json:"work_hours,omitempty"`
+	SkipPostDeployment bool                 `json:"skip_post_deployment,omitempty"`
+	EnableEFSStorage   bool                 `json:"enable_efs_storage,omitempty"`
+	PostConfigAddOns   []AddonSelection     `json:"postConfigAddOns,omitempty"` // Pre-approved add-ons with version selection
+	CustomPostConfig   *CustomPostConfig    `json:"customPostConfig,omitempty"` // Custom post-deployment operators, scripts, and manifests
+	PreserveOnFailure  bool                 `json:"preserve_on_failure,omitempty"`
+	CredentialsMode    *string              `json:"credentials_mode,omitempty" validate:"omitempty,oneof=Auto Manual Passthrough Mint Static"`
+	CustomPullSecret   *string              `json:"custom_pull_secret,omitempty"` // Optional custom pull secret JSON to merge with standard pull secret
+	ComputeOverrides   *ComputeOverrides    `json:"compute_overrides,omitempty"`  // Worker count/type, root volume and extra pools within the profile's overridable bounds
+	RestrictedNetwork  *RestrictedNetwork   `json:"restricted_network,omitempty"` // Proxy, trust bundle and image mirrors; each field replaces the profile's
+	Architecture       *ClusterArchitecture `json:"architecture,omitempty"`       // Control plane and compute architectures; each field replaces the profile's
+	IdempotencyKey     string               `json:"idempotency_key,omitempty"`    // Deprecated: send the Idempotency-Key header instead
+}
+
+// ExtendClusterRequest represents the API request to extend cluster TTL
+type ExtendClusterRequest struct {
+	TTLHours int `json:"ttl_hours" validate:"required,min=1"`
+}
+
+// EC2Instance represents an EC2 instance with relevant details
+type EC2Instance struct {
+	InstanceID       string    `json:"instance_id"`
+	InstanceType     string    `json:"instance_type"`
+	State            string    `json:"state"`
+	PrivateIPAddress *string   `json:"private_ip_address"`
+	PublicIPAddress  *string   `json:"public_ip_address"`
+	LaunchTime       time.Time `json:"launch_time"`
+	Name             string    `json:"name"`
+}
+
+// ClusterInstance represents a generic instance across cloud platforms
+type ClusterInstance struct {
+	InstanceID       string            `json:"instance_id"`
+	InstanceType     string            `json:"instance_type"`
+	State            string            `json:"state"`
+	PrivateIPAddress *string           `json:"private_ip_address,omitempty"`
+	PublicIPAddress  *string           `json:"public_ip_address,omitempty"`
+	LaunchTime       *time.Time        `json:"launch_time,omitempty"`
+	Name             string            `json:"name"`
+	Zone             string            `json:"zone,omitempty"`
+	Platform         string            `json:"platform"` // aws, gcp, ibmcloud
+	Labels           map[string]string `json:"labels,omitempty"`
+	MachineType      string            `json:"machine_type,omitempty"` // GCP-specific
+}
+
+// StorageClass represents a Kubernetes storage class
+type StorageClass struct {
+	Name              string `json:"name"`
+	Provisioner       string `json:"provisioner"`
+	ReclaimPolicy     string `json:"reclaim_policy,omitempty"`
+	VolumeBindingMode string `json:"volume_binding_mode,omitempty"`
+	IsDefault         bool   `json:"is_default"`
+}
+
+// ClusterOutputsResponse represents the cluster outputs (kubeconfig, credentials, etc.)
+type ClusterOutputsResponse struct {
+	ClusterID          string                `json:"cluster_id"`
+	ClusterName        string                `json:"cluster_name"`
+	Status             string                `json:"status"`
+	APIUrl             string                `json:"api_url,omitempty"`
+	ConsoleURL         string                `json:"console_url,omitempty"`
+	DashboardToken     string                `json:"dashboard_token,omitempty"`      // Kubernetes Dashboard bearer token
+	Kubeconfig         string                `json:"kubeconfig,omitempty"`           // Full kubeconfig content
+	KubeconfigS3URI    string                `json:"kubeconfig_s3_uri,omitempty"`    // S3 URI to kubeconfig file
+	Kubeadmin          *KubeadminCredentials `json:"kubeadmin,omitempty"`            // Actual credentials
+	KubeadminSecretRef string                `json:"kubeadmin_secret_ref,omitempty"` // Reference to secret location
+
+	// ServiceAccount credentials for pool clusters
+	SAName           string     `json:"sa_name,omitempty"`
+	SANamespace      string     `json:"sa_namespace,omitempty"`
+	SAToken          string     `json:"sa_token,omitempty"`
+	SATokenExpiresAt *time.Time `json:"sa_token_expires_at,omitempty"`
+	OcLoginCommand   string     `json:"oc_login_command,omitempty"`
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// KubeadminCredentials holds the kubeadmin username and password
+type KubeadminCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// KubeconfigDownload is a short-lived link to a cluster's kubeconfig
+type KubeconfigDownload struct {
+	DownloadURL string `json:"download_url"`
+	ExpiresIn   string `json:"expires_in"`
+	Filename    string `json:"filename"`
+	StorageType string `json:"storage_type"`
+}
+
+// ClusterConfigurationList is the response of GET /clusters/{id}/configurations
+type ClusterConfigurationList struct {
+	ClusterID      string                  `json:"cluster_id"`
+	ClusterName    string                  `json:"cluster_name"`
+	Configurations []*ClusterConfiguration `json:"configurations"`
+	Total          int                     `json:"total"`
+	JobRetryInfo   json.RawMessage         `json:"job_retry_info,omitempty"`
+}
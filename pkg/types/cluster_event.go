@@ -0,0 +1,36 @@
+package types
+
+import "time"
+
+// ClusterEventType identifies the kind of lifecycle transition a cluster event records
+type ClusterEventType string
+
+const (
+	ClusterEventCreated       ClusterEventType = "CREATED"        // Cluster record created
+	ClusterEventStatusChanged ClusterEventType = "STATUS_CHANGED" // Cluster status changed
+	ClusterEventLeased        ClusterEventType = "LEASED"         // Pool cluster leased
+	ClusterEventReleased      ClusterEventType = "RELEASED"       // Pool cluster lease released
+)
+
+// ClusterEvent records a single cluster lifecycle transition, who caused it and why.
+// For CREATED and STATUS_CHANGED events FromStatus and ToStatus are cluster
+// statuses; for LEASED and RELEASED events they are pool states.
+type ClusterEvent struct {
+	ID          int64            `json:"id" db:"id"`
+	ClusterID   string           `json:"cluster_id" db:"cluster_id"`
+	ClusterName string           `json:"cluster_name" db:"cluster_name"`
+	Type        ClusterEventType `json:"type" db:"event_type"`
+	FromStatus  string           `json:"from_status,omitempty" db:"from_status"`
+	ToStatus    string           `json:"to_status" db:"to_status"`
+	Actor       string           `json:"actor" db:"actor"`
+	Reason      string           `json:"reason,omitempty" db:"reason"`
+	JobID       string           `json:"job_id,omitempty" db:"job_id"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+}
+
+// ClusterEventList is a page of a cluster's events, oldest first
+type ClusterEventList struct {
+	Events      []*ClusterEvent `json:"events"`
+	NextAfterID int64           `json:"next_after_id"`
+	HasMore     bool            `json:"has_more"`
+}
@@ -0,0 +1,247 @@
+package types
+
+import "time"
+
+const (
+	// ClusterSpecAPIVersion is the apiVersion of desired-state documents
+	ClusterSpecAPIVersion = "ocpctl.io/v1"
+	// ClusterSpecKind is the kind of desired-state documents
+	ClusterSpecKind = "ClusterSpec"
+)
+
+// ClusterSpecDocument is a declarative desired-state document, usually kept in git:
+//
+//	apiVersion: ocpctl.io/v1
+//	kind: ClusterSpec
+//	metadata:
+//	  name: team-a
+//	spec:
+//	  clusters: [...]
+//	  pools: [...]
+//	  storageLinks: [...]
+type ClusterSpecDocument struct {
+	APIVersion string              `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string              `json:"kind" yaml:"kind"`
+	Metadata   ClusterSpecMetadata `json:"metadata" yaml:"metadata"`
+	Spec       DesiredState        `json:"spec" yaml:"spec"`
+}
+
+// ClusterSpecMetadata identifies a desired-state document
+type ClusterSpecMetadata struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+// DesiredState lists the resources a cluster spec manages
+type DesiredState struct {
+	Clusters     []DesiredCluster     `json:"clusters,omitempty" yaml:"clusters,omitempty"`
+	Pools        []DesiredPool        `json:"pools,omitempty" yaml:"pools,omitempty"`
+	StorageLinks []DesiredStorageLink `json:"storageLinks,omitempty" yaml:"storageLinks,omitempty"`
+}
+
+// DesiredCluster declares a cluster. Platform, cluster type, version, profile,
+// region and base domain cannot change once the cluster exists.
+type DesiredCluster struct {
+	Name               string            `json:"name" yaml:"name"`
+	Platform           string            `json:"platform" yaml:"platform"`
+	ClusterType        string            `json:"clusterType" yaml:"clusterType"`
+	Version            string            `json:"version" yaml:"version"`
+	Profile            string            `json:"profile" yaml:"profile"`
+	Region             string            `json:"region" yaml:"region"`
+	BaseDomain         string            `json:"baseDomain,omitempty" yaml:"baseDomain,omitempty"`
+	Owner              string            `json:"owner" yaml:"owner"` // Owner email
+	Team               string            `json:"team" yaml:"team"`
+	CostCenter         string            `json:"costCenter" yaml:"costCenter"`
+	TTLHours           *int              `json:"ttlHours,omitempty" yaml:"ttlHours,omitempty"`
+	OffhoursOptIn      bool              `json:"offhoursOptIn,omitempty" yaml:"offhoursOptIn,omitempty"`
+	SkipPostDeployment bool              `json:"skipPostDeployment,omitempty" yaml:"skipPostDeployment,omitempty"`
+	Addons             []AddonSelection  `json:"addons,omitempty" yaml:"addons,omitempty"`
+	ExtraTags          map[string]string `json:"extraTags,omitempty" yaml:"extraTags,omitempty"`
+}
+
+// DesiredPool declares a cluster pool. Unset optional fields take the same
+// defaults as POST /admin/pools.
+type DesiredPool struct {
+	Name                      string `json:"name" yaml:"name"`
+	DisplayName               string `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Description               string `json:"description,omitempty" yaml:"description,omitempty"`
+	Profile                   string `json:"profile" yaml:"profile"`
+	TargetSize                int    `json:"targetSize" yaml:"targetSize"`
+	MinSize                   int    `json:"minSize,omitempty" yaml:"minSize,omitempty"`
+	MaxSize                   int    `json:"maxSize,omitempty" yaml:"maxSize,omitempty"`
+	DefaultLeaseDurationHours *int   `json:"defaultLeaseDurationHours,omitempty" yaml:"defaultLeaseDurationHours,omitempty"`
+	MaxLeaseDurationHours     *int   `json:"maxLeaseDurationHours,omitempty" yaml:"maxLeaseDurationHours,omitempty"`
+	AutoReleaseEnabled        *bool  `json:"autoReleaseEnabled,omitempty" yaml:"autoReleaseEnabled,omitempty"`
+	MaxClusterAgeDays         *int   `json:"maxClusterAgeDays,omitempty" yaml:"maxClusterAgeDays,omitempty"`
+	AutoRefreshEnabled        *bool  `json:"autoRefreshEnabled,omitempty" yaml:"autoRefreshEnabled,omitempty"`
+	Enabled                   *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+}
+
+// DesiredStorageLink declares shared storage between two clusters, by name.
+// Both clusters must be declared in the same spec.
+type DesiredStorageLink struct {
+	Source string `json:"source" yaml:"source"`
+	Target string `json:"target" yaml:"target"`
+}
+
+// ClusterSpecSource identifies where a cluster spec's document comes from
+type ClusterSpecSource string
+
+const (
+	ClusterSpecSourceUpload ClusterSpecSource = "upload" // Document uploaded through the API
+	ClusterSpecSourceGit    ClusterSpecSource = "git"    // Document fetched from a git repository
+)
+
+// IsValid checks if the source is valid
+func (s ClusterSpecSource) IsValid() bool {
+	switch s {
+	case ClusterSpecSourceUpload, ClusterSpecSourceGit:
+		return true
+	default:
+		return false
+	}
+}
+
+// ClusterSpecSyncStatus is the outcome of the last reconcile of a cluster spec
+type ClusterSpecSyncStatus string
+
+const (
+	ClusterSpecSyncPending   ClusterSpecSyncStatus = "PENDING"
+	ClusterSpecSyncSucceeded ClusterSpecSyncStatus = "SUCCEEDED"
+	ClusterSpecSyncFailed    ClusterSpecSyncStatus = "FAILED"
+)
+
+// ClusterSpec is a registered desired-state document and its sync state
+type ClusterSpec struct {
+	ID                  string                `json:"id" db:"id"`
+	Name                string                `json:"name" db:"name"`
+	Source              ClusterSpecSource     `json:"source" db:"source"`
+	GitURL              *string               `json:"git_url,omitempty" db:"git_url"`
+	GitRef              *string               `json:"git_ref,omitempty" db:"git_ref"`
+	GitPath             *string               `json:"git_path,omitempty" db:"git_path"`
+	SyncIntervalMinutes int                   `json:"sync_interval_minutes" db:"sync_interval_minutes"`
+	Prune               bool                  `json:"prune" db:"prune"`
+	Document            *ClusterSpecDocument  `json:"document,omitempty" db:"document"`
+	Revision            *string               `json:"revision,omitempty" db:"revision"`
+	LastPlan            *ClusterSpecPlan      `json:"last_plan,omitempty" db:"last_plan"`
+	LastSyncStatus      ClusterSpecSyncStatus `json:"last_sync_status" db:"last_sync_status"`
+	LastSyncError       *string               `json:"last_sync_error,omitempty" db:"last_sync_error"`
+	LastSyncedAt        *time.Time            `json:"last_synced_at,omitempty" db:"last_synced_at"`
+	OwnerID             string                `json:"owner_id" db:"owner_id"`
+	CreatedAt           time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// ClusterSpecResourceKind identifies the kind of resource a cluster spec manages
+type ClusterSpecResourceKind string
+
+const (
+	ClusterSpecResourceCluster     ClusterSpecResourceKind = "cluster"
+	ClusterSpecResourcePool        ClusterSpecResourceKind = "pool"
+	ClusterSpecResourceStorageLink ClusterSpecResourceKind = "storage_link"
+)
+
+// ClusterSpecResource records a resource created by a cluster spec. Only
+// recorded resources are updated or destroyed when the document changes.
+type ClusterSpecResource struct {
+	SpecID     string                  `json:"spec_id" db:"spec_id"`
+	Kind       ClusterSpecResourceKind `json:"kind" db:"kind"`
+	Name       string                  `json:"name" db:"name"`
+	ResourceID string                  `json:"resource_id" db:"resource_id"`
+	CreatedAt  time.Time               `json:"created_at" db:"created_at"`
+}
+
+// PlanActionType is what reconciling does to a resource
+type PlanActionType string
+
+const (
+	PlanActionCreate    PlanActionType = "create"
+	PlanActionUpdate    PlanActionType = "update"
+	PlanActionDestroy   PlanActionType = "destroy"
+	PlanActionUnmanage  PlanActionType = "unmanage" // Removed from the spec with prune disabled: left running
+	PlanActionBlocked   PlanActionType = "blocked"  // Cannot be reconciled automatically; see Reason
+	PlanActionWaiting   PlanActionType = "waiting"  // Depends on resources that are not ready yet
+	PlanActionLink      PlanActionType = "link"
+	PlanActionUnlink    PlanActionType = "unlink"
+	PlanActionUnchanged PlanActionType = "unchanged"
+)
+
+// PlanFieldChange is a single field difference between desired and current state
+type PlanFieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// PlanAction is one step of a cluster spec plan
+type PlanAction struct {
+	Action  PlanActionType          `json:"action"`
+	Kind    ClusterSpecResourceKind `json:"kind"`
+	Name    string                  `json:"name"`
+	Changes []PlanFieldChange       `json:"changes,omitempty"`
+	Reason  string                  `json:"reason,omitempty"`
+}
+
+// ClusterSpecPlan is the diff between a desired-state document and current state
+type ClusterSpecPlan struct {
+	Revision  string       `json:"revision,omitempty"`
+	Actions   []PlanAction `json:"actions"`
+	Unchanged int          `json:"unchanged"`
+}
+
+// HasChanges reports whether applying the plan would do anything
+func (p *ClusterSpecPlan) HasChanges() bool {
+	for _, a := range p.Actions {
+		switch a.Action {
+		case PlanActionCreate, PlanActionUpdate, PlanActionDestroy, PlanActionUnmanage, PlanActionLink, PlanActionUnlink:
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyResult is the outcome of applying one plan action
+type ApplyResult struct {
+	PlanAction
+	Applied    bool   `json:"applied"`
+	ResourceID string `json:"resource_id,omitempty"`
+	JobID      string `json:"job_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CreateClusterSpecRequest registers a cluster spec. Git specs are synced
+// periodically; upload specs are reconciled whenever a document is uploaded.
+type CreateClusterSpecRequest struct {
+	Name                string               `json:"name" validate:"required,min=3,max=63,alphanum-dash"`
+	Source              ClusterSpecSource    `json:"source" validate:"required"`
+	GitURL              *string              `json:"git_url,omitempty"`
+	GitRef              *string              `json:"git_ref,omitempty"`
+	GitPath             *string              `json:"git_path,omitempty"`
+	SyncIntervalMinutes int                  `json:"sync_interval_minutes,omitempty" validate:"omitempty,min=1,max=1440"`
+	Prune               bool                 `json:"prune,omitempty"`
+	Document            *ClusterSpecDocument `json:"document,omitempty"`
+}
+
+// UpdateClusterSpecRequest updates the settings of a cluster spec
+type UpdateClusterSpecRequest struct {
+	GitURL              *string `json:"git_url,omitempty"`
+	GitRef              *string `json:"git_ref,omitempty"`
+	GitPath             *string `json:"git_path,omitempty"`
+	SyncIntervalMinutes *int    `json:"sync_interval_minutes,omitempty" validate:"omitempty,min=1,max=1440"`
+	Prune               *bool   `json:"prune,omitempty"`
+}
+
+// ClusterSpecSyncResult is the outcome of syncing a cluster spec. Results is
+// empty for a dry run.
+type ClusterSpecSyncResult struct {
+	Spec     string           `json:"spec"`
+	Revision string           `json:"revision,omitempty"`
+	DryRun   bool             `json:"dry_run"`
+	Plan     *ClusterSpecPlan `json:"plan"`
+	Results  []ApplyResult    `json:"results,omitempty"`
+}
+
+// ClusterSpecDetail is a cluster spec with the resources it manages
+type ClusterSpecDetail struct {
+	*ClusterSpec
+	Resources []*ClusterSpecResource `json:"resources"`
+}
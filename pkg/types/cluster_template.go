@@ -16,3 +16,10 @@ type ClusterTemplate struct {
 	CreatedAt time.Time       `db:"created_at" json:"createdAt"`
 	UpdatedAt time.Time       `db:"updated_at" json:"updatedAt"`
 }
+
+// ClusterTemplateRequest is the body for creating or updating a cluster template.
+// Config is a partial create-cluster payload captured from the creation form.
+type ClusterTemplateRequest struct {
+	Name   string          `json:"name" validate:"required,min=3,max=100"`
+	Config json.RawMessage `json:"config" validate:"required"`
+}
@@ -0,0 +1,29 @@
+package types
+
+// ComputeOverrides adjusts a profile's compute shape for a single cluster.
+// Every field is optional; unset fields keep the profile default. Overrides are
+// only accepted when the profile declares an overridable section and each value
+// falls within the bounds it allows.
+type ComputeOverrides struct {
+	WorkerReplicas     *int                  `json:"worker_replicas,omitempty" yaml:"workerReplicas,omitempty"`
+	WorkerInstanceType string                `json:"worker_instance_type,omitempty" yaml:"workerInstanceType,omitempty"`
+	RootVolumeGB       *int                  `json:"root_volume_gb,omitempty" yaml:"rootVolumeGB,omitempty"`
+	MachinePools       []MachinePoolOverride `json:"machine_pools,omitempty" yaml:"machinePools,omitempty"`
+	// OnDemand replaces any spot worker capacity in the profile with
+	// on-demand capacity. Set by the worker when preflight finds no spot
+	// capacity and the profile allows falling back; needs no overridable
+	// section.
+	OnDemand bool `json:"on_demand,omitempty" yaml:"onDemand,omitempty"`
+}
+
+// MachinePoolOverride is an extra worker pool created alongside the default one
+type MachinePoolOverride struct {
+	Name         string `json:"name" yaml:"name"`
+	InstanceType string `json:"instance_type" yaml:"instanceType"`
+	Replicas     int    `json:"replicas" yaml:"replicas"`
+}
+
+// IsEmpty reports whether the overrides leave the profile shape unchanged
+func (o *ComputeOverrides) IsEmpty() bool {
+	return o == nil || (o.WorkerReplicas == nil && o.WorkerInstanceType == "" && o.RootVolumeGB == nil && len(o.MachinePools) == 0 && !o.OnDemand)
+}
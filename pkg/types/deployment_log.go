@@ -41,3 +41,20 @@ type DeploymentLogStats struct {
 	WarnCount   int       `json:"warn_count"`
 	LastUpdated time.Time `json:"last_updated"`
 }
+
+// ClusterLogs is the response of GET /clusters/{id}/logs
+type ClusterLogs struct {
+	Logs []*DeploymentLog `json:"logs"`
+	Meta ClusterLogsMeta  `json:"meta"`
+}
+
+// ClusterLogsMeta describes a page of cluster logs
+type ClusterLogsMeta struct {
+	ClusterID     string              `json:"cluster_id"`
+	JobID         string              `json:"job_id"`
+	AfterID       int64               `json:"after_id,omitempty"`
+	AfterSequence int64               `json:"after_sequence"`
+	Limit         int                 `json:"limit"`
+	Count         int                 `json:"count"`
+	Stats         *DeploymentLogStats `json:"stats,omitempty"`
+}
@@ -0,0 +1,40 @@
+package types
+
+import "time"
+
+// ConfigDriftStatus is the outcome of a post-config drift check
+type ConfigDriftStatus string
+
+const (
+	ConfigDriftInSync     ConfigDriftStatus = "IN_SYNC"    // Every checked task matches the recorded configuration
+	ConfigDriftDrifted    ConfigDriftStatus = "DRIFTED"    // Drift was found and left in place
+	ConfigDriftReconciled ConfigDriftStatus = "RECONCILED" // Drift was found and the drifted tasks were re-applied
+	ConfigDriftFailed     ConfigDriftStatus = "FAILED"     // The check or the re-apply failed; see Error
+)
+
+// ConfigDriftItem is a post-config task whose cluster state no longer matches
+// the recorded configuration
+type ConfigDriftItem struct {
+	Task       string     `json:"task"`
+	Type       ConfigType `json:"type"`
+	Source     string     `json:"source"` // Add-on ID, or "custom" for the cluster's own post-config
+	Reason     string     `json:"reason"`
+	Reconciled bool       `json:"reconciled"`
+}
+
+// ConfigDriftReport is the result of the most recent drift check of a cluster.
+// Scripts cannot be inspected after they run and are not checked.
+type ConfigDriftReport struct {
+	ClusterID string            `json:"cluster_id" db:"cluster_id"`
+	JobID     *string           `json:"job_id,omitempty" db:"job_id"`
+	Status    ConfigDriftStatus `json:"status" db:"status"`
+	Checked   int               `json:"checked" db:"checked"` // Number of tasks compared against the cluster
+	Items     []ConfigDriftItem `json:"items" db:"items"`
+	Error     *string           `json:"error,omitempty" db:"error"`
+	CheckedAt time.Time         `json:"checked_at" db:"checked_at"`
+}
+
+// DriftCheckRequest queues a drift check of a cluster's post-config
+type DriftCheckRequest struct {
+	Reconcile bool `json:"reconcile"` // Re-apply drifted tasks
+}
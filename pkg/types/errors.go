@@ -1,6 +1,9 @@
 package types
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // NotReadyError indicates a job should be deferred because a precondition is not met
 type NotReadyError struct {
@@ -13,10 +16,10 @@ func (e *NotReadyError) Error() string {
 	return fmt.Sprintf("%s is not ready: current state=%s, required=%s", e.Resource, e.Current, e.Required)
 }
 
-// IsNotReadyError checks if an error is a NotReadyError
+// IsNotReadyError checks if an error is, or wraps, a NotReadyError
 func IsNotReadyError(err error) bool {
-	_, ok := err.(*NotReadyError)
-	return ok
+	var notReady *NotReadyError
+	return errors.As(err, &notReady)
 }
 
 // TransientError indicates a job failed due to a transient condition and should be retried with backoff
@@ -29,6 +29,14 @@ const (
 	// Windows snapshot job types
 	JobTypeCreateWindowsSnapshot JobType = "CREATE_WINDOWS_SNAPSHOT" // Creates regional EBS snapshot for Windows VMs
 
+	// Day-2 add-on job types
+	JobTypeAddonInstall   JobType = "ADDON_INSTALL"   // Installs a single add-on on a running cluster
+	JobTypeAddonUpgrade   JobType = "ADDON_UPGRADE"   // Moves an installed add-on to another version/channel
+	JobTypeAddonUninstall JobType = "ADDON_UNINSTALL" // Removes an installed add-on from a running cluster
+
+	// Post-config drift job types
+	JobTypeDriftCheck JobType = "DRIFT_CHECK" // Compares installed post-config with the recorded config, optionally re-applying
+
 	// Future job types (not yet implemented):
 	// JobTypeScaleWorkers           JobType = "SCALE_WORKERS"  // Off-hours worker scaling
 	// JobTypeOrphanSweep            JobType = "ORPHAN_SWEEP"   // Automated orphan resource cleanup
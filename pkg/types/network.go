@@ -0,0 +1,42 @@
+package types
+
+// RestrictedNetwork configures a cluster for a restricted-egress network: a
+// cluster-wide proxy, an additional CA trust bundle (for the proxy or a mirror
+// registry with a private CA) and release/operator image mirrors.
+//
+// OpenShift IPI renders all three into install-config. ROSA and ARO accept the
+// proxy and trust bundle only; other cluster types do not support them.
+type RestrictedNetwork struct {
+	Proxy                 *ProxyConfig  `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	AdditionalTrustBundle string        `json:"additional_trust_bundle,omitempty" yaml:"additionalTrustBundle,omitempty"` // PEM-encoded CA certificates
+	ImageMirrors          []ImageMirror `json:"image_mirrors,omitempty" yaml:"imageMirrors,omitempty"`
+}
+
+// ProxyConfig is the cluster-wide egress proxy
+type ProxyConfig struct {
+	HTTPProxy  string `json:"http_proxy,omitempty" yaml:"httpProxy,omitempty"`
+	HTTPSProxy string `json:"https_proxy,omitempty" yaml:"httpsProxy,omitempty"`
+	NoProxy    string `json:"no_proxy,omitempty" yaml:"noProxy,omitempty"` // Comma-separated hosts, domains and CIDRs
+	// CredentialsSecret names a <team>/<name> entry in the post-config secrets
+	// backend holding the proxy username and password. They are added to the
+	// proxy URLs only when the cluster is installed, so they are never stored
+	// with the cluster.
+	CredentialsSecret string `json:"credentials_secret,omitempty" yaml:"credentialsSecret,omitempty"`
+}
+
+// ImageMirror maps a source repository to one or more mirror repositories,
+// e.g. quay.io/openshift-release-dev/ocp-release -> registry.lab:5000/ocp/release
+type ImageMirror struct {
+	Source  string   `json:"source" yaml:"source"`
+	Mirrors []string `json:"mirrors" yaml:"mirrors"`
+}
+
+// IsEmpty reports whether no restricted-network settings are configured
+func (r *RestrictedNetwork) IsEmpty() bool {
+	return r == nil || (r.Proxy.IsEmpty() && r.AdditionalTrustBundle == "" && len(r.ImageMirrors) == 0)
+}
+
+// IsEmpty reports whether no proxy is configured
+func (p *ProxyConfig) IsEmpty() bool {
+	return p == nil || (p.HTTPProxy == "" && p.HTTPSProxy == "" && p.NoProxy == "" && p.CredentialsSecret == "")
+}
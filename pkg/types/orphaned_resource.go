@@ -107,3 +107,21 @@ type OrphanedResourceStats struct {
 	ByRegion       map[string]int `json:"by_region"`
 	OldestDetected *time.Time     `json:"oldest_detected,omitempty"`
 }
+
+// MarkResolvedRequest represents the request to mark a resource as resolved
+type MarkResolvedRequest struct {
+	Notes string `json:"notes"`
+}
+
+// MarkIgnoredRequest represents the request to mark a resource as ignored
+type MarkIgnoredRequest struct {
+	Notes string `json:"notes"`
+}
+
+// OrphanedResourceListResponse represents the paginated list response
+type OrphanedResourceListResponse struct {
+	Resources []*OrphanedResource `json:"resources"`
+	Total     int                 `json:"total"`
+	Limit     int                 `json:"limit"`
+	Offset    int                 `json:"offset"`
+}
@@ -38,6 +38,9 @@ type ClusterPool struct {
 	MaxClusterAgeDays  int  `json:"max_cluster_age_days" db:"max_cluster_age_days"`
 	AutoRefreshEnabled bool `json:"auto_refresh_enabled" db:"auto_refresh_enabled"`
 
+	// Re-apply drifted post-config while cleaning a released cluster
+	ReconcileDriftEnabled bool `json:"reconcile_drift_enabled" db:"reconcile_drift_enabled"`
+
 	// Scheduling (work hours mode)
 	ScheduledMode      bool   `json:"scheduled_mode" db:"scheduled_mode"`
 	ScheduleTimezone   string `json:"schedule_timezone,omitempty" db:"schedule_timezone"`
@@ -136,8 +139,9 @@ type CreatePoolRequest struct {
 	AutoReleaseEnabled        *bool `json:"auto_release_enabled,omitempty"`
 
 	// Cluster lifecycle
-	MaxClusterAgeDays  *int  `json:"max_cluster_age_days,omitempty"`
-	AutoRefreshEnabled *bool `json:"auto_refresh_enabled,omitempty"`
+	MaxClusterAgeDays     *int  `json:"max_cluster_age_days,omitempty"`
+	AutoRefreshEnabled    *bool `json:"auto_refresh_enabled,omitempty"`
+	ReconcileDriftEnabled *bool `json:"reconcile_drift_enabled,omitempty"`
 
 	// Scheduling
 	ScheduledMode      *bool  `json:"scheduled_mode,omitempty"`
@@ -166,8 +170,9 @@ type UpdatePoolRequest struct {
 	AutoReleaseEnabled        *bool `json:"auto_release_enabled,omitempty"`
 
 	// Cluster lifecycle
-	MaxClusterAgeDays  *int  `json:"max_cluster_age_days,omitempty"`
-	AutoRefreshEnabled *bool `json:"auto_refresh_enabled,omitempty"`
+	MaxClusterAgeDays     *int  `json:"max_cluster_age_days,omitempty"`
+	AutoRefreshEnabled    *bool `json:"auto_refresh_enabled,omitempty"`
+	ReconcileDriftEnabled *bool `json:"reconcile_drift_enabled,omitempty"`
 
 	// Scheduling
 	ScheduledMode      *bool   `json:"scheduled_mode,omitempty"`
@@ -240,3 +245,9 @@ func (p *ClusterPool) NeedsReplenishment(readyCount int) bool {
 func (p *ClusterPool) CanProvisionMore(totalCount int) bool {
 	return totalCount < p.MaxSize
 }
+
+// PoolWithStats is a pool together with its current statistics
+type PoolWithStats struct {
+	ClusterPool
+	Stats *ClusterPoolStats `json:"stats"`
+}
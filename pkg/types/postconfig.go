@@ -18,6 +18,7 @@ type CustomOperatorConfig struct {
 	CustomResource *CustomResourceConfig `json:"customResource,omitempty" yaml:"customResource,omitempty"`
 	Condition      string                `json:"condition,omitempty" yaml:"condition,omitempty"` // Conditional execution (e.g. "clusterType == 'openshift'")
 	DependsOn      []string              `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"` // Task dependencies (names of other tasks)
+	Readiness      []ReadinessCheck      `json:"readiness,omitempty" yaml:"readiness,omitempty"` // Checks that must pass before the task is marked completed
 }
 
 // CustomResourceConfig defines a custom resource to create after operator installation
@@ -38,34 +39,104 @@ type CustomScriptConfig struct {
 	Path        string            `json:"path,omitempty" yaml:"path,omitempty"`       // Path to script in manifests directory
 	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
 	Timeout     string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`     // Duration string, e.g. "10m" (max 30m)
-	Env         map[string]string `json:"env,omitempty" yaml:"env,omitempty"`             // Environment variables (supports {{.Variable}} templating)
-	Variables   map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"` // Custom variables for template rendering
+	Env         map[string]string `json:"env,omitempty" yaml:"env,omitempty"`             // Environment variables (supports {{.Variable}} and {{ secret "team/name" }} templating)
+	Variables   map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"` // Custom variables for template rendering (may reference secrets)
 	Condition   string            `json:"condition,omitempty" yaml:"condition,omitempty"` // Conditional execution (e.g. "clusterType == 'openshift'")
 	DependsOn   []string          `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"` // Task dependencies (names of other tasks)
+	Readiness   []ReadinessCheck  `json:"readiness,omitempty" yaml:"readiness,omitempty"` // Checks that must pass before the task is marked completed
 }
 
 // CustomManifestConfig defines a user-specified manifest to apply
-// Supports both inline content and URL-based manifests with template variable substitution
+// Supports inline content, URL-based manifests with template variable substitution,
+// and manifests or kustomize overlays read from a Git repository
 type CustomManifestConfig struct {
 	Name        string            `json:"name" yaml:"name" validate:"required"`
-	Content     string            `json:"content,omitempty" yaml:"content,omitempty"` // Inline YAML/JSON content (supports {{.Variable}} templating)
-	URL         string            `json:"url,omitempty" yaml:"url,omitempty"`         // URL to download manifest from
+	Content     string            `json:"content,omitempty" yaml:"content,omitempty"`     // Inline YAML/JSON content (supports {{.Variable}} templating)
+	URL         string            `json:"url,omitempty" yaml:"url,omitempty"`             // URL to download manifest from
+	Git         *GitSourceConfig  `json:"git,omitempty" yaml:"git,omitempty"`             // Git repository to read manifests from
+	Kustomize   bool              `json:"kustomize,omitempty" yaml:"kustomize,omitempty"` // Build the Git path with kustomize before applying
 	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
 	Namespace   string            `json:"namespace,omitempty" yaml:"namespace,omitempty"` // Target namespace (supports {{.Variable}} templating)
 	Variables   map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"` // Custom variables for template rendering
 	Condition   string            `json:"condition,omitempty" yaml:"condition,omitempty"` // Conditional execution (e.g. "clusterType == 'openshift'")
 	DependsOn   []string          `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"` // Task dependencies (names of other tasks)
+	Readiness   []ReadinessCheck  `json:"readiness,omitempty" yaml:"readiness,omitempty"` // Checks that must pass before the task is marked completed
+}
+
+// GitSourceConfig identifies files in a Git repository
+type GitSourceConfig struct {
+	Repo              string `json:"repo" yaml:"repo" validate:"required"`                           // https or ssh repository URL
+	Ref               string `json:"ref,omitempty" yaml:"ref,omitempty"`                             // Branch, tag or commit (default: HEAD)
+	Path              string `json:"path,omitempty" yaml:"path,omitempty"`                           // File or directory relative to the repository root
+	CredentialsSecret string `json:"credentialsSecret,omitempty" yaml:"credentialsSecret,omitempty"` // Secrets backend entry holding repository credentials
 }
 
 // CustomHelmChartConfig defines a user-specified Helm chart to install
+// Repo is an HTTPS chart repository or an oci:// registry path
 type CustomHelmChartConfig struct {
-	Name      string                 `json:"name" yaml:"name" validate:"required"`
-	Repo      string                 `json:"repo" yaml:"repo" validate:"required"`
-	Chart     string                 `json:"chart" yaml:"chart" validate:"required"`
-	Version   string                 `json:"version,omitempty" yaml:"version,omitempty"`
+	Name    string `json:"name" yaml:"name" validate:"required"`
+	Repo    string `json:"repo" yaml:"repo" validate:"required"`
+	Chart   string `json:"chart" yaml:"chart" validate:"required"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	Digest  string `json:"digest,omitempty" yaml:"digest,omitempty"` // sha256 digest an OCI chart must match
+
 	Namespace string                 `json:"namespace,omitempty" yaml:"namespace,omitempty"` // Target namespace (supports {{.Variable}} templating)
 	Values    map[string]interface{} `json:"values,omitempty" yaml:"values,omitempty"`       // Helm values (supports {{.Variable}} templating in string values)
 	Variables map[string]string      `json:"variables,omitempty" yaml:"variables,omitempty"` // Custom variables for template rendering
 	Condition string                 `json:"condition,omitempty" yaml:"condition,omitempty"` // Conditional execution (e.g. "clusterType == 'openshift'")
 	DependsOn []string               `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"` // Task dependencies (names of other tasks)
+	Readiness []ReadinessCheck       `json:"readiness,omitempty" yaml:"readiness,omitempty"` // Checks that must pass before the task is marked completed
+}
+
+// ReadinessCheckType names what a readiness check waits for
+type ReadinessCheckType string
+
+const (
+	// ReadinessCheckCondition waits for a resource status condition, or a
+	// JSONPath into the resource, to have the expected value
+	ReadinessCheckCondition ReadinessCheckType = "condition"
+	// ReadinessCheckRollout waits for the rollout of Deployments to complete
+	ReadinessCheckRollout ReadinessCheckType = "rollout"
+	// ReadinessCheckScript runs a script probe until it exits 0
+	ReadinessCheckScript ReadinessCheckType = "script"
+)
+
+// ReadinessCheck is a check run after a task is applied. The task is only
+// marked completed once every check passes, each within its own timeout.
+type ReadinessCheck struct {
+	Type      ReadinessCheckType `json:"type" yaml:"type"`
+	Timeout   string             `json:"timeout,omitempty" yaml:"timeout,omitempty"`     // Duration string, e.g. "5m" (default 10m, max 1h)
+	Namespace string             `json:"namespace,omitempty" yaml:"namespace,omitempty"` // Defaults to the task's namespace; empty for cluster-scoped resources
+
+	// condition: the resource to watch, and either a status condition type or a JSONPath
+	Kind          string `json:"kind,omitempty" yaml:"kind,omitempty"`                   // e.g. "DataProtectionApplication" or "dataprotectionapplications.oadp.openshift.io"
+	Name          string `json:"name,omitempty" yaml:"name,omitempty"`                   // Resource name
+	ConditionType string `json:"conditionType,omitempty" yaml:"conditionType,omitempty"` // e.g. "Reconciled"
+	JSONPath      string `json:"jsonPath,omitempty" yaml:"jsonPath,omitempty"`           // e.g. "{.status.phase}", instead of conditionType
+	Value         string `json:"value,omitempty" yaml:"value,omitempty"`                 // Expected value (default "True" for conditionType)
+
+	// rollout: Deployments whose rollout must complete
+	Deployments []string `json:"deployments,omitempty" yaml:"deployments,omitempty"`
+
+	// script: inline shell probe run with KUBECONFIG set; ready when it exits 0
+	Script string `json:"script,omitempty" yaml:"script,omitempty"`
+}
+
+// ValidatePostConfigRequest represents a validation request
+type ValidatePostConfigRequest struct {
+	Config *CustomPostConfig `json:"config" validate:"required"`
+}
+
+// ValidatePostConfigResponse represents a validation response
+type ValidatePostConfigResponse struct {
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+	DAG    *DAGInfo `json:"dag,omitempty"`
+}
+
+// DAGInfo provides information about the execution DAG
+type DAGInfo struct {
+	ExecutionOrder []string            `json:"executionOrder"`
+	TaskCount      int                 `json:"taskCount"`
+	Dependencies   map[string][]string `json:"dependencies"`
 }
@@ -0,0 +1,48 @@
+package types
+
+// PreviewArtifactType names the installer input rendered by a cluster preview
+type PreviewArtifactType string
+
+const (
+	// PreviewArtifactInstallConfig is an openshift-install install-config.yaml
+	PreviewArtifactInstallConfig PreviewArtifactType = "install-config"
+	// PreviewArtifactEksctlConfig is an eksctl ClusterConfig
+	PreviewArtifactEksctlConfig PreviewArtifactType = "eksctl-config"
+	// PreviewArtifactCommand is one or more CLI invocations (rosa, gcloud, az, ibmcloud)
+	PreviewArtifactCommand PreviewArtifactType = "command"
+	// PreviewArtifactHostedCluster is the HostedCluster, NodePool and Secret manifests applied to the management cluster
+	PreviewArtifactHostedCluster PreviewArtifactType = "hosted-cluster"
+	// PreviewArtifactKindConfig is a kind.x-k8s.io Cluster config
+	PreviewArtifactKindConfig PreviewArtifactType = "kind-config"
+)
+
+// ClusterPreview is what the worker would submit to the installer for a create
+// request, rendered without creating anything. Secrets (pull secret, service
+// principal credentials) are replaced with a redaction marker.
+type ClusterPreview struct {
+	Name         string              `json:"name"`
+	Platform     Platform            `json:"platform"`
+	ClusterType  ClusterType         `json:"cluster_type"`
+	Profile      string              `json:"profile"`
+	ArtifactType PreviewArtifactType `json:"artifact_type"`
+	// Artifact is the rendered YAML document for install-config, eksctl-config
+	// and hosted-cluster
+	Artifact string `json:"artifact,omitempty"`
+	// Commands are the argument vectors run for command artifacts, in order,
+	// each starting with the CLI name
+	Commands [][]string `json:"commands,omitempty"`
+	// MergedTags are the tags applied to cloud resources: profile, request and
+	// ocpctl provenance tags. Provenance values for the cluster ID and creation
+	// time are assigned when the cluster is created.
+	MergedTags          Tags    `json:"merged_tags"`
+	EstimatedHourlyCost float64 `json:"estimated_hourly_cost"`
+	// Addons are the add-ons that would be installed, as "id:version" refs in
+	// install order: profile defaults, selections and their requirements
+	Addons []string `json:"addons,omitempty"`
+	// AddonDependencies are the add-ons in Addons pulled in by requirements
+	AddonDependencies []AddonDependency `json:"addon_dependencies,omitempty"`
+	// RecommendedAddons are add-ons recommended by Addons but not selected
+	RecommendedAddons []AddonDependency `json:"recommended_addons,omitempty"`
+	// Notes lists inputs that are only resolved when the cluster is created
+	Notes []string `json:"notes,omitempty"`
+}
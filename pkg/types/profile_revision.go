@@ -0,0 +1,61 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProfileRevisionStatus is the state of a saved profile revision
+type ProfileRevisionStatus string
+
+const (
+	// ProfileRevisionDraft is saved but not in use
+	ProfileRevisionDraft ProfileRevisionStatus = "draft"
+	// ProfileRevisionPublished is the revision clusters are created from
+	ProfileRevisionPublished ProfileRevisionStatus = "published"
+	// ProfileRevisionSuperseded was published and replaced by a later revision
+	ProfileRevisionSuperseded ProfileRevisionStatus = "superseded"
+)
+
+// ProfileSource records where a profile is managed
+type ProfileSource string
+
+const (
+	// ProfileSourceFile profiles are synced from the profile YAML files
+	ProfileSourceFile ProfileSource = "file"
+	// ProfileSourceAPI profiles are managed through the admin API and are no
+	// longer overwritten by the file sync
+	ProfileSourceAPI ProfileSource = "api"
+)
+
+// ProfileRevision is one saved version of a cluster profile
+type ProfileRevision struct {
+	ProfileName string                `json:"profile_name"`
+	Revision    int                   `json:"revision"`
+	Status      ProfileRevisionStatus `json:"status"`
+	Source      ProfileSource         `json:"source"`
+	Definition  string                `json:"definition"`             // Profile YAML as submitted
+	ProfileData json.RawMessage       `json:"profile_data,omitempty"` // Resolved profile
+	Diff        string                `json:"diff,omitempty"`         // Unified diff against the profile published when saved
+	Message     string                `json:"message,omitempty"`
+	AuthorID    *string               `json:"author_id,omitempty"`
+	AuthorEmail *string               `json:"author_email,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	PublishedAt *time.Time            `json:"published_at,omitempty"`
+}
+
+// SaveProfileRequest creates or replaces a profile
+type SaveProfileRequest struct {
+	Definition string `json:"definition" validate:"required"` // Profile YAML (or JSON)
+	Message    string `json:"message,omitempty"`
+	Draft      bool   `json:"draft,omitempty"` // Save without publishing
+}
+
+// RollbackProfileRequest publishes the content of an earlier revision as a
+// new revision
+type RollbackProfileRequest struct {
+	// Revision to restore. Defaults to the revision published before the
+	// current one.
+	Revision int    `json:"revision,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
@@ -0,0 +1,53 @@
+package types
+
+import "time"
+
+// RateLimitScope identifies which principal a rate limit policy applies to
+type RateLimitScope string
+
+const (
+	RateLimitScopeAPIKey RateLimitScope = "api_key" // Subject is an API key ID
+	RateLimitScopeUser   RateLimitScope = "user"    // Subject is a user ID
+	RateLimitScopeRole   RateLimitScope = "role"    // Subject is a UserRole
+)
+
+// IsValid checks if the scope is valid
+func (s RateLimitScope) IsValid() bool {
+	switch s {
+	case RateLimitScopeAPIKey, RateLimitScopeUser, RateLimitScopeRole:
+		return true
+	default:
+		return false
+	}
+}
+
+// RateLimitPolicy overrides the global API rate limit for a principal.
+// When several policies match a request the most specific one wins:
+// API key, then user, then role.
+type RateLimitPolicy struct {
+	ID                string         `json:"id" db:"id"`
+	Scope             RateLimitScope `json:"scope" db:"scope"`
+	Subject           string         `json:"subject" db:"subject"`
+	RequestsPerMinute int            `json:"requests_per_minute" db:"requests_per_minute"`
+	Burst             int            `json:"burst" db:"burst"`
+	Description       *string        `json:"description,omitempty" db:"description"`
+	CreatedBy         *string        `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt         time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// CreateRateLimitPolicyRequest represents a request to create a rate limit policy
+type CreateRateLimitPolicyRequest struct {
+	Scope             RateLimitScope `json:"scope" validate:"required"`
+	Subject           string         `json:"subject" validate:"required,max=255"`
+	RequestsPerMinute int            `json:"requests_per_minute" validate:"required,min=1,max=100000"`
+	Burst             int            `json:"burst" validate:"required,min=1,max=10000"`
+	Description       *string        `json:"description,omitempty"`
+}
+
+// UpdateRateLimitPolicyRequest represents a request to update a rate limit policy
+type UpdateRateLimitPolicyRequest struct {
+	RequestsPerMinute *int    `json:"requests_per_minute,omitempty" validate:"omitempty,min=1,max=100000"`
+	Burst             *int    `json:"burst,omitempty" validate:"omitempty,min=1,max=10000"`
+	Description       *string `json:"description,omitempty"`
+}
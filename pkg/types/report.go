@@ -79,3 +79,54 @@ type JobStats struct {
 	// Counts[jobType][status] = count
 	Counts map[string]map[string]int `json:"counts"`
 }
+
+// ClusterStatistics represents aggregated cluster statistics
+type ClusterStatistics struct {
+	TotalClusters      int                    `json:"total_clusters"`
+	ClustersByStatus   []ClusterStatusCount   `json:"clusters_by_status"`
+	ClustersByProfile  []ClusterProfileCount  `json:"clusters_by_profile"`
+	ClustersByPlatform []ClusterPlatformCount `json:"clusters_by_platform"`
+	ActiveClusters     int                    `json:"active_clusters"`
+	TotalHourlyCost    float64                `json:"total_hourly_cost"`
+	TotalDailyCost     float64                `json:"total_daily_cost"`
+	TotalMonthlyCost   float64                `json:"total_monthly_cost"`
+	CostByProfile      []ProfileCostBreakdown `json:"cost_by_profile"`
+	CostByUser         []UserCostBreakdown    `json:"cost_by_user"`
+}
+
+// ClusterStatusCount represents cluster count per status
+type ClusterStatusCount struct {
+	Status string `json:"status"`
+	Count  int    `json:"count"`
+}
+
+// ClusterProfileCount represents cluster count per profile
+type ClusterProfileCount struct {
+	Profile string `json:"profile"`
+	Count   int    `json:"count"`
+}
+
+// ClusterPlatformCount represents cluster count per platform
+type ClusterPlatformCount struct {
+	Platform string `json:"platform"`
+	Count    int    `json:"count"`
+}
+
+// ProfileCostBreakdown represents cost breakdown by profile
+type ProfileCostBreakdown struct {
+	Profile      string  `json:"profile"`
+	ClusterCount int     `json:"cluster_count"`
+	HourlyCost   float64 `json:"hourly_cost"`
+	DailyCost    float64 `json:"daily_cost"`
+	MonthlyCost  float64 `json:"monthly_cost"`
+}
+
+// UserCostBreakdown represents cost breakdown by user
+type UserCostBreakdown struct {
+	UserID       string  `json:"user_id"`
+	Username     string  `json:"username"`
+	ClusterCount int     `json:"cluster_count"`
+	HourlyCost   float64 `json:"hourly_cost"`
+	DailyCost    float64 `json:"daily_cost"`
+	MonthlyCost  float64 `json:"monthly_cost"`
+}
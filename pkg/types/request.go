@@ -20,4 +20,7 @@ type CreateClusterRequest struct {
 	WorkHours         *WorkHoursSchedule
 	PreserveOnFailure bool
 	CredentialsMode   *string
+	Overrides         *ComputeOverrides
+	RestrictedNetwork *RestrictedNetwork
+	Architecture      *ClusterArchitecture
 }
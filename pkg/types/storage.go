@@ -116,3 +116,30 @@ type ClusterStorageLink struct {
 	Role           ClusterStorageLinkRole `db:"role" json:"role"`
 	CreatedAt      time.Time              `db:"created_at" json:"created_at"`
 }
+
+// LinkStorageRequest represents the request to link storage between clusters
+type LinkStorageRequest struct {
+	TargetClusterID string `json:"target_cluster_id" validate:"required"`
+}
+
+// StorageGroupResponse represents the response for a storage group
+type StorageGroupResponse struct {
+	ID                 string                       `json:"id"`
+	Name               string                       `json:"name"`
+	EFSID              *string                      `json:"efs_id,omitempty"`
+	EFSSecurityGroupID *string                      `json:"efs_security_group_id,omitempty"`
+	S3Bucket           *string                      `json:"s3_bucket,omitempty"`
+	Region             string                       `json:"region"`
+	Status             StorageGroupStatus           `json:"status"`
+	LinkedClusters     []ClusterStorageLinkResponse `json:"linked_clusters"`
+	CreatedAt          time.Time                    `json:"created_at"`
+	UpdatedAt          time.Time                    `json:"updated_at"`
+}
+
+// ClusterStorageLinkResponse represents a cluster linked to a storage group
+type ClusterStorageLinkResponse struct {
+	ClusterID   string                 `json:"cluster_id"`
+	ClusterName string                 `json:"cluster_name"`
+	Role        ClusterStorageLinkRole `json:"role"`
+	LinkedAt    time.Time              `json:"linked_at"`
+}
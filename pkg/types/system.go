@@ -0,0 +1,118 @@
+package types
+
+import "time"
+
+// MetricsSnapshot represents current system metrics in JSON format
+type MetricsSnapshot struct {
+	API       APIMetricsSnapshot       `json:"api"`
+	Workers   WorkerMetricsSnapshot    `json:"workers"`
+	Jobs      JobMetricsSnapshot       `json:"jobs"`
+	Clusters  ClusterMetricsSnapshot   `json:"clusters"`
+	Autoscale AutoscaleMetricsSnapshot `json:"autoscale"`
+	Database  DatabaseMetricsSnapshot  `json:"database"`
+}
+
+type APIMetricsSnapshot struct {
+	RequestsPerSecond float64        `json:"requests_per_second"`
+	ActiveConnections int64          `json:"active_connections"`
+	ErrorRate         float64        `json:"error_rate"`
+	RequestsByStatus  map[string]int `json:"requests_by_status"`
+}
+
+type WorkerMetricsSnapshot struct {
+	Total  int `json:"total"`
+	Active int `json:"active"`
+	Idle   int `json:"idle"`
+}
+
+type JobMetricsSnapshot struct {
+	QueuedByType    map[string]int `json:"queued_by_type"`
+	ProcessingTotal int            `json:"processing_total"`
+	TotalQueued     int            `json:"total_queued"`
+}
+
+type ClusterMetricsSnapshot struct {
+	Total     int            `json:"total"`
+	ByStatus  map[string]int `json:"by_status"`
+	ByProfile map[string]int `json:"by_profile"`
+}
+
+type AutoscaleMetricsSnapshot struct {
+	CurrentWorkers int `json:"current_workers"`
+	DesiredWorkers int `json:"desired_workers"`
+}
+
+type DatabaseMetricsSnapshot struct {
+	OpenConnections int `json:"open_connections"`
+	MaxConnections  int `json:"max_connections"`
+}
+
+// WorkerInfo represents a worker instance
+type WorkerInfo struct {
+	InstanceID   string    `json:"instance_id"`
+	PrivateIP    string    `json:"private_ip"`
+	PublicIP     string    `json:"public_ip,omitempty"`
+	LaunchTime   time.Time `json:"launch_time"`
+	State        string    `json:"state"`
+	HealthStatus string    `json:"health_status"`
+	Type         string    `json:"type"` // "static" or "autoscale"
+	Version      string    `json:"version,omitempty"`
+}
+
+// ASGInfo represents autoscaling group information
+type ASGInfo struct {
+	Name            string       `json:"name"`
+	DesiredCapacity int          `json:"desired_capacity"`
+	MinSize         int          `json:"min_size"`
+	MaxSize         int          `json:"max_size"`
+	Instances       []WorkerInfo `json:"instances"`
+}
+
+// InfrastructureInfo represents the overall infrastructure status
+type InfrastructureInfo struct {
+	APIServer struct {
+		IP      string `json:"ip"`
+		Version string `json:"version"`
+		Status  string `json:"status"`
+		Uptime  string `json:"uptime,omitempty"`
+	} `json:"api_server"`
+	Database struct {
+		Host   string `json:"host"`
+		Status string `json:"status"`
+	} `json:"database"`
+	StaticWorkers  []WorkerInfo `json:"static_workers"`
+	AutoscaleGroup *ASGInfo     `json:"autoscale_group,omitempty"`
+	Timestamp      time.Time    `json:"timestamp"`
+}
+
+// InstalledVersionsResponse represents the response for installed versions
+type InstalledVersionsResponse struct {
+	OpenShiftVersions map[string]InstalledVersion `json:"openshift_versions"`
+	TotalInstalled    int                         `json:"total_installed"`
+	BinariesPath      string                      `json:"binaries_path"`
+}
+
+// InstalledVersion represents details about an installed version
+type InstalledVersion struct {
+	MajorMinor        string   `json:"major_minor"`                  // e.g., "4.20"
+	ExactVersion      string   `json:"exact_version"`                // e.g., "4.20.17"
+	BinaryPath        string   `json:"binary_path"`                  // e.g., "/usr/local/bin/openshift-install-4.20"
+	CcoctlPath        string   `json:"ccoctl_path,omitempty"`        // e.g., "/usr/local/bin/ccoctl-4.20"
+	ProfileReferences []string `json:"profile_references,omitempty"` // e.g., ["4.20", "4.20.3", "4.20.4"]
+}
+
+// UpdateVersionsRequest represents the request to update profile versions
+type UpdateVersionsRequest struct {
+	OpenshiftVersions        []string `json:"openshift_versions,omitempty"`
+	KubernetesVersions       []string `json:"kubernetes_versions,omitempty"`
+	OpenshiftDefaultVersion  string   `json:"openshift_default_version,omitempty"`
+	KubernetesDefaultVersion string   `json:"kubernetes_default_version,omitempty"`
+	DryRun                   bool     `json:"dry_run,omitempty"`
+}
+
+// ReloadProfilesResponse represents the response after reloading profiles
+type ReloadProfilesResponse struct {
+	Success        bool      `json:"success"`
+	ProfilesLoaded int       `json:"profiles_loaded"`
+	ReloadedAt     time.Time `json:"reloaded_at"`
+}
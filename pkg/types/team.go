@@ -116,3 +116,9 @@ type TeamCostSummary struct {
 	MonthOverMonth *PeriodComparison    `json:"month_over_month"` // This month vs last month
 	Clusters       []*ClusterCostDetail `json:"clusters"`
 }
+
+// TeamMember is a team membership with the member's user details
+type TeamMember struct {
+	*UserTeamMembership
+	User *UserResponse `json:"user"`
+}
@@ -14,3 +14,21 @@ type PostConfigTemplate struct {
 	CreatedAt   time.Time        `db:"created_at" json:"createdAt"`
 	UpdatedAt   time.Time        `db:"updated_at" json:"updatedAt"`
 }
+
+// CreateTemplateRequest represents a request to create a template
+type CreateTemplateRequest struct {
+	Name        string            `json:"name" validate:"required,min=3,max=100"`
+	Description string            `json:"description" validate:"max=500"`
+	Config      *CustomPostConfig `json:"config" validate:"required"`
+	IsPublic    bool              `json:"isPublic"`
+	Tags        []string          `json:"tags"`
+}
+
+// UpdateTemplateRequest represents a request to update a template
+type UpdateTemplateRequest struct {
+	Name        string            `json:"name" validate:"required,min=3,max=100"`
+	Description string            `json:"description" validate:"max=500"`
+	Config      *CustomPostConfig `json:"config" validate:"required"`
+	IsPublic    bool              `json:"isPublic"`
+	Tags        []string          `json:"tags"`
+}
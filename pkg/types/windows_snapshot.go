@@ -63,3 +63,11 @@ type WindowsSnapshotAudit struct {
 	Details    map[string]interface{} `json:"details,omitempty" db:"details"`
 	CreatedAt  time.Time              `json:"created_at" db:"created_at"`
 }
+
+// CreateWindowsSnapshotResponse is returned when a snapshot creation job is started
+type CreateWindowsSnapshotResponse struct {
+	SnapshotID string                `json:"snapshot_id"`
+	JobID      string                `json:"job_id"`
+	Status     WindowsSnapshotStatus `json:"status"`
+	Message    string                `json:"message"`
+}
@@ -6,14 +6,26 @@ set -euo pipefail
 
 FULL_VERSION="${1:-}"
 INSTALL_DIR="${2:-/usr/local/bin}"
+RELEASE_ARCH="${3:-amd64}"
 S3_BUCKET="${S3_BUCKET:-ocpctl-binaries}"
 
 if [ -z "$FULL_VERSION" ]; then
-    echo "Usage: $0 <full-version> [install_dir]"
+    echo "Usage: $0 <full-version> [install_dir] [amd64|multi]"
     echo "Example: $0 4.22.0-rc.4 /usr/local/bin"
     exit 1
 fi
 
+# Installers for the multi-architecture payload (arm64 and heterogeneous
+# clusters) are stored with a -multi suffix next to the amd64 ones
+case "$RELEASE_ARCH" in
+    amd64) SUFFIX="" ;;
+    multi) SUFFIX="-multi" ;;
+    *)
+        echo "Unsupported release architecture: $RELEASE_ARCH (must be amd64 or multi)"
+        exit 1
+        ;;
+esac
+
 # Extract major.minor
 MAJOR_MINOR=$(echo "$FULL_VERSION" | cut -d- -f1 | cut -d. -f1,2)
 
@@ -54,8 +66,8 @@ get_mirror_base_path() {
 
 download_from_s3() {
     local binary=$1
-    local s3_path="s3://${S3_BUCKET}/installers/${FULL_VERSION}/${binary}"
-    local local_path="${INSTALL_DIR}/${binary}-${FULL_VERSION}"
+    local s3_path="s3://${S3_BUCKET}/installers/${FULL_VERSION}${SUFFIX}/${binary}"
+    local local_path="${INSTALL_DIR}/${binary}-${FULL_VERSION}${SUFFIX}"
 
     log "Checking S3 cache..."
     if aws s3 cp "${s3_path}" "${local_path}" 2>/dev/null; then
@@ -81,8 +93,12 @@ download_from_mirror() {
     fi
 
     local mirror_url="https://mirror.openshift.com/pub/openshift-v4/${arch_path}clients/${mirror_base}/${FULL_VERSION}/${tarball_name}"
+    if [ "$RELEASE_ARCH" = "multi" ]; then
+        # Multi-payload clients are published per host architecture
+        mirror_url="https://mirror.openshift.com/pub/openshift-v4/multi/clients/${mirror_base}/${FULL_VERSION}/amd64/${tarball_name}"
+    fi
     local tmp_dir=$(mktemp -d)
-    local local_path="${INSTALL_DIR}/${binary}-${FULL_VERSION}"
+    local local_path="${INSTALL_DIR}/${binary}-${FULL_VERSION}${SUFFIX}"
 
     log "Trying public mirror: ${mirror_url}"
 
@@ -101,7 +117,7 @@ download_from_mirror() {
             rm -rf "${tmp_dir}"
 
             # Upload to S3 for caching
-            aws s3 cp "${local_path}" "s3://${S3_BUCKET}/installers/${FULL_VERSION}/${binary}" 2>/dev/null || true
+            aws s3 cp "${local_path}" "s3://${S3_BUCKET}/installers/${FULL_VERSION}${SUFFIX}/${binary}" 2>/dev/null || true
 
             success "Downloaded ${binary} from public mirror"
             return 0
@@ -127,8 +143,11 @@ download_from_ci_release() {
     fi
 
     local release_image="quay.io/openshift-release-dev/ocp-release:${FULL_VERSION}-x86_64"
+    if [ "$RELEASE_ARCH" = "multi" ]; then
+        release_image="quay.io/openshift-release-dev/ocp-release:${FULL_VERSION}-multi"
+    fi
     local tmp_dir=$(mktemp -d)
-    local local_path="${INSTALL_DIR}/${binary}-${FULL_VERSION}"
+    local local_path="${INSTALL_DIR}/${binary}-${FULL_VERSION}${SUFFIX}"
     local pull_secret_file="${tmp_dir}/pull-secret.json"
 
     # Write pull secret to temp file for oc command
@@ -172,7 +191,7 @@ download_from_ci_release() {
             rm -rf "${tmp_dir}"
 
             # Upload to S3
-            aws s3 cp "${local_path}" "s3://${S3_BUCKET}/installers/${FULL_VERSION}/${binary}" 2>/dev/null || true
+            aws s3 cp "${local_path}" "s3://${S3_BUCKET}/installers/${FULL_VERSION}${SUFFIX}/${binary}" 2>/dev/null || true
 
             success "Downloaded ${binary} from CI release stream"
             return 0
@@ -185,13 +204,13 @@ download_from_ci_release() {
 
 # Main download logic
 main() {
-    log "Downloading OpenShift ${FULL_VERSION} installer binaries..."
+    log "Downloading OpenShift ${FULL_VERSION} (${RELEASE_ARCH}) installer binaries..."
 
     local failed=0
 
     # Download openshift-install
-    if [ -f "${INSTALL_DIR}/openshift-install-${FULL_VERSION}" ]; then
-        log "✓ openshift-install-${FULL_VERSION} already exists"
+    if [ -f "${INSTALL_DIR}/openshift-install-${FULL_VERSION}${SUFFIX}" ]; then
+        log "✓ openshift-install-${FULL_VERSION}${SUFFIX} already exists"
     else
         log "Downloading openshift-install..."
         if ! download_from_s3 "openshift-install"; then
@@ -205,8 +224,8 @@ main() {
     fi
 
     # Download ccoctl (non-fatal)
-    if [ -f "${INSTALL_DIR}/ccoctl-${FULL_VERSION}" ]; then
-        log "✓ ccoctl-${FULL_VERSION} already exists"
+    if [ -f "${INSTALL_DIR}/ccoctl-${FULL_VERSION}${SUFFIX}" ]; then
+        log "✓ ccoctl-${FULL_VERSION}${SUFFIX} already exists"
     else
         log "Downloading ccoctl..."
         if ! download_from_s3 "ccoctl"; then
@@ -219,8 +238,8 @@ main() {
     fi
 
     # Download oc (non-fatal)
-    if [ -f "${INSTALL_DIR}/oc-${FULL_VERSION}" ]; then
-        log "✓ oc-${FULL_VERSION} already exists"
+    if [ -f "${INSTALL_DIR}/oc-${FULL_VERSION}${SUFFIX}" ]; then
+        log "✓ oc-${FULL_VERSION}${SUFFIX} already exists"
     else
         log "Downloading oc..."
         if ! download_from_s3 "oc"; then
@@ -240,9 +259,9 @@ main() {
     success "Successfully downloaded OpenShift ${FULL_VERSION} installer binaries"
 
     # Verify installed version
-    if [ -f "${INSTALL_DIR}/openshift-install-${FULL_VERSION}" ]; then
+    if [ -f "${INSTALL_DIR}/openshift-install-${FULL_VERSION}${SUFFIX}" ]; then
         log "Verifying installed version..."
-        ACTUAL_VERSION=$("${INSTALL_DIR}/openshift-install-${FULL_VERSION}" version 2>/dev/null | head -1 | awk '{print $2}' || echo "unknown")
+        ACTUAL_VERSION=$("${INSTALL_DIR}/openshift-install-${FULL_VERSION}${SUFFIX}" version 2>/dev/null | head -1 | awk '{print $2}' || echo "unknown")
         log "Installed version: ${ACTUAL_VERSION}"
 
         if [ "$ACTUAL_VERSION" != "$FULL_VERSION" ]; then
@@ -36,6 +36,7 @@ export default function PoolDetailsPage() {
   const [updateError, setUpdateError] = useState("");
   const [scheduledMode, setScheduledMode] = useState(false);
   const [autoRefresh, setAutoRefresh] = useState(false);
+  const [reconcileDrift, setReconcileDrift] = useState(false);
   const [autoRelease, setAutoRelease] = useState(true);
   const [enabled, setEnabled] = useState(true);
   const [selectedDays, setSelectedDays] = useState<number[]>([1, 2, 3, 4, 5]);
@@ -69,6 +70,7 @@ export default function PoolDetailsPage() {
       });
       setScheduledMode(pool.scheduled_mode);
       setAutoRefresh(pool.auto_refresh_enabled);
+      setReconcileDrift(pool.reconcile_drift_enabled);
       setAutoRelease(pool.auto_release_enabled);
       setEnabled(pool.enabled);
       setSelectedDays(pool.schedule_days_of_week || [1, 2, 3, 4, 5]);
@@ -106,6 +108,7 @@ export default function PoolDetailsPage() {
       ...data,
       auto_release_enabled: autoRelease,
       auto_refresh_enabled: autoRefresh,
+      reconcile_drift_enabled: reconcileDrift,
       scheduled_mode: scheduledMode,
       schedule_days_of_week: scheduledMode ? selectedDays : undefined,
       enabled,
@@ -411,6 +414,17 @@ export default function PoolDetailsPage() {
                 />
               </div>
             )}
+
+            <div className="flex items-center space-x-2">
+              <Checkbox
+                id="reconcile_drift"
+                checked={reconcileDrift}
+                onCheckedChange={(checked) => setReconcileDrift(!!checked)}
+              />
+              <label htmlFor="reconcile_drift" className="text-sm font-medium">
+                Reconcile post-config drift between leases
+              </label>
+            </div>
           </CardContent>
         </Card>
 
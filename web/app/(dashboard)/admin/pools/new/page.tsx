@@ -32,6 +32,7 @@ export default function NewPoolPage() {
   const [selectedProfile, setSelectedProfile] = useState<string>("");
   const [scheduledMode, setScheduledMode] = useState(false);
   const [autoRefresh, setAutoRefresh] = useState(false);
+  const [reconcileDrift, setReconcileDrift] = useState(false);
   const [autoRelease, setAutoRelease] = useState(true);
   const [selectedDays, setSelectedDays] = useState<number[]>([1, 2, 3, 4, 5]); // Mon-Fri default
 
@@ -119,6 +120,7 @@ export default function NewPoolPage() {
       profile: selectedProfile,
       auto_release_enabled: autoRelease,
       auto_refresh_enabled: autoRefresh,
+      reconcile_drift_enabled: reconcileDrift,
       scheduled_mode: scheduledMode,
       schedule_days_of_week: scheduledMode ? selectedDays : undefined,
       cluster_config: Object.keys(cluster_config).length > 0 ? cluster_config : undefined,
@@ -567,6 +569,20 @@ export default function NewPoolPage() {
                 </p>
               </div>
             )}
+
+            <div className="flex items-center space-x-2">
+              <Checkbox
+                id="reconcile_drift"
+                checked={reconcileDrift}
+                onCheckedChange={(checked) => setReconcileDrift(!!checked)}
+              />
+              <label
+                htmlFor="reconcile_drift"
+                className="text-sm font-medium leading-none peer-disabled:cursor-not-allowed peer-disabled:opacity-70"
+              >
+                Reconcile post-config drift between leases
+              </label>
+            </div>
           </CardContent>
         </Card>
 
@@ -73,6 +73,12 @@ interface ProfileVersionStatus {
   new_versions: string[]
   update_count: number
   last_checked: string
+  addon_gaps?: AddonCoverageGap[]
+}
+
+interface AddonCoverageGap {
+  addon_id: string
+  versions: string[]
 }
 
 interface CheckVersionsResponse {
@@ -905,6 +911,26 @@ export default function ProfileUpdatesPage() {
                 </>
               )}
 
+              {/* Add-ons whose compatibility ranges miss a version */}
+              {profile.addon_gaps && profile.addon_gaps.length > 0 && (
+                <Alert>
+                  <AlertCircle className="h-4 w-4" />
+                  <AlertTitle>Add-on Coverage</AlertTitle>
+                  <AlertDescription>
+                    <span className="text-xs block mb-1">
+                      These add-ons have no version compatible with some of this profile&apos;s versions.
+                      Extend their compatibility ranges before offering these versions.
+                    </span>
+                    {profile.addon_gaps.map((gap) => (
+                      <div key={gap.addon_id}>
+                        <span className="font-semibold">{gap.addon_id}:</span>{' '}
+                        {[...gap.versions].sort(compareVersions).join(', ')}
+                      </div>
+                    ))}
+                  </AlertDescription>
+                </Alert>
+              )}
+
               {/* Action Buttons */}
               <div className="flex gap-2">
                 <Button
@@ -1036,6 +1036,7 @@ export default function NewClusterPage() {
                     <CustomPostConfigEditor
                       platform={watchedValues.cluster_type}
                       profile={watchedValues.profile}
+                      version={watchedValues.version}
                       value={watchedValues.customPostConfig}
                       selectedAddons={watchedValues.postConfigAddOns || []}
                       onAddonsChange={(addonIds) => setValue("postConfigAddOns", addonIds)}
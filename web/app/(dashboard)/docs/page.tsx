@@ -762,13 +762,29 @@ Task waits for **all** listed dependencies to complete before starting.
 
 Use the \`condition\` field to run tasks only when specific criteria are met. This allows the same post-deployment configuration to work across different cluster types or platforms.
 
-**Available Conditions:**
+Conditions are [CEL](https://cel.dev) expressions that must evaluate to true or false. They are checked when the configuration is validated, so syntax errors and unknown variables are reported before the cluster is created.
+
+**Available Variables:**
+- \`clusterType\`, \`platform\`, \`region\`, \`profile\`, \`baseDomain\` - Strings
+- \`version\` - OpenShift version (Kubernetes version for non-OpenShift clusters), compared as a semantic version
+- \`kubernetesVersion\` - Kubernetes version, compared as a semantic version
+- \`workerCount\` - Total worker nodes in the profile
+- \`features\` - Profile features, e.g. \`features.fipsMode\`, \`features.privateCluster\`
+- \`capabilities\` - Profile capabilities, e.g. \`'bare-metal' in capabilities\`
+- \`addons\` - Selected addon IDs
+- \`vars\` - The task's custom variables; each is also available by name
+- \`cluster\` - Map with \`id\`, \`name\`, \`type\`, \`platform\`, \`region\`, \`profile\`, \`baseDomain\`, \`infraID\` and \`workerCount\`
+
+**Examples:**
 - \`clusterType == 'openshift'\` - Only on OpenShift clusters
-- \`clusterType == 'eks'\` - Only on EKS clusters
-- \`clusterType == 'iks'\` - Only on IKS clusters
-- \`platform == 'aws'\` - Only on AWS
-- \`platform == 'ibmcloud'\` - Only on IBM Cloud
-- \`region == 'us-east-1'\` - Only in specific region
+- \`platform == 'aws' && version >= '4.18'\` - AWS clusters on 4.18 or later
+- \`version < '4.20' || kubernetesVersion >= '1.33'\` - Version ranges compare numerically, so 4.9 is older than 4.18
+- \`version == semver('4.18')\` - Exact version match
+- \`workerCount >= 3 && !features.fipsMode\` - At least three workers and FIPS disabled
+- \`'openshift-virtualization' in addons\` - Only when an addon is selected
+- \`region.startsWith('us-')\` - String functions such as \`contains\`, \`startsWith\` and \`matches\`
+
+The earlier \`variable operator value\` form, such as \`region contains 'us-'\`, is still accepted.
 
 **Example: Platform-specific scripts**
 \`\`\`json
@@ -812,6 +828,80 @@ Use the \`condition\` field to run tasks only when specific criteria are met. Th
 }
 \`\`\`
 
+### Readiness Checks
+
+By default an operator task completes when its CSV succeeds, and scripts, manifests and Helm charts complete as soon as their command exits. Add \`readiness\` checks to a task to wait until what it deployed is actually working. Dependent tasks only start once every check passes.
+
+**Check types:**
+- \`condition\` - Waits for a status condition on a resource (\`kind\`, \`name\`, \`conditionType\`, optional \`value\`, default \`True\`), or for a \`jsonPath\` into it to equal \`value\`
+- \`rollout\` - Waits for the rollout of the named \`deployments\` to complete
+- \`script\` - Runs a shell probe with \`KUBECONFIG\` set until it exits 0
+
+Each check has its own \`timeout\` (default 10m, maximum 1h). Checks use the task's namespace unless they set \`namespace\`. When a check times out, the task's configuration record shows which check failed and the last state observed, such as the resource's conditions or pods stuck in \`CrashLoopBackOff\`.
+
+**Example: Wait for OADP to reconcile and Velero to roll out**
+\`\`\`json
+{
+  "name": "redhat-oadp-operator",
+  "namespace": "openshift-adp",
+  "channel": "stable-1.4",
+  "readiness": [
+    {"type": "condition", "kind": "DataProtectionApplication", "name": "velero", "conditionType": "Reconciled"},
+    {"type": "rollout", "deployments": ["velero"], "timeout": "5m"},
+    {"type": "script", "script": "oc get backupstoragelocations -n openshift-adp -o jsonpath='{.items[0].status.phase}' | grep -q Available"}
+  ]
+}
+\`\`\`
+
+### Git, Kustomize and OCI Sources
+
+Manifests can be read from a Git repository instead of inline \`content\` or a \`url\`, and Helm charts can come from an OCI registry.
+
+**Git manifests:**
+- \`repo\` - HTTPS or SSH repository URL
+- \`ref\` - Branch, tag or commit (default \`HEAD\`)
+- \`path\` - A manifest file, or a directory whose \`.yaml\`, \`.yml\` and \`.json\` files are applied in name order
+- \`credentialsSecret\` - Secrets Manager entry holding a token, a \`username\`/\`password\` pair, or an \`sshPrivateKey\` with optional \`knownHosts\`. Names have the form \`<team>/<name>\` and only secrets of the cluster's team under the worker's post-config prefix (default \`ocpctl/post-config/\`) can be read; give the name without the prefix.
+
+Set \`kustomize: true\` to build \`path\` with kustomize and apply the output. Kustomizations may only reference files in the repository; remote bases are rejected. Git content is not rendered with template variables.
+
+**OCI Helm charts:** set \`repo\` to an \`oci://\` registry path. Pin the chart with \`digest\` and the install fails if the pulled chart's digest differs.
+
+\`\`\`json
+{
+  "manifests": [
+    {
+      "name": "platform-config",
+      "git": {
+        "repo": "https://github.com/example/platform-config.git",
+        "ref": "v1.4.0",
+        "path": "overlays/dev",
+        "credentialsSecret": "platform/config-token"
+      },
+      "kustomize": true
+    }
+  ],
+  "helmCharts": [
+    {
+      "name": "observability",
+      "repo": "oci://quay.io/example/charts",
+      "chart": "observability",
+      "version": "2.3.1",
+      "digest": "sha256:<64 hex characters>"
+    }
+  ]
+}
+\`\`\`
+
+### Drift Detection
+
+A drift check compares the cluster against its recorded post-config and add-ons. Operators are checked for their subscription channel, installed CSV and custom resources, manifests with \`oc diff\`, and Helm charts for release status and version. Readiness checks run once each. Scripts cannot be inspected after they run and are skipped.
+
+- GET \`/clusters/{id}/drift\` returns the latest report with status \`IN_SYNC\`, \`DRIFTED\`, \`RECONCILED\` or \`FAILED\`
+- POST \`/clusters/{id}/drift-check\` queues a check. Send \`{"reconcile": true}\` to re-apply only the drifted tasks.
+
+Pool clusters are checked when they are cleaned between leases. If the pool has **Reconcile drift** enabled, drifted tasks are re-applied and a cluster that cannot be reconciled is expired instead of returned to the pool.
+
 ### Template Variables in Scripts and Manifests
 
 **What are template variables?**
@@ -880,6 +970,29 @@ In addition to template variables, scripts support custom environment variables:
 
 **Note:** Environment variables also support template variable substitution!
 
+### Secret References
+
+Don't paste registry tokens or cloud credentials into a cluster request; the request is stored and returned by the API. Reference a secret instead:
+
+\`\`\`json
+{
+  "name": "mirror-images",
+  "content": "#!/bin/bash\\nskopeo login quay.io -u \\"$QUAY_USER\\" -p \\"$QUAY_TOKEN\\"",
+  "env": {
+    "QUAY_USER": "{{ .Variables.quayUser }}",
+    "QUAY_TOKEN": "{{ secret \\"platform/quay-token\\" }}"
+  },
+  "variables": {
+    "quayUser": "{{ secret \\"platform/quay-user\\" }}"
+  }
+}
+\`\`\`
+
+- \`{{ secret "<team>/<name>" }}\` works in script content and env, manifest content and namespace, Helm chart values and namespace, and task variables
+- The name must be a quoted literal. Clusters can only reference their own team's secrets; other teams' secrets are rejected when the cluster is created.
+- Secrets are read from the worker's secrets backend under the post-config prefix (default \`ocpctl/post-config/\`) when the task runs, and only if its condition is met
+- Resolved values are replaced with \`***\` in deployment logs, task errors and job errors, and are never stored with the cluster or job
+
 ### Viewing Post-Deployment Details
 
 To see what will be installed automatically:
@@ -2078,6 +2191,17 @@ Some addons use automatic resolution:
 - Warning badge appears when conflict detected
 - Tooltip provides details
 
+### Version Compatibility
+
+Addon versions can declare which cluster types and OpenShift or Kubernetes versions they support, e.g. CNV \`stable\` for OpenShift >=4.17 and \`stable-4.16\` for 4.16 only.
+
+- The Addon Browser only lists versions compatible with the selected cluster type and version
+- If a profile's default version doesn't fit, the first compatible version is preselected
+- Creating a cluster, or adding an addon to a running one, with an incompatible version is rejected with the reason
+- Addons without compatibility information can be installed on any cluster
+
+Administrators see addons with no compatible version for a profile's current or newly available versions under **Admin** → **Profile Updates**.
+
 ### Testing Draft Addons
 
 **For Addon Authors:**
@@ -2647,6 +2771,29 @@ Use \`dependsOn\` / \`depends_on\` to control execution order.
 
 **Published addons cannot be edited.** To make changes, you must clone the addon to create a new draft version.
 
+### Generating Addons from OperatorHub
+
+Operators in the \`redhat-operators\`, \`certified-operators\` and \`community-operators\` catalogs can be browsed per OpenShift version and turned into a draft addon.
+
+- GET \`/post-config/catalog/{catalog}/packages?version=4.18&search=backup\` lists packages with their channels
+- GET \`/post-config/catalog/{catalog}/packages/{package}?version=4.18&channel=stable\` shows the channel head's install modes, suggested namespace and example custom resources (alm-examples)
+- POST \`/post-config/catalog/addons\` creates the draft
+
+\`\`\`json
+{
+  "version": "4.18",
+  "catalog": "redhat-operators",
+  "package": "redhat-oadp-operator",
+  "channel": "stable",
+  "category": "backup",
+  "exampleKind": "DataProtectionApplication"
+}
+\`\`\`
+
+The draft subscribes to the channel in the operator's suggested namespace, or \`openshift-operators\` for operators that only support AllNamespaces. With \`exampleKind\` set, that example is added as a manifest applied after the operator installs; review it before publishing, as examples often need credentials or sizing. Set \`namespace\` or \`addonId\` to override the defaults.
+
+**Server configuration:** catalogs are read from \`OPERATOR_CATALOG_DIR\` as \`<catalog>-<version>.json\` files (the output of \`opm render <index image> -o json\`). With \`OPERATOR_CATALOG_RENDER=true\` the API server renders missing catalogs, and refreshes them daily, with \`opm\`. \`OPERATOR_CATALOG_IMAGES\` points catalogs at a mirror, e.g. \`redhat-operators=mirror.example.com/redhat/redhat-operator-index:v{version}\`. Browsing returns 503 when no catalog is available.
+
 ### Publishing Addons
 
 When your addon is tested and production-ready:
@@ -4121,6 +4268,14 @@ OCPCTL provides a comprehensive REST API with Swagger/OpenAPI documentation.
 - GET \`/clusters/{id}/configurations\` - List configs
 - POST \`/clusters/{id}/configure\` - Trigger post-deployment
 - PATCH \`/clusters/{id}/configurations/{config_id}/retry\` - Retry failed
+- POST \`/clusters/{id}/addons/{addon_id}\` - Install add-on on a running cluster
+- PATCH \`/clusters/{id}/addons/{addon_id}\` - Change add-on version/channel
+- DELETE \`/clusters/{id}/addons/{addon_id}\` - Uninstall add-on
+- GET \`/clusters/{id}/drift\` - Latest post-config drift report
+- POST \`/clusters/{id}/drift-check\` - Check for drift, optionally reconcile
+- GET \`/post-config/catalog/{catalog}/packages\` - Browse OperatorHub catalog packages
+- GET \`/post-config/catalog/{catalog}/packages/{package}\` - Package channels and examples
+- POST \`/post-config/catalog/addons\` - Generate a draft add-on from a catalog package
 
 **Orphaned Resources (Admin):**
 - GET \`/admin/orphaned-resources\` - List orphans
@@ -4213,9 +4368,10 @@ To prevent abuse, APIs are rate-limited:
 
 **Rate Limit Headers:**
 \`\`\`
-X-RateLimit-Limit: 100
-X-RateLimit-Remaining: 95
-X-RateLimit-Reset: 1234567890
+RateLimit-Limit: 100
+RateLimit-Remaining: 95
+RateLimit-Reset: 42
+RateLimit-Policy: 100;w=60
 \`\`\`
 
 ### API Code Generation
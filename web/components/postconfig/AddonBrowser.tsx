@@ -24,6 +24,7 @@ import type { PostConfigAddon, AddonSelection } from "@/types/api";
 interface AddonBrowserProps {
   platform?: string;
   profile?: string;
+  version?: string;
   selectedAddons: AddonSelection[];
   onSelectionChange: (selections: AddonSelection[]) => void;
 }
@@ -31,6 +32,7 @@ interface AddonBrowserProps {
 export function AddonBrowser({
   platform,
   profile,
+  version,
   selectedAddons,
   onSelectionChange,
 }: AddonBrowserProps) {
@@ -42,6 +44,7 @@ export function AddonBrowser({
     platform,
     profile,
     search: search || undefined,
+    version,
   });
 
   const handleToggleAddon = (addonId: string, defaultVersion: string) => {
@@ -7,6 +7,7 @@ import type { CustomPostConfig, AddonSelection } from "@/types/api";
 interface CustomPostConfigEditorProps {
   platform?: string;
   profile?: string;
+  version?: string;
   value?: CustomPostConfig;
   selectedAddons: AddonSelection[];
   onAddonsChange: (selections: AddonSelection[]) => void;
@@ -16,6 +17,7 @@ interface CustomPostConfigEditorProps {
 export function CustomPostConfigEditor({
   platform,
   profile,
+  version,
   selectedAddons,
   onAddonsChange,
 }: CustomPostConfigEditorProps) {
@@ -38,6 +40,7 @@ export function CustomPostConfigEditor({
         <AddonBrowser
           platform={platform}
           profile={profile}
+          version={version}
           selectedAddons={selectedAddons}
           onSelectionChange={onAddonsChange}
         />
@@ -15,12 +15,14 @@ export const postConfigApi = {
       platform?: string;
       profile?: string;
       search?: string;
+      version?: string;
     }): Promise<PostConfigAddonsResponse> => {
       const queryParams = new URLSearchParams();
       if (params?.category) queryParams.set("category", params.category);
       if (params?.platform) queryParams.set("platform", params.platform);
       if (params?.profile) queryParams.set("profile", params.profile);
       if (params?.search) queryParams.set("search", params.search);
+      if (params?.version) queryParams.set("version", params.version);
 
       const query = queryParams.toString() ? `?${queryParams.toString()}` : "";
       return apiClient.get<PostConfigAddonsResponse>(`/post-config/addons${query}`);
@@ -11,6 +11,7 @@ export function usePostConfigAddons(params?: {
   platform?: string;
   profile?: string;
   search?: string;
+  version?: string;
 }) {
   return useQuery({
     queryKey: ["postConfigAddons", params],
@@ -50,6 +50,7 @@ export enum JobType {
   RESUME = "RESUME",
   POST_CONFIGURE = "POST_CONFIGURE",
   POOL_CLEAN = "POOL_CLEAN",
+  DRIFT_CHECK = "DRIFT_CHECK",
 }
 
 export enum JobStatus {
@@ -326,6 +327,7 @@ export interface HelmChartConfig {
   repo: string;
   chart: string;
   version?: string;
+  digest?: string;
   namespace: string;
   values?: Record<string, any>;
 }
@@ -480,6 +482,30 @@ export interface ClusterConfigurationsResponse {
   job_retry_info?: JobRetryInfo;
 }
 
+export type ConfigDriftStatus = "IN_SYNC" | "DRIFTED" | "RECONCILED" | "FAILED";
+
+export interface ConfigDriftItem {
+  task: string;
+  type: ConfigType;
+  source: string; // Add-on ID, or "custom"
+  reason: string;
+  reconciled: boolean;
+}
+
+export interface ConfigDriftReport {
+  cluster_id: string;
+  job_id?: string;
+  status: ConfigDriftStatus;
+  checked: number;
+  items: ConfigDriftItem[];
+  error?: string;
+  checked_at: string;
+}
+
+export interface DriftCheckRequest {
+  reconcile?: boolean;
+}
+
 // Job Types
 export interface Job {
   id: string;
@@ -555,6 +581,19 @@ export interface APIError {
 }
 
 // Custom Post-Config Types
+export interface ReadinessCheck {
+  type: 'condition' | 'rollout' | 'script';
+  timeout?: string;
+  namespace?: string;
+  kind?: string;
+  name?: string;
+  conditionType?: string;
+  jsonPath?: string;
+  value?: string;
+  deployments?: string[];
+  script?: string;
+}
+
 export interface CustomOperatorConfig {
   name: string;
   namespace: string;
@@ -564,6 +603,7 @@ export interface CustomOperatorConfig {
   variables?: Record<string, string>;
   condition?: string;
   dependsOn?: string[];
+  readiness?: ReadinessCheck[];
 }
 
 export interface CustomScriptConfig {
@@ -576,16 +616,27 @@ export interface CustomScriptConfig {
   variables?: Record<string, string>;
   condition?: string;
   dependsOn?: string[];
+  readiness?: ReadinessCheck[];
+}
+
+export interface GitSourceConfig {
+  repo: string;
+  ref?: string;
+  path?: string;
+  credentialsSecret?: string;
 }
 
 export interface CustomManifestConfig {
   name: string;
   content?: string;
   url?: string;
+  git?: GitSourceConfig;
+  kustomize?: boolean;
   description?: string;
   variables?: Record<string, string>;
   condition?: string;
   dependsOn?: string[];
+  readiness?: ReadinessCheck[];
 }
 
 export interface CustomHelmChartConfig {
@@ -593,11 +644,13 @@ export interface CustomHelmChartConfig {
   repo: string;
   chart: string;
   version?: string;
+  digest?: string;
   namespace: string;
   values?: Record<string, any>;
   variables?: Record<string, string>;
   condition?: string;
   dependsOn?: string[];
+  readiness?: ReadinessCheck[];
 }
 
 export interface CustomPostConfig {
@@ -622,8 +675,24 @@ export interface AddonMetadata {
   requiresBareMetal?: boolean;
   requiredCapabilities?: string[];
   conflictsWith?: string[];
+  requires?: AddonRequirement[];
+  recommends?: AddonRequirement[];
   notes?: string[];
   warnings?: string[];
+  compatibility?: AddonCompatibility;
+}
+
+// Clusters an add-on version supports; ranges are constraints such as ">=4.16, <=4.18"
+export interface AddonCompatibility {
+  openshiftVersions?: string;
+  kubernetesVersions?: string;
+  clusterTypes?: ClusterType[];
+}
+
+export interface AddonRequirement {
+  id: string;
+  version?: string;
+  condition?: string;
 }
 
 export interface PostConfigAddon {
@@ -648,6 +717,58 @@ export interface PostConfigAddonsResponse {
   total: number;
 }
 
+// OperatorHub Catalog Types
+export type OperatorCatalogName = "redhat-operators" | "certified-operators" | "community-operators";
+
+export interface CatalogChannel {
+  name: string;
+  currentCSV: string;
+  version?: string;
+}
+
+export interface CatalogPackage {
+  name: string;
+  catalog: OperatorCatalogName;
+  displayName: string;
+  description?: string;
+  provider?: string;
+  defaultChannel: string;
+  channels: CatalogChannel[];
+}
+
+export interface CatalogExample {
+  apiVersion: string;
+  kind: string;
+  name?: string;
+  namespace?: string;
+  spec?: Record<string, any>;
+}
+
+export interface CatalogPackageDetail extends CatalogPackage {
+  channel: string;
+  installModes?: string[];
+  suggestedNamespace?: string;
+  examples: CatalogExample[];
+}
+
+export interface CatalogPackagesResponse {
+  catalog: OperatorCatalogName;
+  version: string;
+  packages: CatalogPackage[];
+  total: number;
+}
+
+export interface GenerateCatalogAddonRequest {
+  version: string;
+  catalog: OperatorCatalogName;
+  package: string;
+  channel?: string;
+  category: string;
+  addonId?: string;
+  namespace?: string;
+  exampleKind?: string;
+}
+
 // Post-Config Template Types
 export interface PostConfigTemplate {
   id: string;
@@ -796,6 +917,7 @@ export interface ClusterPool {
   auto_release_enabled: boolean;
   max_cluster_age_days: number;
   auto_refresh_enabled: boolean;
+  reconcile_drift_enabled: boolean;
   scheduled_mode: boolean;
   schedule_timezone?: string;
   schedule_start_hour?: number;
@@ -836,6 +958,7 @@ export interface CreatePoolRequest {
   auto_release_enabled?: boolean;
   max_cluster_age_days?: number;
   auto_refresh_enabled?: boolean;
+  reconcile_drift_enabled?: boolean;
   scheduled_mode?: boolean;
   schedule_timezone?: string;
   schedule_start_hour?: number;
@@ -856,6 +979,7 @@ export interface UpdatePoolRequest {
   auto_release_enabled?: boolean;
   max_cluster_age_days?: number;
   auto_refresh_enabled?: boolean;
+  reconcile_drift_enabled?: boolean;
   scheduled_mode?: boolean;
   schedule_timezone?: string;
   schedule_start_hour?: number;